@@ -20,13 +20,16 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/hanko-field/api/internal/di"
 	"github.com/hanko-field/api/internal/handlers"
 	"github.com/hanko-field/api/internal/platform/auth"
 	"github.com/hanko-field/api/internal/platform/config"
+	pfirestore "github.com/hanko-field/api/internal/platform/firestore"
 	"github.com/hanko-field/api/internal/platform/idempotency"
 	"github.com/hanko-field/api/internal/platform/observability"
 	"github.com/hanko-field/api/internal/platform/secrets"
 	"github.com/hanko-field/api/internal/repositories"
+	firestorerepo "github.com/hanko-field/api/internal/repositories/firestore"
 	"github.com/hanko-field/api/internal/services"
 )
 
@@ -76,12 +79,14 @@ func main() {
 
 	buildInfo := buildInfoFromEnv(envValues, cfg, startedAt)
 
-	firestoreClient, err := newFirestoreClient(ctx, cfg)
+	// One provider backs every Firestore consumer so the process holds a single client.
+	firestoreProvider := newFirestoreProvider(cfg)
+	firestoreClient, err := firestoreProvider.Client(ctx)
 	if err != nil {
 		logger.Fatal("failed to initialise firestore client", zap.Error(err))
 	}
 	defer func() {
-		if err := firestoreClient.Close(); err != nil {
+		if err := firestoreProvider.Close(context.Background()); err != nil {
 			logger.Warn("firestore close error", zap.Error(err))
 		}
 	}()
@@ -128,6 +133,53 @@ func main() {
 		}()
 	}
 
+	inventoryLogger := logger.Named("inventory")
+	var (
+		inventoryService   services.InventoryService
+		reservationSweeper services.ReservationSweeper
+	)
+	container, err := newContainer(ctx, cfg, firestoreProvider, inventoryLogger)
+	if err != nil {
+		inventoryLogger.Warn("service container init failed", zap.Error(err))
+	} else {
+		inventoryService = container.Services.Inventory
+		reservationSweeper = container.Services.Sweeper
+	}
+
+	var sweepTicker *time.Ticker
+	if reservationSweeper != nil && cfg.Inventory.ReservationSweepInterval > 0 {
+		sweepTicker = time.NewTicker(cfg.Inventory.ReservationSweepInterval)
+		cleanupWG.Add(1)
+		go func() {
+			defer cleanupWG.Done()
+			for {
+				select {
+				case <-sweepTicker.C:
+					runCtx, cancel := context.WithTimeout(cleanupCtx, time.Minute)
+					result, err := reservationSweeper.SweepExpired(runCtx, services.ReleaseExpiredReservationsCommand{
+						BatchSize: cfg.Inventory.ReservationSweepBatchSize,
+						Limit:     cfg.Inventory.ReservationSweepLimit,
+						ActorID:   "system:reservation-sweeper",
+					})
+					cancel()
+					if err != nil {
+						inventoryLogger.Error("reservation sweep error", zap.Error(err))
+						continue
+					}
+					if result.ReleasedCount > 0 || len(result.FailedIDs) > 0 {
+						inventoryLogger.Info("reservation sweep released reservations",
+							zap.Int("released", result.ReleasedCount),
+							zap.Int("failed", len(result.FailedIDs)),
+							zap.Int("canceledOrders", len(result.CanceledOrderIDs)),
+						)
+					}
+				case <-cleanupCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+
 	oidcMiddleware := buildOIDCMiddleware(logger.Named("auth"), cfg)
 	hmacMiddleware := buildHMACMiddleware(logger.Named("auth"), cfg)
 
@@ -150,6 +202,27 @@ func main() {
 	opts = append(opts, handlers.WithHealthHandlers(healthHandlers))
 	publicHandlers := handlers.NewPublicHandlers()
 	opts = append(opts, handlers.WithPublicRoutes(publicHandlers.Routes))
+	webhookHandlers := handlers.NewWebhookHandlers()
+	opts = append(opts, handlers.WithWebhookRoutes(webhookHandlers.Routes))
+	internalHandlers := handlers.NewInternalHandlers(
+		handlers.WithInternalInventoryService(inventoryService),
+		handlers.WithInternalReservationSweeper(reservationSweeper),
+		handlers.WithInternalCleanupBounds(cfg.Inventory.ReservationSweepBatchSize, cfg.Inventory.ReservationSweepLimit),
+	)
+	opts = append(opts, handlers.WithInternalRoutes(internalHandlers.Routes))
+	stockSubscriptionHandlers := handlers.NewStockSubscriptionHandlers()
+	opts = append(opts, handlers.WithMeRoutes(stockSubscriptionHandlers.Routes))
+	reviewHandlers := handlers.NewReviewHandlers()
+	opts = append(opts, handlers.WithReviewRoutes(reviewHandlers.Routes))
+	opts = append(opts, handlers.WithAdminRoutes(reviewHandlers.AdminRoutes))
+	paymentHandlers := handlers.NewPaymentHandlers()
+	opts = append(opts, handlers.WithAdminRoutes(paymentHandlers.AdminRoutes))
+	disputeHandlers := handlers.NewDisputeHandlers()
+	opts = append(opts, handlers.WithAdminRoutes(disputeHandlers.AdminRoutes))
+	pricingHandlers := handlers.NewPricingHandlers()
+	opts = append(opts, handlers.WithAdminRoutes(pricingHandlers.AdminRoutes))
+	inventoryHandlers := handlers.NewInventoryHandlers()
+	opts = append(opts, handlers.WithAdminRoutes(inventoryHandlers.AdminRoutes))
 	if oidcMiddleware != nil {
 		opts = append(opts, handlers.WithInternalMiddlewares(oidcMiddleware))
 	}
@@ -183,6 +256,9 @@ func main() {
 	if cleanupTicker != nil {
 		cleanupTicker.Stop()
 	}
+	if sweepTicker != nil {
+		sweepTicker.Stop()
+	}
 	cleanupCancel()
 	cleanupWG.Wait()
 
@@ -372,24 +448,24 @@ func webhookSecretResolver(secrets map[string]string) func(*http.Request) (strin
 	}
 }
 
-func newFirestoreClient(ctx context.Context, cfg config.Config) (*firestore.Client, error) {
-	projectID := strings.TrimSpace(cfg.Firestore.ProjectID)
-	if projectID == "" {
-		return nil, fmt.Errorf("firestore project id not configured")
-	}
-
-	if host := strings.TrimSpace(cfg.Firestore.EmulatorHost); host != "" {
-		if err := os.Setenv("FIRESTORE_EMULATOR_HOST", host); err != nil {
-			return nil, fmt.Errorf("failed to set FIRESTORE_EMULATOR_HOST: %w", err)
-		}
+// newContainer builds the service container on the Firestore repositories. The reservation sweeper
+// comes from the container so it shares the inventory and order services the API uses.
+func newContainer(ctx context.Context, cfg config.Config, provider *pfirestore.Provider, logger *zap.Logger) (*di.Container, error) {
+	registry, err := firestorerepo.NewRegistry(provider)
+	if err != nil {
+		return nil, err
 	}
+	return di.NewContainer(ctx, cfg, registry, di.WithLogger(func(_ context.Context, event string, fields map[string]any) {
+		logger.Warn(event, zap.Any("fields", fields))
+	}))
+}
 
-	var opts []option.ClientOption
+func newFirestoreProvider(cfg config.Config) *pfirestore.Provider {
+	var opts []pfirestore.ProviderOption
 	if credentials := strings.TrimSpace(cfg.Firebase.CredentialsFile); credentials != "" {
-		opts = append(opts, option.WithCredentialsFile(credentials))
+		opts = append(opts, pfirestore.WithClientOptions(option.WithCredentialsFile(credentials)))
 	}
-
-	return firestore.NewClient(ctx, projectID, opts...)
+	return pfirestore.NewProvider(cfg.Firestore, opts...)
 }
 
 func traceProjectID(cfg config.Config) string {
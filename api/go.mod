@@ -24,6 +24,7 @@ require (
 	golang.org/x/text v0.27.0
 	google.golang.org/api v0.231.0
 	google.golang.org/grpc v1.72.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -17,6 +17,7 @@ import (
 type Services struct {
 	Design     services.DesignService
 	Cart       services.CartService
+	Pricing    *services.CartPricingEngine
 	Checkout   services.CheckoutService
 	Orders     services.OrderService
 	Reviews    services.ReviewService
@@ -26,6 +27,7 @@ type Services struct {
 	Promotions services.PromotionService
 	Users      services.UserService
 	Inventory  services.InventoryService
+	Sweeper    services.ReservationSweeper
 	Content    services.ContentService
 	Catalog    services.CatalogService
 	Assets     services.AssetService
@@ -42,14 +44,43 @@ type Container struct {
 	Services     Services
 }
 
+// Option customises collaborators that do not come from the repository registry.
+type Option func(*options)
+
+type options struct {
+	checkoutSessions services.CheckoutSessionCreator
+	logger           func(ctx context.Context, event string, fields map[string]any)
+}
+
+// WithLogger routes structured service events, such as sweeper failures, to the given sink.
+func WithLogger(logger func(ctx context.Context, event string, fields map[string]any)) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithCheckoutSessions supplies the PSP session creator, typically a *payments.Manager, that the
+// checkout service opens sessions with.
+func WithCheckoutSessions(creator services.CheckoutSessionCreator) Option {
+	return func(o *options) {
+		o.checkoutSessions = creator
+	}
+}
+
 // NewContainer constructs the runtime dependencies. Production wiring will provide real
 // implementations, while tests can supply in-memory registries.
-func NewContainer(ctx context.Context, cfg config.Config, reg repositories.Registry) (*Container, error) {
+func NewContainer(ctx context.Context, cfg config.Config, reg repositories.Registry, opts ...Option) (*Container, error) {
 	if reg == nil {
 		return nil, errors.New("repositories registry is required")
 	}
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
 
-	svc, err := buildServices(ctx, reg, cfg)
+	svc, err := buildServices(ctx, reg, cfg, o)
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +100,9 @@ func (c *Container) Close(ctx context.Context) error {
 	return c.Repositories.Close(ctx)
 }
 
-func buildServices(ctx context.Context, reg repositories.Registry, cfg config.Config) (Services, error) {
+func buildServices(ctx context.Context, reg repositories.Registry, cfg config.Config, o options) (Services, error) {
 	var svc Services
+	svc.Errors = services.NewErrorTranslator()
 	if reg == nil {
 		return svc, nil
 	}
@@ -110,6 +142,7 @@ func buildServices(ctx context.Context, reg repositories.Registry, cfg config.Co
 		inventorySvc, err := services.NewInventoryService(services.InventoryServiceDeps{
 			Inventory: inventoryRepo,
 			Clock:     time.Now,
+			Logger:    o.logger,
 		})
 		if err != nil {
 			return Services{}, fmt.Errorf("build inventory service: %w", err)
@@ -186,11 +219,25 @@ func buildServices(ctx context.Context, reg repositories.Registry, cfg config.Co
 		svc.Orders = orderSvc
 	}
 
+	if svc.Inventory != nil {
+		sweeper, err := services.NewReservationSweeper(services.ReservationSweeperDeps{
+			Inventory: svc.Inventory,
+			Orders:    svc.Orders,
+			Logger:    o.logger,
+		})
+		if err != nil {
+			return Services{}, fmt.Errorf("build reservation sweeper: %w", err)
+		}
+		svc.Sweeper = sweeper
+	}
+
 	if reviewRepo := reg.Reviews(); reviewRepo != nil && ordersRepo != nil {
 		reviewSvc, err := services.NewReviewService(services.ReviewServiceDeps{
-			Reviews: reviewRepo,
-			Orders:  ordersRepo,
-			Clock:   time.Now,
+			Reviews:    reviewRepo,
+			Orders:     ordersRepo,
+			Ratings:    reg.ProductRatings(),
+			UnitOfWork: reg,
+			Clock:      time.Now,
 		})
 		if err != nil {
 			return Services{}, fmt.Errorf("build review service: %w", err)
@@ -198,5 +245,30 @@ func buildServices(ctx context.Context, reg repositories.Registry, cfg config.Co
 		svc.Reviews = reviewSvc
 	}
 
+	// Checkout only accepts signed quotes, so the engine that issues them must share the secret.
+	if svc.Promotions != nil && cfg.Pricing.QuoteSecret != "" {
+		pricing, err := services.NewCartPricingEngine(services.CartPricingEngineDeps{
+			Promotion:   svc.Promotions,
+			QuoteSecret: []byte(cfg.Pricing.QuoteSecret),
+			QuoteTTL:    cfg.Pricing.QuoteTTL,
+		})
+		if err != nil {
+			return Services{}, fmt.Errorf("build cart pricing engine: %w", err)
+		}
+		svc.Pricing = pricing
+	}
+
+	if svc.Cart != nil && svc.Pricing != nil && o.checkoutSessions != nil {
+		checkoutSvc, err := services.NewCheckoutService(services.CheckoutServiceDeps{
+			Carts:    svc.Cart,
+			Pricing:  svc.Pricing,
+			Sessions: o.checkoutSessions,
+		})
+		if err != nil {
+			return Services{}, fmt.Errorf("build checkout service: %w", err)
+		}
+		svc.Checkout = checkoutSvc
+	}
+
 	return svc, nil
 }
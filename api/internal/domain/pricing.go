@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // PricingBreakdown captures the aggregated monetary results of pricing a cart.
 type PricingBreakdown struct {
 	Currency        string
@@ -18,14 +20,28 @@ type PricingBreakdown struct {
 
 // ItemPricingBreakdown stores the per-item pricing outputs after running the engine.
 type ItemPricingBreakdown struct {
-	ItemID   string
-	Currency string
-	Subtotal int64
-	Discount int64
-	Tax      int64
-	Shipping int64
-	Total    int64
-	Metadata map[string]any
+	ItemID     string
+	Currency   string
+	Subtotal   int64
+	Discount   int64
+	Tax        int64
+	Shipping   int64
+	Total      int64
+	Surcharges []SurchargeBreakdown
+	Metadata   map[string]any
+}
+
+// SurchargeBreakdown records a customization charge included in a line subtotal. TaxCode overrides the
+// line's tax code when the add-on is taxed differently.
+type SurchargeBreakdown struct {
+	Type        string
+	Code        string
+	Description string
+	Quantity    int
+	UnitAmount  int64
+	Amount      int64
+	TaxCode     string
+	Metadata    map[string]any
 }
 
 // DiscountBreakdown lists the individual discount adjustments applied to the cart.
@@ -56,3 +72,15 @@ type ShippingBreakdown struct {
 	EstimateDays *int
 	Metadata     map[string]any
 }
+
+// PriceQuote is a signed, time-limited commitment to a priced cart. Token carries the signed quote and
+// must be presented at checkout; Hash fingerprints the monetary fields of the breakdown.
+type PriceQuote struct {
+	ID        string
+	Token     string
+	Hash      string
+	Currency  string
+	Total     int64
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
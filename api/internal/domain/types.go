@@ -172,6 +172,8 @@ type CartEstimate struct {
 	Tax      int64
 	Shipping int64
 	Total    int64
+	// Quote is set when the pricing engine signs quotes; checkout must present Quote.Token.
+	Quote *PriceQuote
 }
 
 // CheckoutSession represents PSP checkout session metadata stored by services.
@@ -239,6 +241,35 @@ type Order struct {
 	Payments         []Payment
 	Shipments        []Shipment
 	ProductionEvents []OrderProductionEvent
+	// ExchangeRates records the FX conversions used to price the order so refunds reuse them.
+	ExchangeRates []FXConversion
+}
+
+// FXRateTable is a dated, immutable set of exchange rates. Rates are quoted in major units of each
+// currency per one major unit of BaseCurrency.
+type FXRateTable struct {
+	ID            string
+	BaseCurrency  string
+	Rates         map[string]float64
+	Source        string
+	EffectiveDate string
+	EffectiveAt   time.Time
+	CreatedBy     string
+	CreatedAt     time.Time
+}
+
+// FXConversion snapshots the rate and rounding rule applied when converting From into To, so the
+// same conversion can be replayed later.
+type FXConversion struct {
+	TableID      string
+	From         string
+	To           string
+	Rate         float64
+	FromDecimals int
+	ToDecimals   int
+	Rounding     string
+	Increment    int64
+	ConvertedAt  time.Time
 }
 
 // OrderTotals holds rolled-up monetary fields in the smallest currency unit.
@@ -320,21 +351,105 @@ type OrderProductionQC struct {
 	Defects []string
 }
 
-// Payment encapsulates payment status and PSP references for an order.
+// Payment encapsulates payment status and PSP references for an order. The Amount* balances are
+// derived from the payment's transaction ledger; Captured and RefundedAt are kept for older readers.
 type Payment struct {
-	ID         string
-	OrderID    string
-	Provider   string
-	IntentID   string
-	Status     string
-	Amount     int64
-	Currency   string
-	Captured   bool
-	CapturedAt *time.Time
-	RefundedAt *time.Time
-	Raw        map[string]any
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID               string
+	OrderID          string
+	Provider         string
+	IntentID         string
+	Status           string
+	Amount           int64
+	Currency         string
+	Captured         bool
+	CapturedAt       *time.Time
+	RefundedAt       *time.Time
+	AmountAuthorized int64
+	AmountCaptured   int64
+	AmountRefunded   int64
+	AmountDisputed   int64
+	Transactions     []PaymentTransaction
+	Raw              map[string]any
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PaymentTransactionType enumerates payment ledger entry kinds.
+type PaymentTransactionType string
+
+const (
+	// PaymentTransactionAuthorization records funds held by the PSP.
+	PaymentTransactionAuthorization PaymentTransactionType = "authorization"
+	// PaymentTransactionCapture records funds captured from an authorization.
+	PaymentTransactionCapture PaymentTransactionType = "capture"
+	// PaymentTransactionRefund records funds returned to the customer.
+	PaymentTransactionRefund PaymentTransactionType = "refund"
+	// PaymentTransactionDispute records funds withdrawn by a chargeback.
+	PaymentTransactionDispute PaymentTransactionType = "dispute"
+)
+
+// PaymentTransaction is an immutable ledger entry stored under a payment. Amounts are positive minor
+// units; the entry type determines its effect on the payment balances.
+type PaymentTransaction struct {
+	ID           string
+	PaymentID    string
+	OrderID      string
+	Type         PaymentTransactionType
+	Amount       int64
+	Currency     string
+	PSPReference string
+	Reason       string
+	ActorRef     string
+	// BaseAmount and BaseCurrency restate Amount in the store currency at the order's recorded rate.
+	BaseAmount   int64
+	BaseCurrency string
+	OccurredAt   time.Time
+	CreatedAt    time.Time
+}
+
+// DisputeStatus tracks a chargeback through the PSP dispute lifecycle.
+type DisputeStatus string
+
+const (
+	// DisputeStatusNeedsResponse indicates evidence must be submitted before EvidenceDueBy.
+	DisputeStatusNeedsResponse DisputeStatus = "needs_response"
+	// DisputeStatusUnderReview indicates evidence was submitted and the issuer is deciding.
+	DisputeStatusUnderReview DisputeStatus = "under_review"
+	// DisputeStatusWon indicates the dispute was resolved in the merchant's favour.
+	DisputeStatusWon DisputeStatus = "won"
+	// DisputeStatusLost indicates the disputed funds were returned to the cardholder.
+	DisputeStatusLost DisputeStatus = "lost"
+)
+
+// PaymentDispute records a PSP chargeback against an order payment.
+type PaymentDispute struct {
+	ID             string
+	OrderID        string
+	PaymentID      string
+	Provider       string
+	PSPDisputeID   string
+	Status         DisputeStatus
+	Reason         string
+	Amount         int64
+	Currency       string
+	EvidenceDueBy  *time.Time
+	Evidence       []DisputeEvidence
+	SubmittedAt    *time.Time
+	SubmittedBy    string
+	ReminderSentAt *time.Time
+	ClosedAt       *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// DisputeEvidence references an uploaded asset offered as evidence for a dispute.
+type DisputeEvidence struct {
+	AssetID     string
+	Kind        string
+	FileName    string
+	ContentType string
+	UploadedBy  string
+	UploadedAt  time.Time
 }
 
 // Shipment represents fulfilment records for an order.
@@ -378,6 +493,7 @@ type Review struct {
 	Status      ReviewStatus
 	ModeratedBy *string
 	ModeratedAt *time.Time
+	ProductIDs  []string
 	Reply       *ReviewReply
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -392,6 +508,16 @@ type ReviewReply struct {
 	UpdatedAt time.Time
 }
 
+// ProductRatingSummary aggregates approved review ratings for a single product.
+// Histogram holds the number of approved reviews per star, index 0 being one star.
+type ProductRatingSummary struct {
+	ProductID     string
+	ReviewCount   int
+	AverageRating float64
+	Histogram     [5]int
+	UpdatedAt     time.Time
+}
+
 // Promotion describes promotional rules persisted by admin services.
 type Promotion struct {
 	ID          string
@@ -460,6 +586,7 @@ type UserProfile struct {
 	IsActive          bool
 	NotificationPrefs NotificationPreferences
 	ProviderData      []AuthProvider
+	PaymentCustomers  map[string]string
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	PiiMaskedAt       *time.Time
@@ -481,21 +608,24 @@ type PromotionUsage struct {
 
 // PaymentMethod stores PSP-backed payment references without sensitive card data.
 type PaymentMethod struct {
-	ID        string
-	Provider  string
-	Reference string
-	Brand     string
-	Last4     string
-	ExpMonth  int
-	ExpYear   int
-	CreatedAt time.Time
+	ID          string
+	Provider    string
+	Reference   string
+	CustomerRef string
+	Brand       string
+	Last4       string
+	ExpMonth    int
+	ExpYear     int
+	CreatedAt   time.Time
 }
 
-// InventoryReservationLine stores per-SKU quantities for a reservation.
+// InventoryReservationLine stores per-SKU quantities for a reservation. LocationID names the stock
+// location the line was allocated from and is empty for SKUs that are not stocked per location.
 type InventoryReservationLine struct {
 	ProductRef string
 	SKU        string
 	Quantity   int
+	LocationID string
 }
 
 // InventoryReservation holds temporary or committed stock reservations.
@@ -510,14 +640,17 @@ type InventoryReservation struct {
 	ExpiresAt      time.Time
 	ReleasedAt     *time.Time
 	CommittedAt    *time.Time
+	RestockedAt    *time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
 
-// InventoryStock represents current stock metrics tracked per SKU.
+// InventoryStock represents current stock metrics tracked per SKU. LocationID is set for the stock
+// held at one location and empty for the SKU aggregate across all locations.
 type InventoryStock struct {
 	SKU         string
 	ProductRef  string
+	LocationID  string
 	OnHand      int
 	Reserved    int
 	Available   int
@@ -530,6 +663,7 @@ type InventoryStock struct {
 type InventorySnapshot struct {
 	SKU         string
 	ProductRef  string
+	LocationID  string
 	OnHand      int
 	Reserved    int
 	Available   int
@@ -538,9 +672,41 @@ type InventorySnapshot struct {
 	UpdatedAt   time.Time
 }
 
-// InventoryStockEvent captures stock adjustments for downstream analytics/audit.
+// InventoryAdjustmentType classifies manual changes to on-hand stock.
+type InventoryAdjustmentType string
+
+const (
+	// InventoryAdjustmentReceipt adds received goods to on-hand stock.
+	InventoryAdjustmentReceipt InventoryAdjustmentType = "receipt"
+	// InventoryAdjustmentCycleCount replaces on-hand stock with a physical count.
+	InventoryAdjustmentCycleCount InventoryAdjustmentType = "cycle_count"
+	// InventoryAdjustmentDamage writes off damaged or lost units.
+	InventoryAdjustmentDamage InventoryAdjustmentType = "damage"
+	// InventoryAdjustmentCorrection applies a signed manual correction.
+	InventoryAdjustmentCorrection InventoryAdjustmentType = "correction"
+)
+
+// InventoryLocation is a site that holds stock, such as the workshop or a fulfilment partner.
+// Prefecture and NearbyPrefectures drive the choice of location for a reservation; lower Priority
+// values win among locations at the same distance.
+type InventoryLocation struct {
+	ID                string
+	Name              string
+	Prefecture        string
+	NearbyPrefectures []string
+	Priority          int
+	Active            bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// InventoryStockEvent captures stock adjustments for downstream analytics/audit. Events with an ID
+// are entries of the immutable per-SKU stock ledger. When LocationID is set the balances are those
+// of that location rather than the SKU aggregate.
 type InventoryStockEvent struct {
+	ID            string
 	Type          string
+	LocationID    string
 	ReservationID string
 	OrderRef      string
 	UserRef       string
@@ -551,10 +717,54 @@ type InventoryStockEvent struct {
 	OnHand        int
 	Reserved      int
 	SafetyStock   int
+	ActorID       string
+	Reason        string
 	OccurredAt    time.Time
 	Metadata      map[string]any
 }
 
+// StockSubscriptionStatus tracks a back-in-stock subscription from sign-up to delivery.
+type StockSubscriptionStatus string
+
+const (
+	// StockSubscriptionActive waits for the subscribed stock to become available.
+	StockSubscriptionActive StockSubscriptionStatus = "active"
+	// StockSubscriptionNotified indicates the subscriber has been told the stock is back.
+	StockSubscriptionNotified StockSubscriptionStatus = "notified"
+	// StockSubscriptionExpired indicates the stock did not return before ExpiresAt.
+	StockSubscriptionExpired StockSubscriptionStatus = "expired"
+	// StockSubscriptionCanceled indicates the subscriber withdrew the subscription.
+	StockSubscriptionCanceled StockSubscriptionStatus = "canceled"
+)
+
+// StockSubscriptionTrigger selects the availability level that fires a subscription.
+type StockSubscriptionTrigger string
+
+const (
+	// StockSubscriptionTriggerInStock fires once any unit is available.
+	StockSubscriptionTriggerInStock StockSubscriptionTrigger = "in_stock"
+	// StockSubscriptionTriggerAboveSafetyStock fires once availability exceeds the safety stock.
+	StockSubscriptionTriggerAboveSafetyStock StockSubscriptionTrigger = "above_safety_stock"
+)
+
+// StockSubscription asks to notify a user once when a SKU, or any SKU of a product when SKU is
+// empty, is back in stock. NotifyFailedAt records the last failed delivery of an active
+// subscription.
+type StockSubscription struct {
+	ID              string
+	UserID          string
+	SKU             string
+	ProductRef      string
+	Trigger         StockSubscriptionTrigger
+	Status          StockSubscriptionStatus
+	ExpiresAt       time.Time
+	NotifiedAt      *time.Time
+	NotifiedEventID string
+	NotifyFailedAt  *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
 // ContentPage describes CMS-managed content accessible via public endpoints.
 type ContentPage struct {
 	ID          string
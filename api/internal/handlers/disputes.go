@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const (
+	defaultDisputePageSize = 50
+	maxDisputePageSize     = 200
+)
+
+// DisputeHandlers exposes the staff chargeback queue and evidence submission under /admin.
+type DisputeHandlers struct {
+	disputes services.DisputeService
+}
+
+// DisputeOption customises DisputeHandlers.
+type DisputeOption func(*DisputeHandlers)
+
+// WithDisputeService injects the service tracking PSP disputes.
+func WithDisputeService(svc services.DisputeService) DisputeOption {
+	return func(h *DisputeHandlers) {
+		h.disputes = svc
+	}
+}
+
+// NewDisputeHandlers constructs dispute handlers with the provided options.
+func NewDisputeHandlers(opts ...DisputeOption) *DisputeHandlers {
+	handler := &DisputeHandlers{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// AdminRoutes registers staff dispute endpoints under /admin.
+func (h *DisputeHandlers) AdminRoutes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Get("/disputes", h.listDisputes)
+	r.Get("/disputes/{disputeID}", h.getDispute)
+	r.Post("/disputes/{disputeID}/evidence", h.addEvidence)
+	r.Post("/disputes/{disputeID}:submit", h.submitEvidence)
+}
+
+type addDisputeEvidenceRequest struct {
+	AssetID     string `json:"asset_id"`
+	Kind        string `json:"kind"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+}
+
+type submitDisputeEvidenceRequest struct {
+	Explanation string `json:"explanation"`
+}
+
+type disputeListPayload struct {
+	Disputes      []disputePayload `json:"disputes"`
+	NextPageToken string           `json:"next_page_token,omitempty"`
+}
+
+type disputePayload struct {
+	ID             string                   `json:"id"`
+	OrderID        string                   `json:"order_id"`
+	PaymentID      string                   `json:"payment_id"`
+	Provider       string                   `json:"provider"`
+	PSPDisputeID   string                   `json:"psp_dispute_id"`
+	Status         string                   `json:"status"`
+	Reason         string                   `json:"reason,omitempty"`
+	Amount         int64                    `json:"amount"`
+	Currency       string                   `json:"currency"`
+	EvidenceDueBy  string                   `json:"evidence_due_by,omitempty"`
+	Evidence       []disputeEvidencePayload `json:"evidence"`
+	SubmittedAt    string                   `json:"submitted_at,omitempty"`
+	SubmittedBy    string                   `json:"submitted_by,omitempty"`
+	ReminderSentAt string                   `json:"reminder_sent_at,omitempty"`
+	ClosedAt       string                   `json:"closed_at,omitempty"`
+	CreatedAt      string                   `json:"created_at,omitempty"`
+	UpdatedAt      string                   `json:"updated_at,omitempty"`
+}
+
+type disputeEvidencePayload struct {
+	AssetID     string `json:"asset_id"`
+	Kind        string `json:"kind"`
+	FileName    string `json:"file_name,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	UploadedBy  string `json:"uploaded_by,omitempty"`
+	UploadedAt  string `json:"uploaded_at,omitempty"`
+}
+
+func (h *DisputeHandlers) listDisputes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.disputes == nil {
+		writeDisputesUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultDisputePageSize, maxDisputePageSize)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+	filter := services.DisputeListFilter{
+		OrderID: strings.TrimSpace(values.Get("orderId")),
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	}
+	for _, raw := range strings.Split(values.Get("status"), ",") {
+		if status := strings.ToLower(strings.TrimSpace(raw)); status != "" {
+			filter.Status = append(filter.Status, services.DisputeStatus(status))
+		}
+	}
+	if raw := strings.TrimSpace(values.Get("dueBefore")); raw != "" {
+		dueBefore, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "dueBefore must be an RFC3339 timestamp", http.StatusBadRequest))
+			return
+		}
+		filter.DueBefore = &dueBefore
+	}
+
+	page, err := h.disputes.ListDisputes(ctx, filter)
+	if err != nil {
+		writeDisputeError(w, r, err)
+		return
+	}
+
+	payload := disputeListPayload{
+		Disputes:      make([]disputePayload, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, dispute := range page.Items {
+		payload.Disputes = append(payload.Disputes, buildDisputePayload(dispute))
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *DisputeHandlers) getDispute(w http.ResponseWriter, r *http.Request) {
+	if h.disputes == nil {
+		writeDisputesUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	dispute, err := h.disputes.GetDispute(r.Context(), chi.URLParam(r, "disputeID"))
+	if err != nil {
+		writeDisputeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildDisputePayload(dispute))
+}
+
+func (h *DisputeHandlers) addEvidence(w http.ResponseWriter, r *http.Request) {
+	if h.disputes == nil {
+		writeDisputesUnavailable(w, r)
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req addDisputeEvidenceRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	dispute, err := h.disputes.AddEvidence(r.Context(), services.AddDisputeEvidenceCommand{
+		DisputeID:   chi.URLParam(r, "disputeID"),
+		AssetID:     req.AssetID,
+		Kind:        req.Kind,
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		ActorID:     identity.UID,
+	})
+	if err != nil {
+		writeDisputeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildDisputePayload(dispute))
+}
+
+func (h *DisputeHandlers) submitEvidence(w http.ResponseWriter, r *http.Request) {
+	if h.disputes == nil {
+		writeDisputesUnavailable(w, r)
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req submitDisputeEvidenceRequest
+	if !decodeInternalRequest(w, r, &req, false) {
+		return
+	}
+
+	dispute, err := h.disputes.SubmitEvidence(r.Context(), services.SubmitDisputeEvidenceCommand{
+		DisputeID:   chi.URLParam(r, "disputeID"),
+		Explanation: req.Explanation,
+		ActorID:     identity.UID,
+	})
+	if err != nil {
+		writeDisputeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildDisputePayload(dispute))
+}
+
+func writeDisputesUnavailable(w http.ResponseWriter, r *http.Request) {
+	httpx.WriteError(r.Context(), w, httpx.NewError("disputes_unavailable", "dispute service is unavailable", http.StatusServiceUnavailable))
+}
+
+func writeDisputeError(w http.ResponseWriter, r *http.Request, err error) {
+	writeServiceError(r.Context(), w, err, errorScope{resource: "dispute", service: "dispute"})
+}
+
+func buildDisputePayload(dispute domain.PaymentDispute) disputePayload {
+	payload := disputePayload{
+		ID:           dispute.ID,
+		OrderID:      dispute.OrderID,
+		PaymentID:    dispute.PaymentID,
+		Provider:     dispute.Provider,
+		PSPDisputeID: dispute.PSPDisputeID,
+		Status:       string(dispute.Status),
+		Reason:       dispute.Reason,
+		Amount:       dispute.Amount,
+		Currency:     dispute.Currency,
+		Evidence:     make([]disputeEvidencePayload, 0, len(dispute.Evidence)),
+		SubmittedBy:  dispute.SubmittedBy,
+		CreatedAt:    formatTimestamp(dispute.CreatedAt),
+		UpdatedAt:    formatTimestamp(dispute.UpdatedAt),
+	}
+	if dispute.EvidenceDueBy != nil {
+		payload.EvidenceDueBy = formatTimestamp(*dispute.EvidenceDueBy)
+	}
+	if dispute.SubmittedAt != nil {
+		payload.SubmittedAt = formatTimestamp(*dispute.SubmittedAt)
+	}
+	if dispute.ReminderSentAt != nil {
+		payload.ReminderSentAt = formatTimestamp(*dispute.ReminderSentAt)
+	}
+	if dispute.ClosedAt != nil {
+		payload.ClosedAt = formatTimestamp(*dispute.ClosedAt)
+	}
+	for _, evidence := range dispute.Evidence {
+		payload.Evidence = append(payload.Evidence, disputeEvidencePayload{
+			AssetID:     evidence.AssetID,
+			Kind:        evidence.Kind,
+			FileName:    evidence.FileName,
+			ContentType: evidence.ContentType,
+			UploadedBy:  evidence.UploadedBy,
+			UploadedAt:  formatTimestamp(evidence.UploadedAt),
+		})
+	}
+	return payload
+}
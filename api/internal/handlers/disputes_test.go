@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/services"
+)
+
+type stubDisputeService struct {
+	services.DisputeService
+	dispute   services.PaymentDispute
+	filter    services.DisputeListFilter
+	addCmd    services.AddDisputeEvidenceCommand
+	submitCmd services.SubmitDisputeEvidenceCommand
+	err       error
+}
+
+func (s *stubDisputeService) ListDisputes(_ context.Context, filter services.DisputeListFilter) (domain.CursorPage[services.PaymentDispute], error) {
+	s.filter = filter
+	return domain.CursorPage[services.PaymentDispute]{Items: []services.PaymentDispute{s.dispute}, NextPageToken: "next"}, s.err
+}
+
+func (s *stubDisputeService) GetDispute(_ context.Context, disputeID string) (services.PaymentDispute, error) {
+	if s.err != nil {
+		return services.PaymentDispute{}, s.err
+	}
+	return s.dispute, nil
+}
+
+func (s *stubDisputeService) AddEvidence(_ context.Context, cmd services.AddDisputeEvidenceCommand) (services.PaymentDispute, error) {
+	s.addCmd = cmd
+	return s.dispute, s.err
+}
+
+func (s *stubDisputeService) SubmitEvidence(_ context.Context, cmd services.SubmitDisputeEvidenceCommand) (services.PaymentDispute, error) {
+	s.submitCmd = cmd
+	return s.dispute, s.err
+}
+
+func newDisputeAdminRouter(svc services.DisputeService) chi.Router {
+	router := chi.NewRouter()
+	NewDisputeHandlers(WithDisputeService(svc)).AdminRoutes(router)
+	return router
+}
+
+func TestDisputeHandlers_QueueAndEvidence(t *testing.T) {
+	due := time.Date(2024, 8, 8, 0, 0, 0, 0, time.UTC)
+	svc := &stubDisputeService{dispute: services.PaymentDispute{
+		ID:            "dsp_1",
+		OrderID:       "ord_1",
+		PaymentID:     "pay_1",
+		Provider:      "stripe",
+		PSPDisputeID:  "dp_1",
+		Status:        domain.DisputeStatusNeedsResponse,
+		Amount:        8000,
+		Currency:      "JPY",
+		EvidenceDueBy: &due,
+		Evidence:      []services.DisputeEvidence{{AssetID: "asset_1", Kind: "receipt", UploadedAt: due.Add(-time.Hour)}},
+	}}
+	router := newDisputeAdminRouter(svc)
+
+	req := newIdentityRequest(http.MethodGet, "/disputes?status=needs_response,under_review&dueBefore=2024-08-10T00:00:00Z&pageSize=10", "", "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(svc.filter.Status) != 2 || svc.filter.DueBefore == nil || svc.filter.Pagination.PageSize != 10 {
+		t.Fatalf("unexpected filter %+v", svc.filter)
+	}
+	var list disputeListPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(list.Disputes) != 1 || list.Disputes[0].EvidenceDueBy != "2024-08-08T00:00:00Z" || list.NextPageToken != "next" || len(list.Disputes[0].Evidence) != 1 {
+		t.Fatalf("unexpected list payload %+v", list)
+	}
+
+	body := `{"asset_id":"asset_2","kind":"shipping_documentation","file_name":"tracking.pdf","content_type":"application/pdf"}`
+	req = newIdentityRequest(http.MethodPost, "/disputes/dsp_1/evidence", body, "staff_1", "staff")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if svc.addCmd.DisputeID != "dsp_1" || svc.addCmd.AssetID != "asset_2" || svc.addCmd.Kind != "shipping_documentation" || svc.addCmd.ActorID != "staff_1" {
+		t.Fatalf("unexpected evidence command %+v", svc.addCmd)
+	}
+
+	req = newIdentityRequest(http.MethodPost, "/disputes/dsp_1:submit", `{"explanation":"Delivered"}`, "staff_1", "staff")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if svc.submitCmd.DisputeID != "dsp_1" || svc.submitCmd.Explanation != "Delivered" {
+		t.Fatalf("unexpected submit command %+v", svc.submitCmd)
+	}
+}
+
+func TestDisputeHandlers_Errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		roles  []string
+		err    error
+		status int
+	}{
+		{name: "non staff", method: http.MethodGet, target: "/disputes", roles: []string{"user"}, status: http.StatusForbidden},
+		{name: "bad due before", method: http.MethodGet, target: "/disputes?dueBefore=tomorrow", roles: []string{"staff"}, status: http.StatusBadRequest},
+		{name: "not found", method: http.MethodGet, target: "/disputes/dsp_x", roles: []string{"staff"}, err: services.ErrDisputeNotFound, status: http.StatusNotFound},
+		{name: "already submitted", method: http.MethodPost, target: "/disputes/dsp_1:submit", body: `{}`, roles: []string{"staff"}, err: fmt.Errorf("%w: dispute dsp_1 is under_review", services.ErrDisputeInvalidState), status: http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newIdentityRequest(tc.method, tc.target, tc.body, "staff_1", tc.roles...)
+			rec := httptest.NewRecorder()
+			newDisputeAdminRouter(&stubDisputeService{err: tc.err}).ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+var defaultErrorTranslator = services.NewErrorTranslator()
+
+// errorScope names the resource and backing service of an endpoint so generic repository failures
+// (not found, conflict, unavailable) are reported with resource-specific codes.
+type errorScope struct {
+	resource string
+	service  string
+}
+
+// writeServiceError translates a service error into the canonical error envelope. Status codes are
+// derived from the error kind; codes and messages come from the translator and are safe for clients.
+func writeServiceError(ctx context.Context, w http.ResponseWriter, err error, scope errorScope) {
+	if err == nil {
+		return
+	}
+	httpx.WriteError(ctx, w, translateServiceError(err, scope))
+}
+
+func translateServiceError(err error, scope errorScope) httpx.Error {
+	translated := defaultErrorTranslator.Translate(err)
+
+	code := services.ErrorCodeInternal
+	message := "an internal error occurred"
+	var domainErr services.DomainError
+	if errors.As(translated, &domainErr) {
+		code = domainErr.Code()
+		message = domainErr.SafeMessage()
+	}
+	kind := services.ErrorKindOf(translated)
+
+	resource := strings.TrimSpace(scope.resource)
+	service := strings.TrimSpace(scope.service)
+	switch code {
+	case services.ErrorCodeNotFound:
+		if resource != "" {
+			code = resource + "_not_found"
+			message = strings.ReplaceAll(resource, "_", " ") + " not found"
+		}
+	case services.ErrorCodeConflict:
+		if resource != "" {
+			code = resource + "_conflict"
+		}
+	case services.ErrorCodeUnavailable:
+		if service != "" {
+			code = service + "_unavailable"
+			message = strings.ReplaceAll(service, "_", " ") + " dependency unavailable"
+		}
+	case services.ErrorCodeInternal:
+		if service != "" {
+			code = service + "_error"
+		}
+	}
+
+	httpErr := httpx.NewError(code, message, statusForErrorKind(kind))
+	var priceChanged *services.PriceChangedError
+	if errors.As(err, &priceChanged) {
+		httpErr = httpErr.WithDetails(priceChangedDetails(priceChanged))
+	}
+	return httpErr
+}
+
+// priceChangedDetails exposes the quote diff so clients can show the shopper what moved.
+func priceChangedDetails(err *services.PriceChangedError) map[string]any {
+	changes := make([]map[string]any, 0, len(err.Changes))
+	for _, change := range err.Changes {
+		changes = append(changes, map[string]any{
+			"field":   change.Field,
+			"quoted":  change.Quoted,
+			"current": change.Current,
+		})
+	}
+	return map[string]any{"quote_id": err.QuoteID, "changes": changes}
+}
+
+func statusForErrorKind(kind services.ErrorKind) int {
+	switch kind {
+	case services.ErrorKindInvalidInput:
+		return http.StatusBadRequest
+	case services.ErrorKindUnauthorized:
+		return http.StatusForbidden
+	case services.ErrorKindNotFound:
+		return http.StatusNotFound
+	case services.ErrorKindConflict, services.ErrorKindInvalidState:
+		return http.StatusConflict
+	case services.ErrorKindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hanko-field/api/internal/services"
+)
+
+func TestTranslateServiceError(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		scope  errorScope
+		code   string
+		status int
+	}{
+		{
+			name:   "sentinel keeps stable code",
+			err:    fmt.Errorf("%w: already shipped", services.ErrOrderInvalidState),
+			scope:  errorScope{resource: "order", service: "orders"},
+			code:   "order_invalid_state",
+			status: http.StatusConflict,
+		},
+		{
+			name:   "repository not found uses resource",
+			err:    newRepositoryError(true, false, false),
+			scope:  errorScope{resource: "product", service: "catalog"},
+			code:   "product_not_found",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "repository unavailable uses service",
+			err:    newRepositoryError(false, false, true),
+			scope:  errorScope{resource: "product", service: "catalog"},
+			code:   "catalog_unavailable",
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "unauthorized maps to forbidden",
+			err:    services.ErrReviewUnauthorized,
+			scope:  errorScope{resource: "review", service: "reviews"},
+			code:   "review_forbidden",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "price changes carry the diff",
+			err:    &services.PriceChangedError{QuoteID: "pq_1", Changes: []services.PriceChange{{Field: "total", Quoted: 1000, Current: 1200}}},
+			scope:  errorScope{resource: "checkout", service: "pricing"},
+			code:   "price_changed",
+			status: http.StatusConflict,
+		},
+		{
+			name:   "unknown errors are masked",
+			err:    fmt.Errorf("dial tcp 10.0.0.1: refused"),
+			scope:  errorScope{service: "inventory"},
+			code:   "inventory_error",
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := translateServiceError(tc.err, tc.scope)
+			if got.Code != tc.code || got.Status != tc.status {
+				t.Fatalf("expected %s/%d got %s/%d", tc.code, tc.status, got.Code, got.Status)
+			}
+			if tc.code == "price_changed" && (got.Details["quote_id"] != "pq_1" || len(got.Details["changes"].([]map[string]any)) != 1) {
+				t.Fatalf("expected price diff details got %+v", got.Details)
+			}
+			if tc.status == http.StatusInternalServerError && got.Message != "an internal error occurred" {
+				t.Fatalf("expected masked message got %q", got.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,707 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/hanko-field/api/internal/platform/auth"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const (
+	defaultInternalBodyLimit  = int64(256 << 10)
+	defaultReservationTTL     = 15 * time.Minute
+	maxReservationTTL         = 24 * time.Hour
+	defaultCleanupBatchSize   = 100
+	defaultCleanupLimit       = 1000
+	internalActorType         = "service"
+	internalAuditServiceLabel = "internal"
+)
+
+// InternalHandlers exposes server-to-server endpoints invoked by checkout workers and Cloud Scheduler.
+// Requests must carry a verified OIDC service identity; the identity is recorded as the audit actor.
+type InternalHandlers struct {
+	inventory        services.InventoryService
+	sweeper          services.ReservationSweeper
+	promotions       services.PromotionService
+	disputes         services.DisputeService
+	subscriptions    services.StockSubscriptionService
+	audit            services.AuditLogService
+	reservationTTL   time.Duration
+	cleanupBatchSize int
+	cleanupLimit     int
+}
+
+// InternalOption customises construction of InternalHandlers.
+type InternalOption func(*InternalHandlers)
+
+// WithInternalInventoryService injects the inventory service used for reservation flows.
+func WithInternalInventoryService(svc services.InventoryService) InternalOption {
+	return func(h *InternalHandlers) {
+		h.inventory = svc
+	}
+}
+
+// WithInternalReservationSweeper injects the sweeper used by the reservation cleanup so that draft
+// orders holding released reservations are cancelled as well.
+func WithInternalReservationSweeper(sweeper services.ReservationSweeper) InternalOption {
+	return func(h *InternalHandlers) {
+		h.sweeper = sweeper
+	}
+}
+
+// WithInternalPromotionService injects the promotion service used to record usage.
+func WithInternalPromotionService(svc services.PromotionService) InternalOption {
+	return func(h *InternalHandlers) {
+		h.promotions = svc
+	}
+}
+
+// WithInternalDisputeService injects the dispute service used for evidence deadline reminders.
+func WithInternalDisputeService(svc services.DisputeService) InternalOption {
+	return func(h *InternalHandlers) {
+		h.disputes = svc
+	}
+}
+
+// WithInternalStockSubscriptionService injects the subscription service used to expire stale
+// back-in-stock subscriptions.
+func WithInternalStockSubscriptionService(svc services.StockSubscriptionService) InternalOption {
+	return func(h *InternalHandlers) {
+		h.subscriptions = svc
+	}
+}
+
+// WithInternalAuditLogService injects the audit log writer for internal mutations.
+func WithInternalAuditLogService(svc services.AuditLogService) InternalOption {
+	return func(h *InternalHandlers) {
+		h.audit = svc
+	}
+}
+
+// WithInternalReservationTTL overrides the reservation TTL applied when callers omit ttl_sec.
+func WithInternalReservationTTL(ttl time.Duration) InternalOption {
+	return func(h *InternalHandlers) {
+		if ttl > 0 {
+			h.reservationTTL = ttl
+		}
+	}
+}
+
+// WithInternalCleanupBounds overrides the batch size and per-invocation limit of the reservation cleanup.
+func WithInternalCleanupBounds(batchSize, limit int) InternalOption {
+	return func(h *InternalHandlers) {
+		if batchSize > 0 {
+			h.cleanupBatchSize = batchSize
+		}
+		if limit > 0 {
+			h.cleanupLimit = limit
+		}
+	}
+}
+
+// NewInternalHandlers constructs internal handlers with the provided options.
+func NewInternalHandlers(opts ...InternalOption) *InternalHandlers {
+	handler := &InternalHandlers{
+		reservationTTL:   defaultReservationTTL,
+		cleanupBatchSize: defaultCleanupBatchSize,
+		cleanupLimit:     defaultCleanupLimit,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// Routes registers internal endpoints on the provided router.
+func (h *InternalHandlers) Routes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Post("/checkout/reserve-stock", h.reserveStock)
+	r.Post("/checkout/commit", h.commitReservation)
+	r.Post("/checkout/release", h.releaseReservation)
+	r.Post("/promotions/apply", h.applyPromotion)
+	r.Post("/maintenance/cleanup-reservations", h.cleanupReservations)
+	r.Post("/maintenance/dispute-deadlines", h.remindDisputeDeadlines)
+	r.Post("/maintenance/expire-stock-subscriptions", h.expireStockSubscriptions)
+}
+
+type reserveStockRequest struct {
+	OrderID            string                   `json:"order_id"`
+	UserID             string                   `json:"user_id"`
+	Lines              []reserveStockLineRecord `json:"lines"`
+	TTLSeconds         int                      `json:"ttl_sec"`
+	Reason             string                   `json:"reason"`
+	IdempotencyKey     string                   `json:"idempotency_key"`
+	ShippingPrefecture string                   `json:"shipping_prefecture"`
+}
+
+type reserveStockLineRecord struct {
+	ProductID string `json:"product_id"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+}
+
+type reservationTransitionRequest struct {
+	ReservationID string `json:"reservation_id"`
+	OrderID       string `json:"order_id"`
+	Reason        string `json:"reason"`
+}
+
+type applyPromotionRequest struct {
+	PromotionID string `json:"promotion_id"`
+	UserID      string `json:"user_id"`
+	OrderID     string `json:"order_id"`
+}
+
+type cleanupReservationsRequest struct {
+	BatchSize      int                    `json:"batch_size"`
+	Limit          int                    `json:"limit"`
+	ReservationIDs []string               `json:"reservation_ids"`
+	Message        *pubsubPushMessageBody `json:"message"`
+}
+
+// pubsubPushMessageBody is the message envelope of a Pub/Sub push delivery. Data carries a
+// base64 encoded services.StockCleanupMessage.
+type pubsubPushMessageBody struct {
+	Data       []byte            `json:"data"`
+	MessageID  string            `json:"messageId"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+type disputeDeadlinesRequest struct {
+	WindowHours int `json:"window_hours"`
+	Limit       int `json:"limit"`
+}
+
+type expireStockSubscriptionsRequest struct {
+	Limit int `json:"limit"`
+}
+
+type reservationPayload struct {
+	ID          string                   `json:"id"`
+	OrderRef    string                   `json:"order_ref,omitempty"`
+	UserRef     string                   `json:"user_ref,omitempty"`
+	Status      string                   `json:"status"`
+	Lines       []reservationLinePayload `json:"lines"`
+	Reason      string                   `json:"reason,omitempty"`
+	ExpiresAt   string                   `json:"expires_at,omitempty"`
+	CommittedAt string                   `json:"committed_at,omitempty"`
+	ReleasedAt  string                   `json:"released_at,omitempty"`
+	CreatedAt   string                   `json:"created_at,omitempty"`
+	UpdatedAt   string                   `json:"updated_at,omitempty"`
+}
+
+type reservationLinePayload struct {
+	ProductRef string `json:"product_ref"`
+	SKU        string `json:"sku"`
+	Quantity   int    `json:"quantity"`
+	LocationID string `json:"location_id,omitempty"`
+}
+
+type promotionUsagePayload struct {
+	PromotionID string `json:"promotion_id"`
+	UserID      string `json:"user_id"`
+	Times       int    `json:"times"`
+	LastUsed    string `json:"last_used,omitempty"`
+}
+
+type cleanupReservationsPayload struct {
+	Checked     int      `json:"checked"`
+	Released    int      `json:"released"`
+	Skipped     int      `json:"skipped"`
+	Failed      int      `json:"failed"`
+	ReleasedIDs []string `json:"released_ids,omitempty"`
+	FailedIDs   []string `json:"failed_ids,omitempty"`
+	HasMore     bool     `json:"has_more"`
+
+	CanceledOrderIDs     []string `json:"canceled_order_ids,omitempty"`
+	OrderCancelFailedIDs []string `json:"order_cancel_failed_ids,omitempty"`
+}
+
+type disputeDeadlinesPayload struct {
+	Checked     int      `json:"checked"`
+	Reminded    int      `json:"reminded"`
+	ReminderIDs []string `json:"reminder_ids,omitempty"`
+}
+
+type expireStockSubscriptionsPayload struct {
+	Checked    int      `json:"checked"`
+	Expired    int      `json:"expired"`
+	ExpiredIDs []string `json:"expired_ids,omitempty"`
+}
+
+func (h *InternalHandlers) reserveStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.inventory == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("inventory_unavailable", "inventory service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req reserveStockRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	ttl := h.reservationTTL
+	if req.TTLSeconds < 0 {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "ttl_sec must be positive", http.StatusBadRequest))
+		return
+	}
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > maxReservationTTL {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", fmt.Sprintf("ttl_sec must not exceed %d", int(maxReservationTTL.Seconds())), http.StatusBadRequest))
+		return
+	}
+
+	lines := make([]services.InventoryLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, services.InventoryLine{
+			ProductID: strings.TrimSpace(line.ProductID),
+			SKU:       strings.TrimSpace(line.SKU),
+			Quantity:  line.Quantity,
+		})
+	}
+
+	reservation, err := h.inventory.ReserveStocks(ctx, services.InventoryReserveCommand{
+		OrderID:            strings.TrimSpace(req.OrderID),
+		UserID:             strings.TrimSpace(req.UserID),
+		Lines:              lines,
+		TTL:                ttl,
+		Reason:             strings.TrimSpace(req.Reason),
+		IdempotencyKey:     strings.TrimSpace(req.IdempotencyKey),
+		ShippingPrefecture: strings.TrimSpace(req.ShippingPrefecture),
+	})
+	if err != nil {
+		writeInventoryError(ctx, w, err)
+		return
+	}
+
+	h.recordAudit(r, actor, "inventory.reserve", reservationTargetRef(reservation.ID), map[string]any{
+		"orderRef":  reservation.OrderRef,
+		"lineCount": len(reservation.Lines),
+		"expiresAt": formatTimestamp(reservation.ExpiresAt),
+	})
+
+	writeJSON(w, http.StatusOK, buildReservationPayload(reservation))
+}
+
+func (h *InternalHandlers) commitReservation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.inventory == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("inventory_unavailable", "inventory service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req reservationTransitionRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	reservation, err := h.inventory.CommitReservation(ctx, services.InventoryCommitCommand{
+		ReservationID: strings.TrimSpace(req.ReservationID),
+		OrderID:       strings.TrimSpace(req.OrderID),
+		ActorID:       actor,
+	})
+	if err != nil {
+		writeInventoryError(ctx, w, err)
+		return
+	}
+
+	h.recordAudit(r, actor, "inventory.commit", reservationTargetRef(reservation.ID), map[string]any{
+		"orderRef": reservation.OrderRef,
+	})
+
+	writeJSON(w, http.StatusOK, buildReservationPayload(reservation))
+}
+
+func (h *InternalHandlers) releaseReservation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.inventory == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("inventory_unavailable", "inventory service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req reservationTransitionRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	reservation, err := h.inventory.ReleaseReservation(ctx, services.InventoryReleaseCommand{
+		ReservationID: strings.TrimSpace(req.ReservationID),
+		Reason:        strings.TrimSpace(req.Reason),
+		ActorID:       actor,
+	})
+	if err != nil {
+		writeInventoryError(ctx, w, err)
+		return
+	}
+
+	h.recordAudit(r, actor, "inventory.release", reservationTargetRef(reservation.ID), map[string]any{
+		"orderRef": reservation.OrderRef,
+		"reason":   reservation.Reason,
+	})
+
+	writeJSON(w, http.StatusOK, buildReservationPayload(reservation))
+}
+
+func (h *InternalHandlers) applyPromotion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.promotions == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("promotions_unavailable", "promotion service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req applyPromotionRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	promotionID := strings.TrimSpace(req.PromotionID)
+	userID := strings.TrimSpace(req.UserID)
+	if promotionID == "" || userID == "" {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "promotion_id and user_id are required", http.StatusBadRequest))
+		return
+	}
+
+	usage, err := h.promotions.ApplyPromotionUsage(ctx, services.ApplyPromotionUsageCommand{
+		PromotionID: promotionID,
+		UserID:      userID,
+		OrderID:     strings.TrimSpace(req.OrderID),
+		ActorID:     actor,
+	})
+	if err != nil {
+		writePromotionUsageError(ctx, w, err)
+		return
+	}
+
+	h.recordAudit(r, actor, "promotion.apply", fmt.Sprintf("/promotions/%s", promotionID), map[string]any{
+		"userId":  userID,
+		"orderId": strings.TrimSpace(req.OrderID),
+		"times":   usage.Times,
+	})
+
+	payload := promotionUsagePayload{
+		PromotionID: promotionID,
+		UserID:      usage.UserID,
+		Times:       usage.Times,
+		LastUsed:    formatTimestamp(usage.LastUsed),
+	}
+	if payload.UserID == "" {
+		payload.UserID = userID
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *InternalHandlers) cleanupReservations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.inventory == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("inventory_unavailable", "inventory service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req cleanupReservationsRequest
+	if !decodeInternalRequest(w, r, &req, false) {
+		return
+	}
+
+	batchSize := h.cleanupBatchSize
+	if req.BatchSize > 0 && req.BatchSize < batchSize {
+		batchSize = req.BatchSize
+	}
+	limit := h.cleanupLimit
+	if req.Limit > 0 && req.Limit < limit {
+		limit = req.Limit
+	}
+
+	reservationIDs := copyStringSlice(req.ReservationIDs)
+	if req.Message != nil && len(req.Message.Data) > 0 {
+		var job services.StockCleanupMessage
+		if err := json.Unmarshal(req.Message.Data, &job); err != nil {
+			httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "message data must be a stock cleanup job", http.StatusBadRequest))
+			return
+		}
+		reservationIDs = append(reservationIDs, job.ReservationIDs...)
+	}
+
+	var (
+		result services.ReleaseExpiredReservationsResult
+		err    error
+	)
+	switch {
+	case len(reservationIDs) > 0 && h.sweeper != nil:
+		result, err = h.sweeper.ReleaseReservations(ctx, services.ReleaseReservationsCommand{
+			ReservationIDs: reservationIDs,
+			ActorID:        actor,
+		})
+	case len(reservationIDs) > 0:
+		httpx.WriteError(ctx, w, httpx.NewError("inventory_unavailable", "reservation sweeper is unavailable", http.StatusServiceUnavailable))
+		return
+	case h.sweeper != nil:
+		result, err = h.sweeper.SweepExpired(ctx, services.ReleaseExpiredReservationsCommand{
+			BatchSize: batchSize,
+			Limit:     limit,
+			ActorID:   actor,
+		})
+	default:
+		result, err = h.inventory.ReleaseExpiredReservations(ctx, services.ReleaseExpiredReservationsCommand{
+			BatchSize: batchSize,
+			Limit:     limit,
+			ActorID:   actor,
+		})
+	}
+	if err != nil {
+		writeInventoryError(ctx, w, err)
+		return
+	}
+
+	h.recordAudit(r, actor, "inventory.cleanup_reservations", "/stockReservations", map[string]any{
+		"checked":        result.CheckedCount,
+		"released":       result.ReleasedCount,
+		"skipped":        result.SkippedCount,
+		"failed":         len(result.FailedIDs),
+		"hasMore":        result.HasMore,
+		"canceledOrders": len(result.CanceledOrderIDs),
+	})
+
+	writeJSON(w, http.StatusOK, cleanupReservationsPayload{
+		Checked:     result.CheckedCount,
+		Released:    result.ReleasedCount,
+		Skipped:     result.SkippedCount,
+		Failed:      len(result.FailedIDs),
+		ReleasedIDs: copyStringSlice(result.ReleasedIDs),
+		FailedIDs:   copyStringSlice(result.FailedIDs),
+		HasMore:     result.HasMore,
+
+		CanceledOrderIDs:     copyStringSlice(result.CanceledOrderIDs),
+		OrderCancelFailedIDs: copyStringSlice(result.OrderCancelFailedIDs),
+	})
+}
+
+func (h *InternalHandlers) remindDisputeDeadlines(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.disputes == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("disputes_unavailable", "dispute service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req disputeDeadlinesRequest
+	if !decodeInternalRequest(w, r, &req, false) {
+		return
+	}
+	if req.WindowHours < 0 || req.Limit < 0 {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "window_hours and limit must not be negative", http.StatusBadRequest))
+		return
+	}
+
+	result, err := h.disputes.RemindEvidenceDue(ctx, services.DisputeReminderCommand{
+		Window: time.Duration(req.WindowHours) * time.Hour,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		writeServiceError(ctx, w, err, errorScope{resource: "dispute", service: "dispute"})
+		return
+	}
+
+	h.recordAudit(r, actor, "payment.dispute.remind_deadlines", "/disputes", map[string]any{
+		"checked":  result.Checked,
+		"reminded": len(result.Reminded),
+	})
+
+	writeJSON(w, http.StatusOK, disputeDeadlinesPayload{
+		Checked:     result.Checked,
+		Reminded:    len(result.Reminded),
+		ReminderIDs: copyStringSlice(result.Reminded),
+	})
+}
+
+func (h *InternalHandlers) expireStockSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.subscriptions == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("stock_subscriptions_unavailable", "stock subscription service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	actor, ok := requireServiceActor(w, r)
+	if !ok {
+		return
+	}
+
+	var req expireStockSubscriptionsRequest
+	if !decodeInternalRequest(w, r, &req, false) {
+		return
+	}
+	if req.Limit < 0 {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "limit must not be negative", http.StatusBadRequest))
+		return
+	}
+
+	result, err := h.subscriptions.ExpireSubscriptions(ctx, services.ExpireStockSubscriptionsCommand{Limit: req.Limit})
+	if err != nil {
+		writeStockSubscriptionError(ctx, w, err)
+		return
+	}
+
+	h.recordAudit(r, actor, "inventory.stock_subscriptions.expire", "/stockSubscriptions", map[string]any{
+		"checked": result.Checked,
+		"expired": len(result.Expired),
+	})
+
+	writeJSON(w, http.StatusOK, expireStockSubscriptionsPayload{
+		Checked:    result.Checked,
+		Expired:    len(result.Expired),
+		ExpiredIDs: copyStringSlice(result.Expired),
+	})
+}
+
+func (h *InternalHandlers) recordAudit(r *http.Request, actor, action, target string, metadata map[string]any) {
+	if h.audit == nil {
+		return
+	}
+	meta := make(map[string]any, len(metadata)+2)
+	for key, value := range metadata {
+		meta[key] = value
+	}
+	meta["service"] = internalAuditServiceLabel
+	if identity, ok := auth.ServiceIdentityFromContext(r.Context()); ok && strings.TrimSpace(identity.Issuer) != "" {
+		meta["issuer"] = strings.TrimSpace(identity.Issuer)
+	}
+	h.audit.Record(r.Context(), services.AuditLogRecord{
+		Actor:     actor,
+		ActorType: internalActorType,
+		Action:    action,
+		TargetRef: target,
+		RequestID: middleware.GetReqID(r.Context()),
+		Metadata:  meta,
+		UserAgent: r.UserAgent(),
+	})
+}
+
+// requireServiceActor resolves the OIDC service identity attached by the internal middleware.
+func requireServiceActor(w http.ResponseWriter, r *http.Request) (string, bool) {
+	identity, ok := auth.ServiceIdentityFromContext(r.Context())
+	if !ok {
+		httpx.WriteError(r.Context(), w, httpx.NewError("unauthenticated", "service identity required", http.StatusUnauthorized))
+		return "", false
+	}
+	principal := strings.TrimSpace(identity.Email)
+	if principal == "" {
+		principal = strings.TrimSpace(identity.Subject)
+	}
+	if principal == "" {
+		httpx.WriteError(r.Context(), w, httpx.NewError("unauthenticated", "service identity required", http.StatusUnauthorized))
+		return "", false
+	}
+	return "service:" + principal, true
+}
+
+func decodeInternalRequest(w http.ResponseWriter, r *http.Request, dst any, required bool) bool {
+	if r.Body == nil {
+		if required {
+			httpx.WriteError(r.Context(), w, httpx.NewError("invalid_request", "request body is required", http.StatusBadRequest))
+			return false
+		}
+		return true
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, defaultInternalBodyLimit))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			httpx.WriteError(r.Context(), w, httpx.NewError("payload_too_large", "request body exceeds limit", http.StatusRequestEntityTooLarge))
+			return false
+		}
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_request", "failed to read request body", http.StatusBadRequest))
+		return false
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		if required {
+			httpx.WriteError(r.Context(), w, httpx.NewError("invalid_request", "request body is required", http.StatusBadRequest))
+			return false
+		}
+		return true
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_request", "request body must be valid JSON", http.StatusBadRequest))
+		return false
+	}
+	return true
+}
+
+func writeInventoryError(ctx context.Context, w http.ResponseWriter, err error) {
+	writeServiceError(ctx, w, err, errorScope{resource: "stock", service: "inventory"})
+}
+
+func writePromotionUsageError(ctx context.Context, w http.ResponseWriter, err error) {
+	writeServiceError(ctx, w, err, errorScope{resource: "promotion", service: "promotions"})
+}
+
+func reservationTargetRef(id string) string {
+	return fmt.Sprintf("/stockReservations/%s", strings.TrimSpace(id))
+}
+
+func buildReservationPayload(reservation services.InventoryReservation) reservationPayload {
+	lines := make([]reservationLinePayload, 0, len(reservation.Lines))
+	for _, line := range reservation.Lines {
+		lines = append(lines, reservationLinePayload{
+			ProductRef: line.ProductRef,
+			SKU:        line.SKU,
+			Quantity:   line.Quantity,
+			LocationID: line.LocationID,
+		})
+	}
+	payload := reservationPayload{
+		ID:        reservation.ID,
+		OrderRef:  reservation.OrderRef,
+		UserRef:   reservation.UserRef,
+		Status:    reservation.Status,
+		Lines:     lines,
+		Reason:    reservation.Reason,
+		ExpiresAt: formatTimestamp(reservation.ExpiresAt),
+		CreatedAt: formatTimestamp(reservation.CreatedAt),
+		UpdatedAt: formatTimestamp(reservation.UpdatedAt),
+	}
+	if reservation.CommittedAt != nil {
+		payload.CommittedAt = formatTimestamp(*reservation.CommittedAt)
+	}
+	if reservation.ReleasedAt != nil {
+		payload.ReleasedAt = formatTimestamp(*reservation.ReleasedAt)
+	}
+	return payload
+}
@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/auth"
+	"github.com/hanko-field/api/internal/services"
+)
+
+func TestInternalHandlersReserveStock(t *testing.T) {
+	expires := time.Date(2025, 5, 1, 10, 15, 0, 0, time.UTC)
+	inventory := &stubInventoryService{
+		reserveFn: func(_ context.Context, cmd services.InventoryReserveCommand) (services.InventoryReservation, error) {
+			if cmd.OrderID != "ord_1" || cmd.UserID != "usr_1" {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			if cmd.TTL != 10*time.Minute {
+				t.Fatalf("expected ttl 10m, got %s", cmd.TTL)
+			}
+			if len(cmd.Lines) != 1 || cmd.Lines[0].SKU != "SKU-1" || cmd.Lines[0].Quantity != 2 {
+				t.Fatalf("unexpected lines %+v", cmd.Lines)
+			}
+			return services.InventoryReservation{
+				ID:        "sr_1",
+				OrderRef:  "/orders/ord_1",
+				Status:    "reserved",
+				Lines:     []domain.InventoryReservationLine{{ProductRef: "/products/prod_1", SKU: "SKU-1", Quantity: 2}},
+				ExpiresAt: expires,
+			}, nil
+		},
+	}
+	audit := &captureAuditLog{}
+	handler := NewInternalHandlers(WithInternalInventoryService(inventory), WithInternalAuditLogService(audit))
+	router := chi.NewRouter()
+	handler.Routes(router)
+
+	body := `{"order_id":"ord_1","user_id":"usr_1","ttl_sec":600,"lines":[{"product_id":"prod_1","sku":"SKU-1","quantity":2}]}`
+	req := newServiceRequest(http.MethodPost, "/checkout/reserve-stock", body)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload reservationPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.ID != "sr_1" || payload.ExpiresAt != "2025-05-01T10:15:00Z" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if len(audit.records) != 1 {
+		t.Fatalf("expected audit record, got %d", len(audit.records))
+	}
+	record := audit.records[0]
+	if record.Actor != "service:scheduler@example.iam.gserviceaccount.com" || record.ActorType != "service" {
+		t.Fatalf("unexpected audit actor %+v", record)
+	}
+	if record.Action != "inventory.reserve" || record.TargetRef != "/stockReservations/sr_1" {
+		t.Fatalf("unexpected audit record %+v", record)
+	}
+}
+
+func TestInternalHandlersRequireServiceIdentity(t *testing.T) {
+	handler := NewInternalHandlers(WithInternalInventoryService(&stubInventoryService{}))
+	req := httptest.NewRequest(http.MethodPost, "/checkout/commit", strings.NewReader(`{"reservation_id":"sr_1"}`))
+	rec := httptest.NewRecorder()
+	handler.commitReservation(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestInternalHandlersCommitErrorMapping(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "not found", err: services.ErrInventoryReservationNotFound, status: http.StatusNotFound},
+		{name: "invalid state", err: services.ErrInventoryInvalidState, status: http.StatusConflict},
+		{name: "invalid input", err: services.ErrInventoryInvalidInput, status: http.StatusBadRequest},
+		{name: "unavailable", err: newRepositoryError(false, false, true), status: http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inventory := &stubInventoryService{
+				commitFn: func(context.Context, services.InventoryCommitCommand) (services.InventoryReservation, error) {
+					return services.InventoryReservation{}, tc.err
+				},
+			}
+			handler := NewInternalHandlers(WithInternalInventoryService(inventory))
+			req := newServiceRequest(http.MethodPost, "/checkout/commit", `{"reservation_id":"sr_1","order_id":"ord_1"}`)
+			rec := httptest.NewRecorder()
+			handler.commitReservation(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d", tc.status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestInternalHandlersReleasePassesActor(t *testing.T) {
+	var captured services.InventoryReleaseCommand
+	inventory := &stubInventoryService{
+		releaseFn: func(_ context.Context, cmd services.InventoryReleaseCommand) (services.InventoryReservation, error) {
+			captured = cmd
+			return services.InventoryReservation{ID: cmd.ReservationID, Status: "released"}, nil
+		},
+	}
+	handler := NewInternalHandlers(WithInternalInventoryService(inventory))
+	req := newServiceRequest(http.MethodPost, "/checkout/release", `{"reservation_id":"sr_1","reason":"payment_failed"}`)
+	rec := httptest.NewRecorder()
+	handler.releaseReservation(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if captured.ActorID != "service:scheduler@example.iam.gserviceaccount.com" || captured.Reason != "payment_failed" {
+		t.Fatalf("unexpected release command %+v", captured)
+	}
+}
+
+func TestInternalHandlersApplyPromotion(t *testing.T) {
+	promotions := &stubInternalPromotionService{
+		usage: services.PromotionUsage{UserID: "usr_1", Times: 2, LastUsed: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	handler := NewInternalHandlers(WithInternalPromotionService(promotions))
+
+	req := newServiceRequest(http.MethodPost, "/promotions/apply", `{"promotion_id":"promo_1","user_id":"usr_1","order_id":"ord_1"}`)
+	rec := httptest.NewRecorder()
+	handler.applyPromotion(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if promotions.cmd.PromotionID != "promo_1" || promotions.cmd.OrderID != "ord_1" {
+		t.Fatalf("unexpected command %+v", promotions.cmd)
+	}
+	var payload promotionUsagePayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Times != 2 || payload.PromotionID != "promo_1" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	req = newServiceRequest(http.MethodPost, "/promotions/apply", `{"promotion_id":"promo_1"}`)
+	rec = httptest.NewRecorder()
+	handler.applyPromotion(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for missing user, got %d", rec.Code)
+	}
+}
+
+func TestInternalHandlersCleanupReservationsBoundsRequest(t *testing.T) {
+	var captured services.ReleaseExpiredReservationsCommand
+	inventory := &stubInventoryService{
+		expiredFn: func(_ context.Context, cmd services.ReleaseExpiredReservationsCommand) (services.ReleaseExpiredReservationsResult, error) {
+			captured = cmd
+			return services.ReleaseExpiredReservationsResult{
+				CheckedCount:  3,
+				ReleasedCount: 2,
+				ReleasedIDs:   []string{"sr_1", "sr_2"},
+				FailedIDs:     []string{"sr_3"},
+				HasMore:       true,
+			}, nil
+		},
+	}
+	handler := NewInternalHandlers(WithInternalInventoryService(inventory), WithInternalCleanupBounds(50, 200))
+
+	req := newServiceRequest(http.MethodPost, "/maintenance/cleanup-reservations", `{"batch_size":500,"limit":20}`)
+	rec := httptest.NewRecorder()
+	handler.cleanupReservations(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if captured.BatchSize != 50 || captured.Limit != 20 {
+		t.Fatalf("expected bounded batch 50 and limit 20, got %+v", captured)
+	}
+	var payload cleanupReservationsPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Released != 2 || payload.Failed != 1 || !payload.HasMore {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	req = newServiceRequest(http.MethodPost, "/maintenance/cleanup-reservations", "")
+	rec = httptest.NewRecorder()
+	handler.cleanupReservations(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected empty body to be accepted, got %d", rec.Code)
+	}
+	if captured.BatchSize != 50 || captured.Limit != 200 {
+		t.Fatalf("expected configured defaults, got %+v", captured)
+	}
+}
+
+type stubReservationSweeper struct {
+	sweepCmd   services.ReleaseExpiredReservationsCommand
+	releaseCmd services.ReleaseReservationsCommand
+	result     services.ReleaseExpiredReservationsResult
+	err        error
+}
+
+func (s *stubReservationSweeper) SweepExpired(_ context.Context, cmd services.ReleaseExpiredReservationsCommand) (services.ReleaseExpiredReservationsResult, error) {
+	s.sweepCmd = cmd
+	return s.result, s.err
+}
+
+func (s *stubReservationSweeper) ReleaseReservations(_ context.Context, cmd services.ReleaseReservationsCommand) (services.ReleaseExpiredReservationsResult, error) {
+	s.releaseCmd = cmd
+	return s.result, s.err
+}
+
+func TestInternalHandlersCleanupReservationsUsesSweeper(t *testing.T) {
+	sweeper := &stubReservationSweeper{result: services.ReleaseExpiredReservationsResult{
+		CheckedCount:     1,
+		ReleasedCount:    1,
+		ReleasedIDs:      []string{"sr_1"},
+		CanceledOrderIDs: []string{"ord_1"},
+	}}
+	handler := NewInternalHandlers(WithInternalInventoryService(&stubInventoryService{}), WithInternalReservationSweeper(sweeper))
+
+	req := newServiceRequest(http.MethodPost, "/maintenance/cleanup-reservations", `{"limit":10}`)
+	rec := httptest.NewRecorder()
+	handler.cleanupReservations(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if sweeper.sweepCmd.Limit != 10 || sweeper.sweepCmd.ActorID == "" {
+		t.Fatalf("expected sweep with limit 10 and actor, got %+v", sweeper.sweepCmd)
+	}
+	var payload cleanupReservationsPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.CanceledOrderIDs) != 1 || payload.CanceledOrderIDs[0] != "ord_1" {
+		t.Fatalf("expected canceled order ids, got %+v", payload)
+	}
+
+	data, _ := json.Marshal(services.StockCleanupMessage{JobID: "scj_1", ReservationIDs: []string{"sr_2", "sr_3"}})
+	body, _ := json.Marshal(map[string]any{"message": map[string]any{"data": data, "messageId": "m1"}})
+	req = newServiceRequest(http.MethodPost, "/maintenance/cleanup-reservations", string(body))
+	rec = httptest.NewRecorder()
+	handler.cleanupReservations(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected push delivery to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := sweeper.releaseCmd.ReservationIDs; len(got) != 2 || got[0] != "sr_2" || got[1] != "sr_3" {
+		t.Fatalf("expected reservation ids from push message, got %+v", sweeper.releaseCmd)
+	}
+}
+
+func TestInternalHandlersCleanupReservationIDsRequireSweeper(t *testing.T) {
+	handler := NewInternalHandlers(WithInternalInventoryService(&stubInventoryService{}))
+	req := newServiceRequest(http.MethodPost, "/maintenance/cleanup-reservations", `{"reservation_ids":["sr_1"]}`)
+	rec := httptest.NewRecorder()
+	handler.cleanupReservations(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func newServiceRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx := auth.WithServiceIdentity(req.Context(), &auth.ServiceIdentity{
+		Subject: "1234567890",
+		Email:   "scheduler@example.iam.gserviceaccount.com",
+		Issuer:  "https://accounts.google.com",
+	})
+	return req.WithContext(ctx)
+}
+
+type captureAuditLog struct {
+	records []services.AuditLogRecord
+}
+
+func (c *captureAuditLog) Record(_ context.Context, record services.AuditLogRecord) {
+	c.records = append(c.records, record)
+}
+
+func (c *captureAuditLog) List(context.Context, services.AuditLogFilter) (domain.CursorPage[services.AuditLogEntry], error) {
+	return domain.CursorPage[services.AuditLogEntry]{}, errors.New("not implemented")
+}
+
+type stubInventoryService struct {
+	reserveFn func(context.Context, services.InventoryReserveCommand) (services.InventoryReservation, error)
+	commitFn  func(context.Context, services.InventoryCommitCommand) (services.InventoryReservation, error)
+	releaseFn func(context.Context, services.InventoryReleaseCommand) (services.InventoryReservation, error)
+	expiredFn func(context.Context, services.ReleaseExpiredReservationsCommand) (services.ReleaseExpiredReservationsResult, error)
+	adjustFn  func(context.Context, services.InventoryAdjustCommand) (services.InventoryStockEvent, error)
+	eventsFn  func(context.Context, services.InventoryStockEventFilter) (domain.CursorPage[services.InventoryStockEvent], error)
+
+	lowStockFn       func(context.Context, services.InventoryLowStockFilter) (domain.CursorPage[services.InventorySnapshot], error)
+	locationStocksFn func(context.Context, string) ([]services.InventoryStock, error)
+	transferFn       func(context.Context, services.InventoryTransferCommand) ([]services.InventoryStockEvent, error)
+	locationsFn      func(context.Context) ([]services.InventoryLocation, error)
+	upsertLocationFn func(context.Context, services.InventoryLocation) (services.InventoryLocation, error)
+}
+
+func (s *stubInventoryService) ReserveStocks(ctx context.Context, cmd services.InventoryReserveCommand) (services.InventoryReservation, error) {
+	if s.reserveFn != nil {
+		return s.reserveFn(ctx, cmd)
+	}
+	return services.InventoryReservation{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) CommitReservation(ctx context.Context, cmd services.InventoryCommitCommand) (services.InventoryReservation, error) {
+	if s.commitFn != nil {
+		return s.commitFn(ctx, cmd)
+	}
+	return services.InventoryReservation{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ReleaseReservation(ctx context.Context, cmd services.InventoryReleaseCommand) (services.InventoryReservation, error) {
+	if s.releaseFn != nil {
+		return s.releaseFn(ctx, cmd)
+	}
+	return services.InventoryReservation{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) RestockReservation(context.Context, services.InventoryRestockCommand) ([]services.InventoryStockEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListLowStock(ctx context.Context, filter services.InventoryLowStockFilter) (domain.CursorPage[services.InventorySnapshot], error) {
+	if s.lowStockFn != nil {
+		return s.lowStockFn(ctx, filter)
+	}
+	return domain.CursorPage[services.InventorySnapshot]{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ReleaseExpiredReservations(ctx context.Context, cmd services.ReleaseExpiredReservationsCommand) (services.ReleaseExpiredReservationsResult, error) {
+	if s.expiredFn != nil {
+		return s.expiredFn(ctx, cmd)
+	}
+	return services.ReleaseExpiredReservationsResult{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) AdjustStock(ctx context.Context, cmd services.InventoryAdjustCommand) (services.InventoryStockEvent, error) {
+	if s.adjustFn != nil {
+		return s.adjustFn(ctx, cmd)
+	}
+	return services.InventoryStockEvent{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListStockEvents(ctx context.Context, filter services.InventoryStockEventFilter) (domain.CursorPage[services.InventoryStockEvent], error) {
+	if s.eventsFn != nil {
+		return s.eventsFn(ctx, filter)
+	}
+	return domain.CursorPage[services.InventoryStockEvent]{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListLocationStocks(ctx context.Context, sku string) ([]services.InventoryStock, error) {
+	if s.locationStocksFn != nil {
+		return s.locationStocksFn(ctx, sku)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) TransferStock(ctx context.Context, cmd services.InventoryTransferCommand) ([]services.InventoryStockEvent, error) {
+	if s.transferFn != nil {
+		return s.transferFn(ctx, cmd)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListLocations(ctx context.Context) ([]services.InventoryLocation, error) {
+	if s.locationsFn != nil {
+		return s.locationsFn(ctx)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) UpsertLocation(ctx context.Context, location services.InventoryLocation) (services.InventoryLocation, error) {
+	if s.upsertLocationFn != nil {
+		return s.upsertLocationFn(ctx, location)
+	}
+	return services.InventoryLocation{}, errors.New("not implemented")
+}
+
+type stubInternalPromotionService struct {
+	services.PromotionService
+	cmd   services.ApplyPromotionUsageCommand
+	usage services.PromotionUsage
+	err   error
+}
+
+func (s *stubInternalPromotionService) ApplyPromotionUsage(_ context.Context, cmd services.ApplyPromotionUsageCommand) (services.PromotionUsage, error) {
+	s.cmd = cmd
+	return s.usage, s.err
+}
@@ -0,0 +1,441 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const (
+	defaultStockHistoryPageSize = 50
+	maxStockHistoryPageSize     = 200
+	defaultLowStockPageSize     = 50
+	maxLowStockPageSize         = 200
+)
+
+// InventoryHandlers exposes staff stock adjustments, stock locations and transfers, and the per-SKU
+// stock ledger under /admin.
+type InventoryHandlers struct {
+	inventory services.InventoryService
+}
+
+// InventoryOption customises InventoryHandlers.
+type InventoryOption func(*InventoryHandlers)
+
+// WithInventoryService injects the inventory service used for adjustments and ledger queries.
+func WithInventoryService(svc services.InventoryService) InventoryOption {
+	return func(h *InventoryHandlers) {
+		h.inventory = svc
+	}
+}
+
+// NewInventoryHandlers constructs inventory handlers with the provided options.
+func NewInventoryHandlers(opts ...InventoryOption) *InventoryHandlers {
+	handler := &InventoryHandlers{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// AdminRoutes registers staff inventory endpoints under /admin.
+func (h *InventoryHandlers) AdminRoutes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Get("/inventory/locations", h.listLocations)
+	r.Put("/inventory/locations/{locationId}", h.upsertLocation)
+	r.Get("/inventory/low-stock", h.listLowStock)
+	r.Post("/inventory/{sku}/adjustments", h.adjustStock)
+	r.Post("/inventory/{sku}/transfers", h.transferStock)
+	r.Get("/inventory/{sku}/locations", h.listLocationStocks)
+	r.Get("/inventory/{sku}/history", h.listStockHistory)
+}
+
+type stockAdjustmentRequest struct {
+	Type       string         `json:"type"`
+	Quantity   int            `json:"quantity"`
+	Reason     string         `json:"reason"`
+	ProductID  string         `json:"product_id"`
+	LocationID string         `json:"location_id"`
+	Metadata   map[string]any `json:"metadata"`
+}
+
+type stockTransferRequest struct {
+	FromLocationID string         `json:"from_location_id"`
+	ToLocationID   string         `json:"to_location_id"`
+	Quantity       int            `json:"quantity"`
+	Reason         string         `json:"reason"`
+	Metadata       map[string]any `json:"metadata"`
+}
+
+type stockLocationRequest struct {
+	Name              string   `json:"name"`
+	Prefecture        string   `json:"prefecture"`
+	NearbyPrefectures []string `json:"nearby_prefectures"`
+	Priority          int      `json:"priority"`
+	Active            *bool    `json:"active"`
+}
+
+type stockLocationPayload struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Prefecture        string   `json:"prefecture"`
+	NearbyPrefectures []string `json:"nearby_prefectures,omitempty"`
+	Priority          int      `json:"priority"`
+	Active            bool     `json:"active"`
+	CreatedAt         string   `json:"created_at,omitempty"`
+	UpdatedAt         string   `json:"updated_at,omitempty"`
+}
+
+type stockLocationListPayload struct {
+	Locations []stockLocationPayload `json:"locations"`
+}
+
+type stockLevelPayload struct {
+	SKU         string `json:"sku"`
+	ProductRef  string `json:"product_ref,omitempty"`
+	LocationID  string `json:"location_id,omitempty"`
+	OnHand      int    `json:"on_hand"`
+	Reserved    int    `json:"reserved"`
+	Available   int    `json:"available"`
+	SafetyStock int    `json:"safety_stock"`
+	SafetyDelta int    `json:"safety_delta"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+type locationStocksPayload struct {
+	SKU       string              `json:"sku"`
+	Locations []stockLevelPayload `json:"locations"`
+}
+
+type lowStockPayload struct {
+	LocationID    string              `json:"location_id,omitempty"`
+	Items         []stockLevelPayload `json:"items"`
+	NextPageToken string              `json:"next_page_token,omitempty"`
+}
+
+type stockTransferPayload struct {
+	Events []stockEventPayload `json:"events"`
+}
+
+type stockHistoryPayload struct {
+	SKU           string              `json:"sku"`
+	Events        []stockEventPayload `json:"events"`
+	NextPageToken string              `json:"next_page_token,omitempty"`
+}
+
+type stockEventPayload struct {
+	ID            string         `json:"id"`
+	Type          string         `json:"type"`
+	SKU           string         `json:"sku"`
+	LocationID    string         `json:"location_id,omitempty"`
+	ProductRef    string         `json:"product_ref,omitempty"`
+	ReservationID string         `json:"reservation_id,omitempty"`
+	OrderRef      string         `json:"order_ref,omitempty"`
+	DeltaOnHand   int            `json:"delta_on_hand"`
+	DeltaReserved int            `json:"delta_reserved"`
+	OnHand        int            `json:"on_hand"`
+	Reserved      int            `json:"reserved"`
+	SafetyStock   int            `json:"safety_stock"`
+	ActorID       string         `json:"actor_id,omitempty"`
+	Reason        string         `json:"reason,omitempty"`
+	OccurredAt    string         `json:"occurred_at,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+}
+
+func (h *InventoryHandlers) adjustStock(w http.ResponseWriter, r *http.Request) {
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req stockAdjustmentRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	event, err := h.inventory.AdjustStock(r.Context(), services.InventoryAdjustCommand{
+		SKU:        chi.URLParam(r, "sku"),
+		ProductID:  req.ProductID,
+		LocationID: req.LocationID,
+		Type:       services.InventoryAdjustmentType(strings.ToLower(strings.TrimSpace(req.Type))),
+		Quantity:   req.Quantity,
+		Reason:     req.Reason,
+		ActorID:    identity.UID,
+		Metadata:   req.Metadata,
+	})
+	if err != nil {
+		writeInventoryError(r.Context(), w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, buildStockEventPayload(event))
+}
+
+func (h *InventoryHandlers) listStockHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultStockHistoryPageSize, maxStockHistoryPageSize)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	sku := strings.TrimSpace(chi.URLParam(r, "sku"))
+	page, err := h.inventory.ListStockEvents(ctx, services.InventoryStockEventFilter{
+		SKU: sku,
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	})
+	if err != nil {
+		writeInventoryError(ctx, w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildStockHistoryPayload(sku, page))
+}
+
+func (h *InventoryHandlers) transferStock(w http.ResponseWriter, r *http.Request) {
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req stockTransferRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	events, err := h.inventory.TransferStock(r.Context(), services.InventoryTransferCommand{
+		SKU:            chi.URLParam(r, "sku"),
+		FromLocationID: req.FromLocationID,
+		ToLocationID:   req.ToLocationID,
+		Quantity:       req.Quantity,
+		Reason:         req.Reason,
+		ActorID:        identity.UID,
+		Metadata:       req.Metadata,
+	})
+	if err != nil {
+		writeInventoryError(r.Context(), w, err)
+		return
+	}
+	payload := stockTransferPayload{Events: make([]stockEventPayload, 0, len(events))}
+	for _, event := range events {
+		payload.Events = append(payload.Events, buildStockEventPayload(event))
+	}
+	writeJSON(w, http.StatusCreated, payload)
+}
+
+func (h *InventoryHandlers) listLocationStocks(w http.ResponseWriter, r *http.Request) {
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	sku := strings.TrimSpace(chi.URLParam(r, "sku"))
+	stocks, err := h.inventory.ListLocationStocks(r.Context(), sku)
+	if err != nil {
+		writeInventoryError(r.Context(), w, err)
+		return
+	}
+	payload := locationStocksPayload{SKU: sku, Locations: make([]stockLevelPayload, 0, len(stocks))}
+	for _, stock := range stocks {
+		payload.Locations = append(payload.Locations, buildStockLevelPayload(services.InventorySnapshot(stock)))
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *InventoryHandlers) listLowStock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultLowStockPageSize, maxLowStockPageSize)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+	threshold := 0
+	if raw := strings.TrimSpace(values.Get("threshold")); raw != "" {
+		threshold, err = strconv.Atoi(raw)
+		if err != nil || threshold < 0 {
+			httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "threshold must be a non-negative integer", http.StatusBadRequest))
+			return
+		}
+	}
+
+	locationID := strings.TrimSpace(values.Get("location"))
+	page, err := h.inventory.ListLowStock(ctx, services.InventoryLowStockFilter{
+		Threshold:  threshold,
+		LocationID: locationID,
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	})
+	if err != nil {
+		writeInventoryError(ctx, w, err)
+		return
+	}
+	payload := lowStockPayload{
+		LocationID:    locationID,
+		Items:         make([]stockLevelPayload, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, snapshot := range page.Items {
+		payload.Items = append(payload.Items, buildStockLevelPayload(snapshot))
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *InventoryHandlers) listLocations(w http.ResponseWriter, r *http.Request) {
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	locations, err := h.inventory.ListLocations(r.Context())
+	if err != nil {
+		writeInventoryError(r.Context(), w, err)
+		return
+	}
+	payload := stockLocationListPayload{Locations: make([]stockLocationPayload, 0, len(locations))}
+	for _, location := range locations {
+		payload.Locations = append(payload.Locations, buildStockLocationPayload(location))
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *InventoryHandlers) upsertLocation(w http.ResponseWriter, r *http.Request) {
+	if h.inventory == nil {
+		writeInventoryUnavailable(w, r)
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	var req stockLocationRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	location, err := h.inventory.UpsertLocation(r.Context(), services.InventoryLocation{
+		ID:                chi.URLParam(r, "locationId"),
+		Name:              req.Name,
+		Prefecture:        req.Prefecture,
+		NearbyPrefectures: copyStringSlice(req.NearbyPrefectures),
+		Priority:          req.Priority,
+		Active:            active,
+	})
+	if err != nil {
+		writeInventoryError(r.Context(), w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildStockLocationPayload(location))
+}
+
+func writeInventoryUnavailable(w http.ResponseWriter, r *http.Request) {
+	httpx.WriteError(r.Context(), w, httpx.NewError("inventory_unavailable", "inventory service is unavailable", http.StatusServiceUnavailable))
+}
+
+func buildStockHistoryPayload(sku string, page domain.CursorPage[services.InventoryStockEvent]) stockHistoryPayload {
+	payload := stockHistoryPayload{
+		SKU:           sku,
+		Events:        make([]stockEventPayload, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, event := range page.Items {
+		payload.Events = append(payload.Events, buildStockEventPayload(event))
+	}
+	return payload
+}
+
+func buildStockLevelPayload(stock services.InventorySnapshot) stockLevelPayload {
+	return stockLevelPayload{
+		SKU:         stock.SKU,
+		ProductRef:  stock.ProductRef,
+		LocationID:  stock.LocationID,
+		OnHand:      stock.OnHand,
+		Reserved:    stock.Reserved,
+		Available:   stock.Available,
+		SafetyStock: stock.SafetyStock,
+		SafetyDelta: stock.SafetyDelta,
+		UpdatedAt:   formatTimestamp(stock.UpdatedAt),
+	}
+}
+
+func buildStockLocationPayload(location services.InventoryLocation) stockLocationPayload {
+	return stockLocationPayload{
+		ID:                location.ID,
+		Name:              location.Name,
+		Prefecture:        location.Prefecture,
+		NearbyPrefectures: copyStringSlice(location.NearbyPrefectures),
+		Priority:          location.Priority,
+		Active:            location.Active,
+		CreatedAt:         formatTimestamp(location.CreatedAt),
+		UpdatedAt:         formatTimestamp(location.UpdatedAt),
+	}
+}
+
+func buildStockEventPayload(event services.InventoryStockEvent) stockEventPayload {
+	return stockEventPayload{
+		ID:            event.ID,
+		Type:          event.Type,
+		SKU:           event.SKU,
+		LocationID:    event.LocationID,
+		ProductRef:    event.ProductRef,
+		ReservationID: event.ReservationID,
+		OrderRef:      event.OrderRef,
+		DeltaOnHand:   event.DeltaOnHand,
+		DeltaReserved: event.DeltaReserved,
+		OnHand:        event.OnHand,
+		Reserved:      event.Reserved,
+		SafetyStock:   event.SafetyStock,
+		ActorID:       event.ActorID,
+		Reason:        event.Reason,
+		OccurredAt:    formatTimestamp(event.OccurredAt),
+		Metadata:      event.Metadata,
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/services"
+)
+
+func newInventoryAdminRouter(svc services.InventoryService) chi.Router {
+	router := chi.NewRouter()
+	NewInventoryHandlers(WithInventoryService(svc)).AdminRoutes(router)
+	return router
+}
+
+func TestInventoryHandlers_AdjustStock(t *testing.T) {
+	occurred := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+	var captured services.InventoryAdjustCommand
+	svc := &stubInventoryService{adjustFn: func(_ context.Context, cmd services.InventoryAdjustCommand) (services.InventoryStockEvent, error) {
+		captured = cmd
+		return services.InventoryStockEvent{ID: "ise_1", Type: "inventory.damage", SKU: cmd.SKU, DeltaOnHand: -2, OnHand: 8, ActorID: cmd.ActorID, Reason: cmd.Reason, OccurredAt: occurred}, nil
+	}}
+
+	body := `{"type":"Damage","quantity":2,"reason":"cracked in transit"}`
+	req := newIdentityRequest(http.MethodPost, "/inventory/SKU-1/adjustments", body, "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if captured.SKU != "SKU-1" || captured.Type != domain.InventoryAdjustmentDamage || captured.Quantity != 2 || captured.ActorID != "staff_1" {
+		t.Fatalf("unexpected command: %+v", captured)
+	}
+	var payload stockEventPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.ID != "ise_1" || payload.DeltaOnHand != -2 || payload.OnHand != 8 || payload.OccurredAt != "2025-05-02T09:00:00Z" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	svc.adjustFn = func(context.Context, services.InventoryAdjustCommand) (services.InventoryStockEvent, error) {
+		return services.InventoryStockEvent{}, fmt.Errorf("%w: stock SKU-9 not found", services.ErrInventoryStockNotFound)
+	}
+	rec = httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodPost, "/inventory/SKU-9/adjustments", body, "staff_1", "staff"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodPost, "/inventory/SKU-1/adjustments", body, "user_1"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-staff, got %d", rec.Code)
+	}
+}
+
+func TestInventoryHandlers_ListStockHistory(t *testing.T) {
+	var captured services.InventoryStockEventFilter
+	svc := &stubInventoryService{eventsFn: func(_ context.Context, filter services.InventoryStockEventFilter) (domain.CursorPage[services.InventoryStockEvent], error) {
+		captured = filter
+		return domain.CursorPage[services.InventoryStockEvent]{
+			Items: []services.InventoryStockEvent{
+				{ID: "ise_2", Type: "inventory.cycle_count", SKU: "SKU-1", DeltaOnHand: -1, OnHand: 9},
+				{ID: "ise_1", Type: "inventory.receipt", SKU: "SKU-1", DeltaOnHand: 10, OnHand: 10},
+			},
+			NextPageToken: "next",
+		}, nil
+	}}
+
+	req := newIdentityRequest(http.MethodGet, "/inventory/SKU-1/history?pageSize=2&pageToken=abc", "", "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if captured.SKU != "SKU-1" || captured.Pagination.PageSize != 2 || captured.Pagination.PageToken != "abc" {
+		t.Fatalf("unexpected filter: %+v", captured)
+	}
+	var payload stockHistoryPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Events) != 2 || payload.Events[0].ID != "ise_2" || payload.NextPageToken != "next" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	rec = httptest.NewRecorder()
+	newInventoryAdminRouter(nil).ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without inventory service, got %d", rec.Code)
+	}
+}
+
+func TestInventoryHandlers_TransferStock(t *testing.T) {
+	var captured services.InventoryTransferCommand
+	svc := &stubInventoryService{transferFn: func(_ context.Context, cmd services.InventoryTransferCommand) ([]services.InventoryStockEvent, error) {
+		captured = cmd
+		return []services.InventoryStockEvent{
+			{ID: "ise_out", Type: "inventory.transfer_out", SKU: cmd.SKU, LocationID: cmd.FromLocationID, DeltaOnHand: -cmd.Quantity},
+			{ID: "ise_in", Type: "inventory.transfer_in", SKU: cmd.SKU, LocationID: cmd.ToLocationID, DeltaOnHand: cmd.Quantity},
+		}, nil
+	}}
+
+	body := `{"from_location_id":"workshop","to_location_id":"partner","quantity":4,"reason":"rebalance"}`
+	rec := httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodPost, "/inventory/SKU-1/transfers", body, "staff_1", "staff"))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if captured.SKU != "SKU-1" || captured.FromLocationID != "workshop" || captured.ToLocationID != "partner" || captured.Quantity != 4 || captured.ActorID != "staff_1" {
+		t.Fatalf("unexpected command: %+v", captured)
+	}
+	var payload stockTransferPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Events) != 2 || payload.Events[0].LocationID != "workshop" || payload.Events[1].DeltaOnHand != 4 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	svc.transferFn = func(context.Context, services.InventoryTransferCommand) ([]services.InventoryStockEvent, error) {
+		return nil, fmt.Errorf("%w: location nowhere not found", services.ErrInventoryLocationNotFound)
+	}
+	rec = httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodPost, "/inventory/SKU-1/transfers", body, "staff_1", "staff"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestInventoryHandlers_ListLowStockByLocation(t *testing.T) {
+	var captured services.InventoryLowStockFilter
+	svc := &stubInventoryService{lowStockFn: func(_ context.Context, filter services.InventoryLowStockFilter) (domain.CursorPage[services.InventorySnapshot], error) {
+		captured = filter
+		return domain.CursorPage[services.InventorySnapshot]{
+			Items:         []services.InventorySnapshot{{SKU: "SKU-1", LocationID: filter.LocationID, OnHand: 2, Available: 1, SafetyDelta: -1}},
+			NextPageToken: "next",
+		}, nil
+	}}
+
+	rec := httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodGet, "/inventory/low-stock?location=partner&threshold=3&pageSize=10", "", "staff_1", "staff"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if captured.LocationID != "partner" || captured.Threshold != 3 || captured.Pagination.PageSize != 10 {
+		t.Fatalf("unexpected filter: %+v", captured)
+	}
+	var payload lowStockPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Items) != 1 || payload.Items[0].LocationID != "partner" || payload.NextPageToken != "next" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	rec = httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodGet, "/inventory/low-stock?threshold=-1", "", "staff_1", "staff"))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for negative threshold, got %d", rec.Code)
+	}
+}
+
+func TestInventoryHandlers_UpsertLocation(t *testing.T) {
+	var captured services.InventoryLocation
+	svc := &stubInventoryService{upsertLocationFn: func(_ context.Context, location services.InventoryLocation) (services.InventoryLocation, error) {
+		captured = location
+		return location, nil
+	}}
+
+	body := `{"name":"Fulfilment partner","prefecture":"Osaka","nearby_prefectures":["Kyoto","Hyogo"],"priority":2}`
+	rec := httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodPut, "/inventory/locations/partner", body, "staff_1", "staff"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if captured.ID != "partner" || captured.Prefecture != "Osaka" || len(captured.NearbyPrefectures) != 2 || captured.Priority != 2 || !captured.Active {
+		t.Fatalf("unexpected location: %+v", captured)
+	}
+
+	rec = httptest.NewRecorder()
+	newInventoryAdminRouter(svc).ServeHTTP(rec, newIdentityRequest(http.MethodPut, "/inventory/locations/partner", body, "user_1"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-staff, got %d", rec.Code)
+	}
+}
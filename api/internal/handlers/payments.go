@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const maxReconcileRequestLimit = 5000
+
+// PaymentHandlers exposes staff-facing payment operations under /admin.
+type PaymentHandlers struct {
+	reconciliation services.PaymentReconciliationService
+	router         PaymentRouter
+}
+
+// PaymentRouter previews which PSP a payment context would be routed to. *payments.Manager satisfies it.
+type PaymentRouter interface {
+	Route(paymentCtx payments.PaymentContext) (payments.RouteDecision, error)
+}
+
+// PaymentOption customises PaymentHandlers.
+type PaymentOption func(*PaymentHandlers)
+
+// WithPaymentReconciliationService injects the service comparing stored payments with the PSP.
+func WithPaymentReconciliationService(svc services.PaymentReconciliationService) PaymentOption {
+	return func(h *PaymentHandlers) {
+		h.reconciliation = svc
+	}
+}
+
+// WithPaymentRouter injects the PSP router used by the routing dry-run endpoint.
+func WithPaymentRouter(router PaymentRouter) PaymentOption {
+	return func(h *PaymentHandlers) {
+		h.router = router
+	}
+}
+
+// NewPaymentHandlers constructs payment handlers with the provided options.
+func NewPaymentHandlers(opts ...PaymentOption) *PaymentHandlers {
+	handler := &PaymentHandlers{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// AdminRoutes registers staff payment endpoints under /admin.
+func (h *PaymentHandlers) AdminRoutes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Post("/payments:reconcile", h.reconcilePayments)
+	r.Post("/payments:route", h.previewPaymentRoute)
+}
+
+type reconcilePaymentsRequest struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Provider string `json:"provider"`
+	AutoHeal bool   `json:"auto_heal"`
+	Limit    int    `json:"limit"`
+}
+
+type paymentRouteRequest struct {
+	PreferredProvider string `json:"preferred_provider"`
+	Currency          string `json:"currency"`
+	Amount            int64  `json:"amount"`
+	BillingCountry    string `json:"billing_country"`
+	PaymentMethod     string `json:"payment_method"`
+	UserSegment       string `json:"user_segment"`
+}
+
+type paymentRoutePayload struct {
+	Provider  string   `json:"provider"`
+	Fallbacks []string `json:"fallbacks"`
+	Source    string   `json:"source"`
+	Rule      string   `json:"rule,omitempty"`
+}
+
+type reconciliationReportPayload struct {
+	From          string                      `json:"from"`
+	To            string                      `json:"to"`
+	Checked       int                         `json:"checked"`
+	Matched       int                         `json:"matched"`
+	Healed        int                         `json:"healed"`
+	Discrepancies []paymentDiscrepancyPayload `json:"discrepancies"`
+	FailedIDs     []string                    `json:"failed_ids"`
+	HasMore       bool                        `json:"has_more"`
+	GeneratedAt   string                      `json:"generated_at,omitempty"`
+}
+
+type paymentDiscrepancyPayload struct {
+	PaymentID  string                   `json:"payment_id"`
+	OrderID    string                   `json:"order_id"`
+	Provider   string                   `json:"provider"`
+	IntentID   string                   `json:"intent_id"`
+	Mismatches []paymentMismatchPayload `json:"mismatches"`
+	Healed     bool                     `json:"healed"`
+}
+
+type paymentMismatchPayload struct {
+	Field    string `json:"field"`
+	Recorded any    `json:"recorded"`
+	Provider any    `json:"provider"`
+}
+
+func (h *PaymentHandlers) reconcilePayments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reconciliation == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("payment_reconciliation_unavailable", "payment reconciliation service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req reconcilePaymentsRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	from, err := time.Parse(time.RFC3339, strings.TrimSpace(req.From))
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "from must be an RFC3339 timestamp", http.StatusBadRequest))
+		return
+	}
+	to, err := time.Parse(time.RFC3339, strings.TrimSpace(req.To))
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "to must be an RFC3339 timestamp", http.StatusBadRequest))
+		return
+	}
+	if req.Limit < 0 || req.Limit > maxReconcileRequestLimit {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "limit must be between 0 and 5000", http.StatusBadRequest))
+		return
+	}
+
+	report, err := h.reconciliation.Reconcile(ctx, services.ReconcilePaymentsCommand{
+		From:     from,
+		To:       to,
+		Provider: req.Provider,
+		AutoHeal: req.AutoHeal,
+		ActorID:  identity.UID,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		writeServiceError(ctx, w, err, errorScope{resource: "payment", service: "payment_reconciliation"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buildReconciliationReportPayload(report))
+}
+
+// previewPaymentRoute reports the provider selection for a hypothetical payment without contacting a PSP.
+func (h *PaymentHandlers) previewPaymentRoute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.router == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("payment_routing_unavailable", "payment routing is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	var req paymentRouteRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	if req.Amount < 0 {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "amount must not be negative", http.StatusBadRequest))
+		return
+	}
+
+	decision, err := h.router.Route(payments.PaymentContext{
+		PreferredProvider: req.PreferredProvider,
+		Currency:          req.Currency,
+		Amount:            req.Amount,
+		BillingCountry:    req.BillingCountry,
+		PaymentMethod:     payments.PaymentMethod(req.PaymentMethod),
+		UserSegment:       req.UserSegment,
+	})
+	if err != nil {
+		if errors.Is(err, payments.ErrUnsupportedProvider) {
+			httpx.WriteError(ctx, w, httpx.NewError("no_payment_route", "no provider matches the payment context", http.StatusBadRequest))
+			return
+		}
+		httpx.WriteError(ctx, w, httpx.NewError("payment_routing_unavailable", "payment routing is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+
+	fallbacks := decision.Fallbacks
+	if fallbacks == nil {
+		fallbacks = []string{}
+	}
+	writeJSON(w, http.StatusOK, paymentRoutePayload{
+		Provider:  decision.Provider,
+		Fallbacks: fallbacks,
+		Source:    decision.Source,
+		Rule:      decision.Rule,
+	})
+}
+
+func buildReconciliationReportPayload(report services.PaymentReconciliationReport) reconciliationReportPayload {
+	payload := reconciliationReportPayload{
+		From:          formatTimestamp(report.From),
+		To:            formatTimestamp(report.To),
+		Checked:       report.CheckedCount,
+		Matched:       report.MatchedCount,
+		Healed:        report.HealedCount,
+		Discrepancies: make([]paymentDiscrepancyPayload, 0, len(report.Discrepancies)),
+		FailedIDs:     copyStringSlice(report.FailedIDs),
+		HasMore:       report.HasMore,
+		GeneratedAt:   formatTimestamp(report.GeneratedAt),
+	}
+	for _, discrepancy := range report.Discrepancies {
+		mismatches := make([]paymentMismatchPayload, 0, len(discrepancy.Mismatches))
+		for _, mismatch := range discrepancy.Mismatches {
+			mismatches = append(mismatches, paymentMismatchPayload{
+				Field:    mismatch.Field,
+				Recorded: mismatch.Recorded,
+				Provider: mismatch.Provider,
+			})
+		}
+		payload.Discrepancies = append(payload.Discrepancies, paymentDiscrepancyPayload{
+			PaymentID:  discrepancy.PaymentID,
+			OrderID:    discrepancy.OrderID,
+			Provider:   discrepancy.Provider,
+			IntentID:   discrepancy.IntentID,
+			Mismatches: mismatches,
+			Healed:     discrepancy.Healed,
+		})
+	}
+	return payload
+}
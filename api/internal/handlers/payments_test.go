@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/services"
+)
+
+type stubReconciliationService struct {
+	cmd    services.ReconcilePaymentsCommand
+	report services.PaymentReconciliationReport
+	err    error
+}
+
+func (s *stubReconciliationService) Reconcile(_ context.Context, cmd services.ReconcilePaymentsCommand) (services.PaymentReconciliationReport, error) {
+	s.cmd = cmd
+	return s.report, s.err
+}
+
+func newPaymentAdminRouter(svc services.PaymentReconciliationService) chi.Router {
+	router := chi.NewRouter()
+	NewPaymentHandlers(WithPaymentReconciliationService(svc)).AdminRoutes(router)
+	return router
+}
+
+func TestPaymentHandlers_ReconcileReturnsReport(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc := &stubReconciliationService{report: services.PaymentReconciliationReport{
+		From:         from,
+		To:           from.Add(24 * time.Hour),
+		CheckedCount: 2,
+		MatchedCount: 1,
+		HealedCount:  1,
+		Discrepancies: []services.PaymentDiscrepancy{{
+			PaymentID:  "pay_1",
+			OrderID:    "ord_1",
+			Provider:   "stripe",
+			IntentID:   "pi_1",
+			Mismatches: []services.PaymentFieldMismatch{{Field: "status", Recorded: "pending", Provider: "succeeded"}},
+			Healed:     true,
+		}},
+		GeneratedAt: from.Add(48 * time.Hour),
+	}}
+
+	body := `{"from":"2024-06-01T00:00:00Z","to":"2024-06-02T00:00:00Z","provider":"stripe","auto_heal":true,"limit":50}`
+	req := newIdentityRequest(http.MethodPost, "/payments:reconcile", body, "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	newPaymentAdminRouter(svc).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !svc.cmd.AutoHeal || svc.cmd.ActorID != "staff_1" || svc.cmd.Limit != 50 || !svc.cmd.From.Equal(from) {
+		t.Fatalf("unexpected command: %+v", svc.cmd)
+	}
+
+	var payload reconciliationReportPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Checked != 2 || payload.Healed != 1 || len(payload.Discrepancies) != 1 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if mismatch := payload.Discrepancies[0].Mismatches[0]; mismatch.Field != "status" || mismatch.Provider != "succeeded" {
+		t.Fatalf("unexpected mismatch: %+v", mismatch)
+	}
+}
+
+func TestPaymentHandlers_ReconcileErrors(t *testing.T) {
+	valid := `{"from":"2024-06-01T00:00:00Z","to":"2024-06-02T00:00:00Z"}`
+	cases := []struct {
+		name   string
+		svc    services.PaymentReconciliationService
+		body   string
+		roles  []string
+		status int
+	}{
+		{name: "unavailable", svc: nil, body: valid, roles: []string{"staff"}, status: http.StatusServiceUnavailable},
+		{name: "forbidden", svc: &stubReconciliationService{}, body: valid, status: http.StatusForbidden},
+		{name: "bad timestamp", svc: &stubReconciliationService{}, body: `{"from":"yesterday","to":"2024-06-02T00:00:00Z"}`, roles: []string{"staff"}, status: http.StatusBadRequest},
+		{name: "service validation", svc: &stubReconciliationService{err: fmt.Errorf("%w: from must be before to", services.ErrPaymentReconciliationInvalidInput)}, body: valid, roles: []string{"admin"}, status: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newIdentityRequest(http.MethodPost, "/payments:reconcile", tc.body, "user_1", tc.roles...)
+			rec := httptest.NewRecorder()
+			newPaymentAdminRouter(tc.svc).ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+type stubPaymentRouter struct {
+	ctx      payments.PaymentContext
+	decision payments.RouteDecision
+	err      error
+}
+
+func (s *stubPaymentRouter) Route(paymentCtx payments.PaymentContext) (payments.RouteDecision, error) {
+	s.ctx = paymentCtx
+	return s.decision, s.err
+}
+
+func TestPaymentHandlers_RoutePreview(t *testing.T) {
+	router := &stubPaymentRouter{decision: payments.RouteDecision{Provider: "paypal", Fallbacks: []string{"stripe"}, Source: payments.RouteSourceRule, Rule: "high-value"}}
+	mux := chi.NewRouter()
+	NewPaymentHandlers(WithPaymentRouter(router)).AdminRoutes(mux)
+
+	body := `{"currency":"JPY","amount":150000,"billing_country":"JP","payment_method":"card","user_segment":"vip"}`
+	req := newIdentityRequest(http.MethodPost, "/payments:route", body, "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if router.ctx.Amount != 150000 || router.ctx.BillingCountry != "JP" || router.ctx.PaymentMethod != payments.PaymentMethodCard || router.ctx.UserSegment != "vip" {
+		t.Fatalf("unexpected routing context %+v", router.ctx)
+	}
+	var payload paymentRoutePayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Provider != "paypal" || len(payload.Fallbacks) != 1 || payload.Rule != "high-value" || payload.Source != "rule" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	router.err = payments.ErrUnsupportedProvider
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, newIdentityRequest(http.MethodPost, "/payments:route", `{"currency":"EUR"}`, "staff_1", "staff"))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a route, got %d", rec.Code)
+	}
+}
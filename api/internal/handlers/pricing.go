@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const maxSimulatedCartItems = 200
+
+// PricingSimulator prices a cart without persisting anything. *services.CartPricingEngine satisfies it.
+type PricingSimulator interface {
+	Calculate(ctx context.Context, cmd services.PriceCartCommand) (services.PriceCartResult, error)
+}
+
+// PricingHandlers exposes staff pricing diagnostics under /admin.
+type PricingHandlers struct {
+	simulator PricingSimulator
+	orders    services.OrderService
+}
+
+// PricingOption customises PricingHandlers.
+type PricingOption func(*PricingHandlers)
+
+// WithPricingSimulator injects the pricing engine used by the simulator endpoint.
+func WithPricingSimulator(simulator PricingSimulator) PricingOption {
+	return func(h *PricingHandlers) {
+		h.simulator = simulator
+	}
+}
+
+// WithPricingOrderService injects the order service used to replay existing orders.
+func WithPricingOrderService(svc services.OrderService) PricingOption {
+	return func(h *PricingHandlers) {
+		h.orders = svc
+	}
+}
+
+// NewPricingHandlers constructs pricing handlers with the provided options.
+func NewPricingHandlers(opts ...PricingOption) *PricingHandlers {
+	handler := &PricingHandlers{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// AdminRoutes registers staff pricing endpoints under /admin.
+func (h *PricingHandlers) AdminRoutes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Post("/pricing:simulate", h.simulatePricing)
+}
+
+type simulatePricingRequest struct {
+	OrderID             string                 `json:"order_id"`
+	Cart                *simulatedCartRequest  `json:"cart"`
+	ShippingAddress     *simulatedAddressInput `json:"shipping_address"`
+	PromotionCode       *string                `json:"promotion_code"`
+	UserRoles           []string               `json:"user_roles"`
+	ServiceLevel        string                 `json:"service_level"`
+	BypassShippingCache bool                   `json:"bypass_shipping_cache"`
+}
+
+type simulatedCartRequest struct {
+	UserID   string                     `json:"user_id"`
+	Currency string                     `json:"currency"`
+	Items    []simulatedCartItemRequest `json:"items"`
+}
+
+type simulatedCartItemRequest struct {
+	ID               string         `json:"id"`
+	ProductID        string         `json:"product_id"`
+	SKU              string         `json:"sku"`
+	Quantity         int            `json:"quantity"`
+	UnitPrice        int64          `json:"unit_price"`
+	Currency         string         `json:"currency"`
+	WeightGrams      int            `json:"weight_grams"`
+	TaxCode          string         `json:"tax_code"`
+	RequiresShipping bool           `json:"requires_shipping"`
+	Customization    map[string]any `json:"customization"`
+}
+
+type simulatedAddressInput struct {
+	Recipient  string `json:"recipient"`
+	Line1      string `json:"line1"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+type pricingSimulationPayload struct {
+	OrderID       string                        `json:"order_id,omitempty"`
+	Breakdown     pricingBreakdownPayload       `json:"breakdown"`
+	ExchangeRates []exchangeRatePayload         `json:"exchange_rates,omitempty"`
+	Trace         []pricingTraceStepPayload     `json:"trace"`
+	Recorded      *pricingRecordedTotalsPayload `json:"recorded,omitempty"`
+}
+
+type pricingRecordedTotalsPayload struct {
+	Subtotal int64 `json:"subtotal"`
+	Discount int64 `json:"discount"`
+	Shipping int64 `json:"shipping"`
+	Tax      int64 `json:"tax"`
+	Total    int64 `json:"total"`
+}
+
+type pricingBreakdownPayload struct {
+	Currency  string                   `json:"currency"`
+	Subtotal  int64                    `json:"subtotal"`
+	Discount  int64                    `json:"discount"`
+	Tax       int64                    `json:"tax"`
+	Shipping  int64                    `json:"shipping"`
+	Total     int64                    `json:"total"`
+	Items     []pricingItemPayload     `json:"items"`
+	Discounts []pricingDiscountPayload `json:"discounts,omitempty"`
+	Taxes     []pricingTaxPayload      `json:"taxes,omitempty"`
+	Shipments []pricingShippingPayload `json:"shipping_details,omitempty"`
+	Metadata  map[string]any           `json:"metadata,omitempty"`
+}
+
+type pricingItemPayload struct {
+	ItemID     string                        `json:"item_id"`
+	Subtotal   int64                         `json:"subtotal"`
+	Discount   int64                         `json:"discount"`
+	Tax        int64                         `json:"tax"`
+	Shipping   int64                         `json:"shipping"`
+	Total      int64                         `json:"total"`
+	Surcharges []pricingItemSurchargePayload `json:"surcharges,omitempty"`
+	Metadata   map[string]any                `json:"metadata,omitempty"`
+}
+
+type pricingItemSurchargePayload struct {
+	Type        string `json:"type"`
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
+	Quantity    int    `json:"quantity"`
+	Amount      int64  `json:"amount"`
+}
+
+type pricingDiscountPayload struct {
+	Type        string `json:"type"`
+	Code        string `json:"code,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Description string `json:"description,omitempty"`
+	Amount      int64  `json:"amount"`
+}
+
+type pricingTaxPayload struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount int64   `json:"amount"`
+}
+
+type pricingShippingPayload struct {
+	ServiceLevel string `json:"service_level,omitempty"`
+	Carrier      string `json:"carrier,omitempty"`
+	Amount       int64  `json:"amount"`
+	EstimateDays *int   `json:"estimate_days,omitempty"`
+}
+
+type exchangeRatePayload struct {
+	TableID string  `json:"table_id"`
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Rate    float64 `json:"rate"`
+}
+
+type pricingTraceStepPayload struct {
+	Step   string         `json:"step"`
+	ItemID string         `json:"item_id,omitempty"`
+	Detail map[string]any `json:"detail,omitempty"`
+}
+
+// simulatePricing runs the pricing engine in explain mode for an arbitrary cart or a replay of an
+// existing order. Nothing is persisted: no quote is issued and the shipping cache is not written.
+func (h *PricingHandlers) simulatePricing(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.simulator == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("pricing_unavailable", "pricing simulator is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	var req simulatePricingRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	orderID := strings.TrimSpace(req.OrderID)
+	if (orderID == "") == (req.Cart == nil) {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "exactly one of order_id or cart is required", http.StatusBadRequest))
+		return
+	}
+
+	cmd := services.PriceCartCommand{
+		PromotionCode:       req.PromotionCode,
+		UserRoles:           req.UserRoles,
+		ServiceLevel:        strings.TrimSpace(req.ServiceLevel),
+		BypassShippingCache: req.BypassShippingCache,
+		Explain:             true,
+	}
+	if req.ShippingAddress != nil {
+		cmd.ShippingAddress = req.ShippingAddress.toAddress()
+	}
+
+	var recorded *pricingRecordedTotalsPayload
+	if orderID != "" {
+		if h.orders == nil {
+			httpx.WriteError(ctx, w, httpx.NewError("order_service_unavailable", "order service is unavailable", http.StatusServiceUnavailable))
+			return
+		}
+		order, err := h.orders.GetOrder(ctx, orderID, services.OrderReadOptions{})
+		if err != nil {
+			writeServiceError(ctx, w, err, errorScope{resource: "order", service: "orders"})
+			return
+		}
+		cmd.Cart = cartFromOrder(order)
+		recorded = &pricingRecordedTotalsPayload{
+			Subtotal: order.Totals.Subtotal,
+			Discount: order.Totals.Discount,
+			Shipping: order.Totals.Shipping,
+			Tax:      order.Totals.Tax,
+			Total:    order.Totals.Total,
+		}
+	} else {
+		cart, err := req.Cart.toCart()
+		if err != nil {
+			httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+			return
+		}
+		cmd.Cart = cart
+	}
+
+	result, err := h.simulator.Calculate(ctx, cmd)
+	if err != nil {
+		writeServiceError(ctx, w, err, errorScope{resource: "cart", service: "pricing"})
+		return
+	}
+
+	payload := pricingSimulationPayload{
+		OrderID:   orderID,
+		Breakdown: buildPricingBreakdownPayload(result.Breakdown),
+		Trace:     make([]pricingTraceStepPayload, 0, len(result.Trace)),
+		Recorded:  recorded,
+	}
+	for _, conv := range result.ExchangeRates {
+		payload.ExchangeRates = append(payload.ExchangeRates, exchangeRatePayload{TableID: conv.TableID, From: conv.From, To: conv.To, Rate: conv.Rate})
+	}
+	for _, step := range result.Trace {
+		payload.Trace = append(payload.Trace, pricingTraceStepPayload{Step: step.Step, ItemID: step.ItemID, Detail: step.Detail})
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (c *simulatedCartRequest) toCart() (services.Cart, error) {
+	if len(c.Items) == 0 {
+		return services.Cart{}, fmt.Errorf("cart.items must not be empty")
+	}
+	if len(c.Items) > maxSimulatedCartItems {
+		return services.Cart{}, fmt.Errorf("cart.items must not exceed %d entries", maxSimulatedCartItems)
+	}
+	cart := services.Cart{
+		UserID:   strings.TrimSpace(c.UserID),
+		Currency: strings.ToUpper(strings.TrimSpace(c.Currency)),
+		Items:    make([]services.CartItem, 0, len(c.Items)),
+	}
+	for idx, item := range c.Items {
+		id := strings.TrimSpace(item.ID)
+		if id == "" {
+			id = fmt.Sprintf("line_%d", idx+1)
+		}
+		cart.Items = append(cart.Items, services.CartItem{
+			ID:               id,
+			ProductID:        strings.TrimSpace(item.ProductID),
+			SKU:              strings.TrimSpace(item.SKU),
+			Quantity:         item.Quantity,
+			UnitPrice:        item.UnitPrice,
+			Currency:         strings.ToUpper(strings.TrimSpace(item.Currency)),
+			WeightGrams:      item.WeightGrams,
+			TaxCode:          strings.TrimSpace(item.TaxCode),
+			RequiresShipping: item.RequiresShipping,
+			Customization:    item.Customization,
+		})
+	}
+	return cart, nil
+}
+
+// cartFromOrder rebuilds a cart from an order's lines. Orders do not keep weights or tax codes, so
+// lines are treated as shippable with the tax code recorded in line metadata when present.
+func cartFromOrder(order services.Order) services.Cart {
+	cart := services.Cart{
+		UserID:          order.UserID,
+		Currency:        order.Currency,
+		ShippingAddress: order.ShippingAddress,
+		BillingAddress:  order.BillingAddress,
+		Items:           make([]services.CartItem, 0, len(order.Items)),
+	}
+	if order.CartRef != nil {
+		cart.ID = *order.CartRef
+	}
+	if order.Promotion != nil && strings.TrimSpace(order.Promotion.Code) != "" {
+		cart.Promotion = &services.CartPromotion{Code: order.Promotion.Code}
+	}
+	for idx, line := range order.Items {
+		taxCode, _ := line.Metadata["taxCode"].(string)
+		cart.Items = append(cart.Items, services.CartItem{
+			ID:               fmt.Sprintf("line_%d", idx+1),
+			ProductID:        line.ProductRef,
+			SKU:              line.SKU,
+			Quantity:         line.Quantity,
+			UnitPrice:        line.UnitPrice,
+			Currency:         order.Currency,
+			TaxCode:          taxCode,
+			RequiresShipping: order.ShippingAddress != nil,
+			Customization:    line.Options,
+			Metadata:         line.Metadata,
+		})
+	}
+	return cart
+}
+
+func (a *simulatedAddressInput) toAddress() *services.Address {
+	addr := &services.Address{
+		Recipient:  strings.TrimSpace(a.Recipient),
+		Line1:      strings.TrimSpace(a.Line1),
+		City:       strings.TrimSpace(a.City),
+		PostalCode: strings.TrimSpace(a.PostalCode),
+		Country:    strings.ToUpper(strings.TrimSpace(a.Country)),
+	}
+	if state := strings.TrimSpace(a.State); state != "" {
+		addr.State = &state
+	}
+	return addr
+}
+
+func buildPricingBreakdownPayload(breakdown services.PricingBreakdown) pricingBreakdownPayload {
+	payload := pricingBreakdownPayload{
+		Currency: breakdown.Currency,
+		Subtotal: breakdown.Subtotal,
+		Discount: breakdown.Discount,
+		Tax:      breakdown.Tax,
+		Shipping: breakdown.Shipping,
+		Total:    breakdown.Total,
+		Items:    make([]pricingItemPayload, 0, len(breakdown.Items)),
+		Metadata: breakdown.Metadata,
+	}
+	for _, item := range breakdown.Items {
+		line := pricingItemPayload{
+			ItemID:   item.ItemID,
+			Subtotal: item.Subtotal,
+			Discount: item.Discount,
+			Tax:      item.Tax,
+			Shipping: item.Shipping,
+			Total:    item.Total,
+			Metadata: item.Metadata,
+		}
+		for _, surcharge := range item.Surcharges {
+			line.Surcharges = append(line.Surcharges, pricingItemSurchargePayload{
+				Type:        surcharge.Type,
+				Code:        surcharge.Code,
+				Description: surcharge.Description,
+				Quantity:    surcharge.Quantity,
+				Amount:      surcharge.Amount,
+			})
+		}
+		payload.Items = append(payload.Items, line)
+	}
+	for _, discount := range breakdown.Discounts {
+		payload.Discounts = append(payload.Discounts, pricingDiscountPayload{
+			Type:        discount.Type,
+			Code:        discount.Code,
+			Source:      discount.Source,
+			Description: discount.Description,
+			Amount:      discount.Amount,
+		})
+	}
+	for _, tax := range breakdown.Taxes {
+		payload.Taxes = append(payload.Taxes, pricingTaxPayload{Name: tax.Name, Rate: tax.Rate, Amount: tax.Amount})
+	}
+	for _, shipping := range breakdown.ShippingDetails {
+		payload.Shipments = append(payload.Shipments, pricingShippingPayload{
+			ServiceLevel: shipping.ServiceLevel,
+			Carrier:      shipping.Carrier,
+			Amount:       shipping.Amount,
+			EstimateDays: shipping.EstimateDays,
+		})
+	}
+	return payload
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hanko-field/api/internal/services"
+)
+
+type stubPricingSimulator struct {
+	cmd    services.PriceCartCommand
+	result services.PriceCartResult
+	err    error
+}
+
+func (s *stubPricingSimulator) Calculate(_ context.Context, cmd services.PriceCartCommand) (services.PriceCartResult, error) {
+	s.cmd = cmd
+	return s.result, s.err
+}
+
+type stubPricingOrderService struct {
+	services.OrderService
+	order services.Order
+	err   error
+}
+
+func (s *stubPricingOrderService) GetOrder(_ context.Context, _ string, _ services.OrderReadOptions) (services.Order, error) {
+	return s.order, s.err
+}
+
+func newPricingAdminRouter(opts ...PricingOption) chi.Router {
+	router := chi.NewRouter()
+	NewPricingHandlers(opts...).AdminRoutes(router)
+	return router
+}
+
+func TestPricingHandlers_SimulateCart(t *testing.T) {
+	sim := &stubPricingSimulator{result: services.PriceCartResult{
+		Breakdown: services.PricingBreakdown{Currency: "JPY", Subtotal: 3000, Total: 3300, Tax: 300,
+			Items: []services.ItemPricingBreakdown{{ItemID: "line_1", Subtotal: 3000, Total: 3300}}},
+		Trace: []services.PricingTraceStep{
+			{Step: services.PricingStepInput, ItemID: "line_1", Detail: map[string]any{"unitPrice": int64(3000)}},
+			{Step: services.PricingStepTotal, Detail: map[string]any{"total": int64(3300)}},
+		},
+	}}
+	body := `{"cart":{"currency":"jpy","items":[{"sku":"seal","quantity":1,"unit_price":3000}]},"promotion_code":"SPRING"}`
+	req := newIdentityRequest(http.MethodPost, "/pricing:simulate", body, "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	newPricingAdminRouter(WithPricingSimulator(sim)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !sim.cmd.Explain || sim.cmd.Cart.Currency != "JPY" || sim.cmd.Cart.Items[0].ID != "line_1" || sim.cmd.PromotionCode == nil {
+		t.Fatalf("unexpected command: %+v", sim.cmd)
+	}
+	var payload pricingSimulationPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Breakdown.Total != 3300 || len(payload.Trace) != 2 || payload.Trace[0].ItemID != "line_1" || payload.Recorded != nil {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestPricingHandlers_SimulateOrderReplay(t *testing.T) {
+	cartID := "cart_9"
+	orders := &stubPricingOrderService{order: services.Order{
+		ID:       "ord_1",
+		UserID:   "user_1",
+		Currency: "JPY",
+		CartRef:  &cartID,
+		Items:    []services.OrderLineItem{{SKU: "seal", Quantity: 2, UnitPrice: 1500}},
+		Totals:   services.OrderTotals{Subtotal: 3000, Total: 3000},
+	}}
+	sim := &stubPricingSimulator{result: services.PriceCartResult{Breakdown: services.PricingBreakdown{Currency: "JPY", Subtotal: 3200, Total: 3200}}}
+	req := newIdentityRequest(http.MethodPost, "/pricing:simulate", `{"order_id":"ord_1"}`, "staff_1", "staff")
+	rec := httptest.NewRecorder()
+	newPricingAdminRouter(WithPricingSimulator(sim), WithPricingOrderService(orders)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if sim.cmd.Cart.ID != "cart_9" || len(sim.cmd.Cart.Items) != 1 || sim.cmd.Cart.Items[0].UnitPrice != 1500 {
+		t.Fatalf("expected cart rebuilt from order, got %+v", sim.cmd.Cart)
+	}
+	var payload pricingSimulationPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.OrderID != "ord_1" || payload.Recorded == nil || payload.Recorded.Total != 3000 || payload.Breakdown.Total != 3200 {
+		t.Fatalf("expected recorded and repriced totals, got %+v", payload)
+	}
+}
+
+func TestPricingHandlers_SimulateErrors(t *testing.T) {
+	cart := `{"cart":{"currency":"JPY","items":[{"sku":"seal","quantity":1,"unit_price":3000}]}}`
+	cases := []struct {
+		name   string
+		opts   []PricingOption
+		body   string
+		roles  []string
+		status int
+	}{
+		{name: "missing simulator", body: cart, roles: []string{"staff"}, status: http.StatusServiceUnavailable},
+		{name: "not staff", opts: []PricingOption{WithPricingSimulator(&stubPricingSimulator{})}, body: cart, status: http.StatusForbidden},
+		{name: "neither source", opts: []PricingOption{WithPricingSimulator(&stubPricingSimulator{})}, body: `{}`, roles: []string{"staff"}, status: http.StatusBadRequest},
+		{name: "both sources", opts: []PricingOption{WithPricingSimulator(&stubPricingSimulator{})}, body: `{"order_id":"ord_1","cart":{"items":[{"sku":"a"}]}}`, roles: []string{"staff"}, status: http.StatusBadRequest},
+		{name: "empty cart", opts: []PricingOption{WithPricingSimulator(&stubPricingSimulator{})}, body: `{"cart":{"items":[]}}`, roles: []string{"staff"}, status: http.StatusBadRequest},
+		{name: "order replay without order service", opts: []PricingOption{WithPricingSimulator(&stubPricingSimulator{})}, body: `{"order_id":"ord_1"}`, roles: []string{"staff"}, status: http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newIdentityRequest(http.MethodPost, "/pricing:simulate", tc.body, "user_1", tc.roles...)
+			rec := httptest.NewRecorder()
+			newPricingAdminRouter(tc.opts...).ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"sort"
@@ -19,7 +20,6 @@ import (
 	domain "github.com/hanko-field/api/internal/domain"
 	"github.com/hanko-field/api/internal/platform/httpx"
 	"github.com/hanko-field/api/internal/platform/textutil"
-	"github.com/hanko-field/api/internal/repositories"
 	"github.com/hanko-field/api/internal/services"
 )
 
@@ -32,6 +32,9 @@ const (
 	maxMaterialPageSize       = 100
 	defaultProductPageSize    = 24
 	maxProductPageSize        = 100
+	defaultProductReviewSize  = 10
+	maxProductReviewSize      = 50
+	productReviewCacheControl = "public, max-age=120"
 	fontCacheControl          = "public, max-age=300"
 	materialCacheControl      = "public, max-age=900"
 	productCacheControl       = "public, max-age=300"
@@ -71,6 +74,7 @@ func (fn AssetURLResolverFunc) ResolveURL(ctx context.Context, path string) (str
 type PublicHandlers struct {
 	catalog          services.CatalogService
 	content          services.ContentService
+	reviews          services.ReviewService
 	previewResolver  AssetURLResolver
 	vectorResolver   AssetURLResolver
 	priceDisplayMode string
@@ -93,6 +97,13 @@ func WithPublicContentService(svc services.ContentService) PublicOption {
 	}
 }
 
+// WithPublicReviewService injects the review service used for product ratings and review feeds.
+func WithPublicReviewService(svc services.ReviewService) PublicOption {
+	return func(h *PublicHandlers) {
+		h.reviews = svc
+	}
+}
+
 // WithPublicPreviewResolver sets the resolver used for preview image URLs.
 func WithPublicPreviewResolver(resolver AssetURLResolver) PublicOption {
 	return func(h *PublicHandlers) {
@@ -149,6 +160,7 @@ func (h *PublicHandlers) Routes(r chi.Router) {
 	r.Get("/materials/{materialID}", h.getMaterial)
 	r.Get("/products", h.listProducts)
 	r.Get("/products/{productID}", h.getProduct)
+	r.Get("/products/{productID}/reviews", h.listProductReviews)
 	r.Get("/content/pages/{slug}", h.getPage)
 	r.Get("/content/guides", h.listGuides)
 	r.Get("/content/guides/{slug}", h.getGuide)
@@ -505,6 +517,7 @@ func (h *PublicHandlers) listProducts(w http.ResponseWriter, r *http.Request) {
 		}
 		items = append(items, payload)
 	}
+	h.attachProductRatings(r.Context(), items)
 
 	w.Header().Set("Cache-Control", productCacheControl)
 	response := productListResponse{
@@ -538,11 +551,88 @@ func (h *PublicHandlers) getProduct(w http.ResponseWriter, r *http.Request) {
 		httpx.WriteError(r.Context(), w, httpx.NewError("asset_resolution_failed", err.Error(), http.StatusInternalServerError))
 		return
 	}
+	ratings := []productPayload{payload.productPayload}
+	h.attachProductRatings(r.Context(), ratings)
+	payload.productPayload = ratings[0]
 
 	w.Header().Set("Cache-Control", productCacheControl)
 	writeJSON(w, http.StatusOK, payload)
 }
 
+func (h *PublicHandlers) listProductReviews(w http.ResponseWriter, r *http.Request) {
+	if h.reviews == nil {
+		httpx.WriteError(r.Context(), w, httpx.NewError("reviews_unavailable", "review service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+
+	productID := strings.TrimSpace(chi.URLParam(r, "productID"))
+	if productID == "" {
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_product_id", "product id is required", http.StatusBadRequest))
+		return
+	}
+
+	values := r.URL.Query()
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultProductReviewSize, maxProductReviewSize)
+	if err != nil {
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	page, err := h.reviews.ListPublicByProduct(r.Context(), services.ListProductReviewsCommand{
+		ProductID: productID,
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	})
+	if err != nil {
+		writeReviewError(r.Context(), w, err)
+		return
+	}
+
+	response := productReviewListResponse{
+		Reviews:       make([]publicReviewPayload, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, review := range page.Items {
+		response.Reviews = append(response.Reviews, buildPublicReviewPayload(review))
+	}
+	if summaries, err := h.reviews.GetProductRatings(r.Context(), []string{productID}); err == nil {
+		if summary, ok := summaries[productID]; ok {
+			response.Rating = buildProductRatingPayload(summary)
+		}
+	}
+
+	w.Header().Set("Cache-Control", productReviewCacheControl)
+	writeJSON(w, http.StatusOK, response)
+}
+
+// attachProductRatings decorates product payloads with their rating aggregates. Ratings are
+// supplementary, so lookup failures leave the payloads untouched instead of failing the request.
+func (h *PublicHandlers) attachProductRatings(ctx context.Context, items []productPayload) {
+	if h.reviews == nil || len(items) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	summaries, err := h.reviews.GetProductRatings(ctx, ids)
+	if err != nil {
+		return
+	}
+	for i := range items {
+		summary, ok := summaries[items[i].ID]
+		if !ok || summary.ReviewCount <= 0 {
+			continue
+		}
+		rating := buildProductRatingPayload(summary)
+		items[i].RatingAverage = rating.Average
+		items[i].RatingCount = rating.Count
+		items[i].RatingHistogram = rating.Histogram
+	}
+}
+
 func (h *PublicHandlers) getPage(w http.ResponseWriter, r *http.Request) {
 	if h.content == nil {
 		httpx.WriteError(r.Context(), w, httpx.NewError("content_unavailable", "content service is unavailable", http.StatusServiceUnavailable))
@@ -761,6 +851,34 @@ func (h *PublicHandlers) currentPriceDisplayMode() string {
 	return normalizePriceDisplayMode(h.priceDisplayMode)
 }
 
+func buildProductRatingPayload(summary services.ProductRatingSummary) *productRatingPayload {
+	histogram := make(map[string]int, len(summary.Histogram))
+	for i, count := range summary.Histogram {
+		histogram[strconv.Itoa(i+1)] = count
+	}
+	return &productRatingPayload{
+		Average:   math.Round(summary.AverageRating*100) / 100,
+		Count:     summary.ReviewCount,
+		Histogram: histogram,
+	}
+}
+
+func buildPublicReviewPayload(review services.Review) publicReviewPayload {
+	payload := publicReviewPayload{
+		ID:        review.ID,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		CreatedAt: formatTimestamp(review.CreatedAt),
+	}
+	if review.Reply != nil && review.Reply.Visible && strings.TrimSpace(review.Reply.Message) != "" {
+		payload.Reply = &publicReviewReplyPayload{
+			Message:   review.Reply.Message,
+			CreatedAt: formatTimestamp(review.Reply.CreatedAt),
+		}
+	}
+	return payload
+}
+
 func parseTemplateListFilter(r *http.Request) (services.TemplateFilter, error) {
 	if r == nil {
 		return services.TemplateFilter{}, errors.New("request cannot be nil")
@@ -1158,70 +1276,19 @@ func fallbackNonEmpty(value string, fallback string) string {
 }
 
 func writeCatalogError(ctx context.Context, w http.ResponseWriter, err error, resource string) {
-	if err == nil {
-		return
-	}
-	resource = strings.TrimSpace(resource)
+	resource = strings.ToLower(strings.TrimSpace(resource))
 	if resource == "" {
 		resource = "resource"
 	}
-	codePrefix := strings.ToLower(resource)
-
-	switch {
-	case errors.Is(err, services.ErrCatalogRepositoryMissing):
-		httpx.WriteError(ctx, w, httpx.NewError("catalog_unavailable", "catalog service is unavailable", http.StatusServiceUnavailable))
-		return
-	}
-
-	var repoErr repositories.RepositoryError
-	if errors.As(err, &repoErr) {
-		switch {
-		case repoErr.IsNotFound():
-			httpx.WriteError(ctx, w, httpx.NewError(fmt.Sprintf("%s_not_found", codePrefix), fmt.Sprintf("%s not found", resource), http.StatusNotFound))
-			return
-		case repoErr.IsUnavailable():
-			httpx.WriteError(ctx, w, httpx.NewError("catalog_unavailable", "catalog repository unavailable", http.StatusServiceUnavailable))
-			return
-		default:
-			httpx.WriteError(ctx, w, httpx.NewError("catalog_error", err.Error(), http.StatusInternalServerError))
-			return
-		}
-	}
-
-	httpx.WriteError(ctx, w, httpx.NewError("catalog_error", err.Error(), http.StatusInternalServerError))
+	writeServiceError(ctx, w, err, errorScope{resource: resource, service: "catalog"})
 }
 
 func writeContentError(ctx context.Context, w http.ResponseWriter, err error, resource string) {
-	if err == nil {
-		return
-	}
-	resource = strings.TrimSpace(resource)
+	resource = strings.ToLower(strings.TrimSpace(resource))
 	if resource == "" {
 		resource = "resource"
 	}
-
-	switch {
-	case errors.Is(err, services.ErrContentRepositoryMissing):
-		httpx.WriteError(ctx, w, httpx.NewError("content_unavailable", "content service is unavailable", http.StatusServiceUnavailable))
-		return
-	}
-
-	var repoErr repositories.RepositoryError
-	if errors.As(err, &repoErr) {
-		switch {
-		case repoErr.IsNotFound():
-			httpx.WriteError(ctx, w, httpx.NewError(fmt.Sprintf("%s_not_found", resource), fmt.Sprintf("%s not found", resource), http.StatusNotFound))
-			return
-		case repoErr.IsUnavailable():
-			httpx.WriteError(ctx, w, httpx.NewError("content_unavailable", "content repository unavailable", http.StatusServiceUnavailable))
-			return
-		default:
-			httpx.WriteError(ctx, w, httpx.NewError("content_error", err.Error(), http.StatusInternalServerError))
-			return
-		}
-	}
-
-	httpx.WriteError(ctx, w, httpx.NewError("content_error", err.Error(), http.StatusInternalServerError))
+	writeServiceError(ctx, w, err, errorScope{resource: resource, service: "content"})
 }
 
 func writeJSON(w http.ResponseWriter, status int, payload any) {
@@ -1315,25 +1382,53 @@ type productListResponse struct {
 }
 
 type productPayload struct {
-	ID                    string   `json:"id"`
-	SKU                   string   `json:"sku,omitempty"`
-	Name                  string   `json:"name"`
-	Description           string   `json:"description,omitempty"`
-	Shape                 string   `json:"shape,omitempty"`
-	SizesMm               []int    `json:"sizes_mm,omitempty"`
-	DefaultMaterialID     string   `json:"default_material_id,omitempty"`
-	MaterialIDs           []string `json:"material_ids,omitempty"`
-	BasePrice             int64    `json:"base_price,omitempty"`
-	Currency              string   `json:"currency,omitempty"`
-	PreviewURL            string   `json:"preview_url,omitempty"`
-	ImageURLs             []string `json:"image_urls,omitempty"`
-	IsCustomizable        bool     `json:"is_customizable"`
-	InventoryStatus       string   `json:"inventory_status,omitempty"`
-	CompatibleTemplateIDs []string `json:"compatible_template_ids,omitempty"`
-	LeadTimeDays          int      `json:"lead_time_days,omitempty"`
-	PriceDisplay          string   `json:"price_display,omitempty"`
-	CreatedAt             string   `json:"created_at,omitempty"`
-	UpdatedAt             string   `json:"updated_at,omitempty"`
+	ID                    string         `json:"id"`
+	SKU                   string         `json:"sku,omitempty"`
+	Name                  string         `json:"name"`
+	Description           string         `json:"description,omitempty"`
+	Shape                 string         `json:"shape,omitempty"`
+	SizesMm               []int          `json:"sizes_mm,omitempty"`
+	DefaultMaterialID     string         `json:"default_material_id,omitempty"`
+	MaterialIDs           []string       `json:"material_ids,omitempty"`
+	BasePrice             int64          `json:"base_price,omitempty"`
+	Currency              string         `json:"currency,omitempty"`
+	PreviewURL            string         `json:"preview_url,omitempty"`
+	ImageURLs             []string       `json:"image_urls,omitempty"`
+	IsCustomizable        bool           `json:"is_customizable"`
+	InventoryStatus       string         `json:"inventory_status,omitempty"`
+	CompatibleTemplateIDs []string       `json:"compatible_template_ids,omitempty"`
+	LeadTimeDays          int            `json:"lead_time_days,omitempty"`
+	PriceDisplay          string         `json:"price_display,omitempty"`
+	RatingAverage         float64        `json:"rating_average,omitempty"`
+	RatingCount           int            `json:"rating_count,omitempty"`
+	RatingHistogram       map[string]int `json:"rating_histogram,omitempty"`
+	CreatedAt             string         `json:"created_at,omitempty"`
+	UpdatedAt             string         `json:"updated_at,omitempty"`
+}
+
+type productReviewListResponse struct {
+	Reviews       []publicReviewPayload `json:"reviews"`
+	NextPageToken string                `json:"next_page_token,omitempty"`
+	Rating        *productRatingPayload `json:"rating,omitempty"`
+}
+
+type productRatingPayload struct {
+	Average   float64        `json:"average"`
+	Count     int            `json:"count"`
+	Histogram map[string]int `json:"histogram"`
+}
+
+type publicReviewPayload struct {
+	ID        string                    `json:"id"`
+	Rating    int                       `json:"rating"`
+	Comment   string                    `json:"comment,omitempty"`
+	Reply     *publicReviewReplyPayload `json:"reply,omitempty"`
+	CreatedAt string                    `json:"created_at,omitempty"`
+}
+
+type publicReviewReplyPayload struct {
+	Message   string `json:"message"`
+	CreatedAt string `json:"created_at,omitempty"`
 }
 
 type productDetailPayload struct {
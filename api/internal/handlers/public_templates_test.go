@@ -870,6 +870,136 @@ func TestPublicHandlers_GetProduct(t *testing.T) {
 	}
 }
 
+func TestPublicHandlers_GetProduct_IncludesRating(t *testing.T) {
+	catalog := &stubCatalogService{
+		productGetProd: services.Product{
+			ProductSummary: services.ProductSummary{ID: "prod_round", Name: "Round Hanko"},
+		},
+	}
+	reviews := &stubReviewService{
+		ratingsFn: func(_ context.Context, ids []string) (map[string]services.ProductRatingSummary, error) {
+			if len(ids) != 1 || ids[0] != "prod_round" {
+				t.Fatalf("unexpected rating lookup %v", ids)
+			}
+			return map[string]services.ProductRatingSummary{
+				"prod_round": {ProductID: "prod_round", ReviewCount: 3, AverageRating: 13.0 / 3.0, Histogram: [5]int{0, 0, 1, 0, 2}},
+			}, nil
+		},
+	}
+	handler := NewPublicHandlers(WithPublicCatalogService(catalog), WithPublicReviewService(reviews))
+
+	router := chi.NewRouter()
+	router.Route("/", handler.Routes)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/prod_round", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 got %d", rec.Code)
+	}
+	var payload productDetailPayload
+	if err := json.NewDecoder(rec.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.RatingCount != 3 || payload.RatingAverage != 4.33 {
+		t.Fatalf("unexpected rating fields count=%d average=%v", payload.RatingCount, payload.RatingAverage)
+	}
+	if payload.RatingHistogram["5"] != 2 || payload.RatingHistogram["1"] != 0 {
+		t.Fatalf("unexpected rating histogram %#v", payload.RatingHistogram)
+	}
+}
+
+func TestPublicHandlers_ListProductReviews(t *testing.T) {
+	createdAt := time.Date(2025, time.June, 3, 9, 0, 0, 0, time.UTC)
+	reviews := &stubReviewService{
+		listProductFn: func(_ context.Context, cmd services.ListProductReviewsCommand) (domain.CursorPage[services.Review], error) {
+			if cmd.ProductID != "prod_round" || cmd.Pagination.PageSize != 5 || cmd.Pagination.PageToken != "tok" {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			return domain.CursorPage[services.Review]{
+				Items: []services.Review{
+					{
+						ID:        "rev_1",
+						OrderRef:  "ord_1",
+						UserRef:   "usr_1",
+						Rating:    5,
+						Comment:   "Beautiful",
+						Status:    domain.ReviewStatusApproved,
+						Reply:     &domain.ReviewReply{Message: "Thank you!", Visible: true},
+						CreatedAt: createdAt,
+					},
+					{
+						ID:     "rev_2",
+						Rating: 3,
+						Status: domain.ReviewStatusApproved,
+						Reply:  &domain.ReviewReply{Message: "internal note", Visible: false},
+					},
+				},
+				NextPageToken: "next",
+			}, nil
+		},
+		ratingsFn: func(context.Context, []string) (map[string]services.ProductRatingSummary, error) {
+			return map[string]services.ProductRatingSummary{
+				"prod_round": {ProductID: "prod_round", ReviewCount: 2, AverageRating: 4, Histogram: [5]int{0, 0, 1, 0, 1}},
+			}, nil
+		},
+	}
+	handler := NewPublicHandlers(WithPublicReviewService(reviews))
+
+	router := chi.NewRouter()
+	router.Route("/", handler.Routes)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/prod_round/reviews?pageSize=5&pageToken=tok", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 got %d: %s", rec.Code, rec.Body.String())
+	}
+	if cache := rec.Result().Header.Get("Cache-Control"); cache != productReviewCacheControl {
+		t.Fatalf("expected cache control %q got %q", productReviewCacheControl, cache)
+	}
+	if strings.Contains(rec.Body.String(), "usr_1") || strings.Contains(rec.Body.String(), "ord_1") {
+		t.Fatalf("expected public payload to omit order and user references: %s", rec.Body.String())
+	}
+
+	var payload productReviewListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Reviews) != 2 || payload.NextPageToken != "next" {
+		t.Fatalf("unexpected payload %#v", payload)
+	}
+	if payload.Reviews[0].Reply == nil || payload.Reviews[0].Reply.Message != "Thank you!" {
+		t.Fatalf("expected visible reply got %#v", payload.Reviews[0].Reply)
+	}
+	if payload.Reviews[1].Reply != nil {
+		t.Fatalf("expected hidden reply to be omitted got %#v", payload.Reviews[1].Reply)
+	}
+	if payload.Rating == nil || payload.Rating.Count != 2 || payload.Rating.Histogram["3"] != 1 {
+		t.Fatalf("unexpected rating summary %#v", payload.Rating)
+	}
+}
+
+func TestPublicHandlers_ListProductReviews_Unavailable(t *testing.T) {
+	handler := NewPublicHandlers()
+
+	router := chi.NewRouter()
+	router.Route("/", handler.Routes)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/prod_round/reviews", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 got %d", rec.Code)
+	}
+}
+
 func TestPublicHandlers_GetProduct_NotFound(t *testing.T) {
 	handler := NewPublicHandlers(WithPublicCatalogService(&stubCatalogService{
 		productGetErr: newRepositoryError(true, false, false),
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/auth"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const (
+	defaultReviewPageSize      = 20
+	maxReviewPageSize          = 100
+	defaultModerationPageSize  = 50
+	maxModerationPageSize      = 200
+	defaultModerationQueueName = "pending"
+)
+
+// ReviewHandlers exposes customer review submission and staff moderation endpoints.
+type ReviewHandlers struct {
+	reviews services.ReviewService
+}
+
+// ReviewOption customises construction of ReviewHandlers.
+type ReviewOption func(*ReviewHandlers)
+
+// WithReviewService injects the review service backing the endpoints.
+func WithReviewService(svc services.ReviewService) ReviewOption {
+	return func(h *ReviewHandlers) {
+		h.reviews = svc
+	}
+}
+
+// NewReviewHandlers constructs review handlers with the provided options.
+func NewReviewHandlers(opts ...ReviewOption) *ReviewHandlers {
+	handler := &ReviewHandlers{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// Routes registers customer review endpoints under /reviews.
+func (h *ReviewHandlers) Routes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Post("/", h.createReview)
+	r.Get("/", h.listMyReviews)
+}
+
+// AdminRoutes registers staff moderation endpoints under /admin.
+func (h *ReviewHandlers) AdminRoutes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Get("/reviews", h.listReviewsForModeration)
+	r.Put("/reviews/{reviewID}:moderate", h.moderateReview)
+	r.Post("/reviews/{reviewID}:store-reply", h.storeReply)
+}
+
+type createReviewRequest struct {
+	OrderID string `json:"order_id"`
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+type moderateReviewRequest struct {
+	Moderation string `json:"moderation"`
+}
+
+type storeReplyRequest struct {
+	Body     string `json:"body"`
+	IsPublic *bool  `json:"is_public"`
+}
+
+type reviewListPayload struct {
+	Reviews       []reviewPayload `json:"reviews"`
+	NextPageToken string          `json:"next_page_token,omitempty"`
+}
+
+type reviewPayload struct {
+	ID          string              `json:"id"`
+	OrderID     string              `json:"order_id"`
+	UserID      string              `json:"user_id,omitempty"`
+	Rating      int                 `json:"rating"`
+	Comment     string              `json:"comment"`
+	Status      string              `json:"status"`
+	ModeratedBy string              `json:"moderated_by,omitempty"`
+	ModeratedAt string              `json:"moderated_at,omitempty"`
+	Reply       *reviewReplyPayload `json:"reply,omitempty"`
+	CreatedAt   string              `json:"created_at,omitempty"`
+	UpdatedAt   string              `json:"updated_at,omitempty"`
+}
+
+type reviewReplyPayload struct {
+	Message   string `json:"message"`
+	AuthorRef string `json:"author_ref,omitempty"`
+	Visible   bool   `json:"visible"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+func (h *ReviewHandlers) createReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reviews == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("reviews_unavailable", "review service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireUserIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req createReviewRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	review, err := h.reviews.Create(ctx, services.CreateReviewCommand{
+		OrderID: strings.TrimSpace(req.OrderID),
+		UserID:  identity.UID,
+		Rating:  req.Rating,
+		Comment: req.Comment,
+		ActorID: identity.UID,
+	})
+	if err != nil {
+		writeReviewError(ctx, w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, buildReviewPayload(review, false))
+}
+
+func (h *ReviewHandlers) listMyReviews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reviews == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("reviews_unavailable", "review service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireUserIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	if orderID := strings.TrimSpace(values.Get("orderId")); orderID != "" {
+		review, err := h.reviews.GetByOrder(ctx, services.GetReviewByOrderCommand{
+			OrderID: orderID,
+			ActorID: identity.UID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrReviewNotFound) {
+				writeJSON(w, http.StatusOK, reviewListPayload{Reviews: []reviewPayload{}})
+				return
+			}
+			writeReviewError(ctx, w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, reviewListPayload{Reviews: []reviewPayload{buildReviewPayload(review, false)}})
+		return
+	}
+
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultReviewPageSize, maxReviewPageSize)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	page, err := h.reviews.ListByUser(ctx, services.ListUserReviewsCommand{
+		UserID: identity.UID,
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	})
+	if err != nil {
+		writeReviewError(ctx, w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buildReviewListPayload(page, false))
+}
+
+func (h *ReviewHandlers) listReviewsForModeration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reviews == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("reviews_unavailable", "review service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	if _, ok := requireStaffIdentity(w, r); !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultModerationPageSize, maxModerationPageSize)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	statuses := make([]services.ReviewStatus, 0, 1)
+	moderation := strings.TrimSpace(values.Get("moderation"))
+	if moderation == "" {
+		moderation = defaultModerationQueueName
+	}
+	if !strings.EqualFold(moderation, "all") {
+		for _, part := range strings.Split(moderation, ",") {
+			if trimmed := strings.TrimSpace(part); trimmed != "" {
+				statuses = append(statuses, services.ReviewStatus(trimmed))
+			}
+		}
+	}
+
+	page, err := h.reviews.ListForModeration(ctx, services.ListReviewsForModerationCommand{
+		Status: statuses,
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	})
+	if err != nil {
+		writeReviewError(ctx, w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buildReviewListPayload(page, true))
+}
+
+func (h *ReviewHandlers) moderateReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reviews == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("reviews_unavailable", "review service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	reviewID := strings.TrimSpace(chi.URLParam(r, "reviewID"))
+	if reviewID == "" {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_review_id", "review id is required", http.StatusBadRequest))
+		return
+	}
+
+	var req moderateReviewRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+
+	review, err := h.reviews.Moderate(ctx, services.ModerateReviewCommand{
+		ReviewID: reviewID,
+		ActorID:  identity.UID,
+		Status:   services.ReviewStatus(strings.ToLower(strings.TrimSpace(req.Moderation))),
+	})
+	if err != nil {
+		writeReviewError(ctx, w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buildReviewPayload(review, true))
+}
+
+func (h *ReviewHandlers) storeReply(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reviews == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("reviews_unavailable", "review service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireStaffIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	reviewID := strings.TrimSpace(chi.URLParam(r, "reviewID"))
+	if reviewID == "" {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_review_id", "review id is required", http.StatusBadRequest))
+		return
+	}
+
+	var req storeReplyRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	visible := true
+	if req.IsPublic != nil {
+		visible = *req.IsPublic
+	}
+
+	review, err := h.reviews.StoreReply(ctx, services.StoreReviewReplyCommand{
+		ReviewID: reviewID,
+		ActorID:  identity.UID,
+		Message:  req.Body,
+		Visible:  visible,
+	})
+	if err != nil {
+		writeReviewError(ctx, w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, buildReviewPayload(review, true))
+}
+
+// requireUserIdentity resolves the Firebase identity attached by the authentication middleware.
+func requireUserIdentity(w http.ResponseWriter, r *http.Request) (*auth.Identity, bool) {
+	identity, ok := auth.IdentityFromContext(r.Context())
+	if !ok || identity == nil || strings.TrimSpace(identity.UID) == "" {
+		httpx.WriteError(r.Context(), w, httpx.NewError("unauthenticated", "authentication required", http.StatusUnauthorized))
+		return nil, false
+	}
+	return identity, true
+}
+
+// requireStaffIdentity resolves the caller identity and ensures it holds a staff or admin role.
+func requireStaffIdentity(w http.ResponseWriter, r *http.Request) (*auth.Identity, bool) {
+	identity, ok := requireUserIdentity(w, r)
+	if !ok {
+		return nil, false
+	}
+	if !identity.HasAnyRole(auth.RoleStaff, auth.RoleAdmin) {
+		httpx.WriteError(r.Context(), w, httpx.NewError("insufficient_role", "staff role required", http.StatusForbidden))
+		return nil, false
+	}
+	return identity, true
+}
+
+func writeReviewError(ctx context.Context, w http.ResponseWriter, err error) {
+	writeServiceError(ctx, w, err, errorScope{resource: "review", service: "reviews"})
+}
+
+func buildReviewListPayload(page domain.CursorPage[services.Review], staff bool) reviewListPayload {
+	payload := reviewListPayload{
+		Reviews:       make([]reviewPayload, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, review := range page.Items {
+		payload.Reviews = append(payload.Reviews, buildReviewPayload(review, staff))
+	}
+	return payload
+}
+
+func buildReviewPayload(review services.Review, staff bool) reviewPayload {
+	payload := reviewPayload{
+		ID:        review.ID,
+		OrderID:   strings.TrimPrefix(review.OrderRef, "/orders/"),
+		UserID:    strings.TrimPrefix(review.UserRef, "/users/"),
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		Status:    string(review.Status),
+		CreatedAt: formatTimestamp(review.CreatedAt),
+		UpdatedAt: formatTimestamp(review.UpdatedAt),
+	}
+	if staff {
+		if review.ModeratedBy != nil {
+			payload.ModeratedBy = *review.ModeratedBy
+		}
+		if review.ModeratedAt != nil {
+			payload.ModeratedAt = formatTimestamp(*review.ModeratedAt)
+		}
+	}
+	if review.Reply != nil && (staff || review.Reply.Visible) {
+		reply := &reviewReplyPayload{
+			Message:   review.Reply.Message,
+			Visible:   review.Reply.Visible,
+			CreatedAt: formatTimestamp(review.Reply.CreatedAt),
+			UpdatedAt: formatTimestamp(review.Reply.UpdatedAt),
+		}
+		if staff {
+			reply.AuthorRef = review.Reply.AuthorRef
+		}
+		payload.Reply = reply
+	}
+	return payload
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/auth"
+	"github.com/hanko-field/api/internal/services"
+)
+
+func TestReviewHandlersCreateReview(t *testing.T) {
+	created := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	svc := &stubReviewService{
+		createFn: func(_ context.Context, cmd services.CreateReviewCommand) (services.Review, error) {
+			if cmd.OrderID != "ord_1" || cmd.UserID != "usr_1" || cmd.ActorID != "usr_1" {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			if cmd.Rating != 5 || cmd.Comment != "great" {
+				t.Fatalf("unexpected review content %+v", cmd)
+			}
+			return services.Review{
+				ID:        "rev_1",
+				OrderRef:  "/orders/ord_1",
+				UserRef:   "/users/usr_1",
+				Rating:    5,
+				Comment:   "great",
+				Status:    domain.ReviewStatusPending,
+				CreatedAt: created,
+				UpdatedAt: created,
+			}, nil
+		},
+	}
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(svc)).Routes(router)
+
+	req := newIdentityRequest(http.MethodPost, "/", `{"order_id":"ord_1","rating":5,"comment":"great"}`, "usr_1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload reviewPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.ID != "rev_1" || payload.OrderID != "ord_1" || payload.Status != "pending" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if payload.CreatedAt != "2025-06-01T09:00:00Z" {
+		t.Fatalf("unexpected created_at %q", payload.CreatedAt)
+	}
+}
+
+func TestReviewHandlersRequireAuthentication(t *testing.T) {
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(&stubReviewService{})).Routes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestReviewHandlersListByOrderHidesPrivateReply(t *testing.T) {
+	svc := &stubReviewService{
+		getByOrderFn: func(_ context.Context, cmd services.GetReviewByOrderCommand) (services.Review, error) {
+			if cmd.OrderID != "ord_9" || cmd.ActorID != "usr_1" {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			return services.Review{
+				ID:       "rev_9",
+				OrderRef: "/orders/ord_9",
+				Status:   domain.ReviewStatusApproved,
+				Reply:    &domain.ReviewReply{Message: "internal", Visible: false},
+			}, nil
+		},
+	}
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(svc)).Routes(router)
+
+	req := newIdentityRequest(http.MethodGet, "/?orderId=ord_9", "", "usr_1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload reviewListPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Reviews) != 1 || payload.Reviews[0].ID != "rev_9" {
+		t.Fatalf("unexpected reviews %+v", payload.Reviews)
+	}
+	if payload.Reviews[0].Reply != nil {
+		t.Fatalf("expected private reply to be hidden, got %+v", payload.Reviews[0].Reply)
+	}
+}
+
+func TestReviewHandlersModerationRequiresStaff(t *testing.T) {
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(&stubReviewService{})).AdminRoutes(router)
+
+	req := newIdentityRequest(http.MethodPut, "/reviews/rev_1:moderate", `{"moderation":"approved"}`, "usr_1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestReviewHandlersModerateReview(t *testing.T) {
+	svc := &stubReviewService{
+		moderateFn: func(_ context.Context, cmd services.ModerateReviewCommand) (services.Review, error) {
+			if cmd.ReviewID != "rev_1" || cmd.ActorID != "staff_1" || cmd.Status != domain.ReviewStatusApproved {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			moderator := "/users/staff_1"
+			return services.Review{ID: "rev_1", Status: domain.ReviewStatusApproved, ModeratedBy: &moderator}, nil
+		},
+	}
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(svc)).AdminRoutes(router)
+
+	req := newIdentityRequest(http.MethodPut, "/reviews/rev_1:moderate", `{"moderation":"Approved"}`, "staff_1", auth.RoleStaff)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload reviewPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Status != "approved" || payload.ModeratedBy != "/users/staff_1" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestReviewHandlersModerateInvalidState(t *testing.T) {
+	svc := &stubReviewService{
+		moderateFn: func(context.Context, services.ModerateReviewCommand) (services.Review, error) {
+			return services.Review{}, services.ErrReviewInvalidState
+		},
+	}
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(svc)).AdminRoutes(router)
+
+	req := newIdentityRequest(http.MethodPut, "/reviews/rev_1:moderate", `{"moderation":"rejected"}`, "staff_1", auth.RoleAdmin)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestReviewHandlersListForModerationDefaultsToPending(t *testing.T) {
+	svc := &stubReviewService{
+		listModerationFn: func(_ context.Context, cmd services.ListReviewsForModerationCommand) (domain.CursorPage[services.Review], error) {
+			if len(cmd.Status) != 1 || cmd.Status[0] != domain.ReviewStatusPending {
+				t.Fatalf("expected pending filter, got %+v", cmd.Status)
+			}
+			if cmd.Pagination.PageSize != defaultModerationPageSize {
+				t.Fatalf("expected default page size, got %d", cmd.Pagination.PageSize)
+			}
+			return domain.CursorPage[services.Review]{
+				Items:         []services.Review{{ID: "rev_2", Status: domain.ReviewStatusPending}},
+				NextPageToken: "next",
+			}, nil
+		},
+	}
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(svc)).AdminRoutes(router)
+
+	req := newIdentityRequest(http.MethodGet, "/reviews", "", "staff_1", auth.RoleStaff)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload reviewListPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Reviews) != 1 || payload.NextPageToken != "next" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestReviewHandlersStoreReply(t *testing.T) {
+	svc := &stubReviewService{
+		storeReplyFn: func(_ context.Context, cmd services.StoreReviewReplyCommand) (services.Review, error) {
+			if cmd.ReviewID != "rev_1" || cmd.Message != "thanks" || cmd.Visible {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			return services.Review{
+				ID:    "rev_1",
+				Reply: &domain.ReviewReply{Message: "thanks", AuthorRef: "/users/staff_1"},
+			}, nil
+		},
+	}
+	router := chi.NewRouter()
+	NewReviewHandlers(WithReviewService(svc)).AdminRoutes(router)
+
+	req := newIdentityRequest(http.MethodPost, "/reviews/rev_1:store-reply", `{"body":"thanks","is_public":false}`, "staff_1", auth.RoleStaff)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload reviewPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Reply == nil || payload.Reply.AuthorRef != "/users/staff_1" {
+		t.Fatalf("expected staff reply in payload, got %+v", payload.Reply)
+	}
+}
+
+func newIdentityRequest(method, target, body, uid string, roles ...string) *http.Request {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	ctx := auth.WithIdentity(req.Context(), &auth.Identity{UID: uid, Roles: roles})
+	return req.WithContext(ctx)
+}
+
+type stubReviewService struct {
+	createFn         func(context.Context, services.CreateReviewCommand) (services.Review, error)
+	getByOrderFn     func(context.Context, services.GetReviewByOrderCommand) (services.Review, error)
+	listByUserFn     func(context.Context, services.ListUserReviewsCommand) (domain.CursorPage[services.Review], error)
+	listModerationFn func(context.Context, services.ListReviewsForModerationCommand) (domain.CursorPage[services.Review], error)
+	moderateFn       func(context.Context, services.ModerateReviewCommand) (services.Review, error)
+	storeReplyFn     func(context.Context, services.StoreReviewReplyCommand) (services.Review, error)
+	listProductFn    func(context.Context, services.ListProductReviewsCommand) (domain.CursorPage[services.Review], error)
+	ratingsFn        func(context.Context, []string) (map[string]services.ProductRatingSummary, error)
+}
+
+func (s *stubReviewService) Create(ctx context.Context, cmd services.CreateReviewCommand) (services.Review, error) {
+	if s.createFn != nil {
+		return s.createFn(ctx, cmd)
+	}
+	return services.Review{}, nil
+}
+
+func (s *stubReviewService) GetByOrder(ctx context.Context, cmd services.GetReviewByOrderCommand) (services.Review, error) {
+	if s.getByOrderFn != nil {
+		return s.getByOrderFn(ctx, cmd)
+	}
+	return services.Review{}, services.ErrReviewNotFound
+}
+
+func (s *stubReviewService) ListByUser(ctx context.Context, cmd services.ListUserReviewsCommand) (domain.CursorPage[services.Review], error) {
+	if s.listByUserFn != nil {
+		return s.listByUserFn(ctx, cmd)
+	}
+	return domain.CursorPage[services.Review]{}, nil
+}
+
+func (s *stubReviewService) ListForModeration(ctx context.Context, cmd services.ListReviewsForModerationCommand) (domain.CursorPage[services.Review], error) {
+	if s.listModerationFn != nil {
+		return s.listModerationFn(ctx, cmd)
+	}
+	return domain.CursorPage[services.Review]{}, nil
+}
+
+func (s *stubReviewService) Moderate(ctx context.Context, cmd services.ModerateReviewCommand) (services.Review, error) {
+	if s.moderateFn != nil {
+		return s.moderateFn(ctx, cmd)
+	}
+	return services.Review{}, nil
+}
+
+func (s *stubReviewService) StoreReply(ctx context.Context, cmd services.StoreReviewReplyCommand) (services.Review, error) {
+	if s.storeReplyFn != nil {
+		return s.storeReplyFn(ctx, cmd)
+	}
+	return services.Review{}, nil
+}
+
+func (s *stubReviewService) ListPublicByProduct(ctx context.Context, cmd services.ListProductReviewsCommand) (domain.CursorPage[services.Review], error) {
+	if s.listProductFn != nil {
+		return s.listProductFn(ctx, cmd)
+	}
+	return domain.CursorPage[services.Review]{}, nil
+}
+
+func (s *stubReviewService) GetProductRatings(ctx context.Context, productIDs []string) (map[string]services.ProductRatingSummary, error) {
+	if s.ratingsFn != nil {
+		return s.ratingsFn(ctx, productIDs)
+	}
+	return map[string]services.ProductRatingSummary{}, nil
+}
@@ -24,6 +24,7 @@ type routerConfig struct {
 	designs  RouteRegistrar
 	cart     RouteRegistrar
 	orders   RouteRegistrar
+	reviews  RouteRegistrar
 	admin    RouteRegistrar
 	webhooks RouteRegistrar
 	internal RouteRegistrar
@@ -100,6 +101,7 @@ func NewRouter(opts ...Option) chi.Router {
 		mount("/designs", cfg.designs, "designs", nil)
 		mount("/cart", cfg.cart, "cart", nil)
 		mount("/orders", cfg.orders, "orders", nil)
+		mount("/reviews", cfg.reviews, "reviews", nil)
 		mount("/admin", cfg.admin, "admin", nil)
 		mount("/webhooks", cfg.webhooks, "webhooks", cfg.webhookMiddlewares)
 		mount("/internal", cfg.internal, "internal", cfg.internalMiddlewares)
@@ -157,9 +159,27 @@ func WithOrderRoutes(reg RouteRegistrar) Option {
 	}
 }
 
-// WithAdminRoutes configures the registrar responsible for admin endpoints.
+// WithReviewRoutes configures the registrar responsible for customer review endpoints.
+func WithReviewRoutes(reg RouteRegistrar) Option {
+	return func(cfg *routerConfig) {
+		cfg.reviews = reg
+	}
+}
+
+// WithAdminRoutes configures a registrar for admin endpoints. Admin surfaces are owned by several
+// handler sets, so repeated calls are combined and registered in order.
 func WithAdminRoutes(reg RouteRegistrar) Option {
 	return func(cfg *routerConfig) {
+		if reg == nil {
+			return
+		}
+		if prev := cfg.admin; prev != nil {
+			cfg.admin = func(r chi.Router) {
+				prev(r)
+				reg(r)
+			}
+			return
+		}
 		cfg.admin = reg
 	}
 }
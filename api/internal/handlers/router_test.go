@@ -153,3 +153,48 @@ func TestNewRouter_GroupMiddleware(t *testing.T) {
 		t.Fatalf("expected webhook middleware to set header")
 	}
 }
+
+func TestNewRouter_CombinesAdminRegistrars(t *testing.T) {
+	first := func(r chi.Router) {
+		r.Get("/first", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	second := func(r chi.Router) {
+		r.Get("/second", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+	}
+
+	router := NewRouter(WithAdminRoutes(first), WithAdminRoutes(second))
+
+	for path, want := range map[string]int{
+		"/api/v1/admin/first":  http.StatusNoContent,
+		"/api/v1/admin/second": http.StatusAccepted,
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Code != want {
+			t.Fatalf("%s: expected status %d, got %d", path, want, rr.Code)
+		}
+	}
+}
+
+func TestNewRouter_ReviewRoutes(t *testing.T) {
+	registrar := func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+
+	router := NewRouter(WithReviewRoutes(registrar))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rr.Code)
+	}
+}
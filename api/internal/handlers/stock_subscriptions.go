@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const (
+	defaultStockSubscriptionPageSize = 20
+	maxStockSubscriptionPageSize     = 100
+)
+
+// StockSubscriptionHandlers exposes the customer's back-in-stock subscriptions.
+type StockSubscriptionHandlers struct {
+	subscriptions services.StockSubscriptionService
+}
+
+// StockSubscriptionOption customises construction of StockSubscriptionHandlers.
+type StockSubscriptionOption func(*StockSubscriptionHandlers)
+
+// WithStockSubscriptionService injects the subscription service backing the endpoints.
+func WithStockSubscriptionService(svc services.StockSubscriptionService) StockSubscriptionOption {
+	return func(h *StockSubscriptionHandlers) {
+		h.subscriptions = svc
+	}
+}
+
+// NewStockSubscriptionHandlers constructs stock subscription handlers with the provided options.
+func NewStockSubscriptionHandlers(opts ...StockSubscriptionOption) *StockSubscriptionHandlers {
+	handler := &StockSubscriptionHandlers{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// Routes registers the subscription endpoints under /me.
+func (h *StockSubscriptionHandlers) Routes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Get("/stock-subscriptions", h.listSubscriptions)
+	r.Post("/stock-subscriptions", h.subscribe)
+	r.Delete("/stock-subscriptions/{subscriptionID}", h.unsubscribe)
+}
+
+type subscribeStockRequest struct {
+	SKU        string `json:"sku"`
+	ProductRef string `json:"product_ref"`
+	Trigger    string `json:"trigger"`
+	TTLDays    int    `json:"ttl_days"`
+}
+
+type stockSubscriptionListPayload struct {
+	Subscriptions []stockSubscriptionPayload `json:"subscriptions"`
+	NextPageToken string                     `json:"next_page_token,omitempty"`
+}
+
+type stockSubscriptionPayload struct {
+	ID         string `json:"id"`
+	SKU        string `json:"sku,omitempty"`
+	ProductRef string `json:"product_ref,omitempty"`
+	Trigger    string `json:"trigger"`
+	Status     string `json:"status"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	NotifiedAt string `json:"notified_at,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+func (h *StockSubscriptionHandlers) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.subscriptions == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("stock_subscriptions_unavailable", "stock subscription service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireUserIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	pageSize, err := parseLimitedPageSize(values.Get("pageSize"), defaultStockSubscriptionPageSize, maxStockSubscriptionPageSize)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", err.Error(), http.StatusBadRequest))
+		return
+	}
+	var statuses []services.StockSubscriptionStatus
+	for _, part := range strings.Split(values.Get("status"), ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			statuses = append(statuses, services.StockSubscriptionStatus(strings.ToLower(trimmed)))
+		}
+	}
+
+	page, err := h.subscriptions.ListSubscriptions(ctx, services.ListStockSubscriptionsCommand{
+		UserID: identity.UID,
+		Status: statuses,
+		Pagination: services.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(values.Get("pageToken")),
+		},
+	})
+	if err != nil {
+		writeStockSubscriptionError(ctx, w, err)
+		return
+	}
+
+	payload := stockSubscriptionListPayload{
+		Subscriptions: make([]stockSubscriptionPayload, 0, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+	}
+	for _, subscription := range page.Items {
+		payload.Subscriptions = append(payload.Subscriptions, buildStockSubscriptionPayload(subscription))
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *StockSubscriptionHandlers) subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.subscriptions == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("stock_subscriptions_unavailable", "stock subscription service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireUserIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	var req subscribeStockRequest
+	if !decodeInternalRequest(w, r, &req, true) {
+		return
+	}
+	if req.TTLDays < 0 {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_request", "ttl_days must not be negative", http.StatusBadRequest))
+		return
+	}
+
+	subscription, err := h.subscriptions.Subscribe(ctx, services.SubscribeStockCommand{
+		UserID:     identity.UID,
+		SKU:        strings.TrimSpace(req.SKU),
+		ProductRef: strings.TrimSpace(req.ProductRef),
+		Trigger:    services.StockSubscriptionTrigger(strings.ToLower(strings.TrimSpace(req.Trigger))),
+		TTL:        time.Duration(req.TTLDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		writeStockSubscriptionError(ctx, w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, buildStockSubscriptionPayload(subscription))
+}
+
+func (h *StockSubscriptionHandlers) unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.subscriptions == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("stock_subscriptions_unavailable", "stock subscription service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+	identity, ok := requireUserIdentity(w, r)
+	if !ok {
+		return
+	}
+
+	subscriptionID := strings.TrimSpace(chi.URLParam(r, "subscriptionID"))
+	if subscriptionID == "" {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_subscription_id", "subscription id is required", http.StatusBadRequest))
+		return
+	}
+
+	if err := h.subscriptions.Unsubscribe(ctx, services.UnsubscribeStockCommand{
+		UserID:         identity.UID,
+		SubscriptionID: subscriptionID,
+	}); err != nil {
+		writeStockSubscriptionError(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeStockSubscriptionError(ctx context.Context, w http.ResponseWriter, err error) {
+	writeServiceError(ctx, w, err, errorScope{resource: "stock_subscription", service: "stock_subscriptions"})
+}
+
+func buildStockSubscriptionPayload(subscription domain.StockSubscription) stockSubscriptionPayload {
+	payload := stockSubscriptionPayload{
+		ID:         subscription.ID,
+		SKU:        subscription.SKU,
+		ProductRef: subscription.ProductRef,
+		Trigger:    string(subscription.Trigger),
+		Status:     string(subscription.Status),
+		ExpiresAt:  formatTimestamp(subscription.ExpiresAt),
+		CreatedAt:  formatTimestamp(subscription.CreatedAt),
+		UpdatedAt:  formatTimestamp(subscription.UpdatedAt),
+	}
+	if subscription.NotifiedAt != nil {
+		payload.NotifiedAt = formatTimestamp(*subscription.NotifiedAt)
+	}
+	return payload
+}
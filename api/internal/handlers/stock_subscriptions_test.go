@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/services"
+)
+
+func TestStockSubscriptionHandlersSubscribe(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc := &stubStockSubscriptionService{
+		subscribeFn: func(_ context.Context, cmd services.SubscribeStockCommand) (services.StockSubscription, error) {
+			if cmd.UserID != "usr_1" || cmd.SKU != "SKU-1" || cmd.Trigger != domain.StockSubscriptionTriggerAboveSafetyStock || cmd.TTL != 30*24*time.Hour {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			return services.StockSubscription{
+				ID:        "bis_1",
+				UserID:    cmd.UserID,
+				SKU:       cmd.SKU,
+				Trigger:   cmd.Trigger,
+				Status:    domain.StockSubscriptionActive,
+				ExpiresAt: created.Add(cmd.TTL),
+				CreatedAt: created,
+				UpdatedAt: created,
+			}, nil
+		},
+	}
+	router := chi.NewRouter()
+	NewStockSubscriptionHandlers(WithStockSubscriptionService(svc)).Routes(router)
+
+	req := newIdentityRequest(http.MethodPost, "/stock-subscriptions", `{"sku":"SKU-1","trigger":"above_safety_stock","ttl_days":30}`, "usr_1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload stockSubscriptionPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.ID != "bis_1" || payload.Status != "active" || payload.ExpiresAt != "2026-03-31T09:00:00Z" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestStockSubscriptionHandlersListAndUnsubscribe(t *testing.T) {
+	notified := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	svc := &stubStockSubscriptionService{
+		listFn: func(_ context.Context, cmd services.ListStockSubscriptionsCommand) (domain.CursorPage[services.StockSubscription], error) {
+			if cmd.UserID != "usr_1" || len(cmd.Status) != 1 || cmd.Status[0] != domain.StockSubscriptionNotified || cmd.Pagination.PageSize != 5 {
+				t.Fatalf("unexpected list command %+v", cmd)
+			}
+			return domain.CursorPage[services.StockSubscription]{
+				Items: []services.StockSubscription{{
+					ID:         "bis_1",
+					ProductRef: "/products/p1",
+					Trigger:    domain.StockSubscriptionTriggerInStock,
+					Status:     domain.StockSubscriptionNotified,
+					NotifiedAt: &notified,
+				}},
+				NextPageToken: "next",
+			}, nil
+		},
+		unsubscribeFn: func(_ context.Context, cmd services.UnsubscribeStockCommand) error {
+			if cmd.UserID != "usr_1" {
+				t.Fatalf("unexpected unsubscribe command %+v", cmd)
+			}
+			if cmd.SubscriptionID != "bis_1" {
+				return fmt.Errorf("%w: %s", services.ErrStockSubscriptionNotFound, cmd.SubscriptionID)
+			}
+			return nil
+		},
+	}
+	router := chi.NewRouter()
+	NewStockSubscriptionHandlers(WithStockSubscriptionService(svc)).Routes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newIdentityRequest(http.MethodGet, "/stock-subscriptions?status=notified&pageSize=5", "", "usr_1"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var list stockSubscriptionListPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(list.Subscriptions) != 1 || list.Subscriptions[0].NotifiedAt != "2026-03-02T09:00:00Z" || list.NextPageToken != "next" {
+		t.Fatalf("unexpected list payload %+v", list)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newIdentityRequest(http.MethodDelete, "/stock-subscriptions/bis_1", "", "usr_1"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newIdentityRequest(http.MethodDelete, "/stock-subscriptions/bis_other", "", "usr_1"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestInternalHandlersExpireStockSubscriptions(t *testing.T) {
+	svc := &stubStockSubscriptionService{
+		expireFn: func(_ context.Context, cmd services.ExpireStockSubscriptionsCommand) (services.ExpireStockSubscriptionsResult, error) {
+			if cmd.Limit != 50 {
+				t.Fatalf("unexpected command %+v", cmd)
+			}
+			return services.ExpireStockSubscriptionsResult{Checked: 2, Expired: []string{"bis_1", "bis_2"}}, nil
+		},
+	}
+	handler := NewInternalHandlers(WithInternalStockSubscriptionService(svc))
+
+	rec := httptest.NewRecorder()
+	handler.expireStockSubscriptions(rec, newServiceRequest(http.MethodPost, "/maintenance/expire-stock-subscriptions", `{"limit":50}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload expireStockSubscriptionsPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Checked != 2 || payload.Expired != 2 || len(payload.ExpiredIDs) != 2 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	rec = httptest.NewRecorder()
+	NewInternalHandlers().expireStockSubscriptions(rec, newServiceRequest(http.MethodPost, "/maintenance/expire-stock-subscriptions", `{}`))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a service, got %d", rec.Code)
+	}
+}
+
+type stubStockSubscriptionService struct {
+	subscribeFn   func(context.Context, services.SubscribeStockCommand) (services.StockSubscription, error)
+	unsubscribeFn func(context.Context, services.UnsubscribeStockCommand) error
+	listFn        func(context.Context, services.ListStockSubscriptionsCommand) (domain.CursorPage[services.StockSubscription], error)
+	expireFn      func(context.Context, services.ExpireStockSubscriptionsCommand) (services.ExpireStockSubscriptionsResult, error)
+}
+
+func (s *stubStockSubscriptionService) PublishInventoryEvent(context.Context, services.InventoryStockEvent) error {
+	return nil
+}
+
+func (s *stubStockSubscriptionService) Subscribe(ctx context.Context, cmd services.SubscribeStockCommand) (services.StockSubscription, error) {
+	if s.subscribeFn != nil {
+		return s.subscribeFn(ctx, cmd)
+	}
+	return services.StockSubscription{}, nil
+}
+
+func (s *stubStockSubscriptionService) Unsubscribe(ctx context.Context, cmd services.UnsubscribeStockCommand) error {
+	if s.unsubscribeFn != nil {
+		return s.unsubscribeFn(ctx, cmd)
+	}
+	return nil
+}
+
+func (s *stubStockSubscriptionService) ListSubscriptions(ctx context.Context, cmd services.ListStockSubscriptionsCommand) (domain.CursorPage[services.StockSubscription], error) {
+	if s.listFn != nil {
+		return s.listFn(ctx, cmd)
+	}
+	return domain.CursorPage[services.StockSubscription]{}, nil
+}
+
+func (s *stubStockSubscriptionService) HandleStockEvent(context.Context, services.InventoryStockEvent) (services.BackInStockResult, error) {
+	return services.BackInStockResult{}, nil
+}
+
+func (s *stubStockSubscriptionService) ExpireSubscriptions(ctx context.Context, cmd services.ExpireStockSubscriptionsCommand) (services.ExpireStockSubscriptionsResult, error) {
+	if s.expireFn != nil {
+		return s.expireFn(ctx, cmd)
+	}
+	return services.ExpireStockSubscriptionsResult{}, nil
+}
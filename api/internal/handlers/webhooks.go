@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/platform/httpx"
+	"github.com/hanko-field/api/internal/repositories"
+	"github.com/hanko-field/api/internal/services"
+)
+
+const (
+	defaultWebhookBodyLimit = int64(1 << 20)
+	webhookStatusProcessed  = "processed"
+	webhookStatusDuplicate  = "duplicate"
+	aiWorkerStatusFailed    = "failed"
+	aiWorkerDefaultErrCode  = "worker_failed"
+)
+
+// WebhookHandlers exposes inbound webhook endpoints for payment providers, carriers, and AI workers.
+// Authenticity is enforced by the HMAC middleware mounted on the /webhooks group.
+type WebhookHandlers struct {
+	payments     services.PaymentService
+	shipments    services.ShipmentService
+	jobs         services.BackgroundJobDispatcher
+	maxBodyBytes int64
+}
+
+// WebhookOption customises construction of WebhookHandlers.
+type WebhookOption func(*WebhookHandlers)
+
+// WithWebhookPaymentService injects the payment service receiving PSP events.
+func WithWebhookPaymentService(svc services.PaymentService) WebhookOption {
+	return func(h *WebhookHandlers) {
+		h.payments = svc
+	}
+}
+
+// WithWebhookShipmentService injects the shipment service receiving carrier tracking events.
+func WithWebhookShipmentService(svc services.ShipmentService) WebhookOption {
+	return func(h *WebhookHandlers) {
+		h.shipments = svc
+	}
+}
+
+// WithWebhookJobDispatcher injects the dispatcher that finalises AI worker results.
+func WithWebhookJobDispatcher(dispatcher services.BackgroundJobDispatcher) WebhookOption {
+	return func(h *WebhookHandlers) {
+		h.jobs = dispatcher
+	}
+}
+
+// WithWebhookBodyLimit overrides the maximum accepted request body size in bytes.
+func WithWebhookBodyLimit(limit int64) WebhookOption {
+	return func(h *WebhookHandlers) {
+		if limit > 0 {
+			h.maxBodyBytes = limit
+		}
+	}
+}
+
+// NewWebhookHandlers constructs webhook handlers with the provided options.
+func NewWebhookHandlers(opts ...WebhookOption) *WebhookHandlers {
+	handler := &WebhookHandlers{
+		maxBodyBytes: defaultWebhookBodyLimit,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler
+}
+
+// Routes registers webhook endpoints on the provided router.
+func (h *WebhookHandlers) Routes(r chi.Router) {
+	if r == nil {
+		return
+	}
+	r.Post("/payments/stripe", h.handleStripeWebhook)
+	r.Post("/shipping/{carrier}", h.handleCarrierWebhook)
+	r.Post("/ai/worker", h.handleAIWorkerWebhook)
+}
+
+type webhookAckPayload struct {
+	Status string `json:"status"`
+}
+
+type carrierWebhookRequest struct {
+	OrderID        string         `json:"orderId"`
+	ShipmentID     string         `json:"shipmentId"`
+	TrackingNumber string         `json:"trackingNumber"`
+	Status         string         `json:"status"`
+	OccurredAt     string         `json:"occurredAt"`
+	Details        map[string]any `json:"details"`
+}
+
+type aiWorkerWebhookRequest struct {
+	JobID        string               `json:"jobId"`
+	SuggestionID string               `json:"suggestionId"`
+	DesignID     string               `json:"designId"`
+	Method       string               `json:"method"`
+	Status       string               `json:"status"`
+	Outputs      map[string]any       `json:"outputs"`
+	Metadata     map[string]any       `json:"metadata"`
+	Error        *aiWorkerErrorRecord `json:"error"`
+}
+
+type aiWorkerErrorRecord struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+func (h *WebhookHandlers) handleStripeWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.payments == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("payments_unavailable", "payment service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.payments.RecordWebhookEvent(ctx, services.PaymentWebhookCommand{
+		Provider: "stripe",
+		Payload:  body,
+		Headers:  flattenHeaders(r.Header),
+	})
+	h.acknowledge(ctx, w, err, http.StatusOK, "payment")
+}
+
+func (h *WebhookHandlers) handleCarrierWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.shipments == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("shipments_unavailable", "shipment service is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+
+	carrier := strings.ToLower(strings.TrimSpace(chi.URLParam(r, "carrier")))
+	if carrier == "" {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_carrier", "carrier is required", http.StatusBadRequest))
+		return
+	}
+
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var req carrierWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_payload", "request body must be valid JSON", http.StatusBadRequest))
+		return
+	}
+
+	cmd, err := buildShipmentEventCommand(carrier, req)
+	if err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_payload", err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = h.shipments.RecordCarrierEvent(ctx, cmd)
+	h.acknowledge(ctx, w, err, http.StatusOK, "shipment")
+}
+
+func (h *WebhookHandlers) handleAIWorkerWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.jobs == nil {
+		httpx.WriteError(ctx, w, httpx.NewError("ai_jobs_unavailable", "ai job dispatcher is unavailable", http.StatusServiceUnavailable))
+		return
+	}
+
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var req aiWorkerWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_payload", "request body must be valid JSON", http.StatusBadRequest))
+		return
+	}
+
+	jobID := strings.TrimSpace(req.JobID)
+	if jobID == "" {
+		httpx.WriteError(ctx, w, httpx.NewError("invalid_payload", "jobId is required", http.StatusBadRequest))
+		return
+	}
+
+	_, err := h.jobs.CompleteAISuggestion(ctx, buildCompleteAISuggestionCommand(jobID, req))
+	h.acknowledge(ctx, w, err, http.StatusAccepted, "ai_job")
+}
+
+func (h *WebhookHandlers) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Body == nil {
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_payload", "request body is required", http.StatusBadRequest))
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			httpx.WriteError(r.Context(), w, httpx.NewError("payload_too_large", "request body exceeds limit", http.StatusRequestEntityTooLarge))
+			return nil, false
+		}
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_payload", "failed to read request body", http.StatusBadRequest))
+		return nil, false
+	}
+	if len(body) == 0 {
+		httpx.WriteError(r.Context(), w, httpx.NewError("invalid_payload", "request body is required", http.StatusBadRequest))
+		return nil, false
+	}
+	return body, true
+}
+
+// acknowledge writes the webhook response. Duplicate deliveries are acknowledged with a 2xx so providers
+// stop retrying, permanent failures return 4xx, and only transient failures surface as 5xx.
+func (h *WebhookHandlers) acknowledge(ctx context.Context, w http.ResponseWriter, err error, successStatus int, resource string) {
+	if err == nil {
+		writeJSON(w, successStatus, webhookAckPayload{Status: webhookStatusProcessed})
+		return
+	}
+	if isDuplicateWebhookError(err) {
+		writeJSON(w, successStatus, webhookAckPayload{Status: webhookStatusDuplicate})
+		return
+	}
+	writeWebhookError(ctx, w, err, resource)
+}
+
+func isDuplicateWebhookError(err error) bool {
+	if errors.Is(err, services.ErrWebhookDuplicate) || errors.Is(err, services.ErrAIJobAlreadyCompleted) {
+		return true
+	}
+	var repoErr repositories.RepositoryError
+	if errors.As(err, &repoErr) && repoErr.IsConflict() {
+		return true
+	}
+	return false
+}
+
+func writeWebhookError(ctx context.Context, w http.ResponseWriter, err error, resource string) {
+	writeServiceError(ctx, w, err, errorScope{resource: resource, service: "webhook"})
+}
+
+func buildShipmentEventCommand(carrier string, req carrierWebhookRequest) (services.ShipmentEventCommand, error) {
+	status := strings.TrimSpace(req.Status)
+	if status == "" {
+		return services.ShipmentEventCommand{}, errors.New("status is required")
+	}
+	shipmentID := strings.TrimSpace(req.ShipmentID)
+	trackingNumber := strings.TrimSpace(req.TrackingNumber)
+	if shipmentID == "" && trackingNumber == "" {
+		return services.ShipmentEventCommand{}, errors.New("shipmentId or trackingNumber is required")
+	}
+
+	occurredAt := time.Now().UTC()
+	if raw := strings.TrimSpace(req.OccurredAt); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return services.ShipmentEventCommand{}, errors.New("occurredAt must be RFC3339")
+		}
+		occurredAt = parsed.UTC()
+	}
+
+	details := make(map[string]any, len(req.Details)+1)
+	for key, value := range req.Details {
+		details[key] = value
+	}
+	if trackingNumber != "" {
+		details["trackingNumber"] = trackingNumber
+	}
+
+	return services.ShipmentEventCommand{
+		OrderID:    strings.TrimSpace(req.OrderID),
+		ShipmentID: shipmentID,
+		Carrier:    carrier,
+		Event: services.ShipmentEvent{
+			Status:     strings.ToLower(status),
+			OccurredAt: occurredAt,
+			Details:    details,
+		},
+	}, nil
+}
+
+func buildCompleteAISuggestionCommand(jobID string, req aiWorkerWebhookRequest) services.CompleteAISuggestionCommand {
+	cmd := services.CompleteAISuggestionCommand{
+		JobID:    jobID,
+		Outputs:  req.Outputs,
+		Metadata: req.Metadata,
+	}
+
+	failed := strings.EqualFold(strings.TrimSpace(req.Status), aiWorkerStatusFailed)
+	if req.Error != nil || failed {
+		jobErr := &domain.AIJobError{Code: aiWorkerDefaultErrCode}
+		if req.Error != nil {
+			if code := strings.TrimSpace(req.Error.Code); code != "" {
+				jobErr.Code = code
+			}
+			jobErr.Message = strings.TrimSpace(req.Error.Message)
+			jobErr.Retryable = req.Error.Retryable
+		}
+		cmd.Error = jobErr
+		return cmd
+	}
+
+	cmd.Suggestion = services.AISuggestion{
+		ID:       strings.TrimSpace(req.SuggestionID),
+		DesignID: strings.TrimSpace(req.DesignID),
+		Method:   strings.TrimSpace(req.Method),
+	}
+	return cmd
+}
+
+func flattenHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(header))
+	for key, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		result[http.CanonicalHeaderKey(key)] = values[0]
+	}
+	return result
+}
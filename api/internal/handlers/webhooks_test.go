@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/services"
+)
+
+func TestWebhookHandlersStripeDispatchesEvent(t *testing.T) {
+	payments := &stubPaymentService{}
+	handler := NewWebhookHandlers(WithWebhookPaymentService(payments))
+	router := chi.NewRouter()
+	handler.Routes(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/payments/stripe", strings.NewReader(`{"id":"evt_1","type":"payment_intent.succeeded"}`))
+	req.Header.Set("Stripe-Signature", "t=1,v1=abc")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if payments.webhookCmd.Provider != "stripe" {
+		t.Fatalf("expected stripe provider, got %q", payments.webhookCmd.Provider)
+	}
+	if string(payments.webhookCmd.Payload) != `{"id":"evt_1","type":"payment_intent.succeeded"}` {
+		t.Fatalf("expected raw payload forwarded, got %s", payments.webhookCmd.Payload)
+	}
+	if payments.webhookCmd.Headers["Stripe-Signature"] != "t=1,v1=abc" {
+		t.Fatalf("expected signature header forwarded, got %+v", payments.webhookCmd.Headers)
+	}
+	assertWebhookStatus(t, rec, webhookStatusProcessed)
+}
+
+func TestWebhookHandlersStripeErrorMapping(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		ack    string
+	}{
+		{name: "duplicate sentinel", err: services.ErrWebhookDuplicate, status: http.StatusOK, ack: webhookStatusDuplicate},
+		{name: "repository conflict", err: newRepositoryError(false, true, false), status: http.StatusOK, ack: webhookStatusDuplicate},
+		{name: "invalid payload", err: services.ErrWebhookInvalidPayload, status: http.StatusBadRequest},
+		{name: "not found", err: newRepositoryError(true, false, false), status: http.StatusNotFound},
+		{name: "unavailable", err: newRepositoryError(false, false, true), status: http.StatusServiceUnavailable},
+		{name: "unexpected", err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewWebhookHandlers(WithWebhookPaymentService(&stubPaymentService{webhookErr: tc.err}))
+			req := httptest.NewRequest(http.MethodPost, "/payments/stripe", strings.NewReader(`{"id":"evt_1"}`))
+			rec := httptest.NewRecorder()
+			handler.handleStripeWebhook(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.ack != "" {
+				assertWebhookStatus(t, rec, tc.ack)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlersStripeUnavailableWithoutService(t *testing.T) {
+	handler := NewWebhookHandlers()
+	req := httptest.NewRequest(http.MethodPost, "/payments/stripe", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	handler.handleStripeWebhook(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandlersStripeRejectsOversizedBody(t *testing.T) {
+	handler := NewWebhookHandlers(WithWebhookPaymentService(&stubPaymentService{}), WithWebhookBodyLimit(8))
+	req := httptest.NewRequest(http.MethodPost, "/payments/stripe", strings.NewReader(`{"id":"evt_too_long"}`))
+	rec := httptest.NewRecorder()
+	handler.handleStripeWebhook(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandlersCarrierDispatchesEvent(t *testing.T) {
+	shipments := &stubShipmentService{}
+	handler := NewWebhookHandlers(WithWebhookShipmentService(shipments))
+	router := chi.NewRouter()
+	handler.Routes(router)
+
+	body := `{"orderId":"ord_1","shipmentId":"shp_1","trackingNumber":"YT123","status":"Delivered","occurredAt":"2025-05-01T10:00:00+09:00","details":{"location":"Tokyo"}}`
+	req := httptest.NewRequest(http.MethodPost, "/shipping/Yamato", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	cmd := shipments.eventCmd
+	if cmd.Carrier != "yamato" || cmd.OrderID != "ord_1" || cmd.ShipmentID != "shp_1" {
+		t.Fatalf("unexpected command %+v", cmd)
+	}
+	if cmd.Event.Status != "delivered" {
+		t.Fatalf("expected normalised status, got %q", cmd.Event.Status)
+	}
+	if got := cmd.Event.OccurredAt.Format("2006-01-02T15:04:05Z07:00"); got != "2025-05-01T01:00:00Z" {
+		t.Fatalf("expected UTC occurredAt, got %s", got)
+	}
+	if cmd.Event.Details["trackingNumber"] != "YT123" || cmd.Event.Details["location"] != "Tokyo" {
+		t.Fatalf("unexpected details %+v", cmd.Event.Details)
+	}
+}
+
+func TestWebhookHandlersCarrierValidatesPayload(t *testing.T) {
+	shipments := &stubShipmentService{}
+	handler := NewWebhookHandlers(WithWebhookShipmentService(shipments))
+	router := chi.NewRouter()
+	handler.Routes(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/shipping/dhl", strings.NewReader(`{"status":"in_transit"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	if shipments.calls != 0 {
+		t.Fatalf("expected service not to be called")
+	}
+}
+
+func TestWebhookHandlersAIWorkerCompletesSuggestion(t *testing.T) {
+	jobs := &stubJobDispatcher{}
+	handler := NewWebhookHandlers(WithWebhookJobDispatcher(jobs))
+
+	body := `{"jobId":"aj_1","suggestionId":"as_1","designId":"dsn_1","method":"balance","status":"succeeded","outputs":{"score":0.9}}`
+	req := httptest.NewRequest(http.MethodPost, "/ai/worker", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.handleAIWorkerWebhook(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	cmd := jobs.completeCmd
+	if cmd.JobID != "aj_1" || cmd.Suggestion.ID != "as_1" || cmd.Suggestion.DesignID != "dsn_1" {
+		t.Fatalf("unexpected command %+v", cmd)
+	}
+	if cmd.Error != nil {
+		t.Fatalf("expected no error on success")
+	}
+	if cmd.Outputs["score"] != 0.9 {
+		t.Fatalf("expected outputs forwarded, got %+v", cmd.Outputs)
+	}
+}
+
+func TestWebhookHandlersAIWorkerFailure(t *testing.T) {
+	jobs := &stubJobDispatcher{}
+	handler := NewWebhookHandlers(WithWebhookJobDispatcher(jobs))
+
+	req := httptest.NewRequest(http.MethodPost, "/ai/worker", strings.NewReader(`{"jobId":"aj_1","status":"failed"}`))
+	rec := httptest.NewRecorder()
+	handler.handleAIWorkerWebhook(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+	if jobs.completeCmd.Error == nil || jobs.completeCmd.Error.Code != aiWorkerDefaultErrCode {
+		t.Fatalf("expected default worker error, got %+v", jobs.completeCmd.Error)
+	}
+}
+
+func TestWebhookHandlersAIWorkerDuplicateAndMissingJob(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "duplicate", err: services.ErrAIJobAlreadyCompleted, status: http.StatusAccepted},
+		{name: "missing job", err: services.ErrAIJobNotFound, status: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewWebhookHandlers(WithWebhookJobDispatcher(&stubJobDispatcher{completeErr: tc.err}))
+			req := httptest.NewRequest(http.MethodPost, "/ai/worker", strings.NewReader(`{"jobId":"aj_1"}`))
+			rec := httptest.NewRecorder()
+			handler.handleAIWorkerWebhook(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d", tc.status, rec.Code)
+			}
+		})
+	}
+}
+
+func assertWebhookStatus(t *testing.T, rec *httptest.ResponseRecorder, expected string) {
+	t.Helper()
+	var payload webhookAckPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Status != expected {
+		t.Fatalf("expected status %q, got %q", expected, payload.Status)
+	}
+}
+
+type stubPaymentService struct {
+	webhookCmd services.PaymentWebhookCommand
+	webhookErr error
+}
+
+func (s *stubPaymentService) RecordWebhookEvent(_ context.Context, cmd services.PaymentWebhookCommand) error {
+	s.webhookCmd = cmd
+	return s.webhookErr
+}
+
+func (s *stubPaymentService) ManualCapture(context.Context, services.PaymentManualCaptureCommand) (services.Payment, error) {
+	return services.Payment{}, errors.New("not implemented")
+}
+
+func (s *stubPaymentService) ManualRefund(context.Context, services.PaymentManualRefundCommand) (services.Payment, error) {
+	return services.Payment{}, errors.New("not implemented")
+}
+
+func (s *stubPaymentService) ListPayments(context.Context, string) ([]services.Payment, error) {
+	return nil, errors.New("not implemented")
+}
+
+type stubShipmentService struct {
+	eventCmd services.ShipmentEventCommand
+	calls    int
+	err      error
+}
+
+func (s *stubShipmentService) CreateShipment(context.Context, services.CreateShipmentCommand) (services.Shipment, error) {
+	return services.Shipment{}, errors.New("not implemented")
+}
+
+func (s *stubShipmentService) UpdateShipmentStatus(context.Context, services.UpdateShipmentCommand) (services.Shipment, error) {
+	return services.Shipment{}, errors.New("not implemented")
+}
+
+func (s *stubShipmentService) ListShipments(context.Context, string) ([]services.Shipment, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubShipmentService) RecordCarrierEvent(_ context.Context, cmd services.ShipmentEventCommand) error {
+	s.calls++
+	s.eventCmd = cmd
+	return s.err
+}
+
+type stubJobDispatcher struct {
+	completeCmd services.CompleteAISuggestionCommand
+	completeErr error
+}
+
+func (s *stubJobDispatcher) QueueAISuggestion(context.Context, services.QueueAISuggestionCommand) (services.QueueAISuggestionResult, error) {
+	return services.QueueAISuggestionResult{}, errors.New("not implemented")
+}
+
+func (s *stubJobDispatcher) GetAIJob(context.Context, string) (domain.AIJob, error) {
+	return domain.AIJob{}, errors.New("not implemented")
+}
+
+func (s *stubJobDispatcher) CompleteAISuggestion(_ context.Context, cmd services.CompleteAISuggestionCommand) (services.CompleteAISuggestionResult, error) {
+	s.completeCmd = cmd
+	return services.CompleteAISuggestionResult{}, s.completeErr
+}
+
+func (s *stubJobDispatcher) GetSuggestion(context.Context, string, string) (services.AISuggestion, error) {
+	return services.AISuggestion{}, errors.New("not implemented")
+}
+
+func (s *stubJobDispatcher) EnqueueRegistrabilityCheck(context.Context, services.RegistrabilityJobPayload) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (s *stubJobDispatcher) EnqueueStockCleanup(context.Context, services.StockCleanupPayload) error {
+	return errors.New("not implemented")
+}
@@ -0,0 +1,354 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderFake is the registration key for the offline FakeProvider.
+const ProviderFake = "fake"
+
+// FakeOutcome selects how the FakeProvider resolves confirmations.
+type FakeOutcome string
+
+const (
+	// FakeOutcomeSucceed confirms and captures payments immediately.
+	FakeOutcomeSucceed FakeOutcome = "succeed"
+	// FakeOutcomeFail declines payments on confirmation.
+	FakeOutcomeFail FakeOutcome = "fail"
+	// FakeOutcomeRequiresAction leaves payments pending customer action (e.g. 3-D Secure).
+	FakeOutcomeRequiresAction FakeOutcome = "requires_action"
+)
+
+var (
+	// ErrFakePaymentDeclined is returned when the FakeProvider is configured to fail payments.
+	ErrFakePaymentDeclined = errors.New("payments: fake payment declined")
+	// ErrFakeIntentNotFound is returned when an operation references an unknown fake intent.
+	ErrFakeIntentNotFound = errors.New("payments: fake intent not found")
+	// ErrFakeInvalidState is returned when the fake intent state forbids the operation.
+	ErrFakeInvalidState = errors.New("payments: fake intent invalid state")
+)
+
+// FakeProviderConfig configures the FakeProvider.
+type FakeProviderConfig struct {
+	Outcome FakeOutcome
+	Delay   time.Duration
+	Clock   func() time.Time
+}
+
+// FakeProvider is a deterministic in-memory Provider for local development and CI. Identifiers are
+// sequential, and every call honours the configured outcome and artificial delay.
+type FakeProvider struct {
+	mu      sync.Mutex
+	outcome FakeOutcome
+	delay   time.Duration
+	clock   func() time.Time
+	seq     int
+	intents map[string]*fakeIntent
+}
+
+type fakeIntent struct {
+	id         string
+	status     Status
+	action     bool
+	amount     int64
+	captured   int64
+	refunded   int64
+	currency   string
+	metadata   map[string]string
+	capturedAt *time.Time
+	refundedAt *time.Time
+}
+
+// NewFakeProvider constructs a FakeProvider. The zero config succeeds every payment without delay.
+func NewFakeProvider(cfg FakeProviderConfig) *FakeProvider {
+	outcome := cfg.Outcome
+	if outcome == "" {
+		outcome = FakeOutcomeSucceed
+	}
+	clock := cfg.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	return &FakeProvider{
+		outcome: outcome,
+		delay:   cfg.Delay,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		intents: make(map[string]*fakeIntent),
+	}
+}
+
+// SetOutcome changes the outcome applied to subsequent confirmations.
+func (p *FakeProvider) SetOutcome(outcome FakeOutcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.outcome = outcome
+}
+
+// SetDelay changes the artificial latency applied to every call.
+func (p *FakeProvider) SetDelay(delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delay = delay
+}
+
+// CreateCheckoutSession registers a pending fake intent.
+func (p *FakeProvider) CreateCheckoutSession(ctx context.Context, req CheckoutSessionRequest) (CheckoutSession, error) {
+	if err := p.wait(ctx); err != nil {
+		return CheckoutSession{}, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		for _, item := range req.Items {
+			amount += item.Amount * max64(item.Quantity, 1)
+		}
+	}
+	if amount <= 0 {
+		return CheckoutSession{}, errors.New("payments: fake checkout amount must be positive")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.seq++
+	intentID := fmt.Sprintf("fake_pi_%04d", p.seq)
+	sessionID := fmt.Sprintf("fake_cs_%04d", p.seq)
+	metadata := make(map[string]string, len(req.Metadata))
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+	p.intents[intentID] = &fakeIntent{
+		id:       intentID,
+		status:   StatusPending,
+		amount:   amount,
+		currency: strings.ToUpper(strings.TrimSpace(req.Currency)),
+		metadata: metadata,
+	}
+
+	return CheckoutSession{
+		ID:           sessionID,
+		Provider:     ProviderFake,
+		ClientSecret: intentID + "_secret",
+		RedirectURL:  "https://fake-psp.invalid/checkout/" + sessionID,
+		IntentID:     intentID,
+		ExpiresAt:    p.clock().Add(30 * time.Minute),
+		Raw: map[string]any{
+			"id":             sessionID,
+			"payment_intent": intentID,
+		},
+	}, nil
+}
+
+// Confirm resolves the intent according to the configured outcome. Intents awaiting customer action
+// complete on the second confirmation, mirroring a finished 3-D Secure challenge.
+func (p *FakeProvider) Confirm(ctx context.Context, req ConfirmRequest) (PaymentDetails, error) {
+	if err := p.wait(ctx); err != nil {
+		return PaymentDetails{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, err := p.lookup(req.IntentID)
+	if err != nil {
+		return PaymentDetails{}, err
+	}
+	if intent.status != StatusPending {
+		return intent.details(), nil
+	}
+
+	switch p.outcome {
+	case FakeOutcomeFail:
+		intent.status = StatusFailed
+		return intent.details(), fmt.Errorf("%w: intent %s", ErrFakePaymentDeclined, intent.id)
+	case FakeOutcomeRequiresAction:
+		if !intent.action {
+			intent.action = true
+			return intent.details(), nil
+		}
+	}
+
+	intent.action = false
+	intent.captureAmount(intent.amount, p.clock())
+	return intent.details(), nil
+}
+
+// Capture captures a pending intent, optionally for a partial amount.
+func (p *FakeProvider) Capture(ctx context.Context, req CaptureRequest) (PaymentDetails, error) {
+	if err := p.wait(ctx); err != nil {
+		return PaymentDetails{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, err := p.lookup(req.IntentID)
+	if err != nil {
+		return PaymentDetails{}, err
+	}
+	if intent.status == StatusSucceeded && intent.captured > 0 {
+		return intent.details(), nil
+	}
+	if intent.status != StatusPending || intent.action {
+		return intent.details(), fmt.Errorf("%w: cannot capture intent %s in status %s", ErrFakeInvalidState, intent.id, intent.status)
+	}
+
+	amount := intent.amount
+	if req.Amount != nil {
+		if *req.Amount <= 0 || *req.Amount > intent.amount {
+			return intent.details(), fmt.Errorf("%w: capture amount %d exceeds %d", ErrFakeInvalidState, *req.Amount, intent.amount)
+		}
+		amount = *req.Amount
+	}
+	intent.captureAmount(amount, p.clock())
+	return intent.details(), nil
+}
+
+// Refund refunds a captured intent, fully or partially.
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (PaymentDetails, error) {
+	if err := p.wait(ctx); err != nil {
+		return PaymentDetails{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, err := p.lookup(req.IntentID)
+	if err != nil {
+		return PaymentDetails{}, err
+	}
+	if intent.captured == 0 {
+		return intent.details(), fmt.Errorf("%w: intent %s has not been captured", ErrFakeInvalidState, intent.id)
+	}
+
+	remaining := intent.captured - intent.refunded
+	amount := remaining
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if amount <= 0 || amount > remaining {
+		return intent.details(), fmt.Errorf("%w: refund amount %d exceeds refundable %d", ErrFakeInvalidState, amount, remaining)
+	}
+
+	now := p.clock()
+	intent.refunded += amount
+	intent.refundedAt = &now
+	if intent.refunded >= intent.captured {
+		intent.status = StatusRefunded
+	}
+	return intent.details(), nil
+}
+
+// LookupPayment returns the current fake intent state.
+func (p *FakeProvider) LookupPayment(ctx context.Context, req LookupRequest) (PaymentDetails, error) {
+	if err := p.wait(ctx); err != nil {
+		return PaymentDetails{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, err := p.lookup(req.IntentID)
+	if err != nil {
+		return PaymentDetails{}, err
+	}
+	return intent.details(), nil
+}
+
+// SubmitDisputeEvidence accepts any well-formed submission and reports the dispute as under review.
+func (p *FakeProvider) SubmitDisputeEvidence(ctx context.Context, req DisputeEvidenceRequest) (DisputeDetails, error) {
+	if err := p.wait(ctx); err != nil {
+		return DisputeDetails{}, err
+	}
+	disputeID := strings.TrimSpace(req.DisputeID)
+	if disputeID == "" {
+		return DisputeDetails{}, fmt.Errorf("%w: dispute id is required", ErrDisputeEvidenceInvalid)
+	}
+	return DisputeDetails{
+		Provider:  ProviderFake,
+		DisputeID: disputeID,
+		Status:    "under_review",
+		Raw: map[string]any{
+			"id":          disputeID,
+			"files":       len(req.Files),
+			"explanation": req.Explanation,
+		},
+	}, nil
+}
+
+func (p *FakeProvider) wait(ctx context.Context) error {
+	p.mu.Lock()
+	delay := p.delay
+	p.mu.Unlock()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *FakeProvider) lookup(intentID string) (*fakeIntent, error) {
+	intent, ok := p.intents[strings.TrimSpace(intentID)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFakeIntentNotFound, intentID)
+	}
+	return intent, nil
+}
+
+func (i *fakeIntent) captureAmount(amount int64, at time.Time) {
+	i.status = StatusSucceeded
+	i.captured = amount
+	i.capturedAt = &at
+}
+
+func (i *fakeIntent) details() PaymentDetails {
+	raw := map[string]any{
+		"id":              i.id,
+		"amount_captured": i.captured,
+		"amount_refunded": i.refunded,
+	}
+	if i.action {
+		raw["next_action"] = "redirect_to_url"
+	}
+	if len(i.metadata) > 0 {
+		metadata := make(map[string]any, len(i.metadata))
+		for k, v := range i.metadata {
+			metadata[k] = v
+		}
+		raw["metadata"] = metadata
+	}
+	return PaymentDetails{
+		Provider:       ProviderFake,
+		IntentID:       i.id,
+		Status:         i.status,
+		Amount:         i.amount,
+		Currency:       i.currency,
+		Captured:       i.captured > 0,
+		CapturedAt:     copyTimePointer(i.capturedAt),
+		RefundedAt:     copyTimePointer(i.refundedAt),
+		Raw:            raw,
+		AmountRefunded: i.refunded,
+	}
+}
+
+func copyTimePointer(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := *t
+	return &value
+}
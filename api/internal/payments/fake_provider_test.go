@@ -0,0 +1,115 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeProvider_SucceedLifecycle(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	provider := NewFakeProvider(FakeProviderConfig{Clock: func() time.Time { return now }})
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{
+		Currency: "jpy",
+		Items:    []CheckoutLineItem{{Name: "Seal", Amount: 1500, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.IntentID != "fake_pi_0001" || session.ID != "fake_cs_0001" {
+		t.Fatalf("expected deterministic identifiers, got %+v", session)
+	}
+
+	details, err := provider.Confirm(ctx, ConfirmRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if details.Status != StatusSucceeded || !details.Captured || details.Amount != 3000 || details.Currency != "JPY" {
+		t.Fatalf("unexpected confirm details: %+v", details)
+	}
+
+	partial := int64(1000)
+	details, err = provider.Refund(ctx, RefundRequest{IntentID: session.IntentID, Amount: &partial})
+	if err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+	if details.Status != StatusSucceeded || details.RefundedAt == nil {
+		t.Fatalf("expected partially refunded intent to stay succeeded, got %+v", details)
+	}
+
+	details, err = provider.Refund(ctx, RefundRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("final refund: %v", err)
+	}
+	if details.Status != StatusRefunded {
+		t.Fatalf("expected refunded status, got %s", details.Status)
+	}
+
+	if _, err := provider.Refund(ctx, RefundRequest{IntentID: session.IntentID}); !errors.Is(err, ErrFakeInvalidState) {
+		t.Fatalf("expected invalid state for over-refund, got %v", err)
+	}
+}
+
+func TestFakeProvider_FailAndRequiresAction(t *testing.T) {
+	provider := NewFakeProvider(FakeProviderConfig{Outcome: FakeOutcomeFail})
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Currency: "USD", Amount: 500})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	details, err := provider.Confirm(ctx, ConfirmRequest{IntentID: session.IntentID})
+	if !errors.Is(err, ErrFakePaymentDeclined) {
+		t.Fatalf("expected decline, got %v", err)
+	}
+	if details.Status != StatusFailed {
+		t.Fatalf("expected failed status, got %s", details.Status)
+	}
+
+	provider.SetOutcome(FakeOutcomeRequiresAction)
+	session, err = provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Currency: "USD", Amount: 500})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	details, err = provider.Confirm(ctx, ConfirmRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if details.Status != StatusPending || details.Raw["next_action"] == nil {
+		t.Fatalf("expected pending intent awaiting action, got %+v", details)
+	}
+	if _, err := provider.Capture(ctx, CaptureRequest{IntentID: session.IntentID}); !errors.Is(err, ErrFakeInvalidState) {
+		t.Fatalf("expected capture to be rejected while action is required, got %v", err)
+	}
+
+	details, err = provider.Confirm(ctx, ConfirmRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("second confirm: %v", err)
+	}
+	if details.Status != StatusSucceeded {
+		t.Fatalf("expected action completion to succeed, got %s", details.Status)
+	}
+
+	if _, err := provider.LookupPayment(ctx, LookupRequest{IntentID: "missing"}); !errors.Is(err, ErrFakeIntentNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestFakeProvider_DelayHonoursContext(t *testing.T) {
+	provider := NewFakeProvider(FakeProviderConfig{Delay: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Currency: "JPY", Amount: 100})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	provider.SetDelay(time.Millisecond)
+	if _, err := provider.CreateCheckoutSession(context.Background(), CheckoutSessionRequest{Currency: "JPY", Amount: 100}); err != nil {
+		t.Fatalf("expected short delay to succeed, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package payments
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -38,6 +39,11 @@ func (f *fakeProvider) LookupPayment(ctx context.Context, req LookupRequest) (Pa
 	return f.payment, f.err
 }
 
+func (f *fakeProvider) SubmitDisputeEvidence(ctx context.Context, req DisputeEvidenceRequest) (DisputeDetails, error) {
+	f.lastOp = "dispute"
+	return DisputeDetails{DisputeID: req.DisputeID}, f.err
+}
+
 func TestManagerCreateCheckoutSessionUsesPreferredProvider(t *testing.T) {
 	ctx := context.Background()
 	stripe := &fakeProvider{session: CheckoutSession{ID: "sess_stripe"}}
@@ -137,3 +143,101 @@ func TestNewManagerValidatesProviders(t *testing.T) {
 		t.Fatalf("expected error when providers empty")
 	}
 }
+
+func TestManagerRoutingRules(t *testing.T) {
+	providers := map[string]Provider{"stripe": &fakeProvider{}, "paypal": &fakeProvider{}, "fake": &fakeProvider{}}
+	mgr, err := NewManager(providers,
+		WithCurrencyRoutes(map[string]string{"USD": "paypal"}),
+		WithRoutingRules(
+			RoutingRule{Name: "konbini", Provider: "stripe", PaymentMethods: []PaymentMethod{PaymentMethodKonbini}},
+			RoutingRule{Name: "high-value-jp", Provider: "paypal", Fallbacks: []string{"stripe"}, Currencies: []string{"jpy"}, MinAmount: 100000, Countries: []string{"jp"}},
+			RoutingRule{Name: "staff", Provider: "fake", Segments: []string{"Staff"}},
+		),
+	)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		ctx      PaymentContext
+		provider string
+		source   string
+		rule     string
+	}{
+		{name: "preferred wins", ctx: PaymentContext{PreferredProvider: "fake", PaymentMethod: PaymentMethodKonbini}, provider: "fake", source: RouteSourcePreferred},
+		{name: "method rule", ctx: PaymentContext{Currency: "JPY", PaymentMethod: "KONBINI"}, provider: "stripe", source: RouteSourceRule, rule: "konbini"},
+		{name: "amount band", ctx: PaymentContext{Currency: "JPY", Amount: 150000, BillingCountry: "JP"}, provider: "paypal", source: RouteSourceRule, rule: "high-value-jp"},
+		{name: "below band", ctx: PaymentContext{Currency: "JPY", Amount: 99999, BillingCountry: "JP"}, provider: "stripe", source: RouteSourceDefault},
+		{name: "unknown amount", ctx: PaymentContext{Currency: "JPY", BillingCountry: "JP"}, provider: "stripe", source: RouteSourceDefault},
+		{name: "segment", ctx: PaymentContext{UserSegment: "staff"}, provider: "fake", source: RouteSourceRule, rule: "staff"},
+		{name: "currency route", ctx: PaymentContext{Currency: "USD"}, provider: "paypal", source: RouteSourceCurrency},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision, err := mgr.Route(tc.ctx)
+			if err != nil {
+				t.Fatalf("route: %v", err)
+			}
+			if decision.Provider != tc.provider || decision.Source != tc.source || decision.Rule != tc.rule {
+				t.Fatalf("unexpected decision %+v", decision)
+			}
+		})
+	}
+
+	decision, _ := mgr.Route(PaymentContext{Currency: "JPY", Amount: 150000, BillingCountry: "JP"})
+	if got := decision.Candidates(); len(got) != 2 || got[1] != "stripe" {
+		t.Fatalf("expected stripe fallback, got %v", got)
+	}
+}
+
+func TestManagerCheckoutFallsBackOnRetryableErrors(t *testing.T) {
+	ctx := context.Background()
+	primary := &fakeProvider{err: fmt.Errorf("paypal: create order: %w", &PayPalAPIError{StatusCode: 503})}
+	secondary := &fakeProvider{session: CheckoutSession{ID: "sess_fake"}}
+	var logged []string
+	mgr, err := NewManager(
+		map[string]Provider{"stripe": &fakeProvider{}, "paypal": primary, "fake": secondary},
+		WithRoutingRules(RoutingRule{Name: "usd", Provider: "paypal", Fallbacks: []string{"fake"}, Currencies: []string{"USD"}}),
+		WithManagerLogger(func(_ context.Context, event string, _ map[string]any) { logged = append(logged, event) }),
+	)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	session, err := mgr.CreateCheckoutSession(ctx, PaymentContext{Currency: "USD"}, CheckoutSessionRequest{Currency: "USD"})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.Provider != "fake" || primary.lastOp != "create" || len(logged) != 1 {
+		t.Fatalf("expected fallback to fake, got %+v logged=%v", session, logged)
+	}
+
+	primary.err = fmt.Errorf("%w: paypal cannot charge saved payment methods", ErrUnsupportedPaymentMethod)
+	secondary.lastOp = ""
+	if _, err := mgr.CreateCheckoutSession(ctx, PaymentContext{Currency: "USD"}, CheckoutSessionRequest{Currency: "USD"}); !errors.Is(err, ErrUnsupportedPaymentMethod) {
+		t.Fatalf("expected non-retryable error to surface, got %v", err)
+	}
+	if secondary.lastOp != "" {
+		t.Fatalf("non-retryable errors must not fall back")
+	}
+
+	primary.err = ErrProviderUnavailable
+	secondary.err = ErrProviderUnavailable
+	if _, err := mgr.CreateCheckoutSession(ctx, PaymentContext{Currency: "USD"}, CheckoutSessionRequest{Currency: "USD"}); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("expected last provider error when all fail, got %v", err)
+	}
+}
+
+func TestNewManagerValidatesRoutingRules(t *testing.T) {
+	providers := map[string]Provider{"stripe": &fakeProvider{}}
+	if _, err := NewManager(providers, WithRoutingRules(RoutingRule{Name: "missing", Provider: "adyen"})); err == nil {
+		t.Fatalf("expected unknown rule provider to be rejected")
+	}
+	if _, err := NewManager(providers, WithRoutingRules(RoutingRule{Provider: "stripe", Fallbacks: []string{"paypal"}})); err == nil {
+		t.Fatalf("expected unknown fallback to be rejected")
+	}
+	if _, err := NewManager(providers, WithRoutingRules(RoutingRule{Provider: "stripe", MinAmount: 500, MaxAmount: 100})); err == nil {
+		t.Fatalf("expected empty amount band to be rejected")
+	}
+}
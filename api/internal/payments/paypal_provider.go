@@ -0,0 +1,734 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// PayPalLiveBaseURL is the production REST endpoint for PayPal.
+	PayPalLiveBaseURL = "https://api-m.paypal.com"
+	// PayPalSandboxBaseURL is the sandbox REST endpoint for PayPal.
+	PayPalSandboxBaseURL = "https://api-m.sandbox.paypal.com"
+
+	paypalTokenSkew        = time.Minute
+	paypalDefaultTimeout   = 15 * time.Second
+	paypalMaxResponseBytes = 1 << 20
+	paypalSessionLifetime  = 3 * time.Hour
+)
+
+// zeroDecimalCurrencies lists currencies PayPal expresses without minor units.
+var zeroDecimalCurrencies = map[string]struct{}{
+	"JPY": {},
+	"HUF": {},
+	"TWD": {},
+}
+
+// PayPalLogger defines the logging contract for PayPal provider operations.
+type PayPalLogger func(ctx context.Context, event string, fields map[string]any)
+
+// PayPalProviderConfig configures the PayPalProvider.
+type PayPalProviderConfig struct {
+	ClientID   string
+	Secret     string
+	BaseURL    string
+	HTTPClient *http.Client
+	Logger     PayPalLogger
+	Clock      func() time.Time
+}
+
+// PayPalProvider implements the Provider interface using the PayPal Orders v2 REST API.
+// Checkout sessions map to PayPal orders with intent CAPTURE; the order ID is used as IntentID.
+type PayPalProvider struct {
+	clientID string
+	secret   string
+	baseURL  string
+	client   *http.Client
+	clock    func() time.Time
+	logger   PayPalLogger
+
+	tokenMu     sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// PayPalAPIError describes an error response returned by the PayPal REST API.
+type PayPalAPIError struct {
+	StatusCode int
+	Name       string
+	Message    string
+	DebugID    string
+	Issue      string
+}
+
+// Error implements the error interface.
+func (e *PayPalAPIError) Error() string {
+	if e == nil {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("status %d", e.StatusCode)}
+	if e.Name != "" {
+		parts = append(parts, e.Name)
+	}
+	if e.Issue != "" {
+		parts = append(parts, e.Issue)
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	if e.DebugID != "" {
+		parts = append(parts, "debug_id="+e.DebugID)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// NewPayPalProvider constructs a PayPal Provider using the given configuration.
+func NewPayPalProvider(cfg PayPalProviderConfig) (*PayPalProvider, error) {
+	clientID := strings.TrimSpace(cfg.ClientID)
+	secret := strings.TrimSpace(cfg.Secret)
+	if clientID == "" || secret == "" {
+		return nil, errors.New("paypal: client id and secret are required")
+	}
+
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		baseURL = PayPalLiveBaseURL
+	}
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("paypal: invalid base url: %w", err)
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: paypalDefaultTimeout}
+	}
+
+	clock := cfg.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+
+	return &PayPalProvider{
+		clientID: clientID,
+		secret:   secret,
+		baseURL:  baseURL,
+		client:   client,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		logger: logger,
+	}, nil
+}
+
+type paypalMoney struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type paypalItem struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	SKU         string      `json:"sku,omitempty"`
+	Quantity    string      `json:"quantity"`
+	UnitAmount  paypalMoney `json:"unit_amount"`
+}
+
+type paypalAmount struct {
+	paypalMoney
+	Breakdown *paypalBreakdown `json:"breakdown,omitempty"`
+}
+
+type paypalBreakdown struct {
+	ItemTotal paypalMoney `json:"item_total"`
+}
+
+type paypalPurchaseUnit struct {
+	ReferenceID string          `json:"reference_id,omitempty"`
+	CustomID    string          `json:"custom_id,omitempty"`
+	Amount      paypalAmount    `json:"amount"`
+	Items       []paypalItem    `json:"items,omitempty"`
+	Payments    *paypalPayments `json:"payments,omitempty"`
+}
+
+type paypalPayments struct {
+	Captures []paypalCapture `json:"captures,omitempty"`
+	Refunds  []paypalRefund  `json:"refunds,omitempty"`
+}
+
+type paypalCapture struct {
+	ID         string      `json:"id"`
+	Status     string      `json:"status"`
+	Amount     paypalMoney `json:"amount"`
+	CreateTime string      `json:"create_time,omitempty"`
+	UpdateTime string      `json:"update_time,omitempty"`
+}
+
+type paypalRefund struct {
+	ID         string      `json:"id"`
+	Status     string      `json:"status"`
+	Amount     paypalMoney `json:"amount"`
+	CreateTime string      `json:"create_time,omitempty"`
+}
+
+type paypalLink struct {
+	Href   string `json:"href"`
+	Rel    string `json:"rel"`
+	Method string `json:"method,omitempty"`
+}
+
+type paypalExperienceContext struct {
+	ReturnURL string `json:"return_url,omitempty"`
+	CancelURL string `json:"cancel_url,omitempty"`
+	Locale    string `json:"locale,omitempty"`
+}
+
+type paypalPaymentSource struct {
+	PayPal struct {
+		ExperienceContext paypalExperienceContext `json:"experience_context"`
+	} `json:"paypal"`
+}
+
+type paypalCreateOrderRequest struct {
+	Intent        string               `json:"intent"`
+	PurchaseUnits []paypalPurchaseUnit `json:"purchase_units"`
+	PaymentSource *paypalPaymentSource `json:"payment_source,omitempty"`
+}
+
+type paypalOrder struct {
+	ID            string               `json:"id"`
+	Status        string               `json:"status"`
+	PurchaseUnits []paypalPurchaseUnit `json:"purchase_units"`
+	Links         []paypalLink         `json:"links"`
+	CreateTime    string               `json:"create_time,omitempty"`
+	UpdateTime    string               `json:"update_time,omitempty"`
+}
+
+type paypalRefundRequest struct {
+	Amount      *paypalMoney `json:"amount,omitempty"`
+	NoteToPayer string       `json:"note_to_payer,omitempty"`
+}
+
+type paypalErrorResponse struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	DebugID string `json:"debug_id"`
+	Details []struct {
+		Issue       string `json:"issue"`
+		Description string `json:"description"`
+	} `json:"details"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// CreateCheckoutSession creates a PayPal order and returns the buyer approval link.
+func (p *PayPalProvider) CreateCheckoutSession(ctx context.Context, req CheckoutSessionRequest) (CheckoutSession, error) {
+	if p == nil {
+		return CheckoutSession{}, errors.New("paypal: provider is nil")
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		return CheckoutSession{}, errors.New("paypal: currency is required")
+	}
+	if strings.TrimSpace(req.SavedPaymentMethodID) != "" {
+		return CheckoutSession{}, fmt.Errorf("%w: paypal cannot charge saved payment methods", ErrUnsupportedPaymentMethod)
+	}
+	for _, method := range req.PaymentMethods {
+		if method != PaymentMethodCard {
+			return CheckoutSession{}, fmt.Errorf("%w: paypal cannot offer %q", ErrUnsupportedPaymentMethod, method)
+		}
+	}
+
+	unit := paypalPurchaseUnit{
+		ReferenceID: req.Metadata["orderId"],
+		CustomID:    req.Metadata["paymentId"],
+	}
+	var itemTotal int64
+	for _, item := range req.Items {
+		itemCurrency := strings.ToUpper(defaultString(item.Currency, currency))
+		if itemCurrency != currency {
+			return CheckoutSession{}, fmt.Errorf("paypal: item %q currency %s does not match %s", item.Name, itemCurrency, currency)
+		}
+		quantity := max64(item.Quantity, 1)
+		unit.Items = append(unit.Items, paypalItem{
+			Name:        defaultString(item.Name, "Item"),
+			Description: item.Description,
+			SKU:         item.SKU,
+			Quantity:    strconv.FormatInt(quantity, 10),
+			UnitAmount:  paypalMoney{CurrencyCode: currency, Value: formatPayPalAmount(item.Amount, currency)},
+		})
+		itemTotal += item.Amount * quantity
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = itemTotal
+	}
+	unit.Amount = paypalAmount{paypalMoney: paypalMoney{CurrencyCode: currency, Value: formatPayPalAmount(amount, currency)}}
+	if len(unit.Items) > 0 {
+		if itemTotal != amount {
+			// PayPal rejects orders whose breakdown does not add up, so only itemise exact totals.
+			unit.Items = nil
+		} else {
+			unit.Amount.Breakdown = &paypalBreakdown{ItemTotal: unit.Amount.paypalMoney}
+		}
+	}
+
+	body := paypalCreateOrderRequest{
+		Intent:        "CAPTURE",
+		PurchaseUnits: []paypalPurchaseUnit{unit},
+	}
+	if req.SuccessURL != "" || req.CancelURL != "" || req.Locale != "" {
+		source := &paypalPaymentSource{}
+		source.PayPal.ExperienceContext = paypalExperienceContext{
+			ReturnURL: req.SuccessURL,
+			CancelURL: req.CancelURL,
+			Locale:    normalisePayPalLocale(req.Locale),
+		}
+		body.PaymentSource = source
+	}
+
+	var order paypalOrder
+	raw, err := p.do(ctx, http.MethodPost, "/v2/checkout/orders", req.IdempotencyKey, body, &order)
+	if err != nil {
+		return CheckoutSession{}, fmt.Errorf("paypal: create order: %w", err)
+	}
+
+	p.logger(ctx, "payments.paypal.order.created", map[string]any{
+		"orderId":  order.ID,
+		"status":   order.Status,
+		"currency": currency,
+	})
+
+	return CheckoutSession{
+		ID:          order.ID,
+		Provider:    ProviderPayPal,
+		RedirectURL: paypalApprovalLink(order.Links),
+		IntentID:    order.ID,
+		ExpiresAt:   p.clock().Add(paypalSessionLifetime),
+		Raw:         raw,
+	}, nil
+}
+
+// Confirm reports the order state. PayPal orders are confirmed by the buyer on the approval page,
+// so there is no server-side confirmation call; callers capture once the order is approved.
+func (p *PayPalProvider) Confirm(ctx context.Context, req ConfirmRequest) (PaymentDetails, error) {
+	if p == nil {
+		return PaymentDetails{}, errors.New("paypal: provider is nil")
+	}
+	return p.LookupPayment(ctx, LookupRequest{IntentID: req.IntentID})
+}
+
+// Capture captures an approved PayPal order. Orders v2 captures the full order amount, so partial
+// amounts that differ from the order total are rejected.
+func (p *PayPalProvider) Capture(ctx context.Context, req CaptureRequest) (PaymentDetails, error) {
+	if p == nil {
+		return PaymentDetails{}, errors.New("paypal: provider is nil")
+	}
+	orderID := strings.TrimSpace(req.IntentID)
+	if orderID == "" {
+		return PaymentDetails{}, errors.New("paypal: order id is required")
+	}
+
+	if req.Amount != nil {
+		current, err := p.LookupPayment(ctx, LookupRequest{IntentID: orderID})
+		if err != nil {
+			return PaymentDetails{}, err
+		}
+		if *req.Amount != current.Amount {
+			return PaymentDetails{}, fmt.Errorf("paypal: partial capture of %d is not supported for order amount %d", *req.Amount, current.Amount)
+		}
+	}
+
+	var order paypalOrder
+	raw, err := p.do(ctx, http.MethodPost, "/v2/checkout/orders/"+url.PathEscape(orderID)+"/capture", req.IdempotencyKey, struct{}{}, &order)
+	if err != nil {
+		return PaymentDetails{}, fmt.Errorf("paypal: capture order: %w", err)
+	}
+	details := paypalPaymentDetails(order, raw)
+	p.logger(ctx, "payments.paypal.order.captured", map[string]any{
+		"orderId": order.ID,
+		"status":  order.Status,
+		"amount":  details.Amount,
+	})
+	return details, nil
+}
+
+// Refund refunds the order's capture, fully or for the requested amount.
+func (p *PayPalProvider) Refund(ctx context.Context, req RefundRequest) (PaymentDetails, error) {
+	if p == nil {
+		return PaymentDetails{}, errors.New("paypal: provider is nil")
+	}
+	orderID := strings.TrimSpace(req.IntentID)
+	if orderID == "" {
+		return PaymentDetails{}, errors.New("paypal: order id is required")
+	}
+
+	order, _, err := p.getOrder(ctx, orderID)
+	if err != nil {
+		return PaymentDetails{}, fmt.Errorf("paypal: lookup order: %w", err)
+	}
+	capture, ok := latestPayPalCapture(order)
+	if !ok {
+		return PaymentDetails{}, fmt.Errorf("paypal: order %s has no capture to refund", orderID)
+	}
+
+	body := paypalRefundRequest{NoteToPayer: strings.TrimSpace(req.Reason)}
+	if req.Amount != nil {
+		currency := strings.ToUpper(capture.Amount.CurrencyCode)
+		body.Amount = &paypalMoney{CurrencyCode: currency, Value: formatPayPalAmount(*req.Amount, currency)}
+	}
+
+	var refund paypalRefund
+	if _, err := p.do(ctx, http.MethodPost, "/v2/payments/captures/"+url.PathEscape(capture.ID)+"/refund", req.IdempotencyKey, body, &refund); err != nil {
+		return PaymentDetails{}, fmt.Errorf("paypal: refund capture: %w", err)
+	}
+	p.logger(ctx, "payments.paypal.capture.refunded", map[string]any{
+		"orderId":   orderID,
+		"captureId": capture.ID,
+		"refundId":  refund.ID,
+	})
+	return p.LookupPayment(ctx, LookupRequest{IntentID: orderID})
+}
+
+// LookupPayment retrieves a PayPal order.
+func (p *PayPalProvider) LookupPayment(ctx context.Context, req LookupRequest) (PaymentDetails, error) {
+	if p == nil {
+		return PaymentDetails{}, errors.New("paypal: provider is nil")
+	}
+	orderID := strings.TrimSpace(req.IntentID)
+	if orderID == "" {
+		return PaymentDetails{}, errors.New("paypal: order id is required")
+	}
+	order, raw, err := p.getOrder(ctx, orderID)
+	if err != nil {
+		return PaymentDetails{}, fmt.Errorf("paypal: lookup order: %w", err)
+	}
+	return paypalPaymentDetails(order, raw), nil
+}
+
+// SubmitDisputeEvidence is not supported; PayPal disputes are answered in the resolution center.
+func (p *PayPalProvider) SubmitDisputeEvidence(ctx context.Context, req DisputeEvidenceRequest) (DisputeDetails, error) {
+	return DisputeDetails{}, fmt.Errorf("%w: paypal dispute evidence submission", ErrUnsupportedProvider)
+}
+
+func (p *PayPalProvider) getOrder(ctx context.Context, orderID string) (paypalOrder, map[string]any, error) {
+	var order paypalOrder
+	raw, err := p.do(ctx, http.MethodGet, "/v2/checkout/orders/"+url.PathEscape(orderID), "", nil, &order)
+	return order, raw, err
+}
+
+// do performs an authenticated JSON request, decoding the response into out and returning the raw document.
+func (p *PayPalProvider) do(ctx context.Context, method, path, idempotencyKey string, body any, out any) (map[string]any, error) {
+	token, err := p.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key := strings.TrimSpace(idempotencyKey); key != "" {
+		req.Header.Set("PayPal-Request-Id", key)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, paypalMaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		p.invalidateToken()
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, parsePayPalError(resp.StatusCode, data)
+	}
+
+	raw := map[string]any{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return raw, nil
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	_ = json.Unmarshal(data, &raw)
+	return raw, nil
+}
+
+// token returns a cached OAuth access token, requesting a new one shortly before expiry.
+func (p *PayPalProvider) token(ctx context.Context) (string, error) {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+
+	now := p.clock()
+	if p.accessToken != "" && now.Before(p.tokenExpiry) {
+		return p.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.clientID, p.secret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("paypal: request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, paypalMaxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("paypal: read access token: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("paypal: request access token: %w", parsePayPalError(resp.StatusCode, data))
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("paypal: decode access token: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return "", errors.New("paypal: access token missing from response")
+	}
+
+	lifetime := time.Duration(payload.ExpiresIn) * time.Second
+	if lifetime > paypalTokenSkew {
+		lifetime -= paypalTokenSkew
+	}
+	p.accessToken = payload.AccessToken
+	p.tokenExpiry = now.Add(lifetime)
+	return p.accessToken, nil
+}
+
+func (p *PayPalProvider) invalidateToken() {
+	p.tokenMu.Lock()
+	p.accessToken = ""
+	p.tokenExpiry = time.Time{}
+	p.tokenMu.Unlock()
+}
+
+func parsePayPalError(status int, data []byte) error {
+	apiErr := &PayPalAPIError{StatusCode: status}
+	var payload paypalErrorResponse
+	if err := json.Unmarshal(data, &payload); err == nil {
+		apiErr.Name = defaultString(payload.Name, payload.Error)
+		apiErr.Message = defaultString(payload.Message, payload.ErrorDescription)
+		apiErr.DebugID = payload.DebugID
+		if len(payload.Details) > 0 {
+			apiErr.Issue = payload.Details[0].Issue
+		}
+	}
+	return apiErr
+}
+
+func paypalPaymentDetails(order paypalOrder, raw map[string]any) PaymentDetails {
+	details := PaymentDetails{
+		Provider: ProviderPayPal,
+		IntentID: order.ID,
+		Status:   StatusPending,
+		Raw:      raw,
+	}
+
+	if len(order.PurchaseUnits) > 0 {
+		amount := order.PurchaseUnits[0].Amount
+		details.Currency = strings.ToUpper(amount.CurrencyCode)
+		if value, err := parsePayPalAmount(amount.Value, details.Currency); err == nil {
+			details.Amount = value
+		}
+	}
+
+	switch strings.ToUpper(order.Status) {
+	case "VOIDED":
+		details.Status = StatusFailed
+	case "COMPLETED":
+		details.Status = StatusSucceeded
+	}
+
+	capture, ok := latestPayPalCapture(order)
+	if !ok {
+		return details
+	}
+	if details.Currency == "" {
+		details.Currency = strings.ToUpper(capture.Amount.CurrencyCode)
+	}
+	switch strings.ToUpper(capture.Status) {
+	case "COMPLETED":
+		details.Status = StatusSucceeded
+		details.Captured = true
+		details.CapturedAt = parsePayPalTime(capture.CreateTime)
+	case "PARTIALLY_REFUNDED":
+		details.Status = StatusSucceeded
+		details.Captured = true
+		details.CapturedAt = parsePayPalTime(capture.CreateTime)
+		details.RefundedAt = parsePayPalTime(capture.UpdateTime)
+		details.AmountRefunded = paypalRefundedAmount(order, details.Currency)
+	case "REFUNDED":
+		details.Status = StatusRefunded
+		details.Captured = true
+		details.CapturedAt = parsePayPalTime(capture.CreateTime)
+		details.RefundedAt = parsePayPalTime(capture.UpdateTime)
+		details.AmountRefunded = paypalRefundedAmount(order, details.Currency)
+		if details.AmountRefunded == 0 {
+			if value, err := parsePayPalAmount(capture.Amount.Value, details.Currency); err == nil {
+				details.AmountRefunded = value
+			}
+		}
+	case "DECLINED", "FAILED":
+		details.Status = StatusFailed
+	case "PENDING":
+		details.Status = StatusPending
+	}
+	return details
+}
+
+// paypalRefundedAmount sums the completed refunds on the order.
+func paypalRefundedAmount(order paypalOrder, currency string) int64 {
+	var total int64
+	for _, unit := range order.PurchaseUnits {
+		if unit.Payments == nil {
+			continue
+		}
+		for _, refund := range unit.Payments.Refunds {
+			if !strings.EqualFold(refund.Status, "COMPLETED") {
+				continue
+			}
+			if value, err := parsePayPalAmount(refund.Amount.Value, currency); err == nil {
+				total += value
+			}
+		}
+	}
+	return total
+}
+
+func latestPayPalCapture(order paypalOrder) (paypalCapture, bool) {
+	for i := len(order.PurchaseUnits) - 1; i >= 0; i-- {
+		payments := order.PurchaseUnits[i].Payments
+		if payments == nil || len(payments.Captures) == 0 {
+			continue
+		}
+		return payments.Captures[len(payments.Captures)-1], true
+	}
+	return paypalCapture{}, false
+}
+
+func paypalApprovalLink(links []paypalLink) string {
+	for _, link := range links {
+		switch strings.ToLower(link.Rel) {
+		case "payer-action", "approve":
+			return link.Href
+		}
+	}
+	return ""
+}
+
+func parsePayPalTime(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	parsed = parsed.UTC()
+	return &parsed
+}
+
+func normalisePayPalLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	if locale == "" {
+		return ""
+	}
+	parts := strings.SplitN(locale, "-", 2)
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0])
+	}
+	return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+}
+
+func paypalCurrencyExponent(currency string) int {
+	if _, ok := zeroDecimalCurrencies[strings.ToUpper(currency)]; ok {
+		return 0
+	}
+	return 2
+}
+
+// formatPayPalAmount renders minor units as the decimal string PayPal expects.
+func formatPayPalAmount(amount int64, currency string) string {
+	exponent := paypalCurrencyExponent(currency)
+	if exponent == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	divisor := int64(math.Pow10(exponent))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/divisor, exponent, amount%divisor)
+}
+
+// parsePayPalAmount converts PayPal decimal strings back into minor units.
+func parsePayPalAmount(value, currency string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, errors.New("amount is empty")
+	}
+	exponent := paypalCurrencyExponent(currency)
+	whole, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > exponent {
+		return 0, fmt.Errorf("amount %q has too many decimal places", value)
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+	units, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse amount %q: %w", value, err)
+	}
+	return units, nil
+}
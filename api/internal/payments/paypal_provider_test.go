@@ -0,0 +1,290 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePayPalAPI is an in-memory stand-in for the subset of the PayPal REST API the provider uses.
+type fakePayPalAPI struct {
+	t          *testing.T
+	mu         sync.Mutex
+	tokenCalls int
+	requestIDs []string
+	orders     map[string]map[string]any
+	nextID     int
+}
+
+func newFakePayPalAPI(t *testing.T) (*fakePayPalAPI, *httptest.Server) {
+	api := &fakePayPalAPI{t: t, orders: make(map[string]map[string]any)}
+	server := httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func (f *fakePayPalAPI) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/v1/oauth2/token" {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "secret" {
+			writeFakePayPal(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+			return
+		}
+		f.tokenCalls++
+		writeFakePayPal(w, http.StatusOK, map[string]any{"access_token": "tok", "expires_in": 3600})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer tok" {
+		writeFakePayPal(w, http.StatusUnauthorized, map[string]any{"name": "AUTHENTICATION_FAILURE"})
+		return
+	}
+	if id := r.Header.Get("PayPal-Request-Id"); id != "" {
+		f.requestIDs = append(f.requestIDs, id)
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/checkout/orders":
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			f.t.Fatalf("decode create body: %v", err)
+		}
+		f.nextID++
+		id := "ORDER-" + string(rune('0'+f.nextID))
+		units := body["purchase_units"].([]any)
+		order := map[string]any{
+			"id":             id,
+			"status":         "PAYER_ACTION_REQUIRED",
+			"purchase_units": units,
+			"links": []any{
+				map[string]any{"rel": "self", "href": "https://paypal.test/orders/" + id},
+				map[string]any{"rel": "payer-action", "href": "https://paypal.test/checkoutnow?token=" + id},
+			},
+		}
+		f.orders[id] = order
+		writeFakePayPal(w, http.StatusCreated, order)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v2/checkout/orders/"):
+		order, ok := f.orders[strings.TrimPrefix(r.URL.Path, "/v2/checkout/orders/")]
+		if !ok {
+			writeFakePayPal(w, http.StatusNotFound, map[string]any{"name": "RESOURCE_NOT_FOUND", "details": []any{map[string]any{"issue": "INVALID_RESOURCE_ID"}}})
+			return
+		}
+		writeFakePayPal(w, http.StatusOK, order)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/capture"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/checkout/orders/"), "/capture")
+		order, ok := f.orders[id]
+		if !ok {
+			writeFakePayPal(w, http.StatusNotFound, map[string]any{"name": "RESOURCE_NOT_FOUND"})
+			return
+		}
+		unit := order["purchase_units"].([]any)[0].(map[string]any)
+		unit["payments"] = map[string]any{
+			"captures": []any{map[string]any{
+				"id":          "CAP-" + id,
+				"status":      "COMPLETED",
+				"amount":      unit["amount"],
+				"create_time": "2025-05-01T10:00:00Z",
+			}},
+		}
+		order["status"] = "COMPLETED"
+		writeFakePayPal(w, http.StatusCreated, order)
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v2/payments/captures/"):
+		captureID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/payments/captures/"), "/refund")
+		var body struct {
+			Amount *paypalMoney `json:"amount"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, order := range f.orders {
+			unit := order["purchase_units"].([]any)[0].(map[string]any)
+			payments, ok := unit["payments"].(map[string]any)
+			if !ok {
+				continue
+			}
+			capture := payments["captures"].([]any)[0].(map[string]any)
+			if capture["id"] != captureID {
+				continue
+			}
+			status := "REFUNDED"
+			if body.Amount != nil && body.Amount.Value != capture["amount"].(map[string]any)["value"] {
+				status = "PARTIALLY_REFUNDED"
+			}
+			capture["status"] = status
+			capture["update_time"] = "2025-05-02T10:00:00Z"
+			writeFakePayPal(w, http.StatusCreated, map[string]any{"id": "REF-1", "status": "COMPLETED"})
+			return
+		}
+		writeFakePayPal(w, http.StatusNotFound, map[string]any{"name": "RESOURCE_NOT_FOUND"})
+	default:
+		writeFakePayPal(w, http.StatusNotFound, map[string]any{"name": "NOT_FOUND"})
+	}
+}
+
+func writeFakePayPal(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newTestPayPalProvider(t *testing.T, baseURL string) *PayPalProvider {
+	t.Helper()
+	provider, err := NewPayPalProvider(PayPalProviderConfig{
+		ClientID: "client",
+		Secret:   "secret",
+		BaseURL:  baseURL,
+		Clock:    func() time.Time { return time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC) },
+	})
+	if err != nil {
+		t.Fatalf("new paypal provider: %v", err)
+	}
+	return provider
+}
+
+func TestPayPalProviderOrderLifecycle(t *testing.T) {
+	api, server := newFakePayPalAPI(t)
+	provider := newTestPayPalProvider(t, server.URL)
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{
+		Amount:         2500,
+		Currency:       "usd",
+		SuccessURL:     "https://shop.test/success",
+		CancelURL:      "https://shop.test/cancel",
+		Locale:         "en_us",
+		IdempotencyKey: "idem-create",
+		Metadata:       map[string]string{"orderId": "ord_1"},
+		Items:          []CheckoutLineItem{{Name: "Seal", SKU: "SKU-1", Quantity: 2, Amount: 1250}},
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.Provider != ProviderPayPal || session.IntentID != session.ID {
+		t.Fatalf("unexpected session %+v", session)
+	}
+	if session.RedirectURL != "https://paypal.test/checkoutnow?token="+session.ID {
+		t.Fatalf("expected payer action link, got %q", session.RedirectURL)
+	}
+	unit := api.orders[session.ID]["purchase_units"].([]any)[0].(map[string]any)
+	if value := unit["amount"].(map[string]any)["value"]; value != "25.00" {
+		t.Fatalf("expected decimal amount 25.00, got %v", value)
+	}
+
+	details, err := provider.Confirm(ctx, ConfirmRequest{IntentID: session.ID})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if details.Status != StatusPending || details.Amount != 2500 || details.Currency != "USD" {
+		t.Fatalf("unexpected pending details %+v", details)
+	}
+
+	captured, err := provider.Capture(ctx, CaptureRequest{IntentID: session.ID, IdempotencyKey: "idem-capture"})
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if captured.Status != StatusSucceeded || !captured.Captured || captured.CapturedAt == nil {
+		t.Fatalf("unexpected capture details %+v", captured)
+	}
+
+	partial := int64(500)
+	refunded, err := provider.Refund(ctx, RefundRequest{IntentID: session.ID, Amount: &partial, Reason: "damaged"})
+	if err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+	if refunded.Status != StatusSucceeded || refunded.RefundedAt == nil {
+		t.Fatalf("expected partial refund to keep succeeded status, got %+v", refunded)
+	}
+
+	refunded, err = provider.Refund(ctx, RefundRequest{IntentID: session.ID})
+	if err != nil {
+		t.Fatalf("full refund: %v", err)
+	}
+	if refunded.Status != StatusRefunded {
+		t.Fatalf("expected refunded status, got %s", refunded.Status)
+	}
+
+	if api.tokenCalls != 1 {
+		t.Fatalf("expected cached access token, got %d token requests", api.tokenCalls)
+	}
+	if strings.Join(api.requestIDs, ",") != "idem-create,idem-capture" {
+		t.Fatalf("unexpected PayPal-Request-Id headers %v", api.requestIDs)
+	}
+}
+
+func TestPayPalProviderZeroDecimalCurrencyAndErrors(t *testing.T) {
+	api, server := newFakePayPalAPI(t)
+	provider := newTestPayPalProvider(t, server.URL)
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Amount: 5400, Currency: "JPY"})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	unit := api.orders[session.ID]["purchase_units"].([]any)[0].(map[string]any)
+	if value := unit["amount"].(map[string]any)["value"]; value != "5400" {
+		t.Fatalf("expected JPY amount without decimals, got %v", value)
+	}
+
+	partial := int64(100)
+	if _, err := provider.Capture(ctx, CaptureRequest{IntentID: session.ID, Amount: &partial}); err == nil {
+		t.Fatalf("expected partial capture to be rejected")
+	}
+	if _, err := provider.Refund(ctx, RefundRequest{IntentID: session.ID}); err == nil {
+		t.Fatalf("expected refund without capture to fail")
+	}
+
+	_, err = provider.LookupPayment(ctx, LookupRequest{IntentID: "missing"})
+	var apiErr *PayPalAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected PayPalAPIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Issue != "INVALID_RESOURCE_ID" {
+		t.Fatalf("unexpected api error %+v", apiErr)
+	}
+}
+
+func TestManagerRoutesCurrencyToPayPalProvider(t *testing.T) {
+	_, server := newFakePayPalAPI(t)
+	paypal := newTestPayPalProvider(t, server.URL)
+	stripe := &fakeProvider{session: CheckoutSession{ID: "sess_stripe"}}
+
+	mgr, err := NewManager(
+		map[string]Provider{ProviderStripe: stripe, ProviderPayPal: paypal},
+		WithCurrencyRoutes(map[string]string{"USD": ProviderPayPal}),
+	)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	session, err := mgr.CreateCheckoutSession(context.Background(), PaymentContext{Currency: "USD"}, CheckoutSessionRequest{Amount: 1000, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.Provider != ProviderPayPal || !strings.HasPrefix(session.ID, "ORDER-") {
+		t.Fatalf("expected paypal session, got %+v", session)
+	}
+	if stripe.lastOp != "" {
+		t.Fatalf("expected stripe provider to remain unused")
+	}
+}
+
+func TestPayPalAmountConversion(t *testing.T) {
+	if got := formatPayPalAmount(1205, "EUR"); got != "12.05" {
+		t.Fatalf("expected 12.05 got %s", got)
+	}
+	if got, err := parsePayPalAmount("12.5", "EUR"); err != nil || got != 1250 {
+		t.Fatalf("expected 1250 got %d (%v)", got, err)
+	}
+	if _, err := parsePayPalAmount("1.234", "USD"); err == nil {
+		t.Fatalf("expected error for excess precision")
+	}
+	if _, err := NewPayPalProvider(PayPalProviderConfig{ClientID: "client"}); err == nil {
+		t.Fatalf("expected error when secret missing")
+	}
+}
@@ -22,9 +22,44 @@ const (
 	StatusRefunded Status = "refunded"
 )
 
+const (
+	// ProviderStripe is the registration key for the Stripe provider.
+	ProviderStripe = "stripe"
+	// ProviderPayPal is the registration key for the PayPal provider.
+	ProviderPayPal = "paypal"
+)
+
+// PaymentMethod enumerates the payment methods a checkout session may offer.
+type PaymentMethod string
+
+const (
+	// PaymentMethodCard accepts card payments that settle during checkout.
+	PaymentMethodCard PaymentMethod = "card"
+	// PaymentMethodKonbini issues a voucher the customer pays at a Japanese convenience store.
+	PaymentMethodKonbini PaymentMethod = "konbini"
+	// PaymentMethodBankTransfer issues Japanese bank transfer instructions funded via the customer balance.
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+)
+
+// AsyncPaymentRawKey is the PaymentDetails.Raw key holding voucher or transfer instructions for
+// payment methods that settle after checkout completes.
+const AsyncPaymentRawKey = "async_payment"
+
 // ErrUnsupportedProvider is returned when the manager cannot locate a provider.
 var ErrUnsupportedProvider = errors.New("payments: unsupported provider")
 
+// ErrUnsupportedPaymentMethod is returned when a provider cannot offer a requested payment method.
+var ErrUnsupportedPaymentMethod = errors.New("payments: unsupported payment method")
+
+// ErrSavedMethodsUnsupported is returned when the resolved provider cannot store payment methods.
+var ErrSavedMethodsUnsupported = errors.New("payments: provider does not support saved payment methods")
+
+// ErrDisputeEvidenceInvalid is returned when dispute evidence cannot be mapped onto the PSP format.
+var ErrDisputeEvidenceInvalid = errors.New("payments: invalid dispute evidence")
+
+// ErrAuthenticationRequired is returned when an off-session charge needs the customer to authenticate.
+var ErrAuthenticationRequired = errors.New("payments: customer authentication required")
+
 // CheckoutLineItem describes a single line item to include in a checkout session.
 type CheckoutLineItem struct {
 	Name        string
@@ -47,6 +82,13 @@ type CheckoutSessionRequest struct {
 	IdempotencyKey string
 	Items          []CheckoutLineItem
 	AllowPromotion bool
+	// PaymentMethods restricts the offered methods; empty leaves the choice to the provider.
+	PaymentMethods []PaymentMethod
+	// KonbiniExpiresAfterDays sets the voucher validity in days; zero keeps the provider default.
+	KonbiniExpiresAfterDays int
+	// SavedPaymentMethodID charges a stored PSP payment method off-session instead of opening a
+	// hosted session. CustomerID must reference the customer the method is attached to.
+	SavedPaymentMethodID string
 }
 
 // CheckoutSession represents the PSP session returned to the client.
@@ -100,7 +142,93 @@ type PaymentDetails struct {
 	Captured   bool
 	CapturedAt *time.Time
 	RefundedAt *time.Time
-	Raw        map[string]any
+	// AmountRefunded is the total refunded at the PSP in minor units.
+	AmountRefunded int64
+	Raw            map[string]any
+}
+
+// SetupSessionRequest asks the PSP to collect a reusable payment method without charging it. An empty
+// CustomerID creates a PSP customer for the user.
+type SetupSessionRequest struct {
+	CustomerID     string
+	Email          string
+	Name           string
+	Metadata       map[string]string
+	IdempotencyKey string
+}
+
+// SetupSession is returned to the client to complete payment method collection.
+type SetupSession struct {
+	ID           string
+	Provider     string
+	CustomerID   string
+	ClientSecret string
+	Raw          map[string]any
+}
+
+// AttachPaymentMethodRequest attaches a collected PSP payment method to a PSP customer.
+type AttachPaymentMethodRequest struct {
+	CustomerID      string
+	PaymentMethodID string
+	IdempotencyKey  string
+}
+
+// DetachPaymentMethodRequest removes a stored PSP payment method from its customer.
+type DetachPaymentMethodRequest struct {
+	PaymentMethodID string
+}
+
+// SavedPaymentMethod describes a stored PSP payment method.
+type SavedPaymentMethod struct {
+	Provider        string
+	PaymentMethodID string
+	CustomerID      string
+	Brand           string
+	Last4           string
+	ExpMonth        int
+	ExpYear         int
+}
+
+// SavedMethodProvider is implemented by providers able to store payment methods for later
+// off-session charges.
+type SavedMethodProvider interface {
+	CreateSetupSession(ctx context.Context, req SetupSessionRequest) (SetupSession, error)
+	AttachPaymentMethod(ctx context.Context, req AttachPaymentMethodRequest) (SavedPaymentMethod, error)
+	DetachPaymentMethod(ctx context.Context, req DetachPaymentMethodRequest) error
+}
+
+// Dispute evidence kinds accepted by SubmitDisputeEvidence. Each kind carries at most one file.
+const (
+	DisputeEvidenceReceipt               = "receipt"
+	DisputeEvidenceShippingDocumentation = "shipping_documentation"
+	DisputeEvidenceCustomerCommunication = "customer_communication"
+	DisputeEvidenceUncategorized         = "uncategorized_file"
+)
+
+// DisputeEvidenceFile is a document offered to the PSP as dispute evidence.
+type DisputeEvidenceFile struct {
+	Kind        string
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// DisputeEvidenceRequest submits evidence for a PSP dispute. Submission is final at most PSPs.
+type DisputeEvidenceRequest struct {
+	DisputeID      string
+	Explanation    string
+	Files          []DisputeEvidenceFile
+	IdempotencyKey string
+	Metadata       map[string]string
+}
+
+// DisputeDetails normalises the PSP view of a dispute after evidence submission.
+type DisputeDetails struct {
+	Provider      string
+	DisputeID     string
+	Status        string
+	EvidenceDueBy *time.Time
+	Raw           map[string]any
 }
 
 // Provider defines the contract for PSP adapters to implement.
@@ -110,6 +238,7 @@ type Provider interface {
 	Capture(ctx context.Context, req CaptureRequest) (PaymentDetails, error)
 	Refund(ctx context.Context, req RefundRequest) (PaymentDetails, error)
 	LookupPayment(ctx context.Context, req LookupRequest) (PaymentDetails, error)
+	SubmitDisputeEvidence(ctx context.Context, req DisputeEvidenceRequest) (DisputeDetails, error)
 }
 
 // Manager coordinates provider selection and exposes the aggregated interface.
@@ -117,6 +246,8 @@ type Manager struct {
 	providers       map[string]Provider
 	defaultProvider string
 	currencyRoutes  map[string]string
+	rules           []RoutingRule
+	logger          ManagerLogger
 }
 
 // ManagerOption configures optional behaviour when building a Manager.
@@ -159,69 +290,66 @@ func NewManager(providers map[string]Provider, opts ...ManagerOption) (*Manager,
 	}
 	m := &Manager{
 		providers: copyMap,
+		logger:    func(context.Context, string, map[string]any) {},
 	}
-	if _, ok := copyMap["stripe"]; ok {
-		m.defaultProvider = "stripe"
+	if _, ok := copyMap[ProviderStripe]; ok {
+		m.defaultProvider = ProviderStripe
 	}
 	for _, opt := range opts {
 		opt(m)
 	}
+	if err := m.validateRoutingRules(); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
-// PaymentContext defines the hints available when selecting a provider.
+// PaymentContext defines the hints available when selecting a provider. Operations on an existing
+// payment should set PreferredProvider so they reach the PSP holding the intent; the remaining
+// fields feed routing rules for new payments.
 type PaymentContext struct {
 	PreferredProvider string
 	Currency          string
+	Amount            int64
+	BillingCountry    string
+	PaymentMethod     PaymentMethod
+	UserSegment       string
 	Metadata          map[string]string
 }
 
 func (m *Manager) resolveProvider(ctx PaymentContext) (string, Provider, error) {
-	if m == nil {
-		return "", nil, errors.New("payments: manager is nil")
-	}
-	if len(m.providers) == 0 {
-		return "", nil, errors.New("payments: no providers registered")
-	}
-	if provider := strings.TrimSpace(strings.ToLower(ctx.PreferredProvider)); provider != "" {
-		if p, ok := m.providers[provider]; ok {
-			return provider, p, nil
-		}
-	}
-	currency := strings.ToUpper(strings.TrimSpace(ctx.Currency))
-	if currency != "" && m.currencyRoutes != nil {
-		if providerKey, ok := m.currencyRoutes[currency]; ok {
-			provider := strings.TrimSpace(strings.ToLower(providerKey))
-			if p, ok := m.providers[provider]; ok {
-				return provider, p, nil
-			}
-		}
-	}
-	if def := strings.TrimSpace(strings.ToLower(m.defaultProvider)); def != "" {
-		if p, ok := m.providers[def]; ok {
-			return def, p, nil
-		}
-	}
-	if len(m.providers) == 1 {
-		for key, p := range m.providers {
-			return key, p, nil
-		}
+	decision, err := m.Route(ctx)
+	if err != nil {
+		return "", nil, err
 	}
-	return "", nil, ErrUnsupportedProvider
+	return decision.Provider, m.providers[decision.Provider], nil
 }
 
-// CreateCheckoutSession delegates to the resolved provider.
+// CreateCheckoutSession delegates to the routed provider. When the matching routing rule lists
+// fallbacks, retryable provider errors move on to the next provider in order.
 func (m *Manager) CreateCheckoutSession(ctx context.Context, paymentCtx PaymentContext, req CheckoutSessionRequest) (CheckoutSession, error) {
-	key, provider, err := m.resolveProvider(paymentCtx)
+	decision, err := m.Route(paymentCtx)
 	if err != nil {
 		return CheckoutSession{}, err
 	}
-	session, err := provider.CreateCheckoutSession(ctx, req)
-	if err != nil {
-		return CheckoutSession{}, err
+	candidates := decision.Candidates()
+	for i, key := range candidates {
+		session, err := m.providers[key].CreateCheckoutSession(ctx, req)
+		if err == nil {
+			session.Provider = key
+			return session, nil
+		}
+		if i == len(candidates)-1 || !IsRetryableError(err) || ctx.Err() != nil {
+			return CheckoutSession{}, err
+		}
+		m.logger(ctx, "payments.route.fallback", map[string]any{
+			"rule":     decision.Rule,
+			"provider": key,
+			"next":     candidates[i+1],
+			"error":    err.Error(),
+		})
 	}
-	session.Provider = key
-	return session, nil
+	return CheckoutSession{}, ErrUnsupportedProvider
 }
 
 // Confirm delegates to the resolved provider.
@@ -259,3 +387,66 @@ func (m *Manager) LookupPayment(ctx context.Context, paymentCtx PaymentContext,
 	}
 	return provider.LookupPayment(ctx, req)
 }
+
+// SubmitDisputeEvidence delegates to the resolved provider.
+func (m *Manager) SubmitDisputeEvidence(ctx context.Context, paymentCtx PaymentContext, req DisputeEvidenceRequest) (DisputeDetails, error) {
+	key, provider, err := m.resolveProvider(paymentCtx)
+	if err != nil {
+		return DisputeDetails{}, err
+	}
+	details, err := provider.SubmitDisputeEvidence(ctx, req)
+	if err != nil {
+		return DisputeDetails{}, err
+	}
+	details.Provider = key
+	return details, nil
+}
+
+// CreateSetupSession delegates to the resolved provider when it supports saved payment methods.
+func (m *Manager) CreateSetupSession(ctx context.Context, paymentCtx PaymentContext, req SetupSessionRequest) (SetupSession, error) {
+	key, provider, err := m.resolveSavedMethodProvider(paymentCtx)
+	if err != nil {
+		return SetupSession{}, err
+	}
+	session, err := provider.CreateSetupSession(ctx, req)
+	if err != nil {
+		return SetupSession{}, err
+	}
+	session.Provider = key
+	return session, nil
+}
+
+// AttachPaymentMethod delegates to the resolved provider when it supports saved payment methods.
+func (m *Manager) AttachPaymentMethod(ctx context.Context, paymentCtx PaymentContext, req AttachPaymentMethodRequest) (SavedPaymentMethod, error) {
+	key, provider, err := m.resolveSavedMethodProvider(paymentCtx)
+	if err != nil {
+		return SavedPaymentMethod{}, err
+	}
+	method, err := provider.AttachPaymentMethod(ctx, req)
+	if err != nil {
+		return SavedPaymentMethod{}, err
+	}
+	method.Provider = key
+	return method, nil
+}
+
+// DetachPaymentMethod delegates to the resolved provider when it supports saved payment methods.
+func (m *Manager) DetachPaymentMethod(ctx context.Context, paymentCtx PaymentContext, req DetachPaymentMethodRequest) error {
+	_, provider, err := m.resolveSavedMethodProvider(paymentCtx)
+	if err != nil {
+		return err
+	}
+	return provider.DetachPaymentMethod(ctx, req)
+}
+
+func (m *Manager) resolveSavedMethodProvider(paymentCtx PaymentContext) (string, SavedMethodProvider, error) {
+	key, provider, err := m.resolveProvider(paymentCtx)
+	if err != nil {
+		return "", nil, err
+	}
+	saved, ok := provider.(SavedMethodProvider)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", ErrSavedMethodsUnsupported, key)
+	}
+	return key, saved, nil
+}
@@ -0,0 +1,239 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/stripe/stripe-go/v78"
+)
+
+// ErrProviderUnavailable marks a transient PSP failure. Checkout creation falls back to the next
+// provider of the matching routing rule when it sees this error.
+var ErrProviderUnavailable = errors.New("payments: provider unavailable")
+
+// Route sources reported by RouteDecision.
+const (
+	RouteSourcePreferred = "preferred"
+	RouteSourceRule      = "rule"
+	RouteSourceCurrency  = "currency"
+	RouteSourceDefault   = "default"
+	RouteSourceSingle    = "single"
+)
+
+// RoutingRule selects a provider for new payments whose context matches every populated criterion.
+// Rules are evaluated in order and the first match wins. Amounts are in minor units; the band is
+// [MinAmount, MaxAmount) and a zero MaxAmount leaves it unbounded. Rules with an amount band never
+// match a context without an amount.
+type RoutingRule struct {
+	Name           string
+	Provider       string
+	Fallbacks      []string
+	Currencies     []string
+	MinAmount      int64
+	MaxAmount      int64
+	Countries      []string
+	PaymentMethods []PaymentMethod
+	Segments       []string
+}
+
+// RouteDecision explains which provider a PaymentContext selects, in attempt order.
+type RouteDecision struct {
+	Provider  string
+	Fallbacks []string
+	Source    string
+	Rule      string
+}
+
+// Candidates returns the primary provider followed by its fallbacks.
+func (d RouteDecision) Candidates() []string {
+	out := make([]string, 0, len(d.Fallbacks)+1)
+	out = append(out, d.Provider)
+	return append(out, d.Fallbacks...)
+}
+
+// ManagerLogger receives routing diagnostics such as provider fallbacks.
+type ManagerLogger func(ctx context.Context, event string, fields map[string]any)
+
+// WithRoutingRules appends declarative routing rules evaluated before currency routes.
+func WithRoutingRules(rules ...RoutingRule) ManagerOption {
+	return func(m *Manager) {
+		for _, rule := range rules {
+			m.rules = append(m.rules, normalizeRoutingRule(rule))
+		}
+	}
+}
+
+// WithManagerLogger installs a logger for routing diagnostics.
+func WithManagerLogger(logger ManagerLogger) ManagerOption {
+	return func(m *Manager) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+// Route reports the provider, and any fallbacks, that CreateCheckoutSession would use for the context
+// without contacting a PSP.
+func (m *Manager) Route(paymentCtx PaymentContext) (RouteDecision, error) {
+	if m == nil {
+		return RouteDecision{}, errors.New("payments: manager is nil")
+	}
+	if len(m.providers) == 0 {
+		return RouteDecision{}, errors.New("payments: no providers registered")
+	}
+	if provider := strings.TrimSpace(strings.ToLower(paymentCtx.PreferredProvider)); provider != "" {
+		if _, ok := m.providers[provider]; ok {
+			return RouteDecision{Provider: provider, Source: RouteSourcePreferred}, nil
+		}
+	}
+	for _, rule := range m.rules {
+		if rule.matches(paymentCtx) {
+			return RouteDecision{
+				Provider:  rule.Provider,
+				Fallbacks: append([]string(nil), rule.Fallbacks...),
+				Source:    RouteSourceRule,
+				Rule:      rule.Name,
+			}, nil
+		}
+	}
+	currency := strings.ToUpper(strings.TrimSpace(paymentCtx.Currency))
+	if currency != "" && m.currencyRoutes != nil {
+		if providerKey, ok := m.currencyRoutes[currency]; ok {
+			provider := strings.TrimSpace(strings.ToLower(providerKey))
+			if _, ok := m.providers[provider]; ok {
+				return RouteDecision{Provider: provider, Source: RouteSourceCurrency}, nil
+			}
+		}
+	}
+	if def := strings.TrimSpace(strings.ToLower(m.defaultProvider)); def != "" {
+		if _, ok := m.providers[def]; ok {
+			return RouteDecision{Provider: def, Source: RouteSourceDefault}, nil
+		}
+	}
+	if len(m.providers) == 1 {
+		for key := range m.providers {
+			return RouteDecision{Provider: key, Source: RouteSourceSingle}, nil
+		}
+	}
+	return RouteDecision{}, ErrUnsupportedProvider
+}
+
+// IsRetryableError reports whether a provider error is transient, so that another provider may be
+// tried. Declines, validation failures and cancelled contexts are not retryable.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrProviderUnavailable) {
+		return true
+	}
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) {
+		return stripeErr.HTTPStatusCode == http.StatusTooManyRequests || stripeErr.HTTPStatusCode >= http.StatusInternalServerError ||
+			stripeErr.Type == stripe.ErrorTypeAPI
+	}
+	var paypalErr *PayPalAPIError
+	if errors.As(err, &paypalErr) {
+		return paypalErr.StatusCode == http.StatusTooManyRequests || paypalErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (m *Manager) validateRoutingRules() error {
+	for i, rule := range m.rules {
+		if rule.Provider == "" {
+			return fmt.Errorf("payments: routing rule %d (%s) has no provider", i, rule.Name)
+		}
+		for _, key := range append([]string{rule.Provider}, rule.Fallbacks...) {
+			if _, ok := m.providers[key]; !ok {
+				return fmt.Errorf("payments: routing rule %d (%s) references unknown provider %q", i, rule.Name, key)
+			}
+		}
+		if rule.MaxAmount > 0 && rule.MaxAmount <= rule.MinAmount {
+			return fmt.Errorf("payments: routing rule %d (%s) has an empty amount band", i, rule.Name)
+		}
+	}
+	return nil
+}
+
+func normalizeRoutingRule(rule RoutingRule) RoutingRule {
+	out := RoutingRule{
+		Name:      strings.TrimSpace(rule.Name),
+		Provider:  strings.ToLower(strings.TrimSpace(rule.Provider)),
+		MinAmount: rule.MinAmount,
+		MaxAmount: rule.MaxAmount,
+	}
+	for _, fallback := range rule.Fallbacks {
+		if key := strings.ToLower(strings.TrimSpace(fallback)); key != "" && key != out.Provider {
+			out.Fallbacks = append(out.Fallbacks, key)
+		}
+	}
+	out.Currencies = normalizeRuleValues(rule.Currencies, strings.ToUpper)
+	out.Countries = normalizeRuleValues(rule.Countries, strings.ToUpper)
+	out.Segments = normalizeRuleValues(rule.Segments, strings.ToLower)
+	for _, method := range rule.PaymentMethods {
+		if value := PaymentMethod(strings.ToLower(strings.TrimSpace(string(method)))); value != "" {
+			out.PaymentMethods = append(out.PaymentMethods, value)
+		}
+	}
+	return out
+}
+
+func normalizeRuleValues(values []string, fold func(string) string) []string {
+	var out []string
+	for _, value := range values {
+		if value = fold(strings.TrimSpace(value)); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func (r RoutingRule) matches(paymentCtx PaymentContext) bool {
+	if len(r.Currencies) > 0 && !containsString(r.Currencies, strings.ToUpper(strings.TrimSpace(paymentCtx.Currency))) {
+		return false
+	}
+	if r.MinAmount > 0 || r.MaxAmount > 0 {
+		amount := paymentCtx.Amount
+		if amount <= 0 || amount < r.MinAmount || (r.MaxAmount > 0 && amount >= r.MaxAmount) {
+			return false
+		}
+	}
+	if len(r.Countries) > 0 && !containsString(r.Countries, strings.ToUpper(strings.TrimSpace(paymentCtx.BillingCountry))) {
+		return false
+	}
+	if len(r.PaymentMethods) > 0 {
+		method := PaymentMethod(strings.ToLower(strings.TrimSpace(string(paymentCtx.PaymentMethod))))
+		matched := false
+		for _, candidate := range r.PaymentMethods {
+			if candidate == method {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.Segments) > 0 && !containsString(r.Segments, strings.ToLower(strings.TrimSpace(paymentCtx.UserSegment))) {
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, target string) bool {
+	if target == "" {
+		return false
+	}
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
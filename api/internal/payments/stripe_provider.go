@@ -1,6 +1,7 @@
 package payments
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -12,6 +13,12 @@ import (
 	"github.com/stripe/stripe-go/v78/client"
 )
 
+const (
+	stripeMaxKonbiniExpiryDays = 60
+	stripeJPBankTransferType   = "jp_bank_transfer"
+	stripeAsyncMethodCurrency  = "JPY"
+)
+
 // StripeLogger defines the logging contract for Stripe provider operations.
 type StripeLogger func(ctx context.Context, event string, fields map[string]any)
 
@@ -20,6 +27,7 @@ type stripeSessionAPI interface {
 }
 
 type stripePaymentIntentAPI interface {
+	New(params *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error)
 	Confirm(id string, params *stripe.PaymentIntentConfirmParams) (*stripe.PaymentIntent, error)
 	Capture(id string, params *stripe.PaymentIntentCaptureParams) (*stripe.PaymentIntent, error)
 	Get(id string, params *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error)
@@ -29,10 +37,39 @@ type stripeRefundAPI interface {
 	New(params *stripe.RefundParams) (*stripe.Refund, error)
 }
 
+type stripeCustomerAPI interface {
+	New(params *stripe.CustomerParams) (*stripe.Customer, error)
+}
+
+type stripeSetupIntentAPI interface {
+	New(params *stripe.SetupIntentParams) (*stripe.SetupIntent, error)
+}
+
+type stripePaymentMethodAPI interface {
+	Get(id string, params *stripe.PaymentMethodParams) (*stripe.PaymentMethod, error)
+	Attach(id string, params *stripe.PaymentMethodAttachParams) (*stripe.PaymentMethod, error)
+	Detach(id string, params *stripe.PaymentMethodDetachParams) (*stripe.PaymentMethod, error)
+}
+
+type stripeFileAPI interface {
+	New(params *stripe.FileParams) (*stripe.File, error)
+}
+
+type stripeDisputeAPI interface {
+	Update(id string, params *stripe.DisputeParams) (*stripe.Dispute, error)
+}
+
+// stripeClients groups the Stripe APIs used by the provider. The customer, setup intent and payment
+// method clients are only needed for saved payment methods; files and disputes for chargebacks.
 type stripeClients struct {
-	sessions stripeSessionAPI
-	intents  stripePaymentIntentAPI
-	refunds  stripeRefundAPI
+	sessions       stripeSessionAPI
+	intents        stripePaymentIntentAPI
+	refunds        stripeRefundAPI
+	customers      stripeCustomerAPI
+	setupIntents   stripeSetupIntentAPI
+	paymentMethods stripePaymentMethodAPI
+	files          stripeFileAPI
+	disputes       stripeDisputeAPI
 }
 
 // StripeProviderConfig configures the StripeProvider.
@@ -66,9 +103,14 @@ func NewStripeProvider(cfg StripeProviderConfig) (*StripeProvider, error) {
 	} else {
 		sc := client.New(apiKey, cfg.Backends)
 		clients = stripeClients{
-			sessions: sc.CheckoutSessions,
-			intents:  sc.PaymentIntents,
-			refunds:  sc.Refunds,
+			sessions:       sc.CheckoutSessions,
+			intents:        sc.PaymentIntents,
+			refunds:        sc.Refunds,
+			customers:      sc.Customers,
+			setupIntents:   sc.SetupIntents,
+			paymentMethods: sc.PaymentMethods,
+			files:          sc.Files,
+			disputes:       sc.Disputes,
 		}
 	}
 
@@ -101,6 +143,9 @@ func (p *StripeProvider) CreateCheckoutSession(ctx context.Context, req Checkout
 	if p == nil {
 		return CheckoutSession{}, errors.New("stripe: provider is nil")
 	}
+	if strings.TrimSpace(req.SavedPaymentMethodID) != "" {
+		return p.chargeSavedPaymentMethod(ctx, req)
+	}
 
 	params := &stripe.CheckoutSessionParams{
 		Mode:       stripe.String(string(stripe.CheckoutSessionModePayment)),
@@ -131,6 +176,9 @@ func (p *StripeProvider) CreateCheckoutSession(ctx context.Context, req Checkout
 	if req.AllowPromotion {
 		params.AllowPromotionCodes = stripe.Bool(true)
 	}
+	if err := applyStripePaymentMethods(params, req); err != nil {
+		return CheckoutSession{}, err
+	}
 
 	lineItems := make([]*stripe.CheckoutSessionLineItemParams, 0, len(req.Items))
 	for _, item := range req.Items {
@@ -207,7 +255,7 @@ func (p *StripeProvider) CreateCheckoutSession(ctx context.Context, req Checkout
 
 	return CheckoutSession{
 		ID:           session.ID,
-		Provider:     "stripe",
+		Provider:     ProviderStripe,
 		ClientSecret: session.ClientSecret,
 		RedirectURL:  session.URL,
 		IntentID:     intentID,
@@ -216,6 +264,329 @@ func (p *StripeProvider) CreateCheckoutSession(ctx context.Context, req Checkout
 	}, nil
 }
 
+// chargeSavedPaymentMethod creates and confirms a Payment Intent against a stored payment method
+// without the customer present. Cards that require authentication fail with ErrAuthenticationRequired
+// and carry the intent so the client can bring the customer back on-session.
+func (p *StripeProvider) chargeSavedPaymentMethod(ctx context.Context, req CheckoutSessionRequest) (CheckoutSession, error) {
+	customerID := strings.TrimSpace(req.CustomerID)
+	if customerID == "" {
+		return CheckoutSession{}, errors.New("stripe: customer id is required to charge a saved payment method")
+	}
+	if req.Amount <= 0 {
+		return CheckoutSession{}, errors.New("stripe: amount is required to charge a saved payment method")
+	}
+
+	params := &stripe.PaymentIntentParams{
+		Amount:        stripe.Int64(req.Amount),
+		Currency:      stripe.String(strings.ToLower(req.Currency)),
+		Customer:      stripe.String(customerID),
+		PaymentMethod: stripe.String(strings.TrimSpace(req.SavedPaymentMethodID)),
+		Confirm:       stripe.Bool(true),
+		OffSession:    stripe.Bool(true),
+	}
+	params.Context = ctx
+	if key := strings.TrimSpace(req.IdempotencyKey); key != "" {
+		params.SetIdempotencyKey(key)
+	}
+	if p.account != "" {
+		params.SetStripeAccount(p.account)
+	}
+	if len(req.Metadata) > 0 {
+		params.Metadata = make(map[string]string, len(req.Metadata))
+		for k, v := range req.Metadata {
+			params.Metadata[k] = v
+		}
+	}
+
+	intent, err := p.api.intents.New(params)
+	if err != nil {
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) && stripeErr.Code == stripe.ErrorCodeAuthenticationRequired {
+			intentID := ""
+			if stripeErr.PaymentIntent != nil {
+				intentID = stripeErr.PaymentIntent.ID
+			}
+			return CheckoutSession{}, fmt.Errorf("%w: payment intent %s", ErrAuthenticationRequired, intentID)
+		}
+		return CheckoutSession{}, fmt.Errorf("stripe: charge saved payment method: %w", err)
+	}
+
+	p.logger(ctx, "payments.stripe.intent.off_session", map[string]any{
+		"paymentIntent": intent.ID,
+		"status":        intent.Status,
+	})
+
+	details := stripePaymentDetails(intent)
+	return CheckoutSession{
+		ID:           intent.ID,
+		Provider:     ProviderStripe,
+		ClientSecret: intent.ClientSecret,
+		IntentID:     intent.ID,
+		Raw:          details.Raw,
+	}, nil
+}
+
+// CreateSetupSession creates a Setup Intent for off-session card reuse, creating the customer first
+// when none is supplied.
+func (p *StripeProvider) CreateSetupSession(ctx context.Context, req SetupSessionRequest) (SetupSession, error) {
+	if p == nil {
+		return SetupSession{}, errors.New("stripe: provider is nil")
+	}
+	if p.api.customers == nil || p.api.setupIntents == nil {
+		return SetupSession{}, errors.New("stripe: saved payment methods are not configured")
+	}
+
+	customerID := strings.TrimSpace(req.CustomerID)
+	if customerID == "" {
+		params := &stripe.CustomerParams{}
+		params.Context = ctx
+		if key := strings.TrimSpace(req.IdempotencyKey); key != "" {
+			params.SetIdempotencyKey(key + ":customer")
+		}
+		if p.account != "" {
+			params.SetStripeAccount(p.account)
+		}
+		if email := strings.TrimSpace(req.Email); email != "" {
+			params.Email = stripe.String(email)
+		}
+		if name := strings.TrimSpace(req.Name); name != "" {
+			params.Name = stripe.String(name)
+		}
+		if len(req.Metadata) > 0 {
+			params.Metadata = make(map[string]string, len(req.Metadata))
+			for k, v := range req.Metadata {
+				params.Metadata[k] = v
+			}
+		}
+		customer, err := p.api.customers.New(params)
+		if err != nil {
+			return SetupSession{}, fmt.Errorf("stripe: create customer: %w", err)
+		}
+		customerID = customer.ID
+	}
+
+	params := &stripe.SetupIntentParams{
+		Customer:           stripe.String(customerID),
+		Usage:              stripe.String(string(stripe.SetupIntentUsageOffSession)),
+		PaymentMethodTypes: []*string{stripe.String("card")},
+	}
+	params.Context = ctx
+	if key := strings.TrimSpace(req.IdempotencyKey); key != "" {
+		params.SetIdempotencyKey(key)
+	}
+	if p.account != "" {
+		params.SetStripeAccount(p.account)
+	}
+	if len(req.Metadata) > 0 {
+		params.Metadata = make(map[string]string, len(req.Metadata))
+		for k, v := range req.Metadata {
+			params.Metadata[k] = v
+		}
+	}
+	intent, err := p.api.setupIntents.New(params)
+	if err != nil {
+		return SetupSession{}, fmt.Errorf("stripe: create setup intent: %w", err)
+	}
+
+	p.logger(ctx, "payments.stripe.setup_intent.created", map[string]any{
+		"setupIntent": intent.ID,
+		"customer":    customerID,
+	})
+
+	raw := map[string]any{}
+	if data, err := json.Marshal(intent); err == nil {
+		_ = json.Unmarshal(data, &raw)
+	}
+	return SetupSession{
+		ID:           intent.ID,
+		Provider:     ProviderStripe,
+		CustomerID:   customerID,
+		ClientSecret: intent.ClientSecret,
+		Raw:          raw,
+	}, nil
+}
+
+// AttachPaymentMethod attaches the payment method to the customer and returns its card details.
+// Methods already attached by a completed Setup Intent are returned as-is.
+func (p *StripeProvider) AttachPaymentMethod(ctx context.Context, req AttachPaymentMethodRequest) (SavedPaymentMethod, error) {
+	if p == nil {
+		return SavedPaymentMethod{}, errors.New("stripe: provider is nil")
+	}
+	if p.api.paymentMethods == nil {
+		return SavedPaymentMethod{}, errors.New("stripe: saved payment methods are not configured")
+	}
+	customerID := strings.TrimSpace(req.CustomerID)
+	methodID := strings.TrimSpace(req.PaymentMethodID)
+	if customerID == "" || methodID == "" {
+		return SavedPaymentMethod{}, errors.New("stripe: customer id and payment method id are required")
+	}
+
+	getParams := &stripe.PaymentMethodParams{}
+	getParams.Context = ctx
+	if p.account != "" {
+		getParams.SetStripeAccount(p.account)
+	}
+	method, err := p.api.paymentMethods.Get(methodID, getParams)
+	if err != nil {
+		return SavedPaymentMethod{}, fmt.Errorf("stripe: lookup payment method: %w", err)
+	}
+
+	if method.Customer == nil || method.Customer.ID != customerID {
+		if method.Customer != nil {
+			return SavedPaymentMethod{}, errors.New("stripe: payment method belongs to another customer")
+		}
+		params := &stripe.PaymentMethodAttachParams{Customer: stripe.String(customerID)}
+		params.Context = ctx
+		if key := strings.TrimSpace(req.IdempotencyKey); key != "" {
+			params.SetIdempotencyKey(key)
+		}
+		if p.account != "" {
+			params.SetStripeAccount(p.account)
+		}
+		method, err = p.api.paymentMethods.Attach(methodID, params)
+		if err != nil {
+			return SavedPaymentMethod{}, fmt.Errorf("stripe: attach payment method: %w", err)
+		}
+	}
+
+	saved := SavedPaymentMethod{
+		Provider:        ProviderStripe,
+		PaymentMethodID: method.ID,
+		CustomerID:      customerID,
+	}
+	if card := method.Card; card != nil {
+		saved.Brand = string(card.Brand)
+		saved.Last4 = card.Last4
+		saved.ExpMonth = int(card.ExpMonth)
+		saved.ExpYear = int(card.ExpYear)
+	}
+	return saved, nil
+}
+
+// DetachPaymentMethod detaches the payment method from its customer so it can no longer be charged.
+func (p *StripeProvider) DetachPaymentMethod(ctx context.Context, req DetachPaymentMethodRequest) error {
+	if p == nil {
+		return errors.New("stripe: provider is nil")
+	}
+	if p.api.paymentMethods == nil {
+		return errors.New("stripe: saved payment methods are not configured")
+	}
+	methodID := strings.TrimSpace(req.PaymentMethodID)
+	if methodID == "" {
+		return errors.New("stripe: payment method id is required")
+	}
+	params := &stripe.PaymentMethodDetachParams{}
+	params.Context = ctx
+	if p.account != "" {
+		params.SetStripeAccount(p.account)
+	}
+	if _, err := p.api.paymentMethods.Detach(methodID, params); err != nil {
+		return fmt.Errorf("stripe: detach payment method: %w", err)
+	}
+	p.logger(ctx, "payments.stripe.payment_method.detached", map[string]any{"paymentMethod": methodID})
+	return nil
+}
+
+// SubmitDisputeEvidence uploads the evidence files and submits them with the explanation. Stripe
+// accepts a single file per evidence kind, so callers should merge documents of the same kind.
+func (p *StripeProvider) SubmitDisputeEvidence(ctx context.Context, req DisputeEvidenceRequest) (DisputeDetails, error) {
+	if p == nil {
+		return DisputeDetails{}, errors.New("stripe: provider is nil")
+	}
+	if p.api.files == nil || p.api.disputes == nil {
+		return DisputeDetails{}, errors.New("stripe: dispute clients are not configured")
+	}
+	disputeID := strings.TrimSpace(req.DisputeID)
+	if disputeID == "" {
+		return DisputeDetails{}, fmt.Errorf("%w: dispute id is required", ErrDisputeEvidenceInvalid)
+	}
+
+	evidence := &stripe.DisputeEvidenceParams{}
+	if text := strings.TrimSpace(req.Explanation); text != "" {
+		evidence.UncategorizedText = stripe.String(text)
+	}
+	seen := make(map[string]struct{}, len(req.Files))
+	for _, file := range req.Files {
+		kind := strings.TrimSpace(file.Kind)
+		if _, ok := seen[kind]; ok {
+			return DisputeDetails{}, fmt.Errorf("%w: only one %s file is accepted", ErrDisputeEvidenceInvalid, kind)
+		}
+		seen[kind] = struct{}{}
+
+		var field **string
+		switch kind {
+		case DisputeEvidenceReceipt:
+			field = &evidence.Receipt
+		case DisputeEvidenceShippingDocumentation:
+			field = &evidence.ShippingDocumentation
+		case DisputeEvidenceCustomerCommunication:
+			field = &evidence.CustomerCommunication
+		case DisputeEvidenceUncategorized:
+			field = &evidence.UncategorizedFile
+		default:
+			return DisputeDetails{}, fmt.Errorf("%w: unsupported evidence kind %q", ErrDisputeEvidenceInvalid, file.Kind)
+		}
+
+		params := &stripe.FileParams{
+			FileReader: bytes.NewReader(file.Content),
+			Filename:   stripe.String(defaultString(file.FileName, kind)),
+			Purpose:    stripe.String(string(stripe.FilePurposeDisputeEvidence)),
+		}
+		params.Context = ctx
+		if p.account != "" {
+			params.SetStripeAccount(p.account)
+		}
+		uploaded, err := p.api.files.New(params)
+		if err != nil {
+			return DisputeDetails{}, fmt.Errorf("stripe: upload dispute evidence: %w", err)
+		}
+		*field = stripe.String(uploaded.ID)
+	}
+
+	params := &stripe.DisputeParams{
+		Evidence: evidence,
+		Submit:   stripe.Bool(true),
+	}
+	params.Context = ctx
+	if key := strings.TrimSpace(req.IdempotencyKey); key != "" {
+		params.SetIdempotencyKey(key)
+	}
+	if p.account != "" {
+		params.SetStripeAccount(p.account)
+	}
+	if len(req.Metadata) > 0 {
+		params.Metadata = make(map[string]string, len(req.Metadata))
+		for k, v := range req.Metadata {
+			params.Metadata[k] = v
+		}
+	}
+	dispute, err := p.api.disputes.Update(disputeID, params)
+	if err != nil {
+		return DisputeDetails{}, fmt.Errorf("stripe: submit dispute evidence: %w", err)
+	}
+
+	p.logger(ctx, "payments.stripe.dispute.evidence_submitted", map[string]any{
+		"dispute": dispute.ID,
+		"status":  dispute.Status,
+		"files":   len(req.Files),
+	})
+
+	details := DisputeDetails{
+		Provider:  ProviderStripe,
+		DisputeID: dispute.ID,
+		Status:    string(dispute.Status),
+		Raw:       map[string]any{},
+	}
+	if dispute.EvidenceDetails != nil && dispute.EvidenceDetails.DueBy > 0 {
+		due := time.Unix(dispute.EvidenceDetails.DueBy, 0).UTC()
+		details.EvidenceDueBy = &due
+	}
+	if data, err := json.Marshal(dispute); err == nil {
+		_ = json.Unmarshal(data, &details.Raw)
+	}
+	return details, nil
+}
+
 // Confirm confirms a Stripe Payment Intent.
 func (p *StripeProvider) Confirm(ctx context.Context, req ConfirmRequest) (PaymentDetails, error) {
 	if p == nil {
@@ -343,6 +714,7 @@ func stripePaymentDetails(intent *stripe.PaymentIntent) PaymentDetails {
 
 	var capturedAt *time.Time
 	var refundedAt *time.Time
+	var refunded int64
 	captured := intent.Status == stripe.PaymentIntentStatusSucceeded
 
 	if charge := intent.LatestCharge; charge != nil {
@@ -354,6 +726,7 @@ func stripePaymentDetails(intent *stripe.PaymentIntent) PaymentDetails {
 		if charge.Refunded || charge.AmountRefunded > 0 {
 			t := time.Unix(charge.Created, 0).UTC()
 			refundedAt = &t
+			refunded = charge.AmountRefunded
 			if charge.AmountRefunded >= charge.Amount && charge.Amount > 0 {
 				status = StatusRefunded
 			}
@@ -375,17 +748,144 @@ func stripePaymentDetails(intent *stripe.PaymentIntent) PaymentDetails {
 	} else {
 		raw["payment_intent"] = intent
 	}
+	if async := stripeAsyncPaymentDetails(intent.NextAction); async != nil {
+		raw[AsyncPaymentRawKey] = async
+	}
 
 	return PaymentDetails{
-		Provider:   "stripe",
-		IntentID:   intent.ID,
-		Status:     status,
-		Amount:     intent.Amount,
-		Currency:   currency,
-		Captured:   captured,
-		CapturedAt: capturedAt,
-		RefundedAt: refundedAt,
-		Raw:        raw,
+		Provider:       ProviderStripe,
+		IntentID:       intent.ID,
+		Status:         status,
+		Amount:         intent.Amount,
+		Currency:       currency,
+		Captured:       captured,
+		CapturedAt:     capturedAt,
+		RefundedAt:     refundedAt,
+		Raw:            raw,
+		AmountRefunded: refunded,
+	}
+}
+
+// applyStripePaymentMethods maps the requested payment methods onto the session. Konbini and bank
+// transfer settle asynchronously, so the session completes unpaid and the order waits for the
+// checkout.session.async_payment_* webhooks.
+func applyStripePaymentMethods(params *stripe.CheckoutSessionParams, req CheckoutSessionRequest) error {
+	if req.KonbiniExpiresAfterDays < 0 || req.KonbiniExpiresAfterDays > stripeMaxKonbiniExpiryDays {
+		return fmt.Errorf("%w: konbini expiry must be between 1 and %d days when set", ErrUnsupportedPaymentMethod, stripeMaxKonbiniExpiryDays)
+	}
+
+	seen := make(map[PaymentMethod]struct{}, len(req.PaymentMethods))
+	for _, requested := range req.PaymentMethods {
+		method := PaymentMethod(strings.ToLower(strings.TrimSpace(string(requested))))
+		if _, ok := seen[method]; ok {
+			continue
+		}
+		seen[method] = struct{}{}
+
+		switch method {
+		case PaymentMethodCard:
+			params.PaymentMethodTypes = append(params.PaymentMethodTypes, stripe.String("card"))
+		case PaymentMethodKonbini, PaymentMethodBankTransfer:
+			if !strings.EqualFold(strings.TrimSpace(req.Currency), stripeAsyncMethodCurrency) {
+				return fmt.Errorf("%w: %s requires %s", ErrUnsupportedPaymentMethod, method, stripeAsyncMethodCurrency)
+			}
+			if params.PaymentMethodOptions == nil {
+				params.PaymentMethodOptions = &stripe.CheckoutSessionPaymentMethodOptionsParams{}
+			}
+			if method == PaymentMethodKonbini {
+				params.PaymentMethodTypes = append(params.PaymentMethodTypes, stripe.String("konbini"))
+				options := &stripe.CheckoutSessionPaymentMethodOptionsKonbiniParams{}
+				if req.KonbiniExpiresAfterDays > 0 {
+					options.ExpiresAfterDays = stripe.Int64(int64(req.KonbiniExpiresAfterDays))
+				}
+				params.PaymentMethodOptions.Konbini = options
+				continue
+			}
+			// Bank transfers fund the customer balance, which requires a Stripe customer.
+			params.PaymentMethodTypes = append(params.PaymentMethodTypes, stripe.String("customer_balance"))
+			params.PaymentMethodOptions.CustomerBalance = &stripe.CheckoutSessionPaymentMethodOptionsCustomerBalanceParams{
+				FundingType: stripe.String("bank_transfer"),
+				BankTransfer: &stripe.CheckoutSessionPaymentMethodOptionsCustomerBalanceBankTransferParams{
+					Type: stripe.String(stripeJPBankTransferType),
+				},
+			}
+			if params.Customer == nil {
+				params.CustomerCreation = stripe.String(string(stripe.CheckoutSessionCustomerCreationAlways))
+			}
+		default:
+			return fmt.Errorf("%w: %q", ErrUnsupportedPaymentMethod, requested)
+		}
+	}
+	return nil
+}
+
+// stripeAsyncPaymentDetails extracts the voucher or transfer instructions the customer needs to
+// complete a konbini or bank transfer payment.
+func stripeAsyncPaymentDetails(action *stripe.PaymentIntentNextAction) map[string]any {
+	if action == nil {
+		return nil
+	}
+
+	if konbini := action.KonbiniDisplayDetails; konbini != nil {
+		details := map[string]any{
+			"method":             string(PaymentMethodKonbini),
+			"hosted_voucher_url": konbini.HostedVoucherURL,
+		}
+		if konbini.ExpiresAt > 0 {
+			details["expires_at"] = time.Unix(konbini.ExpiresAt, 0).UTC().Format(time.RFC3339)
+		}
+		stores := map[string]any{}
+		if s := konbini.Stores; s != nil {
+			if s.FamilyMart != nil {
+				stores["familymart"] = konbiniStoreDetails(s.FamilyMart.ConfirmationNumber, s.FamilyMart.PaymentCode)
+			}
+			if s.Lawson != nil {
+				stores["lawson"] = konbiniStoreDetails(s.Lawson.ConfirmationNumber, s.Lawson.PaymentCode)
+			}
+			if s.Ministop != nil {
+				stores["ministop"] = konbiniStoreDetails(s.Ministop.ConfirmationNumber, s.Ministop.PaymentCode)
+			}
+			if s.Seicomart != nil {
+				stores["seicomart"] = konbiniStoreDetails(s.Seicomart.ConfirmationNumber, s.Seicomart.PaymentCode)
+			}
+		}
+		details["stores"] = stores
+		return details
+	}
+
+	if transfer := action.DisplayBankTransferInstructions; transfer != nil {
+		accounts := make([]map[string]any, 0, len(transfer.FinancialAddresses))
+		for _, address := range transfer.FinancialAddresses {
+			if address == nil || address.Zengin == nil {
+				continue
+			}
+			accounts = append(accounts, map[string]any{
+				"account_holder_name": address.Zengin.AccountHolderName,
+				"account_number":      address.Zengin.AccountNumber,
+				"account_type":        address.Zengin.AccountType,
+				"bank_code":           address.Zengin.BankCode,
+				"bank_name":           address.Zengin.BankName,
+				"branch_code":         address.Zengin.BranchCode,
+				"branch_name":         address.Zengin.BranchName,
+			})
+		}
+		return map[string]any{
+			"method":                  string(PaymentMethodBankTransfer),
+			"hosted_instructions_url": transfer.HostedInstructionsURL,
+			"reference":               transfer.Reference,
+			"amount_remaining":        transfer.AmountRemaining,
+			"currency":                strings.ToUpper(string(transfer.Currency)),
+			"bank_accounts":           accounts,
+		}
+	}
+
+	return nil
+}
+
+func konbiniStoreDetails(confirmationNumber, paymentCode string) map[string]any {
+	return map[string]any{
+		"confirmation_number": confirmationNumber,
+		"payment_code":        paymentCode,
 	}
 }
 
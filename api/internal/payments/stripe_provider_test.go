@@ -0,0 +1,358 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v78"
+)
+
+type recordingStripeSessions struct {
+	params []*stripe.CheckoutSessionParams
+}
+
+func (r *recordingStripeSessions) New(params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error) {
+	r.params = append(r.params, params)
+	return &stripe.CheckoutSession{ID: "cs_1", PaymentIntent: &stripe.PaymentIntent{ID: "pi_1"}}, nil
+}
+
+type unusedStripeIntents struct{}
+
+func (unusedStripeIntents) New(*stripe.PaymentIntentParams) (*stripe.PaymentIntent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (unusedStripeIntents) Confirm(string, *stripe.PaymentIntentConfirmParams) (*stripe.PaymentIntent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (unusedStripeIntents) Capture(string, *stripe.PaymentIntentCaptureParams) (*stripe.PaymentIntent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (unusedStripeIntents) Get(string, *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error) {
+	return nil, errors.New("not implemented")
+}
+
+type unusedStripeRefunds struct{}
+
+func (unusedStripeRefunds) New(*stripe.RefundParams) (*stripe.Refund, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestStripeProvider_CheckoutSessionAsyncPaymentMethods(t *testing.T) {
+	sessions := &recordingStripeSessions{}
+	provider, err := NewStripeProvider(StripeProviderConfig{
+		Clients: &stripeClients{sessions: sessions, intents: unusedStripeIntents{}, refunds: unusedStripeRefunds{}},
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{
+		Amount:                  4800,
+		Currency:                "JPY",
+		SuccessURL:              "https://example.com/ok",
+		CancelURL:               "https://example.com/cancel",
+		PaymentMethods:          []PaymentMethod{PaymentMethodCard, PaymentMethodKonbini, PaymentMethodBankTransfer, PaymentMethodKonbini},
+		KonbiniExpiresAfterDays: 5,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	params := sessions.params[0]
+	var types []string
+	for _, pmType := range params.PaymentMethodTypes {
+		types = append(types, *pmType)
+	}
+	if len(types) != 3 || types[0] != "card" || types[1] != "konbini" || types[2] != "customer_balance" {
+		t.Fatalf("unexpected payment method types %v", types)
+	}
+	if got := params.PaymentMethodOptions.Konbini.ExpiresAfterDays; got == nil || *got != 5 {
+		t.Fatalf("expected konbini expiry of 5 days, got %v", got)
+	}
+	balance := params.PaymentMethodOptions.CustomerBalance
+	if *balance.FundingType != "bank_transfer" || *balance.BankTransfer.Type != "jp_bank_transfer" {
+		t.Fatalf("unexpected customer balance options %+v", balance)
+	}
+	if params.CustomerCreation == nil || *params.CustomerCreation != "always" {
+		t.Fatalf("expected customer creation for bank transfer without customer")
+	}
+
+	_, err = provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Amount: 1000, Currency: "USD", PaymentMethods: []PaymentMethod{PaymentMethodKonbini}})
+	if !errors.Is(err, ErrUnsupportedPaymentMethod) {
+		t.Fatalf("expected konbini to require JPY, got %v", err)
+	}
+	_, err = provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Amount: 1000, Currency: "JPY", PaymentMethods: []PaymentMethod{"alipay"}})
+	if !errors.Is(err, ErrUnsupportedPaymentMethod) {
+		t.Fatalf("expected unknown method to be rejected, got %v", err)
+	}
+	if len(sessions.params) != 1 {
+		t.Fatalf("rejected requests must not reach stripe, got %d calls", len(sessions.params))
+	}
+}
+
+func TestStripePaymentDetails_AsyncInstructions(t *testing.T) {
+	expires := time.Date(2024, 7, 4, 14, 59, 59, 0, time.UTC)
+	konbini := stripePaymentDetails(&stripe.PaymentIntent{
+		ID:       "pi_1",
+		Status:   stripe.PaymentIntentStatusRequiresAction,
+		Amount:   4800,
+		Currency: stripe.CurrencyJPY,
+		NextAction: &stripe.PaymentIntentNextAction{
+			KonbiniDisplayDetails: &stripe.PaymentIntentNextActionKonbiniDisplayDetails{
+				ExpiresAt:        expires.Unix(),
+				HostedVoucherURL: "https://payments.stripe.com/konbini/voucher/1",
+				Stores: &stripe.PaymentIntentNextActionKonbiniDisplayDetailsStores{
+					Lawson: &stripe.PaymentIntentNextActionKonbiniDisplayDetailsStoresLawson{ConfirmationNumber: "11111", PaymentCode: "123456"},
+				},
+			},
+		},
+	})
+	if konbini.Status != StatusPending || konbini.Captured {
+		t.Fatalf("konbini awaiting payment must stay pending, got %+v", konbini)
+	}
+	voucher, ok := konbini.Raw[AsyncPaymentRawKey].(map[string]any)
+	if !ok {
+		t.Fatalf("expected voucher details in raw, got %v", konbini.Raw)
+	}
+	if voucher["method"] != "konbini" || voucher["expires_at"] != "2024-07-04T14:59:59Z" || voucher["hosted_voucher_url"] == "" {
+		t.Fatalf("unexpected voucher details %v", voucher)
+	}
+	if lawson := voucher["stores"].(map[string]any)["lawson"].(map[string]any); lawson["payment_code"] != "123456" {
+		t.Fatalf("unexpected store details %v", lawson)
+	}
+
+	transfer := stripePaymentDetails(&stripe.PaymentIntent{
+		ID:       "pi_2",
+		Status:   stripe.PaymentIntentStatusRequiresAction,
+		Amount:   9000,
+		Currency: stripe.CurrencyJPY,
+		NextAction: &stripe.PaymentIntentNextAction{
+			DisplayBankTransferInstructions: &stripe.PaymentIntentNextActionDisplayBankTransferInstructions{
+				AmountRemaining: 9000,
+				Currency:        stripe.CurrencyJPY,
+				Reference:       "REF123",
+				FinancialAddresses: []*stripe.PaymentIntentNextActionDisplayBankTransferInstructionsFinancialAddress{
+					{Zengin: &stripe.PaymentIntentNextActionDisplayBankTransferInstructionsFinancialAddressZengin{BankName: "Test Bank", AccountNumber: "1234567"}},
+				},
+			},
+		},
+	})
+	instructions := transfer.Raw[AsyncPaymentRawKey].(map[string]any)
+	accounts := instructions["bank_accounts"].([]map[string]any)
+	if instructions["method"] != "bank_transfer" || instructions["amount_remaining"] != int64(9000) || len(accounts) != 1 || accounts[0]["account_number"] != "1234567" {
+		t.Fatalf("unexpected transfer instructions %v", instructions)
+	}
+}
+
+type recordingStripeIntents struct {
+	unusedStripeIntents
+	params []*stripe.PaymentIntentParams
+	err    error
+}
+
+func (r *recordingStripeIntents) New(params *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error) {
+	r.params = append(r.params, params)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &stripe.PaymentIntent{ID: "pi_saved", Status: stripe.PaymentIntentStatusSucceeded, Amount: *params.Amount, Currency: stripe.CurrencyJPY}, nil
+}
+
+type fakeStripeVault struct {
+	customers []*stripe.CustomerParams
+	setups    []*stripe.SetupIntentParams
+	methods   map[string]*stripe.PaymentMethod
+	attached  []string
+	detached  []string
+}
+
+func (f *fakeStripeVault) newCustomer(params *stripe.CustomerParams) (*stripe.Customer, error) {
+	f.customers = append(f.customers, params)
+	return &stripe.Customer{ID: "cus_new"}, nil
+}
+
+func (f *fakeStripeVault) newSetupIntent(params *stripe.SetupIntentParams) (*stripe.SetupIntent, error) {
+	f.setups = append(f.setups, params)
+	return &stripe.SetupIntent{ID: "seti_1", ClientSecret: "seti_1_secret", Customer: &stripe.Customer{ID: *params.Customer}}, nil
+}
+
+func (f *fakeStripeVault) Get(id string, _ *stripe.PaymentMethodParams) (*stripe.PaymentMethod, error) {
+	method, ok := f.methods[id]
+	if !ok {
+		return nil, &stripe.Error{Code: stripe.ErrorCodeResourceMissing, Msg: "no such payment method"}
+	}
+	return method, nil
+}
+
+func (f *fakeStripeVault) Attach(id string, params *stripe.PaymentMethodAttachParams) (*stripe.PaymentMethod, error) {
+	f.attached = append(f.attached, id)
+	method := f.methods[id]
+	method.Customer = &stripe.Customer{ID: *params.Customer}
+	return method, nil
+}
+
+func (f *fakeStripeVault) Detach(id string, _ *stripe.PaymentMethodDetachParams) (*stripe.PaymentMethod, error) {
+	f.detached = append(f.detached, id)
+	method := f.methods[id]
+	method.Customer = nil
+	return method, nil
+}
+
+type stripeCustomerFunc func(*stripe.CustomerParams) (*stripe.Customer, error)
+
+func (fn stripeCustomerFunc) New(params *stripe.CustomerParams) (*stripe.Customer, error) {
+	return fn(params)
+}
+
+type stripeSetupIntentFunc func(*stripe.SetupIntentParams) (*stripe.SetupIntent, error)
+
+func (fn stripeSetupIntentFunc) New(params *stripe.SetupIntentParams) (*stripe.SetupIntent, error) {
+	return fn(params)
+}
+
+func TestStripeProvider_SavedPaymentMethods(t *testing.T) {
+	vault := &fakeStripeVault{methods: map[string]*stripe.PaymentMethod{
+		"pm_1": {ID: "pm_1", Card: &stripe.PaymentMethodCard{Brand: stripe.PaymentMethodCardBrandVisa, Last4: "4242", ExpMonth: 12, ExpYear: 2030}},
+		"pm_2": {ID: "pm_2", Customer: &stripe.Customer{ID: "cus_other"}},
+	}}
+	intents := &recordingStripeIntents{}
+	provider, err := NewStripeProvider(StripeProviderConfig{
+		Clients: &stripeClients{
+			sessions:       &recordingStripeSessions{},
+			intents:        intents,
+			refunds:        unusedStripeRefunds{},
+			customers:      stripeCustomerFunc(vault.newCustomer),
+			setupIntents:   stripeSetupIntentFunc(vault.newSetupIntent),
+			paymentMethods: vault,
+		},
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	ctx := context.Background()
+
+	setup, err := provider.CreateSetupSession(ctx, SetupSessionRequest{Email: "user@example.com", Metadata: map[string]string{"userId": "u1"}})
+	if err != nil {
+		t.Fatalf("create setup session: %v", err)
+	}
+	if setup.CustomerID != "cus_new" || setup.ClientSecret != "seti_1_secret" || len(vault.customers) != 1 {
+		t.Fatalf("unexpected setup session %+v", setup)
+	}
+	if usage := vault.setups[0].Usage; usage == nil || *usage != "off_session" {
+		t.Fatalf("expected off_session setup intent, got %v", usage)
+	}
+	if _, err := provider.CreateSetupSession(ctx, SetupSessionRequest{CustomerID: "cus_new"}); err != nil || len(vault.customers) != 1 {
+		t.Fatalf("existing customer must be reused, err=%v customers=%d", err, len(vault.customers))
+	}
+
+	saved, err := provider.AttachPaymentMethod(ctx, AttachPaymentMethodRequest{CustomerID: "cus_new", PaymentMethodID: "pm_1"})
+	if err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if saved.Brand != "visa" || saved.Last4 != "4242" || saved.ExpMonth != 12 || saved.ExpYear != 2030 || len(vault.attached) != 1 {
+		t.Fatalf("unexpected saved method %+v", saved)
+	}
+	if _, err := provider.AttachPaymentMethod(ctx, AttachPaymentMethodRequest{CustomerID: "cus_new", PaymentMethodID: "pm_1"}); err != nil || len(vault.attached) != 1 {
+		t.Fatalf("already attached method must not be attached again, err=%v", err)
+	}
+	if _, err := provider.AttachPaymentMethod(ctx, AttachPaymentMethodRequest{CustomerID: "cus_new", PaymentMethodID: "pm_2"}); err == nil {
+		t.Fatalf("expected method owned by another customer to be rejected")
+	}
+
+	session, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Amount: 3000, Currency: "JPY", CustomerID: "cus_new", SavedPaymentMethodID: "pm_1"})
+	if err != nil {
+		t.Fatalf("charge saved method: %v", err)
+	}
+	params := intents.params[0]
+	if session.IntentID != "pi_saved" || !*params.OffSession || !*params.Confirm || *params.PaymentMethod != "pm_1" || *params.Customer != "cus_new" {
+		t.Fatalf("unexpected off-session charge %+v", params)
+	}
+
+	intents.err = &stripe.Error{Code: stripe.ErrorCodeAuthenticationRequired, PaymentIntent: &stripe.PaymentIntent{ID: "pi_auth"}}
+	if _, err := provider.CreateCheckoutSession(ctx, CheckoutSessionRequest{Amount: 3000, Currency: "JPY", CustomerID: "cus_new", SavedPaymentMethodID: "pm_1"}); !errors.Is(err, ErrAuthenticationRequired) {
+		t.Fatalf("expected authentication required, got %v", err)
+	}
+
+	if err := provider.DetachPaymentMethod(ctx, DetachPaymentMethodRequest{PaymentMethodID: "pm_1"}); err != nil || len(vault.detached) != 1 {
+		t.Fatalf("detach: %v", err)
+	}
+}
+
+type recordingStripeDisputes struct {
+	uploads []*stripe.FileParams
+	updated *stripe.DisputeParams
+}
+
+func (r *recordingStripeDisputes) New(params *stripe.FileParams) (*stripe.File, error) {
+	r.uploads = append(r.uploads, params)
+	return &stripe.File{ID: fmt.Sprintf("file_%d", len(r.uploads))}, nil
+}
+
+func (r *recordingStripeDisputes) Update(id string, params *stripe.DisputeParams) (*stripe.Dispute, error) {
+	r.updated = params
+	return &stripe.Dispute{
+		ID:              id,
+		Status:          stripe.DisputeStatusUnderReview,
+		EvidenceDetails: &stripe.DisputeEvidenceDetails{DueBy: 1767225600},
+	}, nil
+}
+
+func TestStripeProvider_SubmitDisputeEvidence(t *testing.T) {
+	disputes := &recordingStripeDisputes{}
+	provider, err := NewStripeProvider(StripeProviderConfig{
+		Clients: &stripeClients{
+			sessions: &recordingStripeSessions{},
+			intents:  unusedStripeIntents{},
+			refunds:  unusedStripeRefunds{},
+			files:    disputes,
+			disputes: disputes,
+		},
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	ctx := context.Background()
+
+	details, err := provider.SubmitDisputeEvidence(ctx, DisputeEvidenceRequest{
+		DisputeID:   "dp_1",
+		Explanation: "Delivered with signature.",
+		Files: []DisputeEvidenceFile{
+			{Kind: DisputeEvidenceReceipt, FileName: "receipt.pdf", Content: []byte("pdf")},
+			{Kind: DisputeEvidenceShippingDocumentation, FileName: "tracking.pdf", Content: []byte("pdf")},
+		},
+		IdempotencyKey: "dispute-evidence-1",
+	})
+	if err != nil {
+		t.Fatalf("submit evidence: %v", err)
+	}
+	if len(disputes.uploads) != 2 || *disputes.uploads[0].Purpose != string(stripe.FilePurposeDisputeEvidence) {
+		t.Fatalf("expected two dispute evidence uploads, got %d", len(disputes.uploads))
+	}
+	evidence := disputes.updated.Evidence
+	if *evidence.Receipt != "file_1" || *evidence.ShippingDocumentation != "file_2" || *evidence.UncategorizedText != "Delivered with signature." {
+		t.Fatalf("unexpected evidence mapping %+v", evidence)
+	}
+	if disputes.updated.Submit == nil || !*disputes.updated.Submit {
+		t.Fatalf("expected evidence to be submitted")
+	}
+	if details.Status != "under_review" || details.EvidenceDueBy == nil || details.EvidenceDueBy.Unix() != 1767225600 {
+		t.Fatalf("unexpected details %+v", details)
+	}
+
+	_, err = provider.SubmitDisputeEvidence(ctx, DisputeEvidenceRequest{
+		DisputeID: "dp_1",
+		Files: []DisputeEvidenceFile{
+			{Kind: DisputeEvidenceReceipt, Content: []byte("a")},
+			{Kind: DisputeEvidenceReceipt, Content: []byte("b")},
+		},
+	})
+	if !errors.Is(err, ErrDisputeEvidenceInvalid) {
+		t.Fatalf("expected duplicate kinds to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,632 @@
+// Package stripetest provides an in-process stand-in for the Stripe API so payment flows built on
+// payments.StripeProvider can run in CI without network access.
+package stripetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stripe/stripe-go/v78"
+
+	"github.com/hanko-field/api/internal/payments"
+)
+
+const (
+	intentRequiresPaymentMethod = "requires_payment_method"
+	intentRequiresAction        = "requires_action"
+	intentRequiresCapture       = "requires_capture"
+	intentSucceeded             = "succeeded"
+)
+
+// Option customises the Server.
+type Option func(*Server)
+
+// WithOutcome sets how payment intent confirmations resolve. Defaults to payments.FakeOutcomeSucceed.
+func WithOutcome(outcome payments.FakeOutcome) Option {
+	return func(s *Server) {
+		if outcome != "" {
+			s.outcome = outcome
+		}
+	}
+}
+
+// WithManualCapture makes confirmed intents wait in requires_capture until captured explicitly.
+func WithManualCapture() Option {
+	return func(s *Server) {
+		s.manualCapture = true
+	}
+}
+
+// WithClock injects the clock used for created/expiry timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		if now != nil {
+			s.now = now
+		}
+	}
+}
+
+// WithWebhookEmitter delivers an event for every intent and refund state change.
+func WithWebhookEmitter(emitter *WebhookEmitter) Option {
+	return func(s *Server) {
+		s.emitter = emitter
+	}
+}
+
+// Delivery records a webhook event emitted by the Server and the outcome of sending it.
+type Delivery struct {
+	Event      Event
+	StatusCode int
+	Err        error
+}
+
+// Server emulates the subset of the Stripe API used by payments.StripeProvider: checkout sessions,
+// payment intent confirm/capture/retrieve and refunds. Identifiers are sequential and deterministic.
+type Server struct {
+	srv *httptest.Server
+
+	mu            sync.Mutex
+	outcome       payments.FakeOutcome
+	manualCapture bool
+	now           func() time.Time
+	emitter       *WebhookEmitter
+	seq           int
+	sessions      map[string]*checkoutSession
+	intents       map[string]*paymentIntent
+	idempotent    map[string]recordedResponse
+	deliveries    []Delivery
+}
+
+type checkoutSession struct {
+	id        string
+	intentID  string
+	amount    int64
+	currency  string
+	metadata  map[string]string
+	created   int64
+	expiresAt int64
+}
+
+type paymentIntent struct {
+	id             string
+	amount         int64
+	amountReceived int64
+	amountRefunded int64
+	amountReleased int64
+	currency       string
+	status         string
+	captureMethod  string
+	metadata       map[string]string
+	created        int64
+	chargeID       string
+	lastError      map[string]any
+}
+
+type recordedResponse struct {
+	status int
+	body   []byte
+}
+
+// NewServer starts the stand-in. Call Close when finished.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		outcome:    payments.FakeOutcomeSucceed,
+		now:        time.Now,
+		sessions:   make(map[string]*checkoutSession),
+		intents:    make(map[string]*paymentIntent),
+		idempotent: make(map[string]recordedResponse),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Post("/v1/checkout/sessions", s.handleCreateSession)
+	r.Get("/v1/checkout/sessions/{id}", s.handleGetSession)
+	r.Get("/v1/payment_intents/{id}", s.handleGetIntent)
+	r.Post("/v1/payment_intents/{id}/confirm", s.handleConfirmIntent)
+	r.Post("/v1/payment_intents/{id}/capture", s.handleCaptureIntent)
+	r.Post("/v1/refunds", s.handleCreateRefund)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeStripeError(w, http.StatusNotFound, "invalid_request_error", "resource_missing", "Unrecognized request URL ("+r.Method+": "+r.URL.Path+").")
+	})
+
+	s.srv = httptest.NewServer(r)
+	return s
+}
+
+// URL returns the base URL of the stand-in.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Close shuts the stand-in down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Backends returns Stripe backends pointed at the stand-in, suitable for
+// payments.StripeProviderConfig.Backends. Retries and client logging are disabled.
+func (s *Server) Backends() *stripe.Backends {
+	config := &stripe.BackendConfig{
+		URL:               stripe.String(s.srv.URL),
+		HTTPClient:        s.srv.Client(),
+		MaxNetworkRetries: stripe.Int64(0),
+		LeveledLogger:     &stripe.LeveledLogger{Level: stripe.LevelNull},
+	}
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, config)
+	return &stripe.Backends{
+		API:     backend,
+		Connect: backend,
+		Uploads: stripe.GetBackendWithConfig(stripe.UploadsBackend, config),
+	}
+}
+
+// SetOutcome changes how subsequent confirmations resolve.
+func (s *Server) SetOutcome(outcome payments.FakeOutcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcome = outcome
+}
+
+// Deliveries returns the webhook deliveries attempted so far, oldest first.
+func (s *Server) Deliveries() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Delivery, len(s.deliveries))
+	copy(out, s.deliveries)
+	return out
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	form, ok := parseForm(w, r)
+	if !ok {
+		return
+	}
+
+	var amount int64
+	currency := ""
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("line_items[%d]", i)
+		unitRaw := form.Get(prefix + "[price_data][unit_amount]")
+		if unitRaw == "" {
+			break
+		}
+		unit, err := strconv.ParseInt(unitRaw, 10, 64)
+		if err != nil {
+			writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid_integer", "Invalid integer: "+unitRaw)
+			return
+		}
+		quantity := int64(1)
+		if raw := form.Get(prefix + "[quantity]"); raw != "" {
+			if quantity, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid_integer", "Invalid integer: "+raw)
+				return
+			}
+		}
+		amount += unit * quantity
+		if currency == "" {
+			currency = strings.ToLower(form.Get(prefix + "[price_data][currency]"))
+		}
+	}
+	if amount <= 0 || currency == "" {
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", "line_items with a positive amount and currency are required.")
+		return
+	}
+
+	s.respondIdempotent(w, r, func() (int, any, []Event) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.seq++
+		created := s.now().Unix()
+		intent := &paymentIntent{
+			id:            fmt.Sprintf("pi_test_%04d", s.seq),
+			amount:        amount,
+			currency:      currency,
+			status:        intentRequiresPaymentMethod,
+			captureMethod: "automatic",
+			metadata:      formMap(form, "payment_intent_data[metadata]"),
+			created:       created,
+		}
+		if s.manualCapture {
+			intent.captureMethod = "manual"
+		}
+		session := &checkoutSession{
+			id:        fmt.Sprintf("cs_test_%04d", s.seq),
+			intentID:  intent.id,
+			amount:    amount,
+			currency:  currency,
+			metadata:  formMap(form, "metadata"),
+			created:   created,
+			expiresAt: created + int64((30 * time.Minute).Seconds()),
+		}
+		s.intents[intent.id] = intent
+		s.sessions[session.id] = session
+		return http.StatusOK, s.sessionObject(session), nil
+	})
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[chi.URLParam(r, "id")]
+	if !ok {
+		writeStripeError(w, http.StatusNotFound, "invalid_request_error", "resource_missing", "No such checkout.session: '"+chi.URLParam(r, "id")+"'")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.sessionObject(session))
+}
+
+func (s *Server) handleGetIntent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[chi.URLParam(r, "id")]
+	if !ok {
+		writeMissingIntent(w, chi.URLParam(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.intentObject(intent))
+}
+
+// handleConfirmIntent applies the configured outcome. Intents already in requires_action complete on
+// the next confirmation, mirroring a finished 3-D Secure challenge.
+func (s *Server) handleConfirmIntent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := parseForm(w, r); !ok {
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	s.respondIdempotent(w, r, func() (int, any, []Event) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		intent, ok := s.intents[id]
+		if !ok {
+			return http.StatusNotFound, missingIntentError(id), nil
+		}
+		if intent.status != intentRequiresPaymentMethod && intent.status != intentRequiresAction {
+			return http.StatusBadRequest, stripeErrorBody("invalid_request_error", "payment_intent_unexpected_state",
+				"This PaymentIntent's status is "+intent.status+" and cannot be confirmed."), nil
+		}
+
+		switch {
+		case s.outcome == payments.FakeOutcomeFail:
+			intent.status = intentRequiresPaymentMethod
+			intent.lastError = map[string]any{
+				"type":         "card_error",
+				"code":         "card_declined",
+				"decline_code": "generic_decline",
+				"message":      "Your card was declined.",
+			}
+			body := stripeErrorBody("card_error", "card_declined", "Your card was declined.")
+			body["error"].(map[string]any)["decline_code"] = "generic_decline"
+			body["error"].(map[string]any)["payment_intent"] = s.intentObject(intent)
+			return http.StatusPaymentRequired, body, []Event{s.intentEvent("payment_intent.payment_failed", intent)}
+		case s.outcome == payments.FakeOutcomeRequiresAction && intent.status == intentRequiresPaymentMethod:
+			intent.status = intentRequiresAction
+			return http.StatusOK, s.intentObject(intent), []Event{s.intentEvent("payment_intent.requires_action", intent)}
+		}
+
+		intent.lastError = nil
+		intent.chargeID = strings.Replace(intent.id, "pi_", "ch_", 1)
+		if intent.captureMethod == "manual" {
+			intent.status = intentRequiresCapture
+			return http.StatusOK, s.intentObject(intent), []Event{s.intentEvent("payment_intent.amount_capturable_updated", intent)}
+		}
+		intent.status = intentSucceeded
+		intent.amountReceived = intent.amount
+		return http.StatusOK, s.intentObject(intent), []Event{s.intentEvent("payment_intent.succeeded", intent)}
+	})
+}
+
+func (s *Server) handleCaptureIntent(w http.ResponseWriter, r *http.Request) {
+	form, ok := parseForm(w, r)
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "id")
+
+	s.respondIdempotent(w, r, func() (int, any, []Event) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		intent, ok := s.intents[id]
+		if !ok {
+			return http.StatusNotFound, missingIntentError(id), nil
+		}
+		if intent.status != intentRequiresCapture {
+			return http.StatusBadRequest, stripeErrorBody("invalid_request_error", "payment_intent_unexpected_state",
+				"This PaymentIntent could not be captured because it has a status of "+intent.status+"."), nil
+		}
+
+		amount := intent.amount
+		if raw := form.Get("amount_to_capture"); raw != "" {
+			parsed, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || parsed <= 0 || parsed > intent.amount {
+				return http.StatusBadRequest, stripeErrorBody("invalid_request_error", "amount_too_large",
+					"The amount_to_capture must be positive and at most the authorized amount."), nil
+			}
+			amount = parsed
+		}
+		intent.status = intentSucceeded
+		intent.amountReceived = amount
+		intent.amountReleased = intent.amount - amount
+		return http.StatusOK, s.intentObject(intent), []Event{s.intentEvent("payment_intent.succeeded", intent)}
+	})
+}
+
+func (s *Server) handleCreateRefund(w http.ResponseWriter, r *http.Request) {
+	form, ok := parseForm(w, r)
+	if !ok {
+		return
+	}
+	id := form.Get("payment_intent")
+
+	s.respondIdempotent(w, r, func() (int, any, []Event) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		intent, ok := s.intents[id]
+		if !ok {
+			return http.StatusNotFound, missingIntentError(id), nil
+		}
+		if intent.status != intentSucceeded {
+			return http.StatusBadRequest, stripeErrorBody("invalid_request_error", "charge_not_refundable",
+				"This PaymentIntent does not have a successful charge to refund."), nil
+		}
+
+		remaining := intent.amountReceived - intent.amountRefunded
+		amount := remaining
+		if raw := form.Get("amount"); raw != "" {
+			parsed, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return http.StatusBadRequest, stripeErrorBody("invalid_request_error", "parameter_invalid_integer", "Invalid integer: "+raw), nil
+			}
+			amount = parsed
+		}
+		if amount <= 0 || amount > remaining {
+			return http.StatusBadRequest, stripeErrorBody("invalid_request_error", "amount_too_large",
+				fmt.Sprintf("Refund amount (%d) is greater than unrefunded amount on charge (%d)", amount, remaining)), nil
+		}
+
+		intent.amountRefunded += amount
+		s.seq++
+		refund := map[string]any{
+			"id":             fmt.Sprintf("re_test_%04d", s.seq),
+			"object":         "refund",
+			"amount":         amount,
+			"charge":         intent.chargeID,
+			"currency":       intent.currency,
+			"payment_intent": intent.id,
+			"reason":         nullable(form.Get("reason")),
+			"metadata":       formMap(form, "metadata"),
+			"status":         "succeeded",
+			"created":        s.now().Unix(),
+		}
+		return http.StatusOK, refund, []Event{s.newEvent("charge.refunded", s.chargeObject(intent))}
+	})
+}
+
+// respondIdempotent runs fn once per Idempotency-Key, replaying the stored response for retries, and
+// delivers any resulting webhook events before answering so callers observe them synchronously.
+func (s *Server) respondIdempotent(w http.ResponseWriter, r *http.Request, fn func() (int, any, []Event)) {
+	key := strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+	if key != "" {
+		key = r.URL.Path + "::" + key
+		s.mu.Lock()
+		recorded, ok := s.idempotent[key]
+		s.mu.Unlock()
+		if ok {
+			writeRaw(w, recorded.status, recorded.body)
+			return
+		}
+	}
+
+	status, payload, events := fn()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		writeStripeError(w, http.StatusInternalServerError, "api_error", "", err.Error())
+		return
+	}
+	if key != "" {
+		s.mu.Lock()
+		s.idempotent[key] = recordedResponse{status: status, body: body}
+		s.mu.Unlock()
+	}
+
+	for _, event := range events {
+		s.deliver(r, event)
+	}
+	writeRaw(w, status, body)
+}
+
+func (s *Server) deliver(r *http.Request, event Event) {
+	s.mu.Lock()
+	emitter := s.emitter
+	s.mu.Unlock()
+	if emitter == nil {
+		return
+	}
+
+	status, err := emitter.Emit(r.Context(), event)
+
+	s.mu.Lock()
+	s.deliveries = append(s.deliveries, Delivery{Event: event, StatusCode: status, Err: err})
+	s.mu.Unlock()
+}
+
+func (s *Server) newEvent(eventType string, object map[string]any) Event {
+	s.seq++
+	return Event{
+		ID:      fmt.Sprintf("evt_test_%04d", s.seq),
+		Type:    eventType,
+		Created: s.now().Unix(),
+		Object:  object,
+	}
+}
+
+func (s *Server) intentEvent(eventType string, intent *paymentIntent) Event {
+	return s.newEvent(eventType, s.intentObject(intent))
+}
+
+func (s *Server) sessionObject(session *checkoutSession) map[string]any {
+	return map[string]any{
+		"id":             session.id,
+		"object":         "checkout.session",
+		"mode":           "payment",
+		"status":         "open",
+		"amount_total":   session.amount,
+		"currency":       session.currency,
+		"payment_intent": session.intentID,
+		"metadata":       session.metadata,
+		"created":        session.created,
+		"expires_at":     session.expiresAt,
+		"url":            s.srv.URL + "/pay/" + session.id,
+	}
+}
+
+func (s *Server) intentObject(intent *paymentIntent) map[string]any {
+	object := map[string]any{
+		"id":                 intent.id,
+		"object":             "payment_intent",
+		"amount":             intent.amount,
+		"amount_capturable":  0,
+		"amount_received":    intent.amountReceived,
+		"capture_method":     intent.captureMethod,
+		"client_secret":      intent.id + "_secret_test",
+		"created":            intent.created,
+		"currency":           intent.currency,
+		"livemode":           false,
+		"metadata":           intent.metadata,
+		"status":             intent.status,
+		"latest_charge":      nil,
+		"last_payment_error": nil,
+		"next_action":        nil,
+	}
+	if intent.status == intentRequiresCapture {
+		object["amount_capturable"] = intent.amount
+	}
+	if intent.status == intentRequiresAction {
+		object["next_action"] = map[string]any{
+			"type":            "redirect_to_url",
+			"redirect_to_url": map[string]any{"url": s.srv.URL + "/3ds/" + intent.id},
+		}
+	}
+	if intent.lastError != nil {
+		object["last_payment_error"] = intent.lastError
+	}
+	if intent.chargeID != "" {
+		object["latest_charge"] = s.chargeObject(intent)
+	}
+	return object
+}
+
+// chargeObject renders the intent's charge. As on Stripe, the uncaptured remainder of a partial
+// capture counts towards amount_refunded.
+func (s *Server) chargeObject(intent *paymentIntent) map[string]any {
+	captured := intent.status == intentSucceeded
+	return map[string]any{
+		"id":              intent.chargeID,
+		"object":          "charge",
+		"amount":          intent.amount,
+		"amount_captured": intent.amountReceived,
+		"amount_refunded": intent.amountReleased + intent.amountRefunded,
+		"captured":        captured,
+		"paid":            true,
+		"refunded":        captured && intent.amountRefunded >= intent.amountReceived,
+		"currency":        intent.currency,
+		"created":         intent.created,
+		"payment_intent":  intent.id,
+		"status":          "succeeded",
+		"metadata":        intent.metadata,
+	}
+}
+
+func parseForm(w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	if err := r.ParseForm(); err != nil {
+		writeStripeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid request body.")
+		return nil, false
+	}
+	return r.PostForm, true
+}
+
+// formMap collects bracketed form keys such as metadata[order_id] into a map.
+func formMap(form url.Values, prefix string) map[string]string {
+	out := map[string]string{}
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix+"[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, prefix+"["), "]")
+		if name == "" || strings.ContainsAny(name, "[]") {
+			continue
+		}
+		out[name] = form.Get(key)
+	}
+	return out
+}
+
+func nullable(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func missingIntentError(id string) map[string]any {
+	return stripeErrorBody("invalid_request_error", "resource_missing", "No such payment_intent: '"+id+"'")
+}
+
+func writeMissingIntent(w http.ResponseWriter, id string) {
+	writeJSON(w, http.StatusNotFound, missingIntentError(id))
+}
+
+func stripeErrorBody(errType, code, message string) map[string]any {
+	body := map[string]any{
+		"type":    errType,
+		"message": message,
+	}
+	if code != "" {
+		body["code"] = code
+	}
+	return map[string]any{"error": body}
+}
+
+func writeStripeError(w http.ResponseWriter, status int, errType, code, message string) {
+	writeJSON(w, status, stripeErrorBody(errType, code, message))
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeRaw(w, status, body)
+}
+
+func writeRaw(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Request-Id", "req_stripetest")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,236 @@
+package stripetest
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v78"
+	"github.com/stripe/stripe-go/v78/webhook"
+
+	"github.com/hanko-field/api/internal/handlers"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/platform/auth"
+	"github.com/hanko-field/api/internal/services"
+)
+
+func newProvider(t *testing.T, srv *Server) *payments.StripeProvider {
+	t.Helper()
+	provider, err := payments.NewStripeProvider(payments.StripeProviderConfig{
+		APIKey:   "sk_test_stripetest",
+		Backends: srv.Backends(),
+	})
+	if err != nil {
+		t.Fatalf("new stripe provider: %v", err)
+	}
+	return provider
+}
+
+func TestServer_ManualCaptureLifecycle(t *testing.T) {
+	srv := NewServer(WithManualCapture())
+	defer srv.Close()
+	provider := newProvider(t, srv)
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, payments.CheckoutSessionRequest{
+		Currency:       "JPY",
+		SuccessURL:     "https://example.test/success",
+		CancelURL:      "https://example.test/cancel",
+		Metadata:       map[string]string{"order_id": "ord_1"},
+		IdempotencyKey: "checkout-ord_1",
+		Items:          []payments.CheckoutLineItem{{Name: "Seal", Amount: 2500, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.ID != "cs_test_0001" || session.IntentID != "pi_test_0001" {
+		t.Fatalf("unexpected session identifiers: %+v", session)
+	}
+
+	replay, err := provider.CreateCheckoutSession(ctx, payments.CheckoutSessionRequest{
+		Currency:       "JPY",
+		IdempotencyKey: "checkout-ord_1",
+		Items:          []payments.CheckoutLineItem{{Name: "Seal", Amount: 2500, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatalf("replay session: %v", err)
+	}
+	if replay.ID != session.ID {
+		t.Fatalf("expected idempotent replay to return %s, got %s", session.ID, replay.ID)
+	}
+
+	details, err := provider.Confirm(ctx, payments.ConfirmRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if details.Status != payments.StatusPending || details.Amount != 5000 || details.Currency != "JPY" {
+		t.Fatalf("expected authorised intent awaiting capture, got %+v", details)
+	}
+
+	partial := int64(4000)
+	details, err = provider.Capture(ctx, payments.CaptureRequest{IntentID: session.IntentID, Amount: &partial})
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if details.Status != payments.StatusSucceeded || !details.Captured {
+		t.Fatalf("expected captured intent, got %+v", details)
+	}
+
+	details, err = provider.Refund(ctx, payments.RefundRequest{IntentID: session.IntentID, Reason: "requested_by_customer"})
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if details.Status != payments.StatusRefunded {
+		t.Fatalf("expected refunded intent, got %s", details.Status)
+	}
+
+	if _, err := provider.Refund(ctx, payments.RefundRequest{IntentID: session.IntentID}); err == nil {
+		t.Fatalf("expected over-refund to fail")
+	}
+}
+
+func TestServer_DeclineAndRequiresAction(t *testing.T) {
+	srv := NewServer(WithOutcome(payments.FakeOutcomeFail))
+	defer srv.Close()
+	provider := newProvider(t, srv)
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, payments.CheckoutSessionRequest{Currency: "USD", Amount: 1200})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	_, err = provider.Confirm(ctx, payments.ConfirmRequest{IntentID: session.IntentID})
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) || stripeErr.Code != stripe.ErrorCodeCardDeclined {
+		t.Fatalf("expected card_declined stripe error, got %v", err)
+	}
+
+	srv.SetOutcome(payments.FakeOutcomeRequiresAction)
+	details, err := provider.Confirm(ctx, payments.ConfirmRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("confirm requiring action: %v", err)
+	}
+	if details.Status != payments.StatusPending || details.Raw["next_action"] == nil {
+		t.Fatalf("expected intent awaiting action, got %+v", details)
+	}
+
+	details, err = provider.Confirm(ctx, payments.ConfirmRequest{IntentID: session.IntentID})
+	if err != nil {
+		t.Fatalf("confirm after action: %v", err)
+	}
+	if details.Status != payments.StatusSucceeded {
+		t.Fatalf("expected success after action, got %s", details.Status)
+	}
+
+	if _, err := provider.LookupPayment(ctx, payments.LookupRequest{IntentID: "pi_missing"}); err == nil {
+		t.Fatalf("expected lookup of unknown intent to fail")
+	}
+}
+
+type recordingPaymentService struct {
+	services.PaymentService
+
+	mu     sync.Mutex
+	secret string
+	events []stripe.Event
+}
+
+func (s *recordingPaymentService) RecordWebhookEvent(_ context.Context, cmd services.PaymentWebhookCommand) error {
+	event, err := webhook.ConstructEvent(cmd.Payload, cmd.Headers["Stripe-Signature"], s.secret)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestServer_EmitsSignedWebhooksToAPI(t *testing.T) {
+	const (
+		signingSecret = "whsec_stripetest"
+		hmacSecret    = "hmac-stripetest"
+	)
+
+	recorder := &recordingPaymentService{secret: signingSecret}
+	validator := auth.NewHMACValidator(
+		auth.SecretProviderFunc(func(_ context.Context, name string) (string, error) {
+			if name != "payments/stripe" {
+				return "", errors.New("unknown secret")
+			}
+			return hmacSecret, nil
+		}),
+		auth.NewInMemoryNonceStore(),
+	)
+	router := handlers.NewRouter(
+		handlers.WithWebhookRoutes(handlers.NewWebhookHandlers(handlers.WithWebhookPaymentService(recorder)).Routes),
+		handlers.WithWebhookMiddlewares(validator.RequireHMAC("payments/stripe")),
+	)
+	api := httptest.NewServer(router)
+	defer api.Close()
+
+	srv := NewServer(WithWebhookEmitter(&WebhookEmitter{
+		URL:           api.URL + "/api/v1/webhooks/payments/stripe",
+		SigningSecret: signingSecret,
+		HMACSecret:    hmacSecret,
+		Clock:         func() time.Time { return time.Now() },
+	}))
+	defer srv.Close()
+	provider := newProvider(t, srv)
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckoutSession(ctx, payments.CheckoutSessionRequest{Currency: "JPY", Amount: 3300})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if _, err := provider.Confirm(ctx, payments.ConfirmRequest{IntentID: session.IntentID}); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	if _, err := provider.Refund(ctx, payments.RefundRequest{IntentID: session.IntentID}); err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	deliveries := srv.Deliveries()
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(deliveries))
+	}
+	for _, delivery := range deliveries {
+		if delivery.Err != nil {
+			t.Fatalf("delivery %s failed: %v", delivery.Event.ID, delivery.Err)
+		}
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.events) != 2 {
+		t.Fatalf("expected 2 verified events, got %d", len(recorder.events))
+	}
+	if recorder.events[0].Type != "payment_intent.succeeded" || recorder.events[1].Type != "charge.refunded" {
+		t.Fatalf("unexpected event sequence: %s, %s", recorder.events[0].Type, recorder.events[1].Type)
+	}
+	if recorder.events[0].Data.Object["id"] != session.IntentID {
+		t.Fatalf("expected event for %s, got %v", session.IntentID, recorder.events[0].Data.Object["id"])
+	}
+}
+
+func TestWebhookEmitter_RejectedWithoutHMAC(t *testing.T) {
+	validator := auth.NewHMACValidator(
+		auth.SecretProviderFunc(func(context.Context, string) (string, error) { return "secret", nil }),
+		auth.NewInMemoryNonceStore(),
+	)
+	router := handlers.NewRouter(
+		handlers.WithWebhookRoutes(handlers.NewWebhookHandlers(handlers.WithWebhookPaymentService(&recordingPaymentService{})).Routes),
+		handlers.WithWebhookMiddlewares(validator.RequireHMAC("payments/stripe")),
+	)
+	api := httptest.NewServer(router)
+	defer api.Close()
+
+	emitter := &WebhookEmitter{URL: api.URL + "/api/v1/webhooks/payments/stripe", SigningSecret: "whsec_x"}
+	status, err := emitter.Emit(context.Background(), Event{ID: "evt_1", Type: "payment_intent.succeeded", Object: map[string]any{"id": "pi_1"}})
+	if err == nil || status != 401 {
+		t.Fatalf("expected 401 rejection without hmac headers, got status=%d err=%v", status, err)
+	}
+}
@@ -473,3 +473,25 @@ func computeHMAC(secret []byte, message []byte) []byte {
 	_, _ = mac.Write(message)
 	return mac.Sum(nil)
 }
+
+// SignHMACRequest signs the request the way HMACValidator expects, setting the default signature,
+// timestamp and nonce headers. The body must match the bytes the request will send. It is intended
+// for trusted callers and test harnesses that emit webhooks to the API.
+func SignHMACRequest(r *http.Request, body []byte, secret string, timestamp time.Time, nonce string) error {
+	if r == nil {
+		return errors.New("auth: request is required")
+	}
+	if secret == "" {
+		return errors.New("auth: secret is required")
+	}
+	if strings.TrimSpace(nonce) == "" {
+		return errors.New("auth: nonce is required")
+	}
+
+	ts := timestamp.UTC().Format(time.RFC3339)
+	signature := computeHMAC([]byte(secret), buildCanonicalString(r, body, ts, nonce))
+	r.Header.Set(defaultSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	r.Header.Set(defaultTimestampHeader, ts)
+	r.Header.Set(defaultNonceHeader, nonce)
+	return nil
+}
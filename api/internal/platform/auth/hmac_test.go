@@ -262,3 +262,32 @@ func TestRequireHMACResolver(t *testing.T) {
 		t.Fatalf("expected 401 for unknown provider, got %d", unknown.Code)
 	}
 }
+
+func TestSignHMACRequest_RoundTrip(t *testing.T) {
+	const secretName = "payments/stripe"
+	secretValue := "signer-secret"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	validator := NewHMACValidator(mapSecretProvider{secretName: secretValue}, NewInMemoryNonceStore(),
+		WithHMACLogger(noopLogger{}),
+		WithHMACClock(func() time.Time { return now }),
+	)
+
+	body := []byte(`{"type":"payment_intent.succeeded"}`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payments/stripe", bytes.NewReader(body))
+	if err := SignHMACRequest(req, body, secretValue, now, "signer-nonce"); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	validator.RequireHMAC(secretName)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected signed request to pass validation, got %d", rr.Code)
+	}
+
+	if err := SignHMACRequest(req, body, "", now, "nonce"); err == nil {
+		t.Fatalf("expected error for empty secret")
+	}
+}
@@ -37,6 +37,10 @@ const (
 	defaultIdempotencyTTL        = 24 * time.Hour
 	defaultIdempotencyInterval   = time.Hour
 	defaultIdempotencyBatchSize  = 200
+	defaultReservationSweep      = time.Minute
+	defaultReservationSweepBatch = 100
+	defaultReservationSweepLimit = 1000
+	defaultPricingQuoteTTL       = 15 * time.Minute
 )
 
 // Config captures all runtime configuration organised by concern.
@@ -52,6 +56,8 @@ type Config struct {
 	Features    FeatureFlags
 	Security    SecurityConfig
 	Idempotency IdempotencyConfig
+	Inventory   InventoryConfig
+	Pricing     PricingConfig
 }
 
 // ServerConfig configures HTTP server parameters.
@@ -147,6 +153,21 @@ type IdempotencyConfig struct {
 	CleanupBatchSize int
 }
 
+// InventoryConfig controls the in-process sweeper that releases expired stock reservations. A zero
+// ReservationSweepInterval disables the sweeper.
+type InventoryConfig struct {
+	ReservationSweepInterval  time.Duration
+	ReservationSweepBatchSize int
+	ReservationSweepLimit     int
+}
+
+// PricingConfig controls signed price quotes. QuoteSecret signs the quote returned with each cart
+// estimate, which checkout requires; QuoteTTL bounds how long a quote stays valid.
+type PricingConfig struct {
+	QuoteSecret string
+	QuoteTTL    time.Duration
+}
+
 // SecretResolver resolves references to external secrets (e.g. Secret Manager URIs).
 type SecretResolver interface {
 	ResolveSecret(ctx context.Context, ref string) (string, error)
@@ -466,6 +487,15 @@ func Load(ctx context.Context, opts ...Option) (Config, error) {
 			CleanupInterval:  durationWithDefault(lookup, "API_IDEMPOTENCY_CLEANUP_INTERVAL", defaultIdempotencyInterval),
 			CleanupBatchSize: intWithDefault(lookup, "API_IDEMPOTENCY_CLEANUP_BATCH", defaultIdempotencyBatchSize),
 		},
+		Inventory: InventoryConfig{
+			ReservationSweepInterval:  durationWithDefault(lookup, "API_INVENTORY_RESERVATION_SWEEP_INTERVAL", defaultReservationSweep),
+			ReservationSweepBatchSize: intWithDefault(lookup, "API_INVENTORY_RESERVATION_SWEEP_BATCH", defaultReservationSweepBatch),
+			ReservationSweepLimit:     intWithDefault(lookup, "API_INVENTORY_RESERVATION_SWEEP_LIMIT", defaultReservationSweepLimit),
+		},
+		Pricing: PricingConfig{
+			QuoteSecret: stringWithDefault(lookup, "API_PRICING_QUOTE_SECRET", ""),
+			QuoteTTL:    durationWithDefault(lookup, "API_PRICING_QUOTE_TTL", defaultPricingQuoteTTL),
+		},
 	}
 
 	resolvedSecrets := make(map[string]string)
@@ -518,6 +548,7 @@ func Load(ctx context.Context, opts ...Option) (Config, error) {
 		{"PSP.PayPalSecret", &cfg.PSP.PayPalSecret},
 		{"AI.AuthToken", &cfg.AI.AuthToken},
 		{"Webhooks.SigningSecret", &cfg.Webhooks.SigningSecret},
+		{"Pricing.QuoteSecret", &cfg.Pricing.QuoteSecret},
 	}
 	for _, target := range secretFields {
 		if err := resolveField(target.name, target.field); err != nil {
@@ -586,6 +617,18 @@ func validateConfig(cfg Config) error {
 	if cfg.Idempotency.CleanupBatchSize <= 0 {
 		missing = append(missing, "Idempotency.CleanupBatchSize")
 	}
+	if cfg.Inventory.ReservationSweepInterval < 0 {
+		missing = append(missing, "Inventory.ReservationSweepInterval")
+	}
+	if cfg.Inventory.ReservationSweepBatchSize <= 0 {
+		missing = append(missing, "Inventory.ReservationSweepBatchSize")
+	}
+	if cfg.Inventory.ReservationSweepLimit <= 0 {
+		missing = append(missing, "Inventory.ReservationSweepLimit")
+	}
+	if cfg.Pricing.QuoteTTL <= 0 {
+		missing = append(missing, "Pricing.QuoteTTL")
+	}
 
 	if len(missing) > 0 {
 		return &ValidationError{fields: missing}
@@ -59,44 +59,53 @@ func TestLoadWithDefaults(t *testing.T) {
 	if cfg.Idempotency.CleanupBatchSize != defaultIdempotencyBatchSize {
 		t.Errorf("unexpected default cleanup batch size: %d", cfg.Idempotency.CleanupBatchSize)
 	}
+	if cfg.Inventory.ReservationSweepInterval != defaultReservationSweep {
+		t.Errorf("unexpected default reservation sweep interval: %s", cfg.Inventory.ReservationSweepInterval)
+	}
+	if cfg.Pricing.QuoteTTL != defaultPricingQuoteTTL || cfg.Pricing.QuoteSecret != "" {
+		t.Errorf("unexpected default pricing config: %+v", cfg.Pricing)
+	}
 }
 
 func TestLoadWithOverridesAndSecrets(t *testing.T) {
 	env := map[string]string{
-		"API_SERVER_PORT":                    "9090",
-		"API_SERVER_READ_TIMEOUT":            "20s",
-		"API_SERVER_WRITE_TIMEOUT":           "25s",
-		"API_SERVER_IDLE_TIMEOUT":            "2m",
-		"API_FIREBASE_PROJECT_ID":            "hf-prod",
-		"API_FIRESTORE_PROJECT_ID":           "hf-fire",
-		"API_STORAGE_ASSETS_BUCKET":          "assets-prod",
-		"API_STORAGE_LOGS_BUCKET":            "logs-prod",
-		"API_STORAGE_EXPORTS_BUCKET":         "exports-prod",
-		"API_PSP_STRIPE_API_KEY":             "secret://stripe/api",
-		"API_PSP_STRIPE_WEBHOOK_SECRET":      "secret://stripe/webhook",
-		"API_PSP_PAYPAL_CLIENT_ID":           "paypal-client",
-		"API_PSP_PAYPAL_SECRET":              "secret://paypal/secret",
-		"API_AI_SUGGESTION_ENDPOINT":         "https://ai.example.com",
-		"API_AI_AUTH_TOKEN":                  "secret://ai/token",
-		"API_WEBHOOK_SIGNING_SECRET":         "secret://webhook/secret",
-		"API_WEBHOOK_ALLOWED_HOSTS":          "https://example.com, https://foo.bar",
-		"API_RATELIMIT_DEFAULT_PER_MIN":      "150",
-		"API_RATELIMIT_AUTH_PER_MIN":         "300",
-		"API_RATELIMIT_WEBHOOK_BURST":        "80",
-		"API_FEATURE_AISUGGESTIONS":          "true",
-		"API_FEATURE_PROMOTIONS":             "false",
-		"API_SECURITY_ENVIRONMENT":           "prod",
-		"API_SECURITY_OIDC_AUDIENCE":         "https://service.example.com",
-		"API_SECURITY_OIDC_ISSUERS":          "https://accounts.google.com, https://cloud.google.com/iap",
-		"API_SECURITY_OIDC_JWKS_URL":         "https://example.com/jwks.json",
-		"API_SECURITY_HMAC_SECRETS":          "payments/stripe=secret://hmac/stripe,shipping=shipping-secret",
-		"API_SECURITY_HMAC_HEADER_SIGNATURE": "X-Custom-Signature",
-		"API_SECURITY_HMAC_CLOCK_SKEW":       "3m",
-		"API_SECURITY_HMAC_NONCE_TTL":        "10m",
-		"API_IDEMPOTENCY_HEADER":             "X-Idem-Key",
-		"API_IDEMPOTENCY_TTL":                "48h",
-		"API_IDEMPOTENCY_CLEANUP_INTERVAL":   "30m",
-		"API_IDEMPOTENCY_CLEANUP_BATCH":      "500",
+		"API_SERVER_PORT":                          "9090",
+		"API_SERVER_READ_TIMEOUT":                  "20s",
+		"API_SERVER_WRITE_TIMEOUT":                 "25s",
+		"API_SERVER_IDLE_TIMEOUT":                  "2m",
+		"API_FIREBASE_PROJECT_ID":                  "hf-prod",
+		"API_FIRESTORE_PROJECT_ID":                 "hf-fire",
+		"API_STORAGE_ASSETS_BUCKET":                "assets-prod",
+		"API_STORAGE_LOGS_BUCKET":                  "logs-prod",
+		"API_STORAGE_EXPORTS_BUCKET":               "exports-prod",
+		"API_PSP_STRIPE_API_KEY":                   "secret://stripe/api",
+		"API_PSP_STRIPE_WEBHOOK_SECRET":            "secret://stripe/webhook",
+		"API_PSP_PAYPAL_CLIENT_ID":                 "paypal-client",
+		"API_PSP_PAYPAL_SECRET":                    "secret://paypal/secret",
+		"API_AI_SUGGESTION_ENDPOINT":               "https://ai.example.com",
+		"API_AI_AUTH_TOKEN":                        "secret://ai/token",
+		"API_WEBHOOK_SIGNING_SECRET":               "secret://webhook/secret",
+		"API_WEBHOOK_ALLOWED_HOSTS":                "https://example.com, https://foo.bar",
+		"API_RATELIMIT_DEFAULT_PER_MIN":            "150",
+		"API_RATELIMIT_AUTH_PER_MIN":               "300",
+		"API_RATELIMIT_WEBHOOK_BURST":              "80",
+		"API_FEATURE_AISUGGESTIONS":                "true",
+		"API_FEATURE_PROMOTIONS":                   "false",
+		"API_SECURITY_ENVIRONMENT":                 "prod",
+		"API_SECURITY_OIDC_AUDIENCE":               "https://service.example.com",
+		"API_SECURITY_OIDC_ISSUERS":                "https://accounts.google.com, https://cloud.google.com/iap",
+		"API_SECURITY_OIDC_JWKS_URL":               "https://example.com/jwks.json",
+		"API_SECURITY_HMAC_SECRETS":                "payments/stripe=secret://hmac/stripe,shipping=shipping-secret",
+		"API_SECURITY_HMAC_HEADER_SIGNATURE":       "X-Custom-Signature",
+		"API_SECURITY_HMAC_CLOCK_SKEW":             "3m",
+		"API_SECURITY_HMAC_NONCE_TTL":              "10m",
+		"API_IDEMPOTENCY_HEADER":                   "X-Idem-Key",
+		"API_IDEMPOTENCY_TTL":                      "48h",
+		"API_IDEMPOTENCY_CLEANUP_INTERVAL":         "30m",
+		"API_IDEMPOTENCY_CLEANUP_BATCH":            "500",
+		"API_INVENTORY_RESERVATION_SWEEP_INTERVAL": "0s",
+		"API_PRICING_QUOTE_SECRET":                 "secret://pricing/quote",
+		"API_PRICING_QUOTE_TTL":                    "5m",
 	}
 
 	secrets := map[string]string{
@@ -106,6 +115,7 @@ func TestLoadWithOverridesAndSecrets(t *testing.T) {
 		"secret://ai/token":       "ai-token",
 		"secret://webhook/secret": "webhook-secret",
 		"secret://hmac/stripe":    "stripe-hmac",
+		"secret://pricing/quote":  "quote-secret",
 	}
 
 	resolver := SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
@@ -177,6 +187,12 @@ func TestLoadWithOverridesAndSecrets(t *testing.T) {
 	if cfg.Idempotency.CleanupBatchSize != 500 {
 		t.Errorf("unexpected cleanup batch size %d", cfg.Idempotency.CleanupBatchSize)
 	}
+	if cfg.Inventory.ReservationSweepInterval != 0 {
+		t.Errorf("expected reservation sweeper to be disabled, got %s", cfg.Inventory.ReservationSweepInterval)
+	}
+	if cfg.Pricing.QuoteSecret != "quote-secret" || cfg.Pricing.QuoteTTL != 5*time.Minute {
+		t.Errorf("unexpected pricing config %+v", cfg.Pricing)
+	}
 }
 
 func TestLoadDotEnvFallback(t *testing.T) {
@@ -62,6 +62,96 @@ func (p *PubSubSuggestionPublisher) PublishSuggestionJob(ctx context.Context, me
 	return id, nil
 }
 
+// PubSubStockCleanupPublisher publishes stock cleanup jobs to a Pub/Sub topic whose push
+// subscription targets the internal reservation cleanup endpoint.
+type PubSubStockCleanupPublisher struct {
+	topic   *pubsub.Topic
+	marshal func(any) ([]byte, error)
+}
+
+// NewPubSubStockCleanupPublisher constructs a Pub/Sub backed stock cleanup publisher.
+func NewPubSubStockCleanupPublisher(topic *pubsub.Topic) (*PubSubStockCleanupPublisher, error) {
+	if topic == nil {
+		return nil, errors.New("pubsub stock cleanup publisher: topic is required")
+	}
+	return &PubSubStockCleanupPublisher{
+		topic:   topic,
+		marshal: json.Marshal,
+	}, nil
+}
+
+// PublishStockCleanup enqueues a stock cleanup message on the configured topic.
+func (p *PubSubStockCleanupPublisher) PublishStockCleanup(ctx context.Context, message services.StockCleanupMessage) (string, error) {
+	if p == nil || p.topic == nil {
+		return "", errors.New("pubsub stock cleanup publisher: not initialised")
+	}
+
+	data, err := p.marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("marshal stock cleanup job: %w", err)
+	}
+
+	attrs := make(map[string]string)
+	setAttr(attrs, "jobId", message.JobID)
+
+	result := p.topic.Publish(ctx, &pubsub.Message{
+		Data:       data,
+		Attributes: attrs,
+	})
+
+	id, err := result.Get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("publish stock cleanup job: %w", err)
+	}
+	return id, nil
+}
+
+// PubSubBackInStockNotifier publishes back-in-stock notifications to a Pub/Sub topic consumed by
+// the notification worker that emails subscribers.
+type PubSubBackInStockNotifier struct {
+	topic   *pubsub.Topic
+	marshal func(any) ([]byte, error)
+}
+
+// NewPubSubBackInStockNotifier constructs a Pub/Sub backed back-in-stock notifier.
+func NewPubSubBackInStockNotifier(topic *pubsub.Topic) (*PubSubBackInStockNotifier, error) {
+	if topic == nil {
+		return nil, errors.New("pubsub back-in-stock notifier: topic is required")
+	}
+	return &PubSubBackInStockNotifier{
+		topic:   topic,
+		marshal: json.Marshal,
+	}, nil
+}
+
+// NotifyBackInStock enqueues a notification on the configured topic. The subscription ID is set as
+// the idempotency key so the worker can drop redeliveries.
+func (n *PubSubBackInStockNotifier) NotifyBackInStock(ctx context.Context, notification services.BackInStockNotification) error {
+	if n == nil || n.topic == nil {
+		return errors.New("pubsub back-in-stock notifier: not initialised")
+	}
+
+	data, err := n.marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshal back-in-stock notification: %w", err)
+	}
+
+	attrs := make(map[string]string)
+	setAttr(attrs, "subscriptionId", notification.SubscriptionID)
+	setAttr(attrs, "userId", notification.UserID)
+	setAttr(attrs, "sku", notification.SKU)
+	setAttr(attrs, "idempotencyKey", notification.SubscriptionID)
+
+	result := n.topic.Publish(ctx, &pubsub.Message{
+		Data:       data,
+		Attributes: attrs,
+	})
+	if _, err := result.Get(ctx); err != nil {
+		return fmt.Errorf("publish back-in-stock notification: %w", err)
+	}
+	return nil
+}
+
 func setAttr(attrs map[string]string, key string, value string) {
 	if v := strings.TrimSpace(value); v != "" {
 		attrs[key] = v
@@ -76,3 +76,103 @@ func TestPubSubSuggestionPublisherPublishesMessage(t *testing.T) {
 		t.Fatalf("prompt attribute should not be present")
 	}
 }
+
+func TestPubSubStockCleanupPublisherPublishesMessage(t *testing.T) {
+	ctx := context.Background()
+	srv := pstest.NewServer()
+	defer srv.Close()
+
+	client, err := pubsub.NewClient(ctx, "test-project",
+		option.WithEndpoint(srv.Addr),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		t.Fatalf("pubsub.NewClient: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	topic, err := client.CreateTopic(ctx, "stock-cleanup")
+	if err != nil {
+		t.Fatalf("CreateTopic: %v", err)
+	}
+
+	publisher, err := NewPubSubStockCleanupPublisher(topic)
+	if err != nil {
+		t.Fatalf("NewPubSubStockCleanupPublisher: %v", err)
+	}
+
+	msg := services.StockCleanupMessage{
+		JobID:          "scj_test",
+		ReservationIDs: []string{"sr_1", "sr_2"},
+		QueuedAt:       time.Date(2025, 5, 6, 9, 0, 0, 0, time.UTC),
+	}
+	if _, err := publisher.PublishStockCleanup(ctx, msg); err != nil {
+		t.Fatalf("PublishStockCleanup: %v", err)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+	var payload services.StockCleanupMessage
+	if err := json.Unmarshal(messages[0].Data, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload.JobID != "scj_test" || len(payload.ReservationIDs) != 2 || messages[0].Attributes["jobId"] != "scj_test" {
+		t.Fatalf("unexpected message %#v %#v", payload, messages[0].Attributes)
+	}
+}
+
+func TestPubSubBackInStockNotifierPublishesMessage(t *testing.T) {
+	ctx := context.Background()
+	srv := pstest.NewServer()
+	defer srv.Close()
+
+	client, err := pubsub.NewClient(ctx, "test-project",
+		option.WithEndpoint(srv.Addr),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		t.Fatalf("pubsub.NewClient: %v", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	topic, err := client.CreateTopic(ctx, "back-in-stock")
+	if err != nil {
+		t.Fatalf("CreateTopic: %v", err)
+	}
+
+	notifier, err := NewPubSubBackInStockNotifier(topic)
+	if err != nil {
+		t.Fatalf("NewPubSubBackInStockNotifier: %v", err)
+	}
+
+	notification := services.BackInStockNotification{
+		SubscriptionID: "bis_1",
+		UserID:         "user_1",
+		SKU:            "SKU-1",
+		Available:      3,
+		OccurredAt:     time.Date(2025, 5, 6, 9, 0, 0, 0, time.UTC),
+	}
+	if err := notifier.NotifyBackInStock(ctx, notification); err != nil {
+		t.Fatalf("NotifyBackInStock: %v", err)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+	var payload services.BackInStockNotification
+	if err := json.Unmarshal(messages[0].Data, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload.SubscriptionID != "bis_1" || payload.Available != 3 || messages[0].Attributes["idempotencyKey"] != "bis_1" {
+		t.Fatalf("unexpected message %#v %#v", payload, messages[0].Attributes)
+	}
+}
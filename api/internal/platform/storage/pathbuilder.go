@@ -13,6 +13,8 @@ const (
 	PurposeDesignMaster AssetPurpose = "design-master"
 	PurposePreview      AssetPurpose = "preview"
 	PurposeReceipt      AssetPurpose = "receipt"
+	// PurposeDisputeEvidence stores staff-uploaded documents offered to a PSP for a chargeback.
+	PurposeDisputeEvidence AssetPurpose = "dispute-evidence"
 )
 
 // PathParams provide required identifiers to compose storage object keys.
@@ -30,9 +32,10 @@ type PathBuilder func(PathParams) (string, error)
 
 var (
 	pathBuilders = map[AssetPurpose]PathBuilder{
-		PurposeDesignMaster: buildDesignMasterPath,
-		PurposePreview:      buildPreviewPath,
-		PurposeReceipt:      buildReceiptPath,
+		PurposeDesignMaster:    buildDesignMasterPath,
+		PurposePreview:         buildPreviewPath,
+		PurposeReceipt:         buildReceiptPath,
+		PurposeDisputeEvidence: buildDisputeEvidencePath,
 	}
 	pathBuildersMu sync.RWMutex
 )
@@ -107,6 +110,22 @@ func buildReceiptPath(params PathParams) (string, error) {
 	return fmt.Sprintf("assets/orders/%s/invoices/%s", orderID, fileName), nil
 }
 
+func buildDisputeEvidencePath(params PathParams) (string, error) {
+	orderID, err := validateSegment("orderID", params.OrderID)
+	if err != nil {
+		return "", err
+	}
+	uploadID, err := validateSegment("uploadID", params.UploadID)
+	if err != nil {
+		return "", err
+	}
+	fileName, err := validateFileName(params.FileName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("assets/orders/%s/disputes/%s/%s", orderID, uploadID, fileName), nil
+}
+
 func validateSegment(name, value string) (string, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
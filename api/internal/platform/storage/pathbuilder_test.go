@@ -31,6 +31,21 @@ func TestBuildReceiptPathUsesInvoiceNumber(t *testing.T) {
 	}
 }
 
+func TestBuildDisputeEvidencePath(t *testing.T) {
+	path, err := BuildObjectPath(PurposeDisputeEvidence, PathParams{
+		OrderID:  "order123",
+		UploadID: "upload456",
+		FileName: "tracking.pdf",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "assets/orders/order123/disputes/upload456/tracking.pdf"
+	if path != expected {
+		t.Fatalf("expected %s, got %s", expected, path)
+	}
+}
+
 func TestBuildObjectPathRejectsInvalidSegment(t *testing.T) {
 	_, err := BuildObjectPath(PurposeDesignMaster, PathParams{
 		DesignID: "../bad",
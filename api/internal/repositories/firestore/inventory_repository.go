@@ -21,18 +21,27 @@ import (
 )
 
 const (
-	inventoryCollection         = "inventory"
-	stockReservationsCollection = "stockReservations"
+	inventoryCollection            = "inventory"
+	stockReservationsCollection    = "stockReservations"
+	inventoryStockEventsCollection = "inventoryStockEvents"
+	inventoryLocationsCollection   = "inventoryLocations"
+	locationStocksCollection       = "locations"
+
+	stockEventTransferOut = "inventory.transfer_out"
+	stockEventTransferIn  = "inventory.transfer_in"
 
 	reservationStatusReserved  = "reserved"
 	reservationStatusCommitted = "committed"
 	reservationStatusReleased  = "released"
+	reservationStatusRestocked = "restocked"
 )
 
 type InventoryRepository struct {
 	provider     *pfirestore.Provider
 	stocks       *pfirestore.BaseRepository[stockDocument]
 	reservations *pfirestore.BaseRepository[reservationDocument]
+	events       *pfirestore.BaseRepository[stockEventDocument]
+	locations    *pfirestore.BaseRepository[locationDocument]
 }
 
 func NewInventoryRepository(provider *pfirestore.Provider) (*InventoryRepository, error) {
@@ -41,7 +50,9 @@ func NewInventoryRepository(provider *pfirestore.Provider) (*InventoryRepository
 	}
 	stocks := pfirestore.NewBaseRepository[stockDocument](provider, inventoryCollection, nil, nil)
 	reservations := pfirestore.NewBaseRepository[reservationDocument](provider, stockReservationsCollection, nil, nil)
-	return &InventoryRepository{provider: provider, stocks: stocks, reservations: reservations}, nil
+	events := pfirestore.NewBaseRepository[stockEventDocument](provider, inventoryStockEventsCollection, nil, nil)
+	locations := pfirestore.NewBaseRepository[locationDocument](provider, inventoryLocationsCollection, nil, nil)
+	return &InventoryRepository{provider: provider, stocks: stocks, reservations: reservations, events: events, locations: locations}, nil
 }
 
 func (r *InventoryRepository) Reserve(ctx context.Context, req repositories.InventoryReserveRequest) (repositories.InventoryReserveResult, error) {
@@ -78,45 +89,33 @@ func (r *InventoryRepository) Reserve(ctx context.Context, req repositories.Inve
 			return err
 		}
 
-		stocks := make(map[string]domain.InventoryStock)
-		for _, line := range reservation.Lines {
-			sku := strings.TrimSpace(line.SKU)
-			if sku == "" {
+		resDoc := newReservationDocument(reservation)
+		for _, line := range resDoc.Lines {
+			if line.SKU == "" {
 				return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, "inventory reserve: sku is required", nil)
 			}
 			if line.Quantity <= 0 {
-				return repositories.NewInventoryError(repositories.InventoryErrorUnknown, fmt.Sprintf("inventory reserve: quantity for %s must be > 0", sku), nil)
+				return repositories.NewInventoryError(repositories.InventoryErrorUnknown, fmt.Sprintf("inventory reserve: quantity for %s must be > 0", line.SKU), nil)
 			}
+		}
 
-			stockRef, err := r.stocks.DocumentRef(ctx, sku)
-			if err != nil {
-				return err
-			}
-			snap, err := tx.Get(stockRef)
-			if err != nil {
-				if status.Code(err) == codes.NotFound {
-					return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", sku), err)
+		touched, err := r.loadReservationStocks(ctx, tx, resDoc.Lines)
+		if err != nil {
+			return err
+		}
+		for _, line := range resDoc.Lines {
+			for _, stockDoc := range touched.forLine(line) {
+				if stockDoc.OnHand-stockDoc.Reserved < line.Quantity {
+					return repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, fmt.Sprintf("insufficient stock for %s", describeStock(line.SKU, stockDoc.LocationID)), nil)
 				}
-				return err
-			}
-			var stockDoc stockDocument
-			if err := snap.DataTo(&stockDoc); err != nil {
-				return fmt.Errorf("decode inventory stock %s: %w", sku, err)
+				stockDoc.Reserved += line.Quantity
 			}
-			available := stockDoc.OnHand - stockDoc.Reserved
-			if available < line.Quantity {
-				return repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, fmt.Sprintf("insufficient stock for %s", sku), nil)
-			}
-			stockDoc.Reserved += line.Quantity
-			stockDoc.UpdatedAt = now
-			stockDoc.recalculate()
-			if err := tx.Set(stockRef, stockDoc); err != nil {
-				return err
-			}
-			stocks[sku] = stockDoc.toDomain(sku)
+		}
+		stocks, err := touched.write(tx, now)
+		if err != nil {
+			return err
 		}
 
-		resDoc := newReservationDocument(reservation)
 		resDoc.UpdatedAt = now
 		if resDoc.CreatedAt.IsZero() {
 			resDoc.CreatedAt = now
@@ -176,38 +175,26 @@ func (r *InventoryRepository) Commit(ctx context.Context, req repositories.Inven
 			return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reservation %s order mismatch", req.ReservationID), nil)
 		}
 
-		stocks := make(map[string]domain.InventoryStock)
+		touched, err := r.loadReservationStocks(ctx, tx, resDoc.Lines)
+		if err != nil {
+			return err
+		}
 		for _, line := range resDoc.Lines {
-			sku := strings.TrimSpace(line.SKU)
-			stockRef, err := r.stocks.DocumentRef(ctx, sku)
-			if err != nil {
-				return err
-			}
-			snap, err := tx.Get(stockRef)
-			if err != nil {
-				if status.Code(err) == codes.NotFound {
-					return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", sku), err)
+			for _, stockDoc := range touched.forLine(line) {
+				desc := describeStock(line.SKU, stockDoc.LocationID)
+				if stockDoc.Reserved < line.Quantity {
+					return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reserved quantity for %s is insufficient", desc), nil)
 				}
-				return err
-			}
-			var stockDoc stockDocument
-			if err := snap.DataTo(&stockDoc); err != nil {
-				return fmt.Errorf("decode inventory stock %s: %w", sku, err)
-			}
-			if stockDoc.Reserved < line.Quantity {
-				return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reserved quantity for %s is insufficient", sku), nil)
-			}
-			if stockDoc.OnHand < line.Quantity {
-				return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("onHand for %s cannot drop below zero", sku), nil)
-			}
-			stockDoc.Reserved -= line.Quantity
-			stockDoc.OnHand -= line.Quantity
-			stockDoc.UpdatedAt = now
-			stockDoc.recalculate()
-			if err := tx.Set(stockRef, stockDoc); err != nil {
-				return err
+				if stockDoc.OnHand < line.Quantity {
+					return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("onHand for %s cannot drop below zero", desc), nil)
+				}
+				stockDoc.Reserved -= line.Quantity
+				stockDoc.OnHand -= line.Quantity
 			}
-			stocks[sku] = stockDoc.toDomain(sku)
+		}
+		stocks, err := touched.write(tx, now)
+		if err != nil {
+			return err
 		}
 
 		resDoc.Status = reservationStatusCommitted
@@ -260,34 +247,21 @@ func (r *InventoryRepository) Release(ctx context.Context, req repositories.Inve
 			return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reservation %s not in reserved status", req.ReservationID), nil)
 		}
 
-		stocks := make(map[string]domain.InventoryStock)
+		touched, err := r.loadReservationStocks(ctx, tx, resDoc.Lines)
+		if err != nil {
+			return err
+		}
 		for _, line := range resDoc.Lines {
-			sku := strings.TrimSpace(line.SKU)
-			stockRef, err := r.stocks.DocumentRef(ctx, sku)
-			if err != nil {
-				return err
-			}
-			snap, err := tx.Get(stockRef)
-			if err != nil {
-				if status.Code(err) == codes.NotFound {
-					return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", sku), err)
+			for _, stockDoc := range touched.forLine(line) {
+				if stockDoc.Reserved < line.Quantity {
+					return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reserved quantity for %s is insufficient", describeStock(line.SKU, stockDoc.LocationID)), nil)
 				}
-				return err
+				stockDoc.Reserved -= line.Quantity
 			}
-			var stockDoc stockDocument
-			if err := snap.DataTo(&stockDoc); err != nil {
-				return fmt.Errorf("decode inventory stock %s: %w", sku, err)
-			}
-			if stockDoc.Reserved < line.Quantity {
-				return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reserved quantity for %s is insufficient", sku), nil)
-			}
-			stockDoc.Reserved -= line.Quantity
-			stockDoc.UpdatedAt = now
-			stockDoc.recalculate()
-			if err := tx.Set(stockRef, stockDoc); err != nil {
-				return err
-			}
-			stocks[sku] = stockDoc.toDomain(sku)
+		}
+		stocks, err := touched.write(tx, now)
+		if err != nil {
+			return err
 		}
 
 		resDoc.Status = reservationStatusReleased
@@ -312,6 +286,127 @@ func (r *InventoryRepository) Release(ctx context.Context, req repositories.Inve
 	return result, nil
 }
 
+// Restock returns the lines of a committed reservation to on-hand stock at the locations they were
+// committed from, appends one ledger entry per line and marks the reservation restocked, all in one
+// transaction so a retried cancellation cannot restock twice.
+func (r *InventoryRepository) Restock(ctx context.Context, req repositories.InventoryRestockRequest) (repositories.InventoryRestockResult, error) {
+	if r == nil || r.provider == nil {
+		return repositories.InventoryRestockResult{}, errors.New("inventory repository not initialised")
+	}
+	if strings.TrimSpace(req.ReservationID) == "" {
+		return repositories.InventoryRestockResult{}, errors.New("inventory restock: reservation id is required")
+	}
+
+	now := req.Now.UTC()
+	var result repositories.InventoryRestockResult
+
+	err := r.provider.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		resRef, err := r.reservations.DocumentRef(ctx, req.ReservationID)
+		if err != nil {
+			return err
+		}
+		resSnap, err := tx.Get(resRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return repositories.NewInventoryError(repositories.InventoryErrorReservationNotFound, fmt.Sprintf("reservation %s not found", req.ReservationID), err)
+			}
+			return err
+		}
+		resDoc, err := decodeReservation(resSnap)
+		if err != nil {
+			return err
+		}
+		if req.OrderRef != "" && !strings.EqualFold(resDoc.OrderRef, req.OrderRef) {
+			return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reservation %s order mismatch", req.ReservationID), nil)
+		}
+		if resDoc.Status == reservationStatusRestocked {
+			result = repositories.InventoryRestockResult{Reservation: resDoc.toDomain(req.ReservationID)}
+			return nil
+		}
+		if resDoc.Status != reservationStatusCommitted {
+			return repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, fmt.Sprintf("reservation %s is not in committed status", req.ReservationID), nil)
+		}
+
+		var lines []reservationLineDocument
+		for _, line := range resDoc.Lines {
+			if line.Quantity > 0 {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) != len(req.Events) {
+			return fmt.Errorf("inventory restock: %d events for %d lines", len(req.Events), len(lines))
+		}
+		eventRefs := make([]*firestore.DocumentRef, len(lines))
+		for i, event := range req.Events {
+			if strings.TrimSpace(event.ID) == "" {
+				return errors.New("inventory restock: event id is required")
+			}
+			if eventRefs[i], err = r.events.DocumentRef(ctx, strings.TrimSpace(event.ID)); err != nil {
+				return err
+			}
+		}
+
+		touched, err := r.loadReservationStocks(ctx, tx, lines)
+		if err != nil {
+			return err
+		}
+		events := make([]domain.InventoryStockEvent, len(lines))
+		for i, line := range lines {
+			docs := touched.forLine(line)
+			for _, stockDoc := range docs {
+				stockDoc.OnHand += line.Quantity
+			}
+			target := docs[len(docs)-1]
+
+			event := req.Events[i]
+			event.ID = strings.TrimSpace(event.ID)
+			event.ReservationID = req.ReservationID
+			event.OrderRef = resDoc.OrderRef
+			event.UserRef = resDoc.UserRef
+			event.SKU = line.SKU
+			event.ProductRef = line.ProductRef
+			event.LocationID = line.LocationID
+			event.DeltaOnHand = line.Quantity
+			event.DeltaReserved = 0
+			event.OnHand = target.OnHand
+			event.Reserved = target.Reserved
+			event.SafetyStock = target.SafetyStock
+			event.OccurredAt = now
+			events[i] = event
+		}
+		stocks, err := touched.write(tx, now)
+		if err != nil {
+			return err
+		}
+		for i, event := range events {
+			if err := tx.Create(eventRefs[i], newStockEventDocument(event)); err != nil {
+				if status.Code(err) == codes.AlreadyExists {
+					return repositories.NewInventoryError(repositories.InventoryErrorUnknown, fmt.Sprintf("stock event %s already exists", event.ID), err)
+				}
+				return err
+			}
+		}
+
+		resDoc.Status = reservationStatusRestocked
+		resDoc.UpdatedAt = now
+		resDoc.RestockedAt = &now
+		if err := tx.Set(resRef, resDoc); err != nil {
+			return err
+		}
+
+		result = repositories.InventoryRestockResult{
+			Reservation: resDoc.toDomain(req.ReservationID),
+			Stocks:      stocks,
+			Events:      events,
+		}
+		return nil
+	})
+	if err != nil {
+		return repositories.InventoryRestockResult{}, wrapInventoryError("inventory.restock", err)
+	}
+	return result, nil
+}
+
 func (r *InventoryRepository) GetReservation(ctx context.Context, reservationID string) (domain.InventoryReservation, error) {
 	if r == nil || r.reservations == nil {
 		return domain.InventoryReservation{}, errors.New("inventory repository not initialised")
@@ -350,7 +445,11 @@ func (r *InventoryRepository) ListLowStock(ctx context.Context, query repositori
 		return domain.CursorPage[domain.InventoryStock]{}, wrapInventoryError("inventory.lowStock", err)
 	}
 
+	locationID := strings.TrimSpace(query.LocationID)
 	firestoreQuery := client.Collection(inventoryCollection).Query
+	if locationID != "" {
+		firestoreQuery = client.CollectionGroup(locationStocksCollection).Where("locationId", "==", locationID)
+	}
 	if query.Threshold > 0 {
 		firestoreQuery = firestoreQuery.Where("available", "<=", query.Threshold).OrderBy("available", firestore.Asc)
 	} else {
@@ -388,9 +487,13 @@ func (r *InventoryRepository) ListLowStock(ctx context.Context, query repositori
 		}
 		var doc stockDocument
 		if err := snap.DataTo(&doc); err != nil {
-			return domain.CursorPage[domain.InventoryStock]{}, fmt.Errorf("decode inventory stock %s: %w", snap.Ref.ID, err)
+			return domain.CursorPage[domain.InventoryStock]{}, fmt.Errorf("decode inventory stock %s: %w", snap.Ref.Path, err)
+		}
+		sku := snap.Ref.ID
+		if locationID != "" {
+			sku = snap.Ref.Parent.Parent.ID
 		}
-		stocks = append(stocks, doc.toDomain(snap.Ref.ID))
+		stocks = append(stocks, doc.toDomain(sku))
 	}
 
 	hasMore := len(stocks) > pageSize
@@ -413,11 +516,573 @@ func (r *InventoryRepository) ListLowStock(ctx context.Context, query repositori
 	}, nil
 }
 
+func (r *InventoryRepository) ListExpiredReservations(ctx context.Context, query repositories.InventoryExpiredReservationQuery) (domain.CursorPage[domain.InventoryReservation], error) {
+	if r == nil || r.reservations == nil {
+		return domain.CursorPage[domain.InventoryReservation]{}, errors.New("inventory repository not initialised")
+	}
+	if query.Before.IsZero() {
+		return domain.CursorPage[domain.InventoryReservation]{}, errors.New("inventory list expired reservations: before is required")
+	}
+
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 500 {
+		pageSize = 500
+	}
+
+	client, err := r.provider.Client(ctx)
+	if err != nil {
+		return domain.CursorPage[domain.InventoryReservation]{}, wrapInventoryError("inventory.listExpired", err)
+	}
+
+	firestoreQuery := client.Collection(stockReservationsCollection).
+		Where("status", "==", reservationStatusReserved).
+		Where("expiresAt", "<", query.Before.UTC()).
+		OrderBy("expiresAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc).
+		Limit(pageSize + 1)
+
+	if token := strings.TrimSpace(query.PageToken); token != "" {
+		decoded, err := decodeReservationPageToken(token)
+		if err != nil {
+			return domain.CursorPage[domain.InventoryReservation]{}, wrapInventoryError("inventory.listExpired", err)
+		}
+		firestoreQuery = firestoreQuery.StartAfter(decoded.ExpiresAt, decoded.ID)
+	}
+
+	iter := firestoreQuery.Documents(ctx)
+	defer iter.Stop()
+
+	var reservations []domain.InventoryReservation
+	for {
+		snap, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return domain.CursorPage[domain.InventoryReservation]{}, wrapInventoryError("inventory.listExpired", err)
+		}
+		doc, err := decodeReservation(snap)
+		if err != nil {
+			return domain.CursorPage[domain.InventoryReservation]{}, err
+		}
+		reservations = append(reservations, doc.toDomain(snap.Ref.ID))
+	}
+
+	hasMore := len(reservations) > pageSize
+	if hasMore {
+		reservations = reservations[:pageSize]
+	}
+	var nextToken string
+	if hasMore && len(reservations) > 0 {
+		last := reservations[len(reservations)-1]
+		encoded, err := encodeReservationPageToken(reservationPageToken{ID: last.ID, ExpiresAt: last.ExpiresAt.UTC()})
+		if err != nil {
+			return domain.CursorPage[domain.InventoryReservation]{}, wrapInventoryError("inventory.listExpired", err)
+		}
+		nextToken = encoded
+	}
+
+	return domain.CursorPage[domain.InventoryReservation]{
+		Items:         reservations,
+		NextPageToken: nextToken,
+	}, nil
+}
+
+func (r *InventoryRepository) Adjust(ctx context.Context, req repositories.InventoryAdjustRequest) (repositories.InventoryAdjustResult, error) {
+	if r == nil || r.provider == nil {
+		return repositories.InventoryAdjustResult{}, errors.New("inventory repository not initialised")
+	}
+	event := req.Event
+	event.ID = strings.TrimSpace(event.ID)
+	event.SKU = strings.TrimSpace(event.SKU)
+	if event.ID == "" {
+		return repositories.InventoryAdjustResult{}, errors.New("inventory adjust: event id is required")
+	}
+	if event.SKU == "" {
+		return repositories.InventoryAdjustResult{}, errors.New("inventory adjust: sku is required")
+	}
+	locationID := strings.TrimSpace(event.LocationID)
+
+	now := req.Now.UTC()
+	var result repositories.InventoryAdjustResult
+	err := r.provider.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		stockRef, err := r.stocks.DocumentRef(ctx, event.SKU)
+		if err != nil {
+			return err
+		}
+		eventRef, err := r.events.DocumentRef(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+
+		stockDoc, found, err := getStock(tx, stockRef, event.SKU, "")
+		if err != nil {
+			return err
+		}
+		if !found {
+			if !req.CreateIfMissing {
+				return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", event.SKU), nil)
+			}
+			stockDoc = stockDocument{SKU: event.SKU, ProductRef: strings.TrimSpace(event.ProductRef)}
+		}
+
+		// The adjustment targets the location when one is given and the SKU aggregate otherwise;
+		// either way the aggregate moves by the same delta. SKUs stocked per location must name one so
+		// the aggregate stays the sum of its locations.
+		if locationID == "" && found {
+			located, err := hasLocationStocks(tx, stockRef)
+			if err != nil {
+				return err
+			}
+			if located {
+				return repositories.NewInventoryError(repositories.InventoryErrorLocationRequired, fmt.Sprintf("stock %s is held per location; a location is required", event.SKU), nil)
+			}
+		}
+		target := &stockDoc
+		var (
+			locationRef *firestore.DocumentRef
+			locationDoc stockDocument
+		)
+		if locationID != "" {
+			locationRef, err = r.stockRef(ctx, event.SKU, locationID)
+			if err != nil {
+				return err
+			}
+			locationDoc, found, err = getStock(tx, locationRef, event.SKU, locationID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				if !req.CreateIfMissing {
+					return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", describeStock(event.SKU, locationID)), nil)
+				}
+				// Stock kept only on the aggregate belongs to no location; opening the first location
+				// beside it would leave that quantity outside every location's sum.
+				if stockDoc.OnHand > 0 || stockDoc.Reserved > 0 {
+					located, err := hasLocationStocks(tx, stockRef)
+					if err != nil {
+						return err
+					}
+					if !located {
+						return repositories.NewInventoryError(repositories.InventoryErrorUnassignedStock, fmt.Sprintf("stock %s has %d on hand not assigned to a location; count the aggregate down before stocking it per location", event.SKU, stockDoc.OnHand), nil)
+					}
+				}
+				locationDoc = stockDocument{SKU: event.SKU, LocationID: locationID}
+			}
+			target = &locationDoc
+		}
+		desc := describeStock(event.SKU, locationID)
+
+		onHand := target.OnHand + req.DeltaOnHand
+		if req.SetOnHand != nil {
+			onHand = *req.SetOnHand
+		}
+		if onHand < 0 {
+			return repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, fmt.Sprintf("onHand for %s cannot drop below zero", desc), nil)
+		}
+		if onHand < target.OnHand && onHand < target.Reserved && !req.AllowBelowReserved {
+			return repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, fmt.Sprintf("onHand for %s cannot drop below reserved quantity %d", desc, target.Reserved), nil)
+		}
+
+		event.DeltaOnHand = onHand - target.OnHand
+		stockDoc.OnHand += event.DeltaOnHand
+		if stockDoc.OnHand < 0 {
+			return repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, fmt.Sprintf("onHand for %s cannot drop below zero", event.SKU), nil)
+		}
+		if stockDoc.ProductRef == "" {
+			stockDoc.ProductRef = strings.TrimSpace(event.ProductRef)
+		}
+		stockDoc.SKU = event.SKU
+		stockDoc.UpdatedAt = now
+		stockDoc.recalculate()
+		if err := tx.Set(stockRef, stockDoc); err != nil {
+			return err
+		}
+
+		var location *domain.InventoryStock
+		if locationID != "" {
+			locationDoc.OnHand = onHand
+			locationDoc.SKU = event.SKU
+			locationDoc.LocationID = locationID
+			locationDoc.ProductRef = stockDoc.ProductRef
+			locationDoc.UpdatedAt = now
+			locationDoc.recalculate()
+			if err := tx.Set(locationRef, locationDoc); err != nil {
+				return err
+			}
+			stock := locationDoc.toDomain(event.SKU)
+			location = &stock
+		}
+
+		event.LocationID = locationID
+		event.ProductRef = stockDoc.ProductRef
+		event.DeltaReserved = 0
+		event.OnHand = target.OnHand
+		event.Reserved = target.Reserved
+		event.SafetyStock = target.SafetyStock
+		event.OccurredAt = now
+		if err := tx.Create(eventRef, newStockEventDocument(event)); err != nil {
+			if status.Code(err) == codes.AlreadyExists {
+				return repositories.NewInventoryError(repositories.InventoryErrorUnknown, fmt.Sprintf("stock event %s already exists", event.ID), err)
+			}
+			return err
+		}
+
+		result = repositories.InventoryAdjustResult{
+			Stock:    stockDoc.toDomain(event.SKU),
+			Location: location,
+			Event:    event,
+		}
+		return nil
+	})
+	if err != nil {
+		return repositories.InventoryAdjustResult{}, wrapInventoryError("inventory.adjust", err)
+	}
+	return result, nil
+}
+
+// hasLocationStocks reports whether the SKU aggregate has any per-location stock documents.
+func hasLocationStocks(tx *firestore.Transaction, stockRef *firestore.DocumentRef) (bool, error) {
+	iter := tx.Documents(stockRef.Collection(locationStocksCollection).Limit(1))
+	defer iter.Stop()
+	if _, err := iter.Next(); err != nil {
+		if errors.Is(err, iterator.Done) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *InventoryRepository) ListStockEvents(ctx context.Context, query repositories.InventoryStockEventQuery) (domain.CursorPage[domain.InventoryStockEvent], error) {
+	if r == nil || r.events == nil {
+		return domain.CursorPage[domain.InventoryStockEvent]{}, errors.New("inventory repository not initialised")
+	}
+	sku := strings.TrimSpace(query.SKU)
+	if sku == "" {
+		return domain.CursorPage[domain.InventoryStockEvent]{}, errors.New("inventory list stock events: sku is required")
+	}
+
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 200 {
+		pageSize = 200
+	}
+
+	client, err := r.provider.Client(ctx)
+	if err != nil {
+		return domain.CursorPage[domain.InventoryStockEvent]{}, wrapInventoryError("inventory.listStockEvents", err)
+	}
+
+	firestoreQuery := client.Collection(inventoryStockEventsCollection).
+		Where("sku", "==", sku).
+		OrderBy("occurredAt", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc).
+		Limit(pageSize + 1)
+
+	if token := strings.TrimSpace(query.PageToken); token != "" {
+		decoded, err := decodeStockEventPageToken(token)
+		if err != nil {
+			return domain.CursorPage[domain.InventoryStockEvent]{}, wrapInventoryError("inventory.listStockEvents", err)
+		}
+		firestoreQuery = firestoreQuery.StartAfter(decoded.OccurredAt, decoded.ID)
+	}
+
+	iter := firestoreQuery.Documents(ctx)
+	defer iter.Stop()
+
+	var events []domain.InventoryStockEvent
+	for {
+		snap, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return domain.CursorPage[domain.InventoryStockEvent]{}, wrapInventoryError("inventory.listStockEvents", err)
+		}
+		var doc stockEventDocument
+		if err := snap.DataTo(&doc); err != nil {
+			return domain.CursorPage[domain.InventoryStockEvent]{}, fmt.Errorf("decode stock event %s: %w", snap.Ref.ID, err)
+		}
+		events = append(events, doc.toDomain(snap.Ref.ID))
+	}
+
+	hasMore := len(events) > pageSize
+	if hasMore {
+		events = events[:pageSize]
+	}
+	var nextToken string
+	if hasMore && len(events) > 0 {
+		last := events[len(events)-1]
+		encoded, err := encodeStockEventPageToken(stockEventPageToken{ID: last.ID, OccurredAt: last.OccurredAt.UTC()})
+		if err != nil {
+			return domain.CursorPage[domain.InventoryStockEvent]{}, wrapInventoryError("inventory.listStockEvents", err)
+		}
+		nextToken = encoded
+	}
+
+	return domain.CursorPage[domain.InventoryStockEvent]{
+		Items:         events,
+		NextPageToken: nextToken,
+	}, nil
+}
+
+func (r *InventoryRepository) ListLocationStocks(ctx context.Context, sku string) ([]domain.InventoryStock, error) {
+	if r == nil || r.stocks == nil {
+		return nil, errors.New("inventory repository not initialised")
+	}
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil, errors.New("inventory list location stocks: sku is required")
+	}
+
+	stockRef, err := r.stocks.DocumentRef(ctx, sku)
+	if err != nil {
+		return nil, wrapInventoryError("inventory.listLocationStocks", err)
+	}
+	iter := stockRef.Collection(locationStocksCollection).Documents(ctx)
+	defer iter.Stop()
+
+	var stocks []domain.InventoryStock
+	for {
+		snap, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, wrapInventoryError("inventory.listLocationStocks", err)
+		}
+		var doc stockDocument
+		if err := snap.DataTo(&doc); err != nil {
+			return nil, fmt.Errorf("decode inventory stock %s: %w", describeStock(sku, snap.Ref.ID), err)
+		}
+		doc.LocationID = snap.Ref.ID
+		stocks = append(stocks, doc.toDomain(sku))
+	}
+	return stocks, nil
+}
+
+func (r *InventoryRepository) Transfer(ctx context.Context, req repositories.InventoryTransferRequest) (repositories.InventoryTransferResult, error) {
+	if r == nil || r.provider == nil {
+		return repositories.InventoryTransferResult{}, errors.New("inventory repository not initialised")
+	}
+	sku := strings.TrimSpace(req.SKU)
+	from := strings.TrimSpace(req.FromLocationID)
+	to := strings.TrimSpace(req.ToLocationID)
+	outEvent := req.OutEvent
+	inEvent := req.InEvent
+	outEvent.ID = strings.TrimSpace(outEvent.ID)
+	inEvent.ID = strings.TrimSpace(inEvent.ID)
+	switch {
+	case sku == "":
+		return repositories.InventoryTransferResult{}, errors.New("inventory transfer: sku is required")
+	case from == "" || to == "":
+		return repositories.InventoryTransferResult{}, errors.New("inventory transfer: source and destination locations are required")
+	case from == to:
+		return repositories.InventoryTransferResult{}, errors.New("inventory transfer: source and destination must differ")
+	case req.Quantity <= 0:
+		return repositories.InventoryTransferResult{}, errors.New("inventory transfer: quantity must be > 0")
+	case outEvent.ID == "" || inEvent.ID == "":
+		return repositories.InventoryTransferResult{}, errors.New("inventory transfer: event ids are required")
+	}
+
+	now := req.Now.UTC()
+	var result repositories.InventoryTransferResult
+	err := r.provider.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		stockRef, err := r.stocks.DocumentRef(ctx, sku)
+		if err != nil {
+			return err
+		}
+		fromRef, err := r.stockRef(ctx, sku, from)
+		if err != nil {
+			return err
+		}
+		toRef, err := r.stockRef(ctx, sku, to)
+		if err != nil {
+			return err
+		}
+		outRef, err := r.events.DocumentRef(ctx, outEvent.ID)
+		if err != nil {
+			return err
+		}
+		inRef, err := r.events.DocumentRef(ctx, inEvent.ID)
+		if err != nil {
+			return err
+		}
+
+		stockDoc, found, err := getStock(tx, stockRef, sku, "")
+		if err != nil {
+			return err
+		}
+		if !found {
+			return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", sku), nil)
+		}
+		fromDoc, found, err := getStock(tx, fromRef, sku, from)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", describeStock(sku, from)), nil)
+		}
+		toDoc, _, err := getStock(tx, toRef, sku, to)
+		if err != nil {
+			return err
+		}
+
+		if fromDoc.OnHand-fromDoc.Reserved < req.Quantity {
+			return repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, fmt.Sprintf("insufficient stock for %s", describeStock(sku, from)), nil)
+		}
+		fromDoc.OnHand -= req.Quantity
+		toDoc.OnHand += req.Quantity
+
+		events := make([]domain.InventoryStockEvent, 0, 2)
+		for _, leg := range []struct {
+			ref         *firestore.DocumentRef
+			doc         *stockDocument
+			location    string
+			eventRef    *firestore.DocumentRef
+			event       domain.InventoryStockEvent
+			defaultType string
+			delta       int
+		}{
+			{ref: fromRef, doc: &fromDoc, location: from, eventRef: outRef, event: outEvent, defaultType: stockEventTransferOut, delta: -req.Quantity},
+			{ref: toRef, doc: &toDoc, location: to, eventRef: inRef, event: inEvent, defaultType: stockEventTransferIn, delta: req.Quantity},
+		} {
+			leg.doc.SKU = sku
+			leg.doc.LocationID = leg.location
+			leg.doc.ProductRef = stockDoc.ProductRef
+			leg.doc.UpdatedAt = now
+			leg.doc.recalculate()
+			if err := tx.Set(leg.ref, *leg.doc); err != nil {
+				return err
+			}
+
+			event := leg.event
+			if strings.TrimSpace(event.Type) == "" {
+				event.Type = leg.defaultType
+			}
+			event.SKU = sku
+			event.LocationID = leg.location
+			event.ProductRef = stockDoc.ProductRef
+			event.DeltaOnHand = leg.delta
+			event.DeltaReserved = 0
+			event.OnHand = leg.doc.OnHand
+			event.Reserved = leg.doc.Reserved
+			event.SafetyStock = leg.doc.SafetyStock
+			event.OccurredAt = now
+			if err := tx.Create(leg.eventRef, newStockEventDocument(event)); err != nil {
+				if status.Code(err) == codes.AlreadyExists {
+					return repositories.NewInventoryError(repositories.InventoryErrorUnknown, fmt.Sprintf("stock event %s already exists", event.ID), err)
+				}
+				return err
+			}
+			events = append(events, event)
+		}
+
+		result = repositories.InventoryTransferResult{
+			From:   fromDoc.toDomain(sku),
+			To:     toDoc.toDomain(sku),
+			Events: events,
+		}
+		return nil
+	})
+	if err != nil {
+		return repositories.InventoryTransferResult{}, wrapInventoryError("inventory.transfer", err)
+	}
+	return result, nil
+}
+
+func (r *InventoryRepository) ListLocations(ctx context.Context) ([]domain.InventoryLocation, error) {
+	if r == nil || r.locations == nil {
+		return nil, errors.New("inventory repository not initialised")
+	}
+	docs, err := r.locations.Query(ctx, func(q firestore.Query) firestore.Query {
+		return q.OrderBy("priority", firestore.Asc)
+	})
+	if err != nil {
+		return nil, wrapInventoryError("inventory.listLocations", err)
+	}
+	locations := make([]domain.InventoryLocation, 0, len(docs))
+	for _, doc := range docs {
+		locations = append(locations, doc.Data.toDomain(doc.ID))
+	}
+	return locations, nil
+}
+
+func (r *InventoryRepository) GetLocation(ctx context.Context, locationID string) (domain.InventoryLocation, error) {
+	if r == nil || r.locations == nil {
+		return domain.InventoryLocation{}, errors.New("inventory repository not initialised")
+	}
+	locationID = strings.TrimSpace(locationID)
+	if locationID == "" {
+		return domain.InventoryLocation{}, errors.New("inventory get location: id is required")
+	}
+
+	doc, err := r.locations.Get(ctx, locationID)
+	if err != nil {
+		if repoErr, ok := err.(*pfirestore.Error); ok && repoErr.IsNotFound() {
+			return domain.InventoryLocation{}, repositories.NewInventoryError(repositories.InventoryErrorLocationNotFound, fmt.Sprintf("location %s not found", locationID), err)
+		}
+		return domain.InventoryLocation{}, wrapInventoryError("inventory.getLocation", err)
+	}
+	return doc.Data.toDomain(doc.ID), nil
+}
+
+func (r *InventoryRepository) UpsertLocation(ctx context.Context, location domain.InventoryLocation) (domain.InventoryLocation, error) {
+	if r == nil || r.provider == nil {
+		return domain.InventoryLocation{}, errors.New("inventory repository not initialised")
+	}
+	location.ID = strings.TrimSpace(location.ID)
+	if location.ID == "" {
+		return domain.InventoryLocation{}, errors.New("inventory upsert location: id is required")
+	}
+
+	var saved domain.InventoryLocation
+	err := r.provider.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		ref, err := r.locations.DocumentRef(ctx, location.ID)
+		if err != nil {
+			return err
+		}
+		doc := newLocationDocument(location)
+		snap, err := tx.Get(ref)
+		switch {
+		case err == nil:
+			var existing locationDocument
+			if err := snap.DataTo(&existing); err != nil {
+				return fmt.Errorf("decode inventory location %s: %w", location.ID, err)
+			}
+			if !existing.CreatedAt.IsZero() {
+				doc.CreatedAt = existing.CreatedAt
+			}
+		case status.Code(err) != codes.NotFound:
+			return err
+		}
+		if doc.CreatedAt.IsZero() {
+			doc.CreatedAt = doc.UpdatedAt
+		}
+		if err := tx.Set(ref, doc); err != nil {
+			return err
+		}
+		saved = doc.toDomain(location.ID)
+		return nil
+	})
+	if err != nil {
+		return domain.InventoryLocation{}, wrapInventoryError("inventory.upsertLocation", err)
+	}
+	return saved, nil
+}
+
 // Helper structures ---------------------------------------------------------
 
 type stockDocument struct {
 	SKU         string    `firestore:"sku"`
 	ProductRef  string    `firestore:"productRef"`
+	LocationID  string    `firestore:"locationId,omitempty"`
 	OnHand      int       `firestore:"onHand"`
 	Reserved    int       `firestore:"reserved"`
 	Available   int       `firestore:"available"`
@@ -435,6 +1100,7 @@ func (s stockDocument) toDomain(id string) domain.InventoryStock {
 	return domain.InventoryStock{
 		SKU:         id,
 		ProductRef:  strings.TrimSpace(s.ProductRef),
+		LocationID:  strings.TrimSpace(s.LocationID),
 		OnHand:      s.OnHand,
 		Reserved:    s.Reserved,
 		Available:   s.Available,
@@ -444,6 +1110,166 @@ func (s stockDocument) toDomain(id string) domain.InventoryStock {
 	}
 }
 
+// stockRef returns the SKU aggregate document, or the per-location document nested under it when
+// locationID is set.
+func (r *InventoryRepository) stockRef(ctx context.Context, sku, locationID string) (*firestore.DocumentRef, error) {
+	ref, err := r.stocks.DocumentRef(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	if locationID == "" {
+		return ref, nil
+	}
+	return ref.Collection(locationStocksCollection).Doc(locationID), nil
+}
+
+// getStock reads a stock document inside tx; found is false when it does not exist.
+func getStock(tx *firestore.Transaction, ref *firestore.DocumentRef, sku, locationID string) (stockDocument, bool, error) {
+	snap, err := tx.Get(ref)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return stockDocument{}, false, nil
+		}
+		return stockDocument{}, false, err
+	}
+	var doc stockDocument
+	if err := snap.DataTo(&doc); err != nil {
+		return stockDocument{}, false, fmt.Errorf("decode inventory stock %s: %w", describeStock(sku, locationID), err)
+	}
+	doc.LocationID = locationID
+	return doc, true, nil
+}
+
+func describeStock(sku, locationID string) string {
+	if locationID == "" {
+		return sku
+	}
+	return fmt.Sprintf("%s at location %s", sku, locationID)
+}
+
+// reservationStocks holds the stock documents touched by a reservation, keyed by SKU for aggregates
+// and by SKU and location for located lines. Firestore transactions reject reads after writes, so
+// every document is loaded before the first write.
+type reservationStocks struct {
+	refs  map[string]*firestore.DocumentRef
+	docs  map[string]*stockDocument
+	order []string
+}
+
+func (r *InventoryRepository) loadReservationStocks(ctx context.Context, tx *firestore.Transaction, lines []reservationLineDocument) (*reservationStocks, error) {
+	touched := &reservationStocks{
+		refs: make(map[string]*firestore.DocumentRef),
+		docs: make(map[string]*stockDocument),
+	}
+	for _, line := range lines {
+		if err := touched.load(ctx, tx, r, line.SKU, ""); err != nil {
+			return nil, err
+		}
+		if line.LocationID != "" {
+			if err := touched.load(ctx, tx, r, line.SKU, line.LocationID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return touched, nil
+}
+
+func (s *reservationStocks) load(ctx context.Context, tx *firestore.Transaction, r *InventoryRepository, sku, locationID string) error {
+	key := stockKey(sku, locationID)
+	if _, ok := s.docs[key]; ok {
+		return nil
+	}
+	ref, err := r.stockRef(ctx, sku, locationID)
+	if err != nil {
+		return err
+	}
+	doc, found, err := getStock(tx, ref, sku, locationID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, fmt.Sprintf("stock %s not found", describeStock(sku, locationID)), nil)
+	}
+	s.refs[key] = ref
+	s.docs[key] = &doc
+	s.order = append(s.order, key)
+	return nil
+}
+
+// forLine returns the aggregate document for the line's SKU followed by its location document, if any.
+func (s *reservationStocks) forLine(line reservationLineDocument) []*stockDocument {
+	docs := []*stockDocument{s.docs[stockKey(line.SKU, "")]}
+	if line.LocationID != "" {
+		docs = append(docs, s.docs[stockKey(line.SKU, line.LocationID)])
+	}
+	return docs
+}
+
+// write stores every touched document and returns the SKU aggregates keyed by SKU.
+func (s *reservationStocks) write(tx *firestore.Transaction, now time.Time) (map[string]domain.InventoryStock, error) {
+	aggregates := make(map[string]domain.InventoryStock)
+	for _, key := range s.order {
+		doc := s.docs[key]
+		doc.UpdatedAt = now
+		doc.recalculate()
+		if err := tx.Set(s.refs[key], *doc); err != nil {
+			return nil, err
+		}
+		if doc.LocationID == "" {
+			aggregates[key] = doc.toDomain(key)
+		}
+	}
+	return aggregates, nil
+}
+
+func stockKey(sku, locationID string) string {
+	if locationID == "" {
+		return sku
+	}
+	return sku + "@" + locationID
+}
+
+type locationDocument struct {
+	Name              string    `firestore:"name"`
+	Prefecture        string    `firestore:"prefecture"`
+	NearbyPrefectures []string  `firestore:"nearbyPrefectures,omitempty"`
+	Priority          int       `firestore:"priority"`
+	Active            bool      `firestore:"active"`
+	CreatedAt         time.Time `firestore:"createdAt"`
+	UpdatedAt         time.Time `firestore:"updatedAt"`
+}
+
+func newLocationDocument(location domain.InventoryLocation) locationDocument {
+	var nearby []string
+	for _, prefecture := range location.NearbyPrefectures {
+		if trimmed := strings.TrimSpace(prefecture); trimmed != "" {
+			nearby = append(nearby, trimmed)
+		}
+	}
+	return locationDocument{
+		Name:              strings.TrimSpace(location.Name),
+		Prefecture:        strings.TrimSpace(location.Prefecture),
+		NearbyPrefectures: nearby,
+		Priority:          location.Priority,
+		Active:            location.Active,
+		CreatedAt:         location.CreatedAt.UTC(),
+		UpdatedAt:         location.UpdatedAt.UTC(),
+	}
+}
+
+func (d locationDocument) toDomain(id string) domain.InventoryLocation {
+	return domain.InventoryLocation{
+		ID:                id,
+		Name:              d.Name,
+		Prefecture:        d.Prefecture,
+		NearbyPrefectures: append([]string(nil), d.NearbyPrefectures...),
+		Priority:          d.Priority,
+		Active:            d.Active,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+	}
+}
+
 type reservationDocument struct {
 	OrderRef       string                    `firestore:"orderRef"`
 	UserRef        string                    `firestore:"userRef"`
@@ -454,6 +1280,7 @@ type reservationDocument struct {
 	ExpiresAt      time.Time                 `firestore:"expiresAt"`
 	ReleasedAt     *time.Time                `firestore:"releasedAt,omitempty"`
 	CommittedAt    *time.Time                `firestore:"committedAt,omitempty"`
+	RestockedAt    *time.Time                `firestore:"restockedAt,omitempty"`
 	CreatedAt      time.Time                 `firestore:"createdAt"`
 	UpdatedAt      time.Time                 `firestore:"updatedAt"`
 }
@@ -462,6 +1289,7 @@ type reservationLineDocument struct {
 	ProductRef string `firestore:"productRef"`
 	SKU        string `firestore:"sku"`
 	Quantity   int    `firestore:"qty"`
+	LocationID string `firestore:"locationId,omitempty"`
 }
 
 func newReservationDocument(res domain.InventoryReservation) reservationDocument {
@@ -471,6 +1299,7 @@ func newReservationDocument(res domain.InventoryReservation) reservationDocument
 			ProductRef: strings.TrimSpace(line.ProductRef),
 			SKU:        strings.TrimSpace(line.SKU),
 			Quantity:   line.Quantity,
+			LocationID: strings.TrimSpace(line.LocationID),
 		}
 	}
 	return reservationDocument{
@@ -483,11 +1312,102 @@ func newReservationDocument(res domain.InventoryReservation) reservationDocument
 		ExpiresAt:      res.ExpiresAt.UTC(),
 		ReleasedAt:     res.ReleasedAt,
 		CommittedAt:    res.CommittedAt,
+		RestockedAt:    res.RestockedAt,
 		CreatedAt:      res.CreatedAt.UTC(),
 		UpdatedAt:      res.UpdatedAt.UTC(),
 	}
 }
 
+type stockEventDocument struct {
+	Type          string         `firestore:"type"`
+	LocationID    string         `firestore:"locationId,omitempty"`
+	ReservationID string         `firestore:"reservationId,omitempty"`
+	OrderRef      string         `firestore:"orderRef,omitempty"`
+	UserRef       string         `firestore:"userRef,omitempty"`
+	SKU           string         `firestore:"sku"`
+	ProductRef    string         `firestore:"productRef,omitempty"`
+	DeltaOnHand   int            `firestore:"deltaOnHand"`
+	DeltaReserved int            `firestore:"deltaReserved"`
+	OnHand        int            `firestore:"onHand"`
+	Reserved      int            `firestore:"reserved"`
+	SafetyStock   int            `firestore:"safetyStock"`
+	ActorID       string         `firestore:"actorId,omitempty"`
+	Reason        string         `firestore:"reason,omitempty"`
+	OccurredAt    time.Time      `firestore:"occurredAt"`
+	Metadata      map[string]any `firestore:"metadata,omitempty"`
+}
+
+func newStockEventDocument(event domain.InventoryStockEvent) stockEventDocument {
+	return stockEventDocument{
+		Type:          strings.TrimSpace(event.Type),
+		LocationID:    strings.TrimSpace(event.LocationID),
+		ReservationID: strings.TrimSpace(event.ReservationID),
+		OrderRef:      strings.TrimSpace(event.OrderRef),
+		UserRef:       strings.TrimSpace(event.UserRef),
+		SKU:           strings.TrimSpace(event.SKU),
+		ProductRef:    strings.TrimSpace(event.ProductRef),
+		DeltaOnHand:   event.DeltaOnHand,
+		DeltaReserved: event.DeltaReserved,
+		OnHand:        event.OnHand,
+		Reserved:      event.Reserved,
+		SafetyStock:   event.SafetyStock,
+		ActorID:       strings.TrimSpace(event.ActorID),
+		Reason:        strings.TrimSpace(event.Reason),
+		OccurredAt:    event.OccurredAt.UTC(),
+		Metadata:      event.Metadata,
+	}
+}
+
+func (d stockEventDocument) toDomain(id string) domain.InventoryStockEvent {
+	return domain.InventoryStockEvent{
+		ID:            id,
+		Type:          d.Type,
+		LocationID:    d.LocationID,
+		ReservationID: d.ReservationID,
+		OrderRef:      d.OrderRef,
+		UserRef:       d.UserRef,
+		SKU:           d.SKU,
+		ProductRef:    d.ProductRef,
+		DeltaOnHand:   d.DeltaOnHand,
+		DeltaReserved: d.DeltaReserved,
+		OnHand:        d.OnHand,
+		Reserved:      d.Reserved,
+		SafetyStock:   d.SafetyStock,
+		ActorID:       d.ActorID,
+		Reason:        d.Reason,
+		OccurredAt:    d.OccurredAt,
+		Metadata:      d.Metadata,
+	}
+}
+
+type stockEventPageToken struct {
+	ID         string
+	OccurredAt time.Time
+}
+
+func encodeStockEventPageToken(token stockEventPageToken) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("encode stock event page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeStockEventPageToken(encoded string) (*stockEventPageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode stock event page token: %w", err)
+	}
+	var token stockEventPageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("decode stock event page token json: %w", err)
+	}
+	if strings.TrimSpace(token.ID) == "" || token.OccurredAt.IsZero() {
+		return nil, errors.New("decode stock event page token: incomplete cursor")
+	}
+	return &token, nil
+}
+
 type inventoryPageToken struct {
 	SKU         string
 	Available   int
@@ -515,6 +1435,34 @@ func decodeInventoryPageToken(encoded string) (*inventoryPageToken, error) {
 	return &token, nil
 }
 
+type reservationPageToken struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+func encodeReservationPageToken(token reservationPageToken) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("encode reservation page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeReservationPageToken(encoded string) (*reservationPageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode reservation page token: %w", err)
+	}
+	var token reservationPageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("decode reservation page token json: %w", err)
+	}
+	if strings.TrimSpace(token.ID) == "" || token.ExpiresAt.IsZero() {
+		return nil, errors.New("decode reservation page token: incomplete cursor")
+	}
+	return &token, nil
+}
+
 func (d reservationDocument) toDomain(id string) domain.InventoryReservation {
 	lines := make([]domain.InventoryReservationLine, len(d.Lines))
 	for i, line := range d.Lines {
@@ -522,6 +1470,7 @@ func (d reservationDocument) toDomain(id string) domain.InventoryReservation {
 			ProductRef: strings.TrimSpace(line.ProductRef),
 			SKU:        strings.TrimSpace(line.SKU),
 			Quantity:   line.Quantity,
+			LocationID: strings.TrimSpace(line.LocationID),
 		}
 	}
 	return domain.InventoryReservation{
@@ -535,6 +1484,7 @@ func (d reservationDocument) toDomain(id string) domain.InventoryReservation {
 		ExpiresAt:      d.ExpiresAt,
 		ReleasedAt:     d.ReleasedAt,
 		CommittedAt:    d.CommittedAt,
+		RestockedAt:    d.RestockedAt,
 		CreatedAt:      d.CreatedAt,
 		UpdatedAt:      d.UpdatedAt,
 	}
@@ -191,6 +191,54 @@ func TestInventoryRepositoryIntegration(t *testing.T) {
 		t.Fatalf("expected released status, got %s", releaseResult.Reservation.Status)
 	}
 
+	expiring := []string{"sr_expire_a", "sr_expire_b"}
+	for i, id := range expiring {
+		if _, err := repo.Reserve(ctx, repositories.InventoryReserveRequest{
+			Reservation: domain.InventoryReservation{
+				ID:        id,
+				OrderRef:  fmt.Sprintf("/orders/o_%s", id),
+				UserRef:   "/users/u_test",
+				Lines:     []domain.InventoryReservationLine{{ProductRef: "/products/prod_001", SKU: "SKU-001", Quantity: 1}},
+				ExpiresAt: now.Add(time.Duration(i+1) * time.Minute),
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			Now: now,
+		}); err != nil {
+			t.Fatalf("reserve %s: %v", id, err)
+		}
+	}
+
+	expiredPage, err := repo.ListExpiredReservations(ctx, repositories.InventoryExpiredReservationQuery{
+		Before:   now.Add(5 * time.Minute),
+		PageSize: 1,
+	})
+	if err != nil {
+		t.Fatalf("list expired reservations: %v", err)
+	}
+	if len(expiredPage.Items) != 1 || expiredPage.Items[0].ID != "sr_expire_a" {
+		t.Fatalf("expected first expired reservation sr_expire_a, got %+v", expiredPage.Items)
+	}
+	if expiredPage.NextPageToken == "" {
+		t.Fatalf("expected next page token for expired reservations")
+	}
+	expiredPage, err = repo.ListExpiredReservations(ctx, repositories.InventoryExpiredReservationQuery{
+		Before:    now.Add(5 * time.Minute),
+		PageSize:  1,
+		PageToken: expiredPage.NextPageToken,
+	})
+	if err != nil {
+		t.Fatalf("list expired reservations page 2: %v", err)
+	}
+	if len(expiredPage.Items) != 1 || expiredPage.Items[0].ID != "sr_expire_b" {
+		t.Fatalf("expected second expired reservation sr_expire_b, got %+v", expiredPage.Items)
+	}
+	for _, id := range expiring {
+		if _, err := repo.Release(ctx, repositories.InventoryReleaseRequest{ReservationID: id, Reason: "expired", Now: now.Add(5 * time.Minute)}); err != nil {
+			t.Fatalf("release %s: %v", id, err)
+		}
+	}
+
 	lowPage, err := repo.ListLowStock(ctx, repositories.InventoryLowStockQuery{Threshold: 0, PageSize: 10})
 	if err != nil {
 		t.Fatalf("list low stock: %v", err)
@@ -202,6 +250,186 @@ func TestInventoryRepositoryIntegration(t *testing.T) {
 	if lowPage.Items[0].SafetyDelta >= 0 {
 		t.Fatalf("expected negative safety delta, got %d", lowPage.Items[0].SafetyDelta)
 	}
+
+	adjustments := []repositories.InventoryAdjustRequest{
+		{Event: domain.InventoryStockEvent{ID: "ise_receipt", Type: "inventory.receipt", SKU: "SKU-001", ActorID: "staff_1"}, DeltaOnHand: 4, CreateIfMissing: true, Now: now.Add(10 * time.Minute)},
+		{Event: domain.InventoryStockEvent{ID: "ise_damage", Type: "inventory.damage", SKU: "SKU-001", ActorID: "staff_1", Reason: "cracked"}, DeltaOnHand: -1, Now: now.Add(11 * time.Minute)},
+	}
+	counted := 3
+	adjustments = append(adjustments, repositories.InventoryAdjustRequest{
+		Event: domain.InventoryStockEvent{ID: "ise_count", Type: "inventory.cycle_count", SKU: "SKU-001", ActorID: "staff_1"}, SetOnHand: &counted, AllowBelowReserved: true, Now: now.Add(12 * time.Minute),
+	})
+	for _, adjustment := range adjustments {
+		if _, err := repo.Adjust(ctx, adjustment); err != nil {
+			t.Fatalf("adjust %s: %v", adjustment.Event.ID, err)
+		}
+	}
+	_, err = repo.Adjust(ctx, repositories.InventoryAdjustRequest{
+		Event:       domain.InventoryStockEvent{ID: "ise_overdraw", Type: "inventory.damage", SKU: "SKU-001", ActorID: "staff_1", Reason: "lost"},
+		DeltaOnHand: -4,
+		Now:         now.Add(13 * time.Minute),
+	})
+	invErr = nil
+	if !errors.As(err, &invErr) || invErr.Code != repositories.InventoryErrorInsufficientStock {
+		t.Fatalf("expected insufficient stock for overdraw, got %v", err)
+	}
+	if _, err := repo.Adjust(ctx, repositories.InventoryAdjustRequest{
+		Event:       domain.InventoryStockEvent{ID: "ise_missing", Type: "inventory.correction", SKU: "SKU-404", ActorID: "staff_1", Reason: "typo"},
+		DeltaOnHand: 1,
+		Now:         now.Add(13 * time.Minute),
+	}); !errors.As(err, &invErr) || invErr.Code != repositories.InventoryErrorStockNotFound {
+		t.Fatalf("expected stock not found for correction, got %v", err)
+	}
+
+	history, err := repo.ListStockEvents(ctx, repositories.InventoryStockEventQuery{SKU: "SKU-001", PageSize: 2})
+	if err != nil {
+		t.Fatalf("list stock events: %v", err)
+	}
+	if len(history.Items) != 2 || history.Items[0].ID != "ise_count" || history.Items[0].DeltaOnHand != -2 || history.Items[0].OnHand != 3 {
+		t.Fatalf("unexpected newest stock events: %+v", history.Items)
+	}
+	history, err = repo.ListStockEvents(ctx, repositories.InventoryStockEventQuery{SKU: "SKU-001", PageSize: 2, PageToken: history.NextPageToken})
+	if err != nil {
+		t.Fatalf("list stock events page 2: %v", err)
+	}
+	if len(history.Items) != 1 || history.Items[0].ID != "ise_receipt" || history.Items[0].DeltaOnHand != 4 || history.NextPageToken != "" {
+		t.Fatalf("unexpected oldest stock events: %+v", history.Items)
+	}
+
+	for _, location := range []domain.InventoryLocation{
+		{ID: "workshop", Name: "Workshop", Prefecture: "Kyoto", Priority: 1, Active: true, UpdatedAt: now},
+		{ID: "partner", Name: "Partner", Prefecture: "Saitama", Priority: 2, Active: true, UpdatedAt: now},
+	} {
+		if _, err := repo.UpsertLocation(ctx, location); err != nil {
+			t.Fatalf("upsert location %s: %v", location.ID, err)
+		}
+	}
+	locations, err := repo.ListLocations(ctx)
+	if err != nil || len(locations) != 2 || locations[0].ID != "workshop" {
+		t.Fatalf("unexpected locations %+v (err %v)", locations, err)
+	}
+
+	if _, err := repo.Adjust(ctx, repositories.InventoryAdjustRequest{
+		Event:           domain.InventoryStockEvent{ID: "ise_unassigned", Type: "inventory.receipt", SKU: "SKU-001", LocationID: "workshop", ActorID: "staff_1"},
+		DeltaOnHand:     1,
+		CreateIfMissing: true,
+		Now:             now.Add(19 * time.Minute),
+	}); !errors.As(err, &invErr) || invErr.Code != repositories.InventoryErrorUnassignedStock {
+		t.Fatalf("expected unassigned stock for first location receipt, got %v", err)
+	}
+
+	receipt, err := repo.Adjust(ctx, repositories.InventoryAdjustRequest{
+		Event:           domain.InventoryStockEvent{ID: "ise_loc_receipt", Type: "inventory.receipt", SKU: "SKU-LOC", LocationID: "workshop", ProductRef: "/products/prod_loc", ActorID: "staff_1"},
+		DeltaOnHand:     6,
+		CreateIfMissing: true,
+		Now:             now.Add(20 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("location receipt: %v", err)
+	}
+	if receipt.Stock.OnHand != 6 || receipt.Location == nil || receipt.Location.OnHand != 6 || receipt.Location.LocationID != "workshop" {
+		t.Fatalf("unexpected location receipt result %+v", receipt)
+	}
+
+	counted = 10
+	if _, err := repo.Adjust(ctx, repositories.InventoryAdjustRequest{
+		Event:              domain.InventoryStockEvent{ID: "ise_loc_aggregate", Type: "inventory.cycle_count", SKU: "SKU-LOC", ActorID: "staff_1"},
+		SetOnHand:          &counted,
+		AllowBelowReserved: true,
+		Now:                now.Add(20 * time.Minute),
+	}); !errors.As(err, &invErr) || invErr.Code != repositories.InventoryErrorLocationRequired {
+		t.Fatalf("expected location required for aggregate count of located stock, got %v", err)
+	}
+
+	transfer, err := repo.Transfer(ctx, repositories.InventoryTransferRequest{
+		SKU:            "SKU-LOC",
+		FromLocationID: "workshop",
+		ToLocationID:   "partner",
+		Quantity:       2,
+		OutEvent:       domain.InventoryStockEvent{ID: "ise_loc_out", ActorID: "staff_1"},
+		InEvent:        domain.InventoryStockEvent{ID: "ise_loc_in", ActorID: "staff_1"},
+		Now:            now.Add(21 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if transfer.From.OnHand != 4 || transfer.To.OnHand != 2 || len(transfer.Events) != 2 || transfer.Events[1].Type != stockEventTransferIn {
+		t.Fatalf("unexpected transfer result %+v", transfer)
+	}
+
+	locReserve, err := repo.Reserve(ctx, repositories.InventoryReserveRequest{
+		Reservation: domain.InventoryReservation{
+			ID:        "sr_loc_1",
+			OrderRef:  "/orders/o_loc",
+			UserRef:   "/users/u_loc",
+			Lines:     []domain.InventoryReservationLine{{ProductRef: "/products/prod_loc", SKU: "SKU-LOC", Quantity: 2, LocationID: "partner"}},
+			ExpiresAt: now.Add(time.Hour),
+		},
+		Now: now.Add(22 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("reserve at location: %v", err)
+	}
+	if stock := locReserve.Stocks["SKU-LOC"]; stock.Reserved != 2 || stock.Available != 4 {
+		t.Fatalf("unexpected aggregate after located reserve %+v", stock)
+	}
+	if locReserve.Reservation.Lines[0].LocationID != "partner" {
+		t.Fatalf("expected location persisted on reservation line, got %+v", locReserve.Reservation.Lines)
+	}
+	_, err = repo.Reserve(ctx, repositories.InventoryReserveRequest{
+		Reservation: domain.InventoryReservation{
+			ID:        "sr_loc_2",
+			Lines:     []domain.InventoryReservationLine{{ProductRef: "/products/prod_loc", SKU: "SKU-LOC", Quantity: 1, LocationID: "partner"}},
+			ExpiresAt: now.Add(time.Hour),
+		},
+		Now: now.Add(23 * time.Minute),
+	})
+	invErr = nil
+	if !errors.As(err, &invErr) || invErr.Code != repositories.InventoryErrorInsufficientStock {
+		t.Fatalf("expected partner location to be exhausted, got %v", err)
+	}
+
+	perLocation, err := repo.ListLocationStocks(ctx, "SKU-LOC")
+	if err != nil || len(perLocation) != 2 {
+		t.Fatalf("unexpected location stocks %+v (err %v)", perLocation, err)
+	}
+	lowAtPartner, err := repo.ListLowStock(ctx, repositories.InventoryLowStockQuery{LocationID: "partner", Threshold: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("low stock at partner: %v", err)
+	}
+	if len(lowAtPartner.Items) != 1 || lowAtPartner.Items[0].SKU != "SKU-LOC" || lowAtPartner.Items[0].LocationID != "partner" || lowAtPartner.Items[0].Available != 0 {
+		t.Fatalf("unexpected partner low stock %+v", lowAtPartner.Items)
+	}
+
+	if _, err := repo.Commit(ctx, repositories.InventoryCommitRequest{ReservationID: "sr_loc_1", Now: now.Add(24 * time.Minute)}); err != nil {
+		t.Fatalf("commit located reservation: %v", err)
+	}
+	restockReq := repositories.InventoryRestockRequest{
+		ReservationID: "sr_loc_1",
+		OrderRef:      "/orders/o_loc",
+		Events:        []domain.InventoryStockEvent{{ID: "ise_restock_loc", Type: "inventory.restock", ActorID: "system"}},
+		Now:           now.Add(25 * time.Minute),
+	}
+	restock, err := repo.Restock(ctx, restockReq)
+	if err != nil {
+		t.Fatalf("restock: %v", err)
+	}
+	if restock.Reservation.Status != reservationStatusRestocked || restock.Reservation.RestockedAt == nil {
+		t.Fatalf("expected restocked reservation, got %+v", restock.Reservation)
+	}
+	if stock := restock.Stocks["SKU-LOC"]; stock.OnHand != 6 || stock.Reserved != 0 {
+		t.Fatalf("unexpected aggregate after restock %+v", stock)
+	}
+	if len(restock.Events) != 1 || restock.Events[0].LocationID != "partner" || restock.Events[0].DeltaOnHand != 2 || restock.Events[0].OnHand != 2 {
+		t.Fatalf("unexpected restock ledger %+v", restock.Events)
+	}
+	again, err := repo.Restock(ctx, restockReq)
+	if err != nil || len(again.Events) != 0 || again.Reservation.Status != reservationStatusRestocked {
+		t.Fatalf("expected a repeated restock to be a no-op, got %+v (err %v)", again, err)
+	}
+	if partner, err := repo.ListLocationStocks(ctx, "SKU-LOC"); err != nil || len(partner) != 2 {
+		t.Fatalf("unexpected location stocks after restock %+v (err %v)", partner, err)
+	}
 }
 
 func freePort(t *testing.T) int {
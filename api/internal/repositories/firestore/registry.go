@@ -0,0 +1,95 @@
+package firestore
+
+import (
+	"context"
+	"errors"
+
+	pfirestore "github.com/hanko-field/api/internal/platform/firestore"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+var _ repositories.Registry = (*Registry)(nil)
+
+// Registry exposes the Firestore-backed repositories the API binary runs on through
+// repositories.Registry. Accessors for collections that are not wired yet return nil, which the DI
+// container treats as "service not available".
+type Registry struct {
+	inventory *InventoryRepository
+	counters  *CounterRepository
+}
+
+// NewRegistry constructs the Firestore repositories on top of a shared provider. The provider stays
+// owned by the caller, so Close does not release it.
+func NewRegistry(provider *pfirestore.Provider) (*Registry, error) {
+	if provider == nil {
+		return nil, errors.New("firestore registry requires firestore provider")
+	}
+	inventory, err := NewInventoryRepository(provider)
+	if err != nil {
+		return nil, err
+	}
+	counters, err := NewCounterRepository(provider)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{inventory: inventory, counters: counters}, nil
+}
+
+// Close is a no-op; the provider is closed by whoever created it.
+func (r *Registry) Close(context.Context) error { return nil }
+
+// RunInTx runs fn directly. Each Firestore repository opens its own transaction for the writes that
+// must be atomic, so there is no outer transaction to join.
+func (r *Registry) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return errors.New("firestore registry: transaction function is required")
+	}
+	return fn(ctx)
+}
+
+func (r *Registry) Inventory() repositories.InventoryRepository {
+	if r.inventory == nil {
+		return nil
+	}
+	return r.inventory
+}
+
+func (r *Registry) Counters() repositories.CounterRepository {
+	if r.counters == nil {
+		return nil
+	}
+	return r.counters
+}
+
+func (r *Registry) Designs() repositories.DesignRepository               { return nil }
+func (r *Registry) DesignVersions() repositories.DesignVersionRepository { return nil }
+func (r *Registry) AISuggestions() repositories.AISuggestionRepository   { return nil }
+func (r *Registry) AIJobs() repositories.AIJobRepository                 { return nil }
+func (r *Registry) Carts() repositories.CartRepository                   { return nil }
+func (r *Registry) StockSubscriptions() repositories.StockSubscriptionRepository {
+	return nil
+}
+func (r *Registry) Orders() repositories.OrderRepository                 { return nil }
+func (r *Registry) Reviews() repositories.ReviewRepository               { return nil }
+func (r *Registry) ProductRatings() repositories.ProductRatingRepository { return nil }
+func (r *Registry) OrderPayments() repositories.OrderPaymentRepository   { return nil }
+func (r *Registry) PaymentTransactions() repositories.PaymentTransactionRepository {
+	return nil
+}
+func (r *Registry) PaymentDisputes() repositories.PaymentDisputeRepository { return nil }
+func (r *Registry) OrderShipments() repositories.OrderShipmentRepository   { return nil }
+func (r *Registry) OrderProductionEvents() repositories.OrderProductionEventRepository {
+	return nil
+}
+func (r *Registry) Promotions() repositories.PromotionRepository          { return nil }
+func (r *Registry) PromotionUsage() repositories.PromotionUsageRepository { return nil }
+func (r *Registry) FXRates() repositories.FXRateRepository                { return nil }
+func (r *Registry) Users() repositories.UserRepository                    { return nil }
+func (r *Registry) Addresses() repositories.AddressRepository             { return nil }
+func (r *Registry) PaymentMethods() repositories.PaymentMethodRepository  { return nil }
+func (r *Registry) Favorites() repositories.FavoriteRepository            { return nil }
+func (r *Registry) Catalog() repositories.CatalogRepository               { return nil }
+func (r *Registry) Content() repositories.ContentRepository               { return nil }
+func (r *Registry) Assets() repositories.AssetRepository                  { return nil }
+func (r *Registry) AuditLogs() repositories.AuditLogRepository            { return nil }
+func (r *Registry) Health() repositories.HealthRepository                 { return nil }
@@ -17,13 +17,18 @@ type Registry interface {
 	AIJobs() AIJobRepository
 	Carts() CartRepository
 	Inventory() InventoryRepository
+	StockSubscriptions() StockSubscriptionRepository
 	Orders() OrderRepository
 	Reviews() ReviewRepository
+	ProductRatings() ProductRatingRepository
 	OrderPayments() OrderPaymentRepository
+	PaymentTransactions() PaymentTransactionRepository
+	PaymentDisputes() PaymentDisputeRepository
 	OrderShipments() OrderShipmentRepository
 	OrderProductionEvents() OrderProductionEventRepository
 	Promotions() PromotionRepository
 	PromotionUsage() PromotionUsageRepository
+	FXRates() FXRateRepository
 	Users() UserRepository
 	Addresses() AddressRepository
 	PaymentMethods() PaymentMethodRepository
@@ -106,17 +111,28 @@ type InventoryRepository interface {
 	Reserve(ctx context.Context, req InventoryReserveRequest) (InventoryReserveResult, error)
 	Commit(ctx context.Context, req InventoryCommitRequest) (InventoryCommitResult, error)
 	Release(ctx context.Context, req InventoryReleaseRequest) (InventoryReleaseResult, error)
+	Restock(ctx context.Context, req InventoryRestockRequest) (InventoryRestockResult, error)
 	GetReservation(ctx context.Context, reservationID string) (domain.InventoryReservation, error)
 	ListLowStock(ctx context.Context, query InventoryLowStockQuery) (domain.CursorPage[domain.InventoryStock], error)
-}
-
-// InventoryReserveRequest encapsulates reservation creation metadata for the repository.
+	ListExpiredReservations(ctx context.Context, query InventoryExpiredReservationQuery) (domain.CursorPage[domain.InventoryReservation], error)
+	Adjust(ctx context.Context, req InventoryAdjustRequest) (InventoryAdjustResult, error)
+	ListStockEvents(ctx context.Context, query InventoryStockEventQuery) (domain.CursorPage[domain.InventoryStockEvent], error)
+	ListLocationStocks(ctx context.Context, sku string) ([]domain.InventoryStock, error)
+	Transfer(ctx context.Context, req InventoryTransferRequest) (InventoryTransferResult, error)
+	ListLocations(ctx context.Context) ([]domain.InventoryLocation, error)
+	GetLocation(ctx context.Context, locationID string) (domain.InventoryLocation, error)
+	UpsertLocation(ctx context.Context, location domain.InventoryLocation) (domain.InventoryLocation, error)
+}
+
+// InventoryReserveRequest encapsulates reservation creation metadata for the repository. Lines with a
+// LocationID draw from that location's stock as well as the SKU aggregate.
 type InventoryReserveRequest struct {
 	Reservation domain.InventoryReservation
 	Now         time.Time
 }
 
-// InventoryReserveResult returns the saved reservation and updated stock projections.
+// InventoryReserveResult returns the saved reservation and updated stock projections. Stocks holds
+// the SKU aggregates keyed by SKU.
 type InventoryReserveResult struct {
 	Reservation domain.InventoryReservation
 	Stocks      map[string]domain.InventoryStock
@@ -148,9 +164,88 @@ type InventoryReleaseResult struct {
 	Stocks      map[string]domain.InventoryStock
 }
 
+// InventoryRestockRequest returns a committed reservation's lines to on-hand stock and moves the
+// reservation to restocked in the same transaction. Events holds one ledger entry per line with a
+// positive quantity, in line order; the repository fills in the SKU, location and balances. A
+// reservation that is already restocked is left untouched and the result carries no events.
+type InventoryRestockRequest struct {
+	ReservationID string
+	OrderRef      string
+	Events        []domain.InventoryStockEvent
+	Now           time.Time
+}
+
+// InventoryRestockResult reports the reservation, the SKU aggregates and the ledger entries as stored.
+type InventoryRestockResult struct {
+	Reservation domain.InventoryReservation
+	Stocks      map[string]domain.InventoryStock
+	Events      []domain.InventoryStockEvent
+}
+
 // InventoryLowStockQuery controls pagination and threshold filtering for low stock listings.
+// LocationID restricts the listing to one location; when empty SKU aggregates are listed.
 type InventoryLowStockQuery struct {
-	Threshold int
+	Threshold  int
+	LocationID string
+	PageSize   int
+	PageToken  string
+}
+
+// InventoryExpiredReservationQuery selects reserved reservations whose expiry precedes Before.
+type InventoryExpiredReservationQuery struct {
+	Before    time.Time
+	PageSize  int
+	PageToken string
+}
+
+// InventoryAdjustRequest changes on-hand stock for one SKU and appends Event to the stock ledger in
+// the same transaction. DeltaOnHand is applied unless SetOnHand is provided, in which case on-hand
+// is replaced by the counted quantity. CreateIfMissing allows receipts for SKUs without a stock
+// record. When AllowBelowReserved is false, decrements may not push on-hand below the reserved
+// quantity. When Event.LocationID is set the change is applied to that location and carried into
+// the SKU aggregate; SetOnHand then refers to the location's on-hand. SKUs that already have
+// location stock reject adjustments without a location with InventoryErrorLocationRequired, and
+// SKUs whose stock is held only on the aggregate reject the first location-scoped adjustment with
+// InventoryErrorUnassignedStock.
+type InventoryAdjustRequest struct {
+	Event              domain.InventoryStockEvent
+	DeltaOnHand        int
+	SetOnHand          *int
+	CreateIfMissing    bool
+	AllowBelowReserved bool
+	Now                time.Time
+}
+
+// InventoryAdjustResult returns the updated stock and the ledger entry as stored. Stock is the SKU
+// aggregate; Location is set when the adjustment targeted a location.
+type InventoryAdjustResult struct {
+	Stock    domain.InventoryStock
+	Location *domain.InventoryStock
+	Event    domain.InventoryStockEvent
+}
+
+// InventoryTransferRequest moves on-hand stock of one SKU between two locations and appends
+// OutEvent and InEvent to the stock ledger in the same transaction. The SKU aggregate is unchanged.
+type InventoryTransferRequest struct {
+	SKU            string
+	FromLocationID string
+	ToLocationID   string
+	Quantity       int
+	OutEvent       domain.InventoryStockEvent
+	InEvent        domain.InventoryStockEvent
+	Now            time.Time
+}
+
+// InventoryTransferResult returns both location stocks and the ledger entries as stored.
+type InventoryTransferResult struct {
+	From   domain.InventoryStock
+	To     domain.InventoryStock
+	Events []domain.InventoryStockEvent
+}
+
+// InventoryStockEventQuery pages through a SKU's ledger, newest first.
+type InventoryStockEventQuery struct {
+	SKU       string
 	PageSize  int
 	PageToken string
 }
@@ -168,6 +263,36 @@ type OrderPaymentRepository interface {
 	Insert(ctx context.Context, payment domain.Payment) error
 	Update(ctx context.Context, payment domain.Payment) error
 	List(ctx context.Context, orderID string) ([]domain.Payment, error)
+	ListByDateRange(ctx context.Context, filter PaymentListFilter) (domain.CursorPage[domain.Payment], error)
+	FindByIntent(ctx context.Context, provider, intentID string) (domain.Payment, error)
+}
+
+// PaymentTransactionRepository stores the append-only ledger beneath each payment. Insert must report a
+// conflict when the transaction ID already exists so PSP events can be deduplicated by ID.
+type PaymentTransactionRepository interface {
+	Insert(ctx context.Context, tx domain.PaymentTransaction) error
+	List(ctx context.Context, orderID, paymentID string) ([]domain.PaymentTransaction, error)
+}
+
+// PaymentDisputeRepository stores PSP chargebacks. FindByPSPDispute locates the record for webhook
+// updates; Insert must report a conflict when the PSP dispute is already recorded.
+type PaymentDisputeRepository interface {
+	Insert(ctx context.Context, dispute domain.PaymentDispute) error
+	Update(ctx context.Context, dispute domain.PaymentDispute) error
+	FindByID(ctx context.Context, disputeID string) (domain.PaymentDispute, error)
+	FindByPSPDispute(ctx context.Context, provider, pspDisputeID string) (domain.PaymentDispute, error)
+	List(ctx context.Context, filter DisputeListFilter) (domain.CursorPage[domain.PaymentDispute], error)
+}
+
+// StockSubscriptionRepository stores back-in-stock subscriptions. MarkNotified must only succeed
+// while the subscription is active and report a conflict otherwise, so concurrent stock events
+// notify each subscriber at most once.
+type StockSubscriptionRepository interface {
+	Insert(ctx context.Context, subscription domain.StockSubscription) error
+	Update(ctx context.Context, subscription domain.StockSubscription) error
+	FindByID(ctx context.Context, subscriptionID string) (domain.StockSubscription, error)
+	List(ctx context.Context, filter StockSubscriptionListFilter) (domain.CursorPage[domain.StockSubscription], error)
+	MarkNotified(ctx context.Context, subscriptionID string, eventID string, notifiedAt time.Time) (domain.StockSubscription, error)
 }
 
 // OrderShipmentRepository stores fulfillment data for orders.
@@ -183,10 +308,18 @@ type ReviewRepository interface {
 	FindByID(ctx context.Context, reviewID string) (domain.Review, error)
 	FindByOrder(ctx context.Context, orderID string) (domain.Review, error)
 	ListByUser(ctx context.Context, userID string, pager domain.Pagination) (domain.CursorPage[domain.Review], error)
+	List(ctx context.Context, filter ReviewListFilter) (domain.CursorPage[domain.Review], error)
 	UpdateStatus(ctx context.Context, reviewID string, status domain.ReviewStatus, update ReviewModerationUpdate) (domain.Review, error)
 	UpdateReply(ctx context.Context, reviewID string, reply *domain.ReviewReply, updatedAt time.Time) (domain.Review, error)
 }
 
+// ProductRatingRepository maintains per-product rating aggregates derived from approved reviews.
+type ProductRatingRepository interface {
+	Get(ctx context.Context, productID string) (domain.ProductRatingSummary, error)
+	GetMany(ctx context.Context, productIDs []string) (map[string]domain.ProductRatingSummary, error)
+	ApplyDelta(ctx context.Context, productID string, delta ProductRatingDelta) (domain.ProductRatingSummary, error)
+}
+
 // OrderProductionEventRepository stores production timeline events for an order.
 type OrderProductionEventRepository interface {
 	Insert(ctx context.Context, event domain.OrderProductionEvent) (domain.OrderProductionEvent, error)
@@ -209,6 +342,15 @@ type PromotionUsageRepository interface {
 	ListUsage(ctx context.Context, promoID string, pager domain.Pagination) (domain.CursorPage[domain.PromotionUsage], error)
 }
 
+// FXRateRepository stores published exchange rate tables. Tables are immutable: corrections are
+// published as new tables, and Insert must report a conflict when the ID already exists. FindEffective
+// returns the table with the latest EffectiveAt not after asOf.
+type FXRateRepository interface {
+	Insert(ctx context.Context, table domain.FXRateTable) error
+	FindByID(ctx context.Context, tableID string) (domain.FXRateTable, error)
+	FindEffective(ctx context.Context, baseCurrency string, asOf time.Time) (domain.FXRateTable, error)
+}
+
 // UserRepository stores user profiles and supports masking/deactivation flows.
 type UserRepository interface {
 	FindByID(ctx context.Context, userID string) (domain.UserProfile, error)
@@ -318,15 +460,63 @@ type OrderListFilter struct {
 	Pagination domain.Pagination
 }
 
+// PaymentListFilter selects payments across orders by creation time, oldest first, for back-office
+// sweeps such as PSP reconciliation.
+type PaymentListFilter struct {
+	Provider   string
+	DateRange  domain.RangeQuery[time.Time]
+	Pagination domain.Pagination
+}
+
+// DisputeListFilter selects disputes by status and evidence deadline, earliest deadline first.
+type DisputeListFilter struct {
+	Status     []domain.DisputeStatus
+	OrderID    string
+	DueBefore  *time.Time
+	Pagination domain.Pagination
+}
+
+// StockSubscriptionListFilter selects subscriptions by owner, target and status. ExpiresBefore
+// limits results to subscriptions expiring before the instant, earliest expiry first; otherwise
+// results are ordered by creation time, newest first.
+type StockSubscriptionListFilter struct {
+	UserID        string
+	SKU           string
+	ProductRef    string
+	Status        []domain.StockSubscriptionStatus
+	ExpiresBefore *time.Time
+	Pagination    domain.Pagination
+}
+
 type PromotionListFilter struct {
 	Status     []string
 	Pagination domain.Pagination
 }
 
-// ReviewModerationUpdate carries moderation metadata for status transitions.
+// ReviewListFilter narrows review listings by moderation status and product. Results are ordered by
+// creation time, oldest first unless SortOrder is domain.SortDesc, so moderators work the queue in
+// submission order while public feeds show the newest reviews first.
+type ReviewListFilter struct {
+	Status     []domain.ReviewStatus
+	ProductID  string
+	SortOrder  domain.SortOrder
+	Pagination domain.Pagination
+}
+
+// ReviewModerationUpdate carries moderation metadata for status transitions. ProductIDs records the
+// products resolved from the order so public feeds can query reviews per product.
 type ReviewModerationUpdate struct {
 	ModeratedBy string
 	ModeratedAt time.Time
+	ProductIDs  []string
+}
+
+// ProductRatingDelta adjusts a product rating aggregate by adding or removing a single star rating.
+// Count is +1 when a review becomes visible and -1 when it is withdrawn.
+type ProductRatingDelta struct {
+	Rating    int
+	Count     int
+	UpdatedAt time.Time
 }
 
 type TemplateFilter struct {
@@ -16,6 +16,12 @@ const (
 	InventoryErrorReservationNotFound InventoryErrorCode = "inventory_reservation_not_found"
 	// InventoryErrorInvalidReservationState indicates the reservation status forbids the operation.
 	InventoryErrorInvalidReservationState InventoryErrorCode = "inventory_invalid_state"
+	// InventoryErrorLocationNotFound indicates the stock location is unknown or inactive.
+	InventoryErrorLocationNotFound InventoryErrorCode = "inventory_location_not_found"
+	// InventoryErrorLocationRequired indicates the SKU is stocked per location and the operation named none.
+	InventoryErrorLocationRequired InventoryErrorCode = "inventory_location_required"
+	// InventoryErrorUnassignedStock indicates the SKU aggregate holds stock that no location accounts for.
+	InventoryErrorUnassignedStock InventoryErrorCode = "inventory_unassigned_stock"
 )
 
 // InventoryError wraps inventory-specific failures with machine readable codes.
@@ -27,6 +27,8 @@ var (
 	ErrAIJobNotFound = errors.New("ai: job not found")
 	// ErrAISuggestionNotFound indicates the requested AI suggestion does not exist.
 	ErrAISuggestionNotFound = errors.New("ai: suggestion not found")
+	// ErrAIJobAlreadyCompleted indicates the AI job already reached a terminal state.
+	ErrAIJobAlreadyCompleted = errors.New("ai: job already completed")
 )
 
 // SuggestionJobPublisher publishes suggestion job messages to the background queue.
@@ -45,23 +47,38 @@ type SuggestionJobMessage struct {
 	IdempotencyKey string    `json:"idempotencyKey,omitempty"`
 }
 
+// StockCleanupPublisher publishes stock cleanup jobs to the background queue.
+type StockCleanupPublisher interface {
+	PublishStockCleanup(ctx context.Context, message StockCleanupMessage) (string, error)
+}
+
+// StockCleanupMessage is the payload delivered to the reservation cleanup endpoint via Pub/Sub.
+type StockCleanupMessage struct {
+	JobID          string    `json:"jobId"`
+	ReservationIDs []string  `json:"reservationIds"`
+	QueuedAt       time.Time `json:"queuedAt"`
+}
+
 // BackgroundJobDispatcherDeps enumerates collaborators required to construct the dispatcher.
+// StockCleanup is optional; without it EnqueueStockCleanup reports that dispatch is unavailable.
 type BackgroundJobDispatcherDeps struct {
-	Jobs        repositories.AIJobRepository
-	Suggestions repositories.AISuggestionRepository
-	Publisher   SuggestionJobPublisher
-	Clock       func() time.Time
-	IDGenerator func() string
-	Logger      func(ctx context.Context, event string, fields map[string]any)
+	Jobs         repositories.AIJobRepository
+	Suggestions  repositories.AISuggestionRepository
+	Publisher    SuggestionJobPublisher
+	StockCleanup StockCleanupPublisher
+	Clock        func() time.Time
+	IDGenerator  func() string
+	Logger       func(ctx context.Context, event string, fields map[string]any)
 }
 
 type backgroundJobDispatcher struct {
-	jobs        repositories.AIJobRepository
-	suggestions repositories.AISuggestionRepository
-	publisher   SuggestionJobPublisher
-	clock       func() time.Time
-	newID       func() string
-	logger      func(context.Context, string, map[string]any)
+	jobs         repositories.AIJobRepository
+	suggestions  repositories.AISuggestionRepository
+	publisher    SuggestionJobPublisher
+	stockCleanup StockCleanupPublisher
+	clock        func() time.Time
+	newID        func() string
+	logger       func(context.Context, string, map[string]any)
 }
 
 // NewBackgroundJobDispatcher wires dependencies into a BackgroundJobDispatcher implementation.
@@ -92,9 +109,10 @@ func NewBackgroundJobDispatcher(deps BackgroundJobDispatcherDeps) (BackgroundJob
 	}
 
 	return &backgroundJobDispatcher{
-		jobs:        deps.Jobs,
-		suggestions: deps.Suggestions,
-		publisher:   deps.Publisher,
+		jobs:         deps.Jobs,
+		suggestions:  deps.Suggestions,
+		publisher:    deps.Publisher,
+		stockCleanup: deps.StockCleanup,
 		clock: func() time.Time {
 			return clock().UTC()
 		},
@@ -185,7 +203,7 @@ func (d *backgroundJobDispatcher) QueueAISuggestion(ctx context.Context, cmd Que
 func (d *backgroundJobDispatcher) GetAIJob(ctx context.Context, jobID string) (domain.AIJob, error) {
 	jobID = strings.TrimSpace(jobID)
 	if jobID == "" {
-		return domain.AIJob{}, fmt.Errorf("%w: job id is required", ErrAIInvalidInput)
+		return domain.AIJob{}, detailf(ErrAIInvalidInput, "job id is required")
 	}
 
 	job, err := d.jobs.FindByID(ctx, jobID)
@@ -201,7 +219,7 @@ func (d *backgroundJobDispatcher) GetAIJob(ctx context.Context, jobID string) (d
 func (d *backgroundJobDispatcher) CompleteAISuggestion(ctx context.Context, cmd CompleteAISuggestionCommand) (CompleteAISuggestionResult, error) {
 	jobID := strings.TrimSpace(cmd.JobID)
 	if jobID == "" {
-		return CompleteAISuggestionResult{}, fmt.Errorf("%w: job id is required", ErrAIInvalidInput)
+		return CompleteAISuggestionResult{}, detailf(ErrAIInvalidInput, "job id is required")
 	}
 
 	job, err := d.jobs.FindByID(ctx, jobID)
@@ -211,6 +229,9 @@ func (d *backgroundJobDispatcher) CompleteAISuggestion(ctx context.Context, cmd
 		}
 		return CompleteAISuggestionResult{}, err
 	}
+	if isTerminalJobStatus(job.Status) {
+		return CompleteAISuggestionResult{Job: job}, detailf(ErrAIJobAlreadyCompleted, "job %s is %s", job.ID, job.Status)
+	}
 
 	now := d.now()
 	payload := mergePayload(job.Payload, cmd.Outputs, cmd.Metadata)
@@ -287,7 +308,7 @@ func (d *backgroundJobDispatcher) GetSuggestion(ctx context.Context, designID st
 	designID = strings.TrimSpace(designID)
 	suggestionID = strings.TrimSpace(suggestionID)
 	if designID == "" || suggestionID == "" {
-		return AISuggestion{}, fmt.Errorf("%w: design id and suggestion id are required", ErrAIInvalidInput)
+		return AISuggestion{}, detailf(ErrAIInvalidInput, "design id and suggestion id are required")
 	}
 
 	suggestion, err := d.suggestions.FindByID(ctx, designID, suggestionID)
@@ -304,22 +325,65 @@ func (d *backgroundJobDispatcher) EnqueueRegistrabilityCheck(context.Context, Re
 	return "", errors.New("registrability job dispatch: not implemented")
 }
 
-func (d *backgroundJobDispatcher) EnqueueStockCleanup(context.Context, StockCleanupPayload) error {
-	return errors.New("stock cleanup dispatch: not implemented")
+func (d *backgroundJobDispatcher) EnqueueStockCleanup(ctx context.Context, payload StockCleanupPayload) error {
+	if d.stockCleanup == nil {
+		return errors.New("stock cleanup dispatch: publisher is not configured")
+	}
+
+	ids := make([]string, 0, len(payload.ReservationIDs))
+	seen := make(map[string]struct{}, len(payload.ReservationIDs))
+	for _, raw := range payload.ReservationIDs {
+		id := strings.TrimSpace(raw)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return detailf(ErrInventoryInvalidInput, "at least one reservation id is required")
+	}
+
+	message := StockCleanupMessage{
+		JobID:          "scj_" + strings.TrimSpace(d.newID()),
+		ReservationIDs: ids,
+		QueuedAt:       d.now(),
+	}
+	if _, err := d.stockCleanup.PublishStockCleanup(ctx, message); err != nil {
+		d.logger(ctx, "stock_cleanup_publish_failed", map[string]any{
+			"jobId": message.JobID,
+			"count": len(ids),
+			"error": err.Error(),
+		})
+		return fmt.Errorf("stock cleanup dispatch: %w", err)
+	}
+	return nil
+}
+
+func isTerminalJobStatus(status domain.AIJobStatus) bool {
+	switch status {
+	case domain.AIJobStatusSucceeded, domain.AIJobStatusFailed, domain.AIJobStatusCanceled:
+		return true
+	default:
+		return false
+	}
 }
 
 func (d *backgroundJobDispatcher) validateQueueCommand(cmd QueueAISuggestionCommand) error {
 	if strings.TrimSpace(cmd.DesignID) == "" {
-		return fmt.Errorf("%w: design id is required", ErrAIInvalidInput)
+		return detailf(ErrAIInvalidInput, "design id is required")
 	}
 	if strings.TrimSpace(cmd.Method) == "" {
-		return fmt.Errorf("%w: method is required", ErrAIInvalidInput)
+		return detailf(ErrAIInvalidInput, "method is required")
 	}
 	if strings.TrimSpace(cmd.Model) == "" {
-		return fmt.Errorf("%w: model is required", ErrAIInvalidInput)
+		return detailf(ErrAIInvalidInput, "model is required")
 	}
 	if len(cmd.Snapshot) == 0 {
-		return fmt.Errorf("%w: snapshot is required", ErrAIInvalidInput)
+		return detailf(ErrAIInvalidInput, "snapshot is required")
 	}
 	return nil
 }
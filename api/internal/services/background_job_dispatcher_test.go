@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -407,6 +408,44 @@ func TestBackgroundJobDispatcherCompleteFailureUpdatesJob(t *testing.T) {
 	}
 }
 
+func TestBackgroundJobDispatcherCompleteRejectsCompletedJob(t *testing.T) {
+	ctx := context.Background()
+	jobRepo := newInMemoryAIJobRepo()
+	suggestionRepo := newInMemorySuggestionRepo()
+
+	jobRepo.Insert(ctx, domain.AIJob{
+		ID:        "aj_done",
+		Status:    domain.AIJobStatusSucceeded,
+		Kind:      domain.AIJobKindDesignSuggestion,
+		Payload:   map[string]any{"designId": "design-1", "suggestionId": "as_done"},
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+
+	dispatcher, err := NewBackgroundJobDispatcher(BackgroundJobDispatcherDeps{
+		Jobs:        jobRepo,
+		Suggestions: suggestionRepo,
+		Publisher:   &captureSuggestionPublisher{},
+	})
+	if err != nil {
+		t.Fatalf("NewBackgroundJobDispatcher: %v", err)
+	}
+
+	result, err := dispatcher.CompleteAISuggestion(ctx, CompleteAISuggestionCommand{
+		JobID:      "aj_done",
+		Suggestion: AISuggestion{ID: "as_done", DesignID: "design-1"},
+	})
+	if !errors.Is(err, ErrAIJobAlreadyCompleted) {
+		t.Fatalf("expected ErrAIJobAlreadyCompleted, got %v", err)
+	}
+	if result.Job.ID != "aj_done" {
+		t.Fatalf("expected existing job in result, got %+v", result.Job)
+	}
+	if _, err := suggestionRepo.FindByID(ctx, "design-1", "as_done"); err == nil {
+		t.Fatalf("expected no suggestion to be persisted for completed job")
+	}
+}
+
 func cloneJob(job domain.AIJob) domain.AIJob {
 	clone := job
 	if job.Payload != nil {
@@ -446,3 +485,45 @@ func cloneMapAny(src map[string]any) map[string]any {
 	}
 	return out
 }
+
+type captureStockCleanupPublisher struct {
+	messages []StockCleanupMessage
+}
+
+func (c *captureStockCleanupPublisher) PublishStockCleanup(_ context.Context, msg StockCleanupMessage) (string, error) {
+	c.messages = append(c.messages, msg)
+	return "pub-1", nil
+}
+
+func TestBackgroundJobDispatcherEnqueueStockCleanup(t *testing.T) {
+	ctx := context.Background()
+	publisher := &captureStockCleanupPublisher{}
+	dispatcher, err := NewBackgroundJobDispatcher(BackgroundJobDispatcherDeps{
+		Jobs:         newInMemoryAIJobRepo(),
+		Suggestions:  newInMemorySuggestionRepo(),
+		Publisher:    &captureSuggestionPublisher{},
+		StockCleanup: publisher,
+		Clock: func() time.Time {
+			return time.Date(2025, 5, 6, 9, 0, 0, 0, time.UTC)
+		},
+		IDGenerator: func() string { return "ABC123" },
+	})
+	if err != nil {
+		t.Fatalf("NewBackgroundJobDispatcher: %v", err)
+	}
+
+	if err := dispatcher.EnqueueStockCleanup(ctx, StockCleanupPayload{ReservationIDs: []string{"sr_1", "sr_1", " ", "sr_2"}}); err != nil {
+		t.Fatalf("EnqueueStockCleanup: %v", err)
+	}
+	if len(publisher.messages) != 1 {
+		t.Fatalf("expected one published message, got %d", len(publisher.messages))
+	}
+	msg := publisher.messages[0]
+	if msg.JobID != "scj_ABC123" || len(msg.ReservationIDs) != 2 || msg.QueuedAt.IsZero() {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	if err := dispatcher.EnqueueStockCleanup(ctx, StockCleanupPayload{}); !errors.Is(err, ErrInventoryInvalidInput) {
+		t.Fatalf("expected invalid input, got %v", err)
+	}
+}
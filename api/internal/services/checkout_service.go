@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hanko-field/api/internal/payments"
+)
+
+const checkoutSessionIdempotencyPrefix = "checkout-"
+
+var (
+	// ErrCheckoutInvalidInput indicates the checkout request is malformed.
+	ErrCheckoutInvalidInput = errors.New("checkout: invalid input")
+)
+
+// CheckoutQuoteVerifier reprices a cart against the signed quote the shopper was shown.
+// *CartPricingEngine satisfies it.
+type CheckoutQuoteVerifier interface {
+	VerifyQuote(ctx context.Context, cmd PriceCartCommand, token string) (PriceCartResult, error)
+}
+
+// CheckoutSessionCreator opens hosted payment sessions. *payments.Manager satisfies it.
+type CheckoutSessionCreator interface {
+	CreateCheckoutSession(ctx context.Context, paymentCtx payments.PaymentContext, req payments.CheckoutSessionRequest) (payments.CheckoutSession, error)
+}
+
+// CheckoutServiceDeps bundles the collaborators required to construct the checkout service.
+type CheckoutServiceDeps struct {
+	Carts    CartService
+	Pricing  CheckoutQuoteVerifier
+	Sessions CheckoutSessionCreator
+	Logger   func(ctx context.Context, event string, fields map[string]any)
+}
+
+type checkoutService struct {
+	carts    CartService
+	pricing  CheckoutQuoteVerifier
+	sessions CheckoutSessionCreator
+	logger   func(context.Context, string, map[string]any)
+}
+
+// NewCheckoutService wires dependencies into a CheckoutService that only opens a PSP session for a
+// cart whose signed quote still matches its current price.
+func NewCheckoutService(deps CheckoutServiceDeps) (CheckoutService, error) {
+	if deps.Carts == nil {
+		return nil, errors.New("checkout service: cart service is required")
+	}
+	if deps.Pricing == nil {
+		return nil, errors.New("checkout service: quote verifier is required")
+	}
+	if deps.Sessions == nil {
+		return nil, errors.New("checkout service: session creator is required")
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+	return &checkoutService{
+		carts:    deps.Carts,
+		pricing:  deps.Pricing,
+		sessions: deps.Sessions,
+		logger:   logger,
+	}, nil
+}
+
+// CreateCheckoutSession reprices the shopper's cart against cmd.QuoteToken before contacting the PSP.
+// A cart that no longer matches its quote fails with a *PriceChangedError listing the differences,
+// and the session is opened for the repriced total otherwise. The PSP idempotency key is derived from
+// the quote, so a retried request for the same quote reuses the session.
+func (s *checkoutService) CreateCheckoutSession(ctx context.Context, cmd CreateCheckoutSessionCommand) (CheckoutSession, error) {
+	userID := strings.TrimSpace(cmd.UserID)
+	if userID == "" {
+		return CheckoutSession{}, detailf(ErrCheckoutInvalidInput, "user id is required")
+	}
+	token := strings.TrimSpace(cmd.QuoteToken)
+	if token == "" {
+		return CheckoutSession{}, detailf(ErrCheckoutInvalidInput, "quote token is required")
+	}
+	if strings.TrimSpace(cmd.SuccessURL) == "" || strings.TrimSpace(cmd.CancelURL) == "" {
+		return CheckoutSession{}, detailf(ErrCheckoutInvalidInput, "success and cancel urls are required")
+	}
+
+	cart, err := s.carts.GetOrCreateCart(ctx, userID)
+	if err != nil {
+		return CheckoutSession{}, err
+	}
+	if cartID := strings.TrimSpace(cmd.CartID); cartID != "" && cartID != cart.ID {
+		return CheckoutSession{}, detailf(ErrCheckoutInvalidInput, "cart %s is not the active cart", cartID)
+	}
+	if len(cart.Items) == 0 {
+		return CheckoutSession{}, detailf(ErrCheckoutInvalidInput, "cart is empty")
+	}
+
+	var promotionCode *string
+	if cart.Promotion != nil && cart.Promotion.Applied {
+		code := cart.Promotion.Code
+		promotionCode = &code
+	}
+	priced, err := s.pricing.VerifyQuote(ctx, PriceCartCommand{
+		Cart:            cart,
+		PromotionCode:   promotionCode,
+		ShippingAddress: cart.ShippingAddress,
+		BillingAddress:  cart.BillingAddress,
+	}, token)
+	if err != nil {
+		var changed *PriceChangedError
+		if errors.As(err, &changed) {
+			s.logger(ctx, "checkout_price_changed", map[string]any{
+				"cartId":  cart.ID,
+				"quoteId": changed.QuoteID,
+				"changes": len(changed.Changes),
+			})
+		}
+		return CheckoutSession{}, err
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(cart.Currency))
+	total := priced.Estimate.Total
+	metadata := make(map[string]string, len(cmd.Metadata)+2)
+	for key, value := range cmd.Metadata {
+		metadata[key] = value
+	}
+	metadata["cartId"] = cart.ID
+	metadata["userId"] = userID
+
+	paymentCtx := payments.PaymentContext{
+		PreferredProvider: strings.TrimSpace(cmd.PSP),
+		Currency:          currency,
+		Amount:            total,
+		Metadata:          metadata,
+	}
+	if cart.BillingAddress != nil {
+		paymentCtx.BillingCountry = strings.ToUpper(strings.TrimSpace(cart.BillingAddress.Country))
+	}
+	sum := sha256.Sum256([]byte(token))
+	session, err := s.sessions.CreateCheckoutSession(ctx, paymentCtx, payments.CheckoutSessionRequest{
+		Amount:         total,
+		Currency:       currency,
+		SuccessURL:     strings.TrimSpace(cmd.SuccessURL),
+		CancelURL:      strings.TrimSpace(cmd.CancelURL),
+		Metadata:       metadata,
+		IdempotencyKey: checkoutSessionIdempotencyPrefix + hex.EncodeToString(sum[:16]),
+	})
+	if err != nil {
+		return CheckoutSession{}, fmt.Errorf("checkout: create session for cart %s: %w", cart.ID, err)
+	}
+
+	return CheckoutSession{
+		SessionID:    session.ID,
+		PSP:          session.Provider,
+		ClientSecret: session.ClientSecret,
+		RedirectURL:  session.RedirectURL,
+		ExpiresAt:    session.ExpiresAt,
+	}, nil
+}
+
+// ConfirmClientCompletion acknowledges the client's return from the PSP. Orders are finalised by the
+// PSP webhook, so the ping only validates its identifiers.
+func (s *checkoutService) ConfirmClientCompletion(ctx context.Context, cmd ConfirmCheckoutCommand) error {
+	if strings.TrimSpace(cmd.UserID) == "" || strings.TrimSpace(cmd.SessionID) == "" {
+		return detailf(ErrCheckoutInvalidInput, "user id and session id are required")
+	}
+	s.logger(ctx, "checkout_client_confirmed", map[string]any{
+		"userId":    strings.TrimSpace(cmd.UserID),
+		"orderId":   strings.TrimSpace(cmd.OrderID),
+		"sessionId": strings.TrimSpace(cmd.SessionID),
+	})
+	return nil
+}
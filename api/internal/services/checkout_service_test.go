@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hanko-field/api/internal/payments"
+)
+
+type stubCheckoutCarts struct {
+	CartService
+	cart Cart
+}
+
+func (s *stubCheckoutCarts) GetOrCreateCart(context.Context, string) (Cart, error) {
+	return s.cart, nil
+}
+
+type stubCheckoutSessions struct {
+	contexts []payments.PaymentContext
+	requests []payments.CheckoutSessionRequest
+}
+
+func (s *stubCheckoutSessions) CreateCheckoutSession(_ context.Context, paymentCtx payments.PaymentContext, req payments.CheckoutSessionRequest) (payments.CheckoutSession, error) {
+	s.contexts = append(s.contexts, paymentCtx)
+	s.requests = append(s.requests, req)
+	return payments.CheckoutSession{ID: "cs_1", Provider: "stripe", RedirectURL: "https://checkout.example/cs_1"}, nil
+}
+
+func TestCheckoutService_RequiresMatchingQuote(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion:   &fakePromotionService{},
+		QuoteSecret: []byte("quote-secret"),
+		Now:         func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	carts := &stubCheckoutCarts{cart: Cart{
+		ID:       "cart_1",
+		UserID:   "user_1",
+		Currency: "JPY",
+		Items:    []CartItem{{ID: "a", SKU: "SEAL", Quantity: 1, UnitPrice: 3000, Currency: "JPY"}},
+	}}
+	sessions := &stubCheckoutSessions{}
+	svc, err := NewCheckoutService(CheckoutServiceDeps{Carts: carts, Pricing: engine, Sessions: sessions})
+	if err != nil {
+		t.Fatalf("NewCheckoutService error: %v", err)
+	}
+
+	estimate, err := engine.Calculate(ctx, PriceCartCommand{Cart: carts.cart})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	cmd := CreateCheckoutSessionCommand{
+		UserID:     "user_1",
+		SuccessURL: "https://shop.example/success",
+		CancelURL:  "https://shop.example/cancel",
+		PSP:        "stripe",
+		QuoteToken: estimate.Estimate.Quote.Token,
+	}
+
+	missing := cmd
+	missing.QuoteToken = ""
+	if _, err := svc.CreateCheckoutSession(ctx, missing); !errors.Is(err, ErrCheckoutInvalidInput) {
+		t.Fatalf("expected a missing quote to be rejected, got %v", err)
+	}
+
+	carts.cart.Items[0].UnitPrice = 3500
+	_, err = svc.CreateCheckoutSession(ctx, cmd)
+	var changed *PriceChangedError
+	if !errors.As(err, &changed) || len(changed.Changes) == 0 {
+		t.Fatalf("expected price_changed with a diff, got %v", err)
+	}
+	if len(sessions.requests) != 0 {
+		t.Fatalf("a repriced cart must not reach the PSP")
+	}
+
+	carts.cart.Items[0].UnitPrice = 3000
+	session, err := svc.CreateCheckoutSession(ctx, cmd)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession error: %v", err)
+	}
+	if session.SessionID != "cs_1" || session.PSP != "stripe" || session.RedirectURL == "" {
+		t.Fatalf("unexpected session %+v", session)
+	}
+	if len(sessions.requests) != 1 || sessions.requests[0].Amount != estimate.Estimate.Total || sessions.contexts[0].PreferredProvider != "stripe" {
+		t.Fatalf("expected the session to charge the quoted total, got %+v", sessions.requests)
+	}
+	if sessions.requests[0].IdempotencyKey == "" || sessions.requests[0].Metadata["cartId"] != "cart_1" {
+		t.Fatalf("unexpected session request %+v", sessions.requests[0])
+	}
+}
@@ -66,10 +66,10 @@ func (s *counterService) Next(ctx context.Context, scope, name string, opts Coun
 	scope = strings.TrimSpace(scope)
 	name = strings.TrimSpace(name)
 	if scope == "" {
-		return CounterValue{}, fmt.Errorf("%w: scope is required", ErrCounterInvalidInput)
+		return CounterValue{}, detailf(ErrCounterInvalidInput, "scope is required")
 	}
 	if name == "" {
-		return CounterValue{}, fmt.Errorf("%w: name is required", ErrCounterInvalidInput)
+		return CounterValue{}, detailf(ErrCounterInvalidInput, "name is required")
 	}
 
 	counterID := scope + ":" + name
@@ -84,9 +84,9 @@ func (s *counterService) Next(ctx context.Context, scope, name string, opts Coun
 		if errors.As(err, &counterErr) {
 			switch counterErr.Code {
 			case repositories.CounterErrorInvalidInput:
-				return CounterValue{}, fmt.Errorf("%w: %s", ErrCounterInvalidInput, counterErr.Message)
+				return CounterValue{}, detailf(ErrCounterInvalidInput, "%s", counterErr.Message)
 			case repositories.CounterErrorExhausted:
-				return CounterValue{}, fmt.Errorf("%w: %s", ErrCounterExhausted, counterErr.Message)
+				return CounterValue{}, detailf(ErrCounterExhausted, "%s", counterErr.Message)
 			}
 		}
 		return CounterValue{}, err
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Customization keys read from CartItem.Customization when pricing surcharges.
+const (
+	CustomizationKeyFont     = "fontId"
+	CustomizationKeyMaterial = "materialId"
+	CustomizationKeyText     = "text"
+	CustomizationKeyGiftBox  = "giftBox"
+)
+
+// Surcharge types reported in SurchargeBreakdown.Type.
+const (
+	SurchargeTypePremiumFont     = "premium_font"
+	SurchargeTypeMaterial        = "material"
+	SurchargeTypeExtraCharacters = "extra_characters"
+	SurchargeTypeGiftBox         = "gift_box"
+)
+
+// SurchargeRate is a per-unit charge. An empty TaxCode taxes the surcharge like its line.
+type SurchargeRate struct {
+	Amount  int64  `json:"amount" yaml:"amount"`
+	TaxCode string `json:"tax_code,omitempty" yaml:"tax_code,omitempty"`
+}
+
+// CustomizationSurchargeConfig prices customization choices in Currency. Materials are matched by ID
+// first and then by catalog category. Engraving text longer than IncludedCharacters pays
+// ExtraCharacter for every additional non-space character.
+type CustomizationSurchargeConfig struct {
+	Currency           string                   `json:"currency" yaml:"currency"`
+	PremiumFont        SurchargeRate            `json:"premium_font" yaml:"premium_font"`
+	Materials          map[string]SurchargeRate `json:"materials" yaml:"materials"`
+	MaterialCategories map[string]SurchargeRate `json:"material_categories" yaml:"material_categories"`
+	IncludedCharacters int                      `json:"included_characters" yaml:"included_characters"`
+	ExtraCharacter     SurchargeRate            `json:"extra_character" yaml:"extra_character"`
+	GiftBox            SurchargeRate            `json:"gift_box" yaml:"gift_box"`
+}
+
+// CustomizationCatalog looks up the fonts and materials referenced by customizations. CatalogService
+// satisfies it.
+type CustomizationCatalog interface {
+	GetFont(ctx context.Context, fontID string) (Font, error)
+	GetMaterial(ctx context.Context, materialID string) (Material, error)
+}
+
+// CatalogSurchargeResolverDeps configures CatalogSurchargeResolver.
+type CatalogSurchargeResolverDeps struct {
+	Catalog CustomizationCatalog
+	Config  CustomizationSurchargeConfig
+}
+
+// CatalogSurchargeResolver implements SurchargeResolver using catalog metadata such as
+// FontSummary.IsPremium and material categories.
+type CatalogSurchargeResolver struct {
+	catalog CustomizationCatalog
+	cfg     CustomizationSurchargeConfig
+}
+
+// NewCatalogSurchargeResolver validates the surcharge configuration.
+func NewCatalogSurchargeResolver(deps CatalogSurchargeResolverDeps) (*CatalogSurchargeResolver, error) {
+	if deps.Catalog == nil {
+		return nil, errors.New("surcharge resolver: catalog is required")
+	}
+	cfg := deps.Config
+	cfg.Currency = strings.ToUpper(strings.TrimSpace(cfg.Currency))
+	if cfg.Currency == "" {
+		return nil, errors.New("surcharge resolver: currency is required")
+	}
+	if cfg.IncludedCharacters < 0 {
+		return nil, errors.New("surcharge resolver: included characters cannot be negative")
+	}
+	rates := []SurchargeRate{cfg.PremiumFont, cfg.ExtraCharacter, cfg.GiftBox}
+	categories := make(map[string]SurchargeRate, len(cfg.MaterialCategories))
+	for category, rate := range cfg.MaterialCategories {
+		categories[strings.ToLower(strings.TrimSpace(category))] = rate
+		rates = append(rates, rate)
+	}
+	cfg.MaterialCategories = categories
+	for _, rate := range cfg.Materials {
+		rates = append(rates, rate)
+	}
+	for _, rate := range rates {
+		if rate.Amount < 0 {
+			return nil, errors.New("surcharge resolver: surcharge amounts cannot be negative")
+		}
+	}
+	return &CatalogSurchargeResolver{catalog: deps.Catalog, cfg: cfg}, nil
+}
+
+// PricingCurrency returns the currency surcharges are configured in.
+func (r *CatalogSurchargeResolver) PricingCurrency() string {
+	return r.cfg.Currency
+}
+
+// ResolveSurcharges returns one entry per chargeable customization choice on the line.
+func (r *CatalogSurchargeResolver) ResolveSurcharges(ctx context.Context, item CartItem, currency string) ([]SurchargeBreakdown, error) {
+	if len(item.Customization) == 0 || item.Quantity <= 0 {
+		return nil, nil
+	}
+	if cur := strings.ToUpper(strings.TrimSpace(currency)); cur != r.cfg.Currency {
+		return nil, detailf(ErrCartPricingCurrencyMismatch, "customization surcharges are priced in %s, cart uses %s", r.cfg.Currency, cur)
+	}
+
+	var out []SurchargeBreakdown
+	add := func(kind, code, description string, units int, rate SurchargeRate, metadata map[string]any) {
+		if rate.Amount <= 0 || units <= 0 {
+			return
+		}
+		out = append(out, SurchargeBreakdown{
+			Type:        kind,
+			Code:        code,
+			Description: description,
+			Quantity:    units,
+			UnitAmount:  rate.Amount,
+			Amount:      rate.Amount * int64(units),
+			TaxCode:     strings.TrimSpace(rate.TaxCode),
+			Metadata:    metadata,
+		})
+	}
+
+	if fontID := customizationString(item.Customization, CustomizationKeyFont); fontID != "" && r.cfg.PremiumFont.Amount > 0 {
+		font, err := r.catalog.GetFont(ctx, fontID)
+		if err != nil {
+			return nil, err
+		}
+		if font.IsPremium {
+			add(SurchargeTypePremiumFont, font.ID, fmt.Sprintf("Premium font: %s", font.DisplayName), item.Quantity, r.cfg.PremiumFont, nil)
+		}
+	}
+
+	if materialID := customizationString(item.Customization, CustomizationKeyMaterial); materialID != "" && (len(r.cfg.Materials) > 0 || len(r.cfg.MaterialCategories) > 0) {
+		rate, byID := r.cfg.Materials[materialID]
+		material, err := r.catalog.GetMaterial(ctx, materialID)
+		if err != nil {
+			return nil, err
+		}
+		if !byID {
+			rate = r.cfg.MaterialCategories[strings.ToLower(strings.TrimSpace(material.Category))]
+		}
+		add(SurchargeTypeMaterial, material.ID, fmt.Sprintf("Material: %s", material.Name), item.Quantity, rate, map[string]any{"category": material.Category})
+	}
+
+	if text := customizationString(item.Customization, CustomizationKeyText); text != "" {
+		characters := countEngravedCharacters(text)
+		if extra := characters - r.cfg.IncludedCharacters; extra > 0 {
+			add(SurchargeTypeExtraCharacters, "", fmt.Sprintf("%d extra engraved characters", extra), extra*item.Quantity, r.cfg.ExtraCharacter,
+				map[string]any{"characters": characters, "includedCharacters": r.cfg.IncludedCharacters})
+		}
+	}
+
+	if giftBox, _ := item.Customization[CustomizationKeyGiftBox].(bool); giftBox {
+		add(SurchargeTypeGiftBox, "", "Gift box", item.Quantity, r.cfg.GiftBox, nil)
+	}
+	return out, nil
+}
+
+func customizationString(customization map[string]any, key string) string {
+	value, _ := customization[key].(string)
+	return strings.TrimSpace(value)
+}
+
+func countEngravedCharacters(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/hanko-field/api/internal/domain"
+)
+
+type stubCustomizationCatalog struct {
+	fonts     map[string]Font
+	materials map[string]Material
+}
+
+func (s *stubCustomizationCatalog) GetFont(_ context.Context, fontID string) (Font, error) {
+	font, ok := s.fonts[fontID]
+	if !ok {
+		return Font{}, errors.New("font not found")
+	}
+	return font, nil
+}
+
+func (s *stubCustomizationCatalog) GetMaterial(_ context.Context, materialID string) (Material, error) {
+	material, ok := s.materials[materialID]
+	if !ok {
+		return Material{}, errors.New("material not found")
+	}
+	return material, nil
+}
+
+func newTestSurchargeResolver(t *testing.T) *CatalogSurchargeResolver {
+	t.Helper()
+	resolver, err := NewCatalogSurchargeResolver(CatalogSurchargeResolverDeps{
+		Catalog: &stubCustomizationCatalog{
+			fonts: map[string]Font{
+				"reisho": {FontSummary: domain.FontSummary{ID: "reisho", DisplayName: "Reisho", IsPremium: true}},
+				"kaisho": {FontSummary: domain.FontSummary{ID: "kaisho", DisplayName: "Kaisho"}},
+			},
+			materials: map[string]Material{
+				"titanium": {MaterialSummary: domain.MaterialSummary{ID: "titanium", Name: "Titanium", Category: "metal"}},
+				"ivory":    {MaterialSummary: domain.MaterialSummary{ID: "ivory", Name: "Mammoth ivory", Category: "Premium"}},
+				"tsuge":    {MaterialSummary: domain.MaterialSummary{ID: "tsuge", Name: "Tsuge", Category: "wood"}},
+			},
+		},
+		Config: CustomizationSurchargeConfig{
+			Currency:           "jpy",
+			PremiumFont:        SurchargeRate{Amount: 500},
+			Materials:          map[string]SurchargeRate{"titanium": {Amount: 3000}},
+			MaterialCategories: map[string]SurchargeRate{"premium": {Amount: 8000}},
+			IncludedCharacters: 4,
+			ExtraCharacter:     SurchargeRate{Amount: 200},
+			GiftBox:            SurchargeRate{Amount: 600, TaxCode: JPTaxCodeStandard},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewCatalogSurchargeResolver error: %v", err)
+	}
+	return resolver
+}
+
+func TestCatalogSurchargeResolver_ResolveSurcharges(t *testing.T) {
+	resolver := newTestSurchargeResolver(t)
+	ctx := context.Background()
+
+	surcharges, err := resolver.ResolveSurcharges(ctx, CartItem{
+		ID:       "line_1",
+		Quantity: 2,
+		Customization: map[string]any{
+			CustomizationKeyFont:     "reisho",
+			CustomizationKeyMaterial: "ivory",
+			CustomizationKeyText:     "山田 太郎 商店",
+			CustomizationKeyGiftBox:  true,
+		},
+	}, "JPY")
+	if err != nil {
+		t.Fatalf("ResolveSurcharges error: %v", err)
+	}
+	want := []struct {
+		kind   string
+		units  int
+		amount int64
+	}{
+		{SurchargeTypePremiumFont, 2, 1000},
+		{SurchargeTypeMaterial, 2, 16000},
+		{SurchargeTypeExtraCharacters, 4, 800},
+		{SurchargeTypeGiftBox, 2, 1200},
+	}
+	if len(surcharges) != len(want) {
+		t.Fatalf("expected %d surcharges, got %+v", len(want), surcharges)
+	}
+	for i, w := range want {
+		if surcharges[i].Type != w.kind || surcharges[i].Quantity != w.units || surcharges[i].Amount != w.amount {
+			t.Fatalf("surcharge %d: expected %+v, got %+v", i, w, surcharges[i])
+		}
+	}
+	if surcharges[3].TaxCode != JPTaxCodeStandard || surcharges[0].Description != "Premium font: Reisho" {
+		t.Fatalf("unexpected surcharge details %+v", surcharges)
+	}
+
+	surcharges, err = resolver.ResolveSurcharges(ctx, CartItem{
+		Quantity:      1,
+		Customization: map[string]any{CustomizationKeyFont: "kaisho", CustomizationKeyMaterial: "tsuge", CustomizationKeyText: "山田"},
+	}, "JPY")
+	if err != nil || len(surcharges) != 0 {
+		t.Fatalf("expected no surcharges for standard choices, got %+v (%v)", surcharges, err)
+	}
+
+	if _, err := resolver.ResolveSurcharges(ctx, CartItem{Quantity: 1, Customization: map[string]any{CustomizationKeyGiftBox: true}}, "USD"); !errors.Is(err, ErrCartPricingCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch, got %v", err)
+	}
+}
+
+func TestCartPricingEngine_CustomizationSurcharges(t *testing.T) {
+	tax, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{})
+	if err != nil {
+		t.Fatalf("NewJPConsumptionTaxCalculator error: %v", err)
+	}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion:  &fakePromotionService{},
+		Tax:        tax,
+		Surcharges: newTestSurchargeResolver(t),
+		ItemRules: []ItemDiscountRule{&fakeItemDiscountRule{name: "half", fn: func(_ CartItem, subtotal int64) int64 {
+			return subtotal / 2
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+
+	res, err := engine.Calculate(context.Background(), PriceCartCommand{Cart: Cart{
+		Currency: "JPY",
+		Items: []CartItem{{
+			ID:            "line_1",
+			SKU:           "snack-seal",
+			Quantity:      1,
+			UnitPrice:     1000,
+			Currency:      "JPY",
+			TaxCode:       JPTaxCodeReduced,
+			Customization: map[string]any{CustomizationKeyFont: "reisho", CustomizationKeyGiftBox: true},
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	line := res.Breakdown.Items[0]
+	// The rule halves the goods only; surcharges are added at full price.
+	if line.Subtotal != 2100 || line.Discount != 500 || len(line.Surcharges) != 2 || line.Metadata["surchargeTotal"] != int64(1100) {
+		t.Fatalf("unexpected line %+v", line)
+	}
+	// Goods and the font surcharge follow the reduced rate: (500+500)*8% = 80. The gift box is
+	// standard rated: 600*10% = 60.
+	if res.Breakdown.Tax != 140 || len(res.Breakdown.Taxes) != 2 {
+		t.Fatalf("expected split tax 140, got %d %+v", res.Breakdown.Tax, res.Breakdown.Taxes)
+	}
+}
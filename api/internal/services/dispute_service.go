@@ -0,0 +1,645 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+const (
+	defaultDisputePageSize        = 50
+	maxDisputePageSize            = 200
+	defaultDisputeReminderWindow  = 72 * time.Hour
+	defaultDisputeReminderLimit   = 100
+	disputeEvidenceIdempotencyKey = "dispute-evidence-"
+
+	// DisputeEventOpened is published when a new chargeback is recorded.
+	DisputeEventOpened = "dispute.opened"
+	// DisputeEventEvidenceDue is published when an unanswered dispute approaches its deadline.
+	DisputeEventEvidenceDue = "dispute.evidence_due"
+	// DisputeEventClosed is published when the PSP reports the dispute outcome.
+	DisputeEventClosed = "dispute.closed"
+
+	auditActionDisputeOpen     = "payment.dispute.open"
+	auditActionDisputeStatus   = "payment.dispute.status"
+	auditActionDisputeEvidence = "payment.dispute.evidence"
+	auditActionDisputeSubmit   = "payment.dispute.submit"
+)
+
+var (
+	// ErrDisputeInvalidInput indicates the dispute request is malformed.
+	ErrDisputeInvalidInput = errors.New("dispute: invalid input")
+	// ErrDisputeNotFound indicates the dispute or its payment could not be located.
+	ErrDisputeNotFound = errors.New("dispute: not found")
+	// ErrDisputeInvalidState indicates the dispute no longer accepts evidence.
+	ErrDisputeInvalidState = errors.New("dispute: invalid state")
+)
+
+var disputeEvidenceKinds = map[string]struct{}{
+	payments.DisputeEvidenceReceipt:               {},
+	payments.DisputeEvidenceShippingDocumentation: {},
+	payments.DisputeEvidenceCustomerCommunication: {},
+	payments.DisputeEvidenceUncategorized:         {},
+}
+
+// DisputeEvidenceSubmitter forwards evidence to the PSP. *payments.Manager satisfies it.
+type DisputeEvidenceSubmitter interface {
+	SubmitDisputeEvidence(ctx context.Context, paymentCtx payments.PaymentContext, req payments.DisputeEvidenceRequest) (payments.DisputeDetails, error)
+}
+
+// DisputeEvidenceReader loads the content of an uploaded evidence asset.
+type DisputeEvidenceReader interface {
+	ReadEvidence(ctx context.Context, assetID string) (payments.DisputeEvidenceFile, error)
+}
+
+// DisputeEventPublisher emits dispute lifecycle events so staff can be notified.
+type DisputeEventPublisher interface {
+	PublishDisputeEvent(ctx context.Context, event DisputeEvent) error
+}
+
+// DisputeEvent captures metadata for dispute lifecycle events.
+type DisputeEvent struct {
+	Type          string
+	DisputeID     string
+	OrderID       string
+	PaymentID     string
+	Status        DisputeStatus
+	EvidenceDueBy *time.Time
+	ActorID       string
+	OccurredAt    time.Time
+	Metadata      map[string]any
+}
+
+// DisputeServiceDeps bundles the collaborators required to construct the dispute service.
+type DisputeServiceDeps struct {
+	Disputes    repositories.PaymentDisputeRepository
+	Payments    repositories.OrderPaymentRepository
+	Orders      repositories.OrderRepository
+	Submitter   DisputeEvidenceSubmitter
+	Evidence    DisputeEvidenceReader
+	Events      DisputeEventPublisher
+	Audit       AuditLogService
+	Clock       func() time.Time
+	IDGenerator func() string
+	Logger      func(ctx context.Context, event string, fields map[string]any)
+}
+
+type disputeService struct {
+	disputes  repositories.PaymentDisputeRepository
+	payments  repositories.OrderPaymentRepository
+	orders    repositories.OrderRepository
+	submitter DisputeEvidenceSubmitter
+	evidence  DisputeEvidenceReader
+	events    DisputeEventPublisher
+	audit     AuditLogService
+	clock     func() time.Time
+	newID     func() string
+	logger    func(context.Context, string, map[string]any)
+}
+
+// NewDisputeService wires dependencies into a DisputeService.
+func NewDisputeService(deps DisputeServiceDeps) (DisputeService, error) {
+	if deps.Disputes == nil {
+		return nil, errors.New("dispute service: dispute repository is required")
+	}
+	if deps.Payments == nil {
+		return nil, errors.New("dispute service: payment repository is required")
+	}
+	if deps.Orders == nil {
+		return nil, errors.New("dispute service: order repository is required")
+	}
+	if deps.Submitter == nil {
+		return nil, errors.New("dispute service: evidence submitter is required")
+	}
+	if deps.Evidence == nil {
+		return nil, errors.New("dispute service: evidence reader is required")
+	}
+
+	clock := deps.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	idGen := deps.IDGenerator
+	if idGen == nil {
+		idGen = func() string {
+			return ulid.Make().String()
+		}
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+
+	return &disputeService{
+		disputes:  deps.Disputes,
+		payments:  deps.Payments,
+		orders:    deps.Orders,
+		submitter: deps.Submitter,
+		evidence:  deps.Evidence,
+		events:    deps.Events,
+		audit:     deps.Audit,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		newID:  idGen,
+		logger: logger,
+	}, nil
+}
+
+// IngestDispute records a new PSP dispute against its payment and flags the order for manual review,
+// or applies a status update to a dispute already on file. Closed disputes are not reopened.
+func (s *disputeService) IngestDispute(ctx context.Context, cmd IngestDisputeCommand) (PaymentDispute, error) {
+	provider := strings.ToLower(strings.TrimSpace(cmd.Provider))
+	pspID := strings.TrimSpace(cmd.PSPDisputeID)
+	if provider == "" || pspID == "" {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "provider and dispute id are required")
+	}
+	status := cmd.Status
+	if status == "" {
+		status = domain.DisputeStatusNeedsResponse
+	}
+	if !isKnownDisputeStatus(status) {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "unsupported status %q", status)
+	}
+
+	existing, err := s.disputes.FindByPSPDispute(ctx, provider, pspID)
+	switch {
+	case err == nil:
+		return s.applyDisputeUpdate(ctx, existing, cmd, status)
+	case !isRepositoryNotFound(err):
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+
+	intentID := strings.TrimSpace(cmd.IntentID)
+	if intentID == "" {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "payment intent is required for a new dispute")
+	}
+	payment, err := s.payments.FindByIntent(ctx, provider, intentID)
+	if err != nil {
+		if isRepositoryNotFound(err) {
+			return PaymentDispute{}, detailf(ErrDisputeNotFound, "no payment for intent %s", intentID)
+		}
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+
+	now := s.clock()
+	currency := strings.ToUpper(strings.TrimSpace(cmd.Currency))
+	if currency == "" {
+		currency = payment.Currency
+	}
+	dispute := domain.PaymentDispute{
+		ID:            s.newID(),
+		OrderID:       payment.OrderID,
+		PaymentID:     payment.ID,
+		Provider:      provider,
+		PSPDisputeID:  pspID,
+		Status:        status,
+		Reason:        strings.TrimSpace(cmd.Reason),
+		Amount:        cmd.Amount,
+		Currency:      currency,
+		EvidenceDueBy: normalizeTimePointer(cmd.EvidenceDueBy),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if isClosedDisputeStatus(status) {
+		dispute.ClosedAt = &now
+	}
+	if err := s.disputes.Insert(ctx, dispute); err != nil {
+		if isRepositoryConflict(err) {
+			// A concurrent delivery of the same dispute won the insert; apply this one as an update.
+			existing, findErr := s.disputes.FindByPSPDispute(ctx, provider, pspID)
+			if findErr != nil {
+				return PaymentDispute{}, s.mapRepositoryError(findErr)
+			}
+			return s.applyDisputeUpdate(ctx, existing, cmd, status)
+		}
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+
+	s.flagOrderForReview(ctx, dispute)
+	s.recordAudit(ctx, auditActionDisputeOpen, "", dispute, map[string]any{
+		"reason":  dispute.Reason,
+		"amount":  dispute.Amount,
+		"eventId": cmd.EventID,
+	}, nil)
+	s.emitEvent(ctx, DisputeEventOpened, dispute, "")
+	return dispute, nil
+}
+
+func (s *disputeService) applyDisputeUpdate(ctx context.Context, dispute domain.PaymentDispute, cmd IngestDisputeCommand, status DisputeStatus) (PaymentDispute, error) {
+	if isClosedDisputeStatus(dispute.Status) {
+		return dispute, nil
+	}
+
+	updated := dispute
+	updated.Status = status
+	if reason := strings.TrimSpace(cmd.Reason); reason != "" {
+		updated.Reason = reason
+	}
+	if cmd.Amount > 0 {
+		updated.Amount = cmd.Amount
+	}
+	if due := normalizeTimePointer(cmd.EvidenceDueBy); due != nil {
+		updated.EvidenceDueBy = due
+	}
+	if updated.Status == dispute.Status && updated.Reason == dispute.Reason && updated.Amount == dispute.Amount &&
+		sameTimePointer(updated.EvidenceDueBy, dispute.EvidenceDueBy) {
+		return dispute, nil
+	}
+
+	now := s.clock()
+	closed := isClosedDisputeStatus(status)
+	if closed {
+		updated.ClosedAt = &now
+	}
+	updated.UpdatedAt = now
+	if err := s.disputes.Update(ctx, updated); err != nil {
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+
+	if updated.Status != dispute.Status {
+		s.recordAudit(ctx, auditActionDisputeStatus, "", updated, map[string]any{"eventId": cmd.EventID}, map[string]AuditLogDiff{
+			"status": {Before: string(dispute.Status), After: string(updated.Status)},
+		})
+	}
+	if closed {
+		s.emitEvent(ctx, DisputeEventClosed, updated, "")
+	}
+	return updated, nil
+}
+
+// ListDisputes returns disputes ordered by evidence deadline, earliest first.
+func (s *disputeService) ListDisputes(ctx context.Context, filter DisputeListFilter) (domain.CursorPage[PaymentDispute], error) {
+	for _, status := range filter.Status {
+		if !isKnownDisputeStatus(status) {
+			return domain.CursorPage[PaymentDispute]{}, detailf(ErrDisputeInvalidInput, "unsupported status %q", status)
+		}
+	}
+	pagination := filter.Pagination
+	if pagination.PageSize <= 0 {
+		pagination.PageSize = defaultDisputePageSize
+	}
+	if pagination.PageSize > maxDisputePageSize {
+		pagination.PageSize = maxDisputePageSize
+	}
+
+	page, err := s.disputes.List(ctx, repositories.DisputeListFilter{
+		Status:     filter.Status,
+		OrderID:    strings.TrimSpace(filter.OrderID),
+		DueBefore:  normalizeTimePointer(filter.DueBefore),
+		Pagination: pagination,
+	})
+	if err != nil {
+		return domain.CursorPage[PaymentDispute]{}, s.mapRepositoryError(err)
+	}
+	return page, nil
+}
+
+// GetDispute loads a single dispute.
+func (s *disputeService) GetDispute(ctx context.Context, disputeID string) (PaymentDispute, error) {
+	disputeID = strings.TrimSpace(disputeID)
+	if disputeID == "" {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "dispute id is required")
+	}
+	dispute, err := s.disputes.FindByID(ctx, disputeID)
+	if err != nil {
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+	return dispute, nil
+}
+
+// AddEvidence attaches an uploaded asset to a dispute awaiting a response. Each kind holds a single
+// asset, so adding a second asset of the same kind replaces the first.
+func (s *disputeService) AddEvidence(ctx context.Context, cmd AddDisputeEvidenceCommand) (PaymentDispute, error) {
+	assetID := strings.TrimSpace(cmd.AssetID)
+	if assetID == "" {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "asset id is required")
+	}
+	kind := strings.ToLower(strings.TrimSpace(cmd.Kind))
+	if kind == "" {
+		kind = payments.DisputeEvidenceUncategorized
+	}
+	if _, ok := disputeEvidenceKinds[kind]; !ok {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "unsupported evidence kind %q", cmd.Kind)
+	}
+
+	dispute, err := s.GetDispute(ctx, cmd.DisputeID)
+	if err != nil {
+		return PaymentDispute{}, err
+	}
+	if err := s.ensureAcceptsEvidence(dispute); err != nil {
+		return PaymentDispute{}, err
+	}
+
+	now := s.clock()
+	actor := strings.TrimSpace(cmd.ActorID)
+	entry := domain.DisputeEvidence{
+		AssetID:     assetID,
+		Kind:        kind,
+		FileName:    strings.TrimSpace(cmd.FileName),
+		ContentType: strings.TrimSpace(cmd.ContentType),
+		UploadedBy:  actor,
+		UploadedAt:  now,
+	}
+	evidence := make([]domain.DisputeEvidence, 0, len(dispute.Evidence)+1)
+	for _, existing := range dispute.Evidence {
+		if existing.Kind != kind {
+			evidence = append(evidence, existing)
+		}
+	}
+	dispute.Evidence = append(evidence, entry)
+	dispute.UpdatedAt = now
+	if err := s.disputes.Update(ctx, dispute); err != nil {
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+
+	s.recordAudit(ctx, auditActionDisputeEvidence, actor, dispute, map[string]any{
+		"assetId": assetID,
+		"kind":    kind,
+	}, nil)
+	return dispute, nil
+}
+
+// SubmitEvidence reads the attached assets and submits them to the PSP with the explanation.
+func (s *disputeService) SubmitEvidence(ctx context.Context, cmd SubmitDisputeEvidenceCommand) (PaymentDispute, error) {
+	explanation := strings.TrimSpace(cmd.Explanation)
+	dispute, err := s.GetDispute(ctx, cmd.DisputeID)
+	if err != nil {
+		return PaymentDispute{}, err
+	}
+	if err := s.ensureAcceptsEvidence(dispute); err != nil {
+		return PaymentDispute{}, err
+	}
+	if len(dispute.Evidence) == 0 && explanation == "" {
+		return PaymentDispute{}, detailf(ErrDisputeInvalidInput, "evidence or an explanation is required")
+	}
+
+	files := make([]payments.DisputeEvidenceFile, 0, len(dispute.Evidence))
+	for _, entry := range dispute.Evidence {
+		file, err := s.evidence.ReadEvidence(ctx, entry.AssetID)
+		if err != nil {
+			return PaymentDispute{}, fmt.Errorf("dispute: read evidence %s: %w", entry.AssetID, err)
+		}
+		file.Kind = entry.Kind
+		if entry.FileName != "" {
+			file.FileName = entry.FileName
+		}
+		if entry.ContentType != "" {
+			file.ContentType = entry.ContentType
+		}
+		files = append(files, file)
+	}
+
+	details, err := s.submitter.SubmitDisputeEvidence(ctx, payments.PaymentContext{
+		PreferredProvider: dispute.Provider,
+		Currency:          dispute.Currency,
+	}, payments.DisputeEvidenceRequest{
+		DisputeID:      dispute.PSPDisputeID,
+		Explanation:    explanation,
+		Files:          files,
+		IdempotencyKey: disputeEvidenceIdempotencyKey + dispute.ID,
+		Metadata: map[string]string{
+			"disputeId": dispute.ID,
+			"orderId":   dispute.OrderID,
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, payments.ErrDisputeEvidenceInvalid):
+			return PaymentDispute{}, fmt.Errorf("%w: %v", ErrDisputeInvalidInput, err)
+		case errors.Is(err, payments.ErrUnsupportedProvider):
+			return PaymentDispute{}, fmt.Errorf("%w: %v", ErrDisputeInvalidState, err)
+		}
+		return PaymentDispute{}, err
+	}
+
+	now := s.clock()
+	actor := strings.TrimSpace(cmd.ActorID)
+	previous := dispute.Status
+	dispute.Status = domain.DisputeStatusUnderReview
+	if status := domain.DisputeStatus(details.Status); isKnownDisputeStatus(status) {
+		dispute.Status = status
+	}
+	dispute.SubmittedAt = &now
+	dispute.SubmittedBy = actor
+	dispute.UpdatedAt = now
+	if err := s.disputes.Update(ctx, dispute); err != nil {
+		// The PSP accepted the evidence; a retry would be rejected, so surface the storage failure only.
+		s.logger(ctx, "dispute_submit_persist_failed", map[string]any{
+			"disputeId": dispute.ID,
+			"error":     err.Error(),
+		})
+		return PaymentDispute{}, s.mapRepositoryError(err)
+	}
+
+	s.recordAudit(ctx, auditActionDisputeSubmit, actor, dispute, map[string]any{
+		"files":          len(files),
+		"hasExplanation": explanation != "",
+	}, map[string]AuditLogDiff{
+		"status": {Before: string(previous), After: string(dispute.Status)},
+	})
+	return dispute, nil
+}
+
+// RemindEvidenceDue notifies staff once about each unanswered dispute whose evidence is due within
+// the window.
+func (s *disputeService) RemindEvidenceDue(ctx context.Context, cmd DisputeReminderCommand) (DisputeReminderResult, error) {
+	now := cmd.Now.UTC()
+	if cmd.Now.IsZero() {
+		now = s.clock()
+	}
+	window := cmd.Window
+	if window <= 0 {
+		window = defaultDisputeReminderWindow
+	}
+	limit := cmd.Limit
+	if limit <= 0 {
+		limit = defaultDisputeReminderLimit
+	}
+	dueBefore := now.Add(window)
+
+	var result DisputeReminderResult
+	pageToken := ""
+	for result.Checked < limit {
+		page, err := s.disputes.List(ctx, repositories.DisputeListFilter{
+			Status:     []domain.DisputeStatus{domain.DisputeStatusNeedsResponse},
+			DueBefore:  &dueBefore,
+			Pagination: domain.Pagination{PageSize: limit - result.Checked, PageToken: pageToken},
+		})
+		if err != nil {
+			return result, s.mapRepositoryError(err)
+		}
+		for _, dispute := range page.Items {
+			result.Checked++
+			if dispute.ReminderSentAt != nil || dispute.SubmittedAt != nil {
+				continue
+			}
+			reminded := dispute
+			reminded.ReminderSentAt = &now
+			reminded.UpdatedAt = now
+			if err := s.disputes.Update(ctx, reminded); err != nil {
+				s.logger(ctx, "dispute_reminder_failed", map[string]any{
+					"disputeId": dispute.ID,
+					"error":     err.Error(),
+				})
+				continue
+			}
+			s.emitEvent(ctx, DisputeEventEvidenceDue, reminded, "")
+			result.Reminded = append(result.Reminded, reminded.ID)
+		}
+		pageToken = strings.TrimSpace(page.NextPageToken)
+		if pageToken == "" || len(page.Items) == 0 {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *disputeService) ensureAcceptsEvidence(dispute domain.PaymentDispute) error {
+	if dispute.Status != domain.DisputeStatusNeedsResponse || dispute.SubmittedAt != nil {
+		return detailf(ErrDisputeInvalidState, "dispute %s is %s", dispute.ID, dispute.Status)
+	}
+	if dispute.EvidenceDueBy != nil && !s.clock().Before(*dispute.EvidenceDueBy) {
+		return detailf(ErrDisputeInvalidState, "evidence was due by %s", dispute.EvidenceDueBy.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// flagOrderForReview marks the disputed order for manual handling. Failures are logged rather than
+// returned so the dispute record is never lost to an order write.
+func (s *disputeService) flagOrderForReview(ctx context.Context, dispute domain.PaymentDispute) {
+	order, err := s.orders.FindByID(ctx, dispute.OrderID)
+	if err != nil {
+		s.logger(ctx, "dispute_order_flag_failed", map[string]any{
+			"disputeId": dispute.ID,
+			"orderId":   dispute.OrderID,
+			"error":     err.Error(),
+		})
+		return
+	}
+	if order.Flags.ManualReview {
+		return
+	}
+	order.Flags.ManualReview = true
+	order.UpdatedAt = s.clock()
+	if err := s.orders.Update(ctx, order); err != nil {
+		s.logger(ctx, "dispute_order_flag_failed", map[string]any{
+			"disputeId": dispute.ID,
+			"orderId":   dispute.OrderID,
+			"error":     err.Error(),
+		})
+	}
+}
+
+func (s *disputeService) emitEvent(ctx context.Context, eventType string, dispute domain.PaymentDispute, actorID string) {
+	if s.events == nil {
+		return
+	}
+	event := DisputeEvent{
+		Type:          eventType,
+		DisputeID:     dispute.ID,
+		OrderID:       dispute.OrderID,
+		PaymentID:     dispute.PaymentID,
+		Status:        dispute.Status,
+		EvidenceDueBy: normalizeTimePointer(dispute.EvidenceDueBy),
+		ActorID:       actorID,
+		OccurredAt:    s.clock(),
+		Metadata: map[string]any{
+			"provider": dispute.Provider,
+			"reason":   dispute.Reason,
+			"amount":   dispute.Amount,
+			"currency": dispute.Currency,
+		},
+	}
+	if err := s.events.PublishDisputeEvent(ctx, event); err != nil {
+		s.logger(ctx, "dispute_event_publish_failed", map[string]any{
+			"disputeId": dispute.ID,
+			"type":      eventType,
+			"error":     err.Error(),
+		})
+	}
+}
+
+func (s *disputeService) recordAudit(ctx context.Context, action, actor string, dispute domain.PaymentDispute, metadata map[string]any, diff map[string]AuditLogDiff) {
+	if s.audit == nil {
+		return
+	}
+	fields := map[string]any{
+		"service":      "dispute",
+		"provider":     dispute.Provider,
+		"pspDisputeId": dispute.PSPDisputeID,
+		"paymentId":    dispute.PaymentID,
+	}
+	for k, v := range metadata {
+		fields[k] = v
+	}
+	if actor == "" {
+		actor = paymentWebhookActor
+	}
+	s.audit.Record(ctx, AuditLogRecord{
+		Actor:      actor,
+		Action:     action,
+		TargetRef:  fmt.Sprintf("/orders/%s/disputes/%s", dispute.OrderID, dispute.ID),
+		OccurredAt: dispute.UpdatedAt,
+		Metadata:   fields,
+		Diff:       diff,
+	})
+}
+
+func (s *disputeService) mapRepositoryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var repoErr repositories.RepositoryError
+	if errors.As(err, &repoErr) {
+		switch {
+		case repoErr.IsNotFound():
+			return fmt.Errorf("%w: %v", ErrDisputeNotFound, err)
+		case repoErr.IsConflict():
+			return fmt.Errorf("%w: %v", ErrDisputeInvalidState, err)
+		case repoErr.IsUnavailable():
+			return fmt.Errorf("dispute: repository unavailable: %w", err)
+		}
+	}
+	return err
+}
+
+func isKnownDisputeStatus(status DisputeStatus) bool {
+	switch status {
+	case domain.DisputeStatusNeedsResponse, domain.DisputeStatusUnderReview, domain.DisputeStatusWon, domain.DisputeStatusLost:
+		return true
+	}
+	return false
+}
+
+func isClosedDisputeStatus(status DisputeStatus) bool {
+	return status == domain.DisputeStatusWon || status == domain.DisputeStatusLost
+}
+
+func isRepositoryConflict(err error) bool {
+	var repoErr repositories.RepositoryError
+	return errors.As(err, &repoErr) && repoErr.IsConflict()
+}
+
+func normalizeTimePointer(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	value := t.UTC()
+	return &value
+}
+
+func sameTimePointer(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
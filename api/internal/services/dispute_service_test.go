@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+type memoryDisputeRepo struct {
+	disputes []domain.PaymentDispute
+	filters  []repositories.DisputeListFilter
+}
+
+func (r *memoryDisputeRepo) Insert(_ context.Context, dispute domain.PaymentDispute) error {
+	for _, existing := range r.disputes {
+		if existing.Provider == dispute.Provider && existing.PSPDisputeID == dispute.PSPDisputeID {
+			return repoError{message: "dispute exists", conflict: true}
+		}
+	}
+	r.disputes = append(r.disputes, dispute)
+	return nil
+}
+
+func (r *memoryDisputeRepo) Update(_ context.Context, dispute domain.PaymentDispute) error {
+	for i := range r.disputes {
+		if r.disputes[i].ID == dispute.ID {
+			r.disputes[i] = dispute
+			return nil
+		}
+	}
+	return repoError{message: "dispute not found", notFound: true}
+}
+
+func (r *memoryDisputeRepo) FindByID(_ context.Context, disputeID string) (domain.PaymentDispute, error) {
+	for _, dispute := range r.disputes {
+		if dispute.ID == disputeID {
+			return dispute, nil
+		}
+	}
+	return domain.PaymentDispute{}, repoError{message: "dispute not found", notFound: true}
+}
+
+func (r *memoryDisputeRepo) FindByPSPDispute(_ context.Context, provider, pspDisputeID string) (domain.PaymentDispute, error) {
+	for _, dispute := range r.disputes {
+		if dispute.Provider == provider && dispute.PSPDisputeID == pspDisputeID {
+			return dispute, nil
+		}
+	}
+	return domain.PaymentDispute{}, repoError{message: "dispute not found", notFound: true}
+}
+
+func (r *memoryDisputeRepo) List(_ context.Context, filter repositories.DisputeListFilter) (domain.CursorPage[domain.PaymentDispute], error) {
+	r.filters = append(r.filters, filter)
+	var matched []domain.PaymentDispute
+	for _, dispute := range r.disputes {
+		if len(filter.Status) > 0 && dispute.Status != filter.Status[0] {
+			continue
+		}
+		if filter.DueBefore != nil && (dispute.EvidenceDueBy == nil || !dispute.EvidenceDueBy.Before(*filter.DueBefore)) {
+			continue
+		}
+		matched = append(matched, dispute)
+	}
+	offset := 0
+	if filter.Pagination.PageToken != "" {
+		offset, _ = strconv.Atoi(filter.Pagination.PageToken)
+	}
+	end := offset + filter.Pagination.PageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page := domain.CursorPage[domain.PaymentDispute]{Items: matched[offset:end]}
+	if end < len(matched) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+type stubDisputeSubmitter struct {
+	requests []payments.DisputeEvidenceRequest
+	err      error
+}
+
+func (s *stubDisputeSubmitter) SubmitDisputeEvidence(_ context.Context, _ payments.PaymentContext, req payments.DisputeEvidenceRequest) (payments.DisputeDetails, error) {
+	s.requests = append(s.requests, req)
+	if s.err != nil {
+		return payments.DisputeDetails{}, s.err
+	}
+	return payments.DisputeDetails{DisputeID: req.DisputeID, Status: "under_review"}, nil
+}
+
+type stubEvidenceReader map[string]payments.DisputeEvidenceFile
+
+func (s stubEvidenceReader) ReadEvidence(_ context.Context, assetID string) (payments.DisputeEvidenceFile, error) {
+	file, ok := s[assetID]
+	if !ok {
+		return payments.DisputeEvidenceFile{}, errors.New("asset not found")
+	}
+	return file, nil
+}
+
+type recordingDisputeEvents struct {
+	events []DisputeEvent
+}
+
+func (r *recordingDisputeEvents) PublishDisputeEvent(_ context.Context, event DisputeEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+type disputeFixture struct {
+	svc       DisputeService
+	disputes  *memoryDisputeRepo
+	order     *domain.Order
+	submitter *stubDisputeSubmitter
+	events    *recordingDisputeEvents
+	audit     *recordingAuditService
+	now       *time.Time
+}
+
+func newDisputeFixture(t *testing.T) *disputeFixture {
+	t.Helper()
+	now := time.Date(2024, 8, 1, 9, 0, 0, 0, time.UTC)
+	fixture := &disputeFixture{
+		disputes:  &memoryDisputeRepo{},
+		order:     &domain.Order{ID: "ord_1", Status: domain.OrderStatusPaid},
+		submitter: &stubDisputeSubmitter{},
+		events:    &recordingDisputeEvents{},
+		audit:     &recordingAuditService{},
+		now:       &now,
+	}
+	orders := &stubOrderRepo{
+		findFn: func(_ context.Context, orderID string) (domain.Order, error) {
+			if orderID != fixture.order.ID {
+				return domain.Order{}, repoError{message: "order not found", notFound: true}
+			}
+			return *fixture.order, nil
+		},
+		updateFn: func(_ context.Context, order domain.Order) error {
+			*fixture.order = order
+			return nil
+		},
+	}
+	seq := 0
+	svc, err := NewDisputeService(DisputeServiceDeps{
+		Disputes: fixture.disputes,
+		Payments: &memoryPaymentRepo{payments: []domain.Payment{
+			{ID: "pay_1", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Amount: 8000, Currency: "JPY"},
+		}},
+		Orders:    orders,
+		Submitter: fixture.submitter,
+		Evidence: stubEvidenceReader{
+			"asset_receipt":  {FileName: "r.pdf", ContentType: "application/pdf", Content: []byte("receipt")},
+			"asset_tracking": {FileName: "t.pdf", ContentType: "application/pdf", Content: []byte("tracking")},
+		},
+		Events: fixture.events,
+		Audit:  fixture.audit,
+		Clock:  func() time.Time { return *fixture.now },
+		IDGenerator: func() string {
+			seq++
+			return "dsp_" + strconv.Itoa(seq)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new dispute service: %v", err)
+	}
+	fixture.svc = svc
+	return fixture
+}
+
+func TestDisputeService_IngestFlagsOrderAndTracksOutcome(t *testing.T) {
+	f := newDisputeFixture(t)
+	ctx := context.Background()
+	due := f.now.Add(7 * 24 * time.Hour)
+
+	cmd := IngestDisputeCommand{
+		Provider:      "stripe",
+		PSPDisputeID:  "dp_1",
+		IntentID:      "pi_1",
+		Status:        domain.DisputeStatusNeedsResponse,
+		Reason:        "fraudulent",
+		Amount:        8000,
+		EvidenceDueBy: &due,
+		EventID:       "evt_1",
+	}
+	dispute, err := f.svc.IngestDispute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if dispute.OrderID != "ord_1" || dispute.PaymentID != "pay_1" || dispute.Currency != "JPY" || !dispute.EvidenceDueBy.Equal(due) {
+		t.Fatalf("unexpected dispute %+v", dispute)
+	}
+	if !f.order.Flags.ManualReview {
+		t.Fatalf("expected order to be flagged for manual review")
+	}
+	if len(f.events.events) != 1 || f.events.events[0].Type != DisputeEventOpened {
+		t.Fatalf("expected dispute.opened event, got %+v", f.events.events)
+	}
+	if len(f.audit.records) != 1 || f.audit.records[0].Action != "payment.dispute.open" || f.audit.records[0].TargetRef != "/orders/ord_1/disputes/dsp_1" {
+		t.Fatalf("unexpected audit %+v", f.audit.records)
+	}
+
+	// Redelivery of the same notification changes nothing.
+	if _, err := f.svc.IngestDispute(ctx, cmd); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if len(f.disputes.disputes) != 1 || len(f.events.events) != 1 || len(f.audit.records) != 1 {
+		t.Fatalf("redelivery must be a no-op")
+	}
+
+	closed, err := f.svc.IngestDispute(ctx, IngestDisputeCommand{Provider: "stripe", PSPDisputeID: "dp_1", Status: domain.DisputeStatusLost})
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if closed.Status != domain.DisputeStatusLost || closed.ClosedAt == nil || closed.Reason != "fraudulent" {
+		t.Fatalf("unexpected closed dispute %+v", closed)
+	}
+	if last := f.events.events[len(f.events.events)-1]; last.Type != DisputeEventClosed {
+		t.Fatalf("expected dispute.closed event, got %s", last.Type)
+	}
+
+	// A late update cannot reopen a closed dispute.
+	reopened, err := f.svc.IngestDispute(ctx, IngestDisputeCommand{Provider: "stripe", PSPDisputeID: "dp_1", Status: domain.DisputeStatusUnderReview})
+	if err != nil || reopened.Status != domain.DisputeStatusLost {
+		t.Fatalf("expected closed dispute to stay lost, got %s err=%v", reopened.Status, err)
+	}
+
+	if _, err := f.svc.IngestDispute(ctx, IngestDisputeCommand{Provider: "stripe", PSPDisputeID: "dp_2", IntentID: "pi_unknown"}); !errors.Is(err, ErrDisputeNotFound) {
+		t.Fatalf("expected unknown payment to be reported, got %v", err)
+	}
+}
+
+func TestDisputeService_EvidenceSubmission(t *testing.T) {
+	f := newDisputeFixture(t)
+	ctx := context.Background()
+	due := f.now.Add(48 * time.Hour)
+	dispute, err := f.svc.IngestDispute(ctx, IngestDisputeCommand{Provider: "stripe", PSPDisputeID: "dp_1", IntentID: "pi_1", Amount: 8000, EvidenceDueBy: &due})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	if _, err := f.svc.SubmitEvidence(ctx, SubmitDisputeEvidenceCommand{DisputeID: dispute.ID, ActorID: "staff_1"}); !errors.Is(err, ErrDisputeInvalidInput) {
+		t.Fatalf("expected empty submission to be rejected, got %v", err)
+	}
+	if _, err := f.svc.AddEvidence(ctx, AddDisputeEvidenceCommand{DisputeID: dispute.ID, AssetID: "asset_receipt", Kind: "selfie"}); !errors.Is(err, ErrDisputeInvalidInput) {
+		t.Fatalf("expected unknown kind to be rejected, got %v", err)
+	}
+
+	for _, cmd := range []AddDisputeEvidenceCommand{
+		{DisputeID: dispute.ID, AssetID: "asset_tracking", Kind: payments.DisputeEvidenceReceipt, ActorID: "staff_1"},
+		{DisputeID: dispute.ID, AssetID: "asset_receipt", Kind: payments.DisputeEvidenceReceipt, FileName: "receipt.pdf", ActorID: "staff_1"},
+		{DisputeID: dispute.ID, AssetID: "asset_tracking", Kind: payments.DisputeEvidenceShippingDocumentation, ActorID: "staff_1"},
+	} {
+		if dispute, err = f.svc.AddEvidence(ctx, cmd); err != nil {
+			t.Fatalf("add evidence: %v", err)
+		}
+	}
+	if len(dispute.Evidence) != 2 || dispute.Evidence[0].AssetID != "asset_receipt" {
+		t.Fatalf("expected the receipt to be replaced, got %+v", dispute.Evidence)
+	}
+
+	submitted, err := f.svc.SubmitEvidence(ctx, SubmitDisputeEvidenceCommand{DisputeID: dispute.ID, Explanation: "Delivered 7/20", ActorID: "staff_1"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if submitted.Status != domain.DisputeStatusUnderReview || submitted.SubmittedAt == nil || submitted.SubmittedBy != "staff_1" {
+		t.Fatalf("unexpected submitted dispute %+v", submitted)
+	}
+	req := f.submitter.requests[0]
+	if req.DisputeID != "dp_1" || req.Explanation != "Delivered 7/20" || len(req.Files) != 2 || req.IdempotencyKey != "dispute-evidence-dsp_1" {
+		t.Fatalf("unexpected PSP request %+v", req)
+	}
+	if file := req.Files[0]; file.Kind != payments.DisputeEvidenceReceipt || file.FileName != "receipt.pdf" || string(file.Content) != "receipt" {
+		t.Fatalf("unexpected evidence file %+v", file)
+	}
+
+	if _, err := f.svc.SubmitEvidence(ctx, SubmitDisputeEvidenceCommand{DisputeID: dispute.ID, Explanation: "again"}); !errors.Is(err, ErrDisputeInvalidState) {
+		t.Fatalf("expected resubmission to be rejected, got %v", err)
+	}
+	if _, err := f.svc.AddEvidence(ctx, AddDisputeEvidenceCommand{DisputeID: dispute.ID, AssetID: "asset_receipt"}); !errors.Is(err, ErrDisputeInvalidState) {
+		t.Fatalf("expected evidence after submission to be rejected, got %v", err)
+	}
+
+	late, err := f.svc.IngestDispute(ctx, IngestDisputeCommand{Provider: "stripe", PSPDisputeID: "dp_late", IntentID: "pi_1", EvidenceDueBy: &due})
+	if err != nil {
+		t.Fatalf("ingest late: %v", err)
+	}
+	*f.now = due
+	if _, err := f.svc.AddEvidence(ctx, AddDisputeEvidenceCommand{DisputeID: late.ID, AssetID: "asset_receipt"}); !errors.Is(err, ErrDisputeInvalidState) {
+		t.Fatalf("expected evidence after the deadline to be rejected, got %v", err)
+	}
+}
+
+func TestDisputeService_RemindEvidenceDueOnce(t *testing.T) {
+	f := newDisputeFixture(t)
+	ctx := context.Background()
+	soon := f.now.Add(24 * time.Hour)
+	later := f.now.Add(10 * 24 * time.Hour)
+	for i, due := range []time.Time{soon, later} {
+		due := due
+		if _, err := f.svc.IngestDispute(ctx, IngestDisputeCommand{Provider: "stripe", PSPDisputeID: "dp_" + strconv.Itoa(i), IntentID: "pi_1", EvidenceDueBy: &due}); err != nil {
+			t.Fatalf("ingest: %v", err)
+		}
+	}
+	f.events.events = nil
+
+	result, err := f.svc.RemindEvidenceDue(ctx, DisputeReminderCommand{})
+	if err != nil {
+		t.Fatalf("remind: %v", err)
+	}
+	if result.Checked != 1 || len(result.Reminded) != 1 || result.Reminded[0] != "dsp_1" {
+		t.Fatalf("unexpected reminder result %+v", result)
+	}
+	if len(f.events.events) != 1 || f.events.events[0].Type != DisputeEventEvidenceDue {
+		t.Fatalf("expected one evidence_due event, got %+v", f.events.events)
+	}
+	if want := f.now.Add(defaultDisputeReminderWindow); !f.disputes.filters[0].DueBefore.Equal(want) {
+		t.Fatalf("expected default window, got %v", f.disputes.filters[0].DueBefore)
+	}
+
+	result, err = f.svc.RemindEvidenceDue(ctx, DisputeReminderCommand{})
+	if err != nil || len(result.Reminded) != 0 || len(f.events.events) != 1 {
+		t.Fatalf("expected reminders to be sent once, got %+v err=%v", result, err)
+	}
+}
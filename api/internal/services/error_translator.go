@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+// ErrorKind classifies translated errors so transports can choose a response without knowing
+// every sentinel declared by the services.
+type ErrorKind string
+
+const (
+	// ErrorKindInvalidInput indicates the caller supplied malformed or unsupported input.
+	ErrorKindInvalidInput ErrorKind = "invalid_input"
+	// ErrorKindUnauthorized indicates the actor may not access the resource.
+	ErrorKindUnauthorized ErrorKind = "unauthorized"
+	// ErrorKindNotFound indicates the referenced resource does not exist.
+	ErrorKindNotFound ErrorKind = "not_found"
+	// ErrorKindConflict indicates the request collides with existing state (duplicates, stale writes, stock).
+	ErrorKindConflict ErrorKind = "conflict"
+	// ErrorKindInvalidState indicates the resource's lifecycle forbids the requested transition.
+	ErrorKindInvalidState ErrorKind = "invalid_state"
+	// ErrorKindUnavailable indicates a dependency is temporarily unavailable and the call may be retried.
+	ErrorKindUnavailable ErrorKind = "unavailable"
+	// ErrorKindInternal indicates an unexpected failure whose details must not reach clients.
+	ErrorKindInternal ErrorKind = "internal"
+)
+
+const (
+	// ErrorCodeNotFound is the generic code for repository lookups that found nothing.
+	ErrorCodeNotFound = "not_found"
+	// ErrorCodeConflict is the generic code for repository write conflicts.
+	ErrorCodeConflict = "conflict"
+	// ErrorCodeUnavailable is the generic code for unavailable repositories or timeouts.
+	ErrorCodeUnavailable = "unavailable"
+	// ErrorCodeInternal is the code reported for untranslated failures.
+	ErrorCodeInternal = "internal_error"
+
+	internalErrorMessage = "an internal error occurred"
+)
+
+type domainError struct {
+	kind    ErrorKind
+	code    string
+	message string
+	cause   error
+}
+
+// NewDomainError constructs a DomainError with the provided classification. The safe message is
+// returned to clients verbatim, while cause remains available through errors.Is/As.
+func NewDomainError(kind ErrorKind, code, message string, cause error) DomainError {
+	if kind == "" {
+		kind = ErrorKindInternal
+	}
+	if code == "" {
+		code = ErrorCodeInternal
+	}
+	return &domainError{kind: kind, code: code, message: message, cause: cause}
+}
+
+func (e *domainError) Error() string {
+	if e.cause != nil {
+		return e.cause.Error()
+	}
+	return e.message
+}
+
+func (e *domainError) Unwrap() error {
+	return e.cause
+}
+
+func (e *domainError) Code() string {
+	return e.code
+}
+
+func (e *domainError) SafeMessage() string {
+	return e.message
+}
+
+func (e *domainError) Kind() ErrorKind {
+	return e.kind
+}
+
+// ErrorKindOf reports the classification of err, defaulting to ErrorKindInternal when err was not
+// produced by the translator.
+func ErrorKindOf(err error) ErrorKind {
+	var kinded interface{ Kind() ErrorKind }
+	if errors.As(err, &kinded) {
+		return kinded.Kind()
+	}
+	return ErrorKindInternal
+}
+
+// DetailError attaches a client-safe detail to a service sentinel. It is the only text the
+// translator exposes besides fixed messages, so services must build Detail from caller input and
+// never from lower-level errors.
+type DetailError struct {
+	Err    error
+	Detail string
+}
+
+func (e *DetailError) Error() string {
+	if e.Detail == "" {
+		return e.Err.Error()
+	}
+	return e.Err.Error() + ": " + e.Detail
+}
+
+func (e *DetailError) Unwrap() error {
+	return e.Err
+}
+
+// detailf wraps sentinel with a client-safe detail.
+func detailf(sentinel error, format string, args ...any) error {
+	return &DetailError{Err: sentinel, Detail: fmt.Sprintf(format, args...)}
+}
+
+// sentinelRule maps a service sentinel to its stable code. Rules with exposeDetail surface the
+// DetailError attached to that sentinel instead of the fixed message; the rest of the error chain
+// is never shown to clients.
+type sentinelRule struct {
+	target       error
+	kind         ErrorKind
+	code         string
+	message      string
+	exposeDetail bool
+}
+
+var defaultSentinelRules = []sentinelRule{
+	{target: ErrOrderInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid order request", exposeDetail: true},
+	{target: ErrOrderNotFound, kind: ErrorKindNotFound, code: "order_not_found", message: "order not found"},
+	{target: ErrOrderInvalidState, kind: ErrorKindInvalidState, code: "order_invalid_state", message: "order state does not allow this operation", exposeDetail: true},
+	{target: ErrOrderConflict, kind: ErrorKindConflict, code: "order_conflict", message: "order was modified concurrently", exposeDetail: true},
+
+	{target: ErrInventoryInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid inventory request", exposeDetail: true},
+	{target: ErrInventoryInsufficientStock, kind: ErrorKindConflict, code: "insufficient_stock", message: "insufficient stock", exposeDetail: true},
+	{target: ErrInventoryReservationNotFound, kind: ErrorKindNotFound, code: "reservation_not_found", message: "reservation not found"},
+	{target: ErrInventoryInvalidState, kind: ErrorKindInvalidState, code: "reservation_invalid_state", message: "reservation state does not allow this operation", exposeDetail: true},
+	{target: ErrInventoryStockNotFound, kind: ErrorKindNotFound, code: "stock_not_found", message: "stock not found"},
+	{target: ErrInventoryLocationNotFound, kind: ErrorKindNotFound, code: "location_not_found", message: "stock location not found"},
+
+	{target: ErrStockSubscriptionInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid stock subscription request", exposeDetail: true},
+	{target: ErrStockSubscriptionNotFound, kind: ErrorKindNotFound, code: "stock_subscription_not_found", message: "stock subscription not found"},
+
+	{target: ErrReviewInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid review request", exposeDetail: true},
+	{target: ErrReviewNotFound, kind: ErrorKindNotFound, code: "review_not_found", message: "review not found"},
+	{target: ErrReviewUnauthorized, kind: ErrorKindUnauthorized, code: "review_forbidden", message: "review is not accessible"},
+	{target: ErrReviewConflict, kind: ErrorKindConflict, code: "review_conflict", message: "review already exists", exposeDetail: true},
+	{target: ErrReviewInvalidState, kind: ErrorKindInvalidState, code: "review_invalid_state", message: "review state does not allow this operation", exposeDetail: true},
+
+	{target: ErrPaymentInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid payment request", exposeDetail: true},
+	{target: ErrPaymentNotFound, kind: ErrorKindNotFound, code: "payment_not_found", message: "payment not found"},
+	{target: ErrPaymentInvalidState, kind: ErrorKindInvalidState, code: "payment_invalid_state", message: "payment state does not allow this operation", exposeDetail: true},
+	{target: ErrPaymentRefundExceedsCaptured, kind: ErrorKindInvalidState, code: "refund_exceeds_captured", message: "refund exceeds the captured amount", exposeDetail: true},
+	{target: ErrPaymentMethodInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid payment method request", exposeDetail: true},
+	{target: ErrPaymentMethodNotFound, kind: ErrorKindNotFound, code: "payment_method_not_found", message: "payment method not found"},
+	{target: ErrPaymentMethodUnavailable, kind: ErrorKindUnavailable, code: "payment_methods_unavailable", message: "saved payment methods are unavailable"},
+	{target: ErrDisputeInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid dispute request", exposeDetail: true},
+	{target: ErrDisputeNotFound, kind: ErrorKindNotFound, code: "dispute_not_found", message: "dispute not found"},
+	{target: ErrDisputeInvalidState, kind: ErrorKindInvalidState, code: "dispute_invalid_state", message: "dispute does not accept evidence", exposeDetail: true},
+	{target: ErrPaymentReconciliationInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid reconciliation request", exposeDetail: true},
+
+	{target: ErrCounterInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid counter request", exposeDetail: true},
+	{target: ErrCounterExhausted, kind: ErrorKindConflict, code: "counter_exhausted", message: "counter has reached its maximum value"},
+
+	{target: ErrAIInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid ai job request", exposeDetail: true},
+	{target: ErrAIJobNotFound, kind: ErrorKindNotFound, code: "ai_job_not_found", message: "ai job not found"},
+	{target: ErrAISuggestionNotFound, kind: ErrorKindNotFound, code: "ai_suggestion_not_found", message: "ai suggestion not found"},
+	{target: ErrAIJobAlreadyCompleted, kind: ErrorKindConflict, code: "ai_job_completed", message: "ai job already completed"},
+
+	{target: ErrWebhookInvalidPayload, kind: ErrorKindInvalidInput, code: "invalid_payload", message: "invalid webhook payload", exposeDetail: true},
+	{target: ErrWebhookDuplicate, kind: ErrorKindConflict, code: "webhook_duplicate", message: "webhook already processed"},
+
+	{target: ErrCheckoutInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid checkout request", exposeDetail: true},
+	{target: ErrCartPricingInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid pricing request", exposeDetail: true},
+	{target: ErrCartPricingCurrencyMismatch, kind: ErrorKindInvalidInput, code: "currency_mismatch", message: "currency mismatch", exposeDetail: true},
+	{target: ErrCartPricingQuoteInvalid, kind: ErrorKindInvalidInput, code: "quote_invalid", message: "price quote is invalid", exposeDetail: true},
+	{target: ErrCartPricingQuoteExpired, kind: ErrorKindInvalidState, code: "quote_expired", message: "price quote has expired", exposeDetail: true},
+	{target: ErrCartPricingPriceChanged, kind: ErrorKindConflict, code: "price_changed", message: "price changed since the quote was issued", exposeDetail: true},
+	{target: ErrFXInvalidInput, kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid exchange rate request", exposeDetail: true},
+	{target: ErrFXRateUnavailable, kind: ErrorKindUnavailable, code: "fx_rate_unavailable", message: "exchange rate is unavailable", exposeDetail: true},
+	{target: ErrFXRatesStale, kind: ErrorKindUnavailable, code: "fx_rates_stale", message: "exchange rates are out of date"},
+	{target: ErrShippingUnavailable, kind: ErrorKindInvalidInput, code: "shipping_unavailable", message: "shipping is not available for this cart", exposeDetail: true},
+
+	{target: ErrCatalogRepositoryMissing, kind: ErrorKindUnavailable, code: "catalog_unavailable", message: "catalog service is unavailable"},
+	{target: ErrContentRepositoryMissing, kind: ErrorKindUnavailable, code: "content_unavailable", message: "content service is unavailable"},
+}
+
+var inventoryErrorRules = map[repositories.InventoryErrorCode]sentinelRule{
+	repositories.InventoryErrorInsufficientStock:       {kind: ErrorKindConflict, code: "insufficient_stock", message: "insufficient stock"},
+	repositories.InventoryErrorStockNotFound:           {kind: ErrorKindNotFound, code: "stock_not_found", message: "stock not found"},
+	repositories.InventoryErrorReservationNotFound:     {kind: ErrorKindNotFound, code: "reservation_not_found", message: "reservation not found"},
+	repositories.InventoryErrorInvalidReservationState: {kind: ErrorKindInvalidState, code: "reservation_invalid_state", message: "reservation state does not allow this operation"},
+	repositories.InventoryErrorLocationNotFound:        {kind: ErrorKindNotFound, code: "location_not_found", message: "stock location not found"},
+	repositories.InventoryErrorLocationRequired:        {kind: ErrorKindInvalidInput, code: "location_required", message: "a stock location is required"},
+	repositories.InventoryErrorUnassignedStock:         {kind: ErrorKindInvalidInput, code: "stock_unassigned", message: "stock is not assigned to a location"},
+}
+
+var counterErrorRules = map[repositories.CounterErrorCode]sentinelRule{
+	repositories.CounterErrorInvalidInput: {kind: ErrorKindInvalidInput, code: "invalid_request", message: "invalid counter request"},
+	repositories.CounterErrorExhausted:    {kind: ErrorKindConflict, code: "counter_exhausted", message: "counter has reached its maximum value"},
+}
+
+type errorTranslator struct {
+	rules []sentinelRule
+}
+
+// NewErrorTranslator returns the translator that classifies service sentinels, typed repository
+// errors and repository error categories into DomainError values with stable codes.
+func NewErrorTranslator() ErrorTranslator {
+	return &errorTranslator{rules: defaultSentinelRules}
+}
+
+// Translate returns err as a DomainError. Errors that are already DomainErrors pass through unchanged.
+func (t *errorTranslator) Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var existing DomainError
+	if errors.As(err, &existing) {
+		return existing
+	}
+
+	for _, rule := range t.rules {
+		if errors.Is(err, rule.target) {
+			return rule.apply(err)
+		}
+	}
+
+	var invErr *repositories.InventoryError
+	if errors.As(err, &invErr) {
+		if rule, ok := inventoryErrorRules[invErr.Code]; ok {
+			return rule.apply(err)
+		}
+	}
+
+	var counterErr *repositories.CounterError
+	if errors.As(err, &counterErr) {
+		if rule, ok := counterErrorRules[counterErr.Code]; ok {
+			return rule.apply(err)
+		}
+	}
+
+	var repoErr repositories.RepositoryError
+	if errors.As(err, &repoErr) {
+		switch {
+		case repoErr.IsNotFound():
+			return NewDomainError(ErrorKindNotFound, ErrorCodeNotFound, "resource not found", err)
+		case repoErr.IsConflict():
+			return NewDomainError(ErrorKindConflict, ErrorCodeConflict, "resource was modified concurrently", err)
+		case repoErr.IsUnavailable():
+			return NewDomainError(ErrorKindUnavailable, ErrorCodeUnavailable, "dependency unavailable", err)
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewDomainError(ErrorKindUnavailable, ErrorCodeUnavailable, "dependency timed out", err)
+	}
+
+	return NewDomainError(ErrorKindInternal, ErrorCodeInternal, internalErrorMessage, err)
+}
+
+func (r sentinelRule) apply(err error) DomainError {
+	message := r.message
+	var detailErr *DetailError
+	if r.exposeDetail && errors.As(err, &detailErr) && detailErr.Detail != "" && errors.Is(detailErr.Err, r.target) {
+		message = detailErr.Error()
+	}
+	return NewDomainError(r.kind, r.code, message, err)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+func TestErrorTranslatorMapsSentinels(t *testing.T) {
+	translator := NewErrorTranslator()
+
+	cases := []struct {
+		name        string
+		err         error
+		kind        ErrorKind
+		code        string
+		safeMessage string
+	}{
+		{
+			name:        "order invalid state exposes detail",
+			err:         detailf(ErrOrderInvalidState, "cannot cancel shipped order"),
+			kind:        ErrorKindInvalidState,
+			code:        "order_invalid_state",
+			safeMessage: "order: invalid status transition: cannot cancel shipped order",
+		},
+		{
+			name:        "wrapped repository text stays hidden",
+			err:         fmt.Errorf("%w: %v", ErrFXRateUnavailable, repoError{message: "projects/p/databases/(default)/documents/fxRateTables: deadline"}),
+			kind:        ErrorKindUnavailable,
+			code:        "fx_rate_unavailable",
+			safeMessage: "exchange rate is unavailable",
+		},
+		{
+			name: "insufficient stock",
+			err:  fmt.Errorf("%w: sku SKU-1", ErrInventoryInsufficientStock),
+			kind: ErrorKindConflict,
+			code: "insufficient_stock",
+		},
+		{
+			name:        "review not found hides detail",
+			err:         fmt.Errorf("%w: rev_1", ErrReviewNotFound),
+			kind:        ErrorKindNotFound,
+			code:        "review_not_found",
+			safeMessage: "review not found",
+		},
+		{
+			name: "review conflict",
+			err:  ErrReviewConflict,
+			kind: ErrorKindConflict,
+			code: "review_conflict",
+		},
+		{
+			name:        "inventory repository code",
+			err:         repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, "stock SKU-9 missing", nil),
+			kind:        ErrorKindNotFound,
+			code:        "stock_not_found",
+			safeMessage: "stock not found",
+		},
+		{
+			name: "counter repository code",
+			err:  &repositories.CounterError{Code: repositories.CounterErrorExhausted, Message: "exhausted"},
+			kind: ErrorKindConflict,
+			code: "counter_exhausted",
+		},
+		{
+			name: "repository unavailable",
+			err:  repoError{message: "firestore down", unavail: true},
+			kind: ErrorKindUnavailable,
+			code: ErrorCodeUnavailable,
+		},
+		{
+			name: "deadline exceeded",
+			err:  fmt.Errorf("query: %w", context.DeadlineExceeded),
+			kind: ErrorKindUnavailable,
+			code: ErrorCodeUnavailable,
+		},
+		{
+			name:        "unknown error is internal",
+			err:         errors.New("secret connection string leaked"),
+			kind:        ErrorKindInternal,
+			code:        ErrorCodeInternal,
+			safeMessage: "an internal error occurred",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			translated := translator.Translate(tc.err)
+			var domainErr DomainError
+			if !errors.As(translated, &domainErr) {
+				t.Fatalf("expected domain error, got %T", translated)
+			}
+			if domainErr.Code() != tc.code {
+				t.Fatalf("expected code %q got %q", tc.code, domainErr.Code())
+			}
+			if kind := ErrorKindOf(translated); kind != tc.kind {
+				t.Fatalf("expected kind %q got %q", tc.kind, kind)
+			}
+			if tc.safeMessage != "" && domainErr.SafeMessage() != tc.safeMessage {
+				t.Fatalf("expected safe message %q got %q", tc.safeMessage, domainErr.SafeMessage())
+			}
+			if !errors.Is(translated, tc.err) {
+				t.Fatalf("expected translated error to wrap original")
+			}
+		})
+	}
+}
+
+func TestErrorTranslatorPassesThroughDomainErrors(t *testing.T) {
+	translator := NewErrorTranslator()
+	original := NewDomainError(ErrorKindConflict, "custom_conflict", "custom", nil)
+
+	if got := translator.Translate(fmt.Errorf("wrapped: %w", original)); got != original {
+		t.Fatalf("expected domain error to pass through, got %v", got)
+	}
+	if translator.Translate(nil) != nil {
+		t.Fatalf("expected nil translation for nil error")
+	}
+}
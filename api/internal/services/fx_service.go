@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+// FX rounding modes applied to converted amounts.
+const (
+	FXRoundingHalfUp  = "half_up"
+	FXRoundingFloor   = "floor"
+	FXRoundingCeiling = "ceiling"
+)
+
+const defaultFXMaxAge = 48 * time.Hour
+
+var (
+	// ErrFXInvalidInput signals malformed rate tables or conversion arguments.
+	ErrFXInvalidInput = errors.New("fx: invalid input")
+	// ErrFXRateUnavailable indicates no published table covers the requested currencies.
+	ErrFXRateUnavailable = errors.New("fx: rate unavailable")
+	// ErrFXRatesStale indicates the newest table is older than the configured maximum age.
+	ErrFXRatesStale = errors.New("fx: rates are stale")
+)
+
+// zeroDecimalCurrencies lists currencies whose minor unit is the major unit.
+var zeroDecimalCurrencies = map[string]struct{}{
+	"JPY": {}, "KRW": {}, "VND": {}, "CLP": {}, "ISK": {}, "UGX": {}, "XAF": {}, "XOF": {},
+}
+
+// CurrencyRounding describes how converted amounts are rounded in a currency. Decimals is the number
+// of minor-unit digits (0 for JPY) and Increment rounds to a multiple of minor units, e.g. 10 for
+// prices ending in zero.
+type CurrencyRounding struct {
+	Decimals  int
+	Mode      string
+	Increment int64
+}
+
+// FXServiceDeps bundles the collaborators required to construct the FX service.
+type FXServiceDeps struct {
+	Rates repositories.FXRateRepository
+	// BaseCurrency is the store currency every table is quoted against. Defaults to JPY.
+	BaseCurrency string
+	// Rounding overrides the default rounding per currency. Unlisted currencies round half up to
+	// two decimals, or zero for currencies such as JPY.
+	Rounding map[string]CurrencyRounding
+	// MaxAge rejects conversions when the effective table is older. Defaults to 48 hours.
+	MaxAge      time.Duration
+	Clock       func() time.Time
+	IDGenerator func() string
+	Logger      func(ctx context.Context, event string, fields map[string]any)
+}
+
+type fxService struct {
+	rates    repositories.FXRateRepository
+	base     string
+	rounding map[string]CurrencyRounding
+	maxAge   time.Duration
+	clock    func() time.Time
+	newID    func() string
+	logger   func(context.Context, string, map[string]any)
+}
+
+// NewFXService wires dependencies into an FXService backed by published rate tables.
+func NewFXService(deps FXServiceDeps) (FXService, error) {
+	if deps.Rates == nil {
+		return nil, errors.New("fx service: rate repository is required")
+	}
+	base := strings.ToUpper(strings.TrimSpace(deps.BaseCurrency))
+	if base == "" {
+		base = "JPY"
+	}
+	rounding := make(map[string]CurrencyRounding, len(deps.Rounding))
+	for code, rule := range deps.Rounding {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if rule.Decimals < 0 || rule.Decimals > 4 {
+			return nil, fmt.Errorf("fx service: currency %s decimals must be between 0 and 4", code)
+		}
+		if rule.Increment < 0 {
+			return nil, fmt.Errorf("fx service: currency %s increment cannot be negative", code)
+		}
+		if rule.Increment == 0 {
+			rule.Increment = 1
+		}
+		if rule.Mode == "" {
+			rule.Mode = FXRoundingHalfUp
+		}
+		if !isKnownFXRounding(rule.Mode) {
+			return nil, fmt.Errorf("fx service: currency %s has unknown rounding mode %q", code, rule.Mode)
+		}
+		rounding[code] = rule
+	}
+	maxAge := deps.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultFXMaxAge
+	}
+	clock := deps.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	idGen := deps.IDGenerator
+	if idGen == nil {
+		idGen = func() string {
+			return ulid.Make().String()
+		}
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+
+	return &fxService{
+		rates:    deps.Rates,
+		base:     base,
+		rounding: rounding,
+		maxAge:   maxAge,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		newID:  idGen,
+		logger: logger,
+	}, nil
+}
+
+// PublishRates stores a new immutable table. Publishing again for the same day creates a new
+// version that supersedes the earlier one from its EffectiveAt onwards.
+func (s *fxService) PublishRates(ctx context.Context, cmd PublishFXRatesCommand) (FXRateTable, error) {
+	if len(cmd.Rates) == 0 {
+		return FXRateTable{}, detailf(ErrFXInvalidInput, "at least one rate is required")
+	}
+	rates := make(map[string]float64, len(cmd.Rates))
+	for code, rate := range cmd.Rates {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) != 3 {
+			return FXRateTable{}, detailf(ErrFXInvalidInput, "currency code %q must have three letters", code)
+		}
+		if code == s.base {
+			continue
+		}
+		if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+			return FXRateTable{}, detailf(ErrFXInvalidInput, "rate for %s must be positive", code)
+		}
+		rates[code] = rate
+	}
+
+	now := s.clock()
+	effectiveAt := now
+	if cmd.EffectiveAt != nil && !cmd.EffectiveAt.IsZero() {
+		effectiveAt = cmd.EffectiveAt.UTC()
+	}
+	table := FXRateTable{
+		BaseCurrency:  s.base,
+		Rates:         rates,
+		Source:        strings.TrimSpace(cmd.Source),
+		EffectiveDate: effectiveAt.Format(time.DateOnly),
+		EffectiveAt:   effectiveAt,
+		CreatedBy:     strings.TrimSpace(cmd.ActorID),
+		CreatedAt:     now,
+	}
+	table.ID = fmt.Sprintf("%s_%s_%s", strings.ToLower(s.base), strings.ReplaceAll(table.EffectiveDate, "-", ""), s.newID())
+
+	if err := s.rates.Insert(ctx, domain.FXRateTable(table)); err != nil {
+		return FXRateTable{}, s.mapRepositoryError(err)
+	}
+	s.logger(ctx, "fx_rates_published", map[string]any{
+		"tableId":       table.ID,
+		"baseCurrency":  table.BaseCurrency,
+		"effectiveDate": table.EffectiveDate,
+		"currencies":    len(table.Rates),
+	})
+	return table, nil
+}
+
+// CurrentRates returns the table in effect now, rejecting tables older than the maximum age.
+func (s *fxService) CurrentRates(ctx context.Context) (FXRateTable, error) {
+	now := s.clock()
+	table, err := s.rates.FindEffective(ctx, s.base, now)
+	if err != nil {
+		return FXRateTable{}, s.mapRepositoryError(err)
+	}
+	if age := now.Sub(table.EffectiveAt); age > s.maxAge {
+		return FXRateTable{}, detailf(ErrFXRatesStale, "table %s is %s old", table.ID, age.Truncate(time.Minute))
+	}
+	return table, nil
+}
+
+// Convert converts an amount in minor units of from into minor units of to using the current table.
+// Cross rates go through the base currency. The returned conversion can be replayed with
+// ConvertWithRecordedRate.
+func (s *fxService) Convert(ctx context.Context, amount int64, from, to string) (int64, FXConversion, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+	if from == "" || to == "" {
+		return 0, FXConversion{}, detailf(ErrFXInvalidInput, "source and target currencies are required")
+	}
+	if amount < 0 {
+		return 0, FXConversion{}, detailf(ErrFXInvalidInput, "amount cannot be negative")
+	}
+	if from == to {
+		return amount, FXConversion{}, nil
+	}
+
+	table, err := s.CurrentRates(ctx)
+	if err != nil {
+		return 0, FXConversion{}, err
+	}
+	fromRate, ok := s.rateFor(table, from)
+	if !ok {
+		return 0, FXConversion{}, detailf(ErrFXRateUnavailable, "table %s has no rate for %s", table.ID, from)
+	}
+	toRate, ok := s.rateFor(table, to)
+	if !ok {
+		return 0, FXConversion{}, detailf(ErrFXRateUnavailable, "table %s has no rate for %s", table.ID, to)
+	}
+
+	fromRounding := s.roundingFor(from)
+	toRounding := s.roundingFor(to)
+	conv := FXConversion{
+		TableID:      table.ID,
+		From:         from,
+		To:           to,
+		Rate:         toRate / fromRate,
+		FromDecimals: fromRounding.Decimals,
+		ToDecimals:   toRounding.Decimals,
+		Rounding:     toRounding.Mode,
+		Increment:    toRounding.Increment,
+		ConvertedAt:  s.clock(),
+	}
+	converted, err := ConvertWithRecordedRate(conv, amount)
+	if err != nil {
+		return 0, FXConversion{}, err
+	}
+	return converted, conv, nil
+}
+
+func (s *fxService) rateFor(table FXRateTable, currency string) (float64, bool) {
+	if currency == table.BaseCurrency {
+		return 1, true
+	}
+	rate, ok := table.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+func (s *fxService) roundingFor(currency string) CurrencyRounding {
+	if rule, ok := s.rounding[currency]; ok {
+		return rule
+	}
+	return defaultCurrencyRounding(currency)
+}
+
+func (s *fxService) mapRepositoryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var repoErr repositories.RepositoryError
+	if errors.As(err, &repoErr) {
+		switch {
+		case repoErr.IsNotFound():
+			return fmt.Errorf("%w: %v", ErrFXRateUnavailable, err)
+		case repoErr.IsConflict():
+			return fmt.Errorf("%w: %v", ErrFXInvalidInput, err)
+		case repoErr.IsUnavailable():
+			return fmt.Errorf("fx: repository unavailable: %w", err)
+		}
+	}
+	return err
+}
+
+func defaultCurrencyRounding(currency string) CurrencyRounding {
+	decimals := 2
+	if _, ok := zeroDecimalCurrencies[strings.ToUpper(currency)]; ok {
+		decimals = 0
+	}
+	return CurrencyRounding{Decimals: decimals, Mode: FXRoundingHalfUp, Increment: 1}
+}
+
+// ConvertWithRecordedRate replays a recorded conversion on a new amount in conv.From, so later
+// operations such as refunds use the rate the order was priced at.
+func ConvertWithRecordedRate(conv FXConversion, amount int64) (int64, error) {
+	rate, err := fxRate(conv.Rate)
+	if err != nil {
+		return 0, err
+	}
+	return applyFXRate(rate, amount, conv.FromDecimals, conv.ToDecimals, conv.Rounding, conv.Increment)
+}
+
+// RevertWithRecordedRate converts an amount in conv.To back into conv.From at the recorded rate.
+// The result is rounded in whole minor units of conv.From.
+func RevertWithRecordedRate(conv FXConversion, amount int64) (int64, error) {
+	rate, err := fxRate(conv.Rate)
+	if err != nil {
+		return 0, err
+	}
+	return applyFXRate(rate.Inv(rate), amount, conv.ToDecimals, conv.FromDecimals, conv.Rounding, 1)
+}
+
+func fxRate(rate float64) (*big.Rat, error) {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		return nil, detailf(ErrFXInvalidInput, "conversion rate must be positive")
+	}
+	return new(big.Rat).SetFloat64(rate), nil
+}
+
+func applyFXRate(rate *big.Rat, amount int64, fromDecimals, toDecimals int, mode string, increment int64) (int64, error) {
+	if amount < 0 {
+		return 0, detailf(ErrFXInvalidInput, "amount cannot be negative")
+	}
+	if fromDecimals < 0 || toDecimals < 0 {
+		return 0, detailf(ErrFXInvalidInput, "currency decimals cannot be negative")
+	}
+	if increment <= 0 {
+		increment = 1
+	}
+	value := new(big.Rat).Mul(rate, new(big.Rat).SetInt64(amount))
+	value.Mul(value, new(big.Rat).SetFrac(pow10(toDecimals), pow10(fromDecimals)))
+	value.Quo(value, new(big.Rat).SetInt64(increment))
+
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() > 0 {
+		switch mode {
+		case FXRoundingCeiling:
+			quotient.Add(quotient, big.NewInt(1))
+		case FXRoundingFloor:
+		default:
+			if new(big.Int).Lsh(remainder, 1).Cmp(value.Denom()) >= 0 {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+	quotient.Mul(quotient, big.NewInt(increment))
+	if !quotient.IsInt64() {
+		return 0, detailf(ErrFXInvalidInput, "converted amount overflows")
+	}
+	return quotient.Int64(), nil
+}
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+func isKnownFXRounding(mode string) bool {
+	switch mode {
+	case FXRoundingHalfUp, FXRoundingFloor, FXRoundingCeiling:
+		return true
+	default:
+		return false
+	}
+}
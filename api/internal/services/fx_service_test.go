@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+)
+
+type memoryFXRateRepo struct {
+	tables []domain.FXRateTable
+}
+
+func (m *memoryFXRateRepo) Insert(_ context.Context, table domain.FXRateTable) error {
+	for _, existing := range m.tables {
+		if existing.ID == table.ID {
+			return repoError{message: "duplicate table", conflict: true}
+		}
+	}
+	m.tables = append(m.tables, table)
+	return nil
+}
+
+func (m *memoryFXRateRepo) FindByID(_ context.Context, tableID string) (domain.FXRateTable, error) {
+	for _, table := range m.tables {
+		if table.ID == tableID {
+			return table, nil
+		}
+	}
+	return domain.FXRateTable{}, repoError{message: "table not found", notFound: true}
+}
+
+func (m *memoryFXRateRepo) FindEffective(_ context.Context, baseCurrency string, asOf time.Time) (domain.FXRateTable, error) {
+	var found *domain.FXRateTable
+	for idx, table := range m.tables {
+		if table.BaseCurrency != baseCurrency || table.EffectiveAt.After(asOf) {
+			continue
+		}
+		if found == nil || table.EffectiveAt.After(found.EffectiveAt) {
+			found = &m.tables[idx]
+		}
+	}
+	if found == nil {
+		return domain.FXRateTable{}, repoError{message: "no effective table", notFound: true}
+	}
+	return *found, nil
+}
+
+func newTestFXService(t *testing.T, now *time.Time, rounding map[string]CurrencyRounding) (FXService, *memoryFXRateRepo) {
+	t.Helper()
+	repo := &memoryFXRateRepo{}
+	seq := 0
+	svc, err := NewFXService(FXServiceDeps{
+		Rates:        repo,
+		BaseCurrency: "jpy",
+		Rounding:     rounding,
+		Clock:        func() time.Time { return *now },
+		IDGenerator: func() string {
+			seq++
+			return "T" + strconv.Itoa(seq)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewFXService error: %v", err)
+	}
+	return svc, repo
+}
+
+func TestFXService_PublishAndConvert(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 3, 1, 0, 0, 0, time.UTC)
+	svc, repo := newTestFXService(t, &now, map[string]CurrencyRounding{"jpy": {Mode: FXRoundingCeiling, Increment: 10}})
+
+	if _, _, err := svc.Convert(ctx, 1000, "JPY", "USD"); !errors.Is(err, ErrFXRateUnavailable) {
+		t.Fatalf("expected unavailable before publishing, got %v", err)
+	}
+	first, err := svc.PublishRates(ctx, PublishFXRatesCommand{Rates: map[string]float64{"usd": 0.0066, "EUR": 0.006, "JPY": 1}, Source: "ecb", ActorID: "staff_1"})
+	if err != nil {
+		t.Fatalf("PublishRates error: %v", err)
+	}
+	now = now.Add(time.Hour)
+	second, err := svc.PublishRates(ctx, PublishFXRatesCommand{Rates: map[string]float64{"USD": 0.0065, "EUR": 0.006}, Source: "ecb"})
+	if err != nil {
+		t.Fatalf("PublishRates error: %v", err)
+	}
+	if first.ID == second.ID || second.EffectiveDate != "2025-03-03" || len(repo.tables) != 2 || len(first.Rates) != 2 {
+		t.Fatalf("expected two versions for the day, got %+v and %+v", first, second)
+	}
+
+	amount, conv, err := svc.Convert(ctx, 10000, "JPY", "USD")
+	if err != nil {
+		t.Fatalf("Convert error: %v", err)
+	}
+	if amount != 6500 || conv.TableID != second.ID || conv.FromDecimals != 0 || conv.ToDecimals != 2 {
+		t.Fatalf("expected 6500 cents from the newest table, got %d %+v", amount, conv)
+	}
+	// 19.99 USD is 3075.38 JPY, rounded up to the next 10 yen by the override.
+	if amount, _, err := svc.Convert(ctx, 1999, "USD", "JPY"); err != nil || amount != 3080 {
+		t.Fatalf("expected 3080 yen, got %d (%v)", amount, err)
+	}
+	// Cross rate through the base currency: 10.00 USD is 9.23 EUR.
+	if amount, _, err := svc.Convert(ctx, 1000, "USD", "EUR"); err != nil || amount != 923 {
+		t.Fatalf("expected 923 euro cents, got %d (%v)", amount, err)
+	}
+	if _, _, err := svc.Convert(ctx, 1000, "JPY", "GBP"); !errors.Is(err, ErrFXRateUnavailable) {
+		t.Fatalf("expected missing currency to be unavailable, got %v", err)
+	}
+	if _, err := svc.PublishRates(ctx, PublishFXRatesCommand{Rates: map[string]float64{"USD": -1}}); !errors.Is(err, ErrFXInvalidInput) {
+		t.Fatalf("expected negative rate to be rejected, got %v", err)
+	}
+
+	now = now.Add(72 * time.Hour)
+	if _, _, err := svc.Convert(ctx, 1000, "JPY", "USD"); !errors.Is(err, ErrFXRatesStale) {
+		t.Fatalf("expected stale rates, got %v", err)
+	}
+}
+
+func TestRecordedFXConversionReplays(t *testing.T) {
+	conv := FXConversion{From: "JPY", To: "USD", Rate: 0.0065, FromDecimals: 0, ToDecimals: 2, Rounding: FXRoundingHalfUp, Increment: 1}
+	if amount, err := ConvertWithRecordedRate(conv, 20000); err != nil || amount != 13000 {
+		t.Fatalf("expected 13000 cents, got %d (%v)", amount, err)
+	}
+	if amount, err := RevertWithRecordedRate(conv, 6500); err != nil || amount != 10000 {
+		t.Fatalf("expected 10000 yen, got %d (%v)", amount, err)
+	}
+	if _, err := ConvertWithRecordedRate(FXConversion{Rate: 0}, 100); !errors.Is(err, ErrFXInvalidInput) {
+		t.Fatalf("expected zero rate to be rejected, got %v", err)
+	}
+}
+
+func TestFXConversion_CartToOrderToRefund(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 3, 1, 0, 0, 0, time.UTC)
+	fx, _ := newTestFXService(t, &now, nil)
+	if _, err := fx.PublishRates(ctx, PublishFXRatesCommand{Rates: map[string]float64{"USD": 0.0065}}); err != nil {
+		t.Fatalf("PublishRates error: %v", err)
+	}
+
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{Promotion: &fakePromotionService{}, Currency: fx})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	cart := Cart{
+		ID:       "cart_1",
+		UserID:   "user_1",
+		Currency: "USD",
+		Items: []CartItem{
+			{ID: "a", SKU: "SEAL", Quantity: 2, UnitPrice: 10000, Currency: "JPY"},
+			{ID: "b", SKU: "CASE", Quantity: 1, UnitPrice: 1500, Currency: "USD"},
+		},
+	}
+	res, err := engine.Calculate(ctx, PriceCartCommand{Cart: cart})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	if res.Breakdown.Subtotal != 2*6500+1500 || len(res.ExchangeRates) != 1 {
+		t.Fatalf("expected converted subtotal, got %d %+v", res.Breakdown.Subtotal, res.ExchangeRates)
+	}
+	if meta := res.Breakdown.Items[0].Metadata; meta["originalUnitPrice"] != int64(10000) || meta["originalCurrency"] != "JPY" {
+		t.Fatalf("expected original price metadata, got %+v", meta)
+	}
+
+	// Rates move after checkout; the order and its refunds keep the recorded rate.
+	now = now.Add(time.Hour)
+	if _, err := fx.PublishRates(ctx, PublishFXRatesCommand{Rates: map[string]float64{"USD": 0.007}}); err != nil {
+		t.Fatalf("PublishRates error: %v", err)
+	}
+	var stored domain.Order
+	orders, err := NewOrderService(OrderServiceDeps{
+		Orders: &stubOrderRepo{
+			insertFn: func(_ context.Context, order domain.Order) error {
+				stored = order
+				return nil
+			},
+			findFn: func(context.Context, string) (domain.Order, error) { return stored, nil },
+		},
+		Counters:    &stubCounterRepo{nextFn: func(context.Context, string, int64) (int64, error) { return 1, nil }},
+		Clock:       func() time.Time { return now },
+		IDGenerator: func() string { return "FX" },
+	})
+	if err != nil {
+		t.Fatalf("new order service: %v", err)
+	}
+	order, err := orders.CreateFromCart(ctx, CreateOrderFromCartCommand{Cart: cart, ExchangeRates: res.ExchangeRates})
+	if err != nil {
+		t.Fatalf("create from cart: %v", err)
+	}
+	if order.Items[0].UnitPrice != 6500 || order.Items[0].Total != 13000 || len(order.ExchangeRates) != 1 {
+		t.Fatalf("expected order priced at the recorded rate, got %+v", order.Items[0])
+	}
+	if _, err := orders.CreateFromCart(ctx, CreateOrderFromCartCommand{Cart: cart}); !errors.Is(err, ErrOrderInvalidInput) {
+		t.Fatalf("expected foreign currency without a recorded rate to be rejected, got %v", err)
+	}
+
+	payment := domain.Payment{ID: "pay_1", OrderID: order.ID, Provider: "stripe", IntentID: "pi_1", Status: "succeeded", Amount: 14500, Currency: "USD"}
+	ledger := &memoryTransactionRepo{txs: []domain.PaymentTransaction{
+		{ID: "ptx_cap", PaymentID: "pay_1", OrderID: order.ID, Type: domain.PaymentTransactionCapture, Amount: 14500, OccurredAt: now},
+	}}
+	payments, err := NewPaymentService(PaymentServiceDeps{
+		Payments:     &memoryPaymentRepo{payments: []domain.Payment{payment}},
+		Transactions: ledger,
+		Provider:     &stubPaymentGateway{},
+		Orders:       orders,
+		Clock:        func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("new payment service: %v", err)
+	}
+	refund := int64(6500)
+	if _, err := payments.ManualRefund(ctx, PaymentManualRefundCommand{OrderID: order.ID, PaymentID: "pay_1", Amount: &refund, IdempotencyKey: "refund-1"}); err != nil {
+		t.Fatalf("ManualRefund error: %v", err)
+	}
+	tx := ledger.txs[len(ledger.txs)-1]
+	if tx.BaseAmount != 10000 || tx.BaseCurrency != "JPY" {
+		t.Fatalf("expected refund restated at the recorded rate, got %+v", tx)
+	}
+}
+
+func TestCartPricingEngine_ConvertsSurchargesAndShipping(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 3, 1, 0, 0, 0, time.UTC)
+	fx, _ := newTestFXService(t, &now, nil)
+	if _, err := fx.PublishRates(ctx, PublishFXRatesCommand{Rates: map[string]float64{"USD": 0.0065}}); err != nil {
+		t.Fatalf("PublishRates error: %v", err)
+	}
+	shipping, err := NewTableShippingEstimator(TableShippingEstimatorDeps{Path: writeShippingRateTable(t, t.TempDir(), "rates.yaml", `
+version: "2025-03"
+currency: JPY
+zones:
+  - name: kanto
+    prefectures: [Tokyo]
+  - name: north_america
+    countries: [US, ca]
+    estimate_days: {standard: 7}
+services:
+  - level: standard
+    carrier: japan_post
+    free_shipping_threshold: 50000
+    brackets:
+      - max_grams: 2000
+        rates: {kanto: 800, north_america: 3000}
+`)})
+	if err != nil {
+		t.Fatalf("NewTableShippingEstimator error: %v", err)
+	}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion:  &fakePromotionService{},
+		Currency:   fx,
+		Surcharges: newTestSurchargeResolver(t),
+		Shipping:   shipping,
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+
+	res, err := engine.Calculate(ctx, PriceCartCommand{Cart: Cart{
+		Currency: "USD",
+		Items: []CartItem{{
+			ID: "a", SKU: "SEAL", Quantity: 2, UnitPrice: 10000, Currency: "JPY",
+			WeightGrams: 300, RequiresShipping: true,
+			Customization: map[string]any{CustomizationKeyFont: "reisho"},
+		}},
+		ShippingAddress: &Address{Country: "US", PostalCode: "94105"},
+	}})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+
+	surcharges := res.Breakdown.Items[0].Surcharges
+	if len(surcharges) != 1 || surcharges[0].UnitAmount != 325 || surcharges[0].Amount != 650 || surcharges[0].Metadata["originalCurrency"] != "JPY" {
+		t.Fatalf("expected premium font surcharge converted to USD, got %+v", surcharges)
+	}
+	if res.Breakdown.Shipping != 1950 || len(res.Breakdown.ShippingDetails) != 1 {
+		t.Fatalf("expected international shipping converted to USD, got %d %+v", res.Breakdown.Shipping, res.Breakdown.ShippingDetails)
+	}
+	if detail := res.Breakdown.ShippingDetails[0]; detail.Currency != "USD" || detail.Amount != 1950 || detail.Metadata["originalAmount"] != int64(3000) || detail.Metadata["zone"] != "north_america" {
+		t.Fatalf("unexpected shipping detail %+v", detail)
+	}
+	if res.Breakdown.Total != 2*6500+650+1950 {
+		t.Fatalf("expected total in USD, got %d", res.Breakdown.Total)
+	}
+	if len(res.ExchangeRates) != 1 || res.ExchangeRates[0].From != "JPY" || res.ExchangeRates[0].To != "USD" {
+		t.Fatalf("expected a single recorded JPY to USD rate, got %+v", res.ExchangeRates)
+	}
+}
@@ -22,6 +22,8 @@ type (
 	PricingBreakdown          = domain.PricingBreakdown
 	ItemPricingBreakdown      = domain.ItemPricingBreakdown
 	DiscountBreakdown         = domain.DiscountBreakdown
+	SurchargeBreakdown        = domain.SurchargeBreakdown
+	PriceQuote                = domain.PriceQuote
 	TaxBreakdown              = domain.TaxBreakdown
 	ShippingBreakdown         = domain.ShippingBreakdown
 	CheckoutSession           = domain.CheckoutSession
@@ -37,11 +39,16 @@ type (
 	OrderFlags                = domain.OrderFlags
 	OrderAudit                = domain.OrderAudit
 	Payment                   = domain.Payment
+	PaymentTransaction        = domain.PaymentTransaction
+	PaymentDispute            = domain.PaymentDispute
+	DisputeEvidence           = domain.DisputeEvidence
+	DisputeStatus             = domain.DisputeStatus
 	Shipment                  = domain.Shipment
 	ShipmentEvent             = domain.ShipmentEvent
 	Review                    = domain.Review
 	ReviewReply               = domain.ReviewReply
 	ReviewStatus              = domain.ReviewStatus
+	ProductRatingSummary      = domain.ProductRatingSummary
 	Promotion                 = domain.Promotion
 	PromotionValidationResult = domain.PromotionValidationResult
 	RegistrabilityCheckResult = domain.RegistrabilityCheckResult
@@ -53,6 +60,11 @@ type (
 	InventorySnapshot         = domain.InventorySnapshot
 	InventoryStock            = domain.InventoryStock
 	InventoryStockEvent       = domain.InventoryStockEvent
+	InventoryAdjustmentType   = domain.InventoryAdjustmentType
+	InventoryLocation         = domain.InventoryLocation
+	StockSubscription         = domain.StockSubscription
+	StockSubscriptionStatus   = domain.StockSubscriptionStatus
+	StockSubscriptionTrigger  = domain.StockSubscriptionTrigger
 	ContentPage               = domain.ContentPage
 	ContentGuide              = domain.ContentGuide
 	TemplateSummary           = domain.TemplateSummary
@@ -73,6 +85,8 @@ type (
 	SignedAssetResponse       = domain.SignedAssetResponse
 	PromotionUsage            = domain.PromotionUsage
 	PaymentMethod             = domain.PaymentMethod
+	FXRateTable               = domain.FXRateTable
+	FXConversion              = domain.FXConversion
 )
 
 // DesignService orchestrates design lifecycle operations, coordinating repositories,
@@ -127,6 +141,28 @@ type PaymentService interface {
 	ListPayments(ctx context.Context, orderID string) ([]Payment, error)
 }
 
+// FXService publishes versioned exchange rate tables and converts amounts between currencies.
+type FXService interface {
+	PublishRates(ctx context.Context, cmd PublishFXRatesCommand) (FXRateTable, error)
+	CurrentRates(ctx context.Context) (FXRateTable, error)
+	Convert(ctx context.Context, amount int64, from, to string) (int64, FXConversion, error)
+}
+
+// PaymentReconciliationService compares stored payments with PSP records and optionally heals drift.
+type PaymentReconciliationService interface {
+	Reconcile(ctx context.Context, cmd ReconcilePaymentsCommand) (PaymentReconciliationReport, error)
+}
+
+// DisputeService tracks PSP chargebacks, their evidence deadlines, and evidence submission.
+type DisputeService interface {
+	IngestDispute(ctx context.Context, cmd IngestDisputeCommand) (PaymentDispute, error)
+	ListDisputes(ctx context.Context, filter DisputeListFilter) (domain.CursorPage[PaymentDispute], error)
+	GetDispute(ctx context.Context, disputeID string) (PaymentDispute, error)
+	AddEvidence(ctx context.Context, cmd AddDisputeEvidenceCommand) (PaymentDispute, error)
+	SubmitEvidence(ctx context.Context, cmd SubmitDisputeEvidenceCommand) (PaymentDispute, error)
+	RemindEvidenceDue(ctx context.Context, cmd DisputeReminderCommand) (DisputeReminderResult, error)
+}
+
 // ShipmentService orchestrates shipment creation, updates, and webhook ingestion.
 type ShipmentService interface {
 	CreateShipment(ctx context.Context, cmd CreateShipmentCommand) (Shipment, error)
@@ -140,8 +176,11 @@ type ReviewService interface {
 	Create(ctx context.Context, cmd CreateReviewCommand) (Review, error)
 	GetByOrder(ctx context.Context, cmd GetReviewByOrderCommand) (Review, error)
 	ListByUser(ctx context.Context, cmd ListUserReviewsCommand) (domain.CursorPage[Review], error)
+	ListForModeration(ctx context.Context, cmd ListReviewsForModerationCommand) (domain.CursorPage[Review], error)
 	Moderate(ctx context.Context, cmd ModerateReviewCommand) (Review, error)
 	StoreReply(ctx context.Context, cmd StoreReviewReplyCommand) (Review, error)
+	ListPublicByProduct(ctx context.Context, cmd ListProductReviewsCommand) (domain.CursorPage[Review], error)
+	GetProductRatings(ctx context.Context, productIDs []string) (map[string]ProductRatingSummary, error)
 }
 
 // CounterService coordinates sequence generation for formatted identifiers.
@@ -160,6 +199,7 @@ type PromotionService interface {
 	UpdatePromotion(ctx context.Context, cmd UpsertPromotionCommand) (Promotion, error)
 	DeletePromotion(ctx context.Context, promoID string) error
 	ListPromotionUsage(ctx context.Context, filter PromotionUsageFilter) (domain.CursorPage[PromotionUsage], error)
+	ApplyPromotionUsage(ctx context.Context, cmd ApplyPromotionUsageCommand) (PromotionUsage, error)
 }
 
 // UserService manages profile, address, payment method, and favorite surfaces.
@@ -173,6 +213,7 @@ type UserService interface {
 	UpsertAddress(ctx context.Context, cmd UpsertAddressCommand) (Address, error)
 	DeleteAddress(ctx context.Context, cmd DeleteAddressCommand) error
 	ListPaymentMethods(ctx context.Context, userID string) ([]PaymentMethod, error)
+	StartPaymentMethodSetup(ctx context.Context, cmd StartPaymentMethodSetupCommand) (PaymentMethodSetup, error)
 	AddPaymentMethod(ctx context.Context, cmd AddPaymentMethodCommand) (PaymentMethod, error)
 	RemovePaymentMethod(ctx context.Context, cmd RemovePaymentMethodCommand) error
 	ListFavorites(ctx context.Context, userID string, pager Pagination) (domain.CursorPage[FavoriteDesign], error)
@@ -180,6 +221,7 @@ type UserService interface {
 }
 
 // InventoryEventPublisher accepts inventory stock change notifications for downstream processing.
+// Events other than transfer legs carry the SKU aggregate balances after the change.
 type InventoryEventPublisher interface {
 	PublishInventoryEvent(ctx context.Context, event InventoryStockEvent) error
 }
@@ -189,7 +231,15 @@ type InventoryService interface {
 	ReserveStocks(ctx context.Context, cmd InventoryReserveCommand) (InventoryReservation, error)
 	CommitReservation(ctx context.Context, cmd InventoryCommitCommand) (InventoryReservation, error)
 	ReleaseReservation(ctx context.Context, cmd InventoryReleaseCommand) (InventoryReservation, error)
+	RestockReservation(ctx context.Context, cmd InventoryRestockCommand) ([]InventoryStockEvent, error)
 	ListLowStock(ctx context.Context, filter InventoryLowStockFilter) (domain.CursorPage[InventorySnapshot], error)
+	ReleaseExpiredReservations(ctx context.Context, cmd ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error)
+	AdjustStock(ctx context.Context, cmd InventoryAdjustCommand) (InventoryStockEvent, error)
+	ListStockEvents(ctx context.Context, filter InventoryStockEventFilter) (domain.CursorPage[InventoryStockEvent], error)
+	ListLocationStocks(ctx context.Context, sku string) ([]InventoryStock, error)
+	TransferStock(ctx context.Context, cmd InventoryTransferCommand) ([]InventoryStockEvent, error)
+	ListLocations(ctx context.Context) ([]InventoryLocation, error)
+	UpsertLocation(ctx context.Context, location InventoryLocation) (InventoryLocation, error)
 }
 
 // ContentService provides read/write access to CMS content for public and admin usage.
@@ -252,6 +302,23 @@ type BackgroundJobDispatcher interface {
 	EnqueueStockCleanup(ctx context.Context, payload StockCleanupPayload) error
 }
 
+// StockSubscriptionService manages back-in-stock subscriptions and notifies subscribers when stock
+// returns. It satisfies InventoryEventPublisher so the inventory service can feed it stock events.
+type StockSubscriptionService interface {
+	InventoryEventPublisher
+	Subscribe(ctx context.Context, cmd SubscribeStockCommand) (StockSubscription, error)
+	Unsubscribe(ctx context.Context, cmd UnsubscribeStockCommand) error
+	ListSubscriptions(ctx context.Context, cmd ListStockSubscriptionsCommand) (domain.CursorPage[StockSubscription], error)
+	HandleStockEvent(ctx context.Context, event InventoryStockEvent) (BackInStockResult, error)
+	ExpireSubscriptions(ctx context.Context, cmd ExpireStockSubscriptionsCommand) (ExpireStockSubscriptionsResult, error)
+}
+
+// ReservationSweeper releases stale stock reservations and cancels the draft orders that held them.
+type ReservationSweeper interface {
+	SweepExpired(ctx context.Context, cmd ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error)
+	ReleaseReservations(ctx context.Context, cmd ReleaseReservationsCommand) (ReleaseExpiredReservationsResult, error)
+}
+
 // ErrorTranslator converts repository or platform errors into domain-aware sentinel errors.
 type ErrorTranslator interface {
 	Translate(err error) error
@@ -356,6 +423,9 @@ type CreateCheckoutSessionCommand struct {
 	CancelURL  string
 	PSP        string
 	Metadata   map[string]string
+	// QuoteToken is the signed quote from the cart estimate and is required. Checkout verifies it with
+	// CartPricingEngine.VerifyQuote and fails with price_changed when the cart reprices differently.
+	QuoteToken string
 }
 
 type ConfirmCheckoutCommand struct {
@@ -379,6 +449,17 @@ type CreateOrderFromCartCommand struct {
 	OrderNumber    *string
 	Metadata       map[string]any
 	ExpectedStatus *OrderStatus
+	// ExchangeRates are the conversions the cart was priced with; items in another currency are
+	// converted at these rates and the rates are kept on the order.
+	ExchangeRates []FXConversion
+}
+
+// PublishFXRatesCommand publishes rates in major units per one major unit of the base currency.
+type PublishFXRatesCommand struct {
+	Rates       map[string]float64
+	Source      string
+	EffectiveAt *time.Time
+	ActorID     string
 }
 
 type OrderStatusTransitionCommand struct {
@@ -390,13 +471,16 @@ type OrderStatusTransitionCommand struct {
 	Metadata       map[string]any
 }
 
+// CancelOrderCommand cancels an order. ReservationID names a reservation still held for the order and
+// is released; RestockReservationID names one already committed, whose lines are returned to stock.
 type CancelOrderCommand struct {
-	OrderID        string
-	ActorID        string
-	Reason         string
-	ReservationID  string
-	ExpectedStatus *OrderStatus
-	Metadata       map[string]any
+	OrderID              string
+	ActorID              string
+	Reason               string
+	ReservationID        string
+	RestockReservationID string
+	ExpectedStatus       *OrderStatus
+	Metadata             map[string]any
 }
 
 type RequestInvoiceCommand struct {
@@ -424,18 +508,162 @@ type PaymentWebhookCommand struct {
 	Headers  map[string]string
 }
 
+// PaymentManualCaptureCommand captures the remaining authorised amount. IdempotencyKey is required;
+// retrying with the same key returns the capture already recorded.
 type PaymentManualCaptureCommand struct {
-	OrderID   string
-	PaymentID string
-	ActorID   string
+	OrderID        string
+	PaymentID      string
+	ActorID        string
+	IdempotencyKey string
 }
 
+// PaymentManualRefundCommand refunds Amount, or the whole refundable balance when nil. IdempotencyKey
+// is required; retrying with the same key returns the refund already recorded.
 type PaymentManualRefundCommand struct {
-	OrderID   string
-	PaymentID string
+	OrderID        string
+	PaymentID      string
+	ActorID        string
+	Amount         *int64
+	Reason         string
+	IdempotencyKey string
+}
+
+// ReconcilePaymentsCommand selects the payments created within [From, To) to compare against the PSP.
+// AutoHeal appends corrective ledger entries for drifted payments and writes an audit entry per healed payment.
+type ReconcilePaymentsCommand struct {
+	From      time.Time
+	To        time.Time
+	Provider  string
+	AutoHeal  bool
 	ActorID   string
-	Amount    *int64
-	Reason    string
+	BatchSize int
+	Limit     int
+}
+
+// PaymentReconciliationReport summarises a reconciliation run.
+type PaymentReconciliationReport struct {
+	From          time.Time
+	To            time.Time
+	CheckedCount  int
+	MatchedCount  int
+	HealedCount   int
+	Discrepancies []PaymentDiscrepancy
+	FailedIDs     []string
+	HasMore       bool
+	GeneratedAt   time.Time
+}
+
+// PaymentDiscrepancy lists the fields where a stored payment disagrees with the PSP.
+type PaymentDiscrepancy struct {
+	PaymentID  string
+	OrderID    string
+	Provider   string
+	IntentID   string
+	Mismatches []PaymentFieldMismatch
+	Healed     bool
+}
+
+// PaymentFieldMismatch records the stored and PSP values of a single field.
+type PaymentFieldMismatch struct {
+	Field    string
+	Recorded any
+	Provider any
+}
+
+// IngestDisputeCommand carries a PSP dispute notification. Repeated notifications for the same
+// PSP dispute update the existing record.
+type IngestDisputeCommand struct {
+	Provider      string
+	PSPDisputeID  string
+	IntentID      string
+	Status        DisputeStatus
+	Reason        string
+	Amount        int64
+	Currency      string
+	EvidenceDueBy *time.Time
+	EventID       string
+}
+
+// DisputeListFilter selects disputes for the staff queue.
+type DisputeListFilter struct {
+	Status     []DisputeStatus
+	OrderID    string
+	DueBefore  *time.Time
+	Pagination Pagination
+}
+
+// AddDisputeEvidenceCommand attaches an uploaded asset to a dispute awaiting a response.
+type AddDisputeEvidenceCommand struct {
+	DisputeID   string
+	AssetID     string
+	Kind        string
+	FileName    string
+	ContentType string
+	ActorID     string
+}
+
+// SubmitDisputeEvidenceCommand sends the attached evidence to the PSP. Submission is final.
+type SubmitDisputeEvidenceCommand struct {
+	DisputeID   string
+	Explanation string
+	ActorID     string
+}
+
+// DisputeReminderCommand selects open disputes whose evidence is due before Now+Window.
+type DisputeReminderCommand struct {
+	Now    time.Time
+	Window time.Duration
+	Limit  int
+}
+
+// DisputeReminderResult reports the disputes staff were reminded about.
+type DisputeReminderResult struct {
+	Checked  int
+	Reminded []string
+}
+
+// SubscribeStockCommand subscribes a user to a SKU, or to every SKU of ProductRef when SKU is
+// empty. Subscribing again to the same target refreshes the existing subscription. TTL defaults to
+// the service's subscription lifetime when zero.
+type SubscribeStockCommand struct {
+	UserID     string
+	SKU        string
+	ProductRef string
+	Trigger    StockSubscriptionTrigger
+	TTL        time.Duration
+}
+
+// UnsubscribeStockCommand cancels one of the user's subscriptions.
+type UnsubscribeStockCommand struct {
+	UserID         string
+	SubscriptionID string
+}
+
+// ListStockSubscriptionsCommand pages through a user's subscriptions, newest first.
+type ListStockSubscriptionsCommand struct {
+	UserID     string
+	Status     []StockSubscriptionStatus
+	Pagination Pagination
+}
+
+// BackInStockResult reports the subscriptions a stock event fired.
+type BackInStockResult struct {
+	Matched  int
+	Notified []string
+	Expired  []string
+	Failed   []string
+}
+
+// ExpireStockSubscriptionsCommand expires active subscriptions whose ExpiresAt is before Now.
+type ExpireStockSubscriptionsCommand struct {
+	Now   time.Time
+	Limit int
+}
+
+// ExpireStockSubscriptionsResult reports the subscriptions that were expired.
+type ExpireStockSubscriptionsResult struct {
+	Checked int
+	Expired []string
 }
 
 type CreateShipmentCommand struct {
@@ -478,6 +706,16 @@ type ListUserReviewsCommand struct {
 	Pagination Pagination
 }
 
+type ListReviewsForModerationCommand struct {
+	Status     []ReviewStatus
+	Pagination Pagination
+}
+
+type ListProductReviewsCommand struct {
+	ProductID  string
+	Pagination Pagination
+}
+
 type GetReviewByOrderCommand struct {
 	OrderID    string
 	ActorID    string
@@ -519,6 +757,14 @@ type PromotionUsageFilter struct {
 	Pagination  Pagination
 }
 
+// ApplyPromotionUsageCommand atomically records promotion usage for a user once checkout completes.
+type ApplyPromotionUsageCommand struct {
+	PromotionID string
+	UserID      string
+	OrderID     string
+	ActorID     string
+}
+
 type UpdateProfileCommand struct {
 	UserID            string
 	ActorID           string
@@ -557,6 +803,21 @@ type DeleteAddressCommand struct {
 	AddressID string
 }
 
+type StartPaymentMethodSetupCommand struct {
+	UserID   string
+	Provider string
+}
+
+// PaymentMethodSetup carries the PSP session the client completes to collect a card for reuse.
+type PaymentMethodSetup struct {
+	Provider     string
+	SessionID    string
+	ClientSecret string
+	CustomerRef  string
+}
+
+// AddPaymentMethodCommand saves a payment method collected by a setup session. Token carries the PSP
+// payment method ID; Reference is accepted as a fallback for clients that send it there.
 type AddPaymentMethodCommand struct {
 	UserID    string
 	Provider  string
@@ -575,13 +836,16 @@ type ToggleFavoriteCommand struct {
 	Mark     bool
 }
 
+// InventoryReserveCommand reserves stock for an order. ShippingPrefecture steers the choice of stock
+// location for SKUs held at more than one location.
 type InventoryReserveCommand struct {
-	OrderID        string
-	UserID         string
-	Lines          []InventoryLine
-	TTL            time.Duration
-	Reason         string
-	IdempotencyKey string
+	OrderID            string
+	UserID             string
+	Lines              []InventoryLine
+	TTL                time.Duration
+	Reason             string
+	IdempotencyKey     string
+	ShippingPrefecture string
 }
 
 type InventoryCommitCommand struct {
@@ -596,17 +860,89 @@ type InventoryReleaseCommand struct {
 	ActorID       string
 }
 
+// InventoryRestockCommand returns a committed reservation's stock, for orders canceled after their
+// reservation was committed. OrderID, when set, must match the reservation's order.
+type InventoryRestockCommand struct {
+	ReservationID string
+	OrderID       string
+	Reason        string
+	ActorID       string
+}
+
 type InventoryLine struct {
 	ProductID string
 	SKU       string
 	Quantity  int
 }
 
+// InventoryLowStockFilter lists low stock for one location when LocationID is set and across all
+// locations otherwise.
 type InventoryLowStockFilter struct {
 	Threshold  int
+	LocationID string
 	Pagination Pagination
 }
 
+// InventoryAdjustCommand records a stock adjustment for one SKU. Quantity is the received or damaged
+// amount for receipts and damage, the counted on-hand for cycle counts, and a signed delta for
+// manual corrections. ProductID is only needed when a receipt creates the stock record. LocationID
+// applies the adjustment to one location; cycle counts then count that location only.
+type InventoryAdjustCommand struct {
+	SKU        string
+	ProductID  string
+	LocationID string
+	Type       InventoryAdjustmentType
+	Quantity   int
+	Reason     string
+	ActorID    string
+	Metadata   map[string]any
+}
+
+// InventoryTransferCommand moves on-hand stock of one SKU from one location to another.
+type InventoryTransferCommand struct {
+	SKU            string
+	FromLocationID string
+	ToLocationID   string
+	Quantity       int
+	Reason         string
+	ActorID        string
+	Metadata       map[string]any
+}
+
+// InventoryStockEventFilter pages through the stock ledger of one SKU, newest first.
+type InventoryStockEventFilter struct {
+	SKU        string
+	Pagination Pagination
+}
+
+// ReleaseExpiredReservationsCommand bounds a sweep over reservations whose TTL elapsed.
+type ReleaseExpiredReservationsCommand struct {
+	BatchSize int
+	Limit     int
+	Reason    string
+	ActorID   string
+}
+
+// ReleaseExpiredReservationsResult summarises the outcome of an expired reservation sweep.
+type ReleaseExpiredReservationsResult struct {
+	CheckedCount         int
+	ReleasedCount        int
+	SkippedCount         int
+	ReleasedIDs          []string
+	FailedIDs            []string
+	Released             []InventoryReservation
+	CanceledOrderIDs     []string
+	OrderCancelFailedIDs []string
+	HasMore              bool
+}
+
+// ReleaseReservationsCommand releases specific reservations, typically delivered by a stock cleanup job.
+type ReleaseReservationsCommand struct {
+	ReservationIDs []string
+	Reason         string
+	ActorID        string
+}
+
 type ContentGuideFilter struct {
 	Category       *string
 	Slug           *string
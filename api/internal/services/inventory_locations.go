@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+// Location distance ranks used when choosing where to reserve stock from.
+const (
+	locationDistanceSamePrefecture = iota
+	locationDistanceNearby
+	locationDistanceFar
+)
+
+func (s *inventoryService) ListLocations(ctx context.Context) ([]InventoryLocation, error) {
+	locations, err := s.repo.ListLocations(ctx)
+	if err != nil {
+		return nil, s.mapRepositoryError(err)
+	}
+	return locations, nil
+}
+
+func (s *inventoryService) UpsertLocation(ctx context.Context, location InventoryLocation) (InventoryLocation, error) {
+	location.ID = strings.TrimSpace(location.ID)
+	location.Name = strings.TrimSpace(location.Name)
+	location.Prefecture = strings.TrimSpace(location.Prefecture)
+	switch {
+	case location.ID == "":
+		return InventoryLocation{}, detailf(ErrInventoryInvalidInput, "location id is required")
+	case strings.ContainsAny(location.ID, "/@"):
+		return InventoryLocation{}, detailf(ErrInventoryInvalidInput, "location id must not contain '/' or '@'")
+	case location.Name == "":
+		return InventoryLocation{}, detailf(ErrInventoryInvalidInput, "location name is required")
+	case location.Prefecture == "":
+		return InventoryLocation{}, detailf(ErrInventoryInvalidInput, "location prefecture is required")
+	case location.Priority < 0:
+		return InventoryLocation{}, detailf(ErrInventoryInvalidInput, "location priority must not be negative")
+	}
+	location.UpdatedAt = s.now()
+
+	saved, err := s.repo.UpsertLocation(ctx, location)
+	if err != nil {
+		return InventoryLocation{}, s.mapRepositoryError(err)
+	}
+	return saved, nil
+}
+
+func (s *inventoryService) ListLocationStocks(ctx context.Context, sku string) ([]InventoryStock, error) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil, detailf(ErrInventoryInvalidInput, "sku is required")
+	}
+	stocks, err := s.repo.ListLocationStocks(ctx, sku)
+	if err != nil {
+		return nil, s.mapRepositoryError(err)
+	}
+	sort.Slice(stocks, func(i, j int) bool { return stocks[i].LocationID < stocks[j].LocationID })
+	return stocks, nil
+}
+
+func (s *inventoryService) TransferStock(ctx context.Context, cmd InventoryTransferCommand) ([]InventoryStockEvent, error) {
+	sku := strings.TrimSpace(cmd.SKU)
+	from := strings.TrimSpace(cmd.FromLocationID)
+	to := strings.TrimSpace(cmd.ToLocationID)
+	actor := strings.TrimSpace(cmd.ActorID)
+	switch {
+	case sku == "":
+		return nil, detailf(ErrInventoryInvalidInput, "sku is required")
+	case from == "" || to == "":
+		return nil, detailf(ErrInventoryInvalidInput, "source and destination locations are required")
+	case from == to:
+		return nil, detailf(ErrInventoryInvalidInput, "source and destination locations must differ")
+	case cmd.Quantity <= 0:
+		return nil, detailf(ErrInventoryInvalidInput, "transfer quantity must be positive")
+	case actor == "":
+		return nil, detailf(ErrInventoryInvalidInput, "actor id is required")
+	}
+	if _, err := s.getLocation(ctx, from); err != nil {
+		return nil, err
+	}
+	destination, err := s.getLocation(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if !destination.Active {
+		return nil, detailf(ErrInventoryInvalidInput, "location %s is inactive", to)
+	}
+
+	template := InventoryStockEvent{
+		ActorID: actor,
+		Reason:  strings.TrimSpace(cmd.Reason),
+	}
+	if len(cmd.Metadata) > 0 {
+		template.Metadata = make(map[string]any, len(cmd.Metadata))
+		for k, v := range cmd.Metadata {
+			template.Metadata[k] = v
+		}
+	}
+	outEvent := template
+	outEvent.ID = s.newStockEventID()
+	inEvent := template
+	inEvent.ID = s.newStockEventID()
+	// Each leg names the other location so either ledger entry can be traced back to the transfer.
+	outEvent.Metadata = withMetadata(template.Metadata, "toLocationId", to)
+	inEvent.Metadata = withMetadata(template.Metadata, "fromLocationId", from)
+
+	result, err := s.repo.Transfer(ctx, repositories.InventoryTransferRequest{
+		SKU:            sku,
+		FromLocationID: from,
+		ToLocationID:   to,
+		Quantity:       cmd.Quantity,
+		OutEvent:       outEvent,
+		InEvent:        inEvent,
+		Now:            s.now(),
+	})
+	if err != nil {
+		var invErr *repositories.InventoryError
+		if errors.As(err, &invErr) && invErr.Code == repositories.InventoryErrorStockNotFound {
+			return nil, detailf(ErrInventoryStockNotFound, "%s", invErr.Message)
+		}
+		return nil, s.mapRepositoryError(err)
+	}
+
+	if s.events != nil {
+		for _, event := range result.Events {
+			s.logEventFailure(ctx, s.events.PublishInventoryEvent(ctx, event))
+		}
+	}
+	return result.Events, nil
+}
+
+// allocateLocations assigns a stock location to each line whose SKU is stocked per location. A
+// candidate must be active and able to cover the whole line. Candidates are ranked by distance to
+// the shipping prefecture (the location's own prefecture, then one of its nearby prefectures, then
+// anywhere else), then by priority, then by the most available stock. Each line is checked against
+// what the earlier lines left at a location, not against the listed availability. SKUs without
+// per-location stock are reserved against the SKU aggregate only.
+func (s *inventoryService) allocateLocations(ctx context.Context, lines []InventoryReservationLine, shippingPrefecture string) error {
+	var locations map[string]InventoryLocation
+	remaining := make(map[string][]InventoryStock, len(lines))
+	for i := range lines {
+		stocks, listed := remaining[lines[i].SKU]
+		if !listed {
+			listedStocks, err := s.repo.ListLocationStocks(ctx, lines[i].SKU)
+			if err != nil {
+				return s.mapRepositoryError(err)
+			}
+			stocks = append([]InventoryStock(nil), listedStocks...)
+			remaining[lines[i].SKU] = stocks
+		}
+		if len(stocks) == 0 {
+			continue
+		}
+		if locations == nil {
+			list, err := s.repo.ListLocations(ctx)
+			if err != nil {
+				return s.mapRepositoryError(err)
+			}
+			locations = make(map[string]InventoryLocation, len(list))
+			for _, location := range list {
+				locations[location.ID] = location
+			}
+		}
+
+		locationID, ok := chooseStockLocation(stocks, locations, shippingPrefecture, lines[i].Quantity)
+		if !ok {
+			return detailf(ErrInventoryInsufficientStock, "no single location can fulfil %d of %s", lines[i].Quantity, lines[i].SKU)
+		}
+		lines[i].LocationID = locationID
+		for j := range stocks {
+			if stocks[j].LocationID == locationID {
+				stocks[j].Available -= lines[i].Quantity
+			}
+		}
+	}
+	return nil
+}
+
+func chooseStockLocation(stocks []InventoryStock, locations map[string]InventoryLocation, shippingPrefecture string, quantity int) (string, bool) {
+	type candidate struct {
+		id        string
+		distance  int
+		priority  int
+		available int
+	}
+	destination := normalizePrefecture(shippingPrefecture)
+	var candidates []candidate
+	for _, stock := range stocks {
+		location, ok := locations[stock.LocationID]
+		if !ok || !location.Active || stock.Available < quantity {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			id:        location.ID,
+			distance:  locationDistance(location, destination),
+			priority:  location.Priority,
+			available: stock.Available,
+		})
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		if a.available != b.available {
+			return a.available > b.available
+		}
+		return a.id < b.id
+	})
+	return candidates[0].id, true
+}
+
+func locationDistance(location InventoryLocation, destination string) int {
+	switch {
+	case destination == "":
+		return locationDistanceFar
+	case normalizePrefecture(location.Prefecture) == destination:
+		return locationDistanceSamePrefecture
+	case containsFold(location.NearbyPrefectures, destination):
+		return locationDistanceNearby
+	default:
+		return locationDistanceFar
+	}
+}
+
+func (s *inventoryService) getLocation(ctx context.Context, locationID string) (InventoryLocation, error) {
+	location, err := s.repo.GetLocation(ctx, locationID)
+	if err != nil {
+		return InventoryLocation{}, s.mapRepositoryError(err)
+	}
+	return location, nil
+}
+
+func (s *inventoryService) newStockEventID() string {
+	return stockEventIDPrefix + strings.TrimPrefix(strings.TrimSpace(s.newID()), stockEventIDPrefix)
+}
+
+func withMetadata(base map[string]any, key string, value any) map[string]any {
+	out := make(map[string]any, len(base)+1)
+	for k, v := range base {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
@@ -18,10 +18,19 @@ const (
 	eventInventoryReserve = "inventory.reserve"
 	eventInventoryCommit  = "inventory.commit"
 	eventInventoryRelease = "inventory.release"
+	eventInventoryRestock = "inventory.restock"
 
 	statusReserved  = "reserved"
 	statusCommitted = "committed"
 	statusReleased  = "released"
+	statusRestocked = "restocked"
+
+	defaultExpiredReleaseReason  = "expired"
+	defaultExpiredSweepBatchSize = 100
+	maxExpiredSweepBatchSize     = 500
+	defaultExpiredSweepLimit     = 1000
+
+	stockEventIDPrefix = "ise_"
 )
 
 var (
@@ -33,6 +42,10 @@ var (
 	ErrInventoryReservationNotFound = errors.New("inventory: reservation not found")
 	// ErrInventoryInvalidState indicates the reservation cannot transition due to its state.
 	ErrInventoryInvalidState = errors.New("inventory: reservation state invalid")
+	// ErrInventoryStockNotFound indicates an adjustment targeted a SKU without a stock record.
+	ErrInventoryStockNotFound = errors.New("inventory: stock not found")
+	// ErrInventoryLocationNotFound indicates the referenced stock location does not exist.
+	ErrInventoryLocationNotFound = errors.New("inventory: location not found")
 )
 
 // InventoryServiceDeps bundles the collaborators required to construct an inventory service.
@@ -96,6 +109,9 @@ func (s *inventoryService) ReserveStocks(ctx context.Context, cmd InventoryReser
 	if err != nil {
 		return InventoryReservation{}, err
 	}
+	if err := s.allocateLocations(ctx, lines, cmd.ShippingPrefecture); err != nil {
+		return InventoryReservation{}, err
+	}
 
 	reservation := InventoryReservation{
 		ID:             ensureReservationID(s.newID()),
@@ -146,7 +162,7 @@ func (s *inventoryService) ReserveStocks(ctx context.Context, cmd InventoryReser
 func (s *inventoryService) CommitReservation(ctx context.Context, cmd InventoryCommitCommand) (InventoryReservation, error) {
 	reservationID := strings.TrimSpace(cmd.ReservationID)
 	if reservationID == "" {
-		return InventoryReservation{}, fmt.Errorf("%w: reservation id is required", ErrInventoryInvalidInput)
+		return InventoryReservation{}, detailf(ErrInventoryInvalidInput, "reservation id is required")
 	}
 
 	now := s.now()
@@ -183,7 +199,7 @@ func (s *inventoryService) CommitReservation(ctx context.Context, cmd InventoryC
 func (s *inventoryService) ReleaseReservation(ctx context.Context, cmd InventoryReleaseCommand) (InventoryReservation, error) {
 	reservationID := strings.TrimSpace(cmd.ReservationID)
 	if reservationID == "" {
-		return InventoryReservation{}, fmt.Errorf("%w: reservation id is required", ErrInventoryInvalidInput)
+		return InventoryReservation{}, detailf(ErrInventoryInvalidInput, "reservation id is required")
 	}
 
 	now := s.now()
@@ -219,11 +235,87 @@ func (s *inventoryService) ReleaseReservation(ctx context.Context, cmd Inventory
 	return result.Reservation, nil
 }
 
+// RestockReservation returns the lines of a committed reservation to on-hand stock at the locations
+// they were committed from. The repository restocks every line and marks the reservation restocked
+// in one transaction, so restocking a reservation twice is a no-op that returns no events.
+func (s *inventoryService) RestockReservation(ctx context.Context, cmd InventoryRestockCommand) ([]InventoryStockEvent, error) {
+	reservationID := strings.TrimSpace(cmd.ReservationID)
+	if reservationID == "" {
+		return nil, detailf(ErrInventoryInvalidInput, "reservation id is required")
+	}
+	actor := strings.TrimSpace(cmd.ActorID)
+	if actor == "" {
+		return nil, detailf(ErrInventoryInvalidInput, "actor id is required")
+	}
+
+	reservation, err := s.repo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, s.mapRepositoryError(err)
+	}
+	if reservation.Status == statusRestocked {
+		return nil, nil
+	}
+	if reservation.Status != statusCommitted {
+		return nil, detailf(ErrInventoryInvalidState, "reservation %s is %s, not committed", reservationID, reservation.Status)
+	}
+	orderRef := ""
+	if orderID := strings.TrimSpace(cmd.OrderID); orderID != "" {
+		orderRef = ensureOrderRef(orderID)
+		if !strings.EqualFold(reservation.OrderRef, orderRef) {
+			return nil, detailf(ErrInventoryInvalidState, "reservation %s order mismatch", reservationID)
+		}
+	}
+
+	reason := strings.TrimSpace(cmd.Reason)
+	ledger := make([]InventoryStockEvent, 0, len(reservation.Lines))
+	for _, line := range reservation.Lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+		ledger = append(ledger, InventoryStockEvent{
+			ID:      s.newStockEventID(),
+			Type:    eventInventoryRestock,
+			ActorID: actor,
+			Reason:  reason,
+		})
+	}
+
+	result, err := s.repo.Restock(ctx, repositories.InventoryRestockRequest{
+		ReservationID: reservationID,
+		OrderRef:      orderRef,
+		Events:        ledger,
+		Now:           s.now(),
+	})
+	if err != nil {
+		return nil, s.mapRepositoryError(err)
+	}
+	if len(result.Events) == 0 {
+		return nil, nil
+	}
+
+	metadata := map[string]any{"actorId": actor}
+	if reason != "" {
+		metadata["reason"] = reason
+	}
+	deltas := make(map[string]stockDelta)
+	for _, line := range result.Reservation.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		delta := deltas[sku]
+		delta.OnHand += line.Quantity
+		deltas[sku] = delta
+	}
+
+	s.logEventFailure(ctx, s.emitStockEvents(ctx, eventInventoryRestock, result.Reservation, result.Stocks, deltas, metadata))
+
+	return result.Events, nil
+}
+
 func (s *inventoryService) ListLowStock(ctx context.Context, filter InventoryLowStockFilter) (domain.CursorPage[InventorySnapshot], error) {
 	req := repositories.InventoryLowStockQuery{
-		Threshold: filter.Threshold,
-		PageSize:  filter.Pagination.PageSize,
-		PageToken: filter.Pagination.PageToken,
+		Threshold:  filter.Threshold,
+		LocationID: strings.TrimSpace(filter.LocationID),
+		PageSize:   filter.Pagination.PageSize,
+		PageToken:  filter.Pagination.PageToken,
 	}
 
 	page, err := s.repo.ListLowStock(ctx, req)
@@ -236,6 +328,7 @@ func (s *inventoryService) ListLowStock(ctx context.Context, filter InventoryLow
 		snapshots[i] = InventorySnapshot{
 			SKU:         stock.SKU,
 			ProductRef:  stock.ProductRef,
+			LocationID:  stock.LocationID,
 			OnHand:      stock.OnHand,
 			Reserved:    stock.Reserved,
 			Available:   stock.Available,
@@ -251,22 +344,209 @@ func (s *inventoryService) ListLowStock(ctx context.Context, filter InventoryLow
 	}, nil
 }
 
+func (s *inventoryService) ReleaseExpiredReservations(ctx context.Context, cmd ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error) {
+	batchSize := cmd.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExpiredSweepBatchSize
+	}
+	if batchSize > maxExpiredSweepBatchSize {
+		batchSize = maxExpiredSweepBatchSize
+	}
+	limit := cmd.Limit
+	if limit <= 0 {
+		limit = defaultExpiredSweepLimit
+	}
+	reason := strings.TrimSpace(cmd.Reason)
+	if reason == "" {
+		reason = defaultExpiredReleaseReason
+	}
+
+	cutoff := s.now()
+	var result ReleaseExpiredReservationsResult
+	pageToken := ""
+	for result.CheckedCount < limit {
+		pageSize := batchSize
+		if remaining := limit - result.CheckedCount; remaining < pageSize {
+			pageSize = remaining
+		}
+
+		page, err := s.repo.ListExpiredReservations(ctx, repositories.InventoryExpiredReservationQuery{
+			Before:    cutoff,
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return result, s.mapRepositoryError(err)
+		}
+
+		for _, reservation := range page.Items {
+			result.CheckedCount++
+			released, err := s.ReleaseReservation(ctx, InventoryReleaseCommand{
+				ReservationID: reservation.ID,
+				Reason:        reason,
+				ActorID:       cmd.ActorID,
+			})
+			switch {
+			case err == nil:
+				result.ReleasedCount++
+				result.ReleasedIDs = append(result.ReleasedIDs, reservation.ID)
+				if released.ID == "" {
+					released = reservation
+				}
+				result.Released = append(result.Released, released)
+			case errors.Is(err, ErrInventoryInvalidState), errors.Is(err, ErrInventoryReservationNotFound):
+				// Committed or released concurrently; nothing left to restore.
+				result.SkippedCount++
+			default:
+				result.FailedIDs = append(result.FailedIDs, reservation.ID)
+				s.logger(ctx, "inventory_expired_release_failed", map[string]any{
+					"reservationId": reservation.ID,
+					"error":         err.Error(),
+				})
+			}
+		}
+
+		pageToken = strings.TrimSpace(page.NextPageToken)
+		if pageToken == "" || len(page.Items) == 0 {
+			return result, nil
+		}
+	}
+
+	result.HasMore = pageToken != ""
+	return result, nil
+}
+
+func (s *inventoryService) AdjustStock(ctx context.Context, cmd InventoryAdjustCommand) (InventoryStockEvent, error) {
+	sku := strings.TrimSpace(cmd.SKU)
+	if sku == "" {
+		return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "sku is required")
+	}
+	actor := strings.TrimSpace(cmd.ActorID)
+	if actor == "" {
+		return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "actor id is required")
+	}
+	reason := strings.TrimSpace(cmd.Reason)
+	locationID := strings.TrimSpace(cmd.LocationID)
+	if locationID != "" {
+		if _, err := s.getLocation(ctx, locationID); err != nil {
+			return InventoryStockEvent{}, err
+		}
+	}
+
+	req := repositories.InventoryAdjustRequest{Now: s.now()}
+	switch cmd.Type {
+	case domain.InventoryAdjustmentReceipt:
+		if cmd.Quantity <= 0 {
+			return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "receipt quantity must be positive")
+		}
+		req.DeltaOnHand = cmd.Quantity
+		req.CreateIfMissing = true
+	case domain.InventoryAdjustmentDamage:
+		if cmd.Quantity <= 0 {
+			return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "damaged quantity must be positive")
+		}
+		req.DeltaOnHand = -cmd.Quantity
+	case domain.InventoryAdjustmentCycleCount:
+		if cmd.Quantity < 0 {
+			return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "counted quantity must not be negative")
+		}
+		counted := cmd.Quantity
+		req.SetOnHand = &counted
+		// A physical count is authoritative even when it leaves reservations uncovered.
+		req.AllowBelowReserved = true
+	case domain.InventoryAdjustmentCorrection:
+		if cmd.Quantity == 0 {
+			return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "correction quantity must not be zero")
+		}
+		req.DeltaOnHand = cmd.Quantity
+	default:
+		return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "unsupported adjustment type %q", cmd.Type)
+	}
+	if reason == "" && (cmd.Type == domain.InventoryAdjustmentDamage || cmd.Type == domain.InventoryAdjustmentCorrection) {
+		return InventoryStockEvent{}, detailf(ErrInventoryInvalidInput, "reason is required for %s adjustments", cmd.Type)
+	}
+
+	req.Event = InventoryStockEvent{
+		ID:         s.newStockEventID(),
+		Type:       "inventory." + string(cmd.Type),
+		SKU:        sku,
+		LocationID: locationID,
+		ActorID:    actor,
+		Reason:     reason,
+	}
+	if productID := strings.TrimSpace(cmd.ProductID); productID != "" {
+		req.Event.ProductRef = fmt.Sprintf("/products/%s", productID)
+	}
+	if len(cmd.Metadata) > 0 {
+		req.Event.Metadata = make(map[string]any, len(cmd.Metadata))
+		for k, v := range cmd.Metadata {
+			req.Event.Metadata[k] = v
+		}
+	}
+
+	result, err := s.repo.Adjust(ctx, req)
+	if err != nil {
+		var invErr *repositories.InventoryError
+		if errors.As(err, &invErr) && invErr.Code == repositories.InventoryErrorStockNotFound {
+			return InventoryStockEvent{}, detailf(ErrInventoryStockNotFound, "%s", invErr.Message)
+		}
+		return InventoryStockEvent{}, s.mapRepositoryError(err)
+	}
+
+	uncovered := result.Stock
+	if result.Location != nil {
+		uncovered = *result.Location
+	}
+	if uncovered.Available < 0 {
+		s.logger(ctx, "inventory_reservations_uncovered", map[string]any{
+			"sku":        sku,
+			"locationId": uncovered.LocationID,
+			"onHand":     uncovered.OnHand,
+			"reserved":   uncovered.Reserved,
+			"eventId":    result.Event.ID,
+			"eventType":  result.Event.Type,
+		})
+	}
+	if s.events != nil {
+		s.logEventFailure(ctx, s.events.PublishInventoryEvent(ctx, aggregateStockEvent(result.Event, result.Stock)))
+	}
+
+	return result.Event, nil
+}
+
+func (s *inventoryService) ListStockEvents(ctx context.Context, filter InventoryStockEventFilter) (domain.CursorPage[InventoryStockEvent], error) {
+	sku := strings.TrimSpace(filter.SKU)
+	if sku == "" {
+		return domain.CursorPage[InventoryStockEvent]{}, detailf(ErrInventoryInvalidInput, "sku is required")
+	}
+
+	page, err := s.repo.ListStockEvents(ctx, repositories.InventoryStockEventQuery{
+		SKU:       sku,
+		PageSize:  filter.Pagination.PageSize,
+		PageToken: filter.Pagination.PageToken,
+	})
+	if err != nil {
+		return domain.CursorPage[InventoryStockEvent]{}, s.mapRepositoryError(err)
+	}
+	return page, nil
+}
+
 func (s *inventoryService) now() time.Time {
 	return s.clock()
 }
 
 func (s *inventoryService) validateReserveInput(cmd InventoryReserveCommand) error {
 	if strings.TrimSpace(cmd.OrderID) == "" {
-		return fmt.Errorf("%w: order id is required", ErrInventoryInvalidInput)
+		return detailf(ErrInventoryInvalidInput, "order id is required")
 	}
 	if strings.TrimSpace(cmd.UserID) == "" {
-		return fmt.Errorf("%w: user id is required", ErrInventoryInvalidInput)
+		return detailf(ErrInventoryInvalidInput, "user id is required")
 	}
 	if len(cmd.Lines) == 0 {
-		return fmt.Errorf("%w: at least one line is required", ErrInventoryInvalidInput)
+		return detailf(ErrInventoryInvalidInput, "at least one line is required")
 	}
 	if cmd.TTL <= 0 {
-		return fmt.Errorf("%w: ttl must be positive", ErrInventoryInvalidInput)
+		return detailf(ErrInventoryInvalidInput, "ttl must be positive")
 	}
 	return nil
 }
@@ -280,13 +560,19 @@ func (s *inventoryService) mapRepositoryError(err error) error {
 	if errors.As(err, &invErr) {
 		switch invErr.Code {
 		case repositories.InventoryErrorInsufficientStock:
-			return fmt.Errorf("%w: %s", ErrInventoryInsufficientStock, invErr.Message)
+			return detailf(ErrInventoryInsufficientStock, "%s", invErr.Message)
 		case repositories.InventoryErrorReservationNotFound:
-			return fmt.Errorf("%w: %s", ErrInventoryReservationNotFound, invErr.Message)
+			return detailf(ErrInventoryReservationNotFound, "%s", invErr.Message)
 		case repositories.InventoryErrorInvalidReservationState:
-			return fmt.Errorf("%w: %s", ErrInventoryInvalidState, invErr.Message)
+			return detailf(ErrInventoryInvalidState, "%s", invErr.Message)
 		case repositories.InventoryErrorStockNotFound:
-			return fmt.Errorf("%w: %s", ErrInventoryInvalidInput, invErr.Message)
+			return detailf(ErrInventoryInvalidInput, "%s", invErr.Message)
+		case repositories.InventoryErrorLocationNotFound:
+			return detailf(ErrInventoryLocationNotFound, "%s", invErr.Message)
+		case repositories.InventoryErrorLocationRequired:
+			return detailf(ErrInventoryInvalidInput, "%s", invErr.Message)
+		case repositories.InventoryErrorUnassignedStock:
+			return detailf(ErrInventoryInvalidInput, "%s", invErr.Message)
 		}
 	}
 
@@ -339,6 +625,16 @@ func (s *inventoryService) emitStockEvents(ctx context.Context, eventType string
 	return nil
 }
 
+// aggregateStockEvent replaces the balances of a location-scoped ledger entry with the SKU aggregate
+// so that subscribers judge availability across all locations. LocationID still names the location
+// that moved.
+func aggregateStockEvent(event InventoryStockEvent, stock domain.InventoryStock) InventoryStockEvent {
+	event.OnHand = stock.OnHand
+	event.Reserved = stock.Reserved
+	event.SafetyStock = stock.SafetyStock
+	return event
+}
+
 func (s *inventoryService) logEventFailure(ctx context.Context, err error) {
 	if err == nil {
 		return
@@ -353,14 +649,14 @@ func normaliseInventoryLines(lines []InventoryLine) ([]InventoryReservationLine,
 	for _, line := range lines {
 		sku := strings.TrimSpace(line.SKU)
 		if sku == "" {
-			return nil, fmt.Errorf("%w: line sku is required", ErrInventoryInvalidInput)
+			return nil, detailf(ErrInventoryInvalidInput, "line sku is required")
 		}
 		productID := strings.TrimSpace(line.ProductID)
 		if productID == "" {
-			return nil, fmt.Errorf("%w: line product id is required", ErrInventoryInvalidInput)
+			return nil, detailf(ErrInventoryInvalidInput, "line product id is required")
 		}
 		if line.Quantity <= 0 {
-			return nil, fmt.Errorf("%w: quantity for %s must be positive", ErrInventoryInvalidInput, sku)
+			return nil, detailf(ErrInventoryInvalidInput, "quantity for %s must be positive", sku)
 		}
 
 		ref := fmt.Sprintf("/products/%s", productID)
@@ -369,7 +665,7 @@ func normaliseInventoryLines(lines []InventoryLine) ([]InventoryReservationLine,
 			agg = &InventoryReservationLine{ProductRef: ref, SKU: sku}
 			aggregated[sku] = agg
 		} else if agg.ProductRef != ref {
-			return nil, fmt.Errorf("%w: conflicting product references for sku %s", ErrInventoryInvalidInput, sku)
+			return nil, detailf(ErrInventoryInvalidInput, "conflicting product references for sku %s", sku)
 		}
 		agg.Quantity += line.Quantity
 	}
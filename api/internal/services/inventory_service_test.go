@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -11,10 +12,20 @@ import (
 )
 
 type stubInventoryRepo struct {
-	reserveFn func(ctx context.Context, req repositories.InventoryReserveRequest) (repositories.InventoryReserveResult, error)
-	commitFn  func(ctx context.Context, req repositories.InventoryCommitRequest) (repositories.InventoryCommitResult, error)
-	releaseFn func(ctx context.Context, req repositories.InventoryReleaseRequest) (repositories.InventoryReleaseResult, error)
-	listFn    func(ctx context.Context, query repositories.InventoryLowStockQuery) (domain.CursorPage[domain.InventoryStock], error)
+	reserveFn        func(ctx context.Context, req repositories.InventoryReserveRequest) (repositories.InventoryReserveResult, error)
+	commitFn         func(ctx context.Context, req repositories.InventoryCommitRequest) (repositories.InventoryCommitResult, error)
+	releaseFn        func(ctx context.Context, req repositories.InventoryReleaseRequest) (repositories.InventoryReleaseResult, error)
+	restockFn        func(ctx context.Context, req repositories.InventoryRestockRequest) (repositories.InventoryRestockResult, error)
+	getReservationFn func(ctx context.Context, reservationID string) (domain.InventoryReservation, error)
+	listFn           func(ctx context.Context, query repositories.InventoryLowStockQuery) (domain.CursorPage[domain.InventoryStock], error)
+	expiredFn        func(ctx context.Context, query repositories.InventoryExpiredReservationQuery) (domain.CursorPage[domain.InventoryReservation], error)
+	adjustFn         func(ctx context.Context, req repositories.InventoryAdjustRequest) (repositories.InventoryAdjustResult, error)
+	eventsFn         func(ctx context.Context, query repositories.InventoryStockEventQuery) (domain.CursorPage[domain.InventoryStockEvent], error)
+
+	locationStocksFn func(ctx context.Context, sku string) ([]domain.InventoryStock, error)
+	transferFn       func(ctx context.Context, req repositories.InventoryTransferRequest) (repositories.InventoryTransferResult, error)
+	locations        []domain.InventoryLocation
+	upsertFn         func(ctx context.Context, location domain.InventoryLocation) (domain.InventoryLocation, error)
 }
 
 func (s *stubInventoryRepo) Reserve(ctx context.Context, req repositories.InventoryReserveRequest) (repositories.InventoryReserveResult, error) {
@@ -38,7 +49,17 @@ func (s *stubInventoryRepo) Release(ctx context.Context, req repositories.Invent
 	return repositories.InventoryReleaseResult{}, nil
 }
 
+func (s *stubInventoryRepo) Restock(ctx context.Context, req repositories.InventoryRestockRequest) (repositories.InventoryRestockResult, error) {
+	if s.restockFn != nil {
+		return s.restockFn(ctx, req)
+	}
+	return repositories.InventoryRestockResult{}, nil
+}
+
 func (s *stubInventoryRepo) GetReservation(ctx context.Context, reservationID string) (domain.InventoryReservation, error) {
+	if s.getReservationFn != nil {
+		return s.getReservationFn(ctx, reservationID)
+	}
 	return domain.InventoryReservation{}, errors.New("not implemented")
 }
 
@@ -49,6 +70,61 @@ func (s *stubInventoryRepo) ListLowStock(ctx context.Context, query repositories
 	return domain.CursorPage[domain.InventoryStock]{}, nil
 }
 
+func (s *stubInventoryRepo) ListExpiredReservations(ctx context.Context, query repositories.InventoryExpiredReservationQuery) (domain.CursorPage[domain.InventoryReservation], error) {
+	if s.expiredFn != nil {
+		return s.expiredFn(ctx, query)
+	}
+	return domain.CursorPage[domain.InventoryReservation]{}, nil
+}
+
+func (s *stubInventoryRepo) Adjust(ctx context.Context, req repositories.InventoryAdjustRequest) (repositories.InventoryAdjustResult, error) {
+	if s.adjustFn != nil {
+		return s.adjustFn(ctx, req)
+	}
+	return repositories.InventoryAdjustResult{}, nil
+}
+
+func (s *stubInventoryRepo) ListStockEvents(ctx context.Context, query repositories.InventoryStockEventQuery) (domain.CursorPage[domain.InventoryStockEvent], error) {
+	if s.eventsFn != nil {
+		return s.eventsFn(ctx, query)
+	}
+	return domain.CursorPage[domain.InventoryStockEvent]{}, nil
+}
+
+func (s *stubInventoryRepo) ListLocationStocks(ctx context.Context, sku string) ([]domain.InventoryStock, error) {
+	if s.locationStocksFn != nil {
+		return s.locationStocksFn(ctx, sku)
+	}
+	return nil, nil
+}
+
+func (s *stubInventoryRepo) Transfer(ctx context.Context, req repositories.InventoryTransferRequest) (repositories.InventoryTransferResult, error) {
+	if s.transferFn != nil {
+		return s.transferFn(ctx, req)
+	}
+	return repositories.InventoryTransferResult{}, nil
+}
+
+func (s *stubInventoryRepo) ListLocations(context.Context) ([]domain.InventoryLocation, error) {
+	return s.locations, nil
+}
+
+func (s *stubInventoryRepo) GetLocation(_ context.Context, locationID string) (domain.InventoryLocation, error) {
+	for _, location := range s.locations {
+		if location.ID == locationID {
+			return location, nil
+		}
+	}
+	return domain.InventoryLocation{}, repositories.NewInventoryError(repositories.InventoryErrorLocationNotFound, "location "+locationID+" not found", nil)
+}
+
+func (s *stubInventoryRepo) UpsertLocation(ctx context.Context, location domain.InventoryLocation) (domain.InventoryLocation, error) {
+	if s.upsertFn != nil {
+		return s.upsertFn(ctx, location)
+	}
+	return location, nil
+}
+
 type captureInventoryEvents struct {
 	events []InventoryStockEvent
 }
@@ -268,3 +344,406 @@ func TestInventoryServiceListLowStock(t *testing.T) {
 		t.Fatalf("unexpected page contents: %+v", page.Items)
 	}
 }
+
+func TestInventoryServiceReleaseExpiredReservationsBatches(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	expired := []domain.InventoryReservation{
+		{ID: "sr_1", Status: statusReserved},
+		{ID: "sr_2", Status: statusReserved},
+		{ID: "sr_3", Status: statusReserved},
+		{ID: "sr_4", Status: statusReserved},
+	}
+
+	var queries []repositories.InventoryExpiredReservationQuery
+	var releasedReasons []string
+	repo := &stubInventoryRepo{
+		expiredFn: func(_ context.Context, query repositories.InventoryExpiredReservationQuery) (domain.CursorPage[domain.InventoryReservation], error) {
+			queries = append(queries, query)
+			start := 0
+			if query.PageToken != "" {
+				start, _ = strconv.Atoi(query.PageToken)
+			}
+			end := start + query.PageSize
+			if end > len(expired) {
+				end = len(expired)
+			}
+			page := domain.CursorPage[domain.InventoryReservation]{Items: expired[start:end]}
+			if end < len(expired) {
+				page.NextPageToken = strconv.Itoa(end)
+			}
+			return page, nil
+		},
+		releaseFn: func(_ context.Context, req repositories.InventoryReleaseRequest) (repositories.InventoryReleaseResult, error) {
+			releasedReasons = append(releasedReasons, req.Reason)
+			switch req.ReservationID {
+			case "sr_2":
+				return repositories.InventoryReleaseResult{}, repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, "already committed", nil)
+			case "sr_3":
+				return repositories.InventoryReleaseResult{}, errors.New("deadline exceeded")
+			}
+			return repositories.InventoryReleaseResult{Reservation: domain.InventoryReservation{ID: req.ReservationID, Status: statusReleased}}, nil
+		},
+	}
+
+	svc, err := NewInventoryService(InventoryServiceDeps{
+		Inventory: repo,
+		Clock:     func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	result, err := svc.ReleaseExpiredReservations(context.Background(), ReleaseExpiredReservationsCommand{BatchSize: 2, Limit: 3})
+	if err != nil {
+		t.Fatalf("ReleaseExpiredReservations: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(queries))
+	}
+	if !queries[0].Before.Equal(now) || queries[0].PageSize != 2 || queries[1].PageSize != 1 {
+		t.Fatalf("unexpected queries %+v", queries)
+	}
+	if result.CheckedCount != 3 || result.ReleasedCount != 1 || result.SkippedCount != 1 {
+		t.Fatalf("unexpected counts %+v", result)
+	}
+	if len(result.FailedIDs) != 1 || result.FailedIDs[0] != "sr_3" {
+		t.Fatalf("expected sr_3 failure, got %+v", result.FailedIDs)
+	}
+	if !result.HasMore {
+		t.Fatalf("expected HasMore when limit reached before exhausting reservations")
+	}
+	for _, reason := range releasedReasons {
+		if reason != defaultExpiredReleaseReason {
+			t.Fatalf("expected expired reason, got %q", reason)
+		}
+	}
+}
+
+func TestInventoryServiceAdjustStock(t *testing.T) {
+	now := time.Date(2025, 5, 2, 9, 0, 0, 0, time.UTC)
+	var requests []repositories.InventoryAdjustRequest
+	repo := &stubInventoryRepo{
+		adjustFn: func(_ context.Context, req repositories.InventoryAdjustRequest) (repositories.InventoryAdjustResult, error) {
+			requests = append(requests, req)
+			switch req.Event.SKU {
+			case "SKU-MISSING":
+				return repositories.InventoryAdjustResult{}, repositories.NewInventoryError(repositories.InventoryErrorStockNotFound, "stock SKU-MISSING not found", nil)
+			case "SKU-LOW":
+				return repositories.InventoryAdjustResult{}, repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, "onHand for SKU-LOW cannot drop below reserved quantity 3", nil)
+			}
+			event := req.Event
+			event.DeltaOnHand = req.DeltaOnHand
+			event.OnHand = 10 + req.DeltaOnHand
+			event.OccurredAt = req.Now
+			// Published events carry the aggregate, which differs from the ledger entry here.
+			return repositories.InventoryAdjustResult{Stock: domain.InventoryStock{SKU: event.SKU, OnHand: event.OnHand + 20, Available: event.OnHand + 20}, Event: event}, nil
+		},
+	}
+	events := &captureInventoryEvents{}
+	svc, err := NewInventoryService(InventoryServiceDeps{
+		Inventory:   repo,
+		Events:      events,
+		Clock:       func() time.Time { return now },
+		IDGenerator: func() string { return "E1" },
+	})
+	if err != nil {
+		t.Fatalf("new inventory service: %v", err)
+	}
+	ctx := context.Background()
+
+	event, err := svc.AdjustStock(ctx, InventoryAdjustCommand{SKU: " SKU-1 ", ProductID: "prod-1", Type: domain.InventoryAdjustmentReceipt, Quantity: 5, ActorID: "staff-1", Reason: "PO-42"})
+	if err != nil {
+		t.Fatalf("receipt: %v", err)
+	}
+	req := requests[0]
+	if !req.CreateIfMissing || req.DeltaOnHand != 5 || req.SetOnHand != nil || req.Event.ProductRef != "/products/prod-1" {
+		t.Fatalf("unexpected receipt request: %+v", req)
+	}
+	if event.ID != "ise_E1" || event.Type != "inventory.receipt" || event.ActorID != "staff-1" || event.Reason != "PO-42" || event.OnHand != 15 {
+		t.Fatalf("unexpected ledger entry: %+v", event)
+	}
+	if len(events.events) != 1 || events.events[0].ID != "ise_E1" || events.events[0].OnHand != 35 {
+		t.Fatalf("expected adjustment to be published with aggregate balances, got %+v", events.events)
+	}
+
+	if _, err := svc.AdjustStock(ctx, InventoryAdjustCommand{SKU: "SKU-1", Type: domain.InventoryAdjustmentCycleCount, Quantity: 7, ActorID: "staff-1"}); err != nil {
+		t.Fatalf("cycle count: %v", err)
+	}
+	if req := requests[1]; req.SetOnHand == nil || *req.SetOnHand != 7 || !req.AllowBelowReserved || req.CreateIfMissing {
+		t.Fatalf("unexpected cycle count request: %+v", req)
+	}
+	if _, err := svc.AdjustStock(ctx, InventoryAdjustCommand{SKU: "SKU-1", Type: domain.InventoryAdjustmentDamage, Quantity: 2, ActorID: "staff-1", Reason: "cracked"}); err != nil {
+		t.Fatalf("damage: %v", err)
+	}
+	if req := requests[2]; req.DeltaOnHand != -2 || req.AllowBelowReserved {
+		t.Fatalf("unexpected damage request: %+v", req)
+	}
+
+	invalid := []InventoryAdjustCommand{
+		{SKU: "SKU-1", Type: domain.InventoryAdjustmentReceipt, Quantity: 1},
+		{SKU: "SKU-1", Type: domain.InventoryAdjustmentReceipt, Quantity: 0, ActorID: "staff-1"},
+		{SKU: "SKU-1", Type: domain.InventoryAdjustmentDamage, Quantity: 1, ActorID: "staff-1"},
+		{SKU: "SKU-1", Type: domain.InventoryAdjustmentCorrection, Quantity: 0, ActorID: "staff-1", Reason: "typo"},
+		{SKU: "SKU-1", Type: "restock", Quantity: 1, ActorID: "staff-1"},
+	}
+	for _, cmd := range invalid {
+		if _, err := svc.AdjustStock(ctx, cmd); !errors.Is(err, ErrInventoryInvalidInput) {
+			t.Fatalf("expected invalid input for %+v, got %v", cmd, err)
+		}
+	}
+	if len(requests) != 3 {
+		t.Fatalf("invalid adjustments must not reach the repository, got %d requests", len(requests))
+	}
+
+	if _, err := svc.AdjustStock(ctx, InventoryAdjustCommand{SKU: "SKU-MISSING", Type: domain.InventoryAdjustmentCorrection, Quantity: -1, ActorID: "staff-1", Reason: "recount"}); !errors.Is(err, ErrInventoryStockNotFound) {
+		t.Fatalf("expected stock not found, got %v", err)
+	}
+	if _, err := svc.AdjustStock(ctx, InventoryAdjustCommand{SKU: "SKU-LOW", Type: domain.InventoryAdjustmentDamage, Quantity: 9, ActorID: "staff-1", Reason: "water"}); !errors.Is(err, ErrInventoryInsufficientStock) {
+		t.Fatalf("expected insufficient stock, got %v", err)
+	}
+}
+
+func TestInventoryServiceReserveAllocatesLocation(t *testing.T) {
+	locations := []domain.InventoryLocation{
+		{ID: "workshop", Prefecture: "Kyoto", NearbyPrefectures: []string{"Osaka", "Shiga"}, Priority: 1, Active: true},
+		{ID: "partner", Prefecture: "Saitama", NearbyPrefectures: []string{"Tokyo", "Chiba"}, Priority: 2, Active: true},
+		{ID: "overflow", Prefecture: "Tokyo", Priority: 9, Active: false},
+	}
+	stocks := map[string][]domain.InventoryStock{
+		"SKU-1": {
+			{SKU: "SKU-1", LocationID: "workshop", Available: 5},
+			{SKU: "SKU-1", LocationID: "partner", Available: 2},
+			{SKU: "SKU-1", LocationID: "overflow", Available: 50},
+		},
+	}
+	var reserved []domain.InventoryReservationLine
+	repo := &stubInventoryRepo{
+		locations: locations,
+		locationStocksFn: func(_ context.Context, sku string) ([]domain.InventoryStock, error) {
+			return stocks[sku], nil
+		},
+		reserveFn: func(_ context.Context, req repositories.InventoryReserveRequest) (repositories.InventoryReserveResult, error) {
+			reserved = req.Reservation.Lines
+			return repositories.InventoryReserveResult{Reservation: req.Reservation}, nil
+		},
+	}
+	svc, err := NewInventoryService(InventoryServiceDeps{Inventory: repo})
+	if err != nil {
+		t.Fatalf("new inventory service: %v", err)
+	}
+	reserve := func(prefecture string, quantity int) error {
+		_, err := svc.ReserveStocks(context.Background(), InventoryReserveCommand{
+			OrderID:            "ord-1",
+			UserID:             "user-1",
+			Lines:              []InventoryLine{{ProductID: "prod-1", SKU: "SKU-1", Quantity: quantity}, {ProductID: "prod-2", SKU: "SKU-LEGACY", Quantity: 1}},
+			TTL:                time.Minute,
+			ShippingPrefecture: prefecture,
+		})
+		return err
+	}
+	locationOf := func(sku string) string {
+		for _, line := range reserved {
+			if line.SKU == sku {
+				return line.LocationID
+			}
+		}
+		return "missing"
+	}
+
+	cases := []struct {
+		name       string
+		prefecture string
+		quantity   int
+		want       string
+	}{
+		{name: "nearby prefecture beats priority", prefecture: "tokyo", quantity: 1, want: "partner"},
+		{name: "same prefecture", prefecture: "Kyoto", quantity: 1, want: "workshop"},
+		{name: "priority when equally far", prefecture: "Okinawa", quantity: 1, want: "workshop"},
+		{name: "availability overrides distance", prefecture: "Tokyo", quantity: 4, want: "workshop"},
+	}
+	for _, tc := range cases {
+		if err := reserve(tc.prefecture, tc.quantity); err != nil {
+			t.Fatalf("%s: reserve: %v", tc.name, err)
+		}
+		if got := locationOf("SKU-1"); got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+		if got := locationOf("SKU-LEGACY"); got != "" {
+			t.Fatalf("%s: expected legacy sku to stay unallocated, got %q", tc.name, got)
+		}
+	}
+
+	if err := reserve("Tokyo", 6); !errors.Is(err, ErrInventoryInsufficientStock) {
+		t.Fatalf("expected insufficient stock when no active location covers the line, got %v", err)
+	}
+
+	lines := []InventoryReservationLine{{SKU: "SKU-1", Quantity: 2}, {SKU: "SKU-1", Quantity: 2}, {SKU: "SKU-1", Quantity: 2}}
+	if err := svc.(*inventoryService).allocateLocations(context.Background(), lines, "Tokyo"); err != nil {
+		t.Fatalf("allocate repeated sku: %v", err)
+	}
+	if lines[0].LocationID != "partner" || lines[1].LocationID != "workshop" || lines[2].LocationID != "workshop" {
+		t.Fatalf("expected later lines to use what earlier lines left, got %+v", lines)
+	}
+	lines = append(lines, InventoryReservationLine{SKU: "SKU-1", Quantity: 2})
+	if err := svc.(*inventoryService).allocateLocations(context.Background(), lines, "Tokyo"); !errors.Is(err, ErrInventoryInsufficientStock) {
+		t.Fatalf("expected insufficient stock once the locations are used up, got %v", err)
+	}
+	if stocks["SKU-1"][1].Available != 2 {
+		t.Fatalf("allocation must not modify the listed stock, got %+v", stocks["SKU-1"])
+	}
+}
+
+func TestInventoryServiceTransferStock(t *testing.T) {
+	now := time.Date(2025, 5, 3, 9, 0, 0, 0, time.UTC)
+	var captured repositories.InventoryTransferRequest
+	ids := 0
+	repo := &stubInventoryRepo{
+		locations: []domain.InventoryLocation{
+			{ID: "workshop", Active: true},
+			{ID: "partner", Active: true},
+			{ID: "closed", Active: false},
+		},
+		transferFn: func(_ context.Context, req repositories.InventoryTransferRequest) (repositories.InventoryTransferResult, error) {
+			captured = req
+			if req.Quantity > 10 {
+				return repositories.InventoryTransferResult{}, repositories.NewInventoryError(repositories.InventoryErrorInsufficientStock, "insufficient stock for SKU-1 at location workshop", nil)
+			}
+			return repositories.InventoryTransferResult{Events: []domain.InventoryStockEvent{req.OutEvent, req.InEvent}}, nil
+		},
+	}
+	events := &captureInventoryEvents{}
+	svc, err := NewInventoryService(InventoryServiceDeps{
+		Inventory: repo,
+		Events:    events,
+		Clock:     func() time.Time { return now },
+		IDGenerator: func() string {
+			ids++
+			return strconv.Itoa(ids)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new inventory service: %v", err)
+	}
+	ctx := context.Background()
+
+	result, err := svc.TransferStock(ctx, InventoryTransferCommand{SKU: "SKU-1", FromLocationID: "workshop", ToLocationID: "partner", Quantity: 3, ActorID: "staff-1", Reason: "rebalance"})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if captured.Quantity != 3 || captured.OutEvent.ID != "ise_1" || captured.InEvent.ID != "ise_2" || !captured.Now.Equal(now) {
+		t.Fatalf("unexpected transfer request: %+v", captured)
+	}
+	if captured.OutEvent.Metadata["toLocationId"] != "partner" || captured.InEvent.Metadata["fromLocationId"] != "workshop" {
+		t.Fatalf("expected legs to reference each other, got %+v / %+v", captured.OutEvent.Metadata, captured.InEvent.Metadata)
+	}
+	if len(result) != 2 || len(events.events) != 2 {
+		t.Fatalf("expected both ledger entries returned and published, got %d / %d", len(result), len(events.events))
+	}
+
+	failures := []struct {
+		cmd  InventoryTransferCommand
+		want error
+	}{
+		{cmd: InventoryTransferCommand{SKU: "SKU-1", FromLocationID: "workshop", ToLocationID: "workshop", Quantity: 1, ActorID: "staff-1"}, want: ErrInventoryInvalidInput},
+		{cmd: InventoryTransferCommand{SKU: "SKU-1", FromLocationID: "workshop", ToLocationID: "partner", Quantity: 0, ActorID: "staff-1"}, want: ErrInventoryInvalidInput},
+		{cmd: InventoryTransferCommand{SKU: "SKU-1", FromLocationID: "workshop", ToLocationID: "closed", Quantity: 1, ActorID: "staff-1"}, want: ErrInventoryInvalidInput},
+		{cmd: InventoryTransferCommand{SKU: "SKU-1", FromLocationID: "nowhere", ToLocationID: "partner", Quantity: 1, ActorID: "staff-1"}, want: ErrInventoryLocationNotFound},
+		{cmd: InventoryTransferCommand{SKU: "SKU-1", FromLocationID: "workshop", ToLocationID: "partner", Quantity: 11, ActorID: "staff-1"}, want: ErrInventoryInsufficientStock},
+	}
+	for _, tc := range failures {
+		if _, err := svc.TransferStock(ctx, tc.cmd); !errors.Is(err, tc.want) {
+			t.Fatalf("transfer %+v: expected %v, got %v", tc.cmd, tc.want, err)
+		}
+	}
+}
+
+func TestInventoryServiceRestockReservationRequiresCommitted(t *testing.T) {
+	repo := &stubInventoryRepo{
+		getReservationFn: func(_ context.Context, id string) (domain.InventoryReservation, error) {
+			return domain.InventoryReservation{ID: id, OrderRef: "/orders/ord_1", Status: "reserved", Lines: []domain.InventoryReservationLine{{SKU: "SKU-1", Quantity: 1}}}, nil
+		},
+		restockFn: func(context.Context, repositories.InventoryRestockRequest) (repositories.InventoryRestockResult, error) {
+			t.Fatalf("reserved stock must not be restocked")
+			return repositories.InventoryRestockResult{}, nil
+		},
+	}
+	svc, err := NewInventoryService(InventoryServiceDeps{Inventory: repo})
+	if err != nil {
+		t.Fatalf("new inventory service: %v", err)
+	}
+
+	_, err = svc.RestockReservation(context.Background(), InventoryRestockCommand{ReservationID: "res_1", OrderID: "ord_1", ActorID: "system"})
+	if !errors.Is(err, ErrInventoryInvalidState) {
+		t.Fatalf("expected invalid state for a reserved reservation, got %v", err)
+	}
+}
+
+func TestInventoryServiceRestockReservationRestocksOnce(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	reservation := domain.InventoryReservation{ID: "res_1", OrderRef: "/orders/ord_1", Status: "committed", Lines: []domain.InventoryReservationLine{
+		{SKU: "SKU-1", ProductRef: "/products/p1", Quantity: 2, LocationID: "workshop"},
+		{SKU: "SKU-3", ProductRef: "/products/p3", Quantity: 1},
+	}}
+	var requests []repositories.InventoryRestockRequest
+	repo := &stubInventoryRepo{
+		getReservationFn: func(context.Context, string) (domain.InventoryReservation, error) {
+			return reservation, nil
+		},
+		restockFn: func(_ context.Context, req repositories.InventoryRestockRequest) (repositories.InventoryRestockResult, error) {
+			requests = append(requests, req)
+			restocked := reservation
+			restocked.Status = "restocked"
+			reservation = restocked
+			return repositories.InventoryRestockResult{
+				Reservation: restocked,
+				Stocks: map[string]domain.InventoryStock{
+					"SKU-1": {SKU: "SKU-1", OnHand: 5},
+					"SKU-3": {SKU: "SKU-3", OnHand: 1},
+				},
+				Events: []domain.InventoryStockEvent{
+					{ID: req.Events[0].ID, Type: req.Events[0].Type, SKU: "SKU-1", LocationID: "workshop", DeltaOnHand: 2},
+					{ID: req.Events[1].ID, Type: req.Events[1].Type, SKU: "SKU-3", DeltaOnHand: 1},
+				},
+			}, nil
+		},
+		adjustFn: func(context.Context, repositories.InventoryAdjustRequest) (repositories.InventoryAdjustResult, error) {
+			t.Fatalf("restock must not adjust lines one transaction at a time")
+			return repositories.InventoryAdjustResult{}, nil
+		},
+	}
+	events := &captureInventoryEvents{}
+	seq := 0
+	svc, err := NewInventoryService(InventoryServiceDeps{
+		Inventory:   repo,
+		Events:      events,
+		Clock:       func() time.Time { return now },
+		IDGenerator: func() string { seq++; return strconv.Itoa(seq) },
+	})
+	if err != nil {
+		t.Fatalf("new inventory service: %v", err)
+	}
+	ctx := context.Background()
+	cmd := InventoryRestockCommand{ReservationID: "res_1", OrderID: "ord_1", ActorID: "system", Reason: "order_canceled"}
+
+	ledger, err := svc.RestockReservation(ctx, cmd)
+	if err != nil {
+		t.Fatalf("restock: %v", err)
+	}
+	if len(requests) != 1 || len(requests[0].Events) != 2 || requests[0].OrderRef != "/orders/ord_1" || !requests[0].Now.Equal(now) {
+		t.Fatalf("expected one restock request with an event per stocked line, got %+v", requests)
+	}
+	if requests[0].Events[0].Type != eventInventoryRestock || requests[0].Events[0].ActorID != "system" {
+		t.Fatalf("unexpected ledger template: %+v", requests[0].Events[0])
+	}
+	if len(ledger) != 2 || len(events.events) != 2 {
+		t.Fatalf("expected both ledger entries returned and published, got %d / %d", len(ledger), len(events.events))
+	}
+
+	again, err := svc.RestockReservation(ctx, cmd)
+	if err != nil {
+		t.Fatalf("second restock: %v", err)
+	}
+	if len(again) != 0 || len(requests) != 1 || len(events.events) != 2 {
+		t.Fatalf("restocking twice must be a no-op, got %d events and %d requests", len(again), len(requests))
+	}
+}
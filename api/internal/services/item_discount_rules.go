@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Item discount rule types accepted in ItemDiscountRuleConfig.Type.
+const (
+	ItemDiscountRuleBuyXGetY     = "buy_x_get_y"
+	ItemDiscountRuleBundle       = "bundle"
+	ItemDiscountRuleQuantityStep = "quantity_step"
+	ItemDiscountRuleMember       = "member"
+	ItemDiscountRuleFlashSale    = "flash_sale"
+)
+
+// ItemDiscountRuleConfig declares one ItemDiscountRule. SKUs and ProductIDs restrict the lines a rule
+// applies to (empty matches every line) and StartsAt/EndsAt bound when it is active. Amounts are in
+// minor units of the cart currency; AmountOff and SalePrice are per unit.
+type ItemDiscountRuleConfig struct {
+	Name        string     `json:"name" yaml:"name"`
+	Type        string     `json:"type" yaml:"type"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	SKUs        []string   `json:"skus,omitempty" yaml:"skus,omitempty"`
+	ProductIDs  []string   `json:"product_ids,omitempty" yaml:"product_ids,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty" yaml:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty" yaml:"ends_at,omitempty"`
+
+	PercentOff int   `json:"percent_off,omitempty" yaml:"percent_off,omitempty"`
+	AmountOff  int64 `json:"amount_off,omitempty" yaml:"amount_off,omitempty"`
+
+	// buy_x_get_y: every BuyQuantity+GetQuantity units of a line discount GetQuantity units by
+	// PercentOff, which defaults to 100 (free).
+	BuyQuantity int `json:"buy_quantity,omitempty" yaml:"buy_quantity,omitempty"`
+	GetQuantity int `json:"get_quantity,omitempty" yaml:"get_quantity,omitempty"`
+
+	// bundle: each complete set of Components costs BundlePrice.
+	Components  []BundleComponentConfig `json:"components,omitempty" yaml:"components,omitempty"`
+	BundlePrice int64                   `json:"bundle_price,omitempty" yaml:"bundle_price,omitempty"`
+
+	// quantity_step: the highest step reached by the line quantity applies.
+	Steps []QuantityStepConfig `json:"steps,omitempty" yaml:"steps,omitempty"`
+
+	// member: shoppers holding any of Roles receive PercentOff or AmountOff.
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+
+	// flash_sale: lines are discounted by PercentOff or down to SalePrice inside the time box.
+	SalePrice int64 `json:"sale_price,omitempty" yaml:"sale_price,omitempty"`
+}
+
+// BundleComponentConfig identifies one product of a bundle by SKU or product ID.
+type BundleComponentConfig struct {
+	SKU       string `json:"sku,omitempty" yaml:"sku,omitempty"`
+	ProductID string `json:"product_id,omitempty" yaml:"product_id,omitempty"`
+	Quantity  int    `json:"quantity" yaml:"quantity"`
+	Label     string `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// QuantityStepConfig discounts lines whose quantity reaches MinQuantity.
+type QuantityStepConfig struct {
+	MinQuantity int   `json:"min_quantity" yaml:"min_quantity"`
+	PercentOff  int   `json:"percent_off,omitempty" yaml:"percent_off,omitempty"`
+	AmountOff   int64 `json:"amount_off,omitempty" yaml:"amount_off,omitempty"`
+}
+
+// LoadItemDiscountRules reads a JSON or YAML list of ItemDiscountRuleConfig and builds the rules.
+func LoadItemDiscountRules(path string) ([]ItemDiscountRule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("item discount rules: read %s: %w", path, err)
+	}
+	var configs []ItemDiscountRuleConfig
+	if err := decodeConfigDocument(path, raw, &configs); err != nil {
+		return nil, fmt.Errorf("item discount rules: parse %s: %w", path, err)
+	}
+	return NewItemDiscountRules(configs)
+}
+
+// NewItemDiscountRules validates the configs and builds rules for CartPricingEngineDeps.ItemRules.
+func NewItemDiscountRules(configs []ItemDiscountRuleConfig) ([]ItemDiscountRule, error) {
+	rules := make([]ItemDiscountRule, 0, len(configs))
+	names := make(map[string]struct{}, len(configs))
+	for i, cfg := range configs {
+		rule, err := newItemDiscountRule(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("item discount rules: rule %d (%s): %w", i, strings.TrimSpace(cfg.Name), err)
+		}
+		if _, dup := names[rule.Name()]; dup {
+			return nil, fmt.Errorf("item discount rules: duplicate rule name %q", rule.Name())
+		}
+		names[rule.Name()] = struct{}{}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+type configuredDiscountRule struct {
+	name        string
+	kind        string
+	description string
+	skus        map[string]struct{}
+	productIDs  map[string]struct{}
+	startsAt    *time.Time
+	endsAt      *time.Time
+	percentOff  int64
+	amountOff   int64
+	buy         int
+	get         int
+	components  []BundleComponentConfig
+	bundlePrice int64
+	steps       []QuantityStepConfig
+	roles       map[string]struct{}
+	salePrice   int64
+}
+
+func newItemDiscountRule(cfg ItemDiscountRuleConfig) (*configuredDiscountRule, error) {
+	rule := &configuredDiscountRule{
+		name:        strings.TrimSpace(cfg.Name),
+		kind:        strings.ToLower(strings.TrimSpace(cfg.Type)),
+		description: strings.TrimSpace(cfg.Description),
+		skus:        foldedSet(cfg.SKUs, strings.ToUpper),
+		productIDs:  foldedSet(cfg.ProductIDs, func(v string) string { return v }),
+		startsAt:    cfg.StartsAt,
+		endsAt:      cfg.EndsAt,
+		percentOff:  int64(cfg.PercentOff),
+		amountOff:   cfg.AmountOff,
+	}
+	if rule.name == "" {
+		return nil, errors.New("name is required")
+	}
+	if cfg.PercentOff < 0 || cfg.PercentOff > 100 {
+		return nil, errors.New("percent_off must be between 0 and 100")
+	}
+	if cfg.AmountOff < 0 || cfg.SalePrice < 0 || cfg.BundlePrice < 0 {
+		return nil, errors.New("amounts cannot be negative")
+	}
+	if cfg.StartsAt != nil && cfg.EndsAt != nil && !cfg.EndsAt.After(*cfg.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+
+	switch rule.kind {
+	case ItemDiscountRuleBuyXGetY:
+		if cfg.BuyQuantity <= 0 || cfg.GetQuantity <= 0 {
+			return nil, errors.New("buy_quantity and get_quantity must be positive")
+		}
+		rule.buy, rule.get = cfg.BuyQuantity, cfg.GetQuantity
+		if rule.percentOff == 0 {
+			rule.percentOff = 100
+		}
+	case ItemDiscountRuleBundle:
+		if len(cfg.Components) < 2 {
+			return nil, errors.New("bundle needs at least two components")
+		}
+		for _, component := range cfg.Components {
+			if strings.TrimSpace(component.SKU) == "" && strings.TrimSpace(component.ProductID) == "" {
+				return nil, errors.New("bundle components need a sku or product_id")
+			}
+			if component.Quantity <= 0 {
+				return nil, errors.New("bundle component quantity must be positive")
+			}
+		}
+		rule.components = append([]BundleComponentConfig(nil), cfg.Components...)
+		rule.bundlePrice = cfg.BundlePrice
+	case ItemDiscountRuleQuantityStep:
+		if len(cfg.Steps) == 0 {
+			return nil, errors.New("quantity_step needs at least one step")
+		}
+		steps := append([]QuantityStepConfig(nil), cfg.Steps...)
+		sort.SliceStable(steps, func(i, j int) bool { return steps[i].MinQuantity < steps[j].MinQuantity })
+		for i, step := range steps {
+			if step.MinQuantity <= 0 || (i > 0 && step.MinQuantity == steps[i-1].MinQuantity) {
+				return nil, fmt.Errorf("invalid step min_quantity %d", step.MinQuantity)
+			}
+			if err := requireOneReduction(int64(step.PercentOff), step.AmountOff); err != nil {
+				return nil, err
+			}
+		}
+		rule.steps = steps
+	case ItemDiscountRuleMember:
+		rule.roles = foldedSet(cfg.Roles, strings.ToLower)
+		if len(rule.roles) == 0 {
+			return nil, errors.New("member rule needs at least one role")
+		}
+		if err := requireOneReduction(rule.percentOff, rule.amountOff); err != nil {
+			return nil, err
+		}
+	case ItemDiscountRuleFlashSale:
+		if cfg.StartsAt == nil || cfg.EndsAt == nil {
+			return nil, errors.New("flash_sale needs starts_at and ends_at")
+		}
+		if err := requireOneReduction(rule.percentOff, cfg.SalePrice); err != nil {
+			return nil, err
+		}
+		rule.salePrice = cfg.SalePrice
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", cfg.Type)
+	}
+	return rule, nil
+}
+
+func requireOneReduction(percent, amount int64) error {
+	if (percent > 0) == (amount > 0) {
+		return errors.New("exactly one of percent_off or an amount must be set")
+	}
+	if percent > 100 {
+		return errors.New("percent_off must be between 0 and 100")
+	}
+	return nil
+}
+
+func (r *configuredDiscountRule) Name() string { return r.name }
+
+// Apply prices a single line without cart context, as if it were the only item in the cart.
+func (r *configuredDiscountRule) Apply(ctx context.Context, item CartItem, subtotal int64) (ItemDiscountResult, error) {
+	bound, err := r.Bind(ctx, ItemRuleContext{Items: []CartItem{item}, Now: time.Now().UTC()})
+	if err != nil || bound == nil {
+		return ItemDiscountResult{}, err
+	}
+	return bound.Apply(ctx, item, subtotal)
+}
+
+// Bind resolves the time box, the shopper's roles and bundle sets for one calculation. It returns nil
+// when the rule cannot discount anything in the cart.
+func (r *configuredDiscountRule) Bind(_ context.Context, rc ItemRuleContext) (ItemDiscountRule, error) {
+	if r.startsAt != nil && rc.Now.Before(*r.startsAt) {
+		return nil, nil
+	}
+	if r.endsAt != nil && !rc.Now.Before(*r.endsAt) {
+		return nil, nil
+	}
+	bound := &boundDiscountRule{rule: r}
+	switch r.kind {
+	case ItemDiscountRuleMember:
+		if !r.hasRole(rc.UserRoles) {
+			return nil, nil
+		}
+	case ItemDiscountRuleBundle:
+		bound.bundleShares = r.bundleShares(rc.Items)
+		if len(bound.bundleShares) == 0 {
+			return nil, nil
+		}
+	}
+	return bound, nil
+}
+
+type boundDiscountRule struct {
+	rule         *configuredDiscountRule
+	bundleShares map[string]int64
+}
+
+func (b *boundDiscountRule) Name() string { return b.rule.name }
+
+func (b *boundDiscountRule) Apply(_ context.Context, item CartItem, subtotal int64) (ItemDiscountResult, error) {
+	r := b.rule
+	if subtotal <= 0 || item.Quantity <= 0 {
+		return ItemDiscountResult{}, nil
+	}
+	var amount int64
+	var description string
+	if r.kind == ItemDiscountRuleBundle {
+		amount = b.bundleShares[item.ID]
+		description = r.bundleDescription()
+	} else {
+		if !r.matches(item) {
+			return ItemDiscountResult{}, nil
+		}
+		amount, description = r.lineDiscount(item.Quantity, subtotal)
+	}
+	if amount <= 0 {
+		return ItemDiscountResult{}, nil
+	}
+	if amount > subtotal {
+		amount = subtotal
+	}
+	if r.description != "" {
+		description = r.description
+	}
+	return ItemDiscountResult{
+		Amount:      amount,
+		Description: description,
+		Metadata:    map[string]any{"ruleType": r.kind},
+	}, nil
+}
+
+// lineDiscount computes the discount for rules that price each line independently.
+func (r *configuredDiscountRule) lineDiscount(quantity int, subtotal int64) (int64, string) {
+	qty := int64(quantity)
+	unitPrice := subtotal / qty
+	switch r.kind {
+	case ItemDiscountRuleBuyXGetY:
+		discounted := int64(quantity/(r.buy+r.get)) * int64(r.get)
+		if r.percentOff == 100 {
+			return discounted * unitPrice, fmt.Sprintf("Buy %d get %d free", r.buy, r.get)
+		}
+		return discounted * unitPrice * r.percentOff / 100, fmt.Sprintf("Buy %d get %d at %d%% off", r.buy, r.get, r.percentOff)
+	case ItemDiscountRuleQuantityStep:
+		var step *QuantityStepConfig
+		for i := range r.steps {
+			if quantity >= r.steps[i].MinQuantity {
+				step = &r.steps[i]
+			}
+		}
+		if step == nil {
+			return 0, ""
+		}
+		if step.PercentOff > 0 {
+			return subtotal * int64(step.PercentOff) / 100, fmt.Sprintf("%d%% off when buying %d or more", step.PercentOff, step.MinQuantity)
+		}
+		return step.AmountOff * qty, fmt.Sprintf("Volume discount when buying %d or more", step.MinQuantity)
+	case ItemDiscountRuleMember:
+		if r.percentOff > 0 {
+			return subtotal * r.percentOff / 100, fmt.Sprintf("Member discount (%d%% off)", r.percentOff)
+		}
+		return r.amountOff * qty, "Member discount"
+	case ItemDiscountRuleFlashSale:
+		if r.percentOff > 0 {
+			return subtotal * r.percentOff / 100, fmt.Sprintf("Flash sale (%d%% off)", r.percentOff)
+		}
+		if unitPrice <= r.salePrice {
+			return 0, ""
+		}
+		return (unitPrice - r.salePrice) * qty, "Flash sale price"
+	}
+	return 0, ""
+}
+
+// bundleShares counts the complete sets in the cart and spreads the set savings across the component
+// lines in proportion to the value each line contributes to the sets.
+func (r *configuredDiscountRule) bundleShares(items []CartItem) map[string]int64 {
+	remaining := make([]int, len(items))
+	for i, item := range items {
+		remaining[i] = item.Quantity
+	}
+
+	type usage struct {
+		lines []int
+		price int64
+	}
+	usages := make([]usage, len(r.components))
+	sets := -1
+	for c, component := range r.components {
+		available := 0
+		for i, item := range items {
+			if remaining[i] > 0 && component.matches(item) {
+				usages[c].lines = append(usages[c].lines, i)
+				if usages[c].price == 0 {
+					usages[c].price = item.UnitPrice
+				}
+				available += remaining[i]
+			}
+		}
+		componentSets := available / component.Quantity
+		if sets < 0 || componentSets < sets {
+			sets = componentSets
+		}
+		// Reserve units so a line matching several components is not counted twice.
+		need := component.Quantity * componentSets
+		for _, i := range usages[c].lines {
+			take := min(need, remaining[i])
+			remaining[i] -= take
+			need -= take
+		}
+	}
+	if sets <= 0 {
+		return nil
+	}
+
+	var regular int64
+	for c, component := range r.components {
+		regular += usages[c].price * int64(component.Quantity)
+	}
+	savings := (regular - r.bundlePrice) * int64(sets)
+	if savings <= 0 {
+		return nil
+	}
+
+	for i, item := range items {
+		remaining[i] = item.Quantity
+	}
+	ids := make([]string, 0, len(items))
+	weights := make([]int64, 0, len(items))
+	for c, component := range r.components {
+		need := component.Quantity * sets
+		for _, i := range usages[c].lines {
+			if need == 0 {
+				break
+			}
+			take := min(need, remaining[i])
+			remaining[i] -= take
+			need -= take
+			ids = append(ids, items[i].ID)
+			weights = append(weights, int64(take)*items[i].UnitPrice)
+		}
+	}
+	shares := make(map[string]int64, len(ids))
+	for i, amount := range allocateByWeight(savings, weights) {
+		shares[ids[i]] += amount
+	}
+	return shares
+}
+
+func (r *configuredDiscountRule) bundleDescription() string {
+	labels := make([]string, 0, len(r.components))
+	for _, component := range r.components {
+		label := strings.TrimSpace(component.Label)
+		if label == "" {
+			label = strings.TrimSpace(component.SKU)
+		}
+		if label == "" {
+			label = strings.TrimSpace(component.ProductID)
+		}
+		labels = append(labels, label)
+	}
+	return "Set discount: " + strings.Join(labels, " + ")
+}
+
+func (c BundleComponentConfig) matches(item CartItem) bool {
+	if sku := strings.TrimSpace(c.SKU); sku != "" {
+		return strings.EqualFold(sku, strings.TrimSpace(item.SKU))
+	}
+	return strings.TrimSpace(c.ProductID) == strings.TrimSpace(item.ProductID)
+}
+
+func (r *configuredDiscountRule) matches(item CartItem) bool {
+	if len(r.skus) == 0 && len(r.productIDs) == 0 {
+		return true
+	}
+	if _, ok := r.skus[strings.ToUpper(strings.TrimSpace(item.SKU))]; ok {
+		return true
+	}
+	_, ok := r.productIDs[strings.TrimSpace(item.ProductID)]
+	return ok
+}
+
+func (r *configuredDiscountRule) hasRole(roles []string) bool {
+	for _, role := range roles {
+		if _, ok := r.roles[strings.ToLower(strings.TrimSpace(role))]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func foldedSet(values []string, fold func(string) string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if value = fold(strings.TrimSpace(value)); value != "" {
+			set[value] = struct{}{}
+		}
+	}
+	return set
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testItemDiscountRules = `[
+	{"name": "seal_set", "type": "bundle", "bundle_price": 18000, "components": [
+		{"sku": "JITSUIN", "quantity": 1, "label": "jitsuin"},
+		{"sku": "GINKOIN", "quantity": 1, "label": "ginkoin"},
+		{"sku": "MITOMEIN", "quantity": 1, "label": "mitomein"}
+	]},
+	{"name": "case_b2g1", "type": "buy_x_get_y", "skus": ["CASE"], "buy_quantity": 2, "get_quantity": 1},
+	{"name": "ink_volume", "type": "quantity_step", "skus": ["INK"], "steps": [
+		{"min_quantity": 50, "percent_off": 10},
+		{"min_quantity": 10, "percent_off": 5}
+	]},
+	{"name": "corporate", "type": "member", "roles": ["corporate"], "skus": ["INK"], "percent_off": 3},
+	{"name": "new_year", "type": "flash_sale", "skus": ["CASE"], "sale_price": 800,
+		"starts_at": "2025-01-01T00:00:00Z", "ends_at": "2025-01-04T00:00:00Z", "description": "New Year flash sale"}
+]`
+
+func newDiscountRuleEngine(t *testing.T, now time.Time) *CartPricingEngine {
+	t.Helper()
+	var configs []ItemDiscountRuleConfig
+	if err := json.Unmarshal([]byte(testItemDiscountRules), &configs); err != nil {
+		t.Fatalf("decode configs: %v", err)
+	}
+	rules, err := NewItemDiscountRules(configs)
+	if err != nil {
+		t.Fatalf("NewItemDiscountRules error: %v", err)
+	}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion: &fakePromotionService{},
+		ItemRules: rules,
+		Now:       func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	return engine
+}
+
+func discountCart() Cart {
+	return Cart{
+		UserID:   "user_1",
+		Currency: "JPY",
+		Items: []CartItem{
+			{ID: "j", SKU: "jitsuin", Quantity: 1, UnitPrice: 10000, Currency: "JPY"},
+			{ID: "g", SKU: "ginkoin", Quantity: 1, UnitPrice: 8000, Currency: "JPY"},
+			{ID: "m", SKU: "mitomein", Quantity: 2, UnitPrice: 3000, Currency: "JPY"},
+			{ID: "c", SKU: "case", Quantity: 7, UnitPrice: 1000, Currency: "JPY"},
+			{ID: "i", SKU: "ink", Quantity: 12, UnitPrice: 500, Currency: "JPY"},
+		},
+	}
+}
+
+func TestItemDiscountRules_InEngine(t *testing.T) {
+	engine := newDiscountRuleEngine(t, time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC))
+
+	res, err := engine.Calculate(context.Background(), PriceCartCommand{Cart: discountCart(), UserRoles: []string{"Corporate"}})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	lines := map[string]int64{}
+	for _, item := range res.Breakdown.Items {
+		lines[item.ItemID] = item.Discount
+	}
+	// 3000 of set savings split 10000:8000:3000 across the components, one mitomein left at full price.
+	want := map[string]int64{"j": 1429, "g": 1143, "m": 428, "c": 2000, "i": 300 + 180}
+	for id, amount := range want {
+		if lines[id] != amount {
+			t.Fatalf("line %s: expected discount %d, got %d (%v)", id, amount, lines[id], lines)
+		}
+	}
+
+	descriptions := map[string]string{}
+	for _, discount := range res.Breakdown.Discounts {
+		descriptions[discount.Source] = discount.Description
+		if discount.Type == "item" && discount.Metadata["ruleType"] == nil {
+			t.Fatalf("expected rule type metadata on %+v", discount)
+		}
+	}
+	expected := map[string]string{
+		"seal_set":   "Set discount: jitsuin + ginkoin + mitomein",
+		"case_b2g1":  "Buy 2 get 1 free",
+		"ink_volume": "5% off when buying 10 or more",
+		"corporate":  "Member discount (3% off)",
+	}
+	for source, description := range expected {
+		if descriptions[source] != description {
+			t.Fatalf("expected %s description %q, got %q", source, description, descriptions[source])
+		}
+	}
+	if _, ok := descriptions["new_year"]; ok {
+		t.Fatalf("flash sale should not apply before it starts")
+	}
+
+	res, err = engine.Calculate(context.Background(), PriceCartCommand{Cart: discountCart()})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	for _, discount := range res.Breakdown.Discounts {
+		if discount.Source == "corporate" {
+			t.Fatalf("member discount applied without the role")
+		}
+	}
+}
+
+func TestItemDiscountRules_FlashSaleWindow(t *testing.T) {
+	engine := newDiscountRuleEngine(t, time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC))
+	cart := Cart{Currency: "JPY", Items: []CartItem{{ID: "c", SKU: "case", Quantity: 2, UnitPrice: 1000, Currency: "JPY"}}}
+
+	res, err := engine.Calculate(context.Background(), PriceCartCommand{Cart: cart})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	if res.Breakdown.Discount != 400 || len(res.Breakdown.Discounts) != 1 || res.Breakdown.Discounts[0].Description != "New Year flash sale" {
+		t.Fatalf("expected flash sale price, got %+v", res.Breakdown.Discounts)
+	}
+}
+
+func TestLoadItemDiscountRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	content := "- name: bulk\n  type: quantity_step\n  steps:\n    - min_quantity: 100\n      amount_off: 50\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rules, err := LoadItemDiscountRules(path)
+	if err != nil {
+		t.Fatalf("LoadItemDiscountRules error: %v", err)
+	}
+	result, err := rules[0].Apply(context.Background(), CartItem{ID: "a", SKU: "any", Quantity: 100, UnitPrice: 700}, 70000)
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+	if result.Amount != 5000 || result.Description != "Volume discount when buying 100 or more" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	invalid := []ItemDiscountRuleConfig{
+		{Name: "x", Type: "mystery"},
+		{Name: "x", Type: ItemDiscountRuleBundle, Components: []BundleComponentConfig{{SKU: "A", Quantity: 1}}},
+		{Name: "x", Type: ItemDiscountRuleMember, PercentOff: 5},
+		{Name: "x", Type: ItemDiscountRuleFlashSale, PercentOff: 5},
+		{Name: "x", Type: ItemDiscountRuleQuantityStep, Steps: []QuantityStepConfig{{MinQuantity: 5, PercentOff: 5, AmountOff: 10}}},
+		{Name: "x", Type: ItemDiscountRuleBuyXGetY, BuyQuantity: 1, GetQuantity: 1, PercentOff: 120},
+	}
+	for _, cfg := range invalid {
+		if _, err := NewItemDiscountRules([]ItemDiscountRuleConfig{cfg}); err == nil {
+			t.Fatalf("expected config %+v to be rejected", cfg)
+		}
+	}
+	duplicate := ItemDiscountRuleConfig{Name: "dup", Type: ItemDiscountRuleBuyXGetY, BuyQuantity: 1, GetQuantity: 1}
+	if _, err := NewItemDiscountRules([]ItemDiscountRuleConfig{duplicate, duplicate}); err == nil {
+		t.Fatalf("expected duplicate names to be rejected")
+	}
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Tax codes understood by JPConsumptionTaxCalculator. Items without a tax code are taxed at the
+// standard rate.
+const (
+	JPTaxCodeStandard = "jp_standard"
+	JPTaxCodeReduced  = "jp_reduced"
+	JPTaxCodeExempt   = "jp_exempt"
+)
+
+// Rounding modes applied once per tax rate, as required for qualified invoices.
+const (
+	JPTaxRoundingFloor   = "floor"
+	JPTaxRoundingHalfUp  = "half_up"
+	JPTaxRoundingCeiling = "ceiling"
+)
+
+const (
+	jpStandardRatePercent = 10
+	jpReducedRatePercent  = 8
+	jpJurisdiction        = "JP"
+)
+
+// JPConsumptionTaxConfig configures the Japanese consumption tax calculator.
+type JPConsumptionTaxConfig struct {
+	// PricesIncludeTax treats unit prices and shipping fees as tax-inclusive list prices.
+	PricesIncludeTax bool
+	// RoundingMode defaults to JPTaxRoundingFloor, the common practice for qualified invoices.
+	RoundingMode string
+	// ReducedTaxCodes and ExemptTaxCodes extend the built-in jp_reduced and jp_exempt codes.
+	ReducedTaxCodes []string
+	ExemptTaxCodes  []string
+	// ShippingTaxCode selects the rate applied to shipping fees; empty means standard.
+	ShippingTaxCode string
+}
+
+// JPConsumptionTaxCalculator implements TaxCalculator for Japan's 10% standard and 8% reduced
+// consumption tax. Taxable amounts are summed per rate before rounding, and shipments to
+// addresses outside Japan are zero-rated as exports.
+type JPConsumptionTaxCalculator struct {
+	inclusive    bool
+	rounding     string
+	codes        map[string]jpTaxCategory
+	shippingCode string
+}
+
+type jpTaxCategory struct {
+	name    string
+	percent int64
+}
+
+var (
+	jpStandardCategory = jpTaxCategory{name: "standard", percent: jpStandardRatePercent}
+	jpReducedCategory  = jpTaxCategory{name: "reduced", percent: jpReducedRatePercent}
+	jpExemptCategory   = jpTaxCategory{name: "non_taxable", percent: 0}
+)
+
+// NewJPConsumptionTaxCalculator validates the configuration and builds the calculator.
+func NewJPConsumptionTaxCalculator(cfg JPConsumptionTaxConfig) (*JPConsumptionTaxCalculator, error) {
+	rounding := strings.ToLower(strings.TrimSpace(cfg.RoundingMode))
+	switch rounding {
+	case "":
+		rounding = JPTaxRoundingFloor
+	case JPTaxRoundingFloor, JPTaxRoundingHalfUp, JPTaxRoundingCeiling:
+	default:
+		return nil, fmt.Errorf("jp tax calculator: unsupported rounding mode %q", cfg.RoundingMode)
+	}
+
+	codes := map[string]jpTaxCategory{
+		JPTaxCodeStandard: jpStandardCategory,
+		JPTaxCodeReduced:  jpReducedCategory,
+		JPTaxCodeExempt:   jpExemptCategory,
+	}
+	for _, code := range cfg.ReducedTaxCodes {
+		if key := normalizeTaxCode(code); key != "" {
+			codes[key] = jpReducedCategory
+		}
+	}
+	for _, code := range cfg.ExemptTaxCodes {
+		key := normalizeTaxCode(code)
+		if key == "" {
+			continue
+		}
+		if existing, ok := codes[key]; ok && existing == jpReducedCategory {
+			return nil, fmt.Errorf("jp tax calculator: tax code %q is both reduced and exempt", code)
+		}
+		codes[key] = jpExemptCategory
+	}
+
+	shippingCode := normalizeTaxCode(cfg.ShippingTaxCode)
+	if shippingCode != "" {
+		if _, ok := codes[shippingCode]; !ok {
+			return nil, fmt.Errorf("jp tax calculator: unknown shipping tax code %q", cfg.ShippingTaxCode)
+		}
+	}
+
+	return &JPConsumptionTaxCalculator{
+		inclusive:    cfg.PricesIncludeTax,
+		rounding:     rounding,
+		codes:        codes,
+		shippingCode: shippingCode,
+	}, nil
+}
+
+// CalculateTax sums the taxable amounts per rate and rounds each rate once. Inclusive price lists
+// extract the contained tax with the rate/(100+rate) formula and return an inclusive quote; for
+// exports the contained tax is returned as a deduction instead.
+func (c *JPConsumptionTaxCalculator) CalculateTax(_ context.Context, req TaxCalculationRequest) (TaxQuote, error) {
+	if c == nil {
+		return TaxQuote{}, errors.New("jp tax calculator: calculator is nil")
+	}
+
+	bases := make(map[jpTaxCategory]int64, 3)
+	add := func(category jpTaxCategory, amount int64) {
+		if amount > 0 {
+			bases[category] += amount
+		}
+	}
+	for _, item := range req.Items {
+		category, err := c.categoryFor(item.TaxCode)
+		if err != nil {
+			return TaxQuote{}, err
+		}
+		add(category, item.Subtotal-item.Discount)
+	}
+	shippingCategory, _ := c.categoryFor(c.shippingCode)
+	add(shippingCategory, req.ShippingAmount)
+
+	if isExportDestination(req.ShippingAddress) {
+		// Exports are zero-rated, so tax contained in inclusive prices is deducted from the total.
+		var taxable, contained int64
+		for category, base := range bases {
+			taxable += base
+			amount, err := c.taxFor(base, category.percent)
+			if err != nil {
+				return TaxQuote{}, err
+			}
+			contained += amount
+		}
+		metadata := map[string]any{
+			"taxableAmount": taxable,
+			"zeroRated":     true,
+			"reason":        "export",
+			"destination":   strings.ToUpper(strings.TrimSpace(req.ShippingAddress.Country)),
+		}
+		if c.inclusive {
+			metadata["deductedTax"] = contained
+		} else {
+			contained = 0
+		}
+		return TaxQuote{
+			Deduction: contained,
+			Breakdown: []TaxBreakdown{{
+				Name:         "consumption_tax_export",
+				Jurisdiction: jpJurisdiction,
+				Rate:         0,
+				Amount:       0,
+				Metadata:     metadata,
+			}},
+		}, nil
+	}
+
+	quote := TaxQuote{Inclusive: c.inclusive}
+	for _, category := range []jpTaxCategory{jpStandardCategory, jpReducedCategory, jpExemptCategory} {
+		base, ok := bases[category]
+		if !ok {
+			continue
+		}
+		amount, err := c.taxFor(base, category.percent)
+		if err != nil {
+			return TaxQuote{}, err
+		}
+		quote.Amount += amount
+		quote.Breakdown = append(quote.Breakdown, TaxBreakdown{
+			Name:         fmt.Sprintf("consumption_tax_%d", category.percent),
+			Jurisdiction: jpJurisdiction,
+			Rate:         float64(category.percent) / 100,
+			Amount:       amount,
+			Metadata: map[string]any{
+				"category":      category.name,
+				"taxableAmount": base,
+				"inclusive":     c.inclusive,
+			},
+		})
+	}
+	return quote, nil
+}
+
+func (c *JPConsumptionTaxCalculator) categoryFor(taxCode string) (jpTaxCategory, error) {
+	key := normalizeTaxCode(taxCode)
+	if key == "" {
+		return jpStandardCategory, nil
+	}
+	category, ok := c.codes[key]
+	if !ok {
+		return jpTaxCategory{}, detailf(ErrCartPricingInvalidInput, "unknown tax code %q", taxCode)
+	}
+	return category, nil
+}
+
+// taxFor computes the tax on a per-rate total. Exclusive amounts are taxed at rate/100; inclusive
+// amounts contain rate/(100+rate) of tax.
+func (c *JPConsumptionTaxCalculator) taxFor(base, percent int64) (int64, error) {
+	if base <= 0 || percent <= 0 {
+		return 0, nil
+	}
+	if base > math.MaxInt64/percent {
+		return 0, detailf(ErrCartPricingInvalidInput, "taxable amount overflow")
+	}
+	denominator := int64(100)
+	if c.inclusive {
+		denominator += percent
+	}
+	return roundQuotient(base*percent, denominator, c.rounding), nil
+}
+
+func roundQuotient(numerator, denominator int64, mode string) int64 {
+	quotient := numerator / denominator
+	remainder := numerator % denominator
+	switch mode {
+	case JPTaxRoundingHalfUp:
+		if remainder*2 >= denominator {
+			quotient++
+		}
+	case JPTaxRoundingCeiling:
+		if remainder > 0 {
+			quotient++
+		}
+	}
+	return quotient
+}
+
+func isExportDestination(addr *Address) bool {
+	if addr == nil {
+		return false
+	}
+	country := strings.ToUpper(strings.TrimSpace(addr.Country))
+	return country != "" && country != jpJurisdiction
+}
+
+func normalizeTaxCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
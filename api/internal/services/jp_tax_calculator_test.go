@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestJPConsumptionTaxCalculator_RoundsPerRate(t *testing.T) {
+	calc, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{ReducedTaxCodes: []string{"food"}})
+	if err != nil {
+		t.Fatalf("NewJPConsumptionTaxCalculator error: %v", err)
+	}
+
+	// Three reduced-rate lines of 105 yen would each round down to 8 yen (24 total) if rounded per
+	// line; rounding once per rate yields 25 yen.
+	quote, err := calc.CalculateTax(context.Background(), TaxCalculationRequest{
+		Currency: "JPY",
+		Items: []TaxableItem{
+			{ItemID: "a", Subtotal: 105, TaxCode: "food"},
+			{ItemID: "b", Subtotal: 105, TaxCode: "jp_reduced"},
+			{ItemID: "c", Subtotal: 105, TaxCode: "FOOD"},
+			{ItemID: "d", Subtotal: 3000, Discount: 500},
+			{ItemID: "e", Subtotal: 400, TaxCode: "jp_exempt"},
+		},
+		ShippingAmount:  800,
+		ShippingAddress: &Address{Country: "JP"},
+	})
+	if err != nil {
+		t.Fatalf("CalculateTax error: %v", err)
+	}
+	if quote.Inclusive {
+		t.Fatalf("expected exclusive quote")
+	}
+	if quote.Amount != 355 {
+		t.Fatalf("expected tax 355, got %d", quote.Amount)
+	}
+	if len(quote.Breakdown) != 3 {
+		t.Fatalf("expected three rate entries, got %+v", quote.Breakdown)
+	}
+	standard, reduced := quote.Breakdown[0], quote.Breakdown[1]
+	if standard.Name != "consumption_tax_10" || standard.Rate != 0.10 || standard.Amount != 330 || standard.Metadata["taxableAmount"] != int64(3300) {
+		t.Fatalf("unexpected standard entry %+v", standard)
+	}
+	if reduced.Name != "consumption_tax_8" || reduced.Rate != 0.08 || reduced.Amount != 25 || reduced.Metadata["taxableAmount"] != int64(315) {
+		t.Fatalf("unexpected reduced entry %+v", reduced)
+	}
+	if quote.Breakdown[2].Amount != 0 || quote.Breakdown[2].Metadata["category"] != "non_taxable" {
+		t.Fatalf("unexpected exempt entry %+v", quote.Breakdown[2])
+	}
+}
+
+func TestJPConsumptionTaxCalculator_InclusiveAndRounding(t *testing.T) {
+	cases := []struct {
+		name     string
+		rounding string
+		want     int64
+	}{
+		{name: "floor", want: 90},
+		{name: "half up", rounding: JPTaxRoundingHalfUp, want: 91},
+		{name: "ceiling", rounding: JPTaxRoundingCeiling, want: 91},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			calc, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{PricesIncludeTax: true, RoundingMode: tc.rounding})
+			if err != nil {
+				t.Fatalf("NewJPConsumptionTaxCalculator error: %v", err)
+			}
+			// 1000 yen including 10% tax contains 90.909... yen of tax.
+			quote, err := calc.CalculateTax(context.Background(), TaxCalculationRequest{
+				Currency: "JPY",
+				Items:    []TaxableItem{{ItemID: "a", Subtotal: 1000}},
+			})
+			if err != nil {
+				t.Fatalf("CalculateTax error: %v", err)
+			}
+			if !quote.Inclusive || quote.Amount != tc.want {
+				t.Fatalf("expected inclusive tax %d, got %+v", tc.want, quote)
+			}
+		})
+	}
+}
+
+func TestJPConsumptionTaxCalculator_ExportAndErrors(t *testing.T) {
+	calc, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{})
+	if err != nil {
+		t.Fatalf("NewJPConsumptionTaxCalculator error: %v", err)
+	}
+
+	quote, err := calc.CalculateTax(context.Background(), TaxCalculationRequest{
+		Currency:        "JPY",
+		Items:           []TaxableItem{{ItemID: "a", Subtotal: 5000}},
+		ShippingAmount:  2000,
+		ShippingAddress: &Address{Country: "us"},
+	})
+	if err != nil {
+		t.Fatalf("CalculateTax error: %v", err)
+	}
+	if quote.Amount != 0 || len(quote.Breakdown) != 1 || quote.Breakdown[0].Metadata["zeroRated"] != true || quote.Breakdown[0].Metadata["destination"] != "US" {
+		t.Fatalf("expected zero-rated export quote, got %+v", quote)
+	}
+
+	if _, err := calc.CalculateTax(context.Background(), TaxCalculationRequest{
+		Items: []TaxableItem{{ItemID: "a", Subtotal: 100, TaxCode: "alcohol"}},
+	}); !errors.Is(err, ErrCartPricingInvalidInput) {
+		t.Fatalf("expected invalid input for unknown tax code, got %v", err)
+	}
+
+	if _, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{RoundingMode: "banker"}); err == nil {
+		t.Fatalf("expected unsupported rounding mode error")
+	}
+	if _, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{ReducedTaxCodes: []string{"x"}, ExemptTaxCodes: []string{"x"}}); err == nil {
+		t.Fatalf("expected conflicting tax code error")
+	}
+}
+
+func TestCartPricingEngine_InclusiveTaxNotAddedToTotal(t *testing.T) {
+	calc, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{PricesIncludeTax: true})
+	if err != nil {
+		t.Fatalf("NewJPConsumptionTaxCalculator error: %v", err)
+	}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion: &fakePromotionService{},
+		Tax:       calc,
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+
+	res, err := engine.Calculate(context.Background(), PriceCartCommand{Cart: Cart{
+		Currency: "JPY",
+		Items: []CartItem{
+			{ID: "a", SKU: "a", Quantity: 2, UnitPrice: 1100, Currency: "JPY"},
+			{ID: "b", SKU: "b", Quantity: 1, UnitPrice: 1080, Currency: "JPY", TaxCode: JPTaxCodeReduced},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	if res.Breakdown.Tax != 280 {
+		t.Fatalf("expected contained tax 280, got %d", res.Breakdown.Tax)
+	}
+	if res.Breakdown.Total != 3280 {
+		t.Fatalf("expected total to equal inclusive subtotal 3280, got %d", res.Breakdown.Total)
+	}
+	if res.Breakdown.Metadata["taxInclusive"] != true {
+		t.Fatalf("expected taxInclusive metadata, got %+v", res.Breakdown.Metadata)
+	}
+	for _, item := range res.Breakdown.Items {
+		if item.Total != item.Subtotal {
+			t.Fatalf("expected item total to include tax once, got %+v", item)
+		}
+	}
+}
+
+func TestCartPricingEngine_InclusiveExportDeductsContainedTax(t *testing.T) {
+	calc, err := NewJPConsumptionTaxCalculator(JPConsumptionTaxConfig{PricesIncludeTax: true})
+	if err != nil {
+		t.Fatalf("NewJPConsumptionTaxCalculator error: %v", err)
+	}
+
+	quote, err := calc.CalculateTax(context.Background(), TaxCalculationRequest{
+		Currency:        "JPY",
+		Items:           []TaxableItem{{ItemID: "a", Subtotal: 2200}, {ItemID: "b", Subtotal: 1080, TaxCode: JPTaxCodeReduced}},
+		ShippingAddress: &Address{Country: "US"},
+	})
+	if err != nil {
+		t.Fatalf("CalculateTax error: %v", err)
+	}
+	if quote.Inclusive || quote.Amount != 0 || quote.Deduction != 280 || quote.Breakdown[0].Metadata["deductedTax"] != int64(280) {
+		t.Fatalf("expected contained tax returned as a deduction, got %+v", quote)
+	}
+
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion: &fakePromotionService{},
+		Tax:       calc,
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	res, err := engine.Calculate(context.Background(), PriceCartCommand{Cart: Cart{
+		Currency: "JPY",
+		Items: []CartItem{
+			{ID: "a", SKU: "a", Quantity: 2, UnitPrice: 1100, Currency: "JPY"},
+			{ID: "b", SKU: "b", Quantity: 1, UnitPrice: 1080, Currency: "JPY", TaxCode: JPTaxCodeReduced},
+		},
+		ShippingAddress: &Address{Country: "US"},
+	}})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	if res.Breakdown.Tax != 0 || res.Breakdown.Total != 3000 {
+		t.Fatalf("expected overseas buyer to pay the tax-exclusive 3000, got tax %d total %d", res.Breakdown.Tax, res.Breakdown.Total)
+	}
+	if res.Breakdown.Metadata["taxDeduction"] != int64(280) || res.Breakdown.Metadata["taxInclusive"] != nil {
+		t.Fatalf("unexpected metadata %+v", res.Breakdown.Metadata)
+	}
+	var itemTotal int64
+	for _, item := range res.Breakdown.Items {
+		itemTotal += item.Total
+	}
+	if itemTotal != res.Breakdown.Total {
+		t.Fatalf("expected item totals %d to sum to the cart total %d", itemTotal, res.Breakdown.Total)
+	}
+}
@@ -197,22 +197,20 @@ func NewOrderService(deps OrderServiceDeps) (OrderService, error) {
 
 func (s *orderService) CreateFromCart(ctx context.Context, cmd CreateOrderFromCartCommand) (Order, error) {
 	if len(cmd.Cart.Items) == 0 {
-		return Order{}, fmt.Errorf("%w: cart must contain at least one item", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "cart must contain at least one item")
 	}
 	userID := strings.TrimSpace(cmd.Cart.UserID)
 	if userID == "" {
-		return Order{}, fmt.Errorf("%w: cart user id is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "cart user id is required")
 	}
 	currency := strings.TrimSpace(cmd.Cart.Currency)
 	if currency == "" {
-		return Order{}, fmt.Errorf("%w: cart currency is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "cart currency is required")
 	}
 
-	for _, item := range cmd.Cart.Items {
-		itemCurrency := strings.TrimSpace(item.Currency)
-		if itemCurrency != "" && itemCurrency != currency {
-			return Order{}, fmt.Errorf("%w: cart item currency mismatch for sku %s", ErrOrderInvalidInput, strings.TrimSpace(item.SKU))
-		}
+	items, err := convertOrderItemCurrencies(cmd.Cart.Items, currency, cmd.ExchangeRates)
+	if err != nil {
+		return Order{}, err
 	}
 
 	now := s.now()
@@ -223,7 +221,7 @@ func (s *orderService) CreateFromCart(ctx context.Context, cmd CreateOrderFromCa
 		Status:          domain.OrderStatusPendingPayment,
 		Currency:        currency,
 		Totals:          buildOrderTotals(cmd.Cart),
-		Items:           buildOrderLineItems(cmd.Cart.Items),
+		Items:           buildOrderLineItems(items),
 		ShippingAddress: cloneAddress(cmd.Cart.ShippingAddress),
 		BillingAddress:  cloneAddress(cmd.Cart.BillingAddress),
 		Promotion:       clonePromotion(cmd.Cart.Promotion),
@@ -236,6 +234,10 @@ func (s *orderService) CreateFromCart(ctx context.Context, cmd CreateOrderFromCa
 		Flags:           OrderFlags{},
 	}
 
+	if len(cmd.ExchangeRates) > 0 {
+		order.ExchangeRates = append([]FXConversion(nil), cmd.ExchangeRates...)
+	}
+
 	if trimmed := strings.TrimSpace(cmd.Cart.ID); trimmed != "" {
 		order.CartRef = valuePtr(trimmed)
 	}
@@ -262,7 +264,7 @@ func (s *orderService) CreateFromCart(ctx context.Context, cmd CreateOrderFromCa
 		order.Audit.UpdatedBy = valuePtr(actor)
 	}
 
-	err := s.runInTx(ctx, func(txCtx context.Context) error {
+	err = s.runInTx(ctx, func(txCtx context.Context) error {
 		if cmd.ReservationID != "" && s.inventory != nil {
 			if _, err := s.inventory.CommitReservation(txCtx, InventoryCommitCommand{
 				ReservationID: cmd.ReservationID,
@@ -304,7 +306,7 @@ func (s *orderService) ListOrders(ctx context.Context, filter OrderListFilter) (
 func (s *orderService) GetOrder(ctx context.Context, orderID string, opts OrderReadOptions) (Order, error) {
 	orderID = strings.TrimSpace(orderID)
 	if orderID == "" {
-		return Order{}, fmt.Errorf("%w: order id is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "order id is required")
 	}
 
 	order, err := s.orders.FindByID(ctx, orderID)
@@ -353,13 +355,13 @@ func (s *orderService) TransitionStatus(ctx context.Context, cmd OrderStatusTran
 	target := normalizeStatus(cmd.TargetStatus)
 
 	if orderID == "" {
-		return Order{}, fmt.Errorf("%w: order id is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "order id is required")
 	}
 	if target == "" {
-		return Order{}, fmt.Errorf("%w: target status is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "target status is required")
 	}
 	if _, ok := validOrderStatuses[target]; !ok {
-		return Order{}, fmt.Errorf("%w: target status %q is not supported", ErrOrderInvalidInput, target)
+		return Order{}, detailf(ErrOrderInvalidInput, "target status %q is not supported", target)
 	}
 
 	order, err := s.orders.FindByID(ctx, orderID)
@@ -370,7 +372,7 @@ func (s *orderService) TransitionStatus(ctx context.Context, cmd OrderStatusTran
 	if cmd.ExpectedStatus != nil {
 		expected := normalizeStatus(*cmd.ExpectedStatus)
 		if expected != "" && order.Status != expected {
-			return Order{}, fmt.Errorf("%w: expected status %q but was %q", ErrOrderConflict, expected, order.Status)
+			return Order{}, detailf(ErrOrderConflict, "expected status %q but was %q", expected, order.Status)
 		}
 	}
 
@@ -415,7 +417,7 @@ func (s *orderService) TransitionStatus(ctx context.Context, cmd OrderStatusTran
 func (s *orderService) Cancel(ctx context.Context, cmd CancelOrderCommand) (Order, error) {
 	orderID := strings.TrimSpace(cmd.OrderID)
 	if orderID == "" {
-		return Order{}, fmt.Errorf("%w: order id is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "order id is required")
 	}
 
 	order, err := s.orders.FindByID(ctx, orderID)
@@ -424,13 +426,13 @@ func (s *orderService) Cancel(ctx context.Context, cmd CancelOrderCommand) (Orde
 	}
 
 	if !slices.Contains(cancellableStatuses, order.Status) {
-		return Order{}, fmt.Errorf("%w: order status %q cannot be canceled", ErrOrderInvalidState, order.Status)
+		return Order{}, detailf(ErrOrderInvalidState, "order status %q cannot be canceled", order.Status)
 	}
 
 	if cmd.ExpectedStatus != nil {
 		expected := normalizeStatus(*cmd.ExpectedStatus)
 		if expected != "" && order.Status != expected {
-			return Order{}, fmt.Errorf("%w: expected status %q but was %q", ErrOrderConflict, expected, order.Status)
+			return Order{}, detailf(ErrOrderConflict, "expected status %q but was %q", expected, order.Status)
 		}
 	}
 
@@ -458,6 +460,16 @@ func (s *orderService) Cancel(ctx context.Context, cmd CancelOrderCommand) (Orde
 				return err
 			}
 		}
+		if s.inventory != nil && strings.TrimSpace(cmd.RestockReservationID) != "" {
+			if _, err := s.inventory.RestockReservation(txCtx, InventoryRestockCommand{
+				ReservationID: strings.TrimSpace(cmd.RestockReservationID),
+				OrderID:       order.ID,
+				Reason:        reason,
+				ActorID:       cmd.ActorID,
+			}); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -473,6 +485,10 @@ func (s *orderService) Cancel(ctx context.Context, cmd CancelOrderCommand) (Orde
 		metadata = ensureMap(metadata)
 		metadata["reservationId"] = strings.TrimSpace(cmd.ReservationID)
 	}
+	if cmd.RestockReservationID != "" {
+		metadata = ensureMap(metadata)
+		metadata["restockedReservationId"] = strings.TrimSpace(cmd.RestockReservationID)
+	}
 
 	s.publishEvent(ctx, OrderEvent{
 		Type:           orderEventStatusChanged,
@@ -494,15 +510,15 @@ func (s *orderService) AppendProductionEvent(ctx context.Context, cmd AppendProd
 	}
 	orderID := strings.TrimSpace(cmd.OrderID)
 	if orderID == "" {
-		return OrderProductionEvent{}, fmt.Errorf("%w: order id is required", ErrOrderInvalidInput)
+		return OrderProductionEvent{}, detailf(ErrOrderInvalidInput, "order id is required")
 	}
 
 	eventType := strings.TrimSpace(cmd.Event.Type)
 	if eventType == "" {
-		return OrderProductionEvent{}, fmt.Errorf("%w: event type is required", ErrOrderInvalidInput)
+		return OrderProductionEvent{}, detailf(ErrOrderInvalidInput, "event type is required")
 	}
 	if _, ok := validProductionEventTypes[eventType]; !ok {
-		return OrderProductionEvent{}, fmt.Errorf("%w: production event type %q is not supported", ErrOrderInvalidInput, eventType)
+		return OrderProductionEvent{}, detailf(ErrOrderInvalidInput, "production event type %q is not supported", eventType)
 	}
 
 	order, err := s.orders.FindByID(ctx, orderID)
@@ -605,7 +621,7 @@ func (s *orderService) AppendProductionEvent(ctx context.Context, cmd AppendProd
 func (s *orderService) RequestInvoice(ctx context.Context, cmd RequestInvoiceCommand) (Order, error) {
 	orderID := strings.TrimSpace(cmd.OrderID)
 	if orderID == "" {
-		return Order{}, fmt.Errorf("%w: order id is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "order id is required")
 	}
 
 	order, err := s.orders.FindByID(ctx, orderID)
@@ -616,7 +632,7 @@ func (s *orderService) RequestInvoice(ctx context.Context, cmd RequestInvoiceCom
 	if cmd.ExpectedStatus != nil {
 		expected := normalizeStatus(*cmd.ExpectedStatus)
 		if expected != "" && order.Status != expected {
-			return Order{}, fmt.Errorf("%w: expected status %q but was %q", ErrOrderConflict, expected, order.Status)
+			return Order{}, detailf(ErrOrderConflict, "expected status %q but was %q", expected, order.Status)
 		}
 	}
 
@@ -661,7 +677,7 @@ func (s *orderService) RequestInvoice(ctx context.Context, cmd RequestInvoiceCom
 func (s *orderService) CloneForReorder(ctx context.Context, cmd CloneForReorderCommand) (Order, error) {
 	orderID := strings.TrimSpace(cmd.OrderID)
 	if orderID == "" {
-		return Order{}, fmt.Errorf("%w: order id is required", ErrOrderInvalidInput)
+		return Order{}, detailf(ErrOrderInvalidInput, "order id is required")
 	}
 
 	source, err := s.orders.FindByID(ctx, orderID)
@@ -670,7 +686,7 @@ func (s *orderService) CloneForReorder(ctx context.Context, cmd CloneForReorderC
 	}
 
 	if !slices.Contains([]domain.OrderStatus{domain.OrderStatusDelivered, domain.OrderStatusCompleted}, source.Status) {
-		return Order{}, fmt.Errorf("%w: reorder only allowed from delivered/completed orders", ErrOrderInvalidState)
+		return Order{}, detailf(ErrOrderInvalidState, "reorder only allowed from delivered/completed orders")
 	}
 
 	now := s.now()
@@ -738,7 +754,7 @@ func (s *orderService) applyStatusTransition(order *Order, target domain.OrderSt
 	target = normalizeStatus(target)
 
 	if target == "" {
-		return current, fmt.Errorf("%w: target status is required", ErrOrderInvalidInput)
+		return current, detailf(ErrOrderInvalidInput, "target status is required")
 	}
 
 	if current == target {
@@ -750,7 +766,7 @@ func (s *orderService) applyStatusTransition(order *Order, target domain.OrderSt
 	}
 
 	if !canTransition(current, target) {
-		return current, fmt.Errorf("%w: %s → %s", ErrOrderInvalidState, current, target)
+		return current, detailf(ErrOrderInvalidState, "%s → %s", current, target)
 	}
 
 	order.Status = target
@@ -882,6 +898,43 @@ func buildOrderTotals(cart Cart) OrderTotals {
 	}
 }
 
+// convertOrderItemCurrencies converts items quoted in another currency at the rate recorded when
+// the cart was priced. Items in a currency without a recorded rate are rejected.
+func convertOrderItemCurrencies(items []CartItem, currency string, rates []FXConversion) ([]CartItem, error) {
+	converted := make([]CartItem, len(items))
+	for idx, item := range items {
+		itemCurrency := strings.TrimSpace(item.Currency)
+		if itemCurrency == "" || itemCurrency == currency {
+			converted[idx] = item
+			continue
+		}
+		conv, ok := findConversion(rates, itemCurrency, currency)
+		if !ok {
+			return nil, detailf(ErrOrderInvalidInput, "cart item currency mismatch for sku %s", strings.TrimSpace(item.SKU))
+		}
+		unitPrice, err := ConvertWithRecordedRate(conv, item.UnitPrice)
+		if err != nil {
+			return nil, fmt.Errorf("%w: convert sku %s: %v", ErrOrderInvalidInput, strings.TrimSpace(item.SKU), err)
+		}
+		item.Metadata = ensureMap(cloneMap(item.Metadata))
+		item.Metadata["originalUnitPrice"] = item.UnitPrice
+		item.Metadata["originalCurrency"] = conv.From
+		item.UnitPrice = unitPrice
+		item.Currency = currency
+		converted[idx] = item
+	}
+	return converted, nil
+}
+
+func findConversion(rates []FXConversion, from, to string) (FXConversion, bool) {
+	for _, conv := range rates {
+		if strings.EqualFold(conv.From, from) && strings.EqualFold(conv.To, to) {
+			return conv, true
+		}
+	}
+	return FXConversion{}, false
+}
+
 func buildOrderLineItems(items []CartItem) []OrderLineItem {
 	lines := make([]OrderLineItem, 0, len(items))
 	for _, item := range items {
@@ -82,6 +82,8 @@ func (s *stubCounterRepo) Configure(context.Context, string, repositories.Counte
 type stubInventoryService struct {
 	commitFn  func(context.Context, InventoryCommitCommand) (InventoryReservation, error)
 	releaseFn func(context.Context, InventoryReleaseCommand) (InventoryReservation, error)
+	restockFn func(context.Context, InventoryRestockCommand) ([]InventoryStockEvent, error)
+	expiredFn func(context.Context, ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error)
 }
 
 func (s *stubInventoryService) ReserveStocks(context.Context, InventoryReserveCommand) (InventoryReservation, error) {
@@ -102,10 +104,48 @@ func (s *stubInventoryService) ReleaseReservation(ctx context.Context, cmd Inven
 	return InventoryReservation{}, nil
 }
 
+func (s *stubInventoryService) RestockReservation(ctx context.Context, cmd InventoryRestockCommand) ([]InventoryStockEvent, error) {
+	if s.restockFn != nil {
+		return s.restockFn(ctx, cmd)
+	}
+	return nil, nil
+}
+
 func (s *stubInventoryService) ListLowStock(context.Context, InventoryLowStockFilter) (domain.CursorPage[InventorySnapshot], error) {
 	return domain.CursorPage[InventorySnapshot]{}, errors.New("not implemented")
 }
 
+func (s *stubInventoryService) ReleaseExpiredReservations(ctx context.Context, cmd ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error) {
+	if s.expiredFn != nil {
+		return s.expiredFn(ctx, cmd)
+	}
+	return ReleaseExpiredReservationsResult{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) AdjustStock(context.Context, InventoryAdjustCommand) (InventoryStockEvent, error) {
+	return InventoryStockEvent{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListStockEvents(context.Context, InventoryStockEventFilter) (domain.CursorPage[InventoryStockEvent], error) {
+	return domain.CursorPage[InventoryStockEvent]{}, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListLocationStocks(context.Context, string) ([]InventoryStock, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) TransferStock(context.Context, InventoryTransferCommand) ([]InventoryStockEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) ListLocations(context.Context) ([]InventoryLocation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubInventoryService) UpsertLocation(context.Context, InventoryLocation) (InventoryLocation, error) {
+	return InventoryLocation{}, errors.New("not implemented")
+}
+
 type captureOrderEvents struct {
 	events []OrderEvent
 }
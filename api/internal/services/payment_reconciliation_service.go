@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+const (
+	defaultReconcileBatchSize = 100
+	maxReconcileBatchSize     = 500
+	defaultReconcileLimit     = 1000
+	maxReconcileWindow        = 31 * 24 * time.Hour
+
+	auditActionPaymentReconcileHeal = "payment.reconcile.heal"
+	auditActionPaymentReconcileRun  = "payment.reconcile.run"
+
+	paymentFieldStatus   = "status"
+	paymentFieldAmount   = "amount"
+	paymentFieldCurrency = "currency"
+	paymentFieldCaptured = "captured"
+	paymentFieldRefunded = "refunded"
+)
+
+// ErrPaymentReconciliationInvalidInput signals an invalid reconciliation request.
+var ErrPaymentReconciliationInvalidInput = errors.New("payment reconciliation: invalid input")
+
+// PaymentProviderLookup resolves the PSP view of a payment. *payments.Manager satisfies it.
+type PaymentProviderLookup interface {
+	LookupPayment(ctx context.Context, paymentCtx payments.PaymentContext, req payments.LookupRequest) (payments.PaymentDetails, error)
+}
+
+// PaymentReconciliationServiceDeps bundles the collaborators required by the reconciliation job.
+type PaymentReconciliationServiceDeps struct {
+	Payments     repositories.OrderPaymentRepository
+	Transactions repositories.PaymentTransactionRepository
+	Provider     PaymentProviderLookup
+	UnitOfWork   repositories.UnitOfWork
+	Audit        AuditLogService
+	Clock        func() time.Time
+	Logger       func(ctx context.Context, event string, fields map[string]any)
+}
+
+type paymentReconciliationService struct {
+	payments     repositories.OrderPaymentRepository
+	transactions repositories.PaymentTransactionRepository
+	provider     PaymentProviderLookup
+	unitOfWork   repositories.UnitOfWork
+	audit        AuditLogService
+	clock        func() time.Time
+	logger       func(context.Context, string, map[string]any)
+}
+
+// NewPaymentReconciliationService wires dependencies into a PaymentReconciliationService.
+func NewPaymentReconciliationService(deps PaymentReconciliationServiceDeps) (PaymentReconciliationService, error) {
+	if deps.Payments == nil {
+		return nil, errors.New("payment reconciliation service: payment repository is required")
+	}
+	if deps.Transactions == nil {
+		return nil, errors.New("payment reconciliation service: transaction repository is required")
+	}
+	if deps.Provider == nil {
+		return nil, errors.New("payment reconciliation service: payment provider is required")
+	}
+
+	unit := deps.UnitOfWork
+	if unit == nil {
+		unit = noopUnitOfWork{}
+	}
+	clock := deps.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+
+	return &paymentReconciliationService{
+		payments:     deps.Payments,
+		transactions: deps.Transactions,
+		provider:     deps.Provider,
+		unitOfWork:   unit,
+		audit:        deps.Audit,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		logger: logger,
+	}, nil
+}
+
+// Reconcile walks payments created in the requested window, oldest first, and compares each with the
+// PSP. Lookup failures are reported in FailedIDs without aborting the run.
+func (s *paymentReconciliationService) Reconcile(ctx context.Context, cmd ReconcilePaymentsCommand) (PaymentReconciliationReport, error) {
+	from := cmd.From.UTC()
+	to := cmd.To.UTC()
+	if from.IsZero() || to.IsZero() {
+		return PaymentReconciliationReport{}, detailf(ErrPaymentReconciliationInvalidInput, "from and to are required")
+	}
+	if !from.Before(to) {
+		return PaymentReconciliationReport{}, detailf(ErrPaymentReconciliationInvalidInput, "from must be before to")
+	}
+	if to.Sub(from) > maxReconcileWindow {
+		return PaymentReconciliationReport{}, detailf(ErrPaymentReconciliationInvalidInput, "range must not exceed %d days", int(maxReconcileWindow.Hours()/24))
+	}
+
+	batchSize := cmd.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultReconcileBatchSize
+	}
+	if batchSize > maxReconcileBatchSize {
+		batchSize = maxReconcileBatchSize
+	}
+	limit := cmd.Limit
+	if limit <= 0 {
+		limit = defaultReconcileLimit
+	}
+	provider := strings.ToLower(strings.TrimSpace(cmd.Provider))
+	actor := strings.TrimSpace(cmd.ActorID)
+
+	report := PaymentReconciliationReport{From: from, To: to}
+	pageToken := ""
+	for report.CheckedCount < limit {
+		pageSize := batchSize
+		if remaining := limit - report.CheckedCount; remaining < pageSize {
+			pageSize = remaining
+		}
+
+		page, err := s.payments.ListByDateRange(ctx, repositories.PaymentListFilter{
+			Provider:   provider,
+			DateRange:  domain.RangeQuery[time.Time]{From: &from, To: &to},
+			Pagination: domain.Pagination{PageSize: pageSize, PageToken: pageToken},
+		})
+		if err != nil {
+			return report, err
+		}
+
+		for _, payment := range page.Items {
+			report.CheckedCount++
+			s.reconcileOne(ctx, payment, cmd.AutoHeal, actor, &report)
+		}
+
+		pageToken = strings.TrimSpace(page.NextPageToken)
+		if pageToken == "" || len(page.Items) == 0 {
+			break
+		}
+	}
+
+	report.HasMore = pageToken != ""
+	report.GeneratedAt = s.clock()
+	s.recordRun(ctx, actor, provider, cmd.AutoHeal, report)
+	return report, nil
+}
+
+func (s *paymentReconciliationService) reconcileOne(ctx context.Context, payment domain.Payment, autoHeal bool, actor string, report *PaymentReconciliationReport) {
+	if strings.TrimSpace(payment.IntentID) == "" {
+		report.FailedIDs = append(report.FailedIDs, payment.ID)
+		s.logger(ctx, "payment_reconcile_missing_intent", map[string]any{"paymentId": payment.ID})
+		return
+	}
+
+	details, err := s.provider.LookupPayment(ctx, payments.PaymentContext{
+		PreferredProvider: payment.Provider,
+		Currency:          payment.Currency,
+	}, payments.LookupRequest{IntentID: payment.IntentID})
+	if err != nil {
+		report.FailedIDs = append(report.FailedIDs, payment.ID)
+		s.logger(ctx, "payment_reconcile_lookup_failed", map[string]any{
+			"paymentId": payment.ID,
+			"intentId":  payment.IntentID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	txs, err := s.transactions.List(ctx, payment.OrderID, payment.ID)
+	if err != nil {
+		report.FailedIDs = append(report.FailedIDs, payment.ID)
+		s.logger(ctx, "payment_reconcile_ledger_failed", map[string]any{
+			"paymentId": payment.ID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	mismatches := comparePayment(payment, applyPaymentLedger(payment, txs), details)
+	if len(mismatches) == 0 {
+		report.MatchedCount++
+		return
+	}
+
+	discrepancy := PaymentDiscrepancy{
+		PaymentID:  payment.ID,
+		OrderID:    payment.OrderID,
+		Provider:   payment.Provider,
+		IntentID:   payment.IntentID,
+		Mismatches: mismatches,
+	}
+	if autoHeal {
+		if err := s.heal(ctx, payment, txs, details, mismatches, actor); err != nil {
+			s.logger(ctx, "payment_reconcile_heal_failed", map[string]any{
+				"paymentId": payment.ID,
+				"error":     err.Error(),
+			})
+			report.FailedIDs = append(report.FailedIDs, payment.ID)
+		} else {
+			discrepancy.Healed = true
+			report.HealedCount++
+		}
+	}
+	report.Discrepancies = append(report.Discrepancies, discrepancy)
+}
+
+// comparePayment reports the fields on which the payment differs from the PSP. Refunds are compared by
+// the amount derived from the ledger, so partial refunds missing on either side are caught.
+func comparePayment(payment, ledger domain.Payment, details payments.PaymentDetails) []PaymentFieldMismatch {
+	var mismatches []PaymentFieldMismatch
+	recordedStatus := strings.ToLower(strings.TrimSpace(payment.Status))
+	if recordedStatus != string(details.Status) {
+		mismatches = append(mismatches, PaymentFieldMismatch{Field: paymentFieldStatus, Recorded: recordedStatus, Provider: string(details.Status)})
+	}
+	if details.Amount != 0 && payment.Amount != details.Amount {
+		mismatches = append(mismatches, PaymentFieldMismatch{Field: paymentFieldAmount, Recorded: payment.Amount, Provider: details.Amount})
+	}
+	if details.Currency != "" && !strings.EqualFold(payment.Currency, details.Currency) {
+		mismatches = append(mismatches, PaymentFieldMismatch{Field: paymentFieldCurrency, Recorded: payment.Currency, Provider: details.Currency})
+	}
+	if payment.Captured != details.Captured {
+		mismatches = append(mismatches, PaymentFieldMismatch{Field: paymentFieldCaptured, Recorded: payment.Captured, Provider: details.Captured})
+	}
+	if remote, known := providerRefundedAmount(ledger.AmountCaptured, details); known {
+		if ledger.AmountRefunded != remote {
+			mismatches = append(mismatches, PaymentFieldMismatch{Field: paymentFieldRefunded, Recorded: ledger.AmountRefunded, Provider: remote})
+		}
+	} else if recorded, remote := payment.RefundedAt != nil, details.RefundedAt != nil; recorded != remote {
+		mismatches = append(mismatches, PaymentFieldMismatch{Field: paymentFieldRefunded, Recorded: recorded, Provider: remote})
+	}
+	return mismatches
+}
+
+// providerRefundedAmount returns the amount the PSP reports as refunded. A PSP that marks the payment
+// refunded without an amount is taken to have refunded the captured amount in full; any other refund
+// without an amount is unknown.
+func providerRefundedAmount(captured int64, details payments.PaymentDetails) (int64, bool) {
+	switch {
+	case details.AmountRefunded > 0:
+		return details.AmountRefunded, true
+	case details.RefundedAt == nil:
+		return 0, true
+	case details.Status == payments.StatusRefunded && captured > 0:
+		return captured, true
+	default:
+		return 0, false
+	}
+}
+
+// heal brings the payment in line with the PSP. Balances are derived from the ledger, so missing
+// captures and refunds are appended as corrective transactions rather than written onto the payment;
+// drift that could only be undone by reversing recorded entries is left for manual review.
+func (s *paymentReconciliationService) heal(ctx context.Context, payment domain.Payment, txs []domain.PaymentTransaction, details payments.PaymentDetails, mismatches []PaymentFieldMismatch, actor string) error {
+	balances := applyPaymentLedger(payment, txs)
+	now := s.clock()
+
+	healed := payment
+	for _, mismatch := range mismatches {
+		switch mismatch.Field {
+		case paymentFieldStatus:
+			healed.Status = string(details.Status)
+		case paymentFieldAmount:
+			healed.Amount = details.Amount
+		case paymentFieldCurrency:
+			healed.Currency = strings.ToUpper(details.Currency)
+		}
+	}
+	if details.Raw != nil {
+		healed.Raw = details.Raw
+	}
+
+	var added []domain.PaymentTransaction
+	captured := balances.AmountCaptured
+	if details.Captured && captured == 0 {
+		amount := details.Amount
+		if amount == 0 {
+			amount = payment.Amount
+		}
+		occurredAt := now
+		if details.CapturedAt != nil {
+			occurredAt = details.CapturedAt.UTC()
+		}
+		added = append(added, s.correctiveTransaction(healed, domain.PaymentTransactionCapture, amount, len(txs), occurredAt, actor))
+		captured = amount
+	}
+	remoteRefunded, known := providerRefundedAmount(captured, details)
+	if !known {
+		return fmt.Errorf("payment %s: provider reports a refund without an amount", payment.ID)
+	}
+	if len(txs) == 0 && (len(added) > 0 || remoteRefunded != balances.AmountRefunded) {
+		added = append(legacyPaymentTransactions(payment), added...)
+	}
+	recordedRefunded := ledgerRefunded(added, txs)
+	if missing := remoteRefunded - recordedRefunded; missing > 0 && captured > 0 {
+		occurredAt := now
+		if details.RefundedAt != nil {
+			occurredAt = details.RefundedAt.UTC()
+		}
+		added = append(added, s.correctiveTransaction(healed, domain.PaymentTransactionRefund, missing, len(txs), occurredAt, actor))
+	}
+
+	result := applyPaymentLedger(healed, append(append([]domain.PaymentTransaction(nil), txs...), added...))
+	if result.Status != string(details.Status) || result.Captured != details.Captured || result.AmountRefunded != remoteRefunded {
+		return fmt.Errorf("payment %s: ledger cannot be healed without reversing recorded entries", payment.ID)
+	}
+	result.UpdatedAt = now
+
+	persisted := result
+	persisted.Transactions = nil
+	if err := s.unitOfWork.RunInTx(ctx, func(txCtx context.Context) error {
+		for _, tx := range added {
+			if err := s.transactions.Insert(txCtx, tx); err != nil {
+				return err
+			}
+		}
+		return s.payments.Update(txCtx, persisted)
+	}); err != nil {
+		return err
+	}
+
+	if s.audit == nil {
+		return nil
+	}
+	diff := make(map[string]AuditLogDiff, len(mismatches))
+	for _, mismatch := range mismatches {
+		diff[mismatch.Field] = AuditLogDiff{Before: mismatch.Recorded, After: mismatch.Provider}
+	}
+	s.audit.Record(ctx, AuditLogRecord{
+		Actor:      actor,
+		Action:     auditActionPaymentReconcileHeal,
+		TargetRef:  fmt.Sprintf("/orders/%s/payments/%s", payment.OrderID, payment.ID),
+		OccurredAt: now,
+		Diff:       diff,
+		Metadata: map[string]any{
+			"service":      "payment_reconciliation",
+			"provider":     payment.Provider,
+			"intentId":     payment.IntentID,
+			"transactions": transactionIDs(added),
+		},
+	})
+	return nil
+}
+
+// correctiveTransaction builds a ledger entry keyed by the ledger length it was derived from, so two
+// runs healing the same drift concurrently conflict instead of double counting.
+func (s *paymentReconciliationService) correctiveTransaction(payment domain.Payment, txType domain.PaymentTransactionType, amount int64, ledgerLen int, occurredAt time.Time, actor string) domain.PaymentTransaction {
+	return domain.PaymentTransaction{
+		ID:           fmt.Sprintf("%sreconcile_%s_%s_%d", paymentTransactionIDPrefix, txType, payment.ID, ledgerLen),
+		PaymentID:    payment.ID,
+		OrderID:      payment.OrderID,
+		Type:         txType,
+		Amount:       amount,
+		Currency:     strings.ToUpper(payment.Currency),
+		PSPReference: payment.IntentID,
+		Reason:       "reconciliation",
+		ActorRef:     actor,
+		OccurredAt:   occurredAt,
+		CreatedAt:    s.clock(),
+	}
+}
+
+// ledgerRefunded sums the refunds recorded in txs and about to be added.
+func ledgerRefunded(added, txs []domain.PaymentTransaction) int64 {
+	var total int64
+	for _, list := range [][]domain.PaymentTransaction{txs, added} {
+		for _, tx := range list {
+			if tx.Type == domain.PaymentTransactionRefund {
+				total += tx.Amount
+			}
+		}
+	}
+	return total
+}
+
+func transactionIDs(txs []domain.PaymentTransaction) []string {
+	ids := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		ids = append(ids, tx.ID)
+	}
+	return ids
+}
+
+func (s *paymentReconciliationService) recordRun(ctx context.Context, actor, provider string, autoHeal bool, report PaymentReconciliationReport) {
+	if s.audit == nil {
+		return
+	}
+	metadata := map[string]any{
+		"service":       "payment_reconciliation",
+		"from":          report.From.Format(time.RFC3339),
+		"to":            report.To.Format(time.RFC3339),
+		"autoHeal":      autoHeal,
+		"checked":       report.CheckedCount,
+		"matched":       report.MatchedCount,
+		"discrepancies": len(report.Discrepancies),
+		"healed":        report.HealedCount,
+		"failed":        len(report.FailedIDs),
+		"hasMore":       report.HasMore,
+	}
+	if provider != "" {
+		metadata["provider"] = provider
+	}
+	s.audit.Record(ctx, AuditLogRecord{
+		Actor:      actor,
+		Action:     auditActionPaymentReconcileRun,
+		TargetRef:  "/payments",
+		OccurredAt: report.GeneratedAt,
+		Metadata:   metadata,
+	})
+}
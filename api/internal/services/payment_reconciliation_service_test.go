@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+type memoryPaymentRepo struct {
+	payments []domain.Payment
+	updated  []domain.Payment
+	filters  []repositories.PaymentListFilter
+}
+
+func (r *memoryPaymentRepo) Insert(_ context.Context, payment domain.Payment) error {
+	r.payments = append(r.payments, payment)
+	return nil
+}
+
+func (r *memoryPaymentRepo) Update(_ context.Context, payment domain.Payment) error {
+	r.updated = append(r.updated, payment)
+	for i := range r.payments {
+		if r.payments[i].ID == payment.ID {
+			r.payments[i] = payment
+		}
+	}
+	return nil
+}
+
+func (r *memoryPaymentRepo) List(_ context.Context, orderID string) ([]domain.Payment, error) {
+	var out []domain.Payment
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			out = append(out, payment)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryPaymentRepo) FindByIntent(_ context.Context, provider, intentID string) (domain.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.Provider == provider && payment.IntentID == intentID {
+			return payment, nil
+		}
+	}
+	return domain.Payment{}, repoError{message: "payment not found", notFound: true}
+}
+
+func (r *memoryPaymentRepo) ListByDateRange(_ context.Context, filter repositories.PaymentListFilter) (domain.CursorPage[domain.Payment], error) {
+	r.filters = append(r.filters, filter)
+	var matched []domain.Payment
+	for _, payment := range r.payments {
+		if filter.Provider != "" && payment.Provider != filter.Provider {
+			continue
+		}
+		if filter.DateRange.From != nil && payment.CreatedAt.Before(*filter.DateRange.From) {
+			continue
+		}
+		if filter.DateRange.To != nil && !payment.CreatedAt.Before(*filter.DateRange.To) {
+			continue
+		}
+		matched = append(matched, payment)
+	}
+	offset := 0
+	if filter.Pagination.PageToken != "" {
+		offset, _ = strconv.Atoi(filter.Pagination.PageToken)
+	}
+	end := offset + filter.Pagination.PageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page := domain.CursorPage[domain.Payment]{Items: matched[offset:end]}
+	if end < len(matched) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+type stubPaymentLookup struct {
+	details map[string]payments.PaymentDetails
+}
+
+func (s *stubPaymentLookup) LookupPayment(_ context.Context, _ payments.PaymentContext, req payments.LookupRequest) (payments.PaymentDetails, error) {
+	details, ok := s.details[req.IntentID]
+	if !ok {
+		return payments.PaymentDetails{}, errors.New("psp: no such intent")
+	}
+	return details, nil
+}
+
+type recordingAuditService struct {
+	records []AuditLogRecord
+}
+
+func (s *recordingAuditService) Record(_ context.Context, record AuditLogRecord) {
+	s.records = append(s.records, record)
+}
+
+func (s *recordingAuditService) List(context.Context, AuditLogFilter) (domain.CursorPage[domain.AuditLogEntry], error) {
+	return domain.CursorPage[domain.AuditLogEntry]{}, nil
+}
+
+func TestPaymentReconciliationService_ReportsAndHealsDrift(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	refundedAt := start.Add(2 * time.Hour)
+	repo := &memoryPaymentRepo{payments: []domain.Payment{
+		{ID: "pay_match", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "succeeded", Amount: 1000, Currency: "JPY", Captured: true, CreatedAt: start.Add(time.Hour)},
+		{ID: "pay_drift", OrderID: "ord_2", Provider: "stripe", IntentID: "pi_2", Status: "succeeded", Amount: 2000, Currency: "JPY", Captured: true, CreatedAt: start.Add(2 * time.Hour)},
+		{ID: "pay_missing", OrderID: "ord_3", Provider: "stripe", IntentID: "pi_3", Status: "pending", Amount: 500, Currency: "JPY", CreatedAt: start.Add(3 * time.Hour)},
+		{ID: "pay_outside", OrderID: "ord_4", Provider: "stripe", IntentID: "pi_4", Status: "pending", Amount: 500, Currency: "JPY", CreatedAt: start.Add(48 * time.Hour)},
+	}}
+	lookup := &stubPaymentLookup{details: map[string]payments.PaymentDetails{
+		"pi_1": {IntentID: "pi_1", Status: payments.StatusSucceeded, Amount: 1000, Currency: "JPY", Captured: true},
+		"pi_2": {IntentID: "pi_2", Status: payments.StatusRefunded, Amount: 2500, Currency: "JPY", Captured: true, RefundedAt: &refundedAt},
+	}}
+	audit := &recordingAuditService{}
+	ledger := &memoryTransactionRepo{}
+
+	svc, err := NewPaymentReconciliationService(PaymentReconciliationServiceDeps{
+		Payments:     repo,
+		Transactions: ledger,
+		Provider:     lookup,
+		Audit:        audit,
+		Clock:        func() time.Time { return start.Add(72 * time.Hour) },
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	report, err := svc.Reconcile(context.Background(), ReconcilePaymentsCommand{
+		From:      start,
+		To:        start.Add(24 * time.Hour),
+		AutoHeal:  true,
+		ActorID:   "staff_1",
+		BatchSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	if report.CheckedCount != 3 || report.MatchedCount != 1 || report.HealedCount != 1 || report.HasMore {
+		t.Fatalf("unexpected report counts: %+v", report)
+	}
+	if len(report.FailedIDs) != 1 || report.FailedIDs[0] != "pay_missing" {
+		t.Fatalf("expected lookup failure for pay_missing, got %v", report.FailedIDs)
+	}
+	if len(report.Discrepancies) != 1 {
+		t.Fatalf("expected one discrepancy, got %d", len(report.Discrepancies))
+	}
+	discrepancy := report.Discrepancies[0]
+	fields := map[string]bool{}
+	for _, mismatch := range discrepancy.Mismatches {
+		fields[mismatch.Field] = true
+	}
+	if !discrepancy.Healed || !fields["status"] || !fields["amount"] || !fields["refunded"] || fields["captured"] {
+		t.Fatalf("unexpected discrepancy: %+v", discrepancy)
+	}
+
+	if len(repo.updated) != 1 {
+		t.Fatalf("expected one healed payment, got %d", len(repo.updated))
+	}
+	healed := repo.updated[0]
+	if healed.Status != "refunded" || healed.Amount != 2500 || healed.RefundedAt == nil || healed.AmountRefunded != 2000 {
+		t.Fatalf("unexpected healed payment: %+v", healed)
+	}
+	if len(ledger.txs) != 2 || ledger.txs[0].Type != domain.PaymentTransactionCapture || ledger.txs[1].Type != domain.PaymentTransactionRefund {
+		t.Fatalf("expected legacy capture and corrective refund entries, got %+v", ledger.txs)
+	}
+	if ledger.txs[1].ActorRef != "staff_1" || !ledger.txs[1].OccurredAt.Equal(refundedAt) {
+		t.Fatalf("unexpected corrective refund: %+v", ledger.txs[1])
+	}
+
+	if len(audit.records) != 2 {
+		t.Fatalf("expected heal and run audit entries, got %d", len(audit.records))
+	}
+	if audit.records[0].Action != "payment.reconcile.heal" || audit.records[0].TargetRef != "/orders/ord_2/payments/pay_drift" || audit.records[0].Actor != "staff_1" {
+		t.Fatalf("unexpected heal audit: %+v", audit.records[0])
+	}
+	if diff := audit.records[0].Diff["amount"]; diff.Before != int64(2000) || diff.After != int64(2500) {
+		t.Fatalf("unexpected amount diff: %+v", diff)
+	}
+	if audit.records[1].Action != "payment.reconcile.run" || audit.records[1].Metadata["discrepancies"] != 1 {
+		t.Fatalf("unexpected run audit: %+v", audit.records[1])
+	}
+}
+
+func TestPaymentReconciliationService_ReportOnlyAndLimits(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := &memoryPaymentRepo{}
+	details := map[string]payments.PaymentDetails{}
+	for i := 0; i < 5; i++ {
+		id := strconv.Itoa(i)
+		repo.payments = append(repo.payments, domain.Payment{ID: "pay_" + id, OrderID: "ord_" + id, Provider: "stripe", IntentID: "pi_" + id, Status: "pending", Amount: 100, Currency: "USD", CreatedAt: start.Add(time.Duration(i) * time.Minute)})
+		details["pi_"+id] = payments.PaymentDetails{Status: payments.StatusSucceeded, Amount: 100, Currency: "USD", Captured: true}
+	}
+
+	svc, err := NewPaymentReconciliationService(PaymentReconciliationServiceDeps{
+		Payments:     repo,
+		Transactions: &memoryTransactionRepo{},
+		Provider:     &stubPaymentLookup{details: details},
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	report, err := svc.Reconcile(context.Background(), ReconcilePaymentsCommand{
+		From:     start,
+		To:       start.Add(time.Hour),
+		Provider: " Stripe ",
+		Limit:    3,
+	})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if report.CheckedCount != 3 || len(report.Discrepancies) != 3 || report.HealedCount != 0 || !report.HasMore {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(repo.updated) != 0 {
+		t.Fatalf("report-only run must not update payments")
+	}
+	if repo.filters[0].Provider != "stripe" {
+		t.Fatalf("expected normalised provider filter, got %q", repo.filters[0].Provider)
+	}
+
+	_, err = svc.Reconcile(context.Background(), ReconcilePaymentsCommand{From: start, To: start.Add(-time.Hour)})
+	if !errors.Is(err, ErrPaymentReconciliationInvalidInput) {
+		t.Fatalf("expected invalid input for inverted range, got %v", err)
+	}
+	_, err = svc.Reconcile(context.Background(), ReconcilePaymentsCommand{From: start, To: start.Add(60 * 24 * time.Hour)})
+	if !errors.Is(err, ErrPaymentReconciliationInvalidInput) {
+		t.Fatalf("expected invalid input for oversized range, got %v", err)
+	}
+}
+
+func TestPaymentReconciliationService_HealAppendsMissingCapture(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	capturedAt := start.Add(90 * time.Minute)
+	repo := &memoryPaymentRepo{payments: []domain.Payment{
+		{ID: "pay_pending", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "pending", Amount: 1200, Currency: "JPY", CreatedAt: start.Add(time.Hour)},
+		{ID: "pay_reversed", OrderID: "ord_2", Provider: "stripe", IntentID: "pi_2", Status: "refunded", Amount: 800, Currency: "JPY", CreatedAt: start.Add(2 * time.Hour)},
+	}}
+	ledger := &memoryTransactionRepo{txs: []domain.PaymentTransaction{
+		{ID: "ptx_auth", OrderID: "ord_1", PaymentID: "pay_pending", Type: domain.PaymentTransactionAuthorization, Amount: 1200, OccurredAt: start.Add(time.Hour)},
+		{ID: "ptx_capture", OrderID: "ord_2", PaymentID: "pay_reversed", Type: domain.PaymentTransactionCapture, Amount: 800, OccurredAt: start.Add(2 * time.Hour)},
+		{ID: "ptx_refund", OrderID: "ord_2", PaymentID: "pay_reversed", Type: domain.PaymentTransactionRefund, Amount: 800, OccurredAt: start.Add(3 * time.Hour)},
+	}}
+	lookup := &stubPaymentLookup{details: map[string]payments.PaymentDetails{
+		"pi_1": {IntentID: "pi_1", Status: payments.StatusSucceeded, Amount: 1200, Currency: "JPY", Captured: true, CapturedAt: &capturedAt},
+		"pi_2": {IntentID: "pi_2", Status: payments.StatusSucceeded, Amount: 800, Currency: "JPY", Captured: true},
+	}}
+
+	svc, err := NewPaymentReconciliationService(PaymentReconciliationServiceDeps{
+		Payments:     repo,
+		Transactions: ledger,
+		Provider:     lookup,
+		Clock:        func() time.Time { return start.Add(72 * time.Hour) },
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	report, err := svc.Reconcile(context.Background(), ReconcilePaymentsCommand{From: start, To: start.Add(24 * time.Hour), AutoHeal: true})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if report.HealedCount != 1 || len(report.FailedIDs) != 1 || report.FailedIDs[0] != "pay_reversed" {
+		t.Fatalf("expected pay_pending healed and pay_reversed left for review, got %+v", report)
+	}
+
+	if len(repo.updated) != 1 {
+		t.Fatalf("expected one healed payment, got %d", len(repo.updated))
+	}
+	healed := repo.updated[0]
+	if healed.ID != "pay_pending" || healed.Status != "succeeded" || !healed.Captured || healed.AmountCaptured != 1200 {
+		t.Fatalf("unexpected healed payment: %+v", healed)
+	}
+	if len(ledger.txs) != 4 {
+		t.Fatalf("expected one corrective entry, got %+v", ledger.txs)
+	}
+	capture := ledger.txs[3]
+	if capture.ID != "ptx_reconcile_capture_pay_pending_1" || capture.Type != domain.PaymentTransactionCapture || capture.Amount != 1200 || !capture.OccurredAt.Equal(capturedAt) {
+		t.Fatalf("unexpected corrective capture: %+v", capture)
+	}
+}
+
+func TestPaymentReconciliationService_HealsPartialRefundByDifference(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	refundedAt := start.Add(5 * time.Hour)
+	repo := &memoryPaymentRepo{payments: []domain.Payment{
+		{ID: "pay_partial", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "succeeded", Amount: 1000, Currency: "JPY", Captured: true, CreatedAt: start.Add(time.Hour)},
+	}}
+	ledger := &memoryTransactionRepo{txs: []domain.PaymentTransaction{
+		{ID: "ptx_capture", OrderID: "ord_1", PaymentID: "pay_partial", Type: domain.PaymentTransactionCapture, Amount: 1000, OccurredAt: start.Add(time.Hour)},
+		{ID: "ptx_refund", OrderID: "ord_1", PaymentID: "pay_partial", Type: domain.PaymentTransactionRefund, Amount: 300, OccurredAt: start.Add(2 * time.Hour)},
+	}}
+	lookup := &stubPaymentLookup{details: map[string]payments.PaymentDetails{
+		"pi_1": {IntentID: "pi_1", Status: payments.StatusSucceeded, Amount: 1000, Currency: "JPY", Captured: true, RefundedAt: &refundedAt, AmountRefunded: 500},
+	}}
+
+	svc, err := NewPaymentReconciliationService(PaymentReconciliationServiceDeps{
+		Payments:     repo,
+		Transactions: ledger,
+		Provider:     lookup,
+		Clock:        func() time.Time { return start.Add(72 * time.Hour) },
+	})
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	report, err := svc.Reconcile(context.Background(), ReconcilePaymentsCommand{From: start, To: start.Add(24 * time.Hour), AutoHeal: true})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if report.HealedCount != 1 || len(report.Discrepancies) != 1 {
+		t.Fatalf("expected the partial refund to be healed, got %+v", report)
+	}
+	mismatch := report.Discrepancies[0].Mismatches
+	if len(mismatch) != 1 || mismatch[0].Field != "refunded" || mismatch[0].Recorded != int64(300) || mismatch[0].Provider != int64(500) {
+		t.Fatalf("expected refunded amount mismatch, got %+v", mismatch)
+	}
+	if len(ledger.txs) != 3 || ledger.txs[2].Type != domain.PaymentTransactionRefund || ledger.txs[2].Amount != 200 {
+		t.Fatalf("expected a corrective refund for the difference, got %+v", ledger.txs)
+	}
+	healed := repo.updated[0]
+	if healed.Status != "succeeded" || healed.AmountRefunded != 500 || healed.AmountCaptured != 1000 {
+		t.Fatalf("unexpected healed payment: %+v", healed)
+	}
+}
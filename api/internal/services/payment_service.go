@@ -0,0 +1,865 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+const (
+	paymentTransactionIDPrefix = "ptx_"
+
+	paymentStatusPending   = "pending"
+	paymentStatusSucceeded = "succeeded"
+	paymentStatusRefunded  = "refunded"
+	paymentStatusFailed    = "failed"
+
+	paymentWebhookActor = "system:payments"
+
+	stripeCheckoutCompleted         = "checkout.session.completed"
+	stripeCheckoutAsyncSucceeded    = "checkout.session.async_payment_succeeded"
+	stripeCheckoutAsyncFailed       = "checkout.session.async_payment_failed"
+	stripeCheckoutPaymentStatusPaid = "paid"
+	stripeDisputeCreated            = "charge.dispute.created"
+	stripeDisputeUpdated            = "charge.dispute.updated"
+	stripeDisputeClosed             = "charge.dispute.closed"
+
+	auditActionPaymentManualCapture = "payment.manual_capture"
+	auditActionPaymentManualRefund  = "payment.manual_refund"
+	auditActionPaymentOverRefund    = "payment.over_refund"
+)
+
+var (
+	// ErrPaymentInvalidInput signals the caller supplied invalid payment arguments.
+	ErrPaymentInvalidInput = errors.New("payment: invalid input")
+	// ErrPaymentNotFound indicates the payment could not be located.
+	ErrPaymentNotFound = errors.New("payment: not found")
+	// ErrPaymentInvalidState indicates the payment balances do not allow the operation.
+	ErrPaymentInvalidState = errors.New("payment: invalid state")
+	// ErrPaymentRefundExceedsCaptured indicates a refund larger than the remaining captured balance.
+	ErrPaymentRefundExceedsCaptured = errors.New("payment: refund exceeds captured amount")
+
+	errPaymentTransactionRecorded = errors.New("payment: transaction already recorded")
+)
+
+// PaymentProviderGateway performs PSP operations on behalf of the payment service. *payments.Manager
+// satisfies it.
+type PaymentProviderGateway interface {
+	PaymentProviderLookup
+	Capture(ctx context.Context, paymentCtx payments.PaymentContext, req payments.CaptureRequest) (payments.PaymentDetails, error)
+	Refund(ctx context.Context, paymentCtx payments.PaymentContext, req payments.RefundRequest) (payments.PaymentDetails, error)
+}
+
+// PaymentServiceDeps bundles the collaborators required to construct the payment service.
+type PaymentServiceDeps struct {
+	Payments     repositories.OrderPaymentRepository
+	Transactions repositories.PaymentTransactionRepository
+	Provider     PaymentProviderGateway
+	Orders       OrderService
+	Disputes     DisputeService
+	UnitOfWork   repositories.UnitOfWork
+	Audit        AuditLogService
+	Clock        func() time.Time
+	Logger       func(ctx context.Context, event string, fields map[string]any)
+}
+
+type paymentService struct {
+	payments     repositories.OrderPaymentRepository
+	transactions repositories.PaymentTransactionRepository
+	provider     PaymentProviderGateway
+	orders       OrderService
+	disputes     DisputeService
+	unitOfWork   repositories.UnitOfWork
+	audit        AuditLogService
+	clock        func() time.Time
+	logger       func(context.Context, string, map[string]any)
+}
+
+// NewPaymentService wires dependencies into a PaymentService whose balances are derived from the
+// payment transaction ledger.
+func NewPaymentService(deps PaymentServiceDeps) (PaymentService, error) {
+	if deps.Payments == nil {
+		return nil, errors.New("payment service: payment repository is required")
+	}
+	if deps.Transactions == nil {
+		return nil, errors.New("payment service: transaction repository is required")
+	}
+	if deps.Provider == nil {
+		return nil, errors.New("payment service: payment provider is required")
+	}
+
+	unit := deps.UnitOfWork
+	if unit == nil {
+		unit = noopUnitOfWork{}
+	}
+	clock := deps.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+
+	return &paymentService{
+		payments:     deps.Payments,
+		transactions: deps.Transactions,
+		provider:     deps.Provider,
+		orders:       deps.Orders,
+		disputes:     deps.Disputes,
+		unitOfWork:   unit,
+		audit:        deps.Audit,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		logger: logger,
+	}, nil
+}
+
+// ListPayments returns the order's payments with their ledgers and derived balances.
+func (s *paymentService) ListPayments(ctx context.Context, orderID string) ([]Payment, error) {
+	orderID = strings.TrimSpace(orderID)
+	if orderID == "" {
+		return nil, detailf(ErrPaymentInvalidInput, "order id is required")
+	}
+	records, err := s.payments.List(ctx, orderID)
+	if err != nil {
+		return nil, s.mapRepositoryError(err)
+	}
+	out := make([]Payment, 0, len(records))
+	for _, payment := range records {
+		txs, err := s.transactions.List(ctx, payment.OrderID, payment.ID)
+		if err != nil {
+			return nil, s.mapRepositoryError(err)
+		}
+		out = append(out, applyPaymentLedger(payment, txs))
+	}
+	return out, nil
+}
+
+// ManualCapture captures the remaining authorised amount of a payment. Like refunds, the ledger entry
+// and the PSP request are keyed by the caller's idempotency key, so a retried capture returns the
+// recorded one instead of capturing again.
+func (s *paymentService) ManualCapture(ctx context.Context, cmd PaymentManualCaptureCommand) (Payment, error) {
+	key := strings.TrimSpace(cmd.IdempotencyKey)
+	if key == "" {
+		return Payment{}, detailf(ErrPaymentInvalidInput, "idempotency key is required")
+	}
+	payment, txs, err := s.loadPayment(ctx, cmd.OrderID, cmd.PaymentID)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	txID := manualTransactionID(domain.PaymentTransactionCapture, payment.ID, key)
+	if hasPaymentTransaction(txs, txID) {
+		return applyPaymentLedger(payment, txs), nil
+	}
+
+	balances := applyPaymentLedger(payment, txs)
+	capturable := balances.AmountAuthorized - balances.AmountCaptured
+	if len(txs) == 0 && !payment.Captured {
+		capturable = payment.Amount
+	}
+	if capturable <= 0 {
+		return Payment{}, detailf(ErrPaymentInvalidState, "payment %s has no authorised amount left to capture", payment.ID)
+	}
+
+	details, err := s.provider.Capture(ctx, paymentContextFor(payment), payments.CaptureRequest{
+		IntentID:       payment.IntentID,
+		Amount:         &capturable,
+		IdempotencyKey: txID,
+	})
+	if err != nil {
+		return Payment{}, fmt.Errorf("payment: capture %s: %w", payment.ID, err)
+	}
+
+	tx := s.newTransaction(txID, payment, domain.PaymentTransactionCapture, capturable, details.IntentID, "", cmd.ActorID)
+	updated, err := s.appendTransactions(ctx, payment, rejectRecorded(txID), tx)
+	if errors.Is(err, errPaymentTransactionRecorded) {
+		return s.reloadPayment(ctx, cmd.OrderID, cmd.PaymentID)
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	s.recordAudit(ctx, auditActionPaymentManualCapture, cmd.ActorID, updated, tx)
+	return updated, nil
+}
+
+// ManualRefund refunds part or all of the captured balance. Refunds larger than the captured amount
+// net of earlier refunds and disputes are rejected before the PSP is called. A refund the PSP has
+// confirmed is always recorded; when a concurrent refund has since used up the balance it is flagged
+// for staff instead of being dropped. The ledger entry and the PSP request are keyed by the caller's
+// idempotency key, so a retried request returns the recorded refund instead of refunding twice.
+func (s *paymentService) ManualRefund(ctx context.Context, cmd PaymentManualRefundCommand) (Payment, error) {
+	key := strings.TrimSpace(cmd.IdempotencyKey)
+	if key == "" {
+		return Payment{}, detailf(ErrPaymentInvalidInput, "idempotency key is required")
+	}
+	payment, txs, err := s.loadPayment(ctx, cmd.OrderID, cmd.PaymentID)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	txID := manualTransactionID(domain.PaymentTransactionRefund, payment.ID, key)
+	if hasPaymentTransaction(txs, txID) {
+		return applyPaymentLedger(payment, txs), nil
+	}
+
+	refundable := refundableAmount(applyPaymentLedger(payment, txs))
+	amount := refundable
+	if cmd.Amount != nil {
+		amount = *cmd.Amount
+		if amount <= 0 {
+			return Payment{}, detailf(ErrPaymentInvalidInput, "refund amount must be positive")
+		}
+	}
+	if refundable <= 0 {
+		return Payment{}, detailf(ErrPaymentRefundExceedsCaptured, "payment %s has no captured balance to refund", payment.ID)
+	}
+	if amount > refundable {
+		return Payment{}, detailf(ErrPaymentRefundExceedsCaptured, "requested %d, refundable %d", amount, refundable)
+	}
+
+	reason := strings.TrimSpace(cmd.Reason)
+	details, err := s.provider.Refund(ctx, paymentContextFor(payment), payments.RefundRequest{
+		IntentID:       payment.IntentID,
+		Amount:         &amount,
+		Reason:         reason,
+		IdempotencyKey: txID,
+	})
+	if err != nil {
+		return Payment{}, fmt.Errorf("payment: refund %s: %w", payment.ID, err)
+	}
+
+	tx := s.newTransaction(txID, payment, domain.PaymentTransactionRefund, amount, details.IntentID, reason, cmd.ActorID)
+	s.restateInBaseCurrency(ctx, &tx)
+	var refundableInTx int64
+	updated, err := s.appendTransactions(ctx, payment, func(ledger []domain.PaymentTransaction) error {
+		if err := rejectRecorded(txID)(ledger); err != nil {
+			return err
+		}
+		refundableInTx = refundableAmount(applyPaymentLedger(payment, ledger))
+		return nil
+	}, tx)
+	if errors.Is(err, errPaymentTransactionRecorded) {
+		return s.reloadPayment(ctx, cmd.OrderID, cmd.PaymentID)
+	}
+	if err != nil {
+		return Payment{}, err
+	}
+	s.recordAudit(ctx, auditActionPaymentManualRefund, cmd.ActorID, updated, tx)
+	if amount > refundableInTx {
+		s.logger(ctx, "payment_refund_exceeded_after_psp", map[string]any{
+			"paymentId":     payment.ID,
+			"transactionId": txID,
+			"amount":        amount,
+			"refundable":    refundableInTx,
+		})
+		s.recordAudit(ctx, auditActionPaymentOverRefund, cmd.ActorID, updated, tx)
+	}
+	return updated, nil
+}
+
+// rejectRecorded is an appendTransactions guard that stops when the ledger already holds id.
+func rejectRecorded(id string) func([]domain.PaymentTransaction) error {
+	return func(ledger []domain.PaymentTransaction) error {
+		if hasPaymentTransaction(ledger, id) {
+			return errPaymentTransactionRecorded
+		}
+		return nil
+	}
+}
+
+func (s *paymentService) reloadPayment(ctx context.Context, orderID, paymentID string) (Payment, error) {
+	payment, txs, err := s.loadPayment(ctx, orderID, paymentID)
+	if err != nil {
+		return Payment{}, err
+	}
+	return applyPaymentLedger(payment, txs), nil
+}
+
+// RecordWebhookEvent appends ledger entries for PSP events. Entries are keyed by the event ID so
+// redeliveries surface ErrWebhookDuplicate, and amounts are reconciled against the ledger so events
+// mirroring manual operations do not double count.
+func (s *paymentService) RecordWebhookEvent(ctx context.Context, cmd PaymentWebhookCommand) error {
+	provider := strings.ToLower(strings.TrimSpace(cmd.Provider))
+	switch provider {
+	case payments.ProviderStripe:
+		return s.recordStripeEvent(ctx, cmd.Payload)
+	default:
+		s.logger(ctx, "payment_webhook_ignored", map[string]any{"provider": provider})
+		return nil
+	}
+}
+
+type stripeWebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object stripeWebhookObject `json:"object"`
+	} `json:"data"`
+}
+
+type stripeWebhookObject struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"`
+	Amount           int64             `json:"amount"`
+	AmountCapturable int64             `json:"amount_capturable"`
+	AmountReceived   int64             `json:"amount_received"`
+	AmountCaptured   int64             `json:"amount_captured"`
+	AmountRefunded   int64             `json:"amount_refunded"`
+	Captured         bool              `json:"captured"`
+	Currency         string            `json:"currency"`
+	Reason           string            `json:"reason"`
+	Status           string            `json:"status"`
+	PaymentStatus    string            `json:"payment_status"`
+	Metadata         map[string]string `json:"metadata"`
+	PaymentIntent    json.RawMessage   `json:"payment_intent"`
+	EvidenceDetails  struct {
+		DueBy int64 `json:"due_by"`
+	} `json:"evidence_details"`
+}
+
+func (o stripeWebhookObject) intentID() string {
+	if o.Object == "payment_intent" {
+		return o.ID
+	}
+	var id string
+	if err := json.Unmarshal(o.PaymentIntent, &id); err == nil {
+		return id
+	}
+	var expanded struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(o.PaymentIntent, &expanded); err == nil {
+		return expanded.ID
+	}
+	return ""
+}
+
+func (s *paymentService) recordStripeEvent(ctx context.Context, payload []byte) error {
+	var event stripeWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookInvalidPayload, err)
+	}
+	if strings.TrimSpace(event.ID) == "" || strings.TrimSpace(event.Type) == "" {
+		return detailf(ErrWebhookInvalidPayload, "event id and type are required")
+	}
+
+	var txType domain.PaymentTransactionType
+	switch event.Type {
+	case stripeCheckoutCompleted, stripeCheckoutAsyncSucceeded, stripeCheckoutAsyncFailed:
+		return s.applyCheckoutOutcome(ctx, event)
+	case "payment_intent.amount_capturable_updated":
+		txType = domain.PaymentTransactionAuthorization
+	case "payment_intent.succeeded":
+		txType = domain.PaymentTransactionCapture
+	case "charge.refunded":
+		txType = domain.PaymentTransactionRefund
+	case stripeDisputeCreated:
+		if err := s.ingestStripeDispute(ctx, event); err != nil {
+			return err
+		}
+		txType = domain.PaymentTransactionDispute
+	case stripeDisputeUpdated, stripeDisputeClosed:
+		return s.ingestStripeDispute(ctx, event)
+	default:
+		return nil
+	}
+
+	object := event.Data.Object
+	intentID := object.intentID()
+	if intentID == "" {
+		return detailf(ErrWebhookInvalidPayload, "event %s has no payment intent", event.ID)
+	}
+	payment, err := s.payments.FindByIntent(ctx, payments.ProviderStripe, intentID)
+	if err != nil {
+		return s.mapRepositoryError(err)
+	}
+	txs, err := s.transactions.List(ctx, payment.OrderID, payment.ID)
+	if err != nil {
+		return s.mapRepositoryError(err)
+	}
+	balances := applyPaymentLedger(payment, txs)
+
+	var amount int64
+	reference := intentID
+	switch txType {
+	case domain.PaymentTransactionAuthorization:
+		amount = object.AmountCapturable - balances.AmountAuthorized
+	case domain.PaymentTransactionCapture:
+		amount = object.AmountReceived - balances.AmountCaptured
+	case domain.PaymentTransactionRefund:
+		// Stripe counts the released remainder of a partial capture towards amount_refunded.
+		released := int64(0)
+		if object.Captured && object.AmountCaptured > 0 {
+			released = object.Amount - object.AmountCaptured
+		}
+		amount = object.AmountRefunded - released - balances.AmountRefunded
+		reference = object.ID
+	case domain.PaymentTransactionDispute:
+		amount = object.Amount
+		reference = object.ID
+	}
+	if amount <= 0 {
+		return nil
+	}
+
+	tx := s.newTransaction(paymentTransactionIDPrefix+event.ID, payment, txType, amount, reference, object.Reason, "")
+	if currency := strings.TrimSpace(object.Currency); currency != "" {
+		tx.Currency = strings.ToUpper(currency)
+	}
+	if _, err := s.appendTransactions(ctx, payment, nil, tx); err != nil {
+		var repoErr repositories.RepositoryError
+		if errors.As(err, &repoErr) && repoErr.IsConflict() {
+			return detailf(ErrWebhookDuplicate, "event %s", event.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *paymentService) loadPayment(ctx context.Context, orderID, paymentID string) (domain.Payment, []domain.PaymentTransaction, error) {
+	orderID = strings.TrimSpace(orderID)
+	paymentID = strings.TrimSpace(paymentID)
+	if orderID == "" || paymentID == "" {
+		return domain.Payment{}, nil, detailf(ErrPaymentInvalidInput, "order id and payment id are required")
+	}
+
+	records, err := s.payments.List(ctx, orderID)
+	if err != nil {
+		return domain.Payment{}, nil, s.mapRepositoryError(err)
+	}
+	for _, payment := range records {
+		if payment.ID != paymentID {
+			continue
+		}
+		txs, err := s.transactions.List(ctx, orderID, paymentID)
+		if err != nil {
+			return domain.Payment{}, nil, s.mapRepositoryError(err)
+		}
+		return payment, txs, nil
+	}
+	return domain.Payment{}, nil, detailf(ErrPaymentNotFound, "%s", paymentID)
+}
+
+// appendTransactions inserts the ledger entries and persists the recomputed balances atomically. The
+// ledger is re-read inside the transaction so balances reflect concurrent writers; guard, when set,
+// sees that ledger and can veto the write.
+func (s *paymentService) appendTransactions(ctx context.Context, payment domain.Payment, guard func(ledger []domain.PaymentTransaction) error, added ...domain.PaymentTransaction) (Payment, error) {
+	var updated Payment
+	err := s.unitOfWork.RunInTx(ctx, func(txCtx context.Context) error {
+		ledger, err := s.transactions.List(txCtx, payment.OrderID, payment.ID)
+		if err != nil {
+			return err
+		}
+		if guard != nil {
+			if err := guard(ledger); err != nil {
+				return err
+			}
+		}
+		entries := added
+		if len(ledger) == 0 {
+			entries = append(legacyPaymentTransactions(payment), added...)
+		}
+		updated = applyPaymentLedger(payment, append(ledger, entries...))
+		updated.UpdatedAt = s.clock()
+
+		persisted := updated
+		persisted.Transactions = nil
+		for _, tx := range entries {
+			if err := s.transactions.Insert(txCtx, tx); err != nil {
+				return err
+			}
+		}
+		return s.payments.Update(txCtx, persisted)
+	})
+	if err != nil {
+		var repoErr repositories.RepositoryError
+		if errors.As(err, &repoErr) && repoErr.IsConflict() {
+			return Payment{}, err
+		}
+		return Payment{}, s.mapRepositoryError(err)
+	}
+	return updated, nil
+}
+
+func (s *paymentService) newTransaction(id string, payment domain.Payment, txType domain.PaymentTransactionType, amount int64, reference, reason, actor string) domain.PaymentTransaction {
+	now := s.clock()
+	return domain.PaymentTransaction{
+		ID:           id,
+		PaymentID:    payment.ID,
+		OrderID:      payment.OrderID,
+		Type:         txType,
+		Amount:       amount,
+		Currency:     strings.ToUpper(payment.Currency),
+		PSPReference: strings.TrimSpace(reference),
+		Reason:       reason,
+		ActorRef:     strings.TrimSpace(actor),
+		OccurredAt:   now,
+		CreatedAt:    now,
+	}
+}
+
+// manualTransactionID derives the ledger entry ID from the caller's idempotency key, scoped to the
+// payment and operation so the same key reused elsewhere does not collide.
+func manualTransactionID(txType domain.PaymentTransactionType, paymentID, key string) string {
+	sum := sha256.Sum256([]byte(paymentID + ":" + key))
+	return paymentTransactionIDPrefix + string(txType) + "_" + hex.EncodeToString(sum[:16])
+}
+
+func hasPaymentTransaction(txs []domain.PaymentTransaction, id string) bool {
+	for _, tx := range txs {
+		if tx.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func refundableAmount(balances domain.Payment) int64 {
+	return balances.AmountCaptured - balances.AmountRefunded - balances.AmountDisputed
+}
+
+func (s *paymentService) recordAudit(ctx context.Context, action, actor string, payment Payment, tx domain.PaymentTransaction) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, AuditLogRecord{
+		Actor:      strings.TrimSpace(actor),
+		Action:     action,
+		TargetRef:  fmt.Sprintf("/orders/%s/payments/%s", payment.OrderID, payment.ID),
+		OccurredAt: tx.OccurredAt,
+		Metadata: map[string]any{
+			"service":        "payment",
+			"transactionId":  tx.ID,
+			"amount":         tx.Amount,
+			"currency":       tx.Currency,
+			"amountCaptured": payment.AmountCaptured,
+			"amountRefunded": payment.AmountRefunded,
+		},
+	})
+}
+
+func (s *paymentService) mapRepositoryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var repoErr repositories.RepositoryError
+	if errors.As(err, &repoErr) {
+		switch {
+		case repoErr.IsNotFound():
+			return fmt.Errorf("%w: %v", ErrPaymentNotFound, err)
+		case repoErr.IsConflict():
+			return fmt.Errorf("%w: %v", ErrPaymentInvalidState, err)
+		case repoErr.IsUnavailable():
+			return fmt.Errorf("payment: repository unavailable: %w", err)
+		}
+	}
+	return err
+}
+
+// legacyPaymentTransactions seeds the ledger of a payment recorded before the ledger existed, so its
+// captured and refunded state survives the first appended entry.
+func legacyPaymentTransactions(payment domain.Payment) []domain.PaymentTransaction {
+	if !payment.Captured {
+		return nil
+	}
+	occurredAt := payment.CreatedAt
+	if payment.CapturedAt != nil {
+		occurredAt = *payment.CapturedAt
+	}
+	entries := []domain.PaymentTransaction{{
+		ID:           paymentTransactionIDPrefix + "legacy_capture_" + payment.ID,
+		PaymentID:    payment.ID,
+		OrderID:      payment.OrderID,
+		Type:         domain.PaymentTransactionCapture,
+		Amount:       payment.Amount,
+		Currency:     strings.ToUpper(payment.Currency),
+		PSPReference: payment.IntentID,
+		OccurredAt:   occurredAt,
+		CreatedAt:    occurredAt,
+	}}
+	if payment.RefundedAt != nil {
+		entries = append(entries, domain.PaymentTransaction{
+			ID:           paymentTransactionIDPrefix + "legacy_refund_" + payment.ID,
+			PaymentID:    payment.ID,
+			OrderID:      payment.OrderID,
+			Type:         domain.PaymentTransactionRefund,
+			Amount:       payment.Amount,
+			Currency:     strings.ToUpper(payment.Currency),
+			PSPReference: payment.IntentID,
+			OccurredAt:   *payment.RefundedAt,
+			CreatedAt:    *payment.RefundedAt,
+		})
+	}
+	return entries
+}
+
+// applyCheckoutOutcome settles the order once Checkout reports the payment outcome. Konbini and bank
+// transfer sessions complete unpaid, so their orders stay pending_payment until the async webhooks
+// arrive; an expired voucher cancels the order and returns its committed stock.
+func (s *paymentService) applyCheckoutOutcome(ctx context.Context, event stripeWebhookEvent) error {
+	object := event.Data.Object
+	payment, found, err := s.findCheckoutPayment(ctx, object)
+	if err != nil {
+		return err
+	}
+	orderID := strings.TrimSpace(object.Metadata["orderId"])
+	if found {
+		orderID = payment.OrderID
+	}
+	if orderID == "" {
+		return detailf(ErrWebhookInvalidPayload, "event %s has no order reference", event.ID)
+	}
+
+	if event.Type == stripeCheckoutAsyncFailed {
+		if found && !payment.Captured && payment.Status != paymentStatusFailed {
+			payment.Status = paymentStatusFailed
+			payment.UpdatedAt = s.clock()
+			if err := s.payments.Update(ctx, payment); err != nil {
+				return s.mapRepositoryError(err)
+			}
+		}
+		return s.cancelUnpaidOrder(ctx, orderID, event)
+	}
+
+	if object.PaymentStatus != stripeCheckoutPaymentStatusPaid {
+		s.logger(ctx, "payment_webhook_awaiting_async_payment", map[string]any{
+			"eventId": event.ID,
+			"orderId": orderID,
+		})
+		return nil
+	}
+	return s.markOrderPaid(ctx, orderID, event)
+}
+
+func (s *paymentService) findCheckoutPayment(ctx context.Context, object stripeWebhookObject) (domain.Payment, bool, error) {
+	intentID := object.intentID()
+	if intentID == "" {
+		return domain.Payment{}, false, nil
+	}
+	payment, err := s.payments.FindByIntent(ctx, payments.ProviderStripe, intentID)
+	if err != nil {
+		var repoErr repositories.RepositoryError
+		if errors.As(err, &repoErr) && repoErr.IsNotFound() {
+			return domain.Payment{}, false, nil
+		}
+		return domain.Payment{}, false, s.mapRepositoryError(err)
+	}
+	return payment, true, nil
+}
+
+func (s *paymentService) markOrderPaid(ctx context.Context, orderID string, event stripeWebhookEvent) error {
+	if s.orders == nil {
+		s.logger(ctx, "payment_webhook_orders_unavailable", map[string]any{"eventId": event.ID, "orderId": orderID})
+		return nil
+	}
+	pending := domain.OrderStatusPendingPayment
+	_, err := s.orders.TransitionStatus(ctx, OrderStatusTransitionCommand{
+		OrderID:        orderID,
+		TargetStatus:   domain.OrderStatusPaid,
+		ActorID:        paymentWebhookActor,
+		Reason:         "payment_succeeded",
+		ExpectedStatus: &pending,
+		Metadata:       map[string]any{"eventId": event.ID, "eventType": event.Type},
+	})
+	if errors.Is(err, ErrOrderConflict) {
+		// Redeliveries and completed-then-async sequences find the order already settled.
+		return nil
+	}
+	return err
+}
+
+// restateInBaseCurrency records the refund in the store currency at the rate the order was priced
+// with, so refunds of foreign-currency orders do not pick up later rate moves. Lookup failures are
+// logged and leave the transaction in the payment currency only.
+func (s *paymentService) restateInBaseCurrency(ctx context.Context, tx *domain.PaymentTransaction) {
+	if s.orders == nil {
+		return
+	}
+	order, err := s.orders.GetOrder(ctx, tx.OrderID, OrderReadOptions{})
+	if err != nil {
+		s.logger(ctx, "payment_refund_fx_lookup_failed", map[string]any{"orderId": tx.OrderID, "error": err.Error()})
+		return
+	}
+	for _, conv := range order.ExchangeRates {
+		if !strings.EqualFold(conv.To, tx.Currency) {
+			continue
+		}
+		base, err := RevertWithRecordedRate(conv, tx.Amount)
+		if err != nil {
+			s.logger(ctx, "payment_refund_fx_failed", map[string]any{"orderId": tx.OrderID, "tableId": conv.TableID, "error": err.Error()})
+			return
+		}
+		tx.BaseAmount = base
+		tx.BaseCurrency = strings.ToUpper(conv.From)
+		return
+	}
+}
+
+func (s *paymentService) cancelUnpaidOrder(ctx context.Context, orderID string, event stripeWebhookEvent) error {
+	if s.orders == nil {
+		s.logger(ctx, "payment_webhook_orders_unavailable", map[string]any{"eventId": event.ID, "orderId": orderID})
+		return nil
+	}
+	order, err := s.orders.GetOrder(ctx, orderID, OrderReadOptions{})
+	if err != nil {
+		return err
+	}
+	if order.Status != domain.OrderStatusPendingPayment {
+		s.logger(ctx, "payment_webhook_cancel_skipped", map[string]any{
+			"eventId": event.ID,
+			"orderId": orderID,
+			"status":  string(order.Status),
+		})
+		return nil
+	}
+
+	// The reservation was committed when the order was placed, so its stock is restocked rather than
+	// released.
+	reservationID, _ := order.Metadata["reservationId"].(string)
+	pending := domain.OrderStatusPendingPayment
+	_, err = s.orders.Cancel(ctx, CancelOrderCommand{
+		OrderID:              orderID,
+		ActorID:              paymentWebhookActor,
+		Reason:               "payment_expired",
+		RestockReservationID: reservationID,
+		ExpectedStatus:       &pending,
+		Metadata:             map[string]any{"eventId": event.ID, "eventType": event.Type},
+	})
+	if errors.Is(err, ErrOrderConflict) {
+		return nil
+	}
+	return err
+}
+
+// ingestStripeDispute forwards a Stripe dispute notification to the dispute service, which tracks
+// the evidence deadline and outcome. The ledger entry for the disputed amount is recorded separately.
+func (s *paymentService) ingestStripeDispute(ctx context.Context, event stripeWebhookEvent) error {
+	if s.disputes == nil {
+		return nil
+	}
+	object := event.Data.Object
+	cmd := IngestDisputeCommand{
+		Provider:     payments.ProviderStripe,
+		PSPDisputeID: object.ID,
+		IntentID:     object.intentID(),
+		Status:       stripeDisputeStatus(object.Status),
+		Reason:       object.Reason,
+		Amount:       object.Amount,
+		Currency:     object.Currency,
+		EventID:      event.ID,
+	}
+	if object.EvidenceDetails.DueBy > 0 {
+		due := time.Unix(object.EvidenceDetails.DueBy, 0).UTC()
+		cmd.EvidenceDueBy = &due
+	}
+	if _, err := s.disputes.IngestDispute(ctx, cmd); err != nil {
+		if errors.Is(err, ErrDisputeInvalidInput) {
+			return fmt.Errorf("%w: %v", ErrWebhookInvalidPayload, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// stripeDisputeStatus folds Stripe's inquiry ("warning_") states and refund closures into the
+// dispute lifecycle tracked by the API.
+func stripeDisputeStatus(status string) domain.DisputeStatus {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "needs_response", "warning_needs_response":
+		return domain.DisputeStatusNeedsResponse
+	case "under_review", "warning_under_review":
+		return domain.DisputeStatusUnderReview
+	case "won", "warning_closed":
+		return domain.DisputeStatusWon
+	case "lost", "charge_refunded":
+		return domain.DisputeStatusLost
+	default:
+		return domain.DisputeStatusNeedsResponse
+	}
+}
+
+func paymentContextFor(payment domain.Payment) payments.PaymentContext {
+	return payments.PaymentContext{
+		PreferredProvider: payment.Provider,
+		Currency:          payment.Currency,
+	}
+}
+
+// applyPaymentLedger derives balances from the ledger. Payments recorded before the ledger existed
+// fall back to their Captured/RefundedAt flags so legacy records stay refundable.
+func applyPaymentLedger(payment domain.Payment, txs []domain.PaymentTransaction) domain.Payment {
+	out := payment
+	out.Transactions = append([]domain.PaymentTransaction(nil), txs...)
+	sort.SliceStable(out.Transactions, func(i, j int) bool {
+		return out.Transactions[i].OccurredAt.Before(out.Transactions[j].OccurredAt)
+	})
+
+	if len(txs) == 0 {
+		out.AmountAuthorized, out.AmountCaptured, out.AmountRefunded, out.AmountDisputed = 0, 0, 0, 0
+		if payment.Captured {
+			out.AmountAuthorized = payment.Amount
+			out.AmountCaptured = payment.Amount
+		}
+		if payment.RefundedAt != nil {
+			out.AmountRefunded = out.AmountCaptured
+		}
+		return out
+	}
+
+	var authorized, captured, refunded, disputed int64
+	var capturedAt, refundedAt *time.Time
+	for _, tx := range out.Transactions {
+		at := tx.OccurredAt
+		switch tx.Type {
+		case domain.PaymentTransactionAuthorization:
+			authorized += tx.Amount
+		case domain.PaymentTransactionCapture:
+			captured += tx.Amount
+			if capturedAt == nil {
+				capturedAt = &at
+			}
+		case domain.PaymentTransactionRefund:
+			refunded += tx.Amount
+			refundedAt = &at
+		case domain.PaymentTransactionDispute:
+			disputed += tx.Amount
+		}
+	}
+	if authorized < captured {
+		authorized = captured
+	}
+
+	out.AmountAuthorized = authorized
+	out.AmountCaptured = captured
+	out.AmountRefunded = refunded
+	out.AmountDisputed = disputed
+	out.Captured = captured > 0
+	if capturedAt != nil {
+		out.CapturedAt = capturedAt
+	}
+	if refundedAt != nil {
+		out.RefundedAt = refundedAt
+	}
+	switch {
+	case captured > 0 && refunded >= captured:
+		out.Status = paymentStatusRefunded
+	case captured > 0:
+		out.Status = paymentStatusSucceeded
+	case strings.TrimSpace(out.Status) == "":
+		out.Status = paymentStatusPending
+	}
+	return out
+}
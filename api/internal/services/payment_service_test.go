@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+type memoryTransactionRepo struct {
+	txs []domain.PaymentTransaction
+}
+
+func (r *memoryTransactionRepo) Insert(_ context.Context, tx domain.PaymentTransaction) error {
+	for _, existing := range r.txs {
+		if existing.ID == tx.ID {
+			return repoError{message: "transaction exists", conflict: true}
+		}
+	}
+	r.txs = append(r.txs, tx)
+	return nil
+}
+
+func (r *memoryTransactionRepo) List(_ context.Context, orderID, paymentID string) ([]domain.PaymentTransaction, error) {
+	var out []domain.PaymentTransaction
+	for _, tx := range r.txs {
+		if tx.OrderID == orderID && tx.PaymentID == paymentID {
+			out = append(out, tx)
+		}
+	}
+	return out, nil
+}
+
+type stubPaymentGateway struct {
+	stubPaymentLookup
+	captures []payments.CaptureRequest
+	refunds  []payments.RefundRequest
+	err      error
+}
+
+func (g *stubPaymentGateway) Capture(_ context.Context, _ payments.PaymentContext, req payments.CaptureRequest) (payments.PaymentDetails, error) {
+	g.captures = append(g.captures, req)
+	return payments.PaymentDetails{IntentID: req.IntentID}, g.err
+}
+
+func (g *stubPaymentGateway) Refund(_ context.Context, _ payments.PaymentContext, req payments.RefundRequest) (payments.PaymentDetails, error) {
+	g.refunds = append(g.refunds, req)
+	return payments.PaymentDetails{IntentID: req.IntentID}, g.err
+}
+
+func newTestPaymentService(t *testing.T, payment domain.Payment, txs ...domain.PaymentTransaction) (PaymentService, *memoryPaymentRepo, *memoryTransactionRepo, *stubPaymentGateway) {
+	t.Helper()
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	repo := &memoryPaymentRepo{payments: []domain.Payment{payment}}
+	ledger := &memoryTransactionRepo{txs: txs}
+	gateway := &stubPaymentGateway{}
+	seq := 0
+	svc, err := NewPaymentService(PaymentServiceDeps{
+		Payments:     repo,
+		Transactions: ledger,
+		Provider:     gateway,
+		Clock: func() time.Time {
+			seq++
+			return now.Add(time.Duration(seq) * time.Second)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new payment service: %v", err)
+	}
+	return svc, repo, ledger, gateway
+}
+
+func TestPaymentService_LedgerTracksMultipleCapturesAndRefunds(t *testing.T) {
+	created := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	payment := domain.Payment{ID: "pay_1", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "pending", Amount: 10000, Currency: "JPY"}
+	svc, repo, ledger, gateway := newTestPaymentService(t, payment,
+		domain.PaymentTransaction{ID: "ptx_auth", PaymentID: "pay_1", OrderID: "ord_1", Type: domain.PaymentTransactionAuthorization, Amount: 10000, OccurredAt: created},
+		domain.PaymentTransaction{ID: "ptx_cap1", PaymentID: "pay_1", OrderID: "ord_1", Type: domain.PaymentTransactionCapture, Amount: 6000, OccurredAt: created.Add(time.Minute)},
+	)
+	ctx := context.Background()
+
+	captured, err := svc.ManualCapture(ctx, PaymentManualCaptureCommand{OrderID: "ord_1", PaymentID: "pay_1", ActorID: "staff_1", IdempotencyKey: "capture-1"})
+	if err != nil {
+		t.Fatalf("manual capture: %v", err)
+	}
+	if *gateway.captures[0].Amount != 4000 {
+		t.Fatalf("expected remaining 4000 to be captured, got %d", *gateway.captures[0].Amount)
+	}
+	if captured.AmountCaptured != 10000 || captured.Status != "succeeded" || !captured.Captured {
+		t.Fatalf("unexpected balances after capture: %+v", captured)
+	}
+	if gateway.captures[0].IdempotencyKey != manualTransactionID(domain.PaymentTransactionCapture, "pay_1", "capture-1") {
+		t.Fatalf("expected PSP idempotency key derived from the request, got %q", gateway.captures[0].IdempotencyKey)
+	}
+	replayedCapture, err := svc.ManualCapture(ctx, PaymentManualCaptureCommand{OrderID: "ord_1", PaymentID: "pay_1", IdempotencyKey: "capture-1"})
+	if err != nil || len(gateway.captures) != 1 || replayedCapture.AmountCaptured != 10000 {
+		t.Fatalf("replayed capture must return the recorded capture without calling the PSP: %+v, %v", replayedCapture, err)
+	}
+	if _, err := svc.ManualCapture(ctx, PaymentManualCaptureCommand{OrderID: "ord_1", PaymentID: "pay_1", IdempotencyKey: "capture-2"}); !errors.Is(err, ErrPaymentInvalidState) {
+		t.Fatalf("expected nothing left to capture, got %v", err)
+	}
+	if _, err := svc.ManualCapture(ctx, PaymentManualCaptureCommand{OrderID: "ord_1", PaymentID: "pay_1"}); !errors.Is(err, ErrPaymentInvalidInput) {
+		t.Fatalf("expected missing idempotency key to be rejected, got %v", err)
+	}
+
+	first := int64(3000)
+	refunded, err := svc.ManualRefund(ctx, PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", Amount: &first, Reason: "requested_by_customer", IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("first refund: %v", err)
+	}
+	if refunded.AmountRefunded != 3000 || refunded.Status != "succeeded" || refunded.RefundedAt == nil {
+		t.Fatalf("unexpected balances after partial refund: %+v", refunded)
+	}
+
+	tooMuch := int64(7001)
+	if _, err := svc.ManualRefund(ctx, PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", Amount: &tooMuch, IdempotencyKey: "refund-2"}); !errors.Is(err, ErrPaymentRefundExceedsCaptured) {
+		t.Fatalf("expected over-refund rejection, got %v", err)
+	}
+	if len(gateway.refunds) != 1 {
+		t.Fatalf("over-refund must not reach the PSP")
+	}
+
+	replayed, err := svc.ManualRefund(ctx, PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", Amount: &first, IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("replayed refund: %v", err)
+	}
+	if len(gateway.refunds) != 1 || replayed.AmountRefunded != 3000 {
+		t.Fatalf("replayed key must return the recorded refund without calling the PSP: %+v", replayed)
+	}
+	if gateway.refunds[0].IdempotencyKey != manualTransactionID(domain.PaymentTransactionRefund, "pay_1", "refund-1") {
+		t.Fatalf("expected PSP idempotency key derived from the request, got %q", gateway.refunds[0].IdempotencyKey)
+	}
+
+	refunded, err = svc.ManualRefund(ctx, PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", IdempotencyKey: "refund-3"})
+	if err != nil {
+		t.Fatalf("final refund: %v", err)
+	}
+	if *gateway.refunds[1].Amount != 7000 || refunded.AmountRefunded != 10000 || refunded.Status != "refunded" {
+		t.Fatalf("unexpected balances after full refund: %+v", refunded)
+	}
+
+	if len(ledger.txs) != 5 {
+		t.Fatalf("expected 5 ledger entries, got %d", len(ledger.txs))
+	}
+	listed, err := svc.ListPayments(ctx, "ord_1")
+	if err != nil {
+		t.Fatalf("list payments: %v", err)
+	}
+	if len(listed) != 1 || len(listed[0].Transactions) != 5 || listed[0].AmountRefunded != 10000 {
+		t.Fatalf("unexpected listed payment: %+v", listed)
+	}
+	if repo.payments[0].Transactions != nil {
+		t.Fatalf("ledger entries must not be persisted on the payment document")
+	}
+}
+
+func TestPaymentService_RefundLegacyPaymentWithoutLedger(t *testing.T) {
+	capturedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	payment := domain.Payment{ID: "pay_1", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "succeeded", Amount: 5000, Currency: "JPY", Captured: true, CapturedAt: &capturedAt}
+	svc, _, _, _ := newTestPaymentService(t, payment)
+
+	over := int64(5001)
+	if _, err := svc.ManualRefund(context.Background(), PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", Amount: &over, IdempotencyKey: "refund-1"}); !errors.Is(err, ErrPaymentRefundExceedsCaptured) {
+		t.Fatalf("expected legacy captured amount to cap refunds, got %v", err)
+	}
+	updated, err := svc.ManualRefund(context.Background(), PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", IdempotencyKey: "refund-2"})
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if updated.Status != "refunded" || updated.AmountCaptured != 5000 || updated.AmountRefunded != 5000 {
+		t.Fatalf("unexpected refunded payment: %+v", updated)
+	}
+	if len(updated.Transactions) != 2 || updated.Transactions[0].ID != "ptx_legacy_capture_pay_1" {
+		t.Fatalf("expected legacy capture to seed the ledger, got %+v", updated.Transactions)
+	}
+	if _, err := svc.ManualRefund(context.Background(), PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "missing", IdempotencyKey: "refund-3"}); !errors.Is(err, ErrPaymentNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestPaymentService_RefundConfirmedByPSPIsRecordedWhenBalanceMoved(t *testing.T) {
+	created := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	payment := domain.Payment{ID: "pay_1", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "succeeded", Amount: 5000, Currency: "JPY"}
+	repo := &memoryPaymentRepo{payments: []domain.Payment{payment}}
+	ledger := &memoryTransactionRepo{txs: []domain.PaymentTransaction{
+		{ID: "ptx_cap", PaymentID: "pay_1", OrderID: "ord_1", Type: domain.PaymentTransactionCapture, Amount: 5000, OccurredAt: created},
+	}}
+	// A concurrent refund lands between the pre-check and the ledger write.
+	unit := &stubUnitOfWork{runFn: func(ctx context.Context, fn func(context.Context) error) error {
+		ledger.txs = append(ledger.txs, domain.PaymentTransaction{ID: "ptx_concurrent", PaymentID: "pay_1", OrderID: "ord_1", Type: domain.PaymentTransactionRefund, Amount: 4000, OccurredAt: created.Add(time.Minute)})
+		return fn(ctx)
+	}}
+	gateway := &stubPaymentGateway{}
+	var events []string
+	svc, err := NewPaymentService(PaymentServiceDeps{
+		Payments:     repo,
+		Transactions: ledger,
+		Provider:     gateway,
+		UnitOfWork:   unit,
+		Clock:        func() time.Time { return created.Add(time.Hour) },
+		Logger: func(_ context.Context, event string, _ map[string]any) {
+			events = append(events, event)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new payment service: %v", err)
+	}
+
+	amount := int64(3000)
+	updated, err := svc.ManualRefund(context.Background(), PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", Amount: &amount, IdempotencyKey: "refund-1"})
+	if err != nil {
+		t.Fatalf("a refund the PSP confirmed must be recorded, got %v", err)
+	}
+	if len(ledger.txs) != 3 || len(repo.updated) != 1 || updated.AmountRefunded != 7000 || updated.Status != "refunded" {
+		t.Fatalf("expected the over-refund to be persisted: %+v", updated)
+	}
+	if len(events) != 1 || events[0] != "payment_refund_exceeded_after_psp" {
+		t.Fatalf("expected the over-refund to be flagged, got %v", events)
+	}
+
+	if _, err := svc.ManualRefund(context.Background(), PaymentManualRefundCommand{OrderID: "ord_1", PaymentID: "pay_1", Amount: &amount}); !errors.Is(err, ErrPaymentInvalidInput) {
+		t.Fatalf("expected missing idempotency key to be rejected, got %v", err)
+	}
+}
+
+func TestPaymentService_RecordStripeWebhookAppendsLedger(t *testing.T) {
+	payment := domain.Payment{ID: "pay_1", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "pending", Amount: 5000, Currency: "JPY"}
+	svc, _, ledger, _ := newTestPaymentService(t, payment)
+	ctx := context.Background()
+
+	events := []string{
+		`{"id":"evt_1","type":"payment_intent.amount_capturable_updated","data":{"object":{"id":"pi_1","object":"payment_intent","amount":5000,"amount_capturable":5000,"currency":"jpy"}}}`,
+		`{"id":"evt_2","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","object":"payment_intent","amount":5000,"amount_received":4000,"currency":"jpy"}}}`,
+		`{"id":"evt_3","type":"charge.refunded","data":{"object":{"id":"ch_1","object":"charge","amount":5000,"amount_captured":4000,"amount_refunded":2500,"captured":true,"currency":"jpy","payment_intent":"pi_1"}}}`,
+		`{"id":"evt_4","type":"customer.created","data":{"object":{"id":"cus_1","object":"customer"}}}`,
+	}
+	for _, payload := range events {
+		if err := svc.RecordWebhookEvent(ctx, PaymentWebhookCommand{Provider: "stripe", Payload: []byte(payload)}); err != nil {
+			t.Fatalf("record %s: %v", payload, err)
+		}
+	}
+
+	if len(ledger.txs) != 3 {
+		t.Fatalf("expected 3 ledger entries, got %d", len(ledger.txs))
+	}
+	if ledger.txs[1].Type != domain.PaymentTransactionCapture || ledger.txs[1].Amount != 4000 {
+		t.Fatalf("unexpected capture entry: %+v", ledger.txs[1])
+	}
+	if refund := ledger.txs[2]; refund.Type != domain.PaymentTransactionRefund || refund.Amount != 1500 || refund.PSPReference != "ch_1" || refund.ID != "ptx_evt_3" {
+		t.Fatalf("expected refund net of released 1000, got %+v", refund)
+	}
+
+	// A redelivered refund event carries the same cumulative amount and adds nothing.
+	if err := svc.RecordWebhookEvent(ctx, PaymentWebhookCommand{Provider: "stripe", Payload: []byte(events[2])}); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if len(ledger.txs) != 3 {
+		t.Fatalf("redelivery must not append, got %d entries", len(ledger.txs))
+	}
+
+	dispute := `{"id":"evt_2","type":"charge.dispute.created","data":{"object":{"id":"dp_1","object":"dispute","amount":500,"payment_intent":"pi_1"}}}`
+	if err := svc.RecordWebhookEvent(ctx, PaymentWebhookCommand{Provider: "stripe", Payload: []byte(dispute)}); !errors.Is(err, ErrWebhookDuplicate) {
+		t.Fatalf("expected duplicate for reused event id, got %v", err)
+	}
+	if err := svc.RecordWebhookEvent(ctx, PaymentWebhookCommand{Provider: "stripe", Payload: []byte(`{"id":`)}); !errors.Is(err, ErrWebhookInvalidPayload) {
+		t.Fatalf("expected invalid payload, got %v", err)
+	}
+}
+
+func TestPaymentService_AsyncCheckoutSettlesOrders(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	orders := map[string]domain.Order{
+		"ord_paid":    {ID: "ord_paid", Status: domain.OrderStatusPendingPayment},
+		"ord_expired": {ID: "ord_expired", Status: domain.OrderStatusPendingPayment, Metadata: map[string]any{"reservationId": "res_1"}},
+	}
+	orderRepo := &stubOrderRepo{
+		findFn: func(_ context.Context, id string) (domain.Order, error) {
+			order, ok := orders[id]
+			if !ok {
+				return domain.Order{}, repoError{message: "order not found", notFound: true}
+			}
+			return order, nil
+		},
+		updateFn: func(_ context.Context, order domain.Order) error {
+			orders[order.ID] = order
+			return nil
+		},
+	}
+	// The order's reservation was committed at checkout, so the inventory repository rejects a release
+	// and only a restock returns its stock.
+	reservations := map[string]domain.InventoryReservation{
+		"res_1": {ID: "res_1", OrderRef: "/orders/ord_expired", Status: "committed", Lines: []domain.InventoryReservationLine{
+			{ProductRef: "/products/p1", SKU: "SKU-1", Quantity: 2, LocationID: "workshop"},
+		}},
+	}
+	var restocked []repositories.InventoryRestockRequest
+	inventoryRepo := &stubInventoryRepo{
+		getReservationFn: func(_ context.Context, id string) (domain.InventoryReservation, error) {
+			reservation, ok := reservations[id]
+			if !ok {
+				return domain.InventoryReservation{}, repositories.NewInventoryError(repositories.InventoryErrorReservationNotFound, "reservation not found", nil)
+			}
+			return reservation, nil
+		},
+		releaseFn: func(context.Context, repositories.InventoryReleaseRequest) (repositories.InventoryReleaseResult, error) {
+			return repositories.InventoryReleaseResult{}, repositories.NewInventoryError(repositories.InventoryErrorInvalidReservationState, "reservation res_1 is not in reserved status", nil)
+		},
+		restockFn: func(_ context.Context, req repositories.InventoryRestockRequest) (repositories.InventoryRestockResult, error) {
+			restocked = append(restocked, req)
+			return repositories.InventoryRestockResult{Reservation: reservations[req.ReservationID], Events: req.Events}, nil
+		},
+	}
+	inventory, err := NewInventoryService(InventoryServiceDeps{Inventory: inventoryRepo, Clock: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("new inventory service: %v", err)
+	}
+	orderSvc, err := NewOrderService(OrderServiceDeps{
+		Orders:    orderRepo,
+		Counters:  &stubCounterRepo{},
+		Inventory: inventory,
+		Clock:     func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("new order service: %v", err)
+	}
+
+	repo := &memoryPaymentRepo{payments: []domain.Payment{
+		{ID: "pay_1", OrderID: "ord_paid", Provider: "stripe", IntentID: "pi_1", Status: "pending", Amount: 5000, Currency: "JPY"},
+		{ID: "pay_2", OrderID: "ord_expired", Provider: "stripe", IntentID: "pi_2", Status: "pending", Amount: 3000, Currency: "JPY"},
+	}}
+	svc, err := NewPaymentService(PaymentServiceDeps{
+		Payments:     repo,
+		Transactions: &memoryTransactionRepo{},
+		Provider:     &stubPaymentGateway{},
+		Orders:       orderSvc,
+		Clock:        func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("new payment service: %v", err)
+	}
+	ctx := context.Background()
+	record := func(payload string) {
+		t.Helper()
+		if err := svc.RecordWebhookEvent(ctx, PaymentWebhookCommand{Provider: "stripe", Payload: []byte(payload)}); err != nil {
+			t.Fatalf("record %s: %v", payload, err)
+		}
+	}
+
+	record(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","object":"checkout.session","payment_status":"unpaid","payment_intent":"pi_1"}}}`)
+	if got := orders["ord_paid"].Status; got != domain.OrderStatusPendingPayment {
+		t.Fatalf("expected unpaid konbini checkout to stay pending_payment, got %s", got)
+	}
+
+	succeeded := `{"id":"evt_2","type":"checkout.session.async_payment_succeeded","data":{"object":{"id":"cs_1","object":"checkout.session","payment_status":"paid","payment_intent":"pi_1"}}}`
+	record(succeeded)
+	if order := orders["ord_paid"]; order.Status != domain.OrderStatusPaid || order.PaidAt == nil {
+		t.Fatalf("expected order paid after async success, got %+v", order)
+	}
+	record(succeeded)
+
+	record(`{"id":"evt_3","type":"checkout.session.async_payment_failed","data":{"object":{"id":"cs_2","object":"checkout.session","payment_status":"unpaid","payment_intent":"pi_2"}}}`)
+	if order := orders["ord_expired"]; order.Status != domain.OrderStatusCanceled || order.CancelReason == nil || *order.CancelReason != "payment_expired" {
+		t.Fatalf("expected expired voucher to cancel the order, got %+v", order)
+	}
+	if len(restocked) != 1 {
+		t.Fatalf("expected committed reservation res_1 restocked, got %+v", restocked)
+	}
+	if restock := restocked[0]; restock.ReservationID != "res_1" || restock.OrderRef != "/orders/ord_expired" || len(restock.Events) != 1 || restock.Events[0].Type != "inventory.restock" {
+		t.Fatalf("unexpected restock request %+v", restock)
+	}
+	if status := repo.payments[1].Status; status != "failed" {
+		t.Fatalf("expected payment marked failed, got %s", status)
+	}
+
+	record(`{"id":"evt_4","type":"checkout.session.async_payment_failed","data":{"object":{"id":"cs_1","object":"checkout.session","payment_status":"unpaid","payment_intent":"pi_1"}}}`)
+	if got := orders["ord_paid"].Status; got != domain.OrderStatusPaid {
+		t.Fatalf("paid order must not be canceled, got %s", got)
+	}
+}
+
+type recordingDisputeService struct {
+	DisputeService
+	ingested []IngestDisputeCommand
+}
+
+func (r *recordingDisputeService) IngestDispute(_ context.Context, cmd IngestDisputeCommand) (PaymentDispute, error) {
+	r.ingested = append(r.ingested, cmd)
+	return PaymentDispute{PSPDisputeID: cmd.PSPDisputeID, Status: cmd.Status}, nil
+}
+
+func TestPaymentService_StripeDisputeWebhooksReachDisputeService(t *testing.T) {
+	payment := domain.Payment{ID: "pay_1", OrderID: "ord_1", Provider: "stripe", IntentID: "pi_1", Status: "succeeded", Amount: 5000, Currency: "JPY"}
+	disputes := &recordingDisputeService{}
+	ledger := &memoryTransactionRepo{}
+	svc, err := NewPaymentService(PaymentServiceDeps{
+		Payments:     &memoryPaymentRepo{payments: []domain.Payment{payment}},
+		Transactions: ledger,
+		Provider:     &stubPaymentGateway{},
+		Disputes:     disputes,
+	})
+	if err != nil {
+		t.Fatalf("new payment service: %v", err)
+	}
+	ctx := context.Background()
+
+	events := []string{
+		`{"id":"evt_d1","type":"charge.dispute.created","data":{"object":{"id":"dp_1","object":"dispute","amount":5000,"currency":"jpy","reason":"fraudulent","status":"needs_response","evidence_details":{"due_by":1722902400},"payment_intent":"pi_1"}}}`,
+		`{"id":"evt_d2","type":"charge.dispute.closed","data":{"object":{"id":"dp_1","object":"dispute","amount":5000,"currency":"jpy","status":"warning_closed","payment_intent":"pi_1"}}}`,
+	}
+	for _, payload := range events {
+		if err := svc.RecordWebhookEvent(ctx, PaymentWebhookCommand{Provider: "stripe", Payload: []byte(payload)}); err != nil {
+			t.Fatalf("record %s: %v", payload, err)
+		}
+	}
+
+	if len(disputes.ingested) != 2 {
+		t.Fatalf("expected both dispute events to be ingested, got %d", len(disputes.ingested))
+	}
+	opened := disputes.ingested[0]
+	if opened.PSPDisputeID != "dp_1" || opened.IntentID != "pi_1" || opened.Status != domain.DisputeStatusNeedsResponse ||
+		opened.EvidenceDueBy == nil || opened.EvidenceDueBy.Unix() != 1722902400 || opened.Reason != "fraudulent" {
+		t.Fatalf("unexpected ingest command %+v", opened)
+	}
+	if disputes.ingested[1].Status != domain.DisputeStatusWon {
+		t.Fatalf("expected closed inquiry to map to won, got %s", disputes.ingested[1].Status)
+	}
+	if len(ledger.txs) != 1 || ledger.txs[0].Type != domain.PaymentTransactionDispute || ledger.txs[0].Amount != 5000 {
+		t.Fatalf("expected a single dispute ledger entry, got %+v", ledger.txs)
+	}
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultQuoteTTL = 15 * time.Minute
+
+var (
+	// ErrCartPricingQuoteInvalid is returned when a quote token is malformed, tampered with, or issued
+	// for another cart.
+	ErrCartPricingQuoteInvalid = errors.New("cart pricing: invalid quote")
+	// ErrCartPricingQuoteExpired is returned when a quote is presented after its expiry.
+	ErrCartPricingQuoteExpired = errors.New("cart pricing: quote expired")
+	// ErrCartPricingPriceChanged is returned when the cart reprices differently from its quote. The
+	// error is a *PriceChangedError carrying the differences.
+	ErrCartPricingPriceChanged = errors.New("cart pricing: price changed")
+)
+
+// PriceChange is one monetary field that differs between a quote and the repriced cart. Field is
+// "total", "tax", "items.<itemID>.total", "discounts.<type>:<code>" and so on.
+type PriceChange struct {
+	Field   string
+	Quoted  int64
+	Current int64
+}
+
+// PriceChangedError lists the differences between a quote and the current price of the cart.
+type PriceChangedError struct {
+	QuoteID string
+	Changes []PriceChange
+}
+
+func (e *PriceChangedError) Error() string {
+	parts := make([]string, 0, len(e.Changes))
+	for _, change := range e.Changes {
+		parts = append(parts, fmt.Sprintf("%s %d -> %d", change.Field, change.Quoted, change.Current))
+	}
+	return fmt.Sprintf("%s since quote %s: %s", ErrCartPricingPriceChanged.Error(), e.QuoteID, strings.Join(parts, ", "))
+}
+
+func (e *PriceChangedError) Unwrap() error {
+	return ErrCartPricingPriceChanged
+}
+
+// quoteSnapshot holds the monetary fields a quote commits to. Metadata is left out so that
+// informational changes do not invalidate a quote.
+type quoteSnapshot struct {
+	Currency  string        `json:"cur"`
+	Subtotal  int64         `json:"sub"`
+	Discount  int64         `json:"disc"`
+	Tax       int64         `json:"tax"`
+	Shipping  int64         `json:"ship"`
+	Total     int64         `json:"total"`
+	Items     []quoteLine   `json:"items,omitempty"`
+	Discounts []quoteAmount `json:"discounts,omitempty"`
+}
+
+type quoteLine struct {
+	ID       string `json:"id"`
+	Subtotal int64  `json:"sub"`
+	Discount int64  `json:"disc"`
+	Tax      int64  `json:"tax"`
+	Shipping int64  `json:"ship"`
+	Total    int64  `json:"total"`
+}
+
+type quoteAmount struct {
+	Key    string `json:"key"`
+	Amount int64  `json:"amount"`
+}
+
+type quoteClaims struct {
+	ID        string        `json:"id"`
+	CartID    string        `json:"cart,omitempty"`
+	UserID    string        `json:"user,omitempty"`
+	IssuedAt  int64         `json:"iat"`
+	ExpiresAt int64         `json:"exp"`
+	Hash      string        `json:"hash"`
+	Snapshot  quoteSnapshot `json:"snap"`
+}
+
+// VerifyQuote checks the signature and expiry of token, reprices the cart, and returns the fresh result
+// when it matches the quote. A differing price yields a *PriceChangedError.
+func (e *CartPricingEngine) VerifyQuote(ctx context.Context, cmd PriceCartCommand, token string) (PriceCartResult, error) {
+	if len(e.quoteSecret) == 0 {
+		return PriceCartResult{}, errors.New("cart pricing engine: quote signing is not configured")
+	}
+	claims, err := e.parseQuote(token)
+	if err != nil {
+		return PriceCartResult{}, err
+	}
+	if !e.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return PriceCartResult{}, detailf(ErrCartPricingQuoteExpired, "quote %s expired at %s", claims.ID, time.Unix(claims.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	if claims.CartID != strings.TrimSpace(cmd.Cart.ID) || claims.UserID != strings.TrimSpace(cmd.Cart.UserID) {
+		return PriceCartResult{}, detailf(ErrCartPricingQuoteInvalid, "quote %s was issued for another cart", claims.ID)
+	}
+
+	result, err := e.Calculate(ctx, cmd)
+	if err != nil {
+		return PriceCartResult{}, err
+	}
+	current := newQuoteSnapshot(result.Breakdown)
+	hash, err := hashQuoteSnapshot(current)
+	if err != nil {
+		return PriceCartResult{}, err
+	}
+	if hash != claims.Hash {
+		return PriceCartResult{}, &PriceChangedError{QuoteID: claims.ID, Changes: diffQuoteSnapshots(claims.Snapshot, current)}
+	}
+	return result, nil
+}
+
+func (e *CartPricingEngine) issueQuote(cart Cart, breakdown PricingBreakdown) (PriceQuote, error) {
+	snapshot := newQuoteSnapshot(breakdown)
+	hash, err := hashQuoteSnapshot(snapshot)
+	if err != nil {
+		return PriceQuote{}, err
+	}
+	issuedAt := e.now().Truncate(time.Second)
+	expiresAt := issuedAt.Add(e.quoteTTL)
+	claims := quoteClaims{
+		ID:        "pq_" + e.newQuoteID(),
+		CartID:    strings.TrimSpace(cart.ID),
+		UserID:    strings.TrimSpace(cart.UserID),
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		Hash:      hash,
+		Snapshot:  snapshot,
+	}
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return PriceQuote{}, fmt.Errorf("cart pricing: encode quote: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return PriceQuote{
+		ID:        claims.ID,
+		Token:     payload + "." + e.signQuote(payload),
+		Hash:      hash,
+		Currency:  breakdown.Currency,
+		Total:     breakdown.Total,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (e *CartPricingEngine) parseQuote(token string) (quoteClaims, error) {
+	payload, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || payload == "" || signature == "" {
+		return quoteClaims{}, detailf(ErrCartPricingQuoteInvalid, "malformed quote token")
+	}
+	if !hmac.Equal([]byte(signature), []byte(e.signQuote(payload))) {
+		return quoteClaims{}, detailf(ErrCartPricingQuoteInvalid, "quote signature mismatch")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return quoteClaims{}, detailf(ErrCartPricingQuoteInvalid, "malformed quote payload")
+	}
+	var claims quoteClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return quoteClaims{}, detailf(ErrCartPricingQuoteInvalid, "malformed quote payload")
+	}
+	return claims, nil
+}
+
+func (e *CartPricingEngine) signQuote(payload string) string {
+	mac := hmac.New(sha256.New, e.quoteSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newQuoteSnapshot(breakdown PricingBreakdown) quoteSnapshot {
+	snapshot := quoteSnapshot{
+		Currency: breakdown.Currency,
+		Subtotal: breakdown.Subtotal,
+		Discount: breakdown.Discount,
+		Tax:      breakdown.Tax,
+		Shipping: breakdown.Shipping,
+		Total:    breakdown.Total,
+	}
+	for _, item := range breakdown.Items {
+		snapshot.Items = append(snapshot.Items, quoteLine{
+			ID:       item.ItemID,
+			Subtotal: item.Subtotal,
+			Discount: item.Discount,
+			Tax:      item.Tax,
+			Shipping: item.Shipping,
+			Total:    item.Total,
+		})
+	}
+	for _, discount := range breakdown.Discounts {
+		key := discount.Code
+		if key == "" {
+			key = discount.Source
+		}
+		snapshot.Discounts = append(snapshot.Discounts, quoteAmount{Key: discount.Type + ":" + key, Amount: discount.Amount})
+	}
+	sort.Slice(snapshot.Discounts, func(i, j int) bool {
+		return snapshot.Discounts[i].Key < snapshot.Discounts[j].Key
+	})
+	return snapshot
+}
+
+func hashQuoteSnapshot(snapshot quoteSnapshot) (string, error) {
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", fmt.Errorf("cart pricing: encode quote snapshot: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// diffQuoteSnapshots lists cart totals first, then lines and discounts in quote order followed by
+// entries that only exist in the current price.
+func diffQuoteSnapshots(quoted, current quoteSnapshot) []PriceChange {
+	var changes []PriceChange
+	add := func(field string, before, after int64) {
+		if before != after {
+			changes = append(changes, PriceChange{Field: field, Quoted: before, Current: after})
+		}
+	}
+	add("subtotal", quoted.Subtotal, current.Subtotal)
+	add("discount", quoted.Discount, current.Discount)
+	add("tax", quoted.Tax, current.Tax)
+	add("shipping", quoted.Shipping, current.Shipping)
+	add("total", quoted.Total, current.Total)
+
+	currentLines := make(map[string]quoteLine, len(current.Items))
+	for _, line := range current.Items {
+		currentLines[line.ID] = line
+	}
+	seen := make(map[string]struct{}, len(quoted.Items))
+	diffLine := func(before, after quoteLine, id string) {
+		prefix := "items." + id + "."
+		add(prefix+"subtotal", before.Subtotal, after.Subtotal)
+		add(prefix+"discount", before.Discount, after.Discount)
+		add(prefix+"tax", before.Tax, after.Tax)
+		add(prefix+"shipping", before.Shipping, after.Shipping)
+		add(prefix+"total", before.Total, after.Total)
+	}
+	for _, line := range quoted.Items {
+		seen[line.ID] = struct{}{}
+		diffLine(line, currentLines[line.ID], line.ID)
+	}
+	for _, line := range current.Items {
+		if _, ok := seen[line.ID]; !ok {
+			diffLine(quoteLine{}, line, line.ID)
+		}
+	}
+
+	currentDiscounts := make(map[string]int64, len(current.Discounts))
+	for _, discount := range current.Discounts {
+		currentDiscounts[discount.Key] += discount.Amount
+	}
+	quotedDiscounts := make(map[string]int64, len(quoted.Discounts))
+	for _, discount := range quoted.Discounts {
+		quotedDiscounts[discount.Key] += discount.Amount
+	}
+	for _, discount := range quoted.Discounts {
+		if _, ok := quotedDiscounts[discount.Key]; !ok {
+			continue
+		}
+		add("discounts."+discount.Key, quotedDiscounts[discount.Key], currentDiscounts[discount.Key])
+		delete(quotedDiscounts, discount.Key)
+		delete(currentDiscounts, discount.Key)
+	}
+	for _, discount := range current.Discounts {
+		if amount, ok := currentDiscounts[discount.Key]; ok {
+			add("discounts."+discount.Key, 0, amount)
+			delete(currentDiscounts, discount.Key)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCartPricingEngine_SignedQuotes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion:        &fakePromotionService{},
+		QuoteSecret:      []byte("quote-secret"),
+		QuoteTTL:         10 * time.Minute,
+		QuoteIDGenerator: func() string { return "Q1" },
+		Now:              func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	cart := Cart{
+		ID:       "cart_1",
+		UserID:   "user_1",
+		Currency: "JPY",
+		Items: []CartItem{
+			{ID: "a", SKU: "SEAL", Quantity: 1, UnitPrice: 3000, Currency: "JPY"},
+			{ID: "b", SKU: "CASE", Quantity: 2, UnitPrice: 500, Currency: "JPY"},
+		},
+	}
+
+	res, err := engine.Calculate(ctx, PriceCartCommand{Cart: cart})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	quote := res.Estimate.Quote
+	if quote == nil || quote.ID != "pq_Q1" || quote.Total != 4000 || !quote.ExpiresAt.Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("expected signed quote, got %+v", quote)
+	}
+
+	now = now.Add(5 * time.Minute)
+	if _, err := engine.VerifyQuote(ctx, PriceCartCommand{Cart: cart}, quote.Token); err != nil {
+		t.Fatalf("expected unchanged cart to verify, got %v", err)
+	}
+
+	changed := cart
+	changed.Items = []CartItem{cart.Items[0], {ID: "b", SKU: "CASE", Quantity: 2, UnitPrice: 600, Currency: "JPY"}}
+	_, err = engine.VerifyQuote(ctx, PriceCartCommand{Cart: changed}, quote.Token)
+	var priceChanged *PriceChangedError
+	if !errors.As(err, &priceChanged) || !errors.Is(err, ErrCartPricingPriceChanged) {
+		t.Fatalf("expected price changed error, got %v", err)
+	}
+	want := map[string][2]int64{"subtotal": {4000, 4200}, "total": {4000, 4200}, "items.b.subtotal": {1000, 1200}, "items.b.total": {1000, 1200}}
+	if len(priceChanged.Changes) != len(want) {
+		t.Fatalf("unexpected diff %+v", priceChanged.Changes)
+	}
+	for _, change := range priceChanged.Changes {
+		if w, ok := want[change.Field]; !ok || w[0] != change.Quoted || w[1] != change.Current {
+			t.Fatalf("unexpected change %+v", change)
+		}
+	}
+
+	payload, signature, _ := strings.Cut(quote.Token, ".")
+	if _, err := engine.VerifyQuote(ctx, PriceCartCommand{Cart: cart}, payload+"x."+signature); !errors.Is(err, ErrCartPricingQuoteInvalid) {
+		t.Fatalf("expected tampered quote to be rejected, got %v", err)
+	}
+	other := cart
+	other.ID = "cart_2"
+	if _, err := engine.VerifyQuote(ctx, PriceCartCommand{Cart: other}, quote.Token); !errors.Is(err, ErrCartPricingQuoteInvalid) {
+		t.Fatalf("expected quote for another cart to be rejected, got %v", err)
+	}
+
+	now = now.Add(5 * time.Minute)
+	if _, err := engine.VerifyQuote(ctx, PriceCartCommand{Cart: cart}, quote.Token); !errors.Is(err, ErrCartPricingQuoteExpired) {
+		t.Fatalf("expected expired quote, got %v", err)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/oklog/ulid/v2"
 )
 
 var (
@@ -19,14 +22,20 @@ var (
 )
 
 type CartPricingEngine struct {
-	promotion PromotionService
-	tax       TaxCalculator
-	shipping  ShippingEstimator
-	inventory InventoryAvailabilityService
-	itemRules []ItemDiscountRule
-	now       func() time.Time
-	logger    func(context.Context, string, map[string]any)
-	cache     *shippingQuoteCache
+	promotion   PromotionService
+	tax         TaxCalculator
+	shipping    ShippingEstimator
+	inventory   InventoryAvailabilityService
+	products    ProductPriceLookup
+	surcharge   SurchargeResolver
+	currency    CurrencyConverter
+	itemRules   []ItemDiscountRule
+	now         func() time.Time
+	quoteSecret []byte
+	quoteTTL    time.Duration
+	newQuoteID  func() string
+	logger      func(context.Context, string, map[string]any)
+	cache       *shippingQuoteCache
 }
 
 type CartPricingEngineDeps struct {
@@ -34,10 +43,22 @@ type CartPricingEngineDeps struct {
 	Tax       TaxCalculator
 	Shipping  ShippingEstimator
 	Inventory InventoryAvailabilityService
+	// Products enables volume tier pricing from Product.PriceTiers; without it UnitPrice is used as is.
+	Products ProductPriceLookup
+	// Surcharges adds customization charges such as premium fonts to line subtotals.
+	Surcharges SurchargeResolver
+	// Currency converts items and price tiers quoted in another currency into the cart currency.
+	// Without it mixed currencies are rejected.
+	Currency  CurrencyConverter
 	ItemRules []ItemDiscountRule
 	CacheTTL  time.Duration
-	Now       func() time.Time
-	Logger    func(context.Context, string, map[string]any)
+	// QuoteSecret enables signed quotes on every estimate; checkout verifies them with VerifyQuote.
+	QuoteSecret []byte
+	// QuoteTTL bounds how long a quote stays valid. Defaults to 15 minutes.
+	QuoteTTL         time.Duration
+	QuoteIDGenerator func() string
+	Now              func() time.Time
+	Logger           func(context.Context, string, map[string]any)
 }
 
 func NewCartPricingEngine(deps CartPricingEngineDeps) (*CartPricingEngine, error) {
@@ -60,33 +81,59 @@ func NewCartPricingEngine(deps CartPricingEngineDeps) (*CartPricingEngine, error
 		logger = func(context.Context, string, map[string]any) {}
 	}
 
+	quoteTTL := deps.QuoteTTL
+	if quoteTTL <= 0 {
+		quoteTTL = defaultQuoteTTL
+	}
+	newQuoteID := deps.QuoteIDGenerator
+	if newQuoteID == nil {
+		newQuoteID = func() string {
+			return ulid.Make().String()
+		}
+	}
+
 	engine := &CartPricingEngine{
 		promotion: deps.Promotion,
 		tax:       deps.Tax,
 		shipping:  deps.Shipping,
 		inventory: deps.Inventory,
+		products:  deps.Products,
+		surcharge: deps.Surcharges,
+		currency:  deps.Currency,
 		itemRules: deps.ItemRules,
 		now: func() time.Time {
 			return now().UTC()
 		},
-		logger: logger,
-		cache:  newShippingQuoteCache(ttl, func() time.Time { return now().UTC() }),
+		quoteSecret: append([]byte(nil), deps.QuoteSecret...),
+		quoteTTL:    quoteTTL,
+		newQuoteID:  newQuoteID,
+		logger:      logger,
+		cache:       newShippingQuoteCache(ttl, func() time.Time { return now().UTC() }),
 	}
 
 	return engine, nil
 }
 
 type PriceCartCommand struct {
-	Cart                Cart
-	PromotionCode       *string
-	ShippingAddress     *Address
-	BillingAddress      *Address
+	Cart            Cart
+	PromotionCode   *string
+	ShippingAddress *Address
+	BillingAddress  *Address
+	// UserRoles lets member-only discount rules check the shopper's roles.
+	UserRoles           []string
+	ServiceLevel        string
 	BypassShippingCache bool
+	// Explain records each pricing decision in PriceCartResult.Trace. Explain runs read the shipping
+	// cache but never write to it.
+	Explain bool
 }
 
 type PriceCartResult struct {
 	Breakdown PricingBreakdown
 	Estimate  CartEstimate
+	// ExchangeRates lists one conversion per source currency used while pricing the cart.
+	ExchangeRates []FXConversion
+	Trace         []PricingTraceStep
 }
 
 type ItemDiscountRule interface {
@@ -100,6 +147,21 @@ type ItemDiscountResult struct {
 	Metadata    map[string]any
 }
 
+// ItemRuleContext describes the cart being priced. Items carry unit prices after volume tiers.
+type ItemRuleContext struct {
+	Items     []CartItem
+	UserID    string
+	UserRoles []string
+	Now       time.Time
+}
+
+// CartAwareDiscountRule is an ItemDiscountRule that needs the whole cart, the shopper's roles or the
+// pricing clock. Bind is called once per calculation and returns the rule applied to each line.
+type CartAwareDiscountRule interface {
+	ItemDiscountRule
+	Bind(ctx context.Context, rc ItemRuleContext) (ItemDiscountRule, error)
+}
+
 type TaxCalculator interface {
 	CalculateTax(ctx context.Context, req TaxCalculationRequest) (TaxQuote, error)
 }
@@ -122,11 +184,20 @@ type TaxableItem struct {
 	Subtotal int64
 	Discount int64
 	TaxCode  string
+	// SurchargeType is set when the entry is a customization surcharge split from line ItemID
+	// because it carries its own tax code.
+	SurchargeType string
 }
 
+// TaxQuote reports the tax owed on a cart. Inclusive quotes describe tax already contained in
+// tax-inclusive prices, so the engine reports the amount without adding it to the total. Deduction
+// is tax contained in tax-inclusive prices that is not owed, such as on zero-rated exports; the
+// engine subtracts it from the total.
 type TaxQuote struct {
 	Amount    int64
 	Breakdown []TaxBreakdown
+	Inclusive bool
+	Deduction int64
 }
 
 type ShippingEstimator interface {
@@ -137,9 +208,11 @@ type ShippingEstimateRequest struct {
 	Currency        string
 	Items           []ShippableItem
 	ShippingAddress *Address
-	CartSubtotal    int64
-	DiscountTotal   int64
-	PromotionCode   *string
+	// ServiceLevel selects the delivery option; estimators fall back to their default when empty.
+	ServiceLevel  string
+	CartSubtotal  int64
+	DiscountTotal int64
+	PromotionCode *string
 }
 
 type ShippableItem struct {
@@ -159,11 +232,46 @@ type InventoryAvailabilityService interface {
 	ValidateAvailability(ctx context.Context, lines []InventoryLine) error
 }
 
+// SurchargeResolver prices the customization choices of a cart line. Returned amounts cover the whole
+// line quantity.
+type SurchargeResolver interface {
+	ResolveSurcharges(ctx context.Context, item CartItem, currency string) ([]SurchargeBreakdown, error)
+}
+
+// CurrencyConverter converts minor-unit amounts between currencies. FXService satisfies it.
+type CurrencyConverter interface {
+	Convert(ctx context.Context, amount int64, from, to string) (int64, FXConversion, error)
+}
+
+// PricingCurrencyReporter is implemented by surcharge resolvers and shipping estimators whose amounts
+// are configured in a single currency. Carts in another currency are priced in that currency and the
+// amounts converted through the engine's CurrencyConverter.
+type PricingCurrencyReporter interface {
+	PricingCurrency() string
+}
+
+// ShippingRateVersioner is implemented by shipping estimators whose rates can change at runtime. The
+// version is part of the shipping cache key, so quotes cached before a reload are not served after it.
+type ShippingRateVersioner interface {
+	Version() string
+}
+
+// ProductPriceLookup loads the product whose price tiers apply to a cart line. CatalogService
+// satisfies it.
+type ProductPriceLookup interface {
+	GetProduct(ctx context.Context, productID string) (Product, error)
+}
+
 func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand) (PriceCartResult, error) {
 	if err := e.validateCartInput(cmd); err != nil {
 		return PriceCartResult{}, err
 	}
 
+	var trace *pricingTrace
+	if cmd.Explain {
+		trace = &pricingTrace{}
+	}
+
 	cart := cmd.Cart
 	if cmd.ShippingAddress != nil {
 		cart.ShippingAddress = cmd.ShippingAddress
@@ -172,6 +280,23 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 		cart.BillingAddress = cmd.BillingAddress
 	}
 
+	itemConversions, exchangeRates, err := e.convertItemCurrencies(ctx, &cart)
+	if err != nil {
+		return PriceCartResult{}, err
+	}
+	for idx, conv := range itemConversions {
+		if conv != nil {
+			trace.add(PricingStepCurrencyConversion, cart.Items[idx].ID, map[string]any{
+				"from":      conv.From,
+				"to":        conv.To,
+				"rate":      conv.Rate,
+				"tableId":   conv.TableID,
+				"original":  cmd.Cart.Items[idx].UnitPrice,
+				"converted": cart.Items[idx].UnitPrice,
+			})
+		}
+	}
+
 	currency, err := ensureSingleCurrency(cart)
 	if err != nil {
 		return PriceCartResult{}, err
@@ -188,6 +313,17 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 	}
 
 	promotionCode := resolvePromotionCode(cart, cmd.PromotionCode)
+	if trace != nil {
+		input := map[string]any{"currency": currency, "items": len(cart.Items), "serviceLevel": cmd.ServiceLevel}
+		if promotionCode != nil {
+			input["promotionCode"] = *promotionCode
+		}
+		if cart.ShippingAddress != nil {
+			input["shippingCountry"] = cart.ShippingAddress.Country
+			input["shippingPostalCode"] = cart.ShippingAddress.PostalCode
+		}
+		trace.add(PricingStepInput, "", input)
+	}
 
 	itemBreakdowns := make([]ItemPricingBreakdown, 0, len(cart.Items))
 	itemDiscountTotals := make(map[string]int64)
@@ -195,36 +331,76 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 	taxWeights := make([]int64, 0, len(cart.Items))
 	shippingWeights := make([]int64, 0, len(cart.Items))
 
-	for _, item := range cart.Items {
+	tiers, err := e.loadPriceTiers(ctx, cart.Items, currency)
+	if err != nil {
+		return PriceCartResult{}, err
+	}
+
+	pricedItems := make([]CartItem, len(cart.Items))
+	appliedTiers := make([]*ProductPriceTier, len(cart.Items))
+	for idx, item := range cart.Items {
+		if tier, ok := selectPriceTier(tiers[item.ProductID], item.Quantity); ok {
+			trace.add(PricingStepPriceTier, item.ID, map[string]any{
+				"baseUnitPrice": item.UnitPrice,
+				"minQuantity":   tier.MinQuantity,
+				"unitPrice":     tier.UnitPrice,
+				"quantity":      item.Quantity,
+			})
+			item.UnitPrice = tier.UnitPrice
+			appliedTiers[idx] = &tier
+		} else if len(tiers[item.ProductID]) > 0 {
+			trace.add(PricingStepPriceTier, item.ID, map[string]any{"unitPrice": item.UnitPrice, "quantity": item.Quantity, "matched": false})
+		}
+		pricedItems[idx] = item
+	}
+
+	itemRules, err := e.bindItemRules(ctx, ItemRuleContext{
+		Items:     pricedItems,
+		UserID:    cart.UserID,
+		UserRoles: cmd.UserRoles,
+		Now:       e.now(),
+	})
+	if err != nil {
+		return PriceCartResult{}, err
+	}
+	ruleDetails := make(map[string]ItemDiscountResult)
+
+	for idx, item := range pricedItems {
+		baseUnitPrice := cart.Items[idx].UnitPrice
+		appliedTier := appliedTiers[idx]
+
 		quantity := int64(item.Quantity)
 		if item.UnitPrice > 0 && quantity > 0 {
 			if item.UnitPrice > math.MaxInt64/quantity {
-				return PriceCartResult{}, fmt.Errorf("%w: item %s subtotal overflow", ErrCartPricingInvalidInput, item.ID)
+				return PriceCartResult{}, detailf(ErrCartPricingInvalidInput, "item %s subtotal overflow", item.ID)
 			}
 		}
 
 		lineSubtotal := item.UnitPrice * quantity
 		if lineSubtotal < 0 {
-			return PriceCartResult{}, fmt.Errorf("%w: negative line subtotal for item %s", ErrCartPricingInvalidInput, item.ID)
+			return PriceCartResult{}, detailf(ErrCartPricingInvalidInput, "negative line subtotal for item %s", item.ID)
 		}
 
 		discountTotal := int64(0)
 		perRuleContribution := make(map[string]int64)
-		if len(e.itemRules) > 0 {
-			for _, rule := range e.itemRules {
-				result, ruleErr := rule.Apply(ctx, item, lineSubtotal)
-				if ruleErr != nil {
-					return PriceCartResult{}, ruleErr
-				}
-				if result.Amount < 0 {
-					return PriceCartResult{}, fmt.Errorf("%w: rule %s produced negative discount", ErrCartPricingInvalidInput, rule.Name())
-				}
-				discountTotal += result.Amount
-				perRuleContribution[rule.Name()] += result.Amount
+		for _, rule := range itemRules {
+			result, ruleErr := rule.Apply(ctx, item, lineSubtotal)
+			if ruleErr != nil {
+				return PriceCartResult{}, ruleErr
+			}
+			if result.Amount < 0 {
+				return PriceCartResult{}, detailf(ErrCartPricingInvalidInput, "rule %s produced negative discount", rule.Name())
+			}
+			discountTotal += result.Amount
+			perRuleContribution[rule.Name()] += result.Amount
+			if result.Amount > 0 {
+				recordRuleDetails(ruleDetails, rule.Name(), result)
+				trace.add(PricingStepItemRule, item.ID, map[string]any{"rule": rule.Name(), "amount": result.Amount, "description": result.Description})
 			}
 		}
 		if discountTotal > lineSubtotal && len(perRuleContribution) > 0 {
 			e.logger(ctx, "pricing_rule_clamped", map[string]any{"itemId": item.ID, "rule": "item", "subtotal": lineSubtotal, "discount": discountTotal})
+			trace.add(PricingStepItemRule, item.ID, map[string]any{"clamped": true, "subtotal": lineSubtotal, "discount": discountTotal})
 			perRuleContribution = scaleDiscountAllocations(perRuleContribution, lineSubtotal)
 		}
 
@@ -234,13 +410,26 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 			discountTotal += amount
 		}
 
+		// Item rules discount the goods only; customization surcharges are added afterwards.
+		surcharges, surchargeTotal, err := e.resolveSurcharges(ctx, item, currency, &exchangeRates)
+		if err != nil {
+			return PriceCartResult{}, err
+		}
+		if surchargeTotal > math.MaxInt64-lineSubtotal {
+			return PriceCartResult{}, detailf(ErrCartPricingInvalidInput, "item %s subtotal overflow", item.ID)
+		}
+		lineSubtotal += surchargeTotal
+		for _, surcharge := range surcharges {
+			trace.add(PricingStepSurcharge, item.ID, map[string]any{"type": surcharge.Type, "code": surcharge.Code, "quantity": surcharge.Quantity, "amount": surcharge.Amount})
+		}
+
 		net := lineSubtotal - discountTotal
 		if net < 0 {
 			net = 0
 		}
 
 		if lineSubtotal > 0 && subtotal > math.MaxInt64-lineSubtotal {
-			return PriceCartResult{}, fmt.Errorf("%w: cart subtotal overflow", ErrCartPricingInvalidInput)
+			return PriceCartResult{}, detailf(ErrCartPricingInvalidInput, "cart subtotal overflow")
 		}
 		subtotal += lineSubtotal
 
@@ -259,18 +448,34 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 		}
 		shippingWeights = append(shippingWeights, shippingWeight)
 
+		metadata := map[string]any{"quantity": item.Quantity, "unitPrice": item.UnitPrice, "weightGrams": item.WeightGrams}
+		if appliedTier != nil {
+			metadata["baseUnitPrice"] = baseUnitPrice
+			metadata["priceTier"] = map[string]any{"minQuantity": appliedTier.MinQuantity, "unitPrice": appliedTier.UnitPrice}
+		}
+		if conv := itemConversions[idx]; conv != nil {
+			original := cmd.Cart.Items[idx]
+			metadata["originalUnitPrice"] = original.UnitPrice
+			metadata["originalCurrency"] = conv.From
+			metadata["fxRate"] = conv.Rate
+			metadata["fxTableId"] = conv.TableID
+		}
+		if surchargeTotal > 0 {
+			metadata["surchargeTotal"] = surchargeTotal
+		}
 		itemBreakdowns = append(itemBreakdowns, ItemPricingBreakdown{
-			ItemID:   item.ID,
-			Currency: currency,
-			Subtotal: lineSubtotal,
-			Discount: discountTotal,
-			Metadata: map[string]any{"quantity": item.Quantity, "unitPrice": item.UnitPrice, "weightGrams": item.WeightGrams},
+			ItemID:     item.ID,
+			Currency:   currency,
+			Subtotal:   lineSubtotal,
+			Discount:   discountTotal,
+			Surcharges: surcharges,
+			Metadata:   metadata,
 		})
 	}
 
 	totalItemDiscount := sumInt64Map(itemDiscountTotals)
 
-	promoDiscount, promoBreakdown, promoApplied, err := e.applyPromotion(ctx, cart, promotionCode)
+	promoDiscount, promoBreakdown, promoApplied, err := e.applyPromotion(ctx, cart, promotionCode, trace)
 	if err != nil {
 		return PriceCartResult{}, err
 	}
@@ -300,6 +505,13 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 	for idx := range itemBreakdowns {
 		itemBreakdowns[idx].Discount += promoAlloc[idx]
 	}
+	if promoApplied && trace != nil {
+		allocation := make(map[string]int64, len(itemBreakdowns))
+		for idx := range itemBreakdowns {
+			allocation[itemBreakdowns[idx].ItemID] = promoAlloc[idx]
+		}
+		trace.add(PricingStepPromotion, "", map[string]any{"amount": promoDiscount, "allocation": allocation})
+	}
 
 	// Recompute tax weights using net amounts after promotions to keep allocations aligned.
 	for idx := range taxWeights {
@@ -319,26 +531,44 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 		netSubtotal = 0
 	}
 
-	shippingAmount, shippingBreakdown, err := e.calculateShipping(ctx, currency, cart, subtotal, totalDiscount, promotionCode, cmd.BypassShippingCache)
+	shippingAmount, shippingBreakdown, err := e.calculateShipping(ctx, currency, cart, subtotal, totalDiscount, promotionCode, cmd.ServiceLevel, cmd.BypassShippingCache, trace, &exchangeRates)
 	if err != nil {
 		return PriceCartResult{}, err
 	}
 
-	taxAmount, taxBreakdown, err := e.calculateTax(ctx, currency, cart, itemBreakdowns, subtotal, totalDiscount, shippingAmount, promotionCode)
+	taxQuote, err := e.calculateTax(ctx, currency, cart, itemBreakdowns, subtotal, totalDiscount, shippingAmount, promotionCode)
 	if err != nil {
 		return PriceCartResult{}, err
 	}
+	taxAmount, taxBreakdown, taxIncluded := taxQuote.Amount, taxQuote.Breakdown, taxQuote.Inclusive
 
-	distributeTaxAndShipping(itemBreakdowns, taxWeights, shippingWeights, taxAmount, shippingAmount)
+	distributeTaxAndShipping(itemBreakdowns, taxWeights, shippingWeights, taxAmount, shippingAmount, taxIncluded)
+	deductContainedTax(itemBreakdowns, taxWeights, taxQuote.Deduction)
+	if trace != nil && e.tax != nil {
+		components := make([]map[string]any, 0, len(taxBreakdown))
+		for _, tax := range taxBreakdown {
+			components = append(components, map[string]any{"name": tax.Name, "rate": tax.Rate, "amount": tax.Amount})
+		}
+		trace.add(PricingStepTax, "", map[string]any{"amount": taxAmount, "inclusive": taxIncluded, "deduction": taxQuote.Deduction, "components": components})
+	}
 
-	total := netSubtotal + taxAmount + shippingAmount
+	total := netSubtotal + shippingAmount - taxQuote.Deduction
+	if !taxIncluded {
+		total += taxAmount
+	}
 	if total < 0 {
 		total = 0
 	}
 
-	discounts := buildDiscountBreakdowns(itemDiscountTotals, promoBreakdown)
+	discounts := buildDiscountBreakdowns(itemDiscountTotals, ruleDetails, promoBreakdown)
 
 	metadata := map[string]any{"netSubtotal": netSubtotal}
+	if taxIncluded {
+		metadata["taxInclusive"] = true
+	}
+	if taxQuote.Deduction > 0 {
+		metadata["taxDeduction"] = taxQuote.Deduction
+	}
 	if promotionCode != nil {
 		metadata["promotionCode"] = *promotionCode
 	}
@@ -365,28 +595,43 @@ func (e *CartPricingEngine) Calculate(ctx context.Context, cmd PriceCartCommand)
 		Shipping: shippingAmount,
 		Total:    total,
 	}
+	if len(e.quoteSecret) > 0 && !cmd.Explain {
+		quote, err := e.issueQuote(cart, breakdown)
+		if err != nil {
+			return PriceCartResult{}, err
+		}
+		estimate.Quote = &quote
+	}
+
+	trace.add(PricingStepTotal, "", map[string]any{
+		"subtotal": subtotal,
+		"discount": totalDiscount,
+		"shipping": shippingAmount,
+		"tax":      taxAmount,
+		"total":    total,
+	})
 
-	return PriceCartResult{Breakdown: breakdown, Estimate: estimate}, nil
+	return PriceCartResult{Breakdown: breakdown, Estimate: estimate, ExchangeRates: exchangeRates, Trace: trace.result()}, nil
 }
 
 func (e *CartPricingEngine) validateCartInput(cmd PriceCartCommand) error {
 	cartCurrency := strings.TrimSpace(cmd.Cart.Currency)
 	if len(cmd.Cart.Items) == 0 {
 		if cartCurrency == "" {
-			return fmt.Errorf("%w: cart currency required when no items provided", ErrCartPricingInvalidInput)
+			return detailf(ErrCartPricingInvalidInput, "cart currency required when no items provided")
 		}
 		return nil
 	}
 
 	for _, item := range cmd.Cart.Items {
 		if item.Quantity <= 0 {
-			return fmt.Errorf("%w: item %s quantity must be positive", ErrCartPricingInvalidInput, item.ID)
+			return detailf(ErrCartPricingInvalidInput, "item %s quantity must be positive", item.ID)
 		}
 		if item.UnitPrice < 0 {
-			return fmt.Errorf("%w: item %s unit price cannot be negative", ErrCartPricingInvalidInput, item.ID)
+			return detailf(ErrCartPricingInvalidInput, "item %s unit price cannot be negative", item.ID)
 		}
 		if strings.TrimSpace(item.Currency) == "" && cartCurrency == "" {
-			return fmt.Errorf("%w: item %s currency missing", ErrCartPricingInvalidInput, item.ID)
+			return detailf(ErrCartPricingInvalidInput, "item %s currency missing", item.ID)
 		}
 		if cartCurrency == "" {
 			cartCurrency = strings.TrimSpace(item.Currency)
@@ -436,7 +681,7 @@ func resolvePromotionCode(cart Cart, override *string) *string {
 	return nil
 }
 
-func (e *CartPricingEngine) applyPromotion(ctx context.Context, cart Cart, promoCode *string) (int64, []DiscountBreakdown, bool, error) {
+func (e *CartPricingEngine) applyPromotion(ctx context.Context, cart Cart, promoCode *string, trace *pricingTrace) (int64, []DiscountBreakdown, bool, error) {
 	if promoCode == nil {
 		return 0, nil, false, nil
 	}
@@ -453,8 +698,10 @@ func (e *CartPricingEngine) applyPromotion(ctx context.Context, cart Cart, promo
 		return 0, nil, false, err
 	}
 	if !result.Eligible {
+		trace.add(PricingStepPromotion, "", map[string]any{"code": *promoCode, "eligible": false, "reason": result.Reason})
 		return 0, nil, false, nil
 	}
+	trace.add(PricingStepPromotion, "", map[string]any{"code": result.Code, "eligible": true, "reason": result.Reason, "quoted": result.DiscountAmount})
 
 	discount := result.DiscountAmount
 	if discount < 0 {
@@ -470,21 +717,294 @@ func (e *CartPricingEngine) applyPromotion(ctx context.Context, cart Cart, promo
 	return discount, []DiscountBreakdown{breakdown}, true, nil
 }
 
-func (e *CartPricingEngine) calculateTax(ctx context.Context, currency string, cart Cart, items []ItemPricingBreakdown, cartSubtotal, discountTotal, shippingAmount int64, promoCode *string) (int64, []TaxBreakdown, error) {
+// bindItemRules resolves cart-aware rules for the current calculation.
+func (e *CartPricingEngine) bindItemRules(ctx context.Context, rc ItemRuleContext) ([]ItemDiscountRule, error) {
+	if len(e.itemRules) == 0 {
+		return nil, nil
+	}
+	rules := make([]ItemDiscountRule, 0, len(e.itemRules))
+	for _, rule := range e.itemRules {
+		if aware, ok := rule.(CartAwareDiscountRule); ok {
+			bound, err := aware.Bind(ctx, rc)
+			if err != nil {
+				return nil, err
+			}
+			if bound == nil {
+				continue
+			}
+			rule = bound
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// recordRuleDetails keeps the first description a rule reports and merges its metadata so the cart
+// level DiscountBreakdown can explain the discount.
+func recordRuleDetails(details map[string]ItemDiscountResult, name string, result ItemDiscountResult) {
+	detail := details[name]
+	if detail.Description == "" {
+		detail.Description = result.Description
+	}
+	for key, value := range result.Metadata {
+		if detail.Metadata == nil {
+			detail.Metadata = make(map[string]any, len(result.Metadata))
+		}
+		if _, exists := detail.Metadata[key]; !exists {
+			detail.Metadata[key] = value
+		}
+	}
+	details[name] = detail
+}
+
+// resolveSurcharges prices the line's customization choices in the cart currency. Resolvers
+// configured in another currency are asked in their own currency and each unit amount is converted,
+// recording the rate in rates.
+func (e *CartPricingEngine) resolveSurcharges(ctx context.Context, item CartItem, currency string, rates *[]FXConversion) ([]SurchargeBreakdown, int64, error) {
+	if e.surcharge == nil || len(item.Customization) == 0 {
+		return nil, 0, nil
+	}
+	source := e.pricingCurrencyOf(e.surcharge, currency)
+	surcharges, err := e.surcharge.ResolveSurcharges(ctx, item, source)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	for idx, surcharge := range surcharges {
+		if surcharge.Amount < 0 {
+			return nil, 0, detailf(ErrCartPricingInvalidInput, "negative %s surcharge for item %s", surcharge.Type, item.ID)
+		}
+		if source != currency {
+			surcharge, err = e.convertSurcharge(ctx, surcharge, source, currency, rates)
+			if err != nil {
+				return nil, 0, err
+			}
+			surcharges[idx] = surcharge
+		}
+		if total > math.MaxInt64-surcharge.Amount {
+			return nil, 0, detailf(ErrCartPricingInvalidInput, "item %s surcharge overflow", item.ID)
+		}
+		total += surcharge.Amount
+	}
+	return surcharges, total, nil
+}
+
+func (e *CartPricingEngine) convertSurcharge(ctx context.Context, surcharge SurchargeBreakdown, from, to string, rates *[]FXConversion) (SurchargeBreakdown, error) {
+	original := surcharge.Amount
+	if surcharge.Quantity > 0 {
+		unit, err := e.convertAmount(ctx, surcharge.UnitAmount, from, to, rates)
+		if err != nil {
+			return SurchargeBreakdown{}, err
+		}
+		if unit > 0 && unit > math.MaxInt64/int64(surcharge.Quantity) {
+			return SurchargeBreakdown{}, detailf(ErrCartPricingInvalidInput, "%s surcharge overflow", surcharge.Type)
+		}
+		surcharge.UnitAmount = unit
+		surcharge.Amount = unit * int64(surcharge.Quantity)
+	} else {
+		amount, err := e.convertAmount(ctx, surcharge.Amount, from, to, rates)
+		if err != nil {
+			return SurchargeBreakdown{}, err
+		}
+		surcharge.Amount = amount
+	}
+	surcharge.Metadata = maps.Clone(surcharge.Metadata)
+	if surcharge.Metadata == nil {
+		surcharge.Metadata = make(map[string]any, 2)
+	}
+	surcharge.Metadata["originalAmount"] = original
+	surcharge.Metadata["originalCurrency"] = from
+	return surcharge, nil
+}
+
+// pricingCurrencyOf returns the currency component prices in when it differs from the cart currency
+// and a converter is configured, and the cart currency otherwise.
+func (e *CartPricingEngine) pricingCurrencyOf(component any, currency string) string {
+	if e.currency == nil {
+		return currency
+	}
+	reporter, ok := component.(PricingCurrencyReporter)
+	if !ok {
+		return currency
+	}
+	if priced := strings.ToUpper(strings.TrimSpace(reporter.PricingCurrency())); priced != "" {
+		return priced
+	}
+	return currency
+}
+
+// convertAmount converts amount and records the rate in rates once per currency pair.
+func (e *CartPricingEngine) convertAmount(ctx context.Context, amount int64, from, to string, rates *[]FXConversion) (int64, error) {
+	converted, conv, err := e.currency.Convert(ctx, amount, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if rates != nil {
+		for _, recorded := range *rates {
+			if strings.EqualFold(recorded.From, conv.From) && strings.EqualFold(recorded.To, conv.To) {
+				return converted, nil
+			}
+		}
+		*rates = append(*rates, conv)
+	}
+	return converted, nil
+}
+
+// splitTaxableItem reports surcharges whose tax code differs from the line as separate taxable
+// entries. The line discount reduces the goods first and only the excess is spread over surcharges.
+func splitTaxableItem(cartItem CartItem, item ItemPricingBreakdown) []TaxableItem {
+	base := TaxableItem{
+		ItemID:   item.ItemID,
+		SKU:      cartItem.SKU,
+		Quantity: cartItem.Quantity,
+		Subtotal: item.Subtotal,
+		Discount: item.Discount,
+		TaxCode:  cartItem.TaxCode,
+	}
+	var split []SurchargeBreakdown
+	for _, surcharge := range item.Surcharges {
+		if surcharge.Amount > 0 && surcharge.TaxCode != "" && surcharge.TaxCode != cartItem.TaxCode {
+			split = append(split, surcharge)
+		}
+	}
+	if len(split) == 0 {
+		return []TaxableItem{base}
+	}
+
+	weights := make([]int64, 0, len(split))
+	for _, surcharge := range split {
+		base.Subtotal -= surcharge.Amount
+		weights = append(weights, surcharge.Amount)
+	}
+	base.Discount = min(item.Discount, base.Subtotal)
+	discounts := allocateByWeight(item.Discount-base.Discount, weights)
+
+	out := make([]TaxableItem, 0, len(split)+1)
+	out = append(out, base)
+	for i, surcharge := range split {
+		out = append(out, TaxableItem{
+			ItemID:        item.ItemID,
+			SKU:           cartItem.SKU,
+			Quantity:      cartItem.Quantity,
+			Subtotal:      surcharge.Amount,
+			Discount:      discounts[i],
+			TaxCode:       surcharge.TaxCode,
+			SurchargeType: surcharge.Type,
+		})
+	}
+	return out
+}
+
+// loadPriceTiers fetches volume tiers once per product in the cart. Tiers are skipped for products
+// priced in a different currency than the cart.
+func (e *CartPricingEngine) loadPriceTiers(ctx context.Context, items []CartItem, currency string) (map[string][]ProductPriceTier, error) {
+	if e.products == nil {
+		return nil, nil
+	}
+	tiers := make(map[string][]ProductPriceTier)
+	seen := make(map[string]struct{})
+	for _, item := range items {
+		productID := strings.TrimSpace(item.ProductID)
+		if productID == "" {
+			continue
+		}
+		if _, ok := seen[productID]; ok {
+			continue
+		}
+		seen[productID] = struct{}{}
+
+		product, err := e.products.GetProduct(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if len(product.PriceTiers) == 0 {
+			continue
+		}
+		productTiers := product.PriceTiers
+		if productCurrency := strings.ToUpper(strings.TrimSpace(product.Currency)); productCurrency != "" && productCurrency != currency {
+			if e.currency == nil {
+				e.logger(ctx, "pricing_tiers_skipped", map[string]any{"productId": productID, "productCurrency": productCurrency, "cartCurrency": currency})
+				continue
+			}
+			productTiers = make([]ProductPriceTier, len(product.PriceTiers))
+			for idx, tier := range product.PriceTiers {
+				converted, _, err := e.currency.Convert(ctx, tier.UnitPrice, productCurrency, currency)
+				if err != nil {
+					return nil, err
+				}
+				tier.UnitPrice = converted
+				productTiers[idx] = tier
+			}
+		}
+		tiers[productID] = productTiers
+	}
+	return tiers, nil
+}
+
+// convertItemCurrencies rewrites unit prices of items quoted in another currency, typically the
+// product BasePrice in the store currency, into the cart currency. The returned slice is aligned
+// with cart.Items and holds the conversion applied to each line.
+func (e *CartPricingEngine) convertItemCurrencies(ctx context.Context, cart *Cart) ([]*FXConversion, []FXConversion, error) {
+	lineConversions := make([]*FXConversion, len(cart.Items))
+	target := strings.ToUpper(strings.TrimSpace(cart.Currency))
+	if e.currency == nil || target == "" {
+		return lineConversions, nil, nil
+	}
+	var items []CartItem
+	var rates []FXConversion
+	bySource := make(map[string]int)
+	for idx, item := range cart.Items {
+		source := strings.ToUpper(strings.TrimSpace(item.Currency))
+		if source == "" || source == target {
+			continue
+		}
+		converted, conv, err := e.currency.Convert(ctx, item.UnitPrice, source, target)
+		if err != nil {
+			return nil, nil, err
+		}
+		if items == nil {
+			items = append([]CartItem(nil), cart.Items...)
+		}
+		item.UnitPrice = converted
+		item.Currency = target
+		items[idx] = item
+		if _, ok := bySource[source]; !ok {
+			bySource[source] = len(rates)
+			rates = append(rates, conv)
+		}
+		recorded := rates[bySource[source]]
+		lineConversions[idx] = &recorded
+	}
+	if items != nil {
+		cart.Items = items
+	}
+	return lineConversions, rates, nil
+}
+
+// selectPriceTier picks the tier with the highest MinQuantity the quantity reaches, so the unit
+// price follows the line quantity every time the cart is priced.
+func selectPriceTier(tiers []ProductPriceTier, quantity int) (ProductPriceTier, bool) {
+	var selected ProductPriceTier
+	found := false
+	for _, tier := range tiers {
+		if tier.MinQuantity <= 0 || tier.UnitPrice < 0 || quantity < tier.MinQuantity {
+			continue
+		}
+		if !found || tier.MinQuantity > selected.MinQuantity {
+			selected = tier
+			found = true
+		}
+	}
+	return selected, found
+}
+
+func (e *CartPricingEngine) calculateTax(ctx context.Context, currency string, cart Cart, items []ItemPricingBreakdown, cartSubtotal, discountTotal, shippingAmount int64, promoCode *string) (TaxQuote, error) {
 	if e.tax == nil {
-		return 0, nil, nil
+		return TaxQuote{}, nil
 	}
 	reqItems := make([]TaxableItem, 0, len(items))
 	for idx, item := range items {
-		cartItem := cart.Items[idx]
-		reqItems = append(reqItems, TaxableItem{
-			ItemID:   item.ItemID,
-			SKU:      cartItem.SKU,
-			Quantity: cartItem.Quantity,
-			Subtotal: item.Subtotal,
-			Discount: item.Discount,
-			TaxCode:  cartItem.TaxCode,
-		})
+		reqItems = append(reqItems, splitTaxableItem(cart.Items[idx], item)...)
 	}
 
 	req := TaxCalculationRequest{
@@ -500,19 +1020,23 @@ func (e *CartPricingEngine) calculateTax(ctx context.Context, currency string, c
 
 	quote, err := e.tax.CalculateTax(ctx, req)
 	if err != nil {
-		return 0, nil, err
+		return TaxQuote{}, err
 	}
 	if quote.Amount < 0 {
-		return 0, nil, fmt.Errorf("%w: tax amount cannot be negative", ErrCartPricingInvalidInput)
+		return TaxQuote{}, detailf(ErrCartPricingInvalidInput, "tax amount cannot be negative")
+	}
+	if quote.Deduction < 0 {
+		return TaxQuote{}, detailf(ErrCartPricingInvalidInput, "tax deduction cannot be negative")
 	}
-	return quote.Amount, quote.Breakdown, nil
+	return quote, nil
 }
 
-func (e *CartPricingEngine) calculateShipping(ctx context.Context, currency string, cart Cart, subtotal int64, discount int64, promoCode *string, bypassCache bool) (int64, []ShippingBreakdown, error) {
+func (e *CartPricingEngine) calculateShipping(ctx context.Context, currency string, cart Cart, subtotal int64, discount int64, promoCode *string, serviceLevel string, bypassCache bool, trace *pricingTrace, rates *[]FXConversion) (int64, []ShippingBreakdown, error) {
 	if e.shipping == nil {
 		return 0, nil, nil
 	}
 	if cart.ShippingAddress == nil {
+		trace.add(PricingStepShipping, "", map[string]any{"skipped": "no shipping address"})
 		return 0, nil, nil
 	}
 
@@ -546,6 +1070,7 @@ func (e *CartPricingEngine) calculateShipping(ctx context.Context, currency stri
 	}
 
 	if !requiresShipment {
+		trace.add(PricingStepShipping, "", map[string]any{"skipped": "no items require shipping"})
 		return 0, nil, nil
 	}
 
@@ -554,35 +1079,93 @@ func (e *CartPricingEngine) calculateShipping(ctx context.Context, currency stri
 		promo = *promoCode
 	}
 
-	cacheKey := buildShippingCacheKey(cart.ShippingAddress, currency, totalWeight, subtotal, discount, promo, shippable)
+	// Estimators priced in another currency quote in their own currency, with the merchandise value
+	// converted for free-shipping thresholds, and the quote is converted back below.
+	source := e.pricingCurrencyOf(e.shipping, currency)
+	if source != currency {
+		var err error
+		if subtotal, err = e.convertAmount(ctx, subtotal, currency, source, nil); err != nil {
+			return 0, nil, err
+		}
+		if discount, err = e.convertAmount(ctx, discount, currency, source, nil); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	serviceLevel = strings.ToLower(strings.TrimSpace(serviceLevel))
+	rateVersion := ""
+	if versioner, ok := e.shipping.(ShippingRateVersioner); ok {
+		rateVersion = versioner.Version()
+	}
+	cacheKey := buildShippingCacheKey(cart.ShippingAddress, rateVersion, source, serviceLevel, totalWeight, subtotal, discount, promo, shippable)
+	quote, cacheHit := ShippingQuote{}, false
 	if !bypassCache {
-		if quote, ok := e.cache.Get(cacheKey); ok {
-			return quote.Amount, quote.Breakdown, nil
+		quote, cacheHit = e.cache.Get(cacheKey)
+	}
+	if !cacheHit {
+		req := ShippingEstimateRequest{
+			Currency:        source,
+			Items:           shippable,
+			ShippingAddress: cart.ShippingAddress,
+			ServiceLevel:    serviceLevel,
+			CartSubtotal:    subtotal,
+			DiscountTotal:   discount,
+			PromotionCode:   promoCode,
+		}
+		var err error
+		quote, err = e.shipping.EstimateShipping(ctx, req)
+		if err != nil {
+			return 0, nil, err
+		}
+		if quote.Amount < 0 {
+			return 0, nil, detailf(ErrCartPricingInvalidInput, "shipping amount cannot be negative")
+		}
+		// Explain runs never write to the cache.
+		if trace == nil {
+			e.cache.Put(cacheKey, quote)
 		}
 	}
 
-	req := ShippingEstimateRequest{
-		Currency:        currency,
-		Items:           shippable,
-		ShippingAddress: cart.ShippingAddress,
-		CartSubtotal:    subtotal,
-		DiscountTotal:   discount,
-		PromotionCode:   promoCode,
+	amount, breakdown := quote.Amount, quote.Breakdown
+	if source != currency {
+		var err error
+		if amount, breakdown, err = e.convertShippingQuote(ctx, quote, source, currency, rates); err != nil {
+			return 0, nil, err
+		}
+	}
+	step := map[string]any{"amount": amount, "serviceLevel": serviceLevel, "weightGrams": totalWeight, "cacheHit": cacheHit}
+	if source != currency {
+		step["originalAmount"] = quote.Amount
+		step["originalCurrency"] = source
 	}
+	trace.add(PricingStepShipping, "", step)
+	return amount, breakdown, nil
+}
 
-	quote, err := e.shipping.EstimateShipping(ctx, req)
+func (e *CartPricingEngine) convertShippingQuote(ctx context.Context, quote ShippingQuote, from, to string, rates *[]FXConversion) (int64, []ShippingBreakdown, error) {
+	amount, err := e.convertAmount(ctx, quote.Amount, from, to, rates)
 	if err != nil {
 		return 0, nil, err
 	}
-	if quote.Amount < 0 {
-		return 0, nil, fmt.Errorf("%w: shipping amount cannot be negative", ErrCartPricingInvalidInput)
+	breakdown := make([]ShippingBreakdown, len(quote.Breakdown))
+	for idx, line := range quote.Breakdown {
+		original := line.Amount
+		if line.Amount, err = e.convertAmount(ctx, line.Amount, from, to, rates); err != nil {
+			return 0, nil, err
+		}
+		line.Currency = to
+		line.Metadata = maps.Clone(line.Metadata)
+		if line.Metadata == nil {
+			line.Metadata = make(map[string]any, 2)
+		}
+		line.Metadata["originalAmount"] = original
+		line.Metadata["originalCurrency"] = from
+		breakdown[idx] = line
 	}
-
-	e.cache.Put(cacheKey, quote)
-	return quote.Amount, quote.Breakdown, nil
+	return amount, breakdown, nil
 }
 
-func distributeTaxAndShipping(items []ItemPricingBreakdown, taxWeights, shippingWeights []int64, taxAmount, shippingAmount int64) {
+func distributeTaxAndShipping(items []ItemPricingBreakdown, taxWeights, shippingWeights []int64, taxAmount, shippingAmount int64, taxIncluded bool) {
 	if len(items) != len(taxWeights) || len(items) != len(shippingWeights) {
 		return
 	}
@@ -591,7 +1174,24 @@ func distributeTaxAndShipping(items []ItemPricingBreakdown, taxWeights, shipping
 	for idx := range items {
 		items[idx].Tax = taxAlloc[idx]
 		items[idx].Shipping = shipAlloc[idx]
-		items[idx].Total = items[idx].Subtotal - items[idx].Discount + taxAlloc[idx] + shipAlloc[idx]
+		items[idx].Total = items[idx].Subtotal - items[idx].Discount + shipAlloc[idx]
+		if !taxIncluded {
+			items[idx].Total += taxAlloc[idx]
+		}
+		if items[idx].Total < 0 {
+			items[idx].Total = 0
+		}
+	}
+}
+
+// deductContainedTax spreads a tax deduction over the line totals in proportion to their taxable
+// amounts, so line totals keep summing to the cart total.
+func deductContainedTax(items []ItemPricingBreakdown, weights []int64, deduction int64) {
+	if deduction <= 0 || len(items) != len(weights) {
+		return
+	}
+	for idx, amount := range allocateByWeight(deduction, weights) {
+		items[idx].Total -= amount
 		if items[idx].Total < 0 {
 			items[idx].Total = 0
 		}
@@ -708,17 +1308,23 @@ func scaleDiscountAllocations(contrib map[string]int64, target int64) map[string
 	return contrib
 }
 
-func buildDiscountBreakdowns(itemTotals map[string]int64, promo []DiscountBreakdown) []DiscountBreakdown {
+func buildDiscountBreakdowns(itemTotals map[string]int64, details map[string]ItemDiscountResult, promo []DiscountBreakdown) []DiscountBreakdown {
 	result := make([]DiscountBreakdown, 0, len(itemTotals)+len(promo))
 	for name, amount := range itemTotals {
 		if amount <= 0 {
 			continue
 		}
+		description := fmt.Sprintf("%s discount", name)
+		detail := details[name]
+		if detail.Description != "" {
+			description = detail.Description
+		}
 		result = append(result, DiscountBreakdown{
 			Type:        "item",
 			Source:      name,
-			Description: fmt.Sprintf("%s discount", name),
+			Description: description,
 			Amount:      amount,
+			Metadata:    detail.Metadata,
 		})
 	}
 	if len(promo) > 0 {
@@ -791,8 +1397,8 @@ func (c *shippingQuoteCache) Put(key string, quote ShippingQuote) {
 	c.mu.Unlock()
 }
 
-func buildShippingCacheKey(addr *Address, currency string, totalWeight, subtotal, discount int64, promo string, items []ShippableItem) string {
-	baseParts := []string{currency, fmt.Sprintf("%d", totalWeight), fmt.Sprintf("%d", subtotal), fmt.Sprintf("%d", discount), strings.ToUpper(strings.TrimSpace(promo))}
+func buildShippingCacheKey(addr *Address, rateVersion, currency, serviceLevel string, totalWeight, subtotal, discount int64, promo string, items []ShippableItem) string {
+	baseParts := []string{strings.TrimSpace(rateVersion), currency, serviceLevel, fmt.Sprintf("%d", totalWeight), fmt.Sprintf("%d", subtotal), fmt.Sprintf("%d", discount), strings.ToUpper(strings.TrimSpace(promo))}
 	if addr != nil {
 		state := ""
 		if addr.State != nil {
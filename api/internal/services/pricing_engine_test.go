@@ -365,6 +365,10 @@ func (f *fakePromotionService) ListPromotionUsage(context.Context, PromotionUsag
 	panic("unexpected call")
 }
 
+func (f *fakePromotionService) ApplyPromotionUsage(context.Context, ApplyPromotionUsageCommand) (PromotionUsage, error) {
+	panic("unexpected call")
+}
+
 type fakeTaxCalculator struct {
 	quote       TaxQuote
 	lastRequest TaxCalculationRequest
@@ -485,3 +489,152 @@ func TestCartPricingEngine_TaxWeightAfterPromo(t *testing.T) {
 		t.Fatalf("expected second item tax to absorb total tax, got %d", res.Breakdown.Items[1].Tax)
 	}
 }
+
+type fakeProductPriceLookup struct {
+	products map[string]Product
+	calls    int
+}
+
+func (f *fakeProductPriceLookup) GetProduct(_ context.Context, productID string) (Product, error) {
+	f.calls++
+	product, ok := f.products[productID]
+	if !ok {
+		return Product{}, errors.New("product not found")
+	}
+	return product, nil
+}
+
+func TestCartPricingEngine_VolumeTiers(t *testing.T) {
+	products := &fakeProductPriceLookup{products: map[string]Product{
+		"prod_seal": {
+			ProductSummary: domain.ProductSummary{ID: "prod_seal", Currency: "JPY"},
+			PriceTiers: []ProductPriceTier{
+				{MinQuantity: 50, UnitPrice: 7000},
+				{MinQuantity: 10, UnitPrice: 7300},
+			},
+		},
+		"prod_usd": {
+			ProductSummary: domain.ProductSummary{ID: "prod_usd", Currency: "USD"},
+			PriceTiers:     []ProductPriceTier{{MinQuantity: 1, UnitPrice: 1}},
+		},
+	}}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion: &fakePromotionService{},
+		Products:  products,
+		ItemRules: []ItemDiscountRule{&fakeItemDiscountRule{name: "seen", fn: func(item CartItem, subtotal int64) int64 {
+			if item.UnitPrice*int64(item.Quantity) != subtotal {
+				t.Errorf("rule saw unit price %d for subtotal %d", item.UnitPrice, subtotal)
+			}
+			return 0
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+
+	price := func(quantity int) ItemPricingBreakdown {
+		t.Helper()
+		res, err := engine.Calculate(context.Background(), PriceCartCommand{Cart: Cart{
+			Currency: "JPY",
+			Items: []CartItem{
+				{ID: "line_1", ProductID: "prod_seal", SKU: "seal", Quantity: quantity, UnitPrice: 8000, Currency: "JPY"},
+				{ID: "line_2", ProductID: "prod_usd", SKU: "case", Quantity: 1, UnitPrice: 500, Currency: "JPY"},
+			},
+		}})
+		if err != nil {
+			t.Fatalf("Calculate error: %v", err)
+		}
+		if res.Breakdown.Items[1].Subtotal != 500 {
+			t.Fatalf("expected tiers in another currency to be ignored, got %+v", res.Breakdown.Items[1])
+		}
+		return res.Breakdown.Items[0]
+	}
+
+	line := price(9)
+	if line.Subtotal != 72000 || line.Metadata["priceTier"] != nil {
+		t.Fatalf("expected base price below the first tier, got %+v", line)
+	}
+
+	line = price(10)
+	tier, _ := line.Metadata["priceTier"].(map[string]any)
+	if line.Subtotal != 73000 || line.Metadata["unitPrice"] != int64(7300) || line.Metadata["baseUnitPrice"] != int64(8000) || tier["minQuantity"] != 10 {
+		t.Fatalf("expected 10+ tier, got %+v", line)
+	}
+
+	line = price(120)
+	if line.Subtotal != 840000 || line.Metadata["unitPrice"] != int64(7000) {
+		t.Fatalf("expected 50+ tier, got %+v", line)
+	}
+	if products.calls != 6 {
+		t.Fatalf("expected one product lookup per product per calculation, got %d", products.calls)
+	}
+}
+
+func TestCartPricingEngine_ExplainTrace(t *testing.T) {
+	shipping := &fakeShippingEstimator{quote: ShippingQuote{Amount: 800}}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{
+		Promotion: &fakePromotionService{results: map[string]PromotionValidationResult{
+			"SPRING": {Code: "SPRING", Eligible: true, DiscountAmount: 500, Reason: "spring sale"},
+		}},
+		Tax:      &fakeTaxCalculator{quote: TaxQuote{Amount: 100, Breakdown: []TaxBreakdown{{Name: "vat", Rate: 0.1, Amount: 100}}}},
+		Shipping: shipping,
+		ItemRules: []ItemDiscountRule{&fakeItemDiscountRule{name: "ten_off", fn: func(_ CartItem, _ int64) int64 {
+			return 10
+		}}},
+		QuoteSecret: []byte("secret"),
+	})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	code := "spring"
+	cmd := PriceCartCommand{
+		Cart: Cart{Currency: "JPY", Items: []CartItem{
+			{ID: "line_1", SKU: "seal", Quantity: 1, UnitPrice: 3000, Currency: "JPY", RequiresShipping: true},
+		}},
+		PromotionCode:   &code,
+		ShippingAddress: &Address{Country: "JP", PostalCode: "100-0001"},
+		Explain:         true,
+	}
+
+	res, err := engine.Calculate(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	steps := map[string][]PricingTraceStep{}
+	for _, step := range res.Trace {
+		steps[step.Step] = append(steps[step.Step], step)
+	}
+	for _, name := range []string{PricingStepInput, PricingStepItemRule, PricingStepPromotion, PricingStepShipping, PricingStepTax, PricingStepTotal} {
+		if len(steps[name]) == 0 {
+			t.Fatalf("expected %s step in trace %+v", name, res.Trace)
+		}
+	}
+	if steps[PricingStepShipping][0].Detail["cacheHit"] != false || steps[PricingStepTotal][0].Detail["total"] != res.Breakdown.Total {
+		t.Fatalf("unexpected trace details %+v", res.Trace)
+	}
+	if res.Estimate.Quote != nil {
+		t.Fatalf("explain runs must not issue quotes")
+	}
+
+	// Explain runs do not populate the shipping cache.
+	if _, err := engine.Calculate(context.Background(), cmd); err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	if shipping.calls != 2 {
+		t.Fatalf("expected explain runs to skip the cache, got %d estimator calls", shipping.calls)
+	}
+	cmd.Explain = false
+	if _, err := engine.Calculate(context.Background(), cmd); err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	cmd.Explain = true
+	res, err = engine.Calculate(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	for _, step := range res.Trace {
+		if step.Step == PricingStepShipping && step.Detail["cacheHit"] != true {
+			t.Fatalf("expected cache hit to be traced, got %+v", step)
+		}
+	}
+}
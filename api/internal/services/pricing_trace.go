@@ -0,0 +1,42 @@
+package services
+
+// Pricing trace steps recorded when PriceCartCommand.Explain is set.
+const (
+	PricingStepInput              = "input"
+	PricingStepCurrencyConversion = "currency_conversion"
+	PricingStepPriceTier          = "price_tier"
+	PricingStepItemRule           = "item_rule"
+	PricingStepSurcharge          = "surcharge"
+	PricingStepPromotion          = "promotion"
+	PricingStepShipping           = "shipping"
+	PricingStepTax                = "tax"
+	PricingStepTotal              = "total"
+)
+
+// PricingTraceStep records one decision taken while pricing a cart. ItemID is empty for cart level
+// steps.
+type PricingTraceStep struct {
+	Step   string
+	ItemID string
+	Detail map[string]any
+}
+
+// pricingTrace collects steps for explain mode. A nil trace records nothing, so Calculate can call it
+// unconditionally.
+type pricingTrace struct {
+	steps []PricingTraceStep
+}
+
+func (t *pricingTrace) add(step, itemID string, detail map[string]any) {
+	if t == nil {
+		return
+	}
+	t.steps = append(t.steps, PricingTraceStep{Step: step, ItemID: itemID, Detail: detail})
+}
+
+func (t *pricingTrace) result() []PricingTraceStep {
+	if t == nil {
+		return nil
+	}
+	return t.steps
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	domain "github.com/hanko-field/api/internal/domain"
+)
+
+const reservationExpiredCancelReason = "reservation_expired"
+
+// ReservationSweeperDeps bundles the collaborators required by the reservation sweeper. Orders is
+// optional; without it reservations are released but their draft orders are left untouched.
+type ReservationSweeperDeps struct {
+	Inventory InventoryService
+	Orders    OrderService
+	Logger    func(ctx context.Context, event string, fields map[string]any)
+}
+
+type reservationSweeper struct {
+	inventory InventoryService
+	orders    OrderService
+	logger    func(context.Context, string, map[string]any)
+}
+
+// NewReservationSweeper constructs a ReservationSweeper.
+func NewReservationSweeper(deps ReservationSweeperDeps) (ReservationSweeper, error) {
+	if deps.Inventory == nil {
+		return nil, errors.New("reservation sweeper: inventory service is required")
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+	return &reservationSweeper{
+		inventory: deps.Inventory,
+		orders:    deps.Orders,
+		logger:    logger,
+	}, nil
+}
+
+// SweepExpired releases reserved reservations past their expiry and cancels the draft orders linked
+// to the released ones. Orders are cancelled even when the sweep stops early with an error.
+func (s *reservationSweeper) SweepExpired(ctx context.Context, cmd ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error) {
+	if strings.TrimSpace(cmd.Reason) == "" {
+		cmd.Reason = defaultExpiredReleaseReason
+	}
+	result, err := s.inventory.ReleaseExpiredReservations(ctx, cmd)
+	s.cancelDraftOrders(ctx, &result, cmd.ActorID)
+	return result, err
+}
+
+// ReleaseReservations releases the listed reservations, as requested by a stock cleanup job, and
+// cancels their draft orders. Reservations already committed or released are skipped.
+func (s *reservationSweeper) ReleaseReservations(ctx context.Context, cmd ReleaseReservationsCommand) (ReleaseExpiredReservationsResult, error) {
+	reason := strings.TrimSpace(cmd.Reason)
+	if reason == "" {
+		reason = defaultExpiredReleaseReason
+	}
+
+	var result ReleaseExpiredReservationsResult
+	seen := make(map[string]struct{}, len(cmd.ReservationIDs))
+	for _, raw := range cmd.ReservationIDs {
+		id := strings.TrimSpace(raw)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		result.CheckedCount++
+		released, err := s.inventory.ReleaseReservation(ctx, InventoryReleaseCommand{
+			ReservationID: id,
+			Reason:        reason,
+			ActorID:       cmd.ActorID,
+		})
+		switch {
+		case err == nil:
+			result.ReleasedCount++
+			result.ReleasedIDs = append(result.ReleasedIDs, id)
+			result.Released = append(result.Released, released)
+		case errors.Is(err, ErrInventoryInvalidState), errors.Is(err, ErrInventoryReservationNotFound):
+			result.SkippedCount++
+		default:
+			result.FailedIDs = append(result.FailedIDs, id)
+			s.logger(ctx, "inventory_cleanup_release_failed", map[string]any{
+				"reservationId": id,
+				"error":         err.Error(),
+			})
+		}
+	}
+	if result.CheckedCount == 0 {
+		return result, detailf(ErrInventoryInvalidInput, "at least one reservation id is required")
+	}
+
+	s.cancelDraftOrders(ctx, &result, cmd.ActorID)
+	return result, nil
+}
+
+// cancelDraftOrders cancels the orders linked to released reservations while they are still drafts.
+// Orders that moved on, for example into payment, are left as they are.
+func (s *reservationSweeper) cancelDraftOrders(ctx context.Context, result *ReleaseExpiredReservationsResult, actorID string) {
+	if s.orders == nil {
+		return
+	}
+	draft := domain.OrderStatusDraft
+	seen := make(map[string]struct{}, len(result.Released))
+	for _, reservation := range result.Released {
+		orderID := strings.TrimPrefix(strings.TrimSpace(reservation.OrderRef), "/orders/")
+		if orderID == "" {
+			continue
+		}
+		if _, ok := seen[orderID]; ok {
+			continue
+		}
+		seen[orderID] = struct{}{}
+
+		_, err := s.orders.Cancel(ctx, CancelOrderCommand{
+			OrderID:        orderID,
+			ActorID:        actorID,
+			Reason:         reservationExpiredCancelReason,
+			ExpectedStatus: &draft,
+			Metadata:       map[string]any{"reservationId": reservation.ID},
+		})
+		switch {
+		case err == nil:
+			result.CanceledOrderIDs = append(result.CanceledOrderIDs, orderID)
+		case errors.Is(err, ErrOrderConflict), errors.Is(err, ErrOrderInvalidState), errors.Is(err, ErrOrderNotFound):
+			// Not a draft any more, or never persisted; nothing to cancel.
+		default:
+			result.OrderCancelFailedIDs = append(result.OrderCancelFailedIDs, orderID)
+			s.logger(ctx, "inventory_expired_order_cancel_failed", map[string]any{
+				"orderId":       orderID,
+				"reservationId": reservation.ID,
+				"error":         err.Error(),
+			})
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/hanko-field/api/internal/domain"
+)
+
+type stubSweeperOrders struct {
+	OrderService
+	cancelFn func(context.Context, CancelOrderCommand) (Order, error)
+	cmds     []CancelOrderCommand
+}
+
+func (s *stubSweeperOrders) Cancel(ctx context.Context, cmd CancelOrderCommand) (Order, error) {
+	s.cmds = append(s.cmds, cmd)
+	if s.cancelFn != nil {
+		return s.cancelFn(ctx, cmd)
+	}
+	return Order{ID: cmd.OrderID}, nil
+}
+
+func TestReservationSweeperSweepExpiredCancelsDraftOrders(t *testing.T) {
+	inventory := &stubInventoryService{
+		expiredFn: func(_ context.Context, cmd ReleaseExpiredReservationsCommand) (ReleaseExpiredReservationsResult, error) {
+			if cmd.Reason != defaultExpiredReleaseReason {
+				t.Fatalf("expected default reason, got %q", cmd.Reason)
+			}
+			return ReleaseExpiredReservationsResult{
+				CheckedCount:  3,
+				ReleasedCount: 3,
+				ReleasedIDs:   []string{"sr_1", "sr_2", "sr_3"},
+				Released: []InventoryReservation{
+					{ID: "sr_1", OrderRef: "/orders/ord_1"},
+					{ID: "sr_2", OrderRef: "/orders/ord_2"},
+					{ID: "sr_3"},
+				},
+			}, nil
+		},
+	}
+	orders := &stubSweeperOrders{
+		cancelFn: func(_ context.Context, cmd CancelOrderCommand) (Order, error) {
+			if cmd.OrderID == "ord_2" {
+				return Order{}, ErrOrderInvalidState
+			}
+			return Order{ID: cmd.OrderID}, nil
+		},
+	}
+	sweeper, err := NewReservationSweeper(ReservationSweeperDeps{Inventory: inventory, Orders: orders})
+	if err != nil {
+		t.Fatalf("NewReservationSweeper: %v", err)
+	}
+
+	result, err := sweeper.SweepExpired(context.Background(), ReleaseExpiredReservationsCommand{ActorID: "svc"})
+	if err != nil {
+		t.Fatalf("SweepExpired: %v", err)
+	}
+	if len(orders.cmds) != 2 {
+		t.Fatalf("expected two cancel attempts, got %d", len(orders.cmds))
+	}
+	first := orders.cmds[0]
+	if first.ExpectedStatus == nil || *first.ExpectedStatus != domain.OrderStatusDraft || first.Reason != reservationExpiredCancelReason || first.ActorID != "svc" {
+		t.Fatalf("unexpected cancel command %+v", first)
+	}
+	if len(result.CanceledOrderIDs) != 1 || result.CanceledOrderIDs[0] != "ord_1" || len(result.OrderCancelFailedIDs) != 0 {
+		t.Fatalf("unexpected cancel outcome %+v", result)
+	}
+}
+
+func TestReservationSweeperReleaseReservations(t *testing.T) {
+	inventory := &stubInventoryService{
+		releaseFn: func(_ context.Context, cmd InventoryReleaseCommand) (InventoryReservation, error) {
+			switch cmd.ReservationID {
+			case "sr_committed":
+				return InventoryReservation{}, ErrInventoryInvalidState
+			case "sr_broken":
+				return InventoryReservation{}, errors.New("boom")
+			}
+			return InventoryReservation{ID: cmd.ReservationID, OrderRef: "/orders/ord_" + cmd.ReservationID}, nil
+		},
+	}
+	orders := &stubSweeperOrders{
+		cancelFn: func(context.Context, CancelOrderCommand) (Order, error) {
+			return Order{}, errors.New("unavailable")
+		},
+	}
+	sweeper, err := NewReservationSweeper(ReservationSweeperDeps{Inventory: inventory, Orders: orders})
+	if err != nil {
+		t.Fatalf("NewReservationSweeper: %v", err)
+	}
+
+	result, err := sweeper.ReleaseReservations(context.Background(), ReleaseReservationsCommand{
+		ReservationIDs: []string{"sr_1", " sr_1 ", "sr_committed", "sr_broken", ""},
+	})
+	if err != nil {
+		t.Fatalf("ReleaseReservations: %v", err)
+	}
+	if result.CheckedCount != 3 || result.ReleasedCount != 1 || result.SkippedCount != 1 || len(result.FailedIDs) != 1 {
+		t.Fatalf("unexpected counts %+v", result)
+	}
+	if len(result.OrderCancelFailedIDs) != 1 || result.OrderCancelFailedIDs[0] != "ord_sr_1" {
+		t.Fatalf("expected failed order cancel recorded, got %+v", result.OrderCancelFailedIDs)
+	}
+
+	if _, err := sweeper.ReleaseReservations(context.Background(), ReleaseReservationsCommand{}); !errors.Is(err, ErrInventoryInvalidInput) {
+		t.Fatalf("expected invalid input for empty ids, got %v", err)
+	}
+}
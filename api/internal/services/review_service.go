@@ -39,6 +39,8 @@ var (
 type ReviewServiceDeps struct {
 	Reviews              repositories.ReviewRepository
 	Orders               repositories.OrderRepository
+	Ratings              repositories.ProductRatingRepository
+	UnitOfWork           repositories.UnitOfWork
 	Clock                func() time.Time
 	IDGenerator          func() string
 	Sanitizer            func(string) string
@@ -50,6 +52,8 @@ type ReviewServiceDeps struct {
 type reviewService struct {
 	reviews                repositories.ReviewRepository
 	orders                 repositories.OrderRepository
+	ratings                repositories.ProductRatingRepository
+	unitOfWork             repositories.UnitOfWork
 	clock                  func() time.Time
 	newID                  func() string
 	sanitize               func(string) string
@@ -95,14 +99,21 @@ func NewReviewService(deps ReviewServiceDeps) (ReviewService, error) {
 		}
 	}
 
+	unit := deps.UnitOfWork
+	if unit == nil {
+		unit = noopUnitOfWork{}
+	}
+
 	completed := make(map[domain.OrderStatus]struct{}, len(orderStatuses))
 	for _, status := range orderStatuses {
 		completed[status] = struct{}{}
 	}
 
 	return &reviewService{
-		reviews: deps.Reviews,
-		orders:  deps.Orders,
+		reviews:    deps.Reviews,
+		orders:     deps.Orders,
+		ratings:    deps.Ratings,
+		unitOfWork: unit,
 		clock: func() time.Time {
 			return clock().UTC()
 		},
@@ -129,10 +140,10 @@ func (s *reviewService) Create(ctx context.Context, cmd CreateReviewCommand) (Re
 	}
 
 	if order.UserID != cmd.UserID {
-		return Review{}, fmt.Errorf("%w: order does not belong to user", ErrReviewInvalidInput)
+		return Review{}, detailf(ErrReviewInvalidInput, "order does not belong to user")
 	}
 	if _, ok := s.completedOrderStatuses[order.Status]; !ok {
-		return Review{}, fmt.Errorf("%w: order must be completed before review submission", ErrReviewInvalidInput)
+		return Review{}, detailf(ErrReviewInvalidInput, "order must be completed before review submission")
 	}
 
 	if err := s.ensureNoExistingReview(ctx, cmd.OrderID); err != nil {
@@ -178,7 +189,7 @@ func (s *reviewService) GetByOrder(ctx context.Context, cmd GetReviewByOrderComm
 
 func (s *reviewService) ListByUser(ctx context.Context, cmd ListUserReviewsCommand) (domain.CursorPage[Review], error) {
 	if strings.TrimSpace(cmd.UserID) == "" {
-		return domain.CursorPage[Review]{}, fmt.Errorf("%w: user id is required", ErrReviewInvalidInput)
+		return domain.CursorPage[Review]{}, detailf(ErrReviewInvalidInput, "user id is required")
 	}
 	page, err := s.reviews.ListByUser(ctx, cmd.UserID, cmd.Pagination)
 	if err != nil {
@@ -190,6 +201,36 @@ func (s *reviewService) ListByUser(ctx context.Context, cmd ListUserReviewsComma
 	}, nil
 }
 
+func (s *reviewService) ListForModeration(ctx context.Context, cmd ListReviewsForModerationCommand) (domain.CursorPage[Review], error) {
+	statuses := make([]domain.ReviewStatus, 0, len(cmd.Status))
+	seen := make(map[domain.ReviewStatus]struct{}, len(cmd.Status))
+	for _, raw := range cmd.Status {
+		status := domain.ReviewStatus(strings.ToLower(strings.TrimSpace(string(raw))))
+		switch status {
+		case domain.ReviewStatusPending, domain.ReviewStatusApproved, domain.ReviewStatusRejected:
+		default:
+			return domain.CursorPage[Review]{}, detailf(ErrReviewInvalidInput, "unsupported review status %q", raw)
+		}
+		if _, ok := seen[status]; ok {
+			continue
+		}
+		seen[status] = struct{}{}
+		statuses = append(statuses, status)
+	}
+
+	page, err := s.reviews.List(ctx, repositories.ReviewListFilter{
+		Status:     statuses,
+		Pagination: cmd.Pagination,
+	})
+	if err != nil {
+		return domain.CursorPage[Review]{}, s.mapReviewError(err)
+	}
+	return domain.CursorPage[Review]{
+		Items:         page.Items,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
 func (s *reviewService) Moderate(ctx context.Context, cmd ModerateReviewCommand) (Review, error) {
 	if err := s.validateModerationCommand(cmd); err != nil {
 		return Review{}, err
@@ -205,16 +246,37 @@ func (s *reviewService) Moderate(ctx context.Context, cmd ModerateReviewCommand)
 	}
 
 	if review.Status != domain.ReviewStatusPending {
-		return Review{}, fmt.Errorf("%w: cannot transition from %s to %s", ErrReviewInvalidState, review.Status, cmd.Status)
+		return Review{}, detailf(ErrReviewInvalidState, "cannot transition from %s to %s", review.Status, cmd.Status)
+	}
+
+	// A rating leaves the aggregates of the products it was counted against, which the review
+	// recorded when it was approved; only a newly counted rating needs the order's products.
+	productIDs := normalizeProductIDs(review.ProductIDs)
+	if review.Status != domain.ReviewStatusApproved {
+		productIDs, err = s.resolveReviewProducts(ctx, review)
+		if err != nil {
+			return Review{}, err
+		}
 	}
 
 	now := s.now()
-	updated, err := s.reviews.UpdateStatus(ctx, cmd.ReviewID, cmd.Status, repositories.ReviewModerationUpdate{
-		ModeratedBy: cmd.ActorID,
-		ModeratedAt: now,
+	var updated Review
+	// The status write and the aggregate deltas commit together so a failed delta cannot leave the
+	// review moderated with aggregates that never counted it.
+	err = s.unitOfWork.RunInTx(ctx, func(txCtx context.Context) error {
+		var txErr error
+		updated, txErr = s.reviews.UpdateStatus(txCtx, cmd.ReviewID, cmd.Status, repositories.ReviewModerationUpdate{
+			ModeratedBy: cmd.ActorID,
+			ModeratedAt: now,
+			ProductIDs:  productIDs,
+		})
+		if txErr != nil {
+			return s.mapReviewError(txErr)
+		}
+		return s.applyRatingChange(txCtx, review.Status, cmd.Status, review.Rating, productIDs, now)
 	})
 	if err != nil {
-		return Review{}, s.mapReviewError(err)
+		return Review{}, err
 	}
 
 	switch cmd.Status {
@@ -238,12 +300,12 @@ func (s *reviewService) StoreReply(ctx context.Context, cmd StoreReviewReplyComm
 	}
 
 	if review.Status != domain.ReviewStatusApproved {
-		return Review{}, fmt.Errorf("%w: replies allowed only for approved reviews", ErrReviewInvalidState)
+		return Review{}, detailf(ErrReviewInvalidState, "replies allowed only for approved reviews")
 	}
 
 	message := s.sanitize(cmd.Message)
 	if message != "" && s.isProfane(message) {
-		return Review{}, fmt.Errorf("%w: reply contains profanity", ErrReviewInvalidInput)
+		return Review{}, detailf(ErrReviewInvalidInput, "reply contains profanity")
 	}
 
 	updateAt := s.now()
@@ -273,59 +335,171 @@ func (s *reviewService) StoreReply(ctx context.Context, cmd StoreReviewReplyComm
 	return updated, nil
 }
 
+func (s *reviewService) ListPublicByProduct(ctx context.Context, cmd ListProductReviewsCommand) (domain.CursorPage[Review], error) {
+	productID := strings.TrimSpace(cmd.ProductID)
+	if productID == "" {
+		return domain.CursorPage[Review]{}, detailf(ErrReviewInvalidInput, "product id is required")
+	}
+
+	page, err := s.reviews.List(ctx, repositories.ReviewListFilter{
+		Status:     []domain.ReviewStatus{domain.ReviewStatusApproved},
+		ProductID:  productID,
+		SortOrder:  domain.SortDesc,
+		Pagination: cmd.Pagination,
+	})
+	if err != nil {
+		return domain.CursorPage[Review]{}, s.mapReviewError(err)
+	}
+	return domain.CursorPage[Review]{
+		Items:         page.Items,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+func (s *reviewService) GetProductRatings(ctx context.Context, productIDs []string) (map[string]ProductRatingSummary, error) {
+	ids := normalizeProductIDs(productIDs)
+	result := make(map[string]ProductRatingSummary, len(ids))
+	if len(ids) == 0 || s.ratings == nil {
+		return result, nil
+	}
+
+	summaries, err := s.ratings.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if summary, ok := summaries[id]; ok {
+			result[id] = summary
+		}
+	}
+	return result, nil
+}
+
+// resolveReviewProducts returns the products purchased in the reviewed order, falling back to the
+// products already recorded on the review when the order cannot be loaded.
+func (s *reviewService) resolveReviewProducts(ctx context.Context, review domain.Review) ([]string, error) {
+	orderID := strings.TrimSpace(review.OrderRef)
+	if orderID == "" {
+		return normalizeProductIDs(review.ProductIDs), nil
+	}
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		var repoErr repositories.RepositoryError
+		if errors.As(err, &repoErr) && repoErr.IsNotFound() {
+			return normalizeProductIDs(review.ProductIDs), nil
+		}
+		return nil, err
+	}
+
+	refs := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		refs = append(refs, item.ProductRef)
+	}
+	return normalizeProductIDs(refs), nil
+}
+
+// applyRatingChange keeps product rating aggregates in step with review visibility. Only approved
+// reviews contribute, so the aggregate moves when a review enters or leaves the approved state.
+func (s *reviewService) applyRatingChange(ctx context.Context, from, to domain.ReviewStatus, rating int, productIDs []string, at time.Time) error {
+	if s.ratings == nil || len(productIDs) == 0 {
+		return nil
+	}
+
+	var count int
+	switch {
+	case from != domain.ReviewStatusApproved && to == domain.ReviewStatusApproved:
+		count = 1
+	case from == domain.ReviewStatusApproved && to != domain.ReviewStatusApproved:
+		count = -1
+	default:
+		return nil
+	}
+
+	for _, productID := range productIDs {
+		if _, err := s.ratings.ApplyDelta(ctx, productID, repositories.ProductRatingDelta{
+			Rating:    rating,
+			Count:     count,
+			UpdatedAt: at,
+		}); err != nil {
+			return fmt.Errorf("review: update rating for product %s: %w", productID, err)
+		}
+	}
+	return nil
+}
+
+func normalizeProductIDs(refs []string) []string {
+	if len(refs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(refs))
+	seen := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		id := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(ref), "/products/"))
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (s *reviewService) validateCreateCommand(cmd CreateReviewCommand) error {
 	if strings.TrimSpace(cmd.OrderID) == "" {
-		return fmt.Errorf("%w: order id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "order id is required")
 	}
 	if strings.TrimSpace(cmd.UserID) == "" {
-		return fmt.Errorf("%w: user id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "user id is required")
 	}
 	if strings.TrimSpace(cmd.ActorID) == "" {
-		return fmt.Errorf("%w: actor id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "actor id is required")
 	}
 	if cmd.Rating < 1 || cmd.Rating > 5 {
-		return fmt.Errorf("%w: rating must be between 1 and 5", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "rating must be between 1 and 5")
 	}
 
 	comment := s.sanitize(cmd.Comment)
 	if comment == "" {
-		return fmt.Errorf("%w: comment is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "comment is required")
 	}
 	if s.isProfane(comment) {
-		return fmt.Errorf("%w: comment contains profanity", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "comment contains profanity")
 	}
 	return nil
 }
 
 func (s *reviewService) validateModerationCommand(cmd ModerateReviewCommand) error {
 	if strings.TrimSpace(cmd.ReviewID) == "" {
-		return fmt.Errorf("%w: review id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "review id is required")
 	}
 	if strings.TrimSpace(cmd.ActorID) == "" {
-		return fmt.Errorf("%w: actor id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "actor id is required")
 	}
 	if _, ok := s.allowedStatuses[cmd.Status]; !ok {
-		return fmt.Errorf("%w: unsupported moderation status %s", ErrReviewInvalidInput, cmd.Status)
+		return detailf(ErrReviewInvalidInput, "unsupported moderation status %s", cmd.Status)
 	}
 	return nil
 }
 
 func (s *reviewService) validateReplyCommand(cmd StoreReviewReplyCommand) error {
 	if strings.TrimSpace(cmd.ReviewID) == "" {
-		return fmt.Errorf("%w: review id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "review id is required")
 	}
 	if strings.TrimSpace(cmd.ActorID) == "" {
-		return fmt.Errorf("%w: actor id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "actor id is required")
 	}
 	return nil
 }
 
 func (s *reviewService) validateGetByOrderCommand(cmd GetReviewByOrderCommand) error {
 	if strings.TrimSpace(cmd.OrderID) == "" {
-		return fmt.Errorf("%w: order id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "order id is required")
 	}
 	if strings.TrimSpace(cmd.ActorID) == "" {
-		return fmt.Errorf("%w: actor id is required", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "actor id is required")
 	}
 	return nil
 }
@@ -333,7 +507,7 @@ func (s *reviewService) validateGetByOrderCommand(cmd GetReviewByOrderCommand) e
 func (s *reviewService) ensureNoExistingReview(ctx context.Context, orderID string) error {
 	_, err := s.reviews.FindByOrder(ctx, orderID)
 	if err == nil {
-		return fmt.Errorf("%w: review already exists for order", ErrReviewConflict)
+		return detailf(ErrReviewConflict, "review already exists for order")
 	}
 	var repoErr repositories.RepositoryError
 	if errors.As(err, &repoErr) && repoErr.IsNotFound() {
@@ -389,7 +563,7 @@ func (s *reviewService) mapOrderError(err error) error {
 	}
 	var repoErr repositories.RepositoryError
 	if errors.As(err, &repoErr) && repoErr.IsNotFound() {
-		return fmt.Errorf("%w: order not found", ErrReviewInvalidInput)
+		return detailf(ErrReviewInvalidInput, "order not found")
 	}
 	return err
 }
@@ -234,6 +234,167 @@ func TestReviewServiceModerateTransitionsAndEmitsEvent(t *testing.T) {
 	}
 }
 
+func TestReviewServiceListForModerationFiltersByStatus(t *testing.T) {
+	base := time.Date(2025, 5, 20, 9, 0, 0, 0, time.UTC)
+	repo := newMemoryReviewRepo()
+	seed := []domain.Review{
+		{ID: "rev_b", OrderRef: "order-b", Status: domain.ReviewStatusPending, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "rev_a", OrderRef: "order-a", Status: domain.ReviewStatusPending, CreatedAt: base.Add(time.Minute)},
+		{ID: "rev_c", OrderRef: "order-c", Status: domain.ReviewStatusApproved, CreatedAt: base},
+	}
+	for _, review := range seed {
+		if _, err := repo.Insert(context.Background(), review); err != nil {
+			t.Fatalf("seed insert: %v", err)
+		}
+	}
+
+	svc, err := NewReviewService(ReviewServiceDeps{Reviews: repo, Orders: &stubOrderRepository{}})
+	if err != nil {
+		t.Fatalf("new review service: %v", err)
+	}
+
+	page, err := svc.ListForModeration(context.Background(), ListReviewsForModerationCommand{
+		Status:     []ReviewStatus{" Pending "},
+		Pagination: Pagination{PageSize: 1},
+	})
+	if err != nil {
+		t.Fatalf("list for moderation: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != "rev_a" {
+		t.Fatalf("expected oldest pending review first, got %+v", page.Items)
+	}
+	if page.NextPageToken == "" {
+		t.Fatalf("expected next page token")
+	}
+
+	_, err = svc.ListForModeration(context.Background(), ListReviewsForModerationCommand{
+		Status: []ReviewStatus{"archived"},
+	})
+	if !errors.Is(err, ErrReviewInvalidInput) {
+		t.Fatalf("expected invalid input for unknown status, got %v", err)
+	}
+}
+
+func TestReviewServiceModerateMaintainsProductRatings(t *testing.T) {
+	now := time.Date(2025, 5, 21, 8, 0, 0, 0, time.UTC)
+	repo := newMemoryReviewRepo()
+	seed := []domain.Review{
+		{ID: "rev_a", OrderRef: "order-a", UserRef: "user-1", Rating: 4, Status: domain.ReviewStatusPending, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "rev_b", OrderRef: "order-b", UserRef: "user-2", Rating: 2, Status: domain.ReviewStatusPending, CreatedAt: now.Add(-time.Hour)},
+	}
+	for _, review := range seed {
+		if _, err := repo.Insert(context.Background(), review); err != nil {
+			t.Fatalf("seed insert: %v", err)
+		}
+	}
+	orderRepo := &stubOrderRepository{orders: map[string]domain.Order{
+		"order-a": {ID: "order-a", Items: []domain.OrderLineItem{{ProductRef: "/products/prod-1"}, {ProductRef: "prod-2"}, {ProductRef: "prod-1"}}},
+		"order-b": {ID: "order-b", Items: []domain.OrderLineItem{{ProductRef: "prod-1"}}},
+	}}
+	ratings := newMemoryRatingRepo()
+
+	svc, err := NewReviewService(ReviewServiceDeps{
+		Reviews: repo,
+		Orders:  orderRepo,
+		Ratings: ratings,
+		Clock:   func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("new review service: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := svc.Moderate(ctx, ModerateReviewCommand{ReviewID: "rev_a", ActorID: "mod", Status: domain.ReviewStatusApproved}); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := svc.Moderate(ctx, ModerateReviewCommand{ReviewID: "rev_b", ActorID: "mod", Status: domain.ReviewStatusRejected}); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+
+	summaries, err := svc.GetProductRatings(ctx, []string{"prod-1", "prod-2", "prod-3"})
+	if err != nil {
+		t.Fatalf("get ratings: %v", err)
+	}
+	prod1 := summaries["prod-1"]
+	if prod1.ReviewCount != 1 || prod1.AverageRating != 4 || prod1.Histogram[3] != 1 {
+		t.Fatalf("unexpected prod-1 summary %+v", prod1)
+	}
+	if summaries["prod-2"].ReviewCount != 1 {
+		t.Fatalf("expected prod-2 to count approved review, got %+v", summaries["prod-2"])
+	}
+	if _, ok := summaries["prod-3"]; ok {
+		t.Fatalf("expected no summary for prod-3")
+	}
+
+	page, err := svc.ListPublicByProduct(ctx, ListProductReviewsCommand{ProductID: "prod-1"})
+	if err != nil {
+		t.Fatalf("list public: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != "rev_a" {
+		t.Fatalf("expected only approved review, got %+v", page.Items)
+	}
+}
+
+func TestReviewServiceModerateRollsBackWhenRatingUpdateFails(t *testing.T) {
+	now := time.Date(2025, 5, 21, 8, 0, 0, 0, time.UTC)
+	repo := newMemoryReviewRepo()
+	if _, err := repo.Insert(context.Background(), domain.Review{ID: "rev_a", OrderRef: "order-a", UserRef: "user-1", Rating: 5, Status: domain.ReviewStatusPending}); err != nil {
+		t.Fatalf("seed insert: %v", err)
+	}
+	orderRepo := &stubOrderRepository{orders: map[string]domain.Order{
+		"order-a": {ID: "order-a", Items: []domain.OrderLineItem{{ProductRef: "prod-1"}, {ProductRef: "prod-2"}}},
+	}}
+	ratings := newMemoryRatingRepo()
+	ratings.failFor = map[string]error{"prod-2": errors.New("ratings unavailable")}
+	// The unit of work restores both stores when the transaction fails, as Firestore would.
+	unit := &stubUnitOfWork{runFn: func(ctx context.Context, fn func(context.Context) error) error {
+		reviews := make(map[string]domain.Review, len(repo.reviews))
+		for id, review := range repo.reviews {
+			reviews[id] = copyReview(review)
+		}
+		summaries := make(map[string]domain.ProductRatingSummary, len(ratings.summaries))
+		for id, summary := range ratings.summaries {
+			summaries[id] = summary
+		}
+		if err := fn(ctx); err != nil {
+			repo.reviews = reviews
+			ratings.summaries = summaries
+			return err
+		}
+		return nil
+	}}
+
+	svc, err := NewReviewService(ReviewServiceDeps{
+		Reviews:    repo,
+		Orders:     orderRepo,
+		Ratings:    ratings,
+		UnitOfWork: unit,
+		Clock:      func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("new review service: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := svc.Moderate(ctx, ModerateReviewCommand{ReviewID: "rev_a", ActorID: "mod", Status: domain.ReviewStatusApproved}); err == nil {
+		t.Fatalf("expected rating failure to fail moderation")
+	}
+	if got := repo.reviews["rev_a"].Status; got != domain.ReviewStatusPending {
+		t.Fatalf("expected review to stay pending, got %s", got)
+	}
+	if _, ok := ratings.summaries["prod-1"]; ok {
+		t.Fatalf("expected prod-1 delta to be rolled back")
+	}
+
+	ratings.failFor = nil
+	if _, err := svc.Moderate(ctx, ModerateReviewCommand{ReviewID: "rev_a", ActorID: "mod", Status: domain.ReviewStatusApproved}); err != nil {
+		t.Fatalf("retry approve: %v", err)
+	}
+	if ratings.summaries["prod-1"].ReviewCount != 1 || ratings.summaries["prod-2"].ReviewCount != 1 {
+		t.Fatalf("expected both products to count the review once, got %+v", ratings.summaries)
+	}
+}
+
 func TestReviewServiceGetByOrderAuthorization(t *testing.T) {
 	now := time.Date(2025, 5, 20, 12, 30, 0, 0, time.UTC)
 	repo := newMemoryReviewRepo()
@@ -453,6 +614,48 @@ func (m *memoryReviewRepo) ListByUser(_ context.Context, userID string, pager do
 		}
 	})
 
+	return paginateReviews(results, pager), nil
+}
+
+func (m *memoryReviewRepo) List(_ context.Context, filter repositories.ReviewListFilter) (domain.CursorPage[domain.Review], error) {
+	allowed := make(map[domain.ReviewStatus]struct{}, len(filter.Status))
+	for _, status := range filter.Status {
+		allowed[status] = struct{}{}
+	}
+
+	var results []domain.Review
+	for _, review := range m.reviews {
+		if len(allowed) > 0 {
+			if _, ok := allowed[review.Status]; !ok {
+				continue
+			}
+		}
+		if filter.ProductID != "" && !slices.Contains(review.ProductIDs, filter.ProductID) {
+			continue
+		}
+		results = append(results, copyReview(review))
+	}
+
+	slices.SortFunc(results, func(a, b domain.Review) int {
+		cmp := 0
+		switch {
+		case a.CreatedAt.Before(b.CreatedAt):
+			cmp = -1
+		case a.CreatedAt.After(b.CreatedAt):
+			cmp = 1
+		default:
+			cmp = strings.Compare(a.ID, b.ID)
+		}
+		if filter.SortOrder == domain.SortDesc {
+			return -cmp
+		}
+		return cmp
+	})
+
+	return paginateReviews(results, filter.Pagination), nil
+}
+
+func paginateReviews(results []domain.Review, pager domain.Pagination) domain.CursorPage[domain.Review] {
 	start := 0
 	if token, err := strconv.Atoi(pager.PageToken); err == nil {
 		switch {
@@ -492,7 +695,7 @@ func (m *memoryReviewRepo) ListByUser(_ context.Context, userID string, pager do
 	return domain.CursorPage[domain.Review]{
 		Items:         pageItems,
 		NextPageToken: nextToken,
-	}, nil
+	}
 }
 
 func (m *memoryReviewRepo) UpdateStatus(_ context.Context, reviewID string, status domain.ReviewStatus, update repositories.ReviewModerationUpdate) (domain.Review, error) {
@@ -504,6 +707,9 @@ func (m *memoryReviewRepo) UpdateStatus(_ context.Context, reviewID string, stat
 	review.UpdatedAt = update.ModeratedAt
 	review.ModeratedAt = &update.ModeratedAt
 	review.ModeratedBy = &update.ModeratedBy
+	if len(update.ProductIDs) > 0 {
+		review.ProductIDs = append([]string(nil), update.ProductIDs...)
+	}
 	m.reviews[reviewID] = copyReview(review)
 	return copyReview(review), nil
 }
@@ -524,6 +730,55 @@ func (m *memoryReviewRepo) UpdateReply(_ context.Context, reviewID string, reply
 	return copyReview(review), nil
 }
 
+type memoryRatingRepo struct {
+	summaries map[string]domain.ProductRatingSummary
+	failFor   map[string]error
+}
+
+func newMemoryRatingRepo() *memoryRatingRepo {
+	return &memoryRatingRepo{summaries: make(map[string]domain.ProductRatingSummary)}
+}
+
+func (m *memoryRatingRepo) Get(_ context.Context, productID string) (domain.ProductRatingSummary, error) {
+	summary, ok := m.summaries[productID]
+	if !ok {
+		return domain.ProductRatingSummary{}, repoError{message: "not found", notFound: true}
+	}
+	return summary, nil
+}
+
+func (m *memoryRatingRepo) GetMany(_ context.Context, productIDs []string) (map[string]domain.ProductRatingSummary, error) {
+	result := make(map[string]domain.ProductRatingSummary, len(productIDs))
+	for _, id := range productIDs {
+		if summary, ok := m.summaries[id]; ok {
+			result[id] = summary
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryRatingRepo) ApplyDelta(_ context.Context, productID string, delta repositories.ProductRatingDelta) (domain.ProductRatingSummary, error) {
+	if err := m.failFor[productID]; err != nil {
+		return domain.ProductRatingSummary{}, err
+	}
+	summary := m.summaries[productID]
+	summary.ProductID = productID
+	summary.Histogram[delta.Rating-1] += delta.Count
+	summary.ReviewCount = 0
+	total := 0
+	for i, count := range summary.Histogram {
+		summary.ReviewCount += count
+		total += count * (i + 1)
+	}
+	summary.AverageRating = 0
+	if summary.ReviewCount > 0 {
+		summary.AverageRating = float64(total) / float64(summary.ReviewCount)
+	}
+	summary.UpdatedAt = delta.UpdatedAt
+	m.summaries[productID] = summary
+	return summary, nil
+}
+
 type repoError struct {
 	message  string
 	notFound bool
@@ -576,6 +831,7 @@ func (s *stubOrderRepository) List(context.Context, repositories.OrderListFilter
 
 func copyReview(in domain.Review) domain.Review {
 	out := in
+	out.ProductIDs = append([]string(nil), in.ProductIDs...)
 	if in.Reply != nil {
 		replyCopy := *in.Reply
 		out.Reply = &replyCopy
@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrShippingUnavailable reports that the rate table cannot serve a destination, weight or service level.
+var ErrShippingUnavailable = errors.New("shipping: no rate available")
+
+// Service levels commonly offered by Japanese carriers. Rate tables may define additional levels.
+const (
+	ShippingServiceStandard = "standard"
+	ShippingServiceExpress  = "express"
+	ShippingServiceCool     = "cool"
+)
+
+const defaultShippingReloadInterval = time.Minute
+
+// ShippingRateTable is the versioned document read by TableShippingEstimator. Zones group the
+// destination prefectures as seen from the table's origin warehouse, or destination countries for
+// international service, and every service level prices each zone per weight bracket.
+type ShippingRateTable struct {
+	Version             string                 `json:"version" yaml:"version"`
+	Currency            string                 `json:"currency" yaml:"currency"`
+	Origin              string                 `json:"origin" yaml:"origin"`
+	DefaultServiceLevel string                 `json:"default_service_level" yaml:"default_service_level"`
+	Zones               []ShippingZone         `json:"zones" yaml:"zones"`
+	Services            []ShippingServiceRates `json:"services" yaml:"services"`
+	RemoteAreas         []ShippingRemoteArea   `json:"remote_areas" yaml:"remote_areas"`
+}
+
+// ShippingZone lists destination prefectures, or ISO country codes for international zones, and the
+// delivery days per service level.
+type ShippingZone struct {
+	Name         string         `json:"name" yaml:"name"`
+	Prefectures  []string       `json:"prefectures" yaml:"prefectures"`
+	Countries    []string       `json:"countries" yaml:"countries"`
+	EstimateDays map[string]int `json:"estimate_days" yaml:"estimate_days"`
+}
+
+// ShippingServiceRates prices one service level. Brackets are matched by the smallest MaxGrams that
+// fits the parcel weight. A positive FreeShippingThreshold waives the base rate once the discounted
+// cart subtotal reaches it; remote area surcharges still apply.
+type ShippingServiceRates struct {
+	Level                 string                  `json:"level" yaml:"level"`
+	Carrier               string                  `json:"carrier" yaml:"carrier"`
+	FreeShippingThreshold int64                   `json:"free_shipping_threshold" yaml:"free_shipping_threshold"`
+	Brackets              []ShippingWeightBracket `json:"brackets" yaml:"brackets"`
+}
+
+// ShippingWeightBracket maps zone names to the rate for parcels up to MaxGrams.
+type ShippingWeightBracket struct {
+	MaxGrams int64            `json:"max_grams" yaml:"max_grams"`
+	Rates    map[string]int64 `json:"rates" yaml:"rates"`
+}
+
+// ShippingRemoteArea adds a surcharge and extra delivery days for postal codes such as remote islands.
+// Services restricts which service levels may ship there; empty allows all.
+type ShippingRemoteArea struct {
+	Name           string   `json:"name" yaml:"name"`
+	PostalPrefixes []string `json:"postal_prefixes" yaml:"postal_prefixes"`
+	Surcharge      int64    `json:"surcharge" yaml:"surcharge"`
+	ExtraDays      int      `json:"extra_days" yaml:"extra_days"`
+	Services       []string `json:"services" yaml:"services"`
+}
+
+// TableShippingEstimatorDeps configures TableShippingEstimator.
+type TableShippingEstimatorDeps struct {
+	// Path points at a .json, .yaml or .yml rate table.
+	Path   string
+	Logger func(context.Context, string, map[string]any)
+}
+
+// TableShippingEstimator implements ShippingEstimator from a rate table file. Reload and Watch pick up
+// edits to the file without a restart; a table that fails validation is rejected and the previous
+// version keeps serving.
+type TableShippingEstimator struct {
+	path   string
+	logger func(context.Context, string, map[string]any)
+
+	mu       sync.RWMutex
+	table    *compiledShippingTable
+	modTime  time.Time
+	size     int64
+	checksum string
+}
+
+type compiledShippingTable struct {
+	version      string
+	currency     string
+	origin       string
+	defaultLevel string
+	prefectures  map[string]*ShippingZone
+	countries    map[string]*ShippingZone
+	services     map[string]ShippingServiceRates
+	remoteAreas  []ShippingRemoteArea
+}
+
+// NewTableShippingEstimator loads and validates the rate table at deps.Path.
+func NewTableShippingEstimator(deps TableShippingEstimatorDeps) (*TableShippingEstimator, error) {
+	path := strings.TrimSpace(deps.Path)
+	if path == "" {
+		return nil, errors.New("shipping estimator: rate table path is required")
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+	estimator := &TableShippingEstimator{path: path, logger: logger}
+	if _, err := estimator.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+	return estimator, nil
+}
+
+// Version returns the version of the rate table currently in use.
+func (e *TableShippingEstimator) Version() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.table == nil {
+		return ""
+	}
+	return e.table.version
+}
+
+// PricingCurrency returns the currency the rate table is priced in.
+func (e *TableShippingEstimator) PricingCurrency() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.table == nil {
+		return ""
+	}
+	return e.table.currency
+}
+
+// Reload re-reads the rate table when the file has changed and reports whether a new version was
+// installed. Content changes must come with a new version so quotes stay attributable.
+func (e *TableShippingEstimator) Reload(ctx context.Context) (bool, error) {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return false, fmt.Errorf("shipping estimator: stat rate table: %w", err)
+	}
+
+	e.mu.RLock()
+	unchanged := e.table != nil && info.ModTime().Equal(e.modTime) && info.Size() == e.size
+	current := e.table
+	currentChecksum := e.checksum
+	e.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	raw, err := os.ReadFile(e.path)
+	if err != nil {
+		return false, fmt.Errorf("shipping estimator: read rate table: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	checksum := hex.EncodeToString(sum[:])
+	if current != nil && checksum == currentChecksum {
+		e.mu.Lock()
+		e.modTime, e.size = info.ModTime(), info.Size()
+		e.mu.Unlock()
+		return false, nil
+	}
+
+	table, err := parseShippingRateTable(e.path, raw)
+	if err != nil {
+		return false, err
+	}
+	compiled, err := compileShippingRateTable(table)
+	if err != nil {
+		return false, err
+	}
+	if current != nil && compiled.version == current.version {
+		return false, fmt.Errorf("shipping estimator: rate table changed without a new version (still %q)", current.version)
+	}
+
+	e.mu.Lock()
+	e.table = compiled
+	e.modTime, e.size = info.ModTime(), info.Size()
+	e.checksum = checksum
+	e.mu.Unlock()
+
+	fields := map[string]any{"path": e.path, "version": compiled.version}
+	if current != nil {
+		fields["previousVersion"] = current.version
+	}
+	e.logger(ctx, "shipping.rate_table.loaded", fields)
+	return true, nil
+}
+
+// Watch polls the rate table file until ctx is cancelled. Failed reloads are logged and the previous
+// table stays in use.
+func (e *TableShippingEstimator) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultShippingReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := e.Reload(ctx); err != nil {
+				e.logger(ctx, "shipping.rate_table.reload_failed", map[string]any{"path": e.path, "error": err.Error()})
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// EstimateShipping prices the parcel formed by all items that require shipping.
+func (e *TableShippingEstimator) EstimateShipping(_ context.Context, req ShippingEstimateRequest) (ShippingQuote, error) {
+	e.mu.RLock()
+	table := e.table
+	e.mu.RUnlock()
+	if table == nil {
+		return ShippingQuote{}, detailf(ErrShippingUnavailable, "rate table not loaded")
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency != "" && currency != table.currency {
+		return ShippingQuote{}, detailf(ErrShippingUnavailable, "rate table is priced in %s, not %s", table.currency, currency)
+	}
+
+	weight := int64(0)
+	for _, item := range req.Items {
+		if !item.RequiresShipping || item.Quantity <= 0 || item.WeightGrams <= 0 {
+			continue
+		}
+		line := int64(item.WeightGrams) * int64(item.Quantity)
+		if weight > math.MaxInt64-line {
+			return ShippingQuote{}, detailf(ErrCartPricingInvalidInput, "parcel weight overflow")
+		}
+		weight += line
+	}
+
+	addr := req.ShippingAddress
+	if addr == nil {
+		return ShippingQuote{}, detailf(ErrCartPricingInvalidInput, "shipping address required")
+	}
+	country := strings.ToUpper(strings.TrimSpace(addr.Country))
+	domestic := country == "" || country == "JP"
+	var zone *ShippingZone
+	if domestic {
+		prefecture := ""
+		if addr.State != nil {
+			prefecture = normalizePrefecture(*addr.State)
+		}
+		var ok bool
+		if zone, ok = table.prefectures[prefecture]; !ok {
+			return ShippingQuote{}, detailf(ErrShippingUnavailable, "no zone for prefecture %q", prefecture)
+		}
+	} else {
+		var ok bool
+		if zone, ok = table.countries[country]; !ok {
+			return ShippingQuote{}, detailf(ErrShippingUnavailable, "destination country %s is not covered", country)
+		}
+	}
+
+	level := strings.ToLower(strings.TrimSpace(req.ServiceLevel))
+	if level == "" {
+		level = table.defaultLevel
+	}
+	service, ok := table.services[level]
+	if !ok {
+		return ShippingQuote{}, detailf(ErrShippingUnavailable, "service level %q is not offered", level)
+	}
+
+	var bracket *ShippingWeightBracket
+	for i := range service.Brackets {
+		if weight <= service.Brackets[i].MaxGrams {
+			bracket = &service.Brackets[i]
+			break
+		}
+	}
+	if bracket == nil {
+		return ShippingQuote{}, detailf(ErrShippingUnavailable, "parcel of %dg exceeds %s limits", weight, level)
+	}
+	base, ok := bracket.Rates[zone.Name]
+	if !ok {
+		return ShippingQuote{}, detailf(ErrShippingUnavailable, "%s does not serve zone %s", level, zone.Name)
+	}
+
+	metadata := map[string]any{
+		"rateTableVersion": table.version,
+		"origin":           table.origin,
+		"zone":             zone.Name,
+		"weightGrams":      weight,
+		"bracketMaxGrams":  bracket.MaxGrams,
+		"baseAmount":       base,
+	}
+
+	amount := base
+	merchandise := req.CartSubtotal - req.DiscountTotal
+	if service.FreeShippingThreshold > 0 && merchandise >= service.FreeShippingThreshold {
+		amount = 0
+		metadata["freeShipping"] = true
+		metadata["freeShippingThreshold"] = service.FreeShippingThreshold
+	}
+
+	days, hasDays := zone.EstimateDays[level]
+	// Remote areas are keyed by Japanese postal codes.
+	if remote := matchRemoteArea(table.remoteAreas, addr.PostalCode); domestic && remote != nil {
+		if len(remote.Services) > 0 && !containsFold(remote.Services, level) {
+			return ShippingQuote{}, detailf(ErrShippingUnavailable, "%s is not available for %s", level, remote.Name)
+		}
+		amount += remote.Surcharge
+		days += remote.ExtraDays
+		metadata["remoteArea"] = remote.Name
+		metadata["remoteSurcharge"] = remote.Surcharge
+	}
+
+	breakdown := ShippingBreakdown{
+		ServiceLevel: level,
+		Carrier:      service.Carrier,
+		Amount:       amount,
+		Currency:     table.currency,
+		Metadata:     metadata,
+	}
+	if hasDays {
+		breakdown.EstimateDays = &days
+	}
+	return ShippingQuote{Amount: amount, Breakdown: []ShippingBreakdown{breakdown}}, nil
+}
+
+func parseShippingRateTable(path string, raw []byte) (ShippingRateTable, error) {
+	var table ShippingRateTable
+	if err := decodeConfigDocument(path, raw, &table); err != nil {
+		return ShippingRateTable{}, fmt.Errorf("shipping estimator: parse rate table: %w", err)
+	}
+	return table, nil
+}
+
+// decodeConfigDocument decodes a JSON or YAML document chosen by the file extension.
+func decodeConfigDocument(path string, raw []byte, out any) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(raw, out)
+	case ".json":
+		return json.Unmarshal(raw, out)
+	default:
+		return fmt.Errorf("unsupported format %q", filepath.Ext(path))
+	}
+}
+
+func compileShippingRateTable(table ShippingRateTable) (*compiledShippingTable, error) {
+	compiled := &compiledShippingTable{
+		version:      strings.TrimSpace(table.Version),
+		currency:     strings.ToUpper(strings.TrimSpace(table.Currency)),
+		origin:       strings.TrimSpace(table.Origin),
+		defaultLevel: strings.ToLower(strings.TrimSpace(table.DefaultServiceLevel)),
+		prefectures:  make(map[string]*ShippingZone),
+		countries:    make(map[string]*ShippingZone),
+		services:     make(map[string]ShippingServiceRates, len(table.Services)),
+	}
+	if compiled.version == "" {
+		return nil, errors.New("shipping estimator: rate table version is required")
+	}
+	if compiled.currency == "" {
+		return nil, errors.New("shipping estimator: rate table currency is required")
+	}
+	if compiled.defaultLevel == "" {
+		compiled.defaultLevel = ShippingServiceStandard
+	}
+
+	zoneNames := make(map[string]struct{}, len(table.Zones))
+	for i := range table.Zones {
+		zone := &table.Zones[i]
+		zone.Name = strings.TrimSpace(zone.Name)
+		if zone.Name == "" {
+			return nil, fmt.Errorf("shipping estimator: zone %d has no name", i)
+		}
+		if _, dup := zoneNames[zone.Name]; dup {
+			return nil, fmt.Errorf("shipping estimator: duplicate zone %q", zone.Name)
+		}
+		zoneNames[zone.Name] = struct{}{}
+		days := make(map[string]int, len(zone.EstimateDays))
+		for level, value := range zone.EstimateDays {
+			days[strings.ToLower(strings.TrimSpace(level))] = value
+		}
+		zone.EstimateDays = days
+		for _, prefecture := range zone.Prefectures {
+			key := normalizePrefecture(prefecture)
+			if key == "" {
+				continue
+			}
+			if existing, dup := compiled.prefectures[key]; dup {
+				return nil, fmt.Errorf("shipping estimator: prefecture %q is in zones %q and %q", prefecture, existing.Name, zone.Name)
+			}
+			compiled.prefectures[key] = zone
+		}
+		for _, country := range zone.Countries {
+			key := strings.ToUpper(strings.TrimSpace(country))
+			if key == "" {
+				continue
+			}
+			if key == "JP" {
+				return nil, fmt.Errorf("shipping estimator: zone %q lists JP; domestic zones are keyed by prefecture", zone.Name)
+			}
+			if existing, dup := compiled.countries[key]; dup {
+				return nil, fmt.Errorf("shipping estimator: country %q is in zones %q and %q", country, existing.Name, zone.Name)
+			}
+			compiled.countries[key] = zone
+		}
+	}
+
+	for _, service := range table.Services {
+		level := strings.ToLower(strings.TrimSpace(service.Level))
+		if level == "" {
+			return nil, errors.New("shipping estimator: service level name is required")
+		}
+		if _, dup := compiled.services[level]; dup {
+			return nil, fmt.Errorf("shipping estimator: duplicate service level %q", level)
+		}
+		if len(service.Brackets) == 0 {
+			return nil, fmt.Errorf("shipping estimator: service level %q has no weight brackets", level)
+		}
+		if service.FreeShippingThreshold < 0 {
+			return nil, fmt.Errorf("shipping estimator: service level %q has a negative free shipping threshold", level)
+		}
+		brackets := append([]ShippingWeightBracket(nil), service.Brackets...)
+		sort.SliceStable(brackets, func(i, j int) bool { return brackets[i].MaxGrams < brackets[j].MaxGrams })
+		for i, bracket := range brackets {
+			if bracket.MaxGrams <= 0 || (i > 0 && bracket.MaxGrams == brackets[i-1].MaxGrams) {
+				return nil, fmt.Errorf("shipping estimator: service level %q has an invalid bracket at %dg", level, bracket.MaxGrams)
+			}
+			for zone, rate := range bracket.Rates {
+				if _, ok := zoneNames[zone]; !ok {
+					return nil, fmt.Errorf("shipping estimator: service level %q prices unknown zone %q", level, zone)
+				}
+				if rate < 0 {
+					return nil, fmt.Errorf("shipping estimator: service level %q has a negative rate for zone %q", level, zone)
+				}
+			}
+		}
+		service.Level = level
+		service.Carrier = strings.TrimSpace(service.Carrier)
+		service.Brackets = brackets
+		compiled.services[level] = service
+	}
+	if _, ok := compiled.services[compiled.defaultLevel]; !ok {
+		return nil, fmt.Errorf("shipping estimator: default service level %q is not defined", compiled.defaultLevel)
+	}
+
+	for _, area := range table.RemoteAreas {
+		if area.Surcharge < 0 || area.ExtraDays < 0 {
+			return nil, fmt.Errorf("shipping estimator: remote area %q has a negative surcharge or delay", area.Name)
+		}
+		prefixes := make([]string, 0, len(area.PostalPrefixes))
+		for _, prefix := range area.PostalPrefixes {
+			if normalized := normalizePostalCode(prefix); normalized != "" {
+				prefixes = append(prefixes, normalized)
+			}
+		}
+		if len(prefixes) == 0 {
+			return nil, fmt.Errorf("shipping estimator: remote area %q has no postal prefixes", area.Name)
+		}
+		area.PostalPrefixes = prefixes
+		compiled.remoteAreas = append(compiled.remoteAreas, area)
+	}
+	return compiled, nil
+}
+
+// matchRemoteArea returns the area with the longest postal prefix matching the postal code.
+func matchRemoteArea(areas []ShippingRemoteArea, postalCode string) *ShippingRemoteArea {
+	code := normalizePostalCode(postalCode)
+	if code == "" {
+		return nil
+	}
+	var match *ShippingRemoteArea
+	longest := 0
+	for i := range areas {
+		for _, prefix := range areas[i].PostalPrefixes {
+			if len(prefix) > longest && strings.HasPrefix(code, prefix) {
+				match = &areas[i]
+				longest = len(prefix)
+			}
+		}
+	}
+	return match
+}
+
+func normalizePrefecture(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func normalizePostalCode(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
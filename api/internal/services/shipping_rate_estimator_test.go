@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testShippingRateTable = `
+version: "2024-10"
+currency: JPY
+origin: tokyo
+default_service_level: standard
+zones:
+  - name: kanto
+    prefectures: [Tokyo, Kanagawa]
+    estimate_days: {standard: 1, express: 1, cool: 2}
+  - name: okinawa
+    prefectures: [Okinawa]
+    estimate_days: {standard: 3, cool: 4}
+services:
+  - level: standard
+    carrier: yamato
+    free_shipping_threshold: 10000
+    brackets:
+      - max_grams: 2000
+        rates: {kanto: 800, okinawa: 1400}
+      - max_grams: 500
+        rates: {kanto: 600, okinawa: 1200}
+  - level: cool
+    carrier: yamato
+    brackets:
+      - max_grams: 2000
+        rates: {kanto: 1100, okinawa: 2000}
+remote_areas:
+  - name: yaeyama_islands
+    postal_prefixes: ["907-0"]
+    surcharge: 900
+    extra_days: 2
+    services: [standard]
+`
+
+func writeShippingRateTable(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write rate table: %v", err)
+	}
+	return path
+}
+
+func shippingRequest(prefecture, postal string, subtotal int64, grams int) ShippingEstimateRequest {
+	return ShippingEstimateRequest{
+		Currency:        "JPY",
+		Items:           []ShippableItem{{ItemID: "a", Quantity: 2, WeightGrams: grams, RequiresShipping: true}, {ItemID: "b", Quantity: 1, WeightGrams: 5000}},
+		ShippingAddress: &Address{Country: "JP", State: &prefecture, PostalCode: postal},
+		CartSubtotal:    subtotal,
+	}
+}
+
+func TestTableShippingEstimator_EstimateShipping(t *testing.T) {
+	path := writeShippingRateTable(t, t.TempDir(), "rates.yaml", testShippingRateTable)
+	estimator, err := NewTableShippingEstimator(TableShippingEstimatorDeps{Path: path})
+	if err != nil {
+		t.Fatalf("NewTableShippingEstimator error: %v", err)
+	}
+	ctx := context.Background()
+
+	quote, err := estimator.EstimateShipping(ctx, shippingRequest("tokyo", "100-0001", 3000, 200))
+	if err != nil {
+		t.Fatalf("EstimateShipping error: %v", err)
+	}
+	entry := quote.Breakdown[0]
+	if quote.Amount != 600 || entry.ServiceLevel != "standard" || entry.Carrier != "yamato" || entry.Metadata["zone"] != "kanto" || entry.Metadata["weightGrams"] != int64(400) {
+		t.Fatalf("unexpected quote %+v", entry)
+	}
+	if entry.EstimateDays == nil || *entry.EstimateDays != 1 || entry.Metadata["rateTableVersion"] != "2024-10" {
+		t.Fatalf("unexpected estimate metadata %+v", entry)
+	}
+
+	quote, err = estimator.EstimateShipping(ctx, shippingRequest("Okinawa", "907-0004", 12000, 800))
+	if err != nil {
+		t.Fatalf("EstimateShipping error: %v", err)
+	}
+	entry = quote.Breakdown[0]
+	if quote.Amount != 900 || entry.Metadata["freeShipping"] != true || entry.Metadata["remoteArea"] != "yaeyama_islands" || *entry.EstimateDays != 5 {
+		t.Fatalf("expected free base rate plus island surcharge, got %+v", entry)
+	}
+
+	req := shippingRequest("kanagawa", "210-0001", 12000, 800)
+	req.ServiceLevel = "Cool"
+	quote, err = estimator.EstimateShipping(ctx, req)
+	if err != nil {
+		t.Fatalf("EstimateShipping error: %v", err)
+	}
+	if quote.Amount != 1100 || *quote.Breakdown[0].EstimateDays != 2 {
+		t.Fatalf("expected cool rate without free shipping, got %+v", quote.Breakdown[0])
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*ShippingEstimateRequest)
+	}{
+		{name: "unknown prefecture", mutate: func(r *ShippingEstimateRequest) { pref := "Hokkaido"; r.ShippingAddress.State = &pref }},
+		{name: "overseas", mutate: func(r *ShippingEstimateRequest) { r.ShippingAddress.Country = "US" }},
+		{name: "unknown service", mutate: func(r *ShippingEstimateRequest) { r.ServiceLevel = "express" }},
+		{name: "too heavy", mutate: func(r *ShippingEstimateRequest) { r.Items[0].WeightGrams = 1500 }},
+		{name: "island cool", mutate: func(r *ShippingEstimateRequest) {
+			pref := "okinawa"
+			r.ShippingAddress.State, r.ShippingAddress.PostalCode, r.ServiceLevel = &pref, "9070004", "cool"
+		}},
+		{name: "currency", mutate: func(r *ShippingEstimateRequest) { r.Currency = "USD" }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := shippingRequest("tokyo", "100-0001", 3000, 200)
+			tc.mutate(&req)
+			if _, err := estimator.EstimateShipping(ctx, req); !errors.Is(err, ErrShippingUnavailable) {
+				t.Fatalf("expected ErrShippingUnavailable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTableShippingEstimator_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeShippingRateTable(t, dir, "rates.yaml", testShippingRateTable)
+	var events []string
+	estimator, err := NewTableShippingEstimator(TableShippingEstimatorDeps{
+		Path:   path,
+		Logger: func(_ context.Context, event string, _ map[string]any) { events = append(events, event) },
+	})
+	if err != nil {
+		t.Fatalf("NewTableShippingEstimator error: %v", err)
+	}
+	ctx := context.Background()
+
+	touch := func(content string, offset time.Duration) {
+		t.Helper()
+		writeShippingRateTable(t, dir, "rates.yaml", content)
+		stamp := time.Now().Add(offset)
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	if changed, err := estimator.Reload(ctx); err != nil || changed {
+		t.Fatalf("expected unchanged reload, got %v %v", changed, err)
+	}
+
+	touch(strings.Replace(testShippingRateTable, "kanto: 600", "kanto: 650", 1), time.Minute)
+	if _, err := estimator.Reload(ctx); err == nil || !strings.Contains(err.Error(), "without a new version") {
+		t.Fatalf("expected version guard error, got %v", err)
+	}
+
+	touch(strings.Replace(testShippingRateTable, "currency: JPY", "currency: \"\"", 1), 2*time.Minute)
+	if _, err := estimator.Reload(ctx); err == nil {
+		t.Fatalf("expected invalid table to be rejected")
+	}
+	if estimator.Version() != "2024-10" {
+		t.Fatalf("expected previous table to stay active, got %s", estimator.Version())
+	}
+
+	updated := strings.NewReplacer(`version: "2024-10"`, `version: "2024-11"`, "kanto: 600", "kanto: 650").Replace(testShippingRateTable)
+	touch(updated, 3*time.Minute)
+	if changed, err := estimator.Reload(ctx); err != nil || !changed {
+		t.Fatalf("expected reload, got %v %v", changed, err)
+	}
+	quote, err := estimator.EstimateShipping(ctx, shippingRequest("tokyo", "100-0001", 3000, 200))
+	if err != nil {
+		t.Fatalf("EstimateShipping error: %v", err)
+	}
+	if quote.Amount != 650 || estimator.Version() != "2024-11" {
+		t.Fatalf("expected new rates, got %d (%s)", quote.Amount, estimator.Version())
+	}
+	if len(events) != 2 || events[1] != "shipping.rate_table.loaded" {
+		t.Fatalf("unexpected events %v", events)
+	}
+}
+
+func TestCartPricingEngine_ShippingCacheFollowsRateTableVersion(t *testing.T) {
+	dir := t.TempDir()
+	path := writeShippingRateTable(t, dir, "rates.yaml", testShippingRateTable)
+	estimator, err := NewTableShippingEstimator(TableShippingEstimatorDeps{Path: path})
+	if err != nil {
+		t.Fatalf("NewTableShippingEstimator error: %v", err)
+	}
+	engine, err := NewCartPricingEngine(CartPricingEngineDeps{Promotion: &fakePromotionService{}, Shipping: estimator, CacheTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewCartPricingEngine error: %v", err)
+	}
+	ctx := context.Background()
+	prefecture := "tokyo"
+	cart := Cart{
+		ID:              "cart_1",
+		Currency:        "JPY",
+		Items:           []CartItem{{ID: "item_1", ProductID: "prod_a", SKU: "SKU-1", Quantity: 1, UnitPrice: 3000, Currency: "JPY", WeightGrams: 200, RequiresShipping: true}},
+		ShippingAddress: &Address{Country: "JP", State: &prefecture, PostalCode: "100-0001"},
+	}
+
+	result, err := engine.Calculate(ctx, PriceCartCommand{Cart: cart})
+	if err != nil {
+		t.Fatalf("Calculate error: %v", err)
+	}
+	if result.Estimate.Shipping != 600 {
+		t.Fatalf("expected shipping 600, got %d", result.Estimate.Shipping)
+	}
+
+	updated := strings.NewReplacer(`version: "2024-10"`, `version: "2024-11"`, "kanto: 600", "kanto: 650").Replace(testShippingRateTable)
+	writeShippingRateTable(t, dir, "rates.yaml", updated)
+	stamp := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if changed, err := estimator.Reload(ctx); err != nil || !changed {
+		t.Fatalf("expected reload, got %v %v", changed, err)
+	}
+
+	result, err = engine.Calculate(ctx, PriceCartCommand{Cart: cart})
+	if err != nil {
+		t.Fatalf("Calculate error after reload: %v", err)
+	}
+	if result.Estimate.Shipping != 650 {
+		t.Fatalf("expected quotes cached before the reload to be ignored, got shipping %d", result.Estimate.Shipping)
+	}
+}
+
+func TestTableShippingEstimator_JSONTable(t *testing.T) {
+	path := writeShippingRateTable(t, t.TempDir(), "rates.json", `{
+		"version": "v1",
+		"currency": "JPY",
+		"zones": [{"name": "all", "prefectures": ["Tokyo"]}],
+		"services": [{"level": "standard", "brackets": [{"max_grams": 1000, "rates": {"all": 500}}]}]
+	}`)
+	estimator, err := NewTableShippingEstimator(TableShippingEstimatorDeps{Path: path})
+	if err != nil {
+		t.Fatalf("NewTableShippingEstimator error: %v", err)
+	}
+	quote, err := estimator.EstimateShipping(context.Background(), shippingRequest("Tokyo", "", 100, 100))
+	if err != nil {
+		t.Fatalf("EstimateShipping error: %v", err)
+	}
+	if quote.Amount != 500 || quote.Breakdown[0].EstimateDays != nil {
+		t.Fatalf("unexpected quote %+v", quote.Breakdown[0])
+	}
+
+	bad := writeShippingRateTable(t, t.TempDir(), "rates.json", `{"version": "v1", "currency": "JPY", "services": [{"level": "standard", "brackets": [{"max_grams": 1000, "rates": {"nowhere": 500}}]}]}`)
+	if _, err := NewTableShippingEstimator(TableShippingEstimatorDeps{Path: bad}); err == nil {
+		t.Fatalf("expected unknown zone to be rejected")
+	}
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+const (
+	stockSubscriptionIDPrefix        = "bis_"
+	defaultStockSubscriptionTTL      = 90 * 24 * time.Hour
+	maxStockSubscriptionTTL          = 365 * 24 * time.Hour
+	defaultStockSubscriptionPageSize = 20
+	maxStockSubscriptionPageSize     = 100
+	stockSubscriptionMatchPageSize   = 100
+	defaultStockSubscriptionExpiry   = 500
+	eventInventoryTransferPrefix     = "inventory.transfer_"
+)
+
+var (
+	// ErrStockSubscriptionInvalidInput indicates the subscription request is malformed.
+	ErrStockSubscriptionInvalidInput = errors.New("stock subscription: invalid input")
+	// ErrStockSubscriptionNotFound indicates the subscription does not exist or belongs to another user.
+	ErrStockSubscriptionNotFound = errors.New("stock subscription: not found")
+)
+
+// BackInStockNotifier delivers the back-in-stock message to a subscriber, for example by queueing
+// an email or push notification. SubscriptionID is stable per subscriber and may serve as an
+// idempotency key.
+type BackInStockNotifier interface {
+	NotifyBackInStock(ctx context.Context, notification BackInStockNotification) error
+}
+
+// BackInStockNotification is the payload handed to the notifier for one subscriber.
+type BackInStockNotification struct {
+	SubscriptionID string    `json:"subscriptionId"`
+	UserID         string    `json:"userId"`
+	SKU            string    `json:"sku"`
+	ProductRef     string    `json:"productRef,omitempty"`
+	Available      int       `json:"available"`
+	EventID        string    `json:"eventId,omitempty"`
+	OccurredAt     time.Time `json:"occurredAt"`
+}
+
+// StockSubscriptionServiceDeps bundles the collaborators required to construct the subscription
+// service. DefaultTTL overrides the lifetime of new subscriptions.
+type StockSubscriptionServiceDeps struct {
+	Subscriptions repositories.StockSubscriptionRepository
+	Notifier      BackInStockNotifier
+	DefaultTTL    time.Duration
+	Clock         func() time.Time
+	IDGenerator   func() string
+	Logger        func(ctx context.Context, event string, fields map[string]any)
+}
+
+type stockSubscriptionService struct {
+	subscriptions repositories.StockSubscriptionRepository
+	notifier      BackInStockNotifier
+	defaultTTL    time.Duration
+	clock         func() time.Time
+	newID         func() string
+	logger        func(context.Context, string, map[string]any)
+}
+
+// NewStockSubscriptionService wires dependencies into a StockSubscriptionService.
+func NewStockSubscriptionService(deps StockSubscriptionServiceDeps) (StockSubscriptionService, error) {
+	if deps.Subscriptions == nil {
+		return nil, errors.New("stock subscription service: subscription repository is required")
+	}
+	if deps.Notifier == nil {
+		return nil, errors.New("stock subscription service: notifier is required")
+	}
+
+	ttl := deps.DefaultTTL
+	if ttl <= 0 {
+		ttl = defaultStockSubscriptionTTL
+	}
+	if ttl > maxStockSubscriptionTTL {
+		ttl = maxStockSubscriptionTTL
+	}
+	clock := deps.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	idGen := deps.IDGenerator
+	if idGen == nil {
+		idGen = func() string {
+			return ulid.Make().String()
+		}
+	}
+	logger := deps.Logger
+	if logger == nil {
+		logger = func(context.Context, string, map[string]any) {}
+	}
+
+	return &stockSubscriptionService{
+		subscriptions: deps.Subscriptions,
+		notifier:      deps.Notifier,
+		defaultTTL:    ttl,
+		clock: func() time.Time {
+			return clock().UTC()
+		},
+		newID:  idGen,
+		logger: logger,
+	}, nil
+}
+
+// Subscribe records the user's interest in a SKU or product. An active subscription to the same
+// target is refreshed with the new trigger and expiry rather than duplicated.
+func (s *stockSubscriptionService) Subscribe(ctx context.Context, cmd SubscribeStockCommand) (StockSubscription, error) {
+	userID := strings.TrimSpace(cmd.UserID)
+	if userID == "" {
+		return StockSubscription{}, detailf(ErrStockSubscriptionInvalidInput, "user id is required")
+	}
+	sku := strings.TrimSpace(cmd.SKU)
+	productRef := strings.TrimSpace(cmd.ProductRef)
+	if sku == "" && productRef == "" {
+		return StockSubscription{}, detailf(ErrStockSubscriptionInvalidInput, "sku or product is required")
+	}
+	trigger := cmd.Trigger
+	if trigger == "" {
+		trigger = domain.StockSubscriptionTriggerInStock
+	}
+	if trigger != domain.StockSubscriptionTriggerInStock && trigger != domain.StockSubscriptionTriggerAboveSafetyStock {
+		return StockSubscription{}, detailf(ErrStockSubscriptionInvalidInput, "unsupported trigger %q", trigger)
+	}
+	ttl := cmd.TTL
+	switch {
+	case ttl < 0:
+		return StockSubscription{}, detailf(ErrStockSubscriptionInvalidInput, "ttl must not be negative")
+	case ttl == 0:
+		ttl = s.defaultTTL
+	case ttl > maxStockSubscriptionTTL:
+		return StockSubscription{}, detailf(ErrStockSubscriptionInvalidInput, "ttl must not exceed %d days", int(maxStockSubscriptionTTL/(24*time.Hour)))
+	}
+
+	now := s.clock()
+	existing, found, err := s.findActive(ctx, userID, sku, productRef)
+	if err != nil {
+		return StockSubscription{}, err
+	}
+	if found {
+		existing.Trigger = trigger
+		existing.ExpiresAt = now.Add(ttl)
+		existing.UpdatedAt = now
+		if productRef != "" {
+			existing.ProductRef = productRef
+		}
+		if err := s.subscriptions.Update(ctx, existing); err != nil {
+			return StockSubscription{}, s.mapRepositoryError(err)
+		}
+		return existing, nil
+	}
+
+	subscription := StockSubscription{
+		ID:         stockSubscriptionIDPrefix + strings.TrimPrefix(strings.TrimSpace(s.newID()), stockSubscriptionIDPrefix),
+		UserID:     userID,
+		SKU:        sku,
+		ProductRef: productRef,
+		Trigger:    trigger,
+		Status:     domain.StockSubscriptionActive,
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.subscriptions.Insert(ctx, subscription); err != nil {
+		return StockSubscription{}, s.mapRepositoryError(err)
+	}
+	return subscription, nil
+}
+
+// Unsubscribe cancels an active subscription. Subscriptions that already fired or expired are left
+// untouched so the call is idempotent.
+func (s *stockSubscriptionService) Unsubscribe(ctx context.Context, cmd UnsubscribeStockCommand) error {
+	userID := strings.TrimSpace(cmd.UserID)
+	subscriptionID := strings.TrimSpace(cmd.SubscriptionID)
+	if userID == "" || subscriptionID == "" {
+		return detailf(ErrStockSubscriptionInvalidInput, "user id and subscription id are required")
+	}
+
+	subscription, err := s.subscriptions.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return s.mapRepositoryError(err)
+	}
+	if subscription.UserID != userID {
+		return detailf(ErrStockSubscriptionNotFound, "%s", subscriptionID)
+	}
+	if subscription.Status != domain.StockSubscriptionActive {
+		return nil
+	}
+
+	subscription.Status = domain.StockSubscriptionCanceled
+	subscription.UpdatedAt = s.clock()
+	if err := s.subscriptions.Update(ctx, subscription); err != nil {
+		return s.mapRepositoryError(err)
+	}
+	return nil
+}
+
+// ListSubscriptions returns the user's subscriptions, newest first.
+func (s *stockSubscriptionService) ListSubscriptions(ctx context.Context, cmd ListStockSubscriptionsCommand) (domain.CursorPage[StockSubscription], error) {
+	userID := strings.TrimSpace(cmd.UserID)
+	if userID == "" {
+		return domain.CursorPage[StockSubscription]{}, detailf(ErrStockSubscriptionInvalidInput, "user id is required")
+	}
+	pageSize := cmd.Pagination.PageSize
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultStockSubscriptionPageSize
+	case pageSize > maxStockSubscriptionPageSize:
+		pageSize = maxStockSubscriptionPageSize
+	}
+
+	page, err := s.subscriptions.List(ctx, repositories.StockSubscriptionListFilter{
+		UserID: userID,
+		Status: append([]StockSubscriptionStatus(nil), cmd.Status...),
+		Pagination: domain.Pagination{
+			PageSize:  pageSize,
+			PageToken: strings.TrimSpace(cmd.Pagination.PageToken),
+		},
+	})
+	if err != nil {
+		return domain.CursorPage[StockSubscription]{}, s.mapRepositoryError(err)
+	}
+	return page, nil
+}
+
+// PublishInventoryEvent lets the inventory service deliver stock events directly. Delivery failures
+// for individual subscribers are logged and retried on the next event that raises availability.
+func (s *stockSubscriptionService) PublishInventoryEvent(ctx context.Context, event InventoryStockEvent) error {
+	result, err := s.HandleStockEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	if len(result.Notified) > 0 || len(result.Failed) > 0 {
+		s.logger(ctx, "stock_subscription_event_handled", map[string]any{
+			"sku":      event.SKU,
+			"eventId":  event.ID,
+			"notified": len(result.Notified),
+			"failed":   len(result.Failed),
+		})
+	}
+	return nil
+}
+
+// HandleStockEvent notifies the active subscribers of the event's SKU and product when the event
+// moves the SKU's availability across their trigger: from zero or less to positive for in-stock
+// subscriptions, and from at or below safety stock to above it for safety stock subscriptions.
+// Each subscription is claimed before the notifier runs so that concurrent events notify a
+// subscriber only once. A failed delivery returns the subscription to active and marks it, and a
+// marked subscription is retried by any later event that raises availability while its trigger
+// still holds. Subscriptions found past their expiry are expired instead.
+func (s *stockSubscriptionService) HandleStockEvent(ctx context.Context, event InventoryStockEvent) (BackInStockResult, error) {
+	var result BackInStockResult
+	sku := strings.TrimSpace(event.SKU)
+	productRef := strings.TrimSpace(event.ProductRef)
+	if sku == "" || !raisesAvailability(event) {
+		return result, nil
+	}
+
+	available := event.OnHand - event.Reserved
+	previous := available - (event.DeltaOnHand - event.DeltaReserved)
+	now := s.clock()
+	occurredAt := event.OccurredAt.UTC()
+	if event.OccurredAt.IsZero() {
+		occurredAt = now
+	}
+
+	seen := make(map[string]struct{})
+	handle := func(subscription StockSubscription) {
+		if _, ok := seen[subscription.ID]; ok {
+			return
+		}
+		seen[subscription.ID] = struct{}{}
+		// Product queries also return SKU subscriptions for the product's other SKUs.
+		if subscription.SKU != "" && subscription.SKU != sku {
+			return
+		}
+		if !subscription.ExpiresAt.IsZero() && !now.Before(subscription.ExpiresAt) {
+			if s.expire(ctx, subscription, now) {
+				result.Expired = append(result.Expired, subscription.ID)
+			}
+			return
+		}
+		retry := subscription.NotifyFailedAt != nil && triggerHolds(subscription.Trigger, available, event.SafetyStock)
+		if !retry && !triggerCrossed(subscription.Trigger, previous, available, event.SafetyStock) {
+			return
+		}
+		result.Matched++
+		switch s.notify(ctx, subscription, event, available, occurredAt, now) {
+		case nil:
+			result.Notified = append(result.Notified, subscription.ID)
+		case errStockSubscriptionClaimed:
+		default:
+			result.Failed = append(result.Failed, subscription.ID)
+		}
+	}
+
+	filters := []repositories.StockSubscriptionListFilter{{SKU: sku}}
+	if productRef != "" {
+		filters = append(filters, repositories.StockSubscriptionListFilter{ProductRef: productRef})
+	}
+	for _, filter := range filters {
+		filter.Status = []domain.StockSubscriptionStatus{domain.StockSubscriptionActive}
+		pageToken := ""
+		for {
+			filter.Pagination = domain.Pagination{PageSize: stockSubscriptionMatchPageSize, PageToken: pageToken}
+			page, err := s.subscriptions.List(ctx, filter)
+			if err != nil {
+				return result, s.mapRepositoryError(err)
+			}
+			for _, subscription := range page.Items {
+				handle(subscription)
+			}
+			pageToken = strings.TrimSpace(page.NextPageToken)
+			if pageToken == "" || len(page.Items) == 0 {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// ExpireSubscriptions expires active subscriptions whose lifetime has ended.
+func (s *stockSubscriptionService) ExpireSubscriptions(ctx context.Context, cmd ExpireStockSubscriptionsCommand) (ExpireStockSubscriptionsResult, error) {
+	now := cmd.Now.UTC()
+	if cmd.Now.IsZero() {
+		now = s.clock()
+	}
+	limit := cmd.Limit
+	if limit <= 0 {
+		limit = defaultStockSubscriptionExpiry
+	}
+
+	var result ExpireStockSubscriptionsResult
+	pageToken := ""
+	for result.Checked < limit {
+		page, err := s.subscriptions.List(ctx, repositories.StockSubscriptionListFilter{
+			Status:        []domain.StockSubscriptionStatus{domain.StockSubscriptionActive},
+			ExpiresBefore: &now,
+			Pagination:    domain.Pagination{PageSize: min(limit-result.Checked, stockSubscriptionMatchPageSize), PageToken: pageToken},
+		})
+		if err != nil {
+			return result, s.mapRepositoryError(err)
+		}
+		for _, subscription := range page.Items {
+			result.Checked++
+			if subscription.Status != domain.StockSubscriptionActive {
+				continue
+			}
+			if s.expire(ctx, subscription, now) {
+				result.Expired = append(result.Expired, subscription.ID)
+			}
+		}
+		pageToken = strings.TrimSpace(page.NextPageToken)
+		if pageToken == "" || len(page.Items) == 0 {
+			break
+		}
+	}
+	return result, nil
+}
+
+var errStockSubscriptionClaimed = errors.New("stock subscription: already claimed")
+
+// notify claims the subscription and hands it to the notifier. It returns
+// errStockSubscriptionClaimed when another event already claimed the subscription.
+func (s *stockSubscriptionService) notify(ctx context.Context, subscription StockSubscription, event InventoryStockEvent, available int, occurredAt, now time.Time) error {
+	claimed, err := s.subscriptions.MarkNotified(ctx, subscription.ID, strings.TrimSpace(event.ID), now)
+	if err != nil {
+		if isRepositoryConflict(err) {
+			return errStockSubscriptionClaimed
+		}
+		s.logger(ctx, "stock_subscription_claim_failed", map[string]any{
+			"subscriptionId": subscription.ID,
+			"error":          err.Error(),
+		})
+		return err
+	}
+
+	notification := BackInStockNotification{
+		SubscriptionID: claimed.ID,
+		UserID:         claimed.UserID,
+		SKU:            strings.TrimSpace(event.SKU),
+		ProductRef:     strings.TrimSpace(event.ProductRef),
+		Available:      available,
+		EventID:        strings.TrimSpace(event.ID),
+		OccurredAt:     occurredAt,
+	}
+	if err := s.notifier.NotifyBackInStock(ctx, notification); err != nil {
+		s.logger(ctx, "stock_subscription_notify_failed", map[string]any{
+			"subscriptionId": claimed.ID,
+			"error":          err.Error(),
+		})
+		claimed.Status = domain.StockSubscriptionActive
+		claimed.NotifiedAt = nil
+		claimed.NotifiedEventID = ""
+		claimed.NotifyFailedAt = &now
+		claimed.UpdatedAt = now
+		if restoreErr := s.subscriptions.Update(ctx, claimed); restoreErr != nil {
+			s.logger(ctx, "stock_subscription_restore_failed", map[string]any{
+				"subscriptionId": claimed.ID,
+				"error":          restoreErr.Error(),
+			})
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *stockSubscriptionService) expire(ctx context.Context, subscription StockSubscription, now time.Time) bool {
+	subscription.Status = domain.StockSubscriptionExpired
+	subscription.UpdatedAt = now
+	if err := s.subscriptions.Update(ctx, subscription); err != nil {
+		s.logger(ctx, "stock_subscription_expire_failed", map[string]any{
+			"subscriptionId": subscription.ID,
+			"error":          err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// findActive returns the user's active subscription to exactly this target, if any.
+func (s *stockSubscriptionService) findActive(ctx context.Context, userID, sku, productRef string) (StockSubscription, bool, error) {
+	filter := repositories.StockSubscriptionListFilter{
+		UserID: userID,
+		Status: []domain.StockSubscriptionStatus{domain.StockSubscriptionActive},
+	}
+	if sku != "" {
+		filter.SKU = sku
+	} else {
+		filter.ProductRef = productRef
+	}
+	pageToken := ""
+	for {
+		filter.Pagination = domain.Pagination{PageSize: stockSubscriptionMatchPageSize, PageToken: pageToken}
+		page, err := s.subscriptions.List(ctx, filter)
+		if err != nil {
+			return StockSubscription{}, false, s.mapRepositoryError(err)
+		}
+		for _, subscription := range page.Items {
+			if subscription.SKU == sku {
+				return subscription, true, nil
+			}
+		}
+		pageToken = strings.TrimSpace(page.NextPageToken)
+		if pageToken == "" || len(page.Items) == 0 {
+			return StockSubscription{}, false, nil
+		}
+	}
+}
+
+func (s *stockSubscriptionService) mapRepositoryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var repoErr repositories.RepositoryError
+	if errors.As(err, &repoErr) {
+		switch {
+		case repoErr.IsNotFound():
+			return fmt.Errorf("%w: %v", ErrStockSubscriptionNotFound, err)
+		case repoErr.IsUnavailable():
+			return fmt.Errorf("stock subscription: repository unavailable: %w", err)
+		}
+	}
+	return err
+}
+
+// raisesAvailability reports whether the event added available units to the SKU. Transfers only
+// move units between locations, so they never bring a SKU back into stock. Published events carry
+// the SKU aggregate balances after the change, including those scoped to a location.
+func raisesAvailability(event InventoryStockEvent) bool {
+	if strings.HasPrefix(event.Type, eventInventoryTransferPrefix) {
+		return false
+	}
+	return event.DeltaOnHand-event.DeltaReserved > 0
+}
+
+// triggerCrossed reports whether availability moved from at or below the trigger's threshold to
+// above it, so that topping up stock that was already available notifies nobody.
+func triggerCrossed(trigger StockSubscriptionTrigger, previous, available, safetyStock int) bool {
+	return !triggerHolds(trigger, previous, safetyStock) && triggerHolds(trigger, available, safetyStock)
+}
+
+// triggerHolds reports whether availability is above the trigger's threshold.
+func triggerHolds(trigger StockSubscriptionTrigger, available, safetyStock int) bool {
+	threshold := 0
+	if trigger == domain.StockSubscriptionTriggerAboveSafetyStock {
+		threshold = safetyStock
+	}
+	return available > threshold
+}
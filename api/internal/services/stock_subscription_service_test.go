@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/repositories"
+)
+
+type memoryStockSubscriptionRepo struct {
+	subscriptions []domain.StockSubscription
+}
+
+func (r *memoryStockSubscriptionRepo) Insert(_ context.Context, subscription domain.StockSubscription) error {
+	for _, existing := range r.subscriptions {
+		if existing.ID == subscription.ID {
+			return repoError{message: "subscription exists", conflict: true}
+		}
+	}
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+func (r *memoryStockSubscriptionRepo) Update(_ context.Context, subscription domain.StockSubscription) error {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == subscription.ID {
+			r.subscriptions[i] = subscription
+			return nil
+		}
+	}
+	return repoError{message: "subscription not found", notFound: true}
+}
+
+func (r *memoryStockSubscriptionRepo) FindByID(_ context.Context, subscriptionID string) (domain.StockSubscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == subscriptionID {
+			return subscription, nil
+		}
+	}
+	return domain.StockSubscription{}, repoError{message: "subscription not found", notFound: true}
+}
+
+func (r *memoryStockSubscriptionRepo) List(_ context.Context, filter repositories.StockSubscriptionListFilter) (domain.CursorPage[domain.StockSubscription], error) {
+	var matched []domain.StockSubscription
+	for _, subscription := range r.subscriptions {
+		if filter.UserID != "" && subscription.UserID != filter.UserID {
+			continue
+		}
+		if filter.SKU != "" && subscription.SKU != filter.SKU {
+			continue
+		}
+		if filter.ProductRef != "" && subscription.ProductRef != filter.ProductRef {
+			continue
+		}
+		if len(filter.Status) > 0 && subscription.Status != filter.Status[0] {
+			continue
+		}
+		if filter.ExpiresBefore != nil && !subscription.ExpiresAt.Before(*filter.ExpiresBefore) {
+			continue
+		}
+		matched = append(matched, subscription)
+	}
+	offset := 0
+	if filter.Pagination.PageToken != "" {
+		offset, _ = strconv.Atoi(filter.Pagination.PageToken)
+	}
+	end := offset + filter.Pagination.PageSize
+	if filter.Pagination.PageSize <= 0 || end > len(matched) {
+		end = len(matched)
+	}
+	page := domain.CursorPage[domain.StockSubscription]{Items: matched[offset:end]}
+	if end < len(matched) {
+		page.NextPageToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func (r *memoryStockSubscriptionRepo) MarkNotified(_ context.Context, subscriptionID string, eventID string, notifiedAt time.Time) (domain.StockSubscription, error) {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID != subscriptionID {
+			continue
+		}
+		if r.subscriptions[i].Status != domain.StockSubscriptionActive {
+			return domain.StockSubscription{}, repoError{message: "subscription is not active", conflict: true}
+		}
+		at := notifiedAt
+		r.subscriptions[i].Status = domain.StockSubscriptionNotified
+		r.subscriptions[i].NotifiedAt = &at
+		r.subscriptions[i].NotifiedEventID = eventID
+		r.subscriptions[i].UpdatedAt = notifiedAt
+		return r.subscriptions[i], nil
+	}
+	return domain.StockSubscription{}, repoError{message: "subscription not found", notFound: true}
+}
+
+func (r *memoryStockSubscriptionRepo) get(id string) domain.StockSubscription {
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == id {
+			return subscription
+		}
+	}
+	return domain.StockSubscription{}
+}
+
+type captureBackInStockNotifier struct {
+	notifications []BackInStockNotification
+	failFor       map[string]bool
+}
+
+func (n *captureBackInStockNotifier) NotifyBackInStock(_ context.Context, notification BackInStockNotification) error {
+	if n.failFor[notification.SubscriptionID] {
+		return errors.New("mail provider unavailable")
+	}
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func newTestStockSubscriptionService(t *testing.T, repo *memoryStockSubscriptionRepo, notifier *captureBackInStockNotifier, now time.Time) StockSubscriptionService {
+	t.Helper()
+	seq := 0
+	svc, err := NewStockSubscriptionService(StockSubscriptionServiceDeps{
+		Subscriptions: repo,
+		Notifier:      notifier,
+		Clock:         func() time.Time { return now },
+		IDGenerator: func() string {
+			seq++
+			return "sub" + strconv.Itoa(seq)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new stock subscription service: %v", err)
+	}
+	return svc
+}
+
+func TestStockSubscriptionServiceSubscribe(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	repo := &memoryStockSubscriptionRepo{}
+	svc := newTestStockSubscriptionService(t, repo, &captureBackInStockNotifier{}, now)
+	ctx := context.Background()
+
+	if _, err := svc.Subscribe(ctx, SubscribeStockCommand{UserID: "user_1"}); !errors.Is(err, ErrStockSubscriptionInvalidInput) {
+		t.Fatalf("expected invalid input without a target, got %v", err)
+	}
+	if _, err := svc.Subscribe(ctx, SubscribeStockCommand{UserID: "user_1", SKU: "SKU-1", Trigger: "someday"}); !errors.Is(err, ErrStockSubscriptionInvalidInput) {
+		t.Fatalf("expected invalid input for unknown trigger, got %v", err)
+	}
+
+	first, err := svc.Subscribe(ctx, SubscribeStockCommand{UserID: "user_1", SKU: " SKU-1 ", ProductRef: "/products/p1"})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if first.ID != "bis_sub1" || first.SKU != "SKU-1" || first.Status != domain.StockSubscriptionActive || first.Trigger != domain.StockSubscriptionTriggerInStock {
+		t.Fatalf("unexpected subscription %+v", first)
+	}
+	if !first.ExpiresAt.Equal(now.Add(defaultStockSubscriptionTTL)) {
+		t.Fatalf("expected default expiry, got %s", first.ExpiresAt)
+	}
+
+	again, err := svc.Subscribe(ctx, SubscribeStockCommand{UserID: "user_1", SKU: "SKU-1", Trigger: domain.StockSubscriptionTriggerAboveSafetyStock, TTL: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("subscribe again: %v", err)
+	}
+	if again.ID != first.ID || again.Trigger != domain.StockSubscriptionTriggerAboveSafetyStock || !again.ExpiresAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("expected existing subscription to be refreshed, got %+v", again)
+	}
+
+	product, err := svc.Subscribe(ctx, SubscribeStockCommand{UserID: "user_1", ProductRef: "/products/p1"})
+	if err != nil {
+		t.Fatalf("subscribe to product: %v", err)
+	}
+	if product.ID == first.ID || product.SKU != "" {
+		t.Fatalf("expected a separate product subscription, got %+v", product)
+	}
+	if len(repo.subscriptions) != 2 {
+		t.Fatalf("expected two subscriptions, got %d", len(repo.subscriptions))
+	}
+
+	if err := svc.Unsubscribe(ctx, UnsubscribeStockCommand{UserID: "user_2", SubscriptionID: product.ID}); !errors.Is(err, ErrStockSubscriptionNotFound) {
+		t.Fatalf("expected other users to be rejected, got %v", err)
+	}
+	if err := svc.Unsubscribe(ctx, UnsubscribeStockCommand{UserID: "user_1", SubscriptionID: product.ID}); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if got := repo.get(product.ID).Status; got != domain.StockSubscriptionCanceled {
+		t.Fatalf("expected canceled subscription, got %s", got)
+	}
+}
+
+func TestStockSubscriptionServiceHandleStockEvent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	active := func(id, user, sku, product string, trigger domain.StockSubscriptionTrigger, expiresAt time.Time) domain.StockSubscription {
+		return domain.StockSubscription{ID: id, UserID: user, SKU: sku, ProductRef: product, Trigger: trigger, Status: domain.StockSubscriptionActive, ExpiresAt: expiresAt}
+	}
+	repo := &memoryStockSubscriptionRepo{subscriptions: []domain.StockSubscription{
+		active("bis_sku", "user_1", "SKU-1", "/products/p1", domain.StockSubscriptionTriggerInStock, now.Add(time.Hour)),
+		active("bis_product", "user_2", "", "/products/p1", domain.StockSubscriptionTriggerInStock, now.Add(time.Hour)),
+		active("bis_other_sku", "user_3", "SKU-2", "/products/p1", domain.StockSubscriptionTriggerInStock, now.Add(time.Hour)),
+		active("bis_safety", "user_4", "SKU-1", "", domain.StockSubscriptionTriggerAboveSafetyStock, now.Add(time.Hour)),
+		active("bis_old", "user_5", "SKU-1", "", domain.StockSubscriptionTriggerInStock, now.Add(-time.Minute)),
+		active("bis_failing", "user_6", "SKU-1", "", domain.StockSubscriptionTriggerInStock, now.Add(time.Hour)),
+	}}
+	notifier := &captureBackInStockNotifier{failFor: map[string]bool{"bis_failing": true}}
+	svc := newTestStockSubscriptionService(t, repo, notifier, now)
+	ctx := context.Background()
+
+	reserve := InventoryStockEvent{Type: "inventory.reserve", SKU: "SKU-1", ProductRef: "/products/p1", DeltaReserved: 1, OnHand: 5, Reserved: 1}
+	transfer := InventoryStockEvent{Type: "inventory.transfer_in", SKU: "SKU-1", ProductRef: "/products/p1", LocationID: "partner", DeltaOnHand: 2, OnHand: 2}
+	for _, event := range []InventoryStockEvent{reserve, transfer} {
+		result, err := svc.HandleStockEvent(ctx, event)
+		if err != nil || result.Matched != 0 {
+			t.Fatalf("expected %s to be ignored, got %+v (err %v)", event.Type, result, err)
+		}
+	}
+
+	receipt := InventoryStockEvent{ID: "ise_1", Type: "inventory.receipt", SKU: "SKU-1", ProductRef: "/products/p1", DeltaOnHand: 2, OnHand: 2, SafetyStock: 3}
+	result, err := svc.HandleStockEvent(ctx, receipt)
+	if err != nil {
+		t.Fatalf("handle receipt: %v", err)
+	}
+	if result.Matched != 3 || len(result.Notified) != 2 || len(result.Failed) != 1 || result.Failed[0] != "bis_failing" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.Expired) != 1 || result.Expired[0] != "bis_old" {
+		t.Fatalf("expected the stale subscription to expire, got %+v", result.Expired)
+	}
+	if len(notifier.notifications) != 2 || notifier.notifications[0].Available != 2 || notifier.notifications[0].EventID != "ise_1" {
+		t.Fatalf("unexpected notifications %+v", notifier.notifications)
+	}
+	if got := repo.get("bis_sku"); got.Status != domain.StockSubscriptionNotified || got.NotifiedEventID != "ise_1" || got.NotifiedAt == nil {
+		t.Fatalf("expected sku subscription to be notified, got %+v", got)
+	}
+	if got := repo.get("bis_failing"); got.Status != domain.StockSubscriptionActive || got.NotifiedAt != nil || got.NotifyFailedAt == nil {
+		t.Fatalf("expected failed delivery to be retried later, got %+v", got)
+	}
+	if got := repo.get("bis_safety").Status; got != domain.StockSubscriptionActive {
+		t.Fatalf("expected safety stock subscription to keep waiting, got %s", got)
+	}
+	if got := repo.get("bis_other_sku").Status; got != domain.StockSubscriptionActive {
+		t.Fatalf("expected other sku subscription to keep waiting, got %s", got)
+	}
+
+	notifier.failFor = nil
+	release := InventoryStockEvent{ID: "ise_2", Type: "inventory.release", SKU: "SKU-1", ProductRef: "/products/p1", DeltaReserved: -3, OnHand: 6, Reserved: 2, SafetyStock: 3}
+	if err := svc.PublishInventoryEvent(ctx, release); err != nil {
+		t.Fatalf("publish release: %v", err)
+	}
+	// Availability rises from 1 to 4: above safety stock for the first time, and the SKU is still in
+	// stock, so the failed delivery is retried without waiting for a sell-out.
+	if len(notifier.notifications) != 4 || notifier.notifications[2].SubscriptionID != "bis_safety" || notifier.notifications[3].SubscriptionID != "bis_failing" {
+		t.Fatalf("expected the safety stock subscriber and the retried delivery, got %+v", notifier.notifications)
+	}
+	if got := repo.get("bis_failing"); got.Status != domain.StockSubscriptionNotified || got.NotifiedEventID != "ise_2" {
+		t.Fatalf("expected the retried delivery to be recorded, got %+v", got)
+	}
+
+	// Topping up available stock, including at one location, crosses no trigger.
+	topUp := InventoryStockEvent{Type: "inventory.receipt", SKU: "SKU-1", LocationID: "partner", DeltaOnHand: 2, OnHand: 8, Reserved: 2, SafetyStock: 3}
+	if result, err := svc.HandleStockEvent(ctx, topUp); err != nil || result.Matched != 0 {
+		t.Fatalf("expected top-up to match nobody, got %+v (err %v)", result, err)
+	}
+}
+
+func TestStockSubscriptionServiceExpireSubscriptions(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	repo := &memoryStockSubscriptionRepo{subscriptions: []domain.StockSubscription{
+		{ID: "bis_1", UserID: "user_1", SKU: "SKU-1", Status: domain.StockSubscriptionActive, ExpiresAt: now.Add(-48 * time.Hour)},
+		{ID: "bis_2", UserID: "user_2", SKU: "SKU-1", Status: domain.StockSubscriptionActive, ExpiresAt: now.Add(-time.Hour)},
+		{ID: "bis_3", UserID: "user_3", SKU: "SKU-1", Status: domain.StockSubscriptionActive, ExpiresAt: now.Add(time.Hour)},
+		{ID: "bis_4", UserID: "user_4", SKU: "SKU-1", Status: domain.StockSubscriptionNotified, ExpiresAt: now.Add(-time.Hour)},
+	}}
+	svc := newTestStockSubscriptionService(t, repo, &captureBackInStockNotifier{}, now)
+
+	result, err := svc.ExpireSubscriptions(context.Background(), ExpireStockSubscriptionsCommand{})
+	if err != nil {
+		t.Fatalf("expire: %v", err)
+	}
+	if result.Checked != 2 || len(result.Expired) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	for id, want := range map[string]domain.StockSubscriptionStatus{
+		"bis_1": domain.StockSubscriptionExpired,
+		"bis_2": domain.StockSubscriptionExpired,
+		"bis_3": domain.StockSubscriptionActive,
+		"bis_4": domain.StockSubscriptionNotified,
+	} {
+		if got := repo.get(id).Status; got != want {
+			t.Fatalf("expected %s to be %s, got %s", id, want, got)
+		}
+	}
+}
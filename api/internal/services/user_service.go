@@ -13,6 +13,7 @@ import (
 
 	firebaseauth "firebase.google.com/go/v4/auth"
 	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
 	"github.com/hanko-field/api/internal/platform/auth"
 	"github.com/hanko-field/api/internal/repositories"
 	"golang.org/x/text/language"
@@ -25,7 +26,6 @@ var (
 	errInvalidLanguageTag           = errors.New("user: invalid language tag")
 	errProfileConflict              = errors.New("user: profile has been modified")
 	errAddressRepositoryUnavailable = errors.New("user: address repository not configured")
-	errPaymentMethodsNotImplemented = errors.New("user: payment method operations not yet implemented")
 	errFavoritesNotImplemented      = errors.New("user: favorites operations not yet implemented")
 	emailMaskSuffix                 = "@hanko-field.invalid"
 	notificationKeyPattern          = regexp.MustCompile(`^[a-z0-9_.-]{1,40}$`)
@@ -33,13 +33,32 @@ var (
 	auditActionProfileMask          = "user.profile.mask"
 	auditActionProfileActivate      = "user.profile.activate"
 	auditActionProfileDeactivate    = "user.profile.deactivate"
+	auditActionPaymentMethodAdd     = "user.payment_method.add"
+	auditActionPaymentMethodRemove  = "user.payment_method.remove"
 )
 
+var (
+	// ErrPaymentMethodInvalidInput signals an invalid saved payment method request.
+	ErrPaymentMethodInvalidInput = errors.New("user: invalid payment method request")
+	// ErrPaymentMethodNotFound indicates the saved payment method does not belong to the user.
+	ErrPaymentMethodNotFound = errors.New("user: payment method not found")
+	// ErrPaymentMethodUnavailable indicates saved payment methods are not configured.
+	ErrPaymentMethodUnavailable = errors.New("user: payment methods unavailable")
+)
+
+// PaymentMethodVault stores payment methods with the PSP. *payments.Manager satisfies it.
+type PaymentMethodVault interface {
+	CreateSetupSession(ctx context.Context, paymentCtx payments.PaymentContext, req payments.SetupSessionRequest) (payments.SetupSession, error)
+	AttachPaymentMethod(ctx context.Context, paymentCtx payments.PaymentContext, req payments.AttachPaymentMethodRequest) (payments.SavedPaymentMethod, error)
+	DetachPaymentMethod(ctx context.Context, paymentCtx payments.PaymentContext, req payments.DetachPaymentMethodRequest) error
+}
+
 // UserServiceDeps bundles the dependencies required to construct a user service instance.
 type UserServiceDeps struct {
 	Users          repositories.UserRepository
 	Addresses      repositories.AddressRepository
 	PaymentMethods repositories.PaymentMethodRepository
+	PaymentVault   PaymentMethodVault
 	Favorites      repositories.FavoriteRepository
 	Audit          AuditLogService
 	Firebase       auth.UserGetter
@@ -50,6 +69,7 @@ type userService struct {
 	users          repositories.UserRepository
 	addresses      repositories.AddressRepository
 	paymentMethods repositories.PaymentMethodRepository
+	paymentVault   PaymentMethodVault
 	favorites      repositories.FavoriteRepository
 	audit          AuditLogService
 	firebase       auth.UserGetter
@@ -74,6 +94,7 @@ func NewUserService(deps UserServiceDeps) (UserService, error) {
 		users:          deps.Users,
 		addresses:      deps.Addresses,
 		paymentMethods: deps.PaymentMethods,
+		paymentVault:   deps.PaymentVault,
 		favorites:      deps.Favorites,
 		audit:          deps.Audit,
 		firebase:       deps.Firebase,
@@ -248,15 +269,183 @@ func (s *userService) DeleteAddress(ctx context.Context, cmd DeleteAddressComman
 }
 
 func (s *userService) ListPaymentMethods(ctx context.Context, userID string) ([]PaymentMethod, error) {
-	return nil, errPaymentMethodsNotImplemented
+	if s.paymentMethods == nil {
+		return nil, ErrPaymentMethodUnavailable
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, errUserIDRequired
+	}
+	return s.paymentMethods.List(ctx, userID)
+}
+
+// StartPaymentMethodSetup opens a PSP setup session for collecting a reusable card. The PSP customer
+// is created on first use and remembered on the profile so later cards share it.
+func (s *userService) StartPaymentMethodSetup(ctx context.Context, cmd StartPaymentMethodSetupCommand) (PaymentMethodSetup, error) {
+	if s.paymentVault == nil {
+		return PaymentMethodSetup{}, ErrPaymentMethodUnavailable
+	}
+	if strings.TrimSpace(cmd.UserID) == "" {
+		return PaymentMethodSetup{}, errUserIDRequired
+	}
+	profile, err := s.getProfile(ctx, cmd.UserID, true)
+	if err != nil {
+		return PaymentMethodSetup{}, err
+	}
+
+	provider := normalizePaymentMethodProvider(cmd.Provider)
+	customerRef := strings.TrimSpace(profile.PaymentCustomers[provider])
+	session, err := s.paymentVault.CreateSetupSession(ctx, payments.PaymentContext{PreferredProvider: provider}, payments.SetupSessionRequest{
+		CustomerID: customerRef,
+		Email:      profile.Email,
+		Name:       profile.DisplayName,
+		Metadata:   map[string]string{"userId": profile.ID},
+	})
+	if err != nil {
+		return PaymentMethodSetup{}, err
+	}
+
+	if customerRef == "" && session.CustomerID != "" {
+		updated := profile
+		updated.LastSyncTime = profile.LastSyncTime
+		updated.PaymentCustomers = maps.Clone(profile.PaymentCustomers)
+		if updated.PaymentCustomers == nil {
+			updated.PaymentCustomers = make(map[string]string, 1)
+		}
+		updated.PaymentCustomers[provider] = session.CustomerID
+		if _, err := s.users.UpdateProfile(ctx, updated); err != nil {
+			return PaymentMethodSetup{}, mapConflictError(err)
+		}
+	}
+
+	if session.Provider != "" {
+		provider = session.Provider
+	}
+	return PaymentMethodSetup{
+		Provider:     provider,
+		SessionID:    session.ID,
+		ClientSecret: session.ClientSecret,
+		CustomerRef:  session.CustomerID,
+	}, nil
 }
 
+// AddPaymentMethod attaches the collected PSP payment method to the user's PSP customer and stores
+// the card details reported by the PSP. Saving the same PSP method twice returns the stored record.
 func (s *userService) AddPaymentMethod(ctx context.Context, cmd AddPaymentMethodCommand) (PaymentMethod, error) {
-	return PaymentMethod{}, errPaymentMethodsNotImplemented
+	if s.paymentMethods == nil || s.paymentVault == nil {
+		return PaymentMethod{}, ErrPaymentMethodUnavailable
+	}
+	if strings.TrimSpace(cmd.UserID) == "" {
+		return PaymentMethod{}, errUserIDRequired
+	}
+	token := strings.TrimSpace(cmd.Token)
+	if token == "" {
+		token = strings.TrimSpace(cmd.Reference)
+	}
+	if token == "" {
+		return PaymentMethod{}, detailf(ErrPaymentMethodInvalidInput, "payment method token is required")
+	}
+
+	profile, err := s.getProfile(ctx, cmd.UserID, false)
+	if err != nil {
+		return PaymentMethod{}, err
+	}
+	provider := normalizePaymentMethodProvider(cmd.Provider)
+	customerRef := strings.TrimSpace(profile.PaymentCustomers[provider])
+	if customerRef == "" {
+		return PaymentMethod{}, detailf(ErrPaymentMethodInvalidInput, "payment method setup has not been started for %s", provider)
+	}
+
+	existing, err := s.paymentMethods.List(ctx, profile.ID)
+	if err != nil {
+		return PaymentMethod{}, err
+	}
+	for _, method := range existing {
+		if method.Provider == provider && method.Reference == token {
+			return method, nil
+		}
+	}
+
+	saved, err := s.paymentVault.AttachPaymentMethod(ctx, payments.PaymentContext{PreferredProvider: provider}, payments.AttachPaymentMethodRequest{
+		CustomerID:      customerRef,
+		PaymentMethodID: token,
+	})
+	if err != nil {
+		return PaymentMethod{}, err
+	}
+
+	method, err := s.paymentMethods.Insert(ctx, profile.ID, domain.PaymentMethod{
+		Provider:    provider,
+		Reference:   saved.PaymentMethodID,
+		CustomerRef: customerRef,
+		Brand:       saved.Brand,
+		Last4:       saved.Last4,
+		ExpMonth:    saved.ExpMonth,
+		ExpYear:     saved.ExpYear,
+		CreatedAt:   s.clock(),
+	})
+	if err != nil {
+		return PaymentMethod{}, err
+	}
+
+	if err := s.appendAudit(ctx, auditActionPaymentMethodAdd, profile.ID, profile.ID, map[string]any{
+		"paymentMethodId": method.ID,
+		"provider":        provider,
+		"brand":           method.Brand,
+		"last4":           method.Last4,
+	}); err != nil {
+		return PaymentMethod{}, err
+	}
+	return method, nil
 }
 
+// RemovePaymentMethod detaches the method at the PSP before deleting it so it can no longer be
+// charged off-session.
 func (s *userService) RemovePaymentMethod(ctx context.Context, cmd RemovePaymentMethodCommand) error {
-	return errPaymentMethodsNotImplemented
+	if s.paymentMethods == nil || s.paymentVault == nil {
+		return ErrPaymentMethodUnavailable
+	}
+	userID := strings.TrimSpace(cmd.UserID)
+	if userID == "" {
+		return errUserIDRequired
+	}
+	methodID := strings.TrimSpace(cmd.PaymentMethodID)
+	if methodID == "" {
+		return detailf(ErrPaymentMethodInvalidInput, "payment method id is required")
+	}
+
+	methods, err := s.paymentMethods.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(methods, func(method PaymentMethod) bool { return method.ID == methodID })
+	if idx < 0 {
+		return detailf(ErrPaymentMethodNotFound, "%s", methodID)
+	}
+	method := methods[idx]
+
+	if reference := strings.TrimSpace(method.Reference); reference != "" {
+		if err := s.paymentVault.DetachPaymentMethod(ctx, payments.PaymentContext{PreferredProvider: method.Provider}, payments.DetachPaymentMethodRequest{
+			PaymentMethodID: reference,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := s.paymentMethods.Delete(ctx, userID, methodID); err != nil {
+		return err
+	}
+
+	return s.appendAudit(ctx, auditActionPaymentMethodRemove, userID, userID, map[string]any{
+		"paymentMethodId": methodID,
+		"provider":        method.Provider,
+	})
+}
+
+func normalizePaymentMethodProvider(provider string) string {
+	if trimmed := strings.ToLower(strings.TrimSpace(provider)); trimmed != "" {
+		return trimmed
+	}
+	return payments.ProviderStripe
 }
 
 func (s *userService) ListFavorites(ctx context.Context, userID string, pager Pagination) (domain.CursorPage[FavoriteDesign], error) {
@@ -9,6 +9,7 @@ import (
 
 	firebaseauth "firebase.google.com/go/v4/auth"
 	domain "github.com/hanko-field/api/internal/domain"
+	"github.com/hanko-field/api/internal/payments"
 	"github.com/hanko-field/api/internal/repositories"
 )
 
@@ -363,6 +364,136 @@ func TestUserServiceSetUserActiveConflict(t *testing.T) {
 	}
 }
 
+type memoryPaymentMethodRepo struct {
+	methods map[string][]domain.PaymentMethod
+	seq     int
+}
+
+func (m *memoryPaymentMethodRepo) List(_ context.Context, userID string) ([]domain.PaymentMethod, error) {
+	return append([]domain.PaymentMethod(nil), m.methods[userID]...), nil
+}
+
+func (m *memoryPaymentMethodRepo) Insert(_ context.Context, userID string, method domain.PaymentMethod) (domain.PaymentMethod, error) {
+	m.seq++
+	method.ID = fmt.Sprintf("pm-%d", m.seq)
+	m.methods[userID] = append(m.methods[userID], method)
+	return method, nil
+}
+
+func (m *memoryPaymentMethodRepo) Delete(_ context.Context, userID string, paymentMethodID string) error {
+	methods := m.methods[userID]
+	for i, method := range methods {
+		if method.ID == paymentMethodID {
+			m.methods[userID] = append(methods[:i], methods[i+1:]...)
+			return nil
+		}
+	}
+	return &repoErr{err: errors.New("payment method not found"), notFound: true}
+}
+
+type stubPaymentVault struct {
+	setups   []payments.SetupSessionRequest
+	attached []payments.AttachPaymentMethodRequest
+	detached []string
+}
+
+func (v *stubPaymentVault) CreateSetupSession(_ context.Context, _ payments.PaymentContext, req payments.SetupSessionRequest) (payments.SetupSession, error) {
+	v.setups = append(v.setups, req)
+	customer := req.CustomerID
+	if customer == "" {
+		customer = "cus_1"
+	}
+	return payments.SetupSession{ID: fmt.Sprintf("seti_%d", len(v.setups)), Provider: payments.ProviderStripe, CustomerID: customer, ClientSecret: "secret"}, nil
+}
+
+func (v *stubPaymentVault) AttachPaymentMethod(_ context.Context, _ payments.PaymentContext, req payments.AttachPaymentMethodRequest) (payments.SavedPaymentMethod, error) {
+	v.attached = append(v.attached, req)
+	return payments.SavedPaymentMethod{PaymentMethodID: req.PaymentMethodID, CustomerID: req.CustomerID, Brand: "visa", Last4: "4242", ExpMonth: 12, ExpYear: 2030}, nil
+}
+
+func (v *stubPaymentVault) DetachPaymentMethod(_ context.Context, _ payments.PaymentContext, req payments.DetachPaymentMethodRequest) error {
+	v.detached = append(v.detached, req.PaymentMethodID)
+	return nil
+}
+
+func TestUserServicePaymentMethodsLifecycle(t *testing.T) {
+	ctx := context.Background()
+	current := time.Date(2024, 9, 1, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		t := current
+		current = current.Add(time.Second)
+		return t
+	}
+	repo := newMemoryUserRepo(clock)
+	methods := &memoryPaymentMethodRepo{methods: map[string][]domain.PaymentMethod{}}
+	vault := &stubPaymentVault{}
+	audits := &captureAuditService{}
+	svc, err := NewUserService(UserServiceDeps{
+		Users:          repo,
+		PaymentMethods: methods,
+		PaymentVault:   vault,
+		Audit:          audits,
+		Firebase:       &stubFirebase{records: map[string]*firebaseauth.UserRecord{}},
+		Clock:          clock,
+	})
+	if err != nil {
+		t.Fatalf("new user service: %v", err)
+	}
+	if _, err := repo.UpdateProfile(ctx, domain.UserProfile{ID: "user-7", DisplayName: "Card Holder", Email: "user7@example.com", IsActive: true}); err != nil {
+		t.Fatalf("seed profile: %v", err)
+	}
+
+	if _, err := svc.AddPaymentMethod(ctx, AddPaymentMethodCommand{UserID: "user-7", Token: "pm_card"}); !errors.Is(err, ErrPaymentMethodInvalidInput) {
+		t.Fatalf("expected setup to be required first, got %v", err)
+	}
+
+	setup, err := svc.StartPaymentMethodSetup(ctx, StartPaymentMethodSetupCommand{UserID: "user-7"})
+	if err != nil {
+		t.Fatalf("start setup: %v", err)
+	}
+	if setup.CustomerRef != "cus_1" || setup.ClientSecret != "secret" || setup.Provider != payments.ProviderStripe {
+		t.Fatalf("unexpected setup %+v", setup)
+	}
+	if got := repo.store["user-7"].PaymentCustomers["stripe"]; got != "cus_1" {
+		t.Fatalf("expected customer persisted on profile, got %q", got)
+	}
+	if _, err := svc.StartPaymentMethodSetup(ctx, StartPaymentMethodSetupCommand{UserID: "user-7"}); err != nil {
+		t.Fatalf("second setup: %v", err)
+	}
+	if vault.setups[1].CustomerID != "cus_1" {
+		t.Fatalf("expected second setup to reuse the customer, got %+v", vault.setups[1])
+	}
+
+	method, err := svc.AddPaymentMethod(ctx, AddPaymentMethodCommand{UserID: "user-7", Token: "pm_card"})
+	if err != nil {
+		t.Fatalf("add payment method: %v", err)
+	}
+	if method.Brand != "visa" || method.Last4 != "4242" || method.ExpMonth != 12 || method.ExpYear != 2030 || method.CustomerRef != "cus_1" || method.Reference != "pm_card" {
+		t.Fatalf("unexpected saved method %+v", method)
+	}
+	again, err := svc.AddPaymentMethod(ctx, AddPaymentMethodCommand{UserID: "user-7", Token: "pm_card"})
+	if err != nil || again.ID != method.ID || len(vault.attached) != 1 {
+		t.Fatalf("expected duplicate add to return stored method, got %+v err=%v", again, err)
+	}
+
+	if err := svc.RemovePaymentMethod(ctx, RemovePaymentMethodCommand{UserID: "user-7", PaymentMethodID: "missing"}); !errors.Is(err, ErrPaymentMethodNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := svc.RemovePaymentMethod(ctx, RemovePaymentMethodCommand{UserID: "user-7", PaymentMethodID: method.ID}); err != nil {
+		t.Fatalf("remove payment method: %v", err)
+	}
+	if len(vault.detached) != 1 || vault.detached[0] != "pm_card" {
+		t.Fatalf("expected PSP detach, got %v", vault.detached)
+	}
+	remaining, err := svc.ListPaymentMethods(ctx, "user-7")
+	if err != nil || len(remaining) != 0 {
+		t.Fatalf("expected no remaining methods, got %+v err=%v", remaining, err)
+	}
+	if len(audits.records) != 2 || audits.records[0].Action != auditActionPaymentMethodAdd || audits.records[1].Action != auditActionPaymentMethodRemove {
+		t.Fatalf("unexpected audit records %+v", audits.records)
+	}
+}
+
 func ptr[T any](value T) *T {
 	return &value
 }
@@ -386,6 +517,12 @@ func cloneProfile(profile domain.UserProfile) domain.UserProfile {
 		t := *profile.PiiMaskedAt
 		copy.PiiMaskedAt = &t
 	}
+	if profile.PaymentCustomers != nil {
+		copy.PaymentCustomers = make(map[string]string, len(profile.PaymentCustomers))
+		for k, v := range profile.PaymentCustomers {
+			copy.PaymentCustomers[k] = v
+		}
+	}
 	return copy
 }
 
@@ -0,0 +1,10 @@
+package services
+
+import "errors"
+
+var (
+	// ErrWebhookDuplicate indicates the webhook delivery was already processed and can be acknowledged.
+	ErrWebhookDuplicate = errors.New("webhook: duplicate delivery")
+	// ErrWebhookInvalidPayload indicates the webhook payload cannot be processed; retrying will not help.
+	ErrWebhookInvalidPayload = errors.New("webhook: invalid payload")
+)
@@ -47,3 +47,313 @@ resource "google_firestore_index" "orders_status_updated" {
     order      = "DESCENDING"
   }
 }
+
+resource "google_firestore_index" "stock_reservations_status_expires" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockReservations"
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "expiresAt"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "reviews_product_status_created" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "reviews"
+
+  fields {
+    field_path   = "productIds"
+    array_config = "CONTAINS"
+  }
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "payments_provider_created" {
+  project     = var.project_id
+  database    = google_firestore_database.default.name
+  collection  = "payments"
+  query_scope = "COLLECTION_GROUP"
+
+  fields {
+    field_path = "provider"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "payments_provider_intent" {
+  project     = var.project_id
+  database    = google_firestore_database.default.name
+  collection  = "payments"
+  query_scope = "COLLECTION_GROUP"
+
+  fields {
+    field_path = "provider"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "intentId"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "disputes_status_due" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "paymentDisputes"
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "evidenceDueBy"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "disputes_provider_psp" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "paymentDisputes"
+
+  fields {
+    field_path = "provider"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "pspDisputeId"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "fx_rate_tables_base_effective" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "fxRateTables"
+
+  fields {
+    field_path = "baseCurrency"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "effectiveAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "inventory_stock_events_sku_occurred" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "inventoryStockEvents"
+
+  fields {
+    field_path = "sku"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "occurredAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "inventory_locations_available" {
+  project     = var.project_id
+  database    = google_firestore_database.default.name
+  collection  = "locations"
+  query_scope = "COLLECTION_GROUP"
+
+  fields {
+    field_path = "locationId"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "available"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "sku"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "inventory_locations_safety_delta" {
+  project     = var.project_id
+  database    = google_firestore_database.default.name
+  collection  = "locations"
+  query_scope = "COLLECTION_GROUP"
+
+  fields {
+    field_path = "locationId"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "safetyDelta"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "sku"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "stock_subscriptions_sku_status" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockSubscriptions"
+
+  fields {
+    field_path = "sku"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "stock_subscriptions_product_status" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockSubscriptions"
+
+  fields {
+    field_path = "productRef"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "stock_subscriptions_user_status" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockSubscriptions"
+
+  fields {
+    field_path = "userId"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "stock_subscriptions_status_expires" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockSubscriptions"
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "expiresAt"
+    order      = "ASCENDING"
+  }
+}
+
+resource "google_firestore_index" "stock_subscriptions_user_sku_status" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockSubscriptions"
+
+  fields {
+    field_path = "userId"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "sku"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "DESCENDING"
+  }
+}
+
+resource "google_firestore_index" "stock_subscriptions_user_product_status" {
+  project    = var.project_id
+  database   = google_firestore_database.default.name
+  collection = "stockSubscriptions"
+
+  fields {
+    field_path = "userId"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "productRef"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "status"
+    order      = "ASCENDING"
+  }
+
+  fields {
+    field_path = "createdAt"
+    order      = "DESCENDING"
+  }
+}